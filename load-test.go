@@ -9,6 +9,8 @@ import (
 	"log"
 	"math/rand"
 	"net/http"
+	"os"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -18,6 +20,11 @@ type LoadTester struct {
 	baseURL    string
 	userIDs    []string
 	userIDsMux sync.RWMutex
+
+	// pauseUntil holds every worker back while the server's rate limit is
+	// exhausted, so the test paces itself instead of collecting 429s.
+	pauseUntil time.Time
+	pauseMux   sync.Mutex
 }
 
 type User struct {
@@ -92,8 +99,10 @@ func (lt *LoadTester) doRequest(req *http.Request) (*http.Response, error) {
 	baseDelay := 100 * time.Millisecond
 
 	for attempt := 0; attempt < maxRetries; attempt++ {
+		lt.waitForQuota()
 		resp, err := lt.client.Do(req)
 		if err == nil {
+			lt.pace(resp)
 			return resp, nil
 		}
 
@@ -105,7 +114,47 @@ func (lt *LoadTester) doRequest(req *http.Request) (*http.Response, error) {
 	}
 
 	// Final attempt without retry
-	return lt.client.Do(req)
+	lt.waitForQuota()
+	resp, err := lt.client.Do(req)
+	if err == nil {
+		lt.pace(resp)
+	}
+	return resp, err
+}
+
+// pace reads the server's RateLimit hints from resp. When the quota is used
+// up, workers pause for Retry-After if the request was rejected, or for
+// about one token's refill time if it was the last one admitted.
+func (lt *LoadTester) pace(resp *http.Response) {
+	var wait time.Duration
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		wait = time.Duration(secs) * time.Second
+	} else if resp.Header.Get("RateLimit-Remaining") == "0" {
+		limit, err1 := strconv.Atoi(resp.Header.Get("RateLimit-Limit"))
+		reset, err2 := strconv.Atoi(resp.Header.Get("RateLimit-Reset"))
+		if err1 != nil || err2 != nil || limit <= 0 {
+			return
+		}
+		wait = time.Duration(reset) * time.Second / time.Duration(limit)
+	}
+	if wait <= 0 {
+		return
+	}
+
+	lt.pauseMux.Lock()
+	defer lt.pauseMux.Unlock()
+	if until := time.Now().Add(wait); until.After(lt.pauseUntil) {
+		lt.pauseUntil = until
+		log.Printf("⏸️ Rate limit reached, pausing requests for %v", wait)
+	}
+}
+
+// waitForQuota sleeps until a pause set by pace is over.
+func (lt *LoadTester) waitForQuota() {
+	lt.pauseMux.Lock()
+	until := lt.pauseUntil
+	lt.pauseMux.Unlock()
+	time.Sleep(time.Until(until))
 }
 func (lt *LoadTester) generateRandomUser() CreateUserRequest {
 	names := []string{"Alice", "Bob", "Charlie", "Diana", "Eve", "Frank", "Grace", "Henry"}
@@ -397,8 +446,13 @@ func (lt *LoadTester) runTestCycle(ctx context.Context, cycleNum int, workerID i
 func main() {
 	// Configuration - targeting the FastAPI client on port 8000
 	baseURL := "http://rpc-client.arch.svc.cluster.local:8000" // FastAPI client service
-	interval := 2 * time.Second                                // Interval between test cycles
-	numWorkers := 4                                            // Number of parallel workers
+	// BASE_URL points the test elsewhere, e.g. at the server's built-in REST
+	// gateway on http://rpc-server.arch.svc.cluster.local:8080.
+	if url := os.Getenv("BASE_URL"); url != "" {
+		baseURL = url
+	}
+	interval := 2 * time.Second // Interval between test cycles
+	numWorkers := 4             // Number of parallel workers
 
 	log.Printf("🚀 Starting HTTP load tester")
 	log.Printf("📡 Base URL: %s", baseURL)
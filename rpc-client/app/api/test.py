@@ -2,10 +2,10 @@
 
 from typing import Annotated
 
-from fastapi import APIRouter, Depends, Path, Request
+from fastapi import APIRouter, Depends, Path, Query, Request
 
 from ..grpc_client import AsyncUserGRPCClient
-from ..models import MessageResponse
+from ..models import MessageResponse, TestEchoResponse, TestLatencyResponse
 from ..services import TestService
 
 router = APIRouter(tags=["test"])
@@ -42,3 +42,38 @@ async def test_error(
 ) -> MessageResponse:
     """Test endpoint that returns the specified HTTP status code."""
     return await test_service.test_error(status_code)
+
+
+@router.get(
+    "/test-latency",
+    response_model=TestLatencyResponse,
+    summary="Test latency endpoint",
+    description="Test endpoint that responds after the requested delay, for validating timeouts and retries",
+    responses={
+        400: {"description": "Bad Request"},
+        504: {"description": "Gateway Timeout"},
+    },
+)
+async def test_latency(
+    test_service: Annotated[TestService, Depends(get_test_service)],
+    duration_ms: Annotated[int, Query(ge=0, description="Delay in milliseconds")] = 0,
+    jitter_ms: Annotated[
+        int, Query(ge=0, description="Random variation of the delay in milliseconds")
+    ] = 0,
+) -> TestLatencyResponse:
+    """Test endpoint that sleeps before responding."""
+    return await test_service.test_latency(duration_ms, jitter_ms)
+
+
+@router.get(
+    "/test-echo",
+    response_model=TestEchoResponse,
+    summary="Test echo endpoint",
+    description="Returns the metadata, peer, deadline and compression the gRPC server saw for the call",
+)
+async def test_echo(
+    test_service: Annotated[TestService, Depends(get_test_service)],
+    message: Annotated[str, Query(description="Text echoed back")] = "",
+) -> TestEchoResponse:
+    """Test endpoint that echoes what the gRPC server received."""
+    return await test_service.test_echo(message)
@@ -2,10 +2,13 @@
 
 from typing import Annotated
 
-from fastapi import APIRouter, Depends, Query, Request
+from fastapi import APIRouter, Depends, Header, Query, Request, Response
 
 from ...grpc_client import AsyncUserGRPCClient
 from ...models import (
+    EmailChangeConfirm,
+    EmailChangeRequest,
+    EmailChangeResponse,
     MessageResponse,
     UserCreate,
     UserListResponse,
@@ -41,19 +44,28 @@ async def create_user(
     response_model=UserResponse,
     summary="Get user by ID",
     description="Retrieve a user by their unique identifier",
+    responses={304: {"description": "User unchanged since the If-None-Match ETag"}},
 )
 async def get_user(
-    user_id: str, user_service: Annotated[UserService, Depends(get_user_service)]
-) -> UserResponse:
-    """Get a user by ID."""
-    return await user_service.get_user(user_id)
+    user_id: str,
+    response: Response,
+    user_service: Annotated[UserService, Depends(get_user_service)],
+    if_none_match: Annotated[str | None, Header()] = None,
+) -> UserResponse | Response:
+    """Get a user by ID, or 304 Not Modified if If-None-Match is current."""
+    user, etag = await user_service.get_user(user_id, if_none_match)
+    headers = {"ETag": etag} if etag else {}
+    if user is None:
+        return Response(status_code=304, headers=headers)
+    response.headers.update(headers)
+    return user
 
 
 @router.put(
     "/users/{user_id}",
     response_model=UserResponse,
     summary="Update user",
-    description="Update an existing user's information",
+    description="Update a user's name or age; email uses /email-change",
 )
 async def update_user(
     user_id: str,
@@ -64,6 +76,37 @@ async def update_user(
     return await user_service.update_user(user_id, user)
 
 
+@router.post(
+    "/users/{user_id}/email-change",
+    response_model=EmailChangeResponse,
+    status_code=202,
+    summary="Request email change",
+    description="Start an email change; a token is sent to the new address",
+)
+async def request_email_change(
+    user_id: str,
+    change: EmailChangeRequest,
+    user_service: Annotated[UserService, Depends(get_user_service)],
+) -> EmailChangeResponse:
+    """Request an email change."""
+    return await user_service.request_email_change(user_id, change.new_email)
+
+
+@router.post(
+    "/users/{user_id}/email-change/confirm",
+    response_model=UserResponse,
+    summary="Confirm email change",
+    description="Apply a pending email change with its confirmation token",
+)
+async def confirm_email_change(
+    user_id: str,
+    confirm: EmailChangeConfirm,
+    user_service: Annotated[UserService, Depends(get_user_service)],
+) -> UserResponse:
+    """Confirm an email change."""
+    return await user_service.confirm_email_change(user_id, confirm.token)
+
+
 @router.delete(
     "/users/{user_id}",
     response_model=MessageResponse,
@@ -51,8 +51,10 @@ def grpc_to_http_exception(grpc_error: Exception) -> HTTPException:
             return HTTPException(status_code=404, detail=detail)
         case grpc.StatusCode.ALREADY_EXISTS:
             return HTTPException(status_code=409, detail=detail)
-        case grpc.StatusCode.INVALID_ARGUMENT:
+        case grpc.StatusCode.INVALID_ARGUMENT | grpc.StatusCode.FAILED_PRECONDITION:
             return HTTPException(status_code=400, detail=detail)
+        case grpc.StatusCode.RESOURCE_EXHAUSTED:
+            return HTTPException(status_code=429, detail=detail)
         case grpc.StatusCode.UNAVAILABLE:
             return HTTPException(status_code=503, detail="gRPC service unavailable")
         case grpc.StatusCode.DEADLINE_EXCEEDED:
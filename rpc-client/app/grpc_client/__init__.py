@@ -1,3 +1,4 @@
 from .client import AsyncUserGRPCClient
+from .ratelimit import rate_limit_header_middleware
 
-__all__ = ["AsyncUserGRPCClient"]
+__all__ = ["AsyncUserGRPCClient", "rate_limit_header_middleware"]
@@ -10,6 +10,7 @@ from ..core.exceptions import (
     GRPCClientError,
     GRPCServiceUnavailableError,
 )
+from .ratelimit import RateLimitHintInterceptor
 
 client_dir = Path(__file__).parent.parent.parent
 sys.path.insert(0, str(client_dir))
@@ -41,7 +42,9 @@ class AsyncUserGRPCClient:
 
     async def connect(self) -> None:
         try:
-            self._channel = aio.insecure_channel(self._address)
+            self._channel = aio.insecure_channel(
+                self._address, interceptors=[RateLimitHintInterceptor()]
+            )
             self._stub = UserServiceStub(self._channel)
             # Try to make a dummy call to ensure connection is healthy
             await self._channel.channel_ready()
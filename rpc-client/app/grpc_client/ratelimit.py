@@ -0,0 +1,55 @@
+"""Pass the gRPC server's rate limit hints on as HTTP response headers.
+
+The server sets RateLimit-style response metadata on every call while its
+rate limit is on. RateLimitHintInterceptor copies it from each call into the
+current request's hints, and rate_limit_header_middleware sets the last
+values seen on the HTTP response, so HTTP clients can pace themselves too.
+"""
+
+from collections.abc import Awaitable, Callable
+from contextvars import ContextVar
+
+import grpc
+from fastapi import Request, Response
+from grpc import aio
+
+# gRPC metadata keys and the HTTP headers they become.
+RATE_LIMIT_HEADERS = {
+    "ratelimit-limit": "RateLimit-Limit",
+    "ratelimit-remaining": "RateLimit-Remaining",
+    "ratelimit-reset": "RateLimit-Reset",
+    "retry-after": "Retry-After",
+}
+
+# The middleware sets a fresh dict per request. Endpoints run in a copy of
+# the middleware's context, so the interceptor fills that same dict.
+_hints: ContextVar[dict[str, str] | None] = ContextVar("rate_limit_hints", default=None)
+
+
+class RateLimitHintInterceptor(aio.UnaryUnaryClientInterceptor):
+    async def intercept_unary_unary(self, continuation, client_call_details, request):
+        call = await continuation(client_call_details, request)
+        hints = _hints.get()
+        if hints is None:
+            return call
+        try:
+            metadata = await call.initial_metadata()
+        except grpc.RpcError:
+            return call
+        for key, value in metadata or ():
+            if key in RATE_LIMIT_HEADERS:
+                hints[RATE_LIMIT_HEADERS[key]] = value
+        return call
+
+
+async def rate_limit_header_middleware(
+    request: Request, call_next: Callable[[Request], Awaitable[Response]]
+) -> Response:
+    hints: dict[str, str] = {}
+    token = _hints.set(hints)
+    try:
+        response = await call_next(request)
+    finally:
+        _hints.reset(token)
+    response.headers.update(hints)
+    return response
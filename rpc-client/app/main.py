@@ -11,7 +11,7 @@ from opentelemetry.sdk.trace import TracerProvider
 from .api import health_router, test_router, users_router
 from .core.config import settings
 from .core.logging import create_access_log_middleware, setup_logging
-from .grpc_client.client import AsyncUserGRPCClient
+from .grpc_client import AsyncUserGRPCClient, rate_limit_header_middleware
 
 # Setup logging first
 setup_logging()
@@ -50,6 +50,8 @@ def create_app() -> FastAPI:
 
     # Add access logging middleware
     app.middleware("http")(create_access_log_middleware())
+    # Pass the gRPC server's rate limit hints on to HTTP clients
+    app.middleware("http")(rate_limit_header_middleware)
 
     @app.exception_handler(Exception)
     async def global_exception_handler(
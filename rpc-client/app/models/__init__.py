@@ -1,7 +1,11 @@
 """Models package initialization."""
 
 from .sys import HealthResponse
+from .test import TestEchoResponse, TestLatencyResponse
 from .user import (
+    EmailChangeConfirm,
+    EmailChangeRequest,
+    EmailChangeResponse,
     MessageResponse,
     UserBase,
     UserCreate,
@@ -11,8 +15,13 @@ from .user import (
 )
 
 __all__ = [
+    "EmailChangeConfirm",
+    "EmailChangeRequest",
+    "EmailChangeResponse",
     "HealthResponse",
     "MessageResponse",
+    "TestEchoResponse",
+    "TestLatencyResponse",
     "UserBase",
     "UserCreate",
     "UserUpdate",
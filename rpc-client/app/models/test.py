@@ -0,0 +1,23 @@
+from pydantic import BaseModel
+
+
+class TestLatencyResponse(BaseModel):
+    requested_ms: int
+    slept_ms: int
+    deadline_remaining_ms: int
+    trace_id: str
+
+
+class TestEchoResponse(BaseModel):
+    message: str
+    metadata: dict[str, list[str]]
+    peer_address: str
+    peer_auth_type: str
+    deadline_unix_ms: int
+    deadline_remaining_ms: int
+    request_compression: str
+    response_compression: str
+    accepted_compression: list[str]
+    instance_id: str
+    method: str
+    trace_id: str
@@ -13,10 +13,22 @@ class UserCreate(UserBase):
 
 class UserUpdate(BaseModel):
     name: str | None = Field(None, min_length=1, max_length=100)
-    email: EmailStr | None = Field(None)
     age: int | None = Field(None, ge=0, le=150)
 
 
+class EmailChangeRequest(BaseModel):
+    new_email: EmailStr
+
+
+class EmailChangeResponse(BaseModel):
+    expires_at: int
+    message: str
+
+
+class EmailChangeConfirm(BaseModel):
+    token: str = Field(..., min_length=1)
+
+
 class UserResponse(UserBase):
     id: str
     created_at: int
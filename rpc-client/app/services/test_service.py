@@ -8,12 +8,16 @@ import grpc
 
 from ..core.exceptions import grpc_to_http_exception
 from ..grpc_client import AsyncUserGRPCClient
-from ..models import MessageResponse
+from ..models import MessageResponse, TestEchoResponse, TestLatencyResponse
 
 client_dir = Path(__file__).parent.parent.parent
 sys.path.insert(0, str(client_dir))
 
-from proto.user_pb2 import TestErrorRequest  # noqa: E402
+from proto.user_pb2 import (  # noqa: E402
+    TestEchoRequest,
+    TestErrorRequest,
+    TestLatencyRequest,
+)
 
 logger = logging.getLogger(__name__)
 
@@ -30,3 +34,39 @@ class TestService:
         except grpc.RpcError as e:
             logger.error(f"gRPC error from test error endpoint: {e}")
             raise grpc_to_http_exception(e) from e
+
+    async def test_latency(self, duration_ms: int, jitter_ms: int) -> TestLatencyResponse:
+        try:
+            request = TestLatencyRequest(duration_ms=duration_ms, jitter_ms=jitter_ms)
+            response = await self.grpc_client.stub.TestLatency(request)
+            return TestLatencyResponse(
+                requested_ms=response.requested_ms,
+                slept_ms=response.slept_ms,
+                deadline_remaining_ms=response.deadline_remaining_ms,
+                trace_id=response.trace_id,
+            )
+        except grpc.RpcError as e:
+            logger.error(f"gRPC error from test latency endpoint: {e}")
+            raise grpc_to_http_exception(e) from e
+
+    async def test_echo(self, message: str) -> TestEchoResponse:
+        try:
+            request = TestEchoRequest(message=message)
+            response = await self.grpc_client.stub.TestEcho(request)
+            return TestEchoResponse(
+                message=response.message,
+                metadata={entry.key: list(entry.values) for entry in response.metadata},
+                peer_address=response.peer_address,
+                peer_auth_type=response.peer_auth_type,
+                deadline_unix_ms=response.deadline_unix_ms,
+                deadline_remaining_ms=response.deadline_remaining_ms,
+                request_compression=response.request_compression,
+                response_compression=response.response_compression,
+                accepted_compression=list(response.accepted_compression),
+                instance_id=response.instance_id,
+                method=response.method,
+                trace_id=response.trace_id,
+            )
+        except grpc.RpcError as e:
+            logger.error(f"gRPC error from test echo endpoint: {e}")
+            raise grpc_to_http_exception(e) from e
@@ -9,6 +9,7 @@ import grpc
 from ..core.exceptions import grpc_to_http_exception
 from ..grpc_client import AsyncUserGRPCClient
 from ..models import (
+    EmailChangeResponse,
     MessageResponse,
     UserCreate,
     UserListResponse,
@@ -20,10 +21,12 @@ client_dir = Path(__file__).parent.parent.parent
 sys.path.insert(0, str(client_dir))
 
 from proto.user_pb2 import (  # noqa: E402
+    ConfirmEmailChangeRequest,
     CreateUserRequest,
     DeleteUserRequest,
     GetUserRequest,
     ListUsersRequest,
+    RequestEmailChangeRequest,
     UpdateUserRequest,
     User,
 )
@@ -48,11 +51,19 @@ class UserService:
             logger.error(f"gRPC error creating user: {e}")
             raise grpc_to_http_exception(e) from e
 
-    async def get_user(self, user_id: str) -> UserResponse:
+    async def get_user(
+        self, user_id: str, if_none_match: str | None = None
+    ) -> tuple[UserResponse | None, str | None]:
+        """Get a user and its ETag; the user is None if if_none_match is current."""
         try:
             request = GetUserRequest(id=user_id)
-            response = await self.grpc_client.stub.GetUser(request)
-            return self._grpc_user_to_pydantic(response.user)
+            metadata = (("if-none-match", if_none_match),) if if_none_match else None
+            call = self.grpc_client.stub.GetUser(request, metadata=metadata)
+            response = await call
+            etag = dict(await call.initial_metadata()).get("etag")
+            if response.not_modified:
+                return None, etag
+            return self._grpc_user_to_pydantic(response.user), etag
         except grpc.RpcError as e:
             logger.error(f"gRPC error getting user {user_id}: {e}")
             raise grpc_to_http_exception(e) from e
@@ -62,7 +73,6 @@ class UserService:
             request = UpdateUserRequest(
                 id=user_id,
                 name=user_data.name or "",
-                email=user_data.email or "",
                 age=user_data.age or 0,
             )
             response = await self.grpc_client.stub.UpdateUser(request)
@@ -71,6 +81,28 @@ class UserService:
             logger.error(f"gRPC error updating user {user_id}: {e}")
             raise grpc_to_http_exception(e) from e
 
+    async def request_email_change(
+        self, user_id: str, new_email: str
+    ) -> EmailChangeResponse:
+        try:
+            request = RequestEmailChangeRequest(id=user_id, new_email=new_email)
+            response = await self.grpc_client.stub.RequestEmailChange(request)
+            return EmailChangeResponse(
+                expires_at=response.expires_at, message=response.message
+            )
+        except grpc.RpcError as e:
+            logger.error(f"gRPC error requesting email change for user {user_id}: {e}")
+            raise grpc_to_http_exception(e) from e
+
+    async def confirm_email_change(self, user_id: str, token: str) -> UserResponse:
+        try:
+            request = ConfirmEmailChangeRequest(id=user_id, token=token)
+            response = await self.grpc_client.stub.ConfirmEmailChange(request)
+            return self._grpc_user_to_pydantic(response.user)
+        except grpc.RpcError as e:
+            logger.error(f"gRPC error confirming email change for user {user_id}: {e}")
+            raise grpc_to_http_exception(e) from e
+
     async def delete_user(self, user_id: str) -> MessageResponse:
         try:
             request = DeleteUserRequest(id=user_id)
@@ -22,9 +22,10 @@ _runtime_version.ValidateProtobufRuntimeVersion(
 _sym_db = _symbol_database.Default()
 
 
+from google.protobuf import field_mask_pb2 as google_dot_protobuf_dot_field__mask__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\nuser.proto\x12\x04user\"d\n\x04User\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\r\n\x05\x65mail\x18\x03 \x01(\t\x12\x0b\n\x03\x61ge\x18\x04 \x01(\x05\x12\x12\n\ncreated_at\x18\x05 \x01(\x03\x12\x12\n\nupdated_at\x18\x06 \x01(\x03\"=\n\x11\x43reateUserRequest\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\r\n\x05\x65mail\x18\x02 \x01(\t\x12\x0b\n\x03\x61ge\x18\x03 \x01(\x05\"?\n\x12\x43reateUserResponse\x12\x18\n\x04user\x18\x01 \x01(\x0b\x32\n.user.User\x12\x0f\n\x07message\x18\x02 \x01(\t\"\x1c\n\x0eGetUserRequest\x12\n\n\x02id\x18\x01 \x01(\t\"<\n\x0fGetUserResponse\x12\x18\n\x04user\x18\x01 \x01(\x0b\x32\n.user.User\x12\x0f\n\x07message\x18\x02 \x01(\t\"I\n\x11UpdateUserRequest\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\r\n\x05\x65mail\x18\x03 \x01(\t\x12\x0b\n\x03\x61ge\x18\x04 \x01(\x05\"?\n\x12UpdateUserResponse\x12\x18\n\x04user\x18\x01 \x01(\x0b\x32\n.user.User\x12\x0f\n\x07message\x18\x02 \x01(\t\"\x1f\n\x11\x44\x65leteUserRequest\x12\n\n\x02id\x18\x01 \x01(\t\"%\n\x12\x44\x65leteUserResponse\x12\x0f\n\x07message\x18\x01 \x01(\t\"/\n\x10ListUsersRequest\x12\x0c\n\x04page\x18\x01 \x01(\x05\x12\r\n\x05limit\x18\x02 \x01(\x05\"N\n\x11ListUsersResponse\x12\x19\n\x05users\x18\x01 \x03(\x0b\x32\n.user.User\x12\r\n\x05total\x18\x02 \x01(\x05\x12\x0f\n\x07message\x18\x03 \x01(\t\"\'\n\x10TestErrorRequest\x12\x13\n\x0bstatus_code\x18\x01 \x01(\t\"6\n\x11TestErrorResponse\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x10\n\x08trace_id\x18\x02 \x01(\t2\x84\x03\n\x0bUserService\x12?\n\nCreateUser\x12\x17.user.CreateUserRequest\x1a\x18.user.CreateUserResponse\x12\x36\n\x07GetUser\x12\x14.user.GetUserRequest\x1a\x15.user.GetUserResponse\x12?\n\nUpdateUser\x12\x17.user.UpdateUserRequest\x1a\x18.user.UpdateUserResponse\x12?\n\nDeleteUser\x12\x17.user.DeleteUserRequest\x1a\x18.user.DeleteUserResponse\x12<\n\tListUsers\x12\x16.user.ListUsersRequest\x1a\x17.user.ListUsersResponse\x12<\n\tTestError\x12\x16.user.TestErrorRequest\x1a\x17.user.TestErrorResponseB\x06Z\x04./pbb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\nuser.proto\x12\x04user\x1a google/protobuf/field_mask.proto\"\x9b\x01\n\x04User\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\r\n\x05\x65mail\x18\x03 \x01(\t\x12\x0b\n\x03\x61ge\x18\x04 \x01(\x05\x12\x12\n\ncreated_at\x18\x05 \x01(\x03\x12\x12\n\nupdated_at\x18\x06 \x01(\x03\x12 \n\x06status\x18\x07 \x01(\x0e\x32\x10.user.UserStatus\x12\x13\n\x0bmerged_into\x18\x08 \x01(\t\"I\n\x11\x43reateUserRequest\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\r\n\x05\x65mail\x18\x02 \x01(\t\x12\x0b\n\x03\x61ge\x18\x03 \x01(\x05\x12\n\n\x02id\x18\x04 \x01(\t\"c\n\x12\x43reateUserResponse\x12\x18\n\x04user\x18\x01 \x01(\x0b\x32\n.user.User\x12\x13\n\x07message\x18\x02 \x01(\tB\x02\x18\x01\x12\x1e\n\x07outcome\x18\x03 \x01(\x0e\x32\r.user.Outcome\"\x82\x01\n\x0eGetUserRequest\x12\n\n\x02id\x18\x01 \x01(\t\x12-\n\tread_mask\x18\x04 \x01(\x0b\x32\x1a.google.protobuf.FieldMask\x12/\n\x10read_consistency\x18\x03 \x01(\x0e\x32\x15.user.ReadConsistencyJ\x04\x08\x02\x10\x03\"\x85\x01\n\x0fGetUserResponse\x12\x18\n\x04user\x18\x01 \x01(\x0b\x32\n.user.User\x12\x13\n\x07message\x18\x02 \x01(\tB\x02\x18\x01\x12\r\n\x05stale\x18\x03 \x01(\x08\x12\x14\n\x0cnot_modified\x18\x04 \x01(\x08\x12\x1e\n\x07outcome\x18\x05 \x01(\x0e\x32\r.user.Outcome\".\n\x14GetUserAtTimeRequest\x12\n\n\x02id\x18\x01 \x01(\t\x12\n\n\x02\x61t\x18\x02 \x01(\x03\"\xa0\x01\n\x15GetUserAtTimeResponse\x12\x18\n\x04user\x18\x01 \x01(\x0b\x32\n.user.User\x12\x12\n\nvalid_from\x18\x02 \x01(\x03\x12\x10\n\x08valid_to\x18\x03 \x01(\x03\x12\x13\n\x07message\x18\x04 \x01(\tB\x02\x18\x01\x12\x12\n\nversion_id\x18\x05 \x01(\x03\x12\x1e\n\x07outcome\x18\x06 \x01(\x0e\x32\r.user.Outcome\"I\n\x11UpdateUserRequest\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\r\n\x05\x65mail\x18\x03 \x01(\t\x12\x0b\n\x03\x61ge\x18\x04 \x01(\x05\"c\n\x12UpdateUserResponse\x12\x18\n\x04user\x18\x01 \x01(\x0b\x32\n.user.User\x12\x13\n\x07message\x18\x02 \x01(\tB\x02\x18\x01\x12\x1e\n\x07outcome\x18\x03 \x01(\x0e\x32\r.user.Outcome\":\n\x19RequestEmailChangeRequest\x12\n\n\x02id\x18\x01 \x01(\t\x12\x11\n\tnew_email\x18\x02 \x01(\t\"e\n\x1aRequestEmailChangeResponse\x12\x12\n\nexpires_at\x18\x01 \x01(\x03\x12\x13\n\x07message\x18\x02 \x01(\tB\x02\x18\x01\x12\x1e\n\x07outcome\x18\x03 \x01(\x0e\x32\r.user.Outcome\"6\n\x19\x43onfirmEmailChangeRequest\x12\n\n\x02id\x18\x01 \x01(\t\x12\r\n\x05token\x18\x02 \x01(\t\"k\n\x1a\x43onfirmEmailChangeResponse\x12\x18\n\x04user\x18\x01 \x01(\x0b\x32\n.user.User\x12\x13\n\x07message\x18\x02 \x01(\tB\x02\x18\x01\x12\x1e\n\x07outcome\x18\x03 \x01(\x0e\x32\r.user.Outcome\"\x1f\n\x11\x44\x65leteUserRequest\x12\n\n\x02id\x18\x01 \x01(\t\"I\n\x12\x44\x65leteUserResponse\x12\x13\n\x07message\x18\x01 \x01(\tB\x02\x18\x01\x12\x1e\n\x07outcome\x18\x02 \x01(\x0e\x32\r.user.Outcome\".\n\x10\x45raseUserRequest\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0e\n\x06reason\x18\x02 \x01(\t\"s\n\x11\x45raseUserResponse\x12\x16\n\x0e\x63\x65rtificate_id\x18\x01 \x01(\t\x12\x11\n\terased_at\x18\x02 \x01(\x03\x12\x13\n\x07message\x18\x03 \x01(\tB\x02\x18\x01\x12\x1e\n\x07outcome\x18\x04 \x01(\x0e\x32\r.user.Outcome\"#\n\x15\x45xportUserDataRequest\x12\n\n\x02id\x18\x01 \x01(\t\"o\n\x16\x45xportUserDataResponse\x12\x10\n\x08\x64ocument\x18\x01 \x01(\x0c\x12\x11\n\tsignature\x18\x02 \x01(\t\x12\x1b\n\x13signature_algorithm\x18\x03 \x01(\t\x12\x13\n\x0b\x65xported_at\x18\x04 \x01(\x03\"C\n\x11RevertUserRequest\x12\n\n\x02id\x18\x01 \x01(\t\x12\x12\n\nversion_id\x18\x02 \x01(\x03\x12\x0e\n\x06reason\x18\x03 \x01(\t\"{\n\x12RevertUserResponse\x12\x18\n\x04user\x18\x01 \x01(\x0b\x32\n.user.User\x12\x16\n\x0e\x61udit_entry_id\x18\x02 \x01(\t\x12\x13\n\x07message\x18\x03 \x01(\tB\x02\x18\x01\x12\x1e\n\x07outcome\x18\x04 \x01(\x0e\x32\r.user.Outcome\"}\n\x11MergeUsersRequest\x12\x11\n\tsource_id\x18\x01 \x01(\t\x12\x11\n\ttarget_id\x18\x02 \x01(\t\x12\x32\n\x0f\x63onflict_policy\x18\x03 \x01(\x0e\x32\x19.user.MergeConflictPolicy\x12\x0e\n\x06reason\x18\x04 \x01(\t\"{\n\x12MergeUsersResponse\x12\x18\n\x04user\x18\x01 \x01(\x0b\x32\n.user.User\x12\x16\n\x0e\x61udit_entry_id\x18\x02 \x01(\t\x12\x13\n\x07message\x18\x03 \x01(\tB\x02\x18\x01\x12\x1e\n\x07outcome\x18\x04 \x01(\x0e\x32\r.user.Outcome\"\xd4\x01\n\x10ListUsersRequest\x12\x0c\n\x04page\x18\x01 \x01(\x05\x12\r\n\x05limit\x18\x02 \x01(\x05\x12\x13\n\x0bname_prefix\x18\x03 \x01(\t\x12\x14\n\x0c\x65mail_prefix\x18\x04 \x01(\t\x12-\n\tread_mask\x18\x08 \x01(\x0b\x32\x1a.google.protobuf.FieldMask\x12\x12\n\npage_token\x18\x06 \x01(\t\x12/\n\x10read_consistency\x18\x07 \x01(\x0e\x32\x15.user.ReadConsistencyJ\x04\x08\x05\x10\x06\"\xb7\x01\n\x11ListUsersResponse\x12\x19\n\x05users\x18\x01 \x03(\x0b\x32\n.user.User\x12\r\n\x05total\x18\x02 \x01(\x05\x12\x13\n\x07message\x18\x03 \x01(\tB\x02\x18\x01\x12\r\n\x05stale\x18\x04 \x01(\x08\x12\r\n\x05limit\x18\x05 \x01(\x05\x12\x17\n\x0fnext_page_token\x18\x06 \x01(\t\x12\x1e\n\x07outcome\x18\x07 \x01(\x0e\x32\r.user.Outcome\x12\x0c\n\x04page\x18\x08 \x01(\x05\"\'\n\x10TestErrorRequest\x12\x13\n\x0bstatus_code\x18\x01 \x01(\t\"6\n\x11TestErrorResponse\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x10\n\x08trace_id\x18\x02 \x01(\t\"<\n\x12TestLatencyRequest\x12\x13\n\x0b\x64uration_ms\x18\x01 \x01(\x03\x12\x11\n\tjitter_ms\x18\x02 \x01(\x03\"Q\n\x18TestLatencyStreamRequest\x12\x13\n\x0b\x64uration_ms\x18\x01 \x01(\x03\x12\x11\n\tjitter_ms\x18\x02 \x01(\x03\x12\r\n\x05\x63ount\x18\x03 \x01(\x05\"\x80\x01\n\x13TestLatencyResponse\x12\x14\n\x0crequested_ms\x18\x01 \x01(\x03\x12\x10\n\x08slept_ms\x18\x02 \x01(\x03\x12\x1d\n\x15\x64\x65\x61\x64line_remaining_ms\x18\x03 \x01(\x03\x12\x10\n\x08sequence\x18\x04 \x01(\x05\x12\x10\n\x08trace_id\x18\x05 \x01(\t\"h\n\x11TestStreamRequest\x12\x0f\n\x07payload\x18\x01 \x01(\x0c\x12\x15\n\rresponse_size\x18\x02 \x01(\x05\x12\r\n\x05\x63ount\x18\x03 \x01(\x05\x12\x1c\n\x14responses_per_second\x18\x04 \x01(\x05\"\x87\x01\n\x12TestStreamResponse\x12\x10\n\x08sequence\x18\x01 \x01(\x03\x12\x0f\n\x07payload\x18\x02 \x01(\x0c\x12\x16\n\x0ereceived_bytes\x18\x03 \x01(\x03\x12\x1c\n\x14total_received_bytes\x18\x04 \x01(\x03\x12\x18\n\x10total_sent_bytes\x18\x05 \x01(\x03\"\"\n\x0fTestEchoRequest\x12\x0f\n\x07message\x18\x01 \x01(\t\",\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\x0e\n\x06values\x18\x02 \x03(\t\"\xc1\x02\n\x10TestEchoResponse\x12\x0f\n\x07message\x18\x01 \x01(\t\x12%\n\x08metadata\x18\x02 \x03(\x0b\x32\x13.user.MetadataEntry\x12\x14\n\x0cpeer_address\x18\x03 \x01(\t\x12\x16\n\x0epeer_auth_type\x18\x04 \x01(\t\x12\x18\n\x10\x64\x65\x61\x64line_unix_ms\x18\x05 \x01(\x03\x12\x1d\n\x15\x64\x65\x61\x64line_remaining_ms\x18\x06 \x01(\x03\x12\x1b\n\x13request_compression\x18\x07 \x01(\t\x12\x1c\n\x14response_compression\x18\x08 \x01(\t\x12\x1c\n\x14\x61\x63\x63\x65pted_compression\x18\t \x03(\t\x12\x13\n\x0binstance_id\x18\n \x01(\t\x12\x0e\n\x06method\x18\x0b \x01(\t\x12\x10\n\x08trace_id\x18\x0c \x01(\t\"+\n\x14GetCacheStatsRequest\x12\x13\n\x0bsample_size\x18\x01 \x01(\x05\"\x97\x01\n\x13\x43\x61\x63heNamespaceStats\x12\x11\n\tnamespace\x18\x01 \x01(\t\x12\x0c\n\x04hits\x18\x02 \x01(\x03\x12\x0e\n\x06misses\x18\x03 \x01(\x03\x12\x0e\n\x06\x65rrors\x18\x04 \x01(\x03\x12\x11\n\thit_ratio\x18\x05 \x01(\x01\x12\x14\n\x0csampled_keys\x18\x06 \x01(\x03\x12\x16\n\x0e\x65stimated_keys\x18\x07 \x01(\x03\"\x9f\x01\n\x10\x43\x61\x63heMemoryStats\x12\x19\n\x11used_memory_bytes\x18\x01 \x01(\x03\x12\x1e\n\x16used_memory_peak_bytes\x18\x02 \x01(\x03\x12\x18\n\x10max_memory_bytes\x18\x03 \x01(\x03\x12\x19\n\x11max_memory_policy\x18\x04 \x01(\t\x12\x1b\n\x13\x66ragmentation_ratio\x18\x05 \x01(\x01\"\xca\x01\n\x15GetCacheStatsResponse\x12-\n\nnamespaces\x18\x01 \x03(\x0b\x32\x19.user.CacheNamespaceStats\x12\x12\n\ntotal_keys\x18\x02 \x01(\x03\x12\x14\n\x0csampled_keys\x18\x03 \x01(\x03\x12&\n\x06memory\x18\x04 \x01(\x0b\x32\x16.user.CacheMemoryStats\x12\x13\n\x0binstance_id\x18\x05 \x01(\t\x12\x1b\n\x13stats_since_unix_ms\x18\x06 \x01(\x03\"\xdc\x02\n\rRuntimeConfig\x12\x11\n\tlog_level\x18\x01 \x01(\t\x12\x1e\n\x16user_cache_ttl_seconds\x18\x02 \x01(\x03\x12\x1e\n\x16list_cache_ttl_seconds\x18\x03 \x01(\x03\x12\x1a\n\x12trace_sample_ratio\x18\x04 \x01(\x01\x12\x16\n\x0erate_limit_qps\x18\x05 \x01(\x01\x12\x18\n\x10rate_limit_burst\x18\x06 \x01(\x05\x12\x11\n\tread_only\x18\x07 \x01(\x08\x12\x1c\n\x14low_priority_reserve\x18\x08 \x01(\x01\x12/\n\x11\x63\x61ller_priorities\x18\t \x03(\x0b\x32\x14.user.CallerPriority\x12/\n\x11module_log_levels\x18\n \x03(\x0b\x32\x14.user.ModuleLogLevel\x12\x17\n\x0frace_user_reads\x18\x0b \x01(\x08\"D\n\x0e\x43\x61llerPriority\x12\x0e\n\x06\x63\x61ller\x18\x01 \x01(\t\x12\"\n\x05\x63lass\x18\x02 \x01(\x0e\x32\x13.user.PriorityClass\"/\n\x0eModuleLogLevel\x12\x0e\n\x06module\x18\x01 \x01(\t\x12\r\n\x05level\x18\x02 \x01(\t\"\x19\n\x17GetRuntimeConfigRequest\"p\n\x18GetRuntimeConfigResponse\x12#\n\x06\x63onfig\x18\x01 \x01(\x0b\x32\x13.user.RuntimeConfig\x12\x13\n\x0binstance_id\x18\x02 \x01(\t\x12\x1a\n\x12updated_at_unix_ms\x18\x03 \x01(\x03\"S\n\x17SetRuntimeConfigRequest\x12#\n\x06\x63onfig\x18\x01 \x01(\x0b\x32\x13.user.RuntimeConfig\x12\x13\n\x0bupdate_mask\x18\x02 \x03(\t\"p\n\x18SetRuntimeConfigResponse\x12#\n\x06\x63onfig\x18\x01 \x01(\x0b\x32\x13.user.RuntimeConfig\x12\x13\n\x0binstance_id\x18\x02 \x01(\t\x12\x1a\n\x12updated_at_unix_ms\x18\x03 \x01(\x03\"\x13\n\x11GetVersionRequest\"\xa0\x01\n\x12GetVersionResponse\x12\x0f\n\x07version\x18\x01 \x01(\t\x12\x0e\n\x06\x63ommit\x18\x02 \x01(\t\x12\x12\n\nbuild_date\x18\x03 \x01(\t\x12\x12\n\ngo_version\x18\x04 \x01(\t\x12\x10\n\x08modified\x18\x05 \x01(\x08\x12\x13\n\x0binstance_id\x18\x06 \x01(\t\x12\x1a\n\x12started_at_unix_ms\x18\x07 \x01(\x03\"\'\n\x19GetUserAttributionRequest\x12\n\n\x02id\x18\x01 \x01(\t\"}\n\x1aGetUserAttributionResponse\x12\x0f\n\x07user_id\x18\x01 \x01(\t\x12\x12\n\ncreated_by\x18\x02 \x01(\t\x12\x12\n\ncreated_at\x18\x03 \x01(\x03\x12\x12\n\nupdated_by\x18\x04 \x01(\t\x12\x12\n\nupdated_at\x18\x05 \x01(\x03*r\n\nUserStatus\x12\x1b\n\x17USER_STATUS_UNSPECIFIED\x10\x00\x12\x16\n\x12USER_STATUS_ACTIVE\x10\x01\x12\x17\n\x13USER_STATUS_EXPIRED\x10\x02\x12\x16\n\x12USER_STATUS_MERGED\x10\x03*\x8f\x02\n\x07Outcome\x12\x17\n\x13OUTCOME_UNSPECIFIED\x10\x00\x12\x13\n\x0fOUTCOME_CREATED\x10\x01\x12\x15\n\x11OUTCOME_RETRIEVED\x10\x02\x12\x18\n\x14OUTCOME_NOT_MODIFIED\x10\x03\x12\x13\n\x0fOUTCOME_UPDATED\x10\x04\x12\x13\n\x0fOUTCOME_DELETED\x10\x05\x12\"\n\x1eOUTCOME_EMAIL_CHANGE_REQUESTED\x10\x06\x12\x19\n\x15OUTCOME_EMAIL_CHANGED\x10\x07\x12\x12\n\x0eOUTCOME_ERASED\x10\x08\x12\x14\n\x10OUTCOME_REVERTED\x10\t\x12\x12\n\x0eOUTCOME_MERGED\x10\n*o\n\x0fReadConsistency\x12 \n\x1cREAD_CONSISTENCY_UNSPECIFIED\x10\x00\x12\x1d\n\x19READ_CONSISTENCY_CACHE_OK\x10\x01\x12\x1b\n\x17READ_CONSISTENCY_STRONG\x10\x02*\xae\x01\n\x13MergeConflictPolicy\x12%\n!MERGE_CONFLICT_POLICY_UNSPECIFIED\x10\x00\x12%\n!MERGE_CONFLICT_POLICY_KEEP_TARGET\x10\x01\x12\'\n#MERGE_CONFLICT_POLICY_PREFER_SOURCE\x10\x02\x12 \n\x1cMERGE_CONFLICT_POLICY_NEWEST\x10\x03*\x87\x01\n\rPriorityClass\x12\x1e\n\x1aPRIORITY_CLASS_UNSPECIFIED\x10\x00\x12\x1e\n\x1aPRIORITY_CLASS_INTERACTIVE\x10\x01\x12\x18\n\x14PRIORITY_CLASS_BATCH\x10\x02\x12\x1c\n\x18PRIORITY_CLASS_LOAD_TEST\x10\x03\x32\xa3\t\n\x0bUserService\x12?\n\nCreateUser\x12\x17.user.CreateUserRequest\x1a\x18.user.CreateUserResponse\x12\x36\n\x07GetUser\x12\x14.user.GetUserRequest\x1a\x15.user.GetUserResponse\x12H\n\rGetUserAtTime\x12\x1a.user.GetUserAtTimeRequest\x1a\x1b.user.GetUserAtTimeResponse\x12?\n\nUpdateUser\x12\x17.user.UpdateUserRequest\x1a\x18.user.UpdateUserResponse\x12W\n\x12RequestEmailChange\x12\x1f.user.RequestEmailChangeRequest\x1a .user.RequestEmailChangeResponse\x12W\n\x12\x43onfirmEmailChange\x12\x1f.user.ConfirmEmailChangeRequest\x1a .user.ConfirmEmailChangeResponse\x12?\n\nDeleteUser\x12\x17.user.DeleteUserRequest\x1a\x18.user.DeleteUserResponse\x12<\n\tListUsers\x12\x16.user.ListUsersRequest\x1a\x17.user.ListUsersResponse\x12<\n\tEraseUser\x12\x16.user.EraseUserRequest\x1a\x17.user.EraseUserResponse\x12K\n\x0e\x45xportUserData\x12\x1b.user.ExportUserDataRequest\x1a\x1c.user.ExportUserDataResponse\x12?\n\nRevertUser\x12\x17.user.RevertUserRequest\x1a\x18.user.RevertUserResponse\x12?\n\nMergeUsers\x12\x17.user.MergeUsersRequest\x1a\x18.user.MergeUsersResponse\x12<\n\tTestError\x12\x16.user.TestErrorRequest\x1a\x17.user.TestErrorResponse\x12\x42\n\x0bTestLatency\x12\x18.user.TestLatencyRequest\x1a\x19.user.TestLatencyResponse\x12P\n\x11TestLatencyStream\x12\x1e.user.TestLatencyStreamRequest\x1a\x19.user.TestLatencyResponse0\x01\x12\x43\n\nTestStream\x12\x17.user.TestStreamRequest\x1a\x18.user.TestStreamResponse(\x01\x30\x01\x12\x39\n\x08TestEcho\x12\x15.user.TestEchoRequest\x1a\x16.user.TestEchoResponse2\x98\x03\n\x0c\x41\x64minService\x12H\n\rGetCacheStats\x12\x1a.user.GetCacheStatsRequest\x1a\x1b.user.GetCacheStatsResponse\x12Q\n\x10GetRuntimeConfig\x12\x1d.user.GetRuntimeConfigRequest\x1a\x1e.user.GetRuntimeConfigResponse\x12Q\n\x10SetRuntimeConfig\x12\x1d.user.SetRuntimeConfigRequest\x1a\x1e.user.SetRuntimeConfigResponse\x12?\n\nGetVersion\x12\x17.user.GetVersionRequest\x1a\x18.user.GetVersionResponse\x12W\n\x12GetUserAttribution\x12\x1f.user.GetUserAttributionRequest\x1a .user.GetUserAttributionResponseB\x06Z\x04./pbb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -32,32 +33,140 @@ _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'user_pb2', _globals)
 if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'Z\004./pb'
-  _globals['_USER']._serialized_start=20
-  _globals['_USER']._serialized_end=120
-  _globals['_CREATEUSERREQUEST']._serialized_start=122
-  _globals['_CREATEUSERREQUEST']._serialized_end=183
-  _globals['_CREATEUSERRESPONSE']._serialized_start=185
-  _globals['_CREATEUSERRESPONSE']._serialized_end=248
-  _globals['_GETUSERREQUEST']._serialized_start=250
-  _globals['_GETUSERREQUEST']._serialized_end=278
-  _globals['_GETUSERRESPONSE']._serialized_start=280
-  _globals['_GETUSERRESPONSE']._serialized_end=340
-  _globals['_UPDATEUSERREQUEST']._serialized_start=342
-  _globals['_UPDATEUSERREQUEST']._serialized_end=415
-  _globals['_UPDATEUSERRESPONSE']._serialized_start=417
-  _globals['_UPDATEUSERRESPONSE']._serialized_end=480
-  _globals['_DELETEUSERREQUEST']._serialized_start=482
-  _globals['_DELETEUSERREQUEST']._serialized_end=513
-  _globals['_DELETEUSERRESPONSE']._serialized_start=515
-  _globals['_DELETEUSERRESPONSE']._serialized_end=552
-  _globals['_LISTUSERSREQUEST']._serialized_start=554
-  _globals['_LISTUSERSREQUEST']._serialized_end=601
-  _globals['_LISTUSERSRESPONSE']._serialized_start=603
-  _globals['_LISTUSERSRESPONSE']._serialized_end=681
-  _globals['_TESTERRORREQUEST']._serialized_start=683
-  _globals['_TESTERRORREQUEST']._serialized_end=722
-  _globals['_TESTERRORRESPONSE']._serialized_start=724
-  _globals['_TESTERRORRESPONSE']._serialized_end=778
-  _globals['_USERSERVICE']._serialized_start=781
-  _globals['_USERSERVICE']._serialized_end=1169
+  _globals['_CREATEUSERRESPONSE'].fields_by_name['message']._loaded_options = None
+  _globals['_CREATEUSERRESPONSE'].fields_by_name['message']._serialized_options = b'\030\001'
+  _globals['_GETUSERRESPONSE'].fields_by_name['message']._loaded_options = None
+  _globals['_GETUSERRESPONSE'].fields_by_name['message']._serialized_options = b'\030\001'
+  _globals['_GETUSERATTIMERESPONSE'].fields_by_name['message']._loaded_options = None
+  _globals['_GETUSERATTIMERESPONSE'].fields_by_name['message']._serialized_options = b'\030\001'
+  _globals['_UPDATEUSERRESPONSE'].fields_by_name['message']._loaded_options = None
+  _globals['_UPDATEUSERRESPONSE'].fields_by_name['message']._serialized_options = b'\030\001'
+  _globals['_REQUESTEMAILCHANGERESPONSE'].fields_by_name['message']._loaded_options = None
+  _globals['_REQUESTEMAILCHANGERESPONSE'].fields_by_name['message']._serialized_options = b'\030\001'
+  _globals['_CONFIRMEMAILCHANGERESPONSE'].fields_by_name['message']._loaded_options = None
+  _globals['_CONFIRMEMAILCHANGERESPONSE'].fields_by_name['message']._serialized_options = b'\030\001'
+  _globals['_DELETEUSERRESPONSE'].fields_by_name['message']._loaded_options = None
+  _globals['_DELETEUSERRESPONSE'].fields_by_name['message']._serialized_options = b'\030\001'
+  _globals['_ERASEUSERRESPONSE'].fields_by_name['message']._loaded_options = None
+  _globals['_ERASEUSERRESPONSE'].fields_by_name['message']._serialized_options = b'\030\001'
+  _globals['_REVERTUSERRESPONSE'].fields_by_name['message']._loaded_options = None
+  _globals['_REVERTUSERRESPONSE'].fields_by_name['message']._serialized_options = b'\030\001'
+  _globals['_MERGEUSERSRESPONSE'].fields_by_name['message']._loaded_options = None
+  _globals['_MERGEUSERSRESPONSE'].fields_by_name['message']._serialized_options = b'\030\001'
+  _globals['_LISTUSERSRESPONSE'].fields_by_name['message']._loaded_options = None
+  _globals['_LISTUSERSRESPONSE'].fields_by_name['message']._serialized_options = b'\030\001'
+  _globals['_USERSTATUS']._serialized_start=5393
+  _globals['_USERSTATUS']._serialized_end=5507
+  _globals['_OUTCOME']._serialized_start=5510
+  _globals['_OUTCOME']._serialized_end=5781
+  _globals['_READCONSISTENCY']._serialized_start=5783
+  _globals['_READCONSISTENCY']._serialized_end=5894
+  _globals['_MERGECONFLICTPOLICY']._serialized_start=5897
+  _globals['_MERGECONFLICTPOLICY']._serialized_end=6071
+  _globals['_PRIORITYCLASS']._serialized_start=6074
+  _globals['_PRIORITYCLASS']._serialized_end=6209
+  _globals['_USER']._serialized_start=55
+  _globals['_USER']._serialized_end=210
+  _globals['_CREATEUSERREQUEST']._serialized_start=212
+  _globals['_CREATEUSERREQUEST']._serialized_end=285
+  _globals['_CREATEUSERRESPONSE']._serialized_start=287
+  _globals['_CREATEUSERRESPONSE']._serialized_end=386
+  _globals['_GETUSERREQUEST']._serialized_start=389
+  _globals['_GETUSERREQUEST']._serialized_end=519
+  _globals['_GETUSERRESPONSE']._serialized_start=522
+  _globals['_GETUSERRESPONSE']._serialized_end=655
+  _globals['_GETUSERATTIMEREQUEST']._serialized_start=657
+  _globals['_GETUSERATTIMEREQUEST']._serialized_end=703
+  _globals['_GETUSERATTIMERESPONSE']._serialized_start=706
+  _globals['_GETUSERATTIMERESPONSE']._serialized_end=866
+  _globals['_UPDATEUSERREQUEST']._serialized_start=868
+  _globals['_UPDATEUSERREQUEST']._serialized_end=941
+  _globals['_UPDATEUSERRESPONSE']._serialized_start=943
+  _globals['_UPDATEUSERRESPONSE']._serialized_end=1042
+  _globals['_REQUESTEMAILCHANGEREQUEST']._serialized_start=1044
+  _globals['_REQUESTEMAILCHANGEREQUEST']._serialized_end=1102
+  _globals['_REQUESTEMAILCHANGERESPONSE']._serialized_start=1104
+  _globals['_REQUESTEMAILCHANGERESPONSE']._serialized_end=1205
+  _globals['_CONFIRMEMAILCHANGEREQUEST']._serialized_start=1207
+  _globals['_CONFIRMEMAILCHANGEREQUEST']._serialized_end=1261
+  _globals['_CONFIRMEMAILCHANGERESPONSE']._serialized_start=1263
+  _globals['_CONFIRMEMAILCHANGERESPONSE']._serialized_end=1370
+  _globals['_DELETEUSERREQUEST']._serialized_start=1372
+  _globals['_DELETEUSERREQUEST']._serialized_end=1403
+  _globals['_DELETEUSERRESPONSE']._serialized_start=1405
+  _globals['_DELETEUSERRESPONSE']._serialized_end=1478
+  _globals['_ERASEUSERREQUEST']._serialized_start=1480
+  _globals['_ERASEUSERREQUEST']._serialized_end=1526
+  _globals['_ERASEUSERRESPONSE']._serialized_start=1528
+  _globals['_ERASEUSERRESPONSE']._serialized_end=1643
+  _globals['_EXPORTUSERDATAREQUEST']._serialized_start=1645
+  _globals['_EXPORTUSERDATAREQUEST']._serialized_end=1680
+  _globals['_EXPORTUSERDATARESPONSE']._serialized_start=1682
+  _globals['_EXPORTUSERDATARESPONSE']._serialized_end=1793
+  _globals['_REVERTUSERREQUEST']._serialized_start=1795
+  _globals['_REVERTUSERREQUEST']._serialized_end=1862
+  _globals['_REVERTUSERRESPONSE']._serialized_start=1864
+  _globals['_REVERTUSERRESPONSE']._serialized_end=1987
+  _globals['_MERGEUSERSREQUEST']._serialized_start=1989
+  _globals['_MERGEUSERSREQUEST']._serialized_end=2114
+  _globals['_MERGEUSERSRESPONSE']._serialized_start=2116
+  _globals['_MERGEUSERSRESPONSE']._serialized_end=2239
+  _globals['_LISTUSERSREQUEST']._serialized_start=2242
+  _globals['_LISTUSERSREQUEST']._serialized_end=2454
+  _globals['_LISTUSERSRESPONSE']._serialized_start=2457
+  _globals['_LISTUSERSRESPONSE']._serialized_end=2640
+  _globals['_TESTERRORREQUEST']._serialized_start=2642
+  _globals['_TESTERRORREQUEST']._serialized_end=2681
+  _globals['_TESTERRORRESPONSE']._serialized_start=2683
+  _globals['_TESTERRORRESPONSE']._serialized_end=2737
+  _globals['_TESTLATENCYREQUEST']._serialized_start=2739
+  _globals['_TESTLATENCYREQUEST']._serialized_end=2799
+  _globals['_TESTLATENCYSTREAMREQUEST']._serialized_start=2801
+  _globals['_TESTLATENCYSTREAMREQUEST']._serialized_end=2882
+  _globals['_TESTLATENCYRESPONSE']._serialized_start=2885
+  _globals['_TESTLATENCYRESPONSE']._serialized_end=3013
+  _globals['_TESTSTREAMREQUEST']._serialized_start=3015
+  _globals['_TESTSTREAMREQUEST']._serialized_end=3119
+  _globals['_TESTSTREAMRESPONSE']._serialized_start=3122
+  _globals['_TESTSTREAMRESPONSE']._serialized_end=3257
+  _globals['_TESTECHOREQUEST']._serialized_start=3259
+  _globals['_TESTECHOREQUEST']._serialized_end=3293
+  _globals['_METADATAENTRY']._serialized_start=3295
+  _globals['_METADATAENTRY']._serialized_end=3339
+  _globals['_TESTECHORESPONSE']._serialized_start=3342
+  _globals['_TESTECHORESPONSE']._serialized_end=3663
+  _globals['_GETCACHESTATSREQUEST']._serialized_start=3665
+  _globals['_GETCACHESTATSREQUEST']._serialized_end=3708
+  _globals['_CACHENAMESPACESTATS']._serialized_start=3711
+  _globals['_CACHENAMESPACESTATS']._serialized_end=3862
+  _globals['_CACHEMEMORYSTATS']._serialized_start=3865
+  _globals['_CACHEMEMORYSTATS']._serialized_end=4024
+  _globals['_GETCACHESTATSRESPONSE']._serialized_start=4027
+  _globals['_GETCACHESTATSRESPONSE']._serialized_end=4229
+  _globals['_RUNTIMECONFIG']._serialized_start=4232
+  _globals['_RUNTIMECONFIG']._serialized_end=4580
+  _globals['_CALLERPRIORITY']._serialized_start=4582
+  _globals['_CALLERPRIORITY']._serialized_end=4650
+  _globals['_MODULELOGLEVEL']._serialized_start=4652
+  _globals['_MODULELOGLEVEL']._serialized_end=4699
+  _globals['_GETRUNTIMECONFIGREQUEST']._serialized_start=4701
+  _globals['_GETRUNTIMECONFIGREQUEST']._serialized_end=4726
+  _globals['_GETRUNTIMECONFIGRESPONSE']._serialized_start=4728
+  _globals['_GETRUNTIMECONFIGRESPONSE']._serialized_end=4840
+  _globals['_SETRUNTIMECONFIGREQUEST']._serialized_start=4842
+  _globals['_SETRUNTIMECONFIGREQUEST']._serialized_end=4925
+  _globals['_SETRUNTIMECONFIGRESPONSE']._serialized_start=4927
+  _globals['_SETRUNTIMECONFIGRESPONSE']._serialized_end=5039
+  _globals['_GETVERSIONREQUEST']._serialized_start=5041
+  _globals['_GETVERSIONREQUEST']._serialized_end=5060
+  _globals['_GETVERSIONRESPONSE']._serialized_start=5063
+  _globals['_GETVERSIONRESPONSE']._serialized_end=5223
+  _globals['_GETUSERATTRIBUTIONREQUEST']._serialized_start=5225
+  _globals['_GETUSERATTRIBUTIONREQUEST']._serialized_end=5264
+  _globals['_GETUSERATTRIBUTIONRESPONSE']._serialized_start=5266
+  _globals['_GETUSERATTRIBUTIONRESPONSE']._serialized_end=5391
+  _globals['_USERSERVICE']._serialized_start=6212
+  _globals['_USERSERVICE']._serialized_end=7399
+  _globals['_ADMINSERVICE']._serialized_start=7402
+  _globals['_ADMINSERVICE']._serialized_end=7810
 # @@protoc_insertion_point(module_scope)
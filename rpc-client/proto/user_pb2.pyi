@@ -1,4 +1,6 @@
+from google.protobuf import field_mask_pb2 as _field_mask_pb2
 from google.protobuf.internal import containers as _containers
+from google.protobuf.internal import enum_type_wrapper as _enum_type_wrapper
 from google.protobuf import descriptor as _descriptor
 from google.protobuf import message as _message
 from collections.abc import Iterable as _Iterable, Mapping as _Mapping
@@ -6,53 +8,162 @@ from typing import ClassVar as _ClassVar, Optional as _Optional, Union as _Union
 
 DESCRIPTOR: _descriptor.FileDescriptor
 
+class UserStatus(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
+    __slots__ = ()
+    USER_STATUS_UNSPECIFIED: _ClassVar[UserStatus]
+    USER_STATUS_ACTIVE: _ClassVar[UserStatus]
+    USER_STATUS_EXPIRED: _ClassVar[UserStatus]
+    USER_STATUS_MERGED: _ClassVar[UserStatus]
+
+class Outcome(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
+    __slots__ = ()
+    OUTCOME_UNSPECIFIED: _ClassVar[Outcome]
+    OUTCOME_CREATED: _ClassVar[Outcome]
+    OUTCOME_RETRIEVED: _ClassVar[Outcome]
+    OUTCOME_NOT_MODIFIED: _ClassVar[Outcome]
+    OUTCOME_UPDATED: _ClassVar[Outcome]
+    OUTCOME_DELETED: _ClassVar[Outcome]
+    OUTCOME_EMAIL_CHANGE_REQUESTED: _ClassVar[Outcome]
+    OUTCOME_EMAIL_CHANGED: _ClassVar[Outcome]
+    OUTCOME_ERASED: _ClassVar[Outcome]
+    OUTCOME_REVERTED: _ClassVar[Outcome]
+    OUTCOME_MERGED: _ClassVar[Outcome]
+
+class ReadConsistency(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
+    __slots__ = ()
+    READ_CONSISTENCY_UNSPECIFIED: _ClassVar[ReadConsistency]
+    READ_CONSISTENCY_CACHE_OK: _ClassVar[ReadConsistency]
+    READ_CONSISTENCY_STRONG: _ClassVar[ReadConsistency]
+
+class MergeConflictPolicy(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
+    __slots__ = ()
+    MERGE_CONFLICT_POLICY_UNSPECIFIED: _ClassVar[MergeConflictPolicy]
+    MERGE_CONFLICT_POLICY_KEEP_TARGET: _ClassVar[MergeConflictPolicy]
+    MERGE_CONFLICT_POLICY_PREFER_SOURCE: _ClassVar[MergeConflictPolicy]
+    MERGE_CONFLICT_POLICY_NEWEST: _ClassVar[MergeConflictPolicy]
+
+class PriorityClass(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
+    __slots__ = ()
+    PRIORITY_CLASS_UNSPECIFIED: _ClassVar[PriorityClass]
+    PRIORITY_CLASS_INTERACTIVE: _ClassVar[PriorityClass]
+    PRIORITY_CLASS_BATCH: _ClassVar[PriorityClass]
+    PRIORITY_CLASS_LOAD_TEST: _ClassVar[PriorityClass]
+USER_STATUS_UNSPECIFIED: UserStatus
+USER_STATUS_ACTIVE: UserStatus
+USER_STATUS_EXPIRED: UserStatus
+USER_STATUS_MERGED: UserStatus
+OUTCOME_UNSPECIFIED: Outcome
+OUTCOME_CREATED: Outcome
+OUTCOME_RETRIEVED: Outcome
+OUTCOME_NOT_MODIFIED: Outcome
+OUTCOME_UPDATED: Outcome
+OUTCOME_DELETED: Outcome
+OUTCOME_EMAIL_CHANGE_REQUESTED: Outcome
+OUTCOME_EMAIL_CHANGED: Outcome
+OUTCOME_ERASED: Outcome
+OUTCOME_REVERTED: Outcome
+OUTCOME_MERGED: Outcome
+READ_CONSISTENCY_UNSPECIFIED: ReadConsistency
+READ_CONSISTENCY_CACHE_OK: ReadConsistency
+READ_CONSISTENCY_STRONG: ReadConsistency
+MERGE_CONFLICT_POLICY_UNSPECIFIED: MergeConflictPolicy
+MERGE_CONFLICT_POLICY_KEEP_TARGET: MergeConflictPolicy
+MERGE_CONFLICT_POLICY_PREFER_SOURCE: MergeConflictPolicy
+MERGE_CONFLICT_POLICY_NEWEST: MergeConflictPolicy
+PRIORITY_CLASS_UNSPECIFIED: PriorityClass
+PRIORITY_CLASS_INTERACTIVE: PriorityClass
+PRIORITY_CLASS_BATCH: PriorityClass
+PRIORITY_CLASS_LOAD_TEST: PriorityClass
+
 class User(_message.Message):
-    __slots__ = ("id", "name", "email", "age", "created_at", "updated_at")
+    __slots__ = ("id", "name", "email", "age", "created_at", "updated_at", "status", "merged_into")
     ID_FIELD_NUMBER: _ClassVar[int]
     NAME_FIELD_NUMBER: _ClassVar[int]
     EMAIL_FIELD_NUMBER: _ClassVar[int]
     AGE_FIELD_NUMBER: _ClassVar[int]
     CREATED_AT_FIELD_NUMBER: _ClassVar[int]
     UPDATED_AT_FIELD_NUMBER: _ClassVar[int]
+    STATUS_FIELD_NUMBER: _ClassVar[int]
+    MERGED_INTO_FIELD_NUMBER: _ClassVar[int]
     id: str
     name: str
     email: str
     age: int
     created_at: int
     updated_at: int
-    def __init__(self, id: _Optional[str] = ..., name: _Optional[str] = ..., email: _Optional[str] = ..., age: _Optional[int] = ..., created_at: _Optional[int] = ..., updated_at: _Optional[int] = ...) -> None: ...
+    status: UserStatus
+    merged_into: str
+    def __init__(self, id: _Optional[str] = ..., name: _Optional[str] = ..., email: _Optional[str] = ..., age: _Optional[int] = ..., created_at: _Optional[int] = ..., updated_at: _Optional[int] = ..., status: _Optional[_Union[UserStatus, str]] = ..., merged_into: _Optional[str] = ...) -> None: ...
 
 class CreateUserRequest(_message.Message):
-    __slots__ = ("name", "email", "age")
+    __slots__ = ("name", "email", "age", "id")
     NAME_FIELD_NUMBER: _ClassVar[int]
     EMAIL_FIELD_NUMBER: _ClassVar[int]
     AGE_FIELD_NUMBER: _ClassVar[int]
+    ID_FIELD_NUMBER: _ClassVar[int]
     name: str
     email: str
     age: int
-    def __init__(self, name: _Optional[str] = ..., email: _Optional[str] = ..., age: _Optional[int] = ...) -> None: ...
+    id: str
+    def __init__(self, name: _Optional[str] = ..., email: _Optional[str] = ..., age: _Optional[int] = ..., id: _Optional[str] = ...) -> None: ...
 
 class CreateUserResponse(_message.Message):
-    __slots__ = ("user", "message")
+    __slots__ = ("user", "message", "outcome")
     USER_FIELD_NUMBER: _ClassVar[int]
     MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    OUTCOME_FIELD_NUMBER: _ClassVar[int]
     user: User
     message: str
-    def __init__(self, user: _Optional[_Union[User, _Mapping]] = ..., message: _Optional[str] = ...) -> None: ...
+    outcome: Outcome
+    def __init__(self, user: _Optional[_Union[User, _Mapping]] = ..., message: _Optional[str] = ..., outcome: _Optional[_Union[Outcome, str]] = ...) -> None: ...
 
 class GetUserRequest(_message.Message):
-    __slots__ = ("id",)
+    __slots__ = ("id", "read_mask", "read_consistency")
     ID_FIELD_NUMBER: _ClassVar[int]
+    READ_MASK_FIELD_NUMBER: _ClassVar[int]
+    READ_CONSISTENCY_FIELD_NUMBER: _ClassVar[int]
     id: str
-    def __init__(self, id: _Optional[str] = ...) -> None: ...
+    read_mask: _field_mask_pb2.FieldMask
+    read_consistency: ReadConsistency
+    def __init__(self, id: _Optional[str] = ..., read_mask: _Optional[_Union[_field_mask_pb2.FieldMask, _Mapping]] = ..., read_consistency: _Optional[_Union[ReadConsistency, str]] = ...) -> None: ...
 
 class GetUserResponse(_message.Message):
-    __slots__ = ("user", "message")
+    __slots__ = ("user", "message", "stale", "not_modified", "outcome")
+    USER_FIELD_NUMBER: _ClassVar[int]
+    MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    STALE_FIELD_NUMBER: _ClassVar[int]
+    NOT_MODIFIED_FIELD_NUMBER: _ClassVar[int]
+    OUTCOME_FIELD_NUMBER: _ClassVar[int]
+    user: User
+    message: str
+    stale: bool
+    not_modified: bool
+    outcome: Outcome
+    def __init__(self, user: _Optional[_Union[User, _Mapping]] = ..., message: _Optional[str] = ..., stale: _Optional[bool] = ..., not_modified: _Optional[bool] = ..., outcome: _Optional[_Union[Outcome, str]] = ...) -> None: ...
+
+class GetUserAtTimeRequest(_message.Message):
+    __slots__ = ("id", "at")
+    ID_FIELD_NUMBER: _ClassVar[int]
+    AT_FIELD_NUMBER: _ClassVar[int]
+    id: str
+    at: int
+    def __init__(self, id: _Optional[str] = ..., at: _Optional[int] = ...) -> None: ...
+
+class GetUserAtTimeResponse(_message.Message):
+    __slots__ = ("user", "valid_from", "valid_to", "message", "version_id", "outcome")
     USER_FIELD_NUMBER: _ClassVar[int]
+    VALID_FROM_FIELD_NUMBER: _ClassVar[int]
+    VALID_TO_FIELD_NUMBER: _ClassVar[int]
     MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    VERSION_ID_FIELD_NUMBER: _ClassVar[int]
+    OUTCOME_FIELD_NUMBER: _ClassVar[int]
     user: User
+    valid_from: int
+    valid_to: int
     message: str
-    def __init__(self, user: _Optional[_Union[User, _Mapping]] = ..., message: _Optional[str] = ...) -> None: ...
+    version_id: int
+    outcome: Outcome
+    def __init__(self, user: _Optional[_Union[User, _Mapping]] = ..., valid_from: _Optional[int] = ..., valid_to: _Optional[int] = ..., message: _Optional[str] = ..., version_id: _Optional[int] = ..., outcome: _Optional[_Union[Outcome, str]] = ...) -> None: ...
 
 class UpdateUserRequest(_message.Message):
     __slots__ = ("id", "name", "email", "age")
@@ -67,12 +178,50 @@ class UpdateUserRequest(_message.Message):
     def __init__(self, id: _Optional[str] = ..., name: _Optional[str] = ..., email: _Optional[str] = ..., age: _Optional[int] = ...) -> None: ...
 
 class UpdateUserResponse(_message.Message):
-    __slots__ = ("user", "message")
+    __slots__ = ("user", "message", "outcome")
+    USER_FIELD_NUMBER: _ClassVar[int]
+    MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    OUTCOME_FIELD_NUMBER: _ClassVar[int]
+    user: User
+    message: str
+    outcome: Outcome
+    def __init__(self, user: _Optional[_Union[User, _Mapping]] = ..., message: _Optional[str] = ..., outcome: _Optional[_Union[Outcome, str]] = ...) -> None: ...
+
+class RequestEmailChangeRequest(_message.Message):
+    __slots__ = ("id", "new_email")
+    ID_FIELD_NUMBER: _ClassVar[int]
+    NEW_EMAIL_FIELD_NUMBER: _ClassVar[int]
+    id: str
+    new_email: str
+    def __init__(self, id: _Optional[str] = ..., new_email: _Optional[str] = ...) -> None: ...
+
+class RequestEmailChangeResponse(_message.Message):
+    __slots__ = ("expires_at", "message", "outcome")
+    EXPIRES_AT_FIELD_NUMBER: _ClassVar[int]
+    MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    OUTCOME_FIELD_NUMBER: _ClassVar[int]
+    expires_at: int
+    message: str
+    outcome: Outcome
+    def __init__(self, expires_at: _Optional[int] = ..., message: _Optional[str] = ..., outcome: _Optional[_Union[Outcome, str]] = ...) -> None: ...
+
+class ConfirmEmailChangeRequest(_message.Message):
+    __slots__ = ("id", "token")
+    ID_FIELD_NUMBER: _ClassVar[int]
+    TOKEN_FIELD_NUMBER: _ClassVar[int]
+    id: str
+    token: str
+    def __init__(self, id: _Optional[str] = ..., token: _Optional[str] = ...) -> None: ...
+
+class ConfirmEmailChangeResponse(_message.Message):
+    __slots__ = ("user", "message", "outcome")
     USER_FIELD_NUMBER: _ClassVar[int]
     MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    OUTCOME_FIELD_NUMBER: _ClassVar[int]
     user: User
     message: str
-    def __init__(self, user: _Optional[_Union[User, _Mapping]] = ..., message: _Optional[str] = ...) -> None: ...
+    outcome: Outcome
+    def __init__(self, user: _Optional[_Union[User, _Mapping]] = ..., message: _Optional[str] = ..., outcome: _Optional[_Union[Outcome, str]] = ...) -> None: ...
 
 class DeleteUserRequest(_message.Message):
     __slots__ = ("id",)
@@ -81,28 +230,134 @@ class DeleteUserRequest(_message.Message):
     def __init__(self, id: _Optional[str] = ...) -> None: ...
 
 class DeleteUserResponse(_message.Message):
-    __slots__ = ("message",)
+    __slots__ = ("message", "outcome")
     MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    OUTCOME_FIELD_NUMBER: _ClassVar[int]
     message: str
-    def __init__(self, message: _Optional[str] = ...) -> None: ...
+    outcome: Outcome
+    def __init__(self, message: _Optional[str] = ..., outcome: _Optional[_Union[Outcome, str]] = ...) -> None: ...
+
+class EraseUserRequest(_message.Message):
+    __slots__ = ("id", "reason")
+    ID_FIELD_NUMBER: _ClassVar[int]
+    REASON_FIELD_NUMBER: _ClassVar[int]
+    id: str
+    reason: str
+    def __init__(self, id: _Optional[str] = ..., reason: _Optional[str] = ...) -> None: ...
+
+class EraseUserResponse(_message.Message):
+    __slots__ = ("certificate_id", "erased_at", "message", "outcome")
+    CERTIFICATE_ID_FIELD_NUMBER: _ClassVar[int]
+    ERASED_AT_FIELD_NUMBER: _ClassVar[int]
+    MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    OUTCOME_FIELD_NUMBER: _ClassVar[int]
+    certificate_id: str
+    erased_at: int
+    message: str
+    outcome: Outcome
+    def __init__(self, certificate_id: _Optional[str] = ..., erased_at: _Optional[int] = ..., message: _Optional[str] = ..., outcome: _Optional[_Union[Outcome, str]] = ...) -> None: ...
+
+class ExportUserDataRequest(_message.Message):
+    __slots__ = ("id",)
+    ID_FIELD_NUMBER: _ClassVar[int]
+    id: str
+    def __init__(self, id: _Optional[str] = ...) -> None: ...
+
+class ExportUserDataResponse(_message.Message):
+    __slots__ = ("document", "signature", "signature_algorithm", "exported_at")
+    DOCUMENT_FIELD_NUMBER: _ClassVar[int]
+    SIGNATURE_FIELD_NUMBER: _ClassVar[int]
+    SIGNATURE_ALGORITHM_FIELD_NUMBER: _ClassVar[int]
+    EXPORTED_AT_FIELD_NUMBER: _ClassVar[int]
+    document: bytes
+    signature: str
+    signature_algorithm: str
+    exported_at: int
+    def __init__(self, document: _Optional[bytes] = ..., signature: _Optional[str] = ..., signature_algorithm: _Optional[str] = ..., exported_at: _Optional[int] = ...) -> None: ...
+
+class RevertUserRequest(_message.Message):
+    __slots__ = ("id", "version_id", "reason")
+    ID_FIELD_NUMBER: _ClassVar[int]
+    VERSION_ID_FIELD_NUMBER: _ClassVar[int]
+    REASON_FIELD_NUMBER: _ClassVar[int]
+    id: str
+    version_id: int
+    reason: str
+    def __init__(self, id: _Optional[str] = ..., version_id: _Optional[int] = ..., reason: _Optional[str] = ...) -> None: ...
+
+class RevertUserResponse(_message.Message):
+    __slots__ = ("user", "audit_entry_id", "message", "outcome")
+    USER_FIELD_NUMBER: _ClassVar[int]
+    AUDIT_ENTRY_ID_FIELD_NUMBER: _ClassVar[int]
+    MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    OUTCOME_FIELD_NUMBER: _ClassVar[int]
+    user: User
+    audit_entry_id: str
+    message: str
+    outcome: Outcome
+    def __init__(self, user: _Optional[_Union[User, _Mapping]] = ..., audit_entry_id: _Optional[str] = ..., message: _Optional[str] = ..., outcome: _Optional[_Union[Outcome, str]] = ...) -> None: ...
+
+class MergeUsersRequest(_message.Message):
+    __slots__ = ("source_id", "target_id", "conflict_policy", "reason")
+    SOURCE_ID_FIELD_NUMBER: _ClassVar[int]
+    TARGET_ID_FIELD_NUMBER: _ClassVar[int]
+    CONFLICT_POLICY_FIELD_NUMBER: _ClassVar[int]
+    REASON_FIELD_NUMBER: _ClassVar[int]
+    source_id: str
+    target_id: str
+    conflict_policy: MergeConflictPolicy
+    reason: str
+    def __init__(self, source_id: _Optional[str] = ..., target_id: _Optional[str] = ..., conflict_policy: _Optional[_Union[MergeConflictPolicy, str]] = ..., reason: _Optional[str] = ...) -> None: ...
+
+class MergeUsersResponse(_message.Message):
+    __slots__ = ("user", "audit_entry_id", "message", "outcome")
+    USER_FIELD_NUMBER: _ClassVar[int]
+    AUDIT_ENTRY_ID_FIELD_NUMBER: _ClassVar[int]
+    MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    OUTCOME_FIELD_NUMBER: _ClassVar[int]
+    user: User
+    audit_entry_id: str
+    message: str
+    outcome: Outcome
+    def __init__(self, user: _Optional[_Union[User, _Mapping]] = ..., audit_entry_id: _Optional[str] = ..., message: _Optional[str] = ..., outcome: _Optional[_Union[Outcome, str]] = ...) -> None: ...
 
 class ListUsersRequest(_message.Message):
-    __slots__ = ("page", "limit")
+    __slots__ = ("page", "limit", "name_prefix", "email_prefix", "read_mask", "page_token", "read_consistency")
     PAGE_FIELD_NUMBER: _ClassVar[int]
     LIMIT_FIELD_NUMBER: _ClassVar[int]
+    NAME_PREFIX_FIELD_NUMBER: _ClassVar[int]
+    EMAIL_PREFIX_FIELD_NUMBER: _ClassVar[int]
+    READ_MASK_FIELD_NUMBER: _ClassVar[int]
+    PAGE_TOKEN_FIELD_NUMBER: _ClassVar[int]
+    READ_CONSISTENCY_FIELD_NUMBER: _ClassVar[int]
     page: int
     limit: int
-    def __init__(self, page: _Optional[int] = ..., limit: _Optional[int] = ...) -> None: ...
+    name_prefix: str
+    email_prefix: str
+    read_mask: _field_mask_pb2.FieldMask
+    page_token: str
+    read_consistency: ReadConsistency
+    def __init__(self, page: _Optional[int] = ..., limit: _Optional[int] = ..., name_prefix: _Optional[str] = ..., email_prefix: _Optional[str] = ..., read_mask: _Optional[_Union[_field_mask_pb2.FieldMask, _Mapping]] = ..., page_token: _Optional[str] = ..., read_consistency: _Optional[_Union[ReadConsistency, str]] = ...) -> None: ...
 
 class ListUsersResponse(_message.Message):
-    __slots__ = ("users", "total", "message")
+    __slots__ = ("users", "total", "message", "stale", "limit", "next_page_token", "outcome", "page")
     USERS_FIELD_NUMBER: _ClassVar[int]
     TOTAL_FIELD_NUMBER: _ClassVar[int]
     MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    STALE_FIELD_NUMBER: _ClassVar[int]
+    LIMIT_FIELD_NUMBER: _ClassVar[int]
+    NEXT_PAGE_TOKEN_FIELD_NUMBER: _ClassVar[int]
+    OUTCOME_FIELD_NUMBER: _ClassVar[int]
+    PAGE_FIELD_NUMBER: _ClassVar[int]
     users: _containers.RepeatedCompositeFieldContainer[User]
     total: int
     message: str
-    def __init__(self, users: _Optional[_Iterable[_Union[User, _Mapping]]] = ..., total: _Optional[int] = ..., message: _Optional[str] = ...) -> None: ...
+    stale: bool
+    limit: int
+    next_page_token: str
+    outcome: Outcome
+    page: int
+    def __init__(self, users: _Optional[_Iterable[_Union[User, _Mapping]]] = ..., total: _Optional[int] = ..., message: _Optional[str] = ..., stale: _Optional[bool] = ..., limit: _Optional[int] = ..., next_page_token: _Optional[str] = ..., outcome: _Optional[_Union[Outcome, str]] = ..., page: _Optional[int] = ...) -> None: ...
 
 class TestErrorRequest(_message.Message):
     __slots__ = ("status_code",)
@@ -117,3 +372,273 @@ class TestErrorResponse(_message.Message):
     message: str
     trace_id: str
     def __init__(self, message: _Optional[str] = ..., trace_id: _Optional[str] = ...) -> None: ...
+
+class TestLatencyRequest(_message.Message):
+    __slots__ = ("duration_ms", "jitter_ms")
+    DURATION_MS_FIELD_NUMBER: _ClassVar[int]
+    JITTER_MS_FIELD_NUMBER: _ClassVar[int]
+    duration_ms: int
+    jitter_ms: int
+    def __init__(self, duration_ms: _Optional[int] = ..., jitter_ms: _Optional[int] = ...) -> None: ...
+
+class TestLatencyStreamRequest(_message.Message):
+    __slots__ = ("duration_ms", "jitter_ms", "count")
+    DURATION_MS_FIELD_NUMBER: _ClassVar[int]
+    JITTER_MS_FIELD_NUMBER: _ClassVar[int]
+    COUNT_FIELD_NUMBER: _ClassVar[int]
+    duration_ms: int
+    jitter_ms: int
+    count: int
+    def __init__(self, duration_ms: _Optional[int] = ..., jitter_ms: _Optional[int] = ..., count: _Optional[int] = ...) -> None: ...
+
+class TestLatencyResponse(_message.Message):
+    __slots__ = ("requested_ms", "slept_ms", "deadline_remaining_ms", "sequence", "trace_id")
+    REQUESTED_MS_FIELD_NUMBER: _ClassVar[int]
+    SLEPT_MS_FIELD_NUMBER: _ClassVar[int]
+    DEADLINE_REMAINING_MS_FIELD_NUMBER: _ClassVar[int]
+    SEQUENCE_FIELD_NUMBER: _ClassVar[int]
+    TRACE_ID_FIELD_NUMBER: _ClassVar[int]
+    requested_ms: int
+    slept_ms: int
+    deadline_remaining_ms: int
+    sequence: int
+    trace_id: str
+    def __init__(self, requested_ms: _Optional[int] = ..., slept_ms: _Optional[int] = ..., deadline_remaining_ms: _Optional[int] = ..., sequence: _Optional[int] = ..., trace_id: _Optional[str] = ...) -> None: ...
+
+class TestStreamRequest(_message.Message):
+    __slots__ = ("payload", "response_size", "count", "responses_per_second")
+    PAYLOAD_FIELD_NUMBER: _ClassVar[int]
+    RESPONSE_SIZE_FIELD_NUMBER: _ClassVar[int]
+    COUNT_FIELD_NUMBER: _ClassVar[int]
+    RESPONSES_PER_SECOND_FIELD_NUMBER: _ClassVar[int]
+    payload: bytes
+    response_size: int
+    count: int
+    responses_per_second: int
+    def __init__(self, payload: _Optional[bytes] = ..., response_size: _Optional[int] = ..., count: _Optional[int] = ..., responses_per_second: _Optional[int] = ...) -> None: ...
+
+class TestStreamResponse(_message.Message):
+    __slots__ = ("sequence", "payload", "received_bytes", "total_received_bytes", "total_sent_bytes")
+    SEQUENCE_FIELD_NUMBER: _ClassVar[int]
+    PAYLOAD_FIELD_NUMBER: _ClassVar[int]
+    RECEIVED_BYTES_FIELD_NUMBER: _ClassVar[int]
+    TOTAL_RECEIVED_BYTES_FIELD_NUMBER: _ClassVar[int]
+    TOTAL_SENT_BYTES_FIELD_NUMBER: _ClassVar[int]
+    sequence: int
+    payload: bytes
+    received_bytes: int
+    total_received_bytes: int
+    total_sent_bytes: int
+    def __init__(self, sequence: _Optional[int] = ..., payload: _Optional[bytes] = ..., received_bytes: _Optional[int] = ..., total_received_bytes: _Optional[int] = ..., total_sent_bytes: _Optional[int] = ...) -> None: ...
+
+class TestEchoRequest(_message.Message):
+    __slots__ = ("message",)
+    MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    message: str
+    def __init__(self, message: _Optional[str] = ...) -> None: ...
+
+class MetadataEntry(_message.Message):
+    __slots__ = ("key", "values")
+    KEY_FIELD_NUMBER: _ClassVar[int]
+    VALUES_FIELD_NUMBER: _ClassVar[int]
+    key: str
+    values: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, key: _Optional[str] = ..., values: _Optional[_Iterable[str]] = ...) -> None: ...
+
+class TestEchoResponse(_message.Message):
+    __slots__ = ("message", "metadata", "peer_address", "peer_auth_type", "deadline_unix_ms", "deadline_remaining_ms", "request_compression", "response_compression", "accepted_compression", "instance_id", "method", "trace_id")
+    MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    METADATA_FIELD_NUMBER: _ClassVar[int]
+    PEER_ADDRESS_FIELD_NUMBER: _ClassVar[int]
+    PEER_AUTH_TYPE_FIELD_NUMBER: _ClassVar[int]
+    DEADLINE_UNIX_MS_FIELD_NUMBER: _ClassVar[int]
+    DEADLINE_REMAINING_MS_FIELD_NUMBER: _ClassVar[int]
+    REQUEST_COMPRESSION_FIELD_NUMBER: _ClassVar[int]
+    RESPONSE_COMPRESSION_FIELD_NUMBER: _ClassVar[int]
+    ACCEPTED_COMPRESSION_FIELD_NUMBER: _ClassVar[int]
+    INSTANCE_ID_FIELD_NUMBER: _ClassVar[int]
+    METHOD_FIELD_NUMBER: _ClassVar[int]
+    TRACE_ID_FIELD_NUMBER: _ClassVar[int]
+    message: str
+    metadata: _containers.RepeatedCompositeFieldContainer[MetadataEntry]
+    peer_address: str
+    peer_auth_type: str
+    deadline_unix_ms: int
+    deadline_remaining_ms: int
+    request_compression: str
+    response_compression: str
+    accepted_compression: _containers.RepeatedScalarFieldContainer[str]
+    instance_id: str
+    method: str
+    trace_id: str
+    def __init__(self, message: _Optional[str] = ..., metadata: _Optional[_Iterable[_Union[MetadataEntry, _Mapping]]] = ..., peer_address: _Optional[str] = ..., peer_auth_type: _Optional[str] = ..., deadline_unix_ms: _Optional[int] = ..., deadline_remaining_ms: _Optional[int] = ..., request_compression: _Optional[str] = ..., response_compression: _Optional[str] = ..., accepted_compression: _Optional[_Iterable[str]] = ..., instance_id: _Optional[str] = ..., method: _Optional[str] = ..., trace_id: _Optional[str] = ...) -> None: ...
+
+class GetCacheStatsRequest(_message.Message):
+    __slots__ = ("sample_size",)
+    SAMPLE_SIZE_FIELD_NUMBER: _ClassVar[int]
+    sample_size: int
+    def __init__(self, sample_size: _Optional[int] = ...) -> None: ...
+
+class CacheNamespaceStats(_message.Message):
+    __slots__ = ("namespace", "hits", "misses", "errors", "hit_ratio", "sampled_keys", "estimated_keys")
+    NAMESPACE_FIELD_NUMBER: _ClassVar[int]
+    HITS_FIELD_NUMBER: _ClassVar[int]
+    MISSES_FIELD_NUMBER: _ClassVar[int]
+    ERRORS_FIELD_NUMBER: _ClassVar[int]
+    HIT_RATIO_FIELD_NUMBER: _ClassVar[int]
+    SAMPLED_KEYS_FIELD_NUMBER: _ClassVar[int]
+    ESTIMATED_KEYS_FIELD_NUMBER: _ClassVar[int]
+    namespace: str
+    hits: int
+    misses: int
+    errors: int
+    hit_ratio: float
+    sampled_keys: int
+    estimated_keys: int
+    def __init__(self, namespace: _Optional[str] = ..., hits: _Optional[int] = ..., misses: _Optional[int] = ..., errors: _Optional[int] = ..., hit_ratio: _Optional[float] = ..., sampled_keys: _Optional[int] = ..., estimated_keys: _Optional[int] = ...) -> None: ...
+
+class CacheMemoryStats(_message.Message):
+    __slots__ = ("used_memory_bytes", "used_memory_peak_bytes", "max_memory_bytes", "max_memory_policy", "fragmentation_ratio")
+    USED_MEMORY_BYTES_FIELD_NUMBER: _ClassVar[int]
+    USED_MEMORY_PEAK_BYTES_FIELD_NUMBER: _ClassVar[int]
+    MAX_MEMORY_BYTES_FIELD_NUMBER: _ClassVar[int]
+    MAX_MEMORY_POLICY_FIELD_NUMBER: _ClassVar[int]
+    FRAGMENTATION_RATIO_FIELD_NUMBER: _ClassVar[int]
+    used_memory_bytes: int
+    used_memory_peak_bytes: int
+    max_memory_bytes: int
+    max_memory_policy: str
+    fragmentation_ratio: float
+    def __init__(self, used_memory_bytes: _Optional[int] = ..., used_memory_peak_bytes: _Optional[int] = ..., max_memory_bytes: _Optional[int] = ..., max_memory_policy: _Optional[str] = ..., fragmentation_ratio: _Optional[float] = ...) -> None: ...
+
+class GetCacheStatsResponse(_message.Message):
+    __slots__ = ("namespaces", "total_keys", "sampled_keys", "memory", "instance_id", "stats_since_unix_ms")
+    NAMESPACES_FIELD_NUMBER: _ClassVar[int]
+    TOTAL_KEYS_FIELD_NUMBER: _ClassVar[int]
+    SAMPLED_KEYS_FIELD_NUMBER: _ClassVar[int]
+    MEMORY_FIELD_NUMBER: _ClassVar[int]
+    INSTANCE_ID_FIELD_NUMBER: _ClassVar[int]
+    STATS_SINCE_UNIX_MS_FIELD_NUMBER: _ClassVar[int]
+    namespaces: _containers.RepeatedCompositeFieldContainer[CacheNamespaceStats]
+    total_keys: int
+    sampled_keys: int
+    memory: CacheMemoryStats
+    instance_id: str
+    stats_since_unix_ms: int
+    def __init__(self, namespaces: _Optional[_Iterable[_Union[CacheNamespaceStats, _Mapping]]] = ..., total_keys: _Optional[int] = ..., sampled_keys: _Optional[int] = ..., memory: _Optional[_Union[CacheMemoryStats, _Mapping]] = ..., instance_id: _Optional[str] = ..., stats_since_unix_ms: _Optional[int] = ...) -> None: ...
+
+class RuntimeConfig(_message.Message):
+    __slots__ = ("log_level", "user_cache_ttl_seconds", "list_cache_ttl_seconds", "trace_sample_ratio", "rate_limit_qps", "rate_limit_burst", "read_only", "low_priority_reserve", "caller_priorities", "module_log_levels", "race_user_reads")
+    LOG_LEVEL_FIELD_NUMBER: _ClassVar[int]
+    USER_CACHE_TTL_SECONDS_FIELD_NUMBER: _ClassVar[int]
+    LIST_CACHE_TTL_SECONDS_FIELD_NUMBER: _ClassVar[int]
+    TRACE_SAMPLE_RATIO_FIELD_NUMBER: _ClassVar[int]
+    RATE_LIMIT_QPS_FIELD_NUMBER: _ClassVar[int]
+    RATE_LIMIT_BURST_FIELD_NUMBER: _ClassVar[int]
+    READ_ONLY_FIELD_NUMBER: _ClassVar[int]
+    LOW_PRIORITY_RESERVE_FIELD_NUMBER: _ClassVar[int]
+    CALLER_PRIORITIES_FIELD_NUMBER: _ClassVar[int]
+    MODULE_LOG_LEVELS_FIELD_NUMBER: _ClassVar[int]
+    RACE_USER_READS_FIELD_NUMBER: _ClassVar[int]
+    log_level: str
+    user_cache_ttl_seconds: int
+    list_cache_ttl_seconds: int
+    trace_sample_ratio: float
+    rate_limit_qps: float
+    rate_limit_burst: int
+    read_only: bool
+    low_priority_reserve: float
+    caller_priorities: _containers.RepeatedCompositeFieldContainer[CallerPriority]
+    module_log_levels: _containers.RepeatedCompositeFieldContainer[ModuleLogLevel]
+    race_user_reads: bool
+    def __init__(self, log_level: _Optional[str] = ..., user_cache_ttl_seconds: _Optional[int] = ..., list_cache_ttl_seconds: _Optional[int] = ..., trace_sample_ratio: _Optional[float] = ..., rate_limit_qps: _Optional[float] = ..., rate_limit_burst: _Optional[int] = ..., read_only: _Optional[bool] = ..., low_priority_reserve: _Optional[float] = ..., caller_priorities: _Optional[_Iterable[_Union[CallerPriority, _Mapping]]] = ..., module_log_levels: _Optional[_Iterable[_Union[ModuleLogLevel, _Mapping]]] = ..., race_user_reads: _Optional[bool] = ...) -> None: ...
+
+class CallerPriority(_message.Message):
+    __slots__ = ("caller", "class")
+    CALLER_FIELD_NUMBER: _ClassVar[int]
+    CLASS_FIELD_NUMBER: _ClassVar[int]
+    caller: str
+    class: PriorityClass
+    def __init__(self, caller: _Optional[str] = ..., class: _Optional[_Union[PriorityClass, str]] = ...) -> None: ...
+
+class ModuleLogLevel(_message.Message):
+    __slots__ = ("module", "level")
+    MODULE_FIELD_NUMBER: _ClassVar[int]
+    LEVEL_FIELD_NUMBER: _ClassVar[int]
+    module: str
+    level: str
+    def __init__(self, module: _Optional[str] = ..., level: _Optional[str] = ...) -> None: ...
+
+class GetRuntimeConfigRequest(_message.Message):
+    __slots__ = ()
+    def __init__(self) -> None: ...
+
+class GetRuntimeConfigResponse(_message.Message):
+    __slots__ = ("config", "instance_id", "updated_at_unix_ms")
+    CONFIG_FIELD_NUMBER: _ClassVar[int]
+    INSTANCE_ID_FIELD_NUMBER: _ClassVar[int]
+    UPDATED_AT_UNIX_MS_FIELD_NUMBER: _ClassVar[int]
+    config: RuntimeConfig
+    instance_id: str
+    updated_at_unix_ms: int
+    def __init__(self, config: _Optional[_Union[RuntimeConfig, _Mapping]] = ..., instance_id: _Optional[str] = ..., updated_at_unix_ms: _Optional[int] = ...) -> None: ...
+
+class SetRuntimeConfigRequest(_message.Message):
+    __slots__ = ("config", "update_mask")
+    CONFIG_FIELD_NUMBER: _ClassVar[int]
+    UPDATE_MASK_FIELD_NUMBER: _ClassVar[int]
+    config: RuntimeConfig
+    update_mask: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, config: _Optional[_Union[RuntimeConfig, _Mapping]] = ..., update_mask: _Optional[_Iterable[str]] = ...) -> None: ...
+
+class SetRuntimeConfigResponse(_message.Message):
+    __slots__ = ("config", "instance_id", "updated_at_unix_ms")
+    CONFIG_FIELD_NUMBER: _ClassVar[int]
+    INSTANCE_ID_FIELD_NUMBER: _ClassVar[int]
+    UPDATED_AT_UNIX_MS_FIELD_NUMBER: _ClassVar[int]
+    config: RuntimeConfig
+    instance_id: str
+    updated_at_unix_ms: int
+    def __init__(self, config: _Optional[_Union[RuntimeConfig, _Mapping]] = ..., instance_id: _Optional[str] = ..., updated_at_unix_ms: _Optional[int] = ...) -> None: ...
+
+class GetVersionRequest(_message.Message):
+    __slots__ = ()
+    def __init__(self) -> None: ...
+
+class GetVersionResponse(_message.Message):
+    __slots__ = ("version", "commit", "build_date", "go_version", "modified", "instance_id", "started_at_unix_ms")
+    VERSION_FIELD_NUMBER: _ClassVar[int]
+    COMMIT_FIELD_NUMBER: _ClassVar[int]
+    BUILD_DATE_FIELD_NUMBER: _ClassVar[int]
+    GO_VERSION_FIELD_NUMBER: _ClassVar[int]
+    MODIFIED_FIELD_NUMBER: _ClassVar[int]
+    INSTANCE_ID_FIELD_NUMBER: _ClassVar[int]
+    STARTED_AT_UNIX_MS_FIELD_NUMBER: _ClassVar[int]
+    version: str
+    commit: str
+    build_date: str
+    go_version: str
+    modified: bool
+    instance_id: str
+    started_at_unix_ms: int
+    def __init__(self, version: _Optional[str] = ..., commit: _Optional[str] = ..., build_date: _Optional[str] = ..., go_version: _Optional[str] = ..., modified: _Optional[bool] = ..., instance_id: _Optional[str] = ..., started_at_unix_ms: _Optional[int] = ...) -> None: ...
+
+class GetUserAttributionRequest(_message.Message):
+    __slots__ = ("id",)
+    ID_FIELD_NUMBER: _ClassVar[int]
+    id: str
+    def __init__(self, id: _Optional[str] = ...) -> None: ...
+
+class GetUserAttributionResponse(_message.Message):
+    __slots__ = ("user_id", "created_by", "created_at", "updated_by", "updated_at")
+    USER_ID_FIELD_NUMBER: _ClassVar[int]
+    CREATED_BY_FIELD_NUMBER: _ClassVar[int]
+    CREATED_AT_FIELD_NUMBER: _ClassVar[int]
+    UPDATED_BY_FIELD_NUMBER: _ClassVar[int]
+    UPDATED_AT_FIELD_NUMBER: _ClassVar[int]
+    user_id: str
+    created_by: str
+    created_at: int
+    updated_by: str
+    updated_at: int
+    def __init__(self, user_id: _Optional[str] = ..., created_by: _Optional[str] = ..., created_at: _Optional[int] = ..., updated_by: _Optional[str] = ..., updated_at: _Optional[int] = ...) -> None: ...
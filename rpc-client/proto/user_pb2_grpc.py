@@ -45,11 +45,26 @@ class UserServiceStub(object):
                 request_serializer=user__pb2.GetUserRequest.SerializeToString,
                 response_deserializer=user__pb2.GetUserResponse.FromString,
                 _registered_method=True)
+        self.GetUserAtTime = channel.unary_unary(
+                '/user.UserService/GetUserAtTime',
+                request_serializer=user__pb2.GetUserAtTimeRequest.SerializeToString,
+                response_deserializer=user__pb2.GetUserAtTimeResponse.FromString,
+                _registered_method=True)
         self.UpdateUser = channel.unary_unary(
                 '/user.UserService/UpdateUser',
                 request_serializer=user__pb2.UpdateUserRequest.SerializeToString,
                 response_deserializer=user__pb2.UpdateUserResponse.FromString,
                 _registered_method=True)
+        self.RequestEmailChange = channel.unary_unary(
+                '/user.UserService/RequestEmailChange',
+                request_serializer=user__pb2.RequestEmailChangeRequest.SerializeToString,
+                response_deserializer=user__pb2.RequestEmailChangeResponse.FromString,
+                _registered_method=True)
+        self.ConfirmEmailChange = channel.unary_unary(
+                '/user.UserService/ConfirmEmailChange',
+                request_serializer=user__pb2.ConfirmEmailChangeRequest.SerializeToString,
+                response_deserializer=user__pb2.ConfirmEmailChangeResponse.FromString,
+                _registered_method=True)
         self.DeleteUser = channel.unary_unary(
                 '/user.UserService/DeleteUser',
                 request_serializer=user__pb2.DeleteUserRequest.SerializeToString,
@@ -60,11 +75,51 @@ class UserServiceStub(object):
                 request_serializer=user__pb2.ListUsersRequest.SerializeToString,
                 response_deserializer=user__pb2.ListUsersResponse.FromString,
                 _registered_method=True)
+        self.EraseUser = channel.unary_unary(
+                '/user.UserService/EraseUser',
+                request_serializer=user__pb2.EraseUserRequest.SerializeToString,
+                response_deserializer=user__pb2.EraseUserResponse.FromString,
+                _registered_method=True)
+        self.ExportUserData = channel.unary_unary(
+                '/user.UserService/ExportUserData',
+                request_serializer=user__pb2.ExportUserDataRequest.SerializeToString,
+                response_deserializer=user__pb2.ExportUserDataResponse.FromString,
+                _registered_method=True)
+        self.RevertUser = channel.unary_unary(
+                '/user.UserService/RevertUser',
+                request_serializer=user__pb2.RevertUserRequest.SerializeToString,
+                response_deserializer=user__pb2.RevertUserResponse.FromString,
+                _registered_method=True)
+        self.MergeUsers = channel.unary_unary(
+                '/user.UserService/MergeUsers',
+                request_serializer=user__pb2.MergeUsersRequest.SerializeToString,
+                response_deserializer=user__pb2.MergeUsersResponse.FromString,
+                _registered_method=True)
         self.TestError = channel.unary_unary(
                 '/user.UserService/TestError',
                 request_serializer=user__pb2.TestErrorRequest.SerializeToString,
                 response_deserializer=user__pb2.TestErrorResponse.FromString,
                 _registered_method=True)
+        self.TestLatency = channel.unary_unary(
+                '/user.UserService/TestLatency',
+                request_serializer=user__pb2.TestLatencyRequest.SerializeToString,
+                response_deserializer=user__pb2.TestLatencyResponse.FromString,
+                _registered_method=True)
+        self.TestLatencyStream = channel.unary_stream(
+                '/user.UserService/TestLatencyStream',
+                request_serializer=user__pb2.TestLatencyStreamRequest.SerializeToString,
+                response_deserializer=user__pb2.TestLatencyResponse.FromString,
+                _registered_method=True)
+        self.TestStream = channel.stream_stream(
+                '/user.UserService/TestStream',
+                request_serializer=user__pb2.TestStreamRequest.SerializeToString,
+                response_deserializer=user__pb2.TestStreamResponse.FromString,
+                _registered_method=True)
+        self.TestEcho = channel.unary_unary(
+                '/user.UserService/TestEcho',
+                request_serializer=user__pb2.TestEchoRequest.SerializeToString,
+                response_deserializer=user__pb2.TestEchoResponse.FromString,
+                _registered_method=True)
 
 
 class UserServiceServicer(object):
@@ -83,12 +138,33 @@ class UserServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def GetUserAtTime(self, request, context):
+        """Returns the version of a user that was current at a point in time.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
     def UpdateUser(self, request, context):
         """Missing associated documentation comment in .proto file."""
         context.set_code(grpc.StatusCode.UNIMPLEMENTED)
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def RequestEmailChange(self, request, context):
+        """Starts an email change; a confirmation token is sent to the new address.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ConfirmEmailChange(self, request, context):
+        """Applies a pending email change given its confirmation token.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
     def DeleteUser(self, request, context):
         """Missing associated documentation comment in .proto file."""
         context.set_code(grpc.StatusCode.UNIMPLEMENTED)
@@ -101,12 +177,71 @@ class UserServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def EraseUser(self, request, context):
+        """Permanently erases a user for right-to-be-forgotten requests.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ExportUserData(self, request, context):
+        """Returns all stored data for a user as a signed JSON document.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def RevertUser(self, request, context):
+        """Restores a user's fields to a recorded history version. Admin only.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def MergeUsers(self, request, context):
+        """Merges a duplicate user into another and soft-deletes it. Admin only.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
     def TestError(self, request, context):
         """Missing associated documentation comment in .proto file."""
         context.set_code(grpc.StatusCode.UNIMPLEMENTED)
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def TestLatency(self, request, context):
+        """Sleeps for the requested duration, within the caller's deadline, and
+        reports the timing seen by the server.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def TestLatencyStream(self, request, context):
+        """Like TestLatency, but sends count responses, sleeping before each.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def TestStream(self, request_iterator, context):
+        """Answers each message with count payloads of response_size bytes, sent at
+        responses_per_second, to exercise message size and flow control limits.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def TestEcho(self, request, context):
+        """Reports what the server saw of the call: metadata, peer, deadline and
+        compression, for debugging propagation through proxies.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_UserServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -120,11 +255,26 @@ def add_UserServiceServicer_to_server(servicer, server):
                     request_deserializer=user__pb2.GetUserRequest.FromString,
                     response_serializer=user__pb2.GetUserResponse.SerializeToString,
             ),
+            'GetUserAtTime': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetUserAtTime,
+                    request_deserializer=user__pb2.GetUserAtTimeRequest.FromString,
+                    response_serializer=user__pb2.GetUserAtTimeResponse.SerializeToString,
+            ),
             'UpdateUser': grpc.unary_unary_rpc_method_handler(
                     servicer.UpdateUser,
                     request_deserializer=user__pb2.UpdateUserRequest.FromString,
                     response_serializer=user__pb2.UpdateUserResponse.SerializeToString,
             ),
+            'RequestEmailChange': grpc.unary_unary_rpc_method_handler(
+                    servicer.RequestEmailChange,
+                    request_deserializer=user__pb2.RequestEmailChangeRequest.FromString,
+                    response_serializer=user__pb2.RequestEmailChangeResponse.SerializeToString,
+            ),
+            'ConfirmEmailChange': grpc.unary_unary_rpc_method_handler(
+                    servicer.ConfirmEmailChange,
+                    request_deserializer=user__pb2.ConfirmEmailChangeRequest.FromString,
+                    response_serializer=user__pb2.ConfirmEmailChangeResponse.SerializeToString,
+            ),
             'DeleteUser': grpc.unary_unary_rpc_method_handler(
                     servicer.DeleteUser,
                     request_deserializer=user__pb2.DeleteUserRequest.FromString,
@@ -135,11 +285,51 @@ def add_UserServiceServicer_to_server(servicer, server):
                     request_deserializer=user__pb2.ListUsersRequest.FromString,
                     response_serializer=user__pb2.ListUsersResponse.SerializeToString,
             ),
+            'EraseUser': grpc.unary_unary_rpc_method_handler(
+                    servicer.EraseUser,
+                    request_deserializer=user__pb2.EraseUserRequest.FromString,
+                    response_serializer=user__pb2.EraseUserResponse.SerializeToString,
+            ),
+            'ExportUserData': grpc.unary_unary_rpc_method_handler(
+                    servicer.ExportUserData,
+                    request_deserializer=user__pb2.ExportUserDataRequest.FromString,
+                    response_serializer=user__pb2.ExportUserDataResponse.SerializeToString,
+            ),
+            'RevertUser': grpc.unary_unary_rpc_method_handler(
+                    servicer.RevertUser,
+                    request_deserializer=user__pb2.RevertUserRequest.FromString,
+                    response_serializer=user__pb2.RevertUserResponse.SerializeToString,
+            ),
+            'MergeUsers': grpc.unary_unary_rpc_method_handler(
+                    servicer.MergeUsers,
+                    request_deserializer=user__pb2.MergeUsersRequest.FromString,
+                    response_serializer=user__pb2.MergeUsersResponse.SerializeToString,
+            ),
             'TestError': grpc.unary_unary_rpc_method_handler(
                     servicer.TestError,
                     request_deserializer=user__pb2.TestErrorRequest.FromString,
                     response_serializer=user__pb2.TestErrorResponse.SerializeToString,
             ),
+            'TestLatency': grpc.unary_unary_rpc_method_handler(
+                    servicer.TestLatency,
+                    request_deserializer=user__pb2.TestLatencyRequest.FromString,
+                    response_serializer=user__pb2.TestLatencyResponse.SerializeToString,
+            ),
+            'TestLatencyStream': grpc.unary_stream_rpc_method_handler(
+                    servicer.TestLatencyStream,
+                    request_deserializer=user__pb2.TestLatencyStreamRequest.FromString,
+                    response_serializer=user__pb2.TestLatencyResponse.SerializeToString,
+            ),
+            'TestStream': grpc.stream_stream_rpc_method_handler(
+                    servicer.TestStream,
+                    request_deserializer=user__pb2.TestStreamRequest.FromString,
+                    response_serializer=user__pb2.TestStreamResponse.SerializeToString,
+            ),
+            'TestEcho': grpc.unary_unary_rpc_method_handler(
+                    servicer.TestEcho,
+                    request_deserializer=user__pb2.TestEchoRequest.FromString,
+                    response_serializer=user__pb2.TestEchoResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'user.UserService', rpc_method_handlers)
@@ -206,6 +396,33 @@ class UserService(object):
             metadata,
             _registered_method=True)
 
+    @staticmethod
+    def GetUserAtTime(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.UserService/GetUserAtTime',
+            user__pb2.GetUserAtTimeRequest.SerializeToString,
+            user__pb2.GetUserAtTimeResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
     @staticmethod
     def UpdateUser(request,
             target,
@@ -233,6 +450,60 @@ class UserService(object):
             metadata,
             _registered_method=True)
 
+    @staticmethod
+    def RequestEmailChange(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.UserService/RequestEmailChange',
+            user__pb2.RequestEmailChangeRequest.SerializeToString,
+            user__pb2.RequestEmailChangeResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ConfirmEmailChange(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.UserService/ConfirmEmailChange',
+            user__pb2.ConfirmEmailChangeRequest.SerializeToString,
+            user__pb2.ConfirmEmailChangeResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
     @staticmethod
     def DeleteUser(request,
             target,
@@ -287,6 +558,114 @@ class UserService(object):
             metadata,
             _registered_method=True)
 
+    @staticmethod
+    def EraseUser(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.UserService/EraseUser',
+            user__pb2.EraseUserRequest.SerializeToString,
+            user__pb2.EraseUserResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ExportUserData(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.UserService/ExportUserData',
+            user__pb2.ExportUserDataRequest.SerializeToString,
+            user__pb2.ExportUserDataResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def RevertUser(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.UserService/RevertUser',
+            user__pb2.RevertUserRequest.SerializeToString,
+            user__pb2.RevertUserResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def MergeUsers(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.UserService/MergeUsers',
+            user__pb2.MergeUsersRequest.SerializeToString,
+            user__pb2.MergeUsersResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
     @staticmethod
     def TestError(request,
             target,
@@ -313,3 +692,319 @@ class UserService(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def TestLatency(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.UserService/TestLatency',
+            user__pb2.TestLatencyRequest.SerializeToString,
+            user__pb2.TestLatencyResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def TestLatencyStream(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_stream(
+            request,
+            target,
+            '/user.UserService/TestLatencyStream',
+            user__pb2.TestLatencyStreamRequest.SerializeToString,
+            user__pb2.TestLatencyResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def TestStream(request_iterator,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.stream_stream(
+            request_iterator,
+            target,
+            '/user.UserService/TestStream',
+            user__pb2.TestStreamRequest.SerializeToString,
+            user__pb2.TestStreamResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def TestEcho(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.UserService/TestEcho',
+            user__pb2.TestEchoRequest.SerializeToString,
+            user__pb2.TestEchoResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+
+class AdminServiceStub(object):
+    """Operational endpoints for operators; not for end-user clients.
+    """
+
+    def __init__(self, channel):
+        """Constructor.
+
+        Args:
+            channel: A grpc.Channel.
+        """
+        self.GetCacheStats = channel.unary_unary(
+                '/user.AdminService/GetCacheStats',
+                request_serializer=user__pb2.GetCacheStatsRequest.SerializeToString,
+                response_deserializer=user__pb2.GetCacheStatsResponse.FromString,
+                _registered_method=True)
+        self.GetRuntimeConfig = channel.unary_unary(
+                '/user.AdminService/GetRuntimeConfig',
+                request_serializer=user__pb2.GetRuntimeConfigRequest.SerializeToString,
+                response_deserializer=user__pb2.GetRuntimeConfigResponse.FromString,
+                _registered_method=True)
+        self.SetRuntimeConfig = channel.unary_unary(
+                '/user.AdminService/SetRuntimeConfig',
+                request_serializer=user__pb2.SetRuntimeConfigRequest.SerializeToString,
+                response_deserializer=user__pb2.SetRuntimeConfigResponse.FromString,
+                _registered_method=True)
+        self.GetVersion = channel.unary_unary(
+                '/user.AdminService/GetVersion',
+                request_serializer=user__pb2.GetVersionRequest.SerializeToString,
+                response_deserializer=user__pb2.GetVersionResponse.FromString,
+                _registered_method=True)
+
+
+class AdminServiceServicer(object):
+    """Operational endpoints for operators; not for end-user clients.
+    """
+
+    def GetCacheStats(self, request, context):
+        """Reports cache hit ratios, sampled key counts and Valkey memory usage.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetRuntimeConfig(self, request, context):
+        """Reads and changes dynamic settings of the answering instance only; other
+        replicas keep their own settings until they restart.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def SetRuntimeConfig(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetVersion(self, request, context):
+        """Identifies the build the answering instance runs.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+
+def add_AdminServiceServicer_to_server(servicer, server):
+    rpc_method_handlers = {
+            'GetCacheStats': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetCacheStats,
+                    request_deserializer=user__pb2.GetCacheStatsRequest.FromString,
+                    response_serializer=user__pb2.GetCacheStatsResponse.SerializeToString,
+            ),
+            'GetRuntimeConfig': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetRuntimeConfig,
+                    request_deserializer=user__pb2.GetRuntimeConfigRequest.FromString,
+                    response_serializer=user__pb2.GetRuntimeConfigResponse.SerializeToString,
+            ),
+            'SetRuntimeConfig': grpc.unary_unary_rpc_method_handler(
+                    servicer.SetRuntimeConfig,
+                    request_deserializer=user__pb2.SetRuntimeConfigRequest.FromString,
+                    response_serializer=user__pb2.SetRuntimeConfigResponse.SerializeToString,
+            ),
+            'GetVersion': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetVersion,
+                    request_deserializer=user__pb2.GetVersionRequest.FromString,
+                    response_serializer=user__pb2.GetVersionResponse.SerializeToString,
+            ),
+    }
+    generic_handler = grpc.method_handlers_generic_handler(
+            'user.AdminService', rpc_method_handlers)
+    server.add_generic_rpc_handlers((generic_handler,))
+    server.add_registered_method_handlers('user.AdminService', rpc_method_handlers)
+
+
+ # This class is part of an EXPERIMENTAL API.
+class AdminService(object):
+    """Operational endpoints for operators; not for end-user clients.
+    """
+
+    @staticmethod
+    def GetCacheStats(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.AdminService/GetCacheStats',
+            user__pb2.GetCacheStatsRequest.SerializeToString,
+            user__pb2.GetCacheStatsResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def GetRuntimeConfig(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.AdminService/GetRuntimeConfig',
+            user__pb2.GetRuntimeConfigRequest.SerializeToString,
+            user__pb2.GetRuntimeConfigResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def SetRuntimeConfig(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.AdminService/SetRuntimeConfig',
+            user__pb2.SetRuntimeConfigRequest.SerializeToString,
+            user__pb2.SetRuntimeConfigResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def GetVersion(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.AdminService/GetVersion',
+            user__pb2.GetVersionRequest.SerializeToString,
+            user__pb2.GetVersionResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
@@ -0,0 +1,48 @@
+// Command protocompat guards the UserService wire contract. It compares the
+// generated descriptors against a recorded schema snapshot and decodes golden
+// payloads recorded by earlier versions, exiting non-zero on breaking changes.
+//
+//	go run ./cmd/protocompat            # check
+//	go run ./cmd/protocompat -update    # accept the current schema
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"grpc-server/internal/protocompat"
+	pb "grpc-server/pkg/pb"
+)
+
+func main() {
+	dir := flag.String("dir", "internal/protocompat/golden", "directory holding schema.json and golden messages")
+	update := flag.Bool("update", false, "record the current schema and add golden messages for new types")
+	flag.Parse()
+
+	if *update {
+		if err := protocompat.Update(*dir, pb.File_user_proto); err != nil {
+			fmt.Fprintf(os.Stderr, "protocompat: update failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("protocompat: golden files updated")
+		return
+	}
+
+	breaking, warnings, err := protocompat.Check(os.DirFS(*dir), pb.File_user_proto)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "protocompat: %v\n", err)
+		os.Exit(1)
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	if len(breaking) > 0 {
+		for _, b := range breaking {
+			fmt.Fprintf(os.Stderr, "breaking: %s\n", b)
+		}
+		fmt.Fprintf(os.Stderr, "protocompat: %d breaking change(s); reserve removed fields or run with -update if intended\n", len(breaking))
+		os.Exit(1)
+	}
+	fmt.Println("protocompat: no breaking changes")
+}
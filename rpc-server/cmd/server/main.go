@@ -3,27 +3,81 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpc_health "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	"grpc-server/internal/audit"
+	"grpc-server/internal/auth"
+	"grpc-server/internal/buildinfo"
 	"grpc-server/internal/cache"
+	"grpc-server/internal/capture"
+	"grpc-server/internal/chaos"
 	"grpc-server/internal/config"
+	"grpc-server/internal/cost"
 	"grpc-server/internal/database"
+	"grpc-server/internal/deadline"
+	"grpc-server/internal/dedup"
+	"grpc-server/internal/events"
+	"grpc-server/internal/export"
+	"grpc-server/internal/gateway"
+	"grpc-server/internal/health"
+	"grpc-server/internal/i18n"
+	"grpc-server/internal/jobs"
+	"grpc-server/internal/lock"
 	"grpc-server/internal/logging"
+	"grpc-server/internal/metrics"
+	"grpc-server/internal/migrations"
+	"grpc-server/internal/msgsize"
+	"grpc-server/internal/openapi"
+	"grpc-server/internal/ratelimit"
+	"grpc-server/internal/repository"
+	"grpc-server/internal/repository/instrumented"
 	"grpc-server/internal/repository/postgres"
+	"grpc-server/internal/repository/sharded"
+	"grpc-server/internal/runtimeconfig"
 	"grpc-server/internal/server"
+	"grpc-server/internal/shadow"
+	"grpc-server/internal/slo"
+	"grpc-server/internal/startup"
+	"grpc-server/internal/timing"
 	"grpc-server/internal/tracing"
+	"grpc-server/internal/transport"
 	pb "grpc-server/pkg/pb"
+	"grpc-server/pkg/serviceconfig"
 )
 
 func main() {
+	// Maintenance subcommands; anything else starts the server
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "backup":
+			os.Exit(runBackup(os.Args[2:]))
+		case "restore":
+			os.Exit(runRestore(os.Args[2:]))
+		case "anonymize":
+			os.Exit(runAnonymize(os.Args[2:]))
+		case "replay":
+			os.Exit(runReplay(os.Args[2:]))
+		case "migrate", "-migrate":
+			os.Exit(runMigrate(os.Args[2:]))
+		}
+	}
+
 	// Create context for the entire application
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -31,41 +85,301 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
-	// Setup structured logging
+	// Settings that SetRuntimeConfig can change while the server runs
+	callerClasses, err := runtimeconfig.ParseCallerClasses(cfg.Server.PriorityClasses)
+	if err != nil {
+		slog.Error("Invalid PRIORITY_CLASSES", "error", err)
+		os.Exit(1)
+	}
+	moduleLogLevels, err := runtimeconfig.ParseModuleLogLevels(cfg.Logger.ModuleLevels)
+	if err != nil {
+		slog.Error("Invalid LOG_MODULE_LEVELS", "error", err)
+		os.Exit(1)
+	}
+	runtimeConfig, err := runtimeconfig.New(runtimeconfig.Settings{
+		LogLevel:           cfg.Logger.Level,
+		ModuleLogLevels:    moduleLogLevels,
+		UserCacheTTL:       time.Duration(cfg.Cache.UserTTLSeconds) * time.Second,
+		ListCacheTTL:       time.Duration(cfg.Cache.ListTTLSeconds) * time.Second,
+		SampleRatio:        min(cfg.Tracing.SampleRatio, 1),
+		RateLimitQPS:       cfg.Server.RateLimitQPS,
+		RateLimitBurst:     cfg.Server.RateLimitBurst,
+		LowPriorityReserve: cfg.Server.LowPriorityReserve,
+		CallerClasses:      callerClasses,
+		ReadOnly:           cfg.Server.ReadOnly,
+		RaceUserReads:      cfg.Cache.RaceUserReads,
+	})
+	if err != nil {
+		slog.Error("Invalid initial runtime config", "error", err)
+		os.Exit(1)
+	}
+
+	// Setup structured logging; the module handler applies the global and
+	// per-subsystem levels from the runtime config
+	var logOutput io.Writer = os.Stdout
+	if cfg.Logger.File != "" {
+		logFile, err := logging.OpenRotatingFile(logging.FileConfig{
+			Path:         cfg.Logger.File,
+			MaxSizeBytes: int64(cfg.Logger.FileMaxSizeMB) << 20,
+			RotateEvery:  time.Duration(cfg.Logger.FileRotateHours) * time.Hour,
+			MaxBackups:   cfg.Logger.FileMaxBackups,
+			Compress:     cfg.Logger.FileCompress,
+		})
+		if err != nil {
+			slog.Error("Failed to open log file", "path", cfg.Logger.File, "error", err)
+			os.Exit(1)
+		}
+		defer logFile.Close()
+		logOutput = io.MultiWriter(os.Stdout, logFile)
+	}
+	redactor, err := logging.NewRedactor(logging.RedactConfig{
+		Keys:     cfg.Logger.RedactKeys,
+		Patterns: cfg.Logger.RedactPatterns,
+		Mode:     cfg.Logger.RedactMode,
+		Salt:     cfg.Logger.RedactSalt,
+	})
+	if err != nil {
+		slog.Error("Invalid log redaction config", "error", err)
+		os.Exit(1)
+	}
+	handlerOpts := &slog.HandlerOptions{ReplaceAttr: redactor.ReplaceAttr}
 	var handler slog.Handler
 	// Note: ensure import "grpc-server/internal/logging" is present for the TraceContextHandler
 	if cfg.Logger.Format == "text" {
-		handler = logging.NewTraceContextHandler(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-			Level: cfg.Logger.Level,
-		}))
+		handler = logging.NewTraceContextHandler(slog.NewTextHandler(logOutput, handlerOpts))
 	} else {
-		handler = logging.NewTraceContextHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level: cfg.Logger.Level,
-		}))
+		handler = logging.NewTraceContextHandler(slog.NewJSONHandler(logOutput, handlerOpts))
 	}
-	logger := slog.New(handler)
+	if cfg.Logger.SampleFirst > 0 {
+		handler = logging.NewSamplingHandler(handler, cfg.Logger.SampleFirst, cfg.Logger.SampleThereafter)
+	}
+	logger := slog.New(logging.NewModuleHandler(handler, runtimeConfig))
 	slog.SetDefault(logger)
 
-	// Initialize OpenTelemetry tracing
-	var tracingShutdown func(context.Context) error
+	build := buildinfo.Get()
+	slog.Info("Starting rpc-server",
+		"version", build.Version,
+		"commit", build.Commit,
+		"build_date", build.Date,
+		"go_version", build.GoVersion,
+		"modified", build.Modified,
+	)
+
+	// Serve metrics for scraping; with tracing on, the same instruments are
+	// also sent to the collector
+	var metricsExporter *metrics.Exporter
+	if cfg.Server.MetricsEnabled {
+		metricsExporter = metrics.NewExporter()
+	}
+
+	// Connect to dependencies concurrently; each has its own timeout, so a
+	// slow or broken one is named in the error instead of stalling the rest
+	var (
+		tracingShutdown func(context.Context) error
+		dbPool          *pgxpool.Pool
+		shardPools      = make([]*pgxpool.Pool, len(cfg.Database.ShardURLs))
+		shadowPool      *pgxpool.Pool
+		valkeyCache     *cache.ValkeyCache
+	)
+	databaseTimeout := time.Duration(cfg.Startup.DatabaseTimeoutMs) * time.Millisecond
+	steps := []startup.Step{
+		{Name: "database", Timeout: databaseTimeout, Init: func(ctx context.Context) (err error) {
+			dbPool, err = database.Connect(ctx, &cfg.Database)
+			return err
+		}},
+		{Name: "cache", Timeout: time.Duration(cfg.Startup.CacheTimeoutMs) * time.Millisecond, Init: func(ctx context.Context) (err error) {
+			valkeyCache, err = cache.NewValkeyCache(&cfg.Cache, logger)
+			return err
+		}},
+	}
 	if cfg.Tracing.Enabled {
-		var err error
-		tracingShutdown, err = tracing.InitTracing(ctx, tracing.TracingConfig{
-			ServiceName:    cfg.Tracing.ServiceName,
-			ServiceVersion: cfg.Tracing.ServiceVersion,
-			CollectorURL:   cfg.Tracing.CollectorURL,
-			Enabled:        cfg.Tracing.Enabled,
-		})
-		if err != nil {
-			slog.Error("Failed to initialize tracing", "error", err)
-			os.Exit(1)
-		}
+		steps = append(steps, startup.Step{Name: "tracing", Timeout: time.Duration(cfg.Startup.TracingTimeoutMs) * time.Millisecond, Init: func(ctx context.Context) (err error) {
+			tracingShutdown, err = tracing.InitTracing(ctx, tracing.TracingConfig{
+				ServiceName:    cfg.Tracing.ServiceName,
+				ServiceVersion: cfg.Tracing.ServiceVersion,
+				CollectorURL:   cfg.Tracing.CollectorURL,
+				Enabled:        cfg.Tracing.Enabled,
+				SampleRatio:    cfg.Tracing.SampleRatio,
+				// Follow trace_sample_ratio changes from SetRuntimeConfig
+				SampleRatioFunc: runtimeConfig.SampleRatio,
+				MetricReaders:   metricReaders(metricsExporter),
+			})
+			return err
+		}})
+	}
+	for i, url := range cfg.Database.ShardURLs {
+		shardCfg := cfg.Database
+		shardCfg.URL = url
+		steps = append(steps, startup.Step{Name: fmt.Sprintf("database shard-%d", i+1), Timeout: databaseTimeout, Init: func(ctx context.Context) (err error) {
+			shardPools[i], err = database.Connect(ctx, &shardCfg)
+			return err
+		}})
+	}
+	if cfg.Shadow.DatabaseURL != "" {
+		shadowCfg := cfg.Database
+		shadowCfg.URL = cfg.Shadow.DatabaseURL
+		steps = append(steps, startup.Step{Name: "shadow database", Timeout: databaseTimeout, Init: func(ctx context.Context) (err error) {
+			shadowPool, err = database.Connect(ctx, &shadowCfg)
+			return err
+		}})
+	}
+	if err := startup.Run(ctx, steps...); err != nil {
+		slog.Error("Failed to initialize dependencies", "error", err)
+		os.Exit(1)
+	}
+	if tracingShutdown != nil {
 		defer func() {
 			if err := tracingShutdown(ctx); err != nil {
 				slog.Error("Failed to shutdown tracing", "error", err)
 			}
 		}()
 	}
+	defer dbPool.Close()
+	for _, shardPool := range shardPools {
+		defer shardPool.Close()
+	}
+	if shadowPool != nil {
+		defer shadowPool.Close()
+	}
+	defer valkeyCache.Close()
+	if metricsExporter != nil {
+		if !cfg.Tracing.Enabled {
+			metricsExporter.InstallProvider()
+		}
+		pools := map[string]*pgxpool.Pool{"shard-0": dbPool}
+		for i, shardPool := range shardPools {
+			pools[fmt.Sprintf("shard-%d", i+1)] = shardPool
+		}
+		if err := metrics.ObservePools(pools); err != nil {
+			slog.Warn("Failed to observe database pools", "error", err)
+		}
+	}
+
+	// Bring every shard's schema up to date before checking it
+	if cfg.Database.MigrateOnStartup {
+		for i, pool := range append([]*pgxpool.Pool{dbPool}, shardPools...) {
+			applied, err := migrations.Apply(ctx, pool, logger)
+			if err != nil {
+				slog.Error("Database migration failed", "shard", i, "error", err)
+				os.Exit(1)
+			}
+			slog.Info("Database migrations applied", "shard", i, "applied", applied)
+		}
+	}
+
+	// Catch missed migrations before they surface as query errors
+	switch mode := cfg.Startup.SchemaDriftMode; mode {
+	case database.DriftModeOff, database.DriftModeWarn, database.DriftModeFail:
+		for _, pool := range append([]*pgxpool.Pool{dbPool}, shardPools...) {
+			if err := database.CheckUsersSchema(ctx, pool, mode); err != nil {
+				if mode == database.DriftModeFail {
+					slog.Error("Database schema check failed", "error", err)
+					os.Exit(1)
+				}
+				slog.Warn("Database schema check failed", "error", err)
+			}
+		}
+	default:
+		slog.Error("Invalid SCHEMA_DRIFT_MODE", "mode", mode)
+		os.Exit(1)
+	}
+
+	// Tenants are kept apart by row-level security, which some roles skip
+	for i, pool := range append([]*pgxpool.Pool{dbPool}, shardPools...) {
+		if bypasses, err := database.BypassesRowSecurity(ctx, pool); err != nil {
+			slog.Warn("Failed to check the database role", "shard", i, "error", err)
+		} else if bypasses {
+			slog.Warn("Database role bypasses row-level security, so tenants are not isolated; connect as an ordinary role", "shard", i)
+		}
+	}
+
+	// Record the environment in one line for support, next to the build
+	// details logged at start
+	dbVersion, err := database.ServerVersion(ctx, dbPool)
+	if err != nil {
+		slog.Warn("Failed to read database version", "error", err)
+		dbVersion = "unknown"
+	}
+	cacheVersion, err := valkeyCache.ServerVersion(ctx)
+	if err != nil {
+		slog.Warn("Failed to read cache version", "error", err)
+		cacheVersion = "unknown"
+	}
+	dbPoolConfig := dbPool.Config()
+	slog.Info("Startup environment",
+		"version", build.Version,
+		"commit", build.Commit,
+		"go_version", build.GoVersion,
+		"postgres_version", dbVersion,
+		"valkey_version", cacheVersion,
+		"db_max_conns", dbPoolConfig.MaxConns,
+		"db_min_conns", dbPoolConfig.MinConns,
+		"db_shards", len(shardPools)+1,
+		"cache_blocking_pool_size", valkeyCache.BlockingPoolSize(),
+	)
+
+	// Split each request's deadline between cache and database calls
+	budget := deadline.Budget{
+		Reserve:      float64(cfg.Server.DeadlineReservePercent) / 100,
+		CacheTimeout: time.Duration(cfg.Cache.OpTimeoutMs) * time.Millisecond,
+		MinBudget:    5 * time.Millisecond,
+	}
+
+	// Create PostgreSQL repository, spread across shards if configured. Each
+	// backend is instrumented outside its deadline budget, so calls the
+	// budget cuts short are classified as timeouts
+	backend := func(pool *pgxpool.Pool, name string) repository.UserRepository {
+		return instrumented.NewUserRepository(deadline.NewUserRepository(postgres.NewUserRepository(pool, logger), budget), name, logger)
+	}
+	var userRepo repository.UserRepository = backend(dbPool, "postgres")
+	var shardedRepo *sharded.UserRepository
+	if len(cfg.Database.ShardURLs) > 0 {
+		shards := []sharded.Shard{{Name: "shard-0", Repo: backend(dbPool, "shard-0"), Ping: dbPool.Ping}}
+		for i, shardPool := range shardPools {
+			name := fmt.Sprintf("shard-%d", i+1)
+			shards = append(shards, sharded.Shard{
+				Name: name,
+				Repo: backend(shardPool, name),
+				Ping: shardPool.Ping,
+			})
+		}
+		shardedRepo = sharded.NewUserRepository(shards...)
+		userRepo = shardedRepo
+		slog.Info("Database sharding enabled", "shards", len(shards))
+	}
+
+	// Mirror a sample of reads to the candidate datastore if configured
+	if shadowPool != nil {
+		userRepo = shadow.NewUserRepository(userRepo, instrumented.NewUserRepository(postgres.NewUserRepository(shadowPool, logger), "shadow", logger), shadow.Config{
+			SamplePercent: cfg.Shadow.SamplePercent,
+			Timeout:       time.Duration(cfg.Shadow.TimeoutMs) * time.Millisecond,
+		}, logger)
+		slog.Info("Shadow reads enabled", "sample_percent", cfg.Shadow.SamplePercent)
+	}
+
+	// Ping every shard when checking on the database
+	dbPing := dbPool.Ping
+	if shardedRepo != nil {
+		dbPing = func(ctx context.Context) error {
+			for _, shard := range shardedRepo.Health(ctx) {
+				if !shard.Healthy {
+					return fmt.Errorf("shard %s: %s", shard.Name, shard.Error)
+				}
+			}
+			return nil
+		}
+	}
+
+	// Watch the database so reads can fall back to the cache during outages
+	var dbMonitor *database.Monitor
+	if cfg.Server.DegradedReads {
+		dbMonitor = database.NewMonitor(dbPing,
+			time.Duration(cfg.Database.OutageCheckIntervalMs)*time.Millisecond,
+			cfg.Database.OutageThreshold,
+		)
+		go dbMonitor.Run(ctx)
+		slog.Info("Degraded cache-only reads enabled", "check_interval_ms", cfg.Database.OutageCheckIntervalMs, "threshold", cfg.Database.OutageThreshold)
+	}
 
 	// Create listener
 	address := fmt.Sprintf(":%s", cfg.Server.Port)
@@ -75,10 +389,199 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Return the trace context first, so calls rejected by any later
+	// interceptor still carry it
+	var interceptors []grpc.UnaryServerInterceptor
+	// Streaming calls go through the same checks, in the same order; only
+	// those that apply to streams have a stream version
+	var streamInterceptors []grpc.StreamServerInterceptor
+	if metricsExporter != nil {
+		interceptors = append(interceptors, metrics.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, metrics.StreamServerInterceptor())
+	}
+	if cfg.Tracing.Enabled {
+		interceptors = append(interceptors, tracing.ResponseTraceInterceptor(cfg.Tracing.URLTemplate))
+	}
+
+	// Track SLO burn rates; the interceptor goes early so it sees the latency
+	// and status code the caller sees
+	if cfg.SLO.Enabled {
+		defaultObjective := slo.Objective{
+			Availability:     cfg.SLO.AvailabilityTarget,
+			Latency:          cfg.SLO.LatencyTarget,
+			LatencyThreshold: time.Duration(cfg.SLO.LatencyThresholdMs) * time.Millisecond,
+		}
+		methodObjectives, err := slo.ParseLatencyThresholds(cfg.SLO.MethodLatencyThresholds, defaultObjective)
+		if err != nil {
+			slog.Error("Invalid SLO_METHOD_LATENCY_THRESHOLDS", "error", err)
+			os.Exit(1)
+		}
+		sloTracker := slo.NewTracker(slo.Config{
+			Objectives: slo.Objectives{Default: defaultObjective, Methods: methodObjectives},
+			Interval:   time.Duration(cfg.SLO.EvalIntervalSeconds) * time.Second,
+		}, logger)
+		go sloTracker.Run(ctx)
+		interceptors = append(interceptors, sloTracker.UnaryServerInterceptor())
+		slog.Info("SLO tracking enabled",
+			"availability_target", cfg.SLO.AvailabilityTarget,
+			"latency_target", cfg.SLO.LatencyTarget,
+			"latency_threshold_ms", cfg.SLO.LatencyThresholdMs,
+		)
+	}
+	// Give every handler a logger carrying the method, request ID and caller
+	interceptors = append(interceptors, logging.UnaryServerInterceptor(logging.ForModule(logger, logging.ModuleServer)))
+	streamInterceptors = append(streamInterceptors, logging.StreamServerInterceptor(logging.ForModule(logger, logging.ModuleServer)))
+	interceptors = append(interceptors, timing.UnaryServerInterceptor())
+	interceptors = append(interceptors, cost.UnaryServerInterceptor())
+
+	// Record message sizes and enforce per-method caps
+	requestLimits, err := msgsize.ParseLimits(cfg.Server.MethodRequestSizeLimits)
+	if err != nil {
+		slog.Error("Invalid METHOD_REQUEST_SIZE_LIMITS", "error", err)
+		os.Exit(1)
+	}
+	responseLimits, err := msgsize.ParseLimits(cfg.Server.MethodResponseSizeLimits)
+	if err != nil {
+		slog.Error("Invalid METHOD_RESPONSE_SIZE_LIMITS", "error", err)
+		os.Exit(1)
+	}
+	interceptors = append(interceptors, msgsize.UnaryServerInterceptor(msgsize.Limits{
+		Request:  requestLimits,
+		Response: responseLimits,
+	}))
+
+	interceptors = append(interceptors, i18n.UnaryServerInterceptor())
+
+	// Authenticate callers if an issuer is configured, before anything keyed
+	// on who the caller is; inside i18n so rejections are localized too
+	if cfg.Auth.JWKSURL != "" {
+		permissions, err := auth.ParsePermissions(cfg.Auth.MethodPermissions)
+		if err != nil {
+			slog.Error("Invalid AUTH_METHOD_PERMISSIONS", "error", err)
+			os.Exit(1)
+		}
+		verifier := auth.NewVerifier(auth.Config{
+			JWKSURL:         cfg.Auth.JWKSURL,
+			RefreshInterval: time.Duration(cfg.Auth.JWKSRefreshMinutes) * time.Minute,
+			Issuer:          cfg.Auth.Issuer,
+			Audience:        cfg.Auth.Audience,
+			RolesClaim:      cfg.Auth.RolesClaim,
+			TenantClaim:     cfg.Auth.TenantClaim,
+			Permissions:     permissions,
+		}, logger)
+		// A missing JWKS only fails calls until a refresh succeeds
+		if err := verifier.Keys().Refresh(ctx); err != nil {
+			slog.Warn("Failed to fetch JWKS, retrying on first use", "error", err)
+		}
+		go verifier.Keys().Run(ctx)
+		interceptors = append(interceptors, verifier.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, verifier.StreamServerInterceptor())
+		slog.Info("Authentication enabled", "jwks_url", cfg.Auth.JWKSURL, "restricted_methods", len(permissions))
+	} else {
+		slog.Warn("Authentication disabled: AUTH_JWKS_URL is not set")
+	}
+
+	// Limit each client before the server-wide limit, so a bursty client is
+	// turned away without spending the tokens everyone shares
+	if cfg.Server.ClientRateLimitQPS > 0 {
+		keyBy, err := ratelimit.ParseKeyBy(cfg.Server.ClientRateLimitKey)
+		if err != nil {
+			slog.Error("Invalid CLIENT_RATE_LIMIT_KEY", "error", err)
+			os.Exit(1)
+		}
+		if cfg.Server.ClientRateLimitBurst < 1 || cfg.Server.ClientRateLimitMaxClients < 1 {
+			slog.Error("CLIENT_RATE_LIMIT_BURST and CLIENT_RATE_LIMIT_MAX_CLIENTS must be at least 1",
+				"burst", cfg.Server.ClientRateLimitBurst, "max_clients", cfg.Server.ClientRateLimitMaxClients)
+			os.Exit(1)
+		}
+		clientLimiter := ratelimit.NewLimiter(ratelimit.Config{
+			QPS:        cfg.Server.ClientRateLimitQPS,
+			Burst:      cfg.Server.ClientRateLimitBurst,
+			KeyBy:      keyBy,
+			MaxClients: cfg.Server.ClientRateLimitMaxClients,
+		})
+		interceptors = append(interceptors, clientLimiter.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, clientLimiter.StreamServerInterceptor())
+		slog.Info("Client rate limit enabled", "qps", cfg.Server.ClientRateLimitQPS, "burst", cfg.Server.ClientRateLimitBurst, "key", keyBy)
+	}
+	interceptors = append(interceptors, server.RuntimeConfigInterceptor(runtimeConfig))
+	streamInterceptors = append(streamInterceptors, server.RuntimeConfigStreamInterceptor(runtimeConfig))
+	// Degraded mode only turns away writes, and no streaming call writes
+	if dbMonitor != nil {
+		interceptors = append(interceptors, server.DegradedModeInterceptor(dbMonitor))
+	}
+	interceptors = append(interceptors, server.MethodConfigInterceptor(serviceconfig.Default()))
+
+	// Inject faults for chaos experiments; runs after the method timeout is
+	// applied so injected delays hit the same deadline real work would
+	var faultInjector *chaos.Injector
+	if cfg.Server.FaultInjectionEnabled {
+		rules, err := chaos.ParseRules(cfg.Server.FaultInjectionRules)
+		if err == nil {
+			faultInjector = chaos.NewInjector(logger)
+			err = faultInjector.SetRules(rules)
+		}
+		if err != nil {
+			slog.Error("Invalid FAULT_INJECTION_RULES", "error", err)
+			os.Exit(1)
+		}
+		interceptors = append(interceptors, faultInjector.UnaryServerInterceptor())
+		slog.Warn("Fault injection enabled", "rules", len(rules), "admin_addr", cfg.Server.AdminHTTPAddr)
+	}
+
+	// Capture sampled, redacted calls for replay against staging
+	if cfg.Capture.File != "" {
+		if cfg.Capture.Salt == "" {
+			slog.Error("CAPTURE_SALT is required when CAPTURE_FILE is set")
+			os.Exit(1)
+		}
+		sink, err := capture.NewFileSink(cfg.Capture.File)
+		if err != nil {
+			slog.Error("Failed to open traffic capture", "error", err)
+			os.Exit(1)
+		}
+		recorder := capture.NewRecorder(sink, capture.NewRedactor(cfg.Capture.Salt), capture.Config{
+			SampleRatio: cfg.Capture.SampleRatio,
+		}, logger)
+		defer recorder.Close()
+		interceptors = append(interceptors, recorder.UnaryServerInterceptor())
+		slog.Info("Traffic capture enabled", "file", cfg.Capture.File, "sample_ratio", cfg.Capture.SampleRatio)
+	}
+	interceptors = append(interceptors, tracing.BaggageInterceptor())
+
+	interceptors = append(interceptors,
+		// Validate inside i18n so rejections are localized too
+		server.ValidationInterceptor(),
+	)
+
 	// Create gRPC server with configuration and tracing interceptors
 	grpcOpts := []grpc.ServerOption{
 		grpc.MaxRecvMsgSize(cfg.Server.MaxRecvMsgSize),
 		grpc.MaxSendMsgSize(cfg.Server.MaxSendMsgSize),
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+
+	// Secure the listener with TLS if configured, reloading the certificate
+	// on SIGHUP; in-process connections from the REST gateway stay plaintext
+	if cfg.Server.TLSCertFile != "" || cfg.Server.TLSKeyFile != "" || cfg.Server.TLSClientCAFile != "" {
+		if cfg.Server.TLSCertFile == "" || cfg.Server.TLSKeyFile == "" {
+			slog.Error("GRPC_TLS_CERT_FILE and GRPC_TLS_KEY_FILE must be set together, and GRPC_TLS_CLIENT_CA_FILE requires both")
+			os.Exit(1)
+		}
+		tlsReloader, err := transport.NewReloader(transport.TLSConfig{
+			CertFile:     cfg.Server.TLSCertFile,
+			KeyFile:      cfg.Server.TLSKeyFile,
+			ClientCAFile: cfg.Server.TLSClientCAFile,
+		}, logger)
+		if err != nil {
+			slog.Error("Failed to load TLS configuration", "error", err)
+			os.Exit(1)
+		}
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		go tlsReloader.ReloadOn(ctx, hup)
+		grpcOpts = append(grpcOpts, grpc.Creds(transport.ServerCredentials(credentials.NewTLS(tlsReloader.ServerConfig()))))
 	}
 
 	// Add tracing interceptors if enabled
@@ -88,40 +591,239 @@ func main() {
 
 	grpcServer := grpc.NewServer(grpcOpts...)
 
-	// Connect to PostgreSQL database (with tracing)
-	slog.Info("Connecting to PostgreSQL database")
-	dbPool, err := database.Connect(ctx, &cfg.Database)
-	if err != nil {
-		slog.Error("Failed to connect to database", "error", err)
-		os.Exit(1)
+	// Fall back to an in-memory cache while Valkey is down
+	cacheInterface := cache.Cache(valkeyCache)
+	var fallbackCache *cache.FallbackCache
+	if cfg.Cache.FallbackMaxEntries > 0 {
+		fallbackCache = cache.NewFallbackCache(valkeyCache, cache.NewMemoryCache(cfg.Cache.FallbackMaxEntries), cache.FallbackConfig{
+			FailureThreshold: cfg.Cache.BreakerFailures,
+			Cooldown:         time.Duration(cfg.Cache.BreakerCooldownMs) * time.Millisecond,
+			MaxDirtyKeys:     cfg.Cache.FallbackMaxDirtyKeys,
+			KeyPatterns:      server.CacheKeyPatterns(),
+			ResyncTimeout:    5 * time.Second,
+		}, logger)
+		cacheInterface = fallbackCache
 	}
-	defer func() {
-		if dbPool != nil {
-			dbPool.Close()
-		}
-	}()
 
-	// Create PostgreSQL repository
-	userRepo := postgres.NewUserRepository(dbPool, logger)
-
-	// Connect to Valkey cache
-	slog.Info("Connecting to Valkey cache")
-	valkeyCache, err := cache.NewValkeyCache(&cfg.Cache, logger)
-	if err != nil {
-		slog.Error("Failed to connect to cache", "error", err)
+	// Notice Valkey coming back as soon as it does, so the fallback resumes
+	// without waiting for a request after its cooldown
+	if cfg.Cache.ConnCheckIntervalMs <= 0 || cfg.Cache.ReconnectMinBackoffMs <= 0 || cfg.Cache.ReconnectMaxBackoffMs < cfg.Cache.ReconnectMinBackoffMs {
+		slog.Error("CACHE_CONN_CHECK_INTERVAL_MS and CACHE_RECONNECT_MIN_BACKOFF_MS must be positive and at most CACHE_RECONNECT_MAX_BACKOFF_MS",
+			"interval_ms", cfg.Cache.ConnCheckIntervalMs, "min_backoff_ms", cfg.Cache.ReconnectMinBackoffMs, "max_backoff_ms", cfg.Cache.ReconnectMaxBackoffMs)
 		os.Exit(1)
 	}
-	defer valkeyCache.Close()
+	reconnectMinBackoff := time.Duration(cfg.Cache.ReconnectMinBackoffMs) * time.Millisecond
+	reconnectMaxBackoff := time.Duration(cfg.Cache.ReconnectMaxBackoffMs) * time.Millisecond
+	cacheMonitor := cache.NewConnectionMonitor(valkeyCache.Ping, cache.ConnectionConfig{
+		Interval:   time.Duration(cfg.Cache.ConnCheckIntervalMs) * time.Millisecond,
+		MinBackoff: reconnectMinBackoff,
+		MaxBackoff: reconnectMaxBackoff,
+		Timeout:    time.Second,
+	}, logger)
+	if fallbackCache != nil {
+		cacheMonitor.OnStateChange(func(_, to cache.ConnState) {
+			if to == cache.ConnConnected {
+				fallbackCache.Reconnected()
+			}
+		})
+	}
+	go cacheMonitor.Run(ctx)
+	cacheInterface = deadline.NewCache(cacheInterface, budget)
+	cacheInterface = timing.NewCache(cacheInterface)
+	cacheInterface = cost.NewCache(cacheInterface)
 
-	// Wrap cache with tracing if enabled
-	cacheInterface := cache.Cache(valkeyCache)
+	// Wrap cache with tracing if enabled, and count lookups for GetCacheStats
 	if cfg.Tracing.Enabled {
-		cacheInterface = cache.NewTracedCache(valkeyCache, cfg.Tracing.ServiceName)
+		cacheInterface = cache.NewTracedCache(cacheInterface, cfg.Tracing.ServiceName)
+	}
+
+	// Serve the most read keys from memory, so a celebrity user's entry
+	// neither pins one Valkey node nor stampedes the database on expiry
+	var hotKeyCache *cache.HotKeyCache
+	if cfg.Cache.HotKeyThreshold > 0 {
+		if cfg.Cache.HotKeySampleRatio <= 0 || cfg.Cache.HotKeySampleRatio > 1 || cfg.Cache.HotKeyWindowSeconds <= 0 ||
+			cfg.Cache.HotKeyTopN < 1 || cfg.Cache.HotKeyLocalTTLMs <= 0 {
+			slog.Error("CACHE_HOT_KEY_SAMPLE_RATIO must be in (0, 1], and CACHE_HOT_KEY_WINDOW_SECONDS, CACHE_HOT_KEY_TOP_N and CACHE_HOT_KEY_LOCAL_TTL_MS positive",
+				"sample_ratio", cfg.Cache.HotKeySampleRatio, "window_seconds", cfg.Cache.HotKeyWindowSeconds,
+				"top_n", cfg.Cache.HotKeyTopN, "local_ttl_ms", cfg.Cache.HotKeyLocalTTLMs)
+			os.Exit(1)
+		}
+		hotKeyCache = cache.NewHotKeyCache(cacheInterface, cache.HotKeyConfig{
+			SampleRatio:     cfg.Cache.HotKeySampleRatio,
+			Window:          time.Duration(cfg.Cache.HotKeyWindowSeconds) * time.Second,
+			Threshold:       cfg.Cache.HotKeyThreshold,
+			TopN:            cfg.Cache.HotKeyTopN,
+			MaxTracked:      cfg.Cache.HotKeyMaxTracked,
+			LocalTTL:        time.Duration(cfg.Cache.HotKeyLocalTTLMs) * time.Millisecond,
+			LocalMaxEntries: cfg.Cache.HotKeyTopN,
+			ExtendTTL:       time.Duration(cfg.Cache.HotKeyExtendTTLSeconds) * time.Second,
+		}, logger)
+		go hotKeyCache.Run(ctx)
+		cacheInterface = hotKeyCache
+		slog.Info("Hot key protection enabled", "threshold", cfg.Cache.HotKeyThreshold, "top_n", cfg.Cache.HotKeyTopN)
+	}
+	cacheStats := cache.NewStatsCache(cacheInterface)
+	cacheInterface = cacheStats
+	if metricsExporter != nil {
+		if err := metrics.ObserveCache(cacheStats); err != nil {
+			slog.Warn("Failed to observe cache lookups", "error", err)
+		}
 	}
 
 	// Create and register the combined service (user + test)
-	combinedService := server.NewCombinedServer(userRepo, cacheInterface, logger)
+	if cfg.Server.DefaultPageSize < 1 || cfg.Server.MaxPageSize < cfg.Server.DefaultPageSize {
+		slog.Error("LIST_DEFAULT_PAGE_SIZE must be at least 1 and at most LIST_MAX_PAGE_SIZE",
+			"default", cfg.Server.DefaultPageSize, "max", cfg.Server.MaxPageSize)
+		os.Exit(1)
+	}
+	serverOpts := []server.Option{
+		server.WithEmailChangeTTL(time.Duration(cfg.Server.EmailChangeTTLMinutes) * time.Minute),
+		server.WithRuntimeConfig(runtimeConfig),
+		server.WithPageSizes(cfg.Server.DefaultPageSize, cfg.Server.MaxPageSize),
+	}
+	userStrategy, err := server.ParseCacheStrategy(server.EntityUser, cfg.Cache.UserStrategy)
+	if err != nil {
+		slog.Error("Invalid CACHE_USER_STRATEGY", "error", err)
+		os.Exit(1)
+	}
+	listStrategy, err := server.ParseCacheStrategy(server.EntityList, cfg.Cache.ListStrategy)
+	if err != nil {
+		slog.Error("Invalid CACHE_LIST_STRATEGY", "error", err)
+		os.Exit(1)
+	}
+	serverOpts = append(serverOpts, server.WithUserCacheStrategy(userStrategy))
+	if listStrategy == server.StrategyWriteBack {
+		writeBack := server.NewWriteBackQueue(cacheInterface, server.WriteBackConfig{
+			FlushInterval: time.Duration(cfg.Cache.WriteBackFlushMs) * time.Millisecond,
+			MaxPending:    cfg.Cache.WriteBackMaxPending,
+		}, logger)
+		go writeBack.Run(ctx)
+		serverOpts = append(serverOpts, server.WithListWriteBack(writeBack))
+	}
+	slog.Info("Cache strategies", "user", userStrategy, "list", listStrategy)
+	if cfg.Server.UpdateDedupWindowMs > 0 {
+		window := time.Duration(cfg.Server.UpdateDedupWindowMs) * time.Millisecond
+		serverOpts = append(serverOpts, server.WithUpdateDedup(dedup.New(valkeyCache, window, logger)))
+		slog.Info("UpdateUser deduplication enabled", "window", window)
+	}
+	if cfg.Cache.NegativeTTLSeconds > 0 {
+		serverOpts = append(serverOpts, server.WithNegativeCaching(time.Duration(cfg.Cache.NegativeTTLSeconds)*time.Second))
+	}
+	if !cfg.Server.ResponseMessagesEnabled {
+		serverOpts = append(serverOpts, server.WithoutResponseMessages())
+	}
+	if cfg.Cache.TenantNamespace {
+		serverOpts = append(serverOpts, server.WithTenantCacheKeys())
+	}
+	if cfg.Cache.KeyHMACSecret != "" {
+		serverOpts = append(serverOpts, server.WithCacheKeyHasher(cache.NewHMACHasher([]byte(cfg.Cache.KeyHMACSecret))))
+	}
+	slog.Info("Cache keys", "tenant_namespace", cfg.Cache.TenantNamespace, "hmac", cfg.Cache.KeyHMACSecret != "")
+	if cfg.Cache.InvalidationBroadcast {
+		var locals []server.LocalCache
+		if fallbackCache != nil {
+			locals = append(locals, fallbackCache)
+		}
+		if hotKeyCache != nil {
+			locals = append(locals, hotKeyCache)
+		}
+		serverOpts = append(serverOpts, server.WithInvalidationBroadcast(valkeyCache, locals...))
+	}
+	if cfg.Cache.SlidingTTLSeconds > 0 {
+		serverOpts = append(serverOpts, server.WithSlidingExpiration(
+			time.Duration(cfg.Cache.SlidingTTLSeconds)*time.Second,
+			time.Duration(cfg.Cache.SlidingMaxLifetimeSeconds)*time.Second,
+		))
+	}
+	if dbMonitor != nil {
+		serverOpts = append(serverOpts, server.WithDegradedReads(dbMonitor))
+	}
+	if cfg.Audit.Sink != "" {
+		auditLogger, auditSink, err := audit.NewChannel(audit.ChannelConfig{
+			Sink: cfg.Audit.Sink,
+			File: logging.FileConfig{
+				Path:         cfg.Audit.File,
+				MaxSizeBytes: int64(cfg.Audit.FileMaxSizeMB) << 20,
+				MaxBackups:   cfg.Audit.FileMaxBackups,
+				Compress:     true,
+			},
+			SyslogNetwork: cfg.Audit.SyslogNetwork,
+			SyslogAddress: cfg.Audit.SyslogAddress,
+			SyslogTag:     "rpc-server",
+		})
+		if err != nil {
+			slog.Error("Failed to open audit log", "sink", cfg.Audit.Sink, "error", err)
+			os.Exit(1)
+		}
+		defer auditSink.Close()
+		serverOpts = append(serverOpts, server.WithAuditLogger(auditLogger))
+	}
+	if cfg.Server.ExportSigningKey != "" {
+		serverOpts = append(serverOpts, server.WithExportSigner(export.NewSigner([]byte(cfg.Server.ExportSigningKey))))
+	} else {
+		slog.Warn("EXPORT_SIGNING_KEY not set, ExportUserData is disabled")
+	}
+	combinedService := server.NewCombinedServer(userRepo, cacheInterface, logger, serverOpts...)
+	if cfg.Server.TestStreamEnabled {
+		combinedService.EnableTestStream()
+		slog.Warn("TestStream enabled")
+	}
 	pb.RegisterUserServiceServer(grpcServer, combinedService)
+	if cfg.Cache.InvalidationBroadcast {
+		go valkeyCache.Subscribe(ctx, []string{server.InvalidationChannel}, combinedService.InvalidationSubscription(logger), cache.SubscribeConfig{
+			MinBackoff: reconnectMinBackoff,
+			MaxBackoff: reconnectMaxBackoff,
+		})
+	}
+	pb.RegisterAdminServiceServer(grpcServer, server.NewAdminServer(cacheStats, valkeyCache, runtimeConfig, userRepo))
+
+	// Start the inactive account expiry job if configured
+	if cfg.Retention.InactiveExpiryDays > 0 {
+		expiryJob := jobs.NewExpiryJob(userRepo, lock.NewPostgres(dbPool, logger), combinedService, events.NewTracingPublisher(events.NewLogPublisher(logger)), logger, jobs.ExpiryConfig{
+			InactiveFor: time.Duration(cfg.Retention.InactiveExpiryDays) * 24 * time.Hour,
+			Interval:    time.Duration(cfg.Retention.ExpiryIntervalMinutes) * time.Minute,
+			BatchSize:   cfg.Retention.ExpiryBatchSize,
+			Parallelism: cfg.Database.FanOutParallelism,
+			DryRun:      cfg.Retention.ExpiryDryRun,
+		})
+		go expiryJob.Run(ctx)
+		slog.Info("Inactive account expiry job started",
+			"inactive_days", cfg.Retention.InactiveExpiryDays,
+			"interval_minutes", cfg.Retention.ExpiryIntervalMinutes,
+			"dry_run", cfg.Retention.ExpiryDryRun,
+		)
+	}
+
+	// Start the table retention job if any table has a retention window
+	if cfg.Retention.HistoryRetentionDays > 0 || cfg.Retention.EmailChangeRetentionDays > 0 {
+		retentionJob := jobs.NewRetentionJob(userRepo, lock.NewPostgres(dbPool, logger), logger, jobs.RetentionConfig{
+			HistoryRetention:     time.Duration(cfg.Retention.HistoryRetentionDays) * 24 * time.Hour,
+			EmailChangeRetention: time.Duration(cfg.Retention.EmailChangeRetentionDays) * 24 * time.Hour,
+			Interval:             time.Duration(cfg.Retention.PruneIntervalMinutes) * time.Minute,
+			BatchSize:            cfg.Retention.PruneBatchSize,
+			MaxBatches:           cfg.Retention.PruneMaxBatches,
+		})
+		go retentionJob.Run(ctx)
+		slog.Info("Table retention job started",
+			"history_retention_days", cfg.Retention.HistoryRetentionDays,
+			"email_change_retention_days", cfg.Retention.EmailChangeRetentionDays,
+			"interval_minutes", cfg.Retention.PruneIntervalMinutes,
+		)
+	}
+
+	// Report SERVING only while the database and cache answer, so probes
+	// take the pod out of rotation during an outage
+	healthServer := grpc_health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	healthChecker := health.NewChecker(healthServer, []health.Check{
+		{Name: "database", Ping: dbPing},
+		{Name: "cache", Ping: valkeyCache.Ping},
+	}, []string{pb.UserService_ServiceDesc.ServiceName, pb.AdminService_ServiceDesc.ServiceName}, health.Config{
+		Interval: time.Duration(cfg.Server.HealthCheckIntervalMs) * time.Millisecond,
+		Timeout:  time.Duration(cfg.Server.HealthCheckTimeoutMs) * time.Millisecond,
+	}, logger)
+	healthChecker.Check(ctx)
+	go healthChecker.Run(ctx)
 
 	// Enable reflection if configured
 	if cfg.Server.EnableReflection {
@@ -141,6 +843,8 @@ func main() {
 			"max_send_size", cfg.Server.MaxSendMsgSize,
 			"reflection", cfg.Server.EnableReflection,
 			"tracing_enabled", cfg.Tracing.Enabled,
+			"tls", cfg.Server.TLSCertFile != "",
+			"mutual_tls", cfg.Server.TLSClientCAFile != "",
 		)
 		if err := grpcServer.Serve(listener); err != nil {
 			slog.Error("gRPC server failed", "error", err)
@@ -148,11 +852,105 @@ func main() {
 		}
 	}()
 
+	// Start HTTP listener (REST gateway, OpenAPI spec and Swagger UI) if configured
+	var httpServer *http.Server
+	if cfg.Server.HTTPPort != "" {
+		mux := http.NewServeMux()
+		openapi.Register(mux)
+		if cfg.Server.GatewayEnabled {
+			// The gateway reaches the server in-process, so it needs no
+			// client certificate under mutual TLS
+			gatewayListener := transport.NewInProcessListener()
+			go func() {
+				if err := grpcServer.Serve(gatewayListener); err != nil {
+					slog.Error("gRPC server failed on the gateway listener", "error", err)
+				}
+			}()
+			gw, err := gateway.New(ctx, gateway.Config{
+				Target:         transport.InProcessTarget,
+				Dial:           gatewayListener.DialContext,
+				MaxRecvMsgSize: cfg.Server.MaxRecvMsgSize,
+				MaxSendMsgSize: cfg.Server.MaxSendMsgSize,
+			})
+			if err != nil {
+				slog.Error("Failed to create REST gateway", "error", err)
+				os.Exit(1)
+			}
+			// Deferred calls run after the HTTP server has shut down
+			defer gw.Close()
+			gw.Register(mux)
+		}
+		if metricsExporter != nil {
+			metricsExporter.Register(mux)
+		}
+		if shardedRepo != nil {
+			shardedRepo.Register(mux)
+		}
+
+		httpServer = &http.Server{
+			Addr:              fmt.Sprintf(":%s", cfg.Server.HTTPPort),
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			slog.Info("HTTP server starting", "address", httpServer.Addr, "docs", openapi.DocsPath, "spec", openapi.SpecPath,
+				"gateway", cfg.Server.GatewayEnabled)
+			if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("HTTP server failed", "error", err)
+				cancel()
+			}
+		}()
+	}
+
+	// Serve fault injection rules apart from the public HTTP listener: they
+	// are unauthenticated and can fail every call
+	var adminServer *http.Server
+	if faultInjector != nil && cfg.Server.AdminHTTPAddr != "" {
+		mux := http.NewServeMux()
+		faultInjector.Register(mux)
+		adminServer = &http.Server{
+			Addr:              cfg.Server.AdminHTTPAddr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			slog.Info("Admin HTTP server starting", "address", adminServer.Addr, "fault_rules", chaos.RulesPath)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("Admin HTTP server failed", "error", err)
+				cancel()
+			}
+		}()
+	}
+
 	// Wait for shutdown signal
-	<-sigChan
-	slog.Info("Shutdown signal received, stopping server...")
+	select {
+	case <-sigChan:
+		slog.Info("Shutdown signal received, stopping server...")
+	case <-ctx.Done():
+		slog.Info("Server error, stopping server...")
+	}
 
-	// Graceful shutdown
+	// Graceful shutdown; fail health checks first so no new calls arrive
+	healthServer.Shutdown()
+	if httpServer != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			slog.Error("HTTP server shutdown failed", "error", err)
+		}
+		shutdownCancel()
+	}
+	if adminServer != nil {
+		// Nothing long-running is served here
+		adminServer.Close()
+	}
 	grpcServer.GracefulStop()
 	slog.Info("Server stopped gracefully")
 }
+
+// metricReaders returns the extra readers for the tracing MeterProvider.
+func metricReaders(exporter *metrics.Exporter) []sdkmetric.Reader {
+	if exporter == nil {
+		return nil
+	}
+	return []sdkmetric.Reader{exporter.Reader()}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"grpc-server/internal/anonymize"
+	"grpc-server/internal/backup"
+	"grpc-server/internal/capture"
+	"grpc-server/internal/config"
+	"grpc-server/internal/database"
+	"grpc-server/internal/lock"
+	"grpc-server/internal/migrations"
+)
+
+// runBackup implements `server backup [-o file]`.
+func runBackup(args []string) int {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	output := fs.String("o", "-", "archive to write, - for stdout")
+	fs.Parse(args)
+
+	return withDatabase(func(ctx context.Context, db *pgxpool.Pool) error {
+		var w io.Writer = os.Stdout
+		if *output != "-" {
+			f, err := os.Create(*output)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+
+		stats, err := backup.Backup(ctx, db, w)
+		if err != nil {
+			return err
+		}
+		slog.Info("Backup completed", "output", *output, "rows", stats.Rows)
+		return nil
+	})
+}
+
+// runRestore implements `server restore [-i file] [-truncate]`.
+func runRestore(args []string) int {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	input := fs.String("i", "-", "archive to read, - for stdin")
+	truncate := fs.Bool("truncate", false, "replace existing data instead of refusing to restore into non-empty tables")
+	fs.Parse(args)
+
+	return withDatabase(func(ctx context.Context, db *pgxpool.Pool) error {
+		var r io.Reader = os.Stdin
+		if *input != "-" {
+			f, err := os.Open(*input)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+
+		return withRewriteLock(ctx, db, func(ctx context.Context) error {
+			stats, err := backup.Restore(ctx, db, r, *truncate)
+			if err != nil {
+				return err
+			}
+			slog.Info("Restore completed", "input", *input, "rows", stats.Rows)
+			return nil
+		})
+	})
+}
+
+// withDatabase runs fn against a fresh connection pool, logging to stderr so
+// stdout stays free for archive data.
+func withDatabase(fn func(ctx context.Context, db *pgxpool.Pool) error) int {
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.Connect(ctx, config.LoadDatabase())
+	if err != nil {
+		slog.Error("Failed to connect to database", "error", err)
+		return 1
+	}
+	defer db.Close()
+
+	if err := fn(ctx, db); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// withRewriteLock keeps restore and anonymize runs, which both rewrite the
+// users table, from overlapping.
+func withRewriteLock(ctx context.Context, db *pgxpool.Pool, fn func(ctx context.Context) error) error {
+	ran, err := lock.Do(ctx, lock.NewPostgres(db, slog.Default()), "maintenance.rewrite", fn)
+	if err != nil {
+		return err
+	}
+	if !ran {
+		return fmt.Errorf("another restore or anonymize run is in progress")
+	}
+	return nil
+}
+
+// runAnonymize implements `server anonymize -salt s -confirm`.
+func runAnonymize(args []string) int {
+	fs := flag.NewFlagSet("anonymize", flag.ExitOnError)
+	salt := fs.String("salt", "", "secret salt for deriving replacement values (required)")
+	confirm := fs.Bool("confirm", false, "confirm that PII in the target database should be overwritten")
+	fs.Parse(args)
+
+	if *salt == "" || !*confirm {
+		fmt.Fprintln(os.Stderr, "anonymize rewrites personal data in place and cannot be undone; pass -salt and -confirm for a non-production copy")
+		return 2
+	}
+
+	return withDatabase(func(ctx context.Context, db *pgxpool.Pool) error {
+		return withRewriteLock(ctx, db, func(ctx context.Context) error {
+			count, err := anonymize.New(*salt).Users(ctx, db)
+			if err != nil {
+				return err
+			}
+			slog.Info("Anonymization completed", "users", count)
+			return nil
+		})
+	})
+}
+
+// runMigrate implements `server migrate`, also accepted as `server -migrate`.
+// It applies pending migrations to the database at DATABASE_URL and then to
+// each shard.
+func runMigrate(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Parse(args)
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg := config.LoadDatabase()
+	for i, url := range append([]string{cfg.URL}, cfg.ShardURLs...) {
+		shardCfg := *cfg
+		shardCfg.URL = url
+		db, err := database.Connect(ctx, &shardCfg)
+		if err != nil {
+			slog.Error("Failed to connect to database", "shard", i, "error", err)
+			return 1
+		}
+		applied, err := migrations.Apply(ctx, db, slog.Default())
+		db.Close()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		slog.Info("Migrations applied", "shard", i, "applied", applied)
+	}
+	return 0
+}
+
+// runReplay implements `server replay -target addr [-i file] [-rate n] [-read-only]`.
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	target := fs.String("target", "", "address of the instance to replay against (required)")
+	input := fs.String("i", "-", "capture to read, - for stdin")
+	rate := fs.Float64("rate", 0, "calls per second, 0 for unlimited")
+	readOnly := fs.Bool("read-only", false, "only replay Get and List calls")
+	timeout := fs.Duration("timeout", 10*time.Second, "timeout for each call")
+	fs.Parse(args)
+
+	if *target == "" {
+		fmt.Fprintln(os.Stderr, "replay re-issues captured calls, including writes unless -read-only is set; pass -target with a staging address")
+		return 2
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	slog.SetDefault(logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var r io.Reader = os.Stdin
+	if *input != "-" {
+		f, err := os.Open(*input)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		r = f
+	}
+
+	conn, err := grpc.NewClient(*target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer conn.Close()
+
+	stats, err := capture.Replay(ctx, conn, r, capture.ReplayOptions{
+		Rate:     *rate,
+		ReadOnly: *readOnly,
+		Timeout:  *timeout,
+	}, logger)
+	slog.Info("Replay finished", "replayed", stats.Replayed, "skipped", stats.Skipped, "mismatched", stats.Mismatched)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if stats.Mismatched > 0 {
+		return 3
+	}
+	return 0
+}
@@ -0,0 +1,32 @@
+// Package actor identifies who is making a change, for attribution of
+// stored records.
+package actor
+
+import "context"
+
+// Anonymous is the actor of changes made by a call without a verified
+// token.
+const Anonymous = "anonymous"
+
+// System returns the actor for changes made by a background job.
+func System(job string) string {
+	return "system:" + job
+}
+
+type actorKey struct{}
+
+// With returns a context whose changes are attributed to id.
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, actorKey{}, id)
+}
+
+// FromContext returns the actor set by With: the token's subject for
+// authenticated calls, or System for background jobs. Anything else is
+// Anonymous; what a caller claims in its metadata is never used, since any
+// caller could claim to be anyone.
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(actorKey{}).(string); ok {
+		return id
+	}
+	return Anonymous
+}
@@ -0,0 +1,169 @@
+// Package anonymize rewrites personal data in place for cloned databases used
+// outside production. Replacements are derived from a salt and the original
+// value, so re-running with the same salt gives the same output and distinct
+// inputs stay distinct.
+package anonymize
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grpc-server/internal/database"
+	"grpc-server/internal/tenant"
+)
+
+// EmailDomain is reserved (RFC 2606), so anonymized addresses can never reach
+// a real inbox.
+const EmailDomain = "example.com"
+
+const batchSize = 500
+
+var (
+	firstNames = []string{
+		"Alex", "Blair", "Casey", "Dana", "Eden", "Finley", "Gray", "Harper",
+		"Indy", "Jordan", "Kai", "Logan", "Morgan", "Noel", "Oakley", "Parker",
+		"Quinn", "Reese", "Sage", "Taylor", "Uma", "Val", "Wren", "Yael",
+	}
+	lastNames = []string{
+		"Abbott", "Baker", "Chen", "Diaz", "Evans", "Fischer", "Garcia", "Huang",
+		"Ito", "Jensen", "Kim", "Lopez", "Moreau", "Novak", "Okafor", "Patel",
+		"Rossi", "Silva", "Tanaka", "Ueda", "Varga", "Weber", "Young", "Zhou",
+	}
+)
+
+// Anonymizer derives replacement values from a secret salt.
+type Anonymizer struct {
+	salt []byte
+}
+
+func New(salt string) *Anonymizer {
+	return &Anonymizer{salt: []byte(salt)}
+}
+
+func (a *Anonymizer) sum(kind, value string) []byte {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// Name returns a fake but plausible name for the user with id.
+func (a *Anonymizer) Name(id string) string {
+	sum := a.sum("name", id)
+	first := firstNames[binary.BigEndian.Uint32(sum[0:4])%uint32(len(firstNames))]
+	last := lastNames[binary.BigEndian.Uint32(sum[4:8])%uint32(len(lastNames))]
+	return first + " " + last
+}
+
+// Email hashes email into EmailDomain. The 96-bit local part keeps distinct
+// addresses distinct, preserving email uniqueness.
+func (a *Anonymizer) Email(email string) string {
+	return fmt.Sprintf("user-%s@%s", hex.EncodeToString(a.sum("email", email)[:12]), EmailDomain)
+}
+
+// Users rewrites the name and email of every user in one transaction and
+// returns how many rows were changed. IDs are untouched, so references to
+// users stay valid, and updated_at is preserved so retention jobs behave as
+// they would on the original data. Past versions in user_history are
+// rewritten the same way; user triggers are disabled meanwhile so the rewrite
+// neither bumps updated_at nor records new versions. Pending email changes
+// are dropped.
+func (a *Anonymizer) Users(ctx context.Context, pool *pgxpool.Pool) (int, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	if err := database.SetTenant(tenant.All(ctx), tx); err != nil {
+		return 0, fmt.Errorf("failed to select every tenant: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, "ALTER TABLE users DISABLE TRIGGER USER"); err != nil {
+		return 0, fmt.Errorf("failed to disable user triggers: %w", err)
+	}
+	// Pending changes hold real addresses and tokens nobody can confirm.
+	if _, err := tx.Exec(ctx, "DELETE FROM email_changes"); err != nil {
+		return 0, fmt.Errorf("failed to clear pending email changes: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, "SELECT id::text, email FROM users ORDER BY id FOR UPDATE")
+	if err != nil {
+		return 0, fmt.Errorf("failed to query users: %w", err)
+	}
+	type user struct{ id, email string }
+	users, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (user, error) {
+		var u user
+		err := row.Scan(&u.id, &u.email)
+		return u, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read users: %w", err)
+	}
+
+	for start := 0; start < len(users); start += batchSize {
+		batch := &pgx.Batch{}
+		for _, u := range users[start:min(start+batchSize, len(users))] {
+			batch.Queue("UPDATE users SET name = $2, email = $3 WHERE id = $1", u.id, a.Name(u.id), a.Email(u.email))
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return 0, fmt.Errorf("failed to update users: %w", err)
+		}
+	}
+
+	if err := a.history(ctx, tx); err != nil {
+		return 0, err
+	}
+	// The email index is trigger-maintained too, so rebuild it from users.
+	if _, err := tx.Exec(ctx, "DELETE FROM user_emails"); err != nil {
+		return 0, fmt.Errorf("failed to clear email index: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO user_emails (tenant_id, email, user_id) SELECT tenant_id, email, id FROM users"); err != nil {
+		return 0, fmt.Errorf("failed to rebuild email index: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, "ALTER TABLE users ENABLE TRIGGER USER"); err != nil {
+		return 0, fmt.Errorf("failed to re-enable user triggers: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(users), nil
+}
+
+func (a *Anonymizer) history(ctx context.Context, tx pgx.Tx) error {
+	rows, err := tx.Query(ctx, "SELECT history_id, user_id::text, email FROM user_history ORDER BY history_id FOR UPDATE")
+	if err != nil {
+		return fmt.Errorf("failed to query user history: %w", err)
+	}
+	type version struct {
+		id            int64
+		userID, email string
+	}
+	versions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (version, error) {
+		var v version
+		err := row.Scan(&v.id, &v.userID, &v.email)
+		return v, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read user history: %w", err)
+	}
+
+	for start := 0; start < len(versions); start += batchSize {
+		batch := &pgx.Batch{}
+		for _, v := range versions[start:min(start+batchSize, len(versions))] {
+			batch.Queue("UPDATE user_history SET name = $2, email = $3 WHERE history_id = $1", v.id, a.Name(v.userID), a.Email(v.email))
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return fmt.Errorf("failed to update user history: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Actions recorded in the audit log.
+const (
+	ActionUserErased   = "user.erased"
+	ActionUserExported = "user.exported"
+	ActionUserReverted = "user.reverted"
+	ActionUserMerged   = "user.merged"
+	ActionEmailChanged = "user.email_changed"
+)
+
+// Logger records security- and compliance-relevant actions. Records carry a
+// log_type=audit attribute so they can be routed and retained separately from
+// application logs. They are written regardless of the handler's level.
+type Logger struct {
+	logger *slog.Logger
+}
+
+// New writes audit records through base, alongside application logs. Use
+// NewChannel to give them a sink of their own.
+func New(base *slog.Logger) *Logger {
+	return &Logger{logger: base.With("log_type", "audit")}
+}
+
+// Record writes one audit record for action. A record the handler fails to
+// write is reported on stderr rather than dropped silently.
+func (l *Logger) Record(ctx context.Context, action string, attrs ...slog.Attr) {
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "audit: "+action, 0)
+	r.AddAttrs(slog.String("action", action))
+	r.AddAttrs(attrs...)
+	if err := l.logger.Handler().Handle(ctx, r); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write audit record %s: %v\n", action, err)
+	}
+}
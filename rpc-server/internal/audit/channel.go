@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"log/syslog"
+	"os"
+
+	"grpc-server/internal/logging"
+)
+
+// Sinks an audit channel can write to.
+const (
+	SinkStdout = "stdout"
+	SinkFile   = "file"
+	SinkSyslog = "syslog"
+)
+
+// ChannelConfig selects where a dedicated audit channel writes.
+type ChannelConfig struct {
+	Sink string
+	// File configures SinkFile; every record is synced to disk.
+	File logging.FileConfig
+	// SyslogNetwork and SyslogAddress configure SinkSyslog; both empty use
+	// the local syslog daemon.
+	SyslogNetwork string
+	SyslogAddress string
+	SyslogTag     string
+}
+
+// NewChannel opens the sink in cfg and returns a Logger writing JSON records
+// to it. The channel has no level or sampling of its own, so every record is
+// written whatever the application log settings are. Close the returned
+// writer on shutdown.
+func NewChannel(cfg ChannelConfig) (*Logger, io.Closer, error) {
+	sink, err := openSink(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	handler := logging.NewTraceContextHandler(slog.NewJSONHandler(sink, nil))
+	return New(slog.New(handler)), sink, nil
+}
+
+func openSink(cfg ChannelConfig) (io.WriteCloser, error) {
+	switch cfg.Sink {
+	case SinkStdout:
+		return nopCloser{os.Stdout}, nil
+	case SinkFile:
+		fileCfg := cfg.File
+		fileCfg.Sync = true
+		f, err := logging.OpenRotatingFile(fileCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log file: %w", err)
+		}
+		return f, nil
+	case SinkSyslog:
+		w, err := syslog.Dial(cfg.SyslogNetwork, cfg.SyslogAddress, syslog.LOG_INFO|syslog.LOG_AUTH, cfg.SyslogTag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+		}
+		return w, nil
+	default:
+		return nil, fmt.Errorf("unknown audit log sink %q", cfg.Sink)
+	}
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,227 @@
+// Package auth authenticates calls with JWT bearer tokens signed by keys
+// from a JWKS URL and authorizes them against per-method role requirements.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	"grpc-server/internal/actor"
+	"grpc-server/internal/clock"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/tenant"
+	"grpc-server/internal/transport"
+	"grpc-server/pkg/apierror"
+)
+
+// Subject is the log field naming the authenticated caller.
+const Subject = "subject"
+
+// healthService is answered without a token: probes have none.
+const healthService = "/grpc.health.v1.Health/"
+
+// Claims are the claims of a verified token.
+type Claims struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	Roles     []string
+	Tenant    string
+	ExpiresAt time.Time
+	NotBefore time.Time
+}
+
+// HasRole reports whether the token grants role.
+func (c *Claims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+type claimsKey struct{}
+
+// WithClaims returns a copy of ctx carrying claims.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext returns the claims of the call's token, or nil outside an
+// authenticated call.
+func FromContext(ctx context.Context) *Claims {
+	claims, _ := ctx.Value(claimsKey{}).(*Claims)
+	return claims
+}
+
+// ParsePermissions parses a comma-separated list of the role each method
+// requires ("DeleteUser=admin,EraseUser=admin"), keyed by method name
+// without the service.
+func ParsePermissions(s string) (map[string]string, error) {
+	permissions := make(map[string]string)
+	for entry := range strings.SplitSeq(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		method, role, ok := strings.Cut(entry, "=")
+		method, role = strings.TrimSpace(method), strings.TrimSpace(role)
+		if !ok || method == "" || role == "" {
+			return nil, fmt.Errorf("invalid permission %q: want Method=role", entry)
+		}
+		permissions[method] = role
+	}
+	return permissions, nil
+}
+
+// Config configures a Verifier.
+type Config struct {
+	// JWKSURL is where the issuer publishes its signing keys.
+	JWKSURL string
+	// RefreshInterval is how often the keys are fetched again.
+	RefreshInterval time.Duration
+	// Issuer and Audience, when set, must match the token's iss and be one
+	// of its aud.
+	Issuer   string
+	Audience string
+	// RolesClaim names the claim listing the caller's roles, and
+	// TenantClaim the one naming the tenant the token was issued for.
+	RolesClaim  string
+	TenantClaim string
+	// Permissions maps method names to the role they require. Methods not
+	// listed only require a valid token.
+	Permissions map[string]string
+	Clock       clock.Clock
+}
+
+// Verifier checks the bearer tokens of incoming calls.
+type Verifier struct {
+	keys        *KeySet
+	issuer      string
+	audience    string
+	rolesClaim  string
+	tenantClaim string
+	permissions map[string]string
+	clock       clock.Clock
+}
+
+func NewVerifier(cfg Config, base *slog.Logger) *Verifier {
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = "roles"
+	}
+	if cfg.TenantClaim == "" {
+		cfg.TenantClaim = "tenant"
+	}
+	return &Verifier{
+		keys:        NewKeySet(cfg.JWKSURL, cfg.RefreshInterval, base),
+		issuer:      cfg.Issuer,
+		audience:    cfg.Audience,
+		rolesClaim:  cfg.RolesClaim,
+		tenantClaim: cfg.TenantClaim,
+		permissions: cfg.Permissions,
+		clock:       clock.OrSystem(cfg.Clock),
+	}
+}
+
+// Keys returns the verifier's key set, to fetch the keys up front and keep
+// them fresh.
+func (v *Verifier) Keys() *KeySet {
+	return v.keys
+}
+
+// UnaryServerInterceptor rejects calls without a valid bearer token with
+// UNAUTHENTICATED, and calls whose token lacks their method's role with
+// PERMISSION_DENIED. Accepted calls carry the token's claims (see
+// FromContext), and their changes are attributed to its subject.
+func (v *Verifier) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	rejections := newRejectionCounter()
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := v.authenticate(ctx, info.FullMethod, rejections)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls. The
+// token is checked once, when the stream opens.
+func (v *Verifier) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	rejections := newRejectionCounter()
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := v.authenticate(ss.Context(), info.FullMethod, rejections)
+		if err != nil {
+			return err
+		}
+		return handler(srv, transport.WithStreamContext(ss, ctx))
+	}
+}
+
+func newRejectionCounter() metric.Int64Counter {
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	rejections, _ := otel.Meter("rpc-server.rpc/auth").Int64Counter("rpc.auth.rejections",
+		metric.WithDescription("Calls rejected for a missing or invalid token or a missing role"),
+	)
+	return rejections
+}
+
+// authenticate checks the bearer token of a call to fullMethod and returns
+// ctx carrying its claims, or the status error to reject the call with.
+func (v *Verifier) authenticate(ctx context.Context, fullMethod string, rejections metric.Int64Counter) (context.Context, error) {
+	if strings.HasPrefix(fullMethod, healthService) {
+		return ctx, nil
+	}
+	method := path.Base(fullMethod)
+	logger := logging.FromContext(ctx)
+
+	token, ok := bearerToken(ctx)
+	if !ok {
+		rejections.Add(ctx, 1, metric.WithAttributes(attribute.String("rpc.method", method), attribute.String("reason", "missing_token")))
+		return nil, apierror.New(grpc_codes.Unauthenticated, apierror.ReasonUnauthenticated,
+			"a bearer token is required", nil, nil)
+	}
+	claims, err := v.verify(ctx, token)
+	if err != nil {
+		rejections.Add(ctx, 1, metric.WithAttributes(attribute.String("rpc.method", method), attribute.String("reason", "invalid_token")))
+		logger.WarnCtx(ctx, "Rejected invalid bearer token", logging.Error, err)
+		return nil, apierror.New(grpc_codes.Unauthenticated, apierror.ReasonUnauthenticated,
+			"the bearer token is not valid", nil, nil)
+	}
+	if role, ok := v.permissions[method]; ok && !claims.HasRole(role) {
+		rejections.Add(ctx, 1, metric.WithAttributes(attribute.String("rpc.method", method), attribute.String("reason", "missing_role")))
+		logger.WarnCtx(ctx, "Rejected call without required role", Subject, claims.Subject, "required_role", role)
+		return nil, apierror.New(grpc_codes.PermissionDenied, apierror.ReasonPermissionDenied,
+			fmt.Sprintf("%s requires the %s role", method, role), nil,
+			map[string]string{"method": method, "required_role": role})
+	}
+
+	ctx = WithClaims(ctx, claims)
+	ctx = actor.With(ctx, claims.Subject)
+	ctx = tenant.With(ctx, claims.Tenant)
+	ctx = logging.WithLogger(ctx, logging.New(logger.With(Subject, claims.Subject)))
+	return ctx, nil
+}
+
+// bearerToken returns the token of the call's "authorization: Bearer"
+// metadata.
+func bearerToken(ctx context.Context) (string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", false
+	}
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
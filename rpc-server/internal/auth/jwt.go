@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers SHA-256 for crypto.Hash
+	_ "crypto/sha512" // registers SHA-384 and SHA-512
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// clockSkew is how far the server's clock may differ from the issuer's
+// when checking exp and nbf.
+const clockSkew = 30 * time.Second
+
+// algorithms maps the JWS algorithms accepted to their hash. "none" and the
+// HMAC algorithms are never accepted: the keys come from a public JWKS.
+var algorithms = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"PS256": crypto.SHA256,
+	"PS384": crypto.SHA384,
+	"PS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// verify checks token's signature against the key set and its claims
+// against v's requirements, and returns the claims.
+func (v *Verifier) verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token is not a signed JWT")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	hash, ok := algorithms[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported algorithm %q", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	key, err := v.keys.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	h := hash.New()
+	h.Write([]byte(parts[0] + "." + parts[1]))
+	if err := verifySignature(header.Alg, key, hash, h.Sum(nil), signature); err != nil {
+		return nil, err
+	}
+
+	var payload map[string]json.RawMessage
+	if err := decodeSegment(parts[1], &payload); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	claims, err := v.claims(payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.validate(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, hash crypto.Hash, digest, signature []byte) error {
+	invalid := errors.New("invalid signature")
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		var err error
+		switch alg[:2] {
+		case "RS":
+			err = rsa.VerifyPKCS1v15(key, hash, digest, signature)
+		case "PS":
+			err = rsa.VerifyPSS(key, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		default:
+			return fmt.Errorf("algorithm %s does not match an RSA key", alg)
+		}
+		if err != nil {
+			return invalid
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if alg[:2] != "ES" {
+			return fmt.Errorf("algorithm %s does not match an EC key", alg)
+		}
+		// JWS ECDSA signatures are r and s as fixed-size big-endian integers
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return invalid
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return invalid
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+// claims reads the registered claims, roles and tenant from a token's payload.
+func (v *Verifier) claims(payload map[string]json.RawMessage) (*Claims, error) {
+	claims := &Claims{}
+	for name, target := range map[string]any{
+		"sub": &claims.Subject,
+		"iss": &claims.Issuer,
+	} {
+		if raw, ok := payload[name]; ok {
+			if err := json.Unmarshal(raw, target); err != nil {
+				return nil, fmt.Errorf("invalid %s claim: %w", name, err)
+			}
+		}
+	}
+	for name, target := range map[string]*time.Time{
+		"exp": &claims.ExpiresAt,
+		"nbf": &claims.NotBefore,
+	} {
+		if raw, ok := payload[name]; ok {
+			var seconds float64
+			if err := json.Unmarshal(raw, &seconds); err != nil {
+				return nil, fmt.Errorf("invalid %s claim: %w", name, err)
+			}
+			*target = time.Unix(int64(seconds), 0)
+		}
+	}
+	// aud is either one audience or a list of them
+	if raw, ok := payload["aud"]; ok {
+		var single string
+		if err := json.Unmarshal(raw, &single); err == nil {
+			claims.Audience = []string{single}
+		} else if err := json.Unmarshal(raw, &claims.Audience); err != nil {
+			return nil, fmt.Errorf("invalid aud claim: %w", err)
+		}
+	}
+	if raw, ok := payload[v.rolesClaim]; ok {
+		if err := json.Unmarshal(raw, &claims.Roles); err != nil {
+			return nil, fmt.Errorf("invalid %s claim: %w", v.rolesClaim, err)
+		}
+	}
+	if raw, ok := payload[v.tenantClaim]; ok {
+		if err := json.Unmarshal(raw, &claims.Tenant); err != nil {
+			return nil, fmt.Errorf("invalid %s claim: %w", v.tenantClaim, err)
+		}
+	}
+	return claims, nil
+}
+
+// validate checks expiry, issuer and audience. Tokens without exp are
+// rejected; a token that never expires is a credential that cannot be
+// revoked.
+func (v *Verifier) validate(claims *Claims) error {
+	now := v.clock.Now()
+	switch {
+	case claims.ExpiresAt.IsZero():
+		return errors.New("token has no expiry")
+	case now.After(claims.ExpiresAt.Add(clockSkew)):
+		return errors.New("token has expired")
+	case !claims.NotBefore.IsZero() && now.Add(clockSkew).Before(claims.NotBefore):
+		return errors.New("token is not valid yet")
+	case claims.Subject == "":
+		return errors.New("token has no subject")
+	case v.issuer != "" && claims.Issuer != v.issuer:
+		return fmt.Errorf("token issuer %q is not trusted", claims.Issuer)
+	case v.audience != "" && !slices.Contains(claims.Audience, v.audience):
+		return errors.New("token is not meant for this service")
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,293 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"grpc-server/internal/clock"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testAudience = "rpc-server"
+)
+
+var now = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// signer holds the keys published by the test JWKS.
+type signer struct {
+	rsa   *rsa.PrivateKey
+	ec    *ecdsa.PrivateKey
+	other *rsa.PrivateKey // not published
+}
+
+func newSigner(t *testing.T) *signer {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &signer{rsa: rsaKey, ec: ecKey, other: other}
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// serveJWKS publishes the RSA key as "rsa" and the EC key as "ec".
+func (s *signer) serveJWKS(t *testing.T) string {
+	t.Helper()
+	size := (s.ec.Curve.Params().BitSize + 7) / 8
+	set := map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": b64(s.rsa.N.Bytes()), "e": b64(big.NewInt(int64(s.rsa.E)).Bytes())},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64(s.ec.X.FillBytes(make([]byte, size))), "y": b64(s.ec.Y.FillBytes(make([]byte, size)))},
+	}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// sign returns a token for claims, signed with key under alg and kid.
+func sign(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := b64(header) + "." + b64(payload)
+
+	var signature []byte
+	var err error
+	if hash, ok := algorithms[alg]; ok {
+		h := hash.New()
+		h.Write([]byte(input))
+		digest := h.Sum(nil)
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			if strings.HasPrefix(alg, "PS") {
+				signature, err = rsa.SignPSS(rand.Reader, k, hash, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+			} else {
+				signature, err = rsa.SignPKCS1v15(rand.Reader, k, hash, digest)
+			}
+		case *ecdsa.PrivateKey:
+			var r, s *big.Int
+			r, s, err = ecdsa.Sign(rand.Reader, k, digest)
+			size := (k.Curve.Params().BitSize + 7) / 8
+			signature = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return input + "." + b64(signature)
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":    "user-1",
+		"iss":    testIssuer,
+		"aud":    testAudience,
+		"exp":    now.Add(time.Hour).Unix(),
+		"roles":  []string{"admin"},
+		"tenant": "acme",
+	}
+}
+
+// with returns validClaims with the given claims replaced, or removed when
+// their value is nil.
+func with(overrides map[string]any) map[string]any {
+	claims := validClaims()
+	for name, value := range overrides {
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+	}
+	return claims
+}
+
+func newTestVerifier(t *testing.T, s *signer) *Verifier {
+	t.Helper()
+	v := NewVerifier(Config{
+		JWKSURL:     s.serveJWKS(t),
+		Issuer:      testIssuer,
+		Audience:    testAudience,
+		Permissions: map[string]string{"DeleteUser": "admin"},
+		Clock:       clock.NewFake(now),
+	}, slog.New(slog.DiscardHandler))
+	if err := v.Keys().Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestVerify(t *testing.T) {
+	s := newSigner(t)
+	v := newTestVerifier(t, s)
+	tampered := strings.Split(sign(t, "RS256", "rsa", s.rsa, validClaims()), ".")
+	tampered[1] = b64([]byte(`{"sub":"admin","exp":9999999999}`))
+
+	for _, tt := range []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{"RS256", sign(t, "RS256", "rsa", s.rsa, validClaims()), ""},
+		{"RS512", sign(t, "RS512", "rsa", s.rsa, validClaims()), ""},
+		{"PS256", sign(t, "PS256", "rsa", s.rsa, validClaims()), ""},
+		{"ES256", sign(t, "ES256", "ec", s.ec, validClaims()), ""},
+		{"audience in a list", sign(t, "RS256", "rsa", s.rsa, with(map[string]any{"aud": []string{"other", testAudience}})), ""},
+
+		{"not a JWT", "abc.def", "not a signed JWT"},
+		{"tampered payload", strings.Join(tampered, "."), "invalid signature"},
+		{"signed by an unpublished key", sign(t, "RS256", "rsa", s.other, validClaims()), "invalid signature"},
+		{"unknown key", sign(t, "RS256", "missing", s.rsa, validClaims()), "unknown"},
+		{"alg none", sign(t, "none", "rsa", s.rsa, validClaims()), "unsupported algorithm"},
+		{"HMAC", sign(t, "HS256", "rsa", s.rsa, validClaims()), "unsupported algorithm"},
+		{"RSA algorithm on an EC key", sign(t, "RS256", "ec", s.rsa, validClaims()), "does not match"},
+		{"EC algorithm on an RSA key", sign(t, "ES256", "rsa", s.ec, validClaims()), "does not match"},
+
+		{"no expiry", sign(t, "RS256", "rsa", s.rsa, with(map[string]any{"exp": nil})), "no expiry"},
+		{"expired", sign(t, "RS256", "rsa", s.rsa, with(map[string]any{"exp": now.Add(-time.Minute).Unix()})), "expired"},
+		{"expired within the clock skew", sign(t, "RS256", "rsa", s.rsa, with(map[string]any{"exp": now.Add(-clockSkew / 2).Unix()})), ""},
+		{"not valid yet", sign(t, "RS256", "rsa", s.rsa, with(map[string]any{"nbf": now.Add(time.Minute).Unix()})), "not valid yet"},
+		{"no subject", sign(t, "RS256", "rsa", s.rsa, with(map[string]any{"sub": nil})), "no subject"},
+		{"untrusted issuer", sign(t, "RS256", "rsa", s.rsa, with(map[string]any{"iss": "https://evil.example.com"})), "not trusted"},
+		{"other audience", sign(t, "RS256", "rsa", s.rsa, with(map[string]any{"aud": "other"})), "not meant for this service"},
+		{"no audience", sign(t, "RS256", "rsa", s.rsa, with(map[string]any{"aud": nil})), "not meant for this service"},
+		{"roles not a list", sign(t, "RS256", "rsa", s.rsa, with(map[string]any{"roles": "admin"})), "invalid roles claim"},
+		{"tenant not a string", sign(t, "RS256", "rsa", s.rsa, with(map[string]any{"tenant": 1})), "invalid tenant claim"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.verify(context.Background(), tt.token)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("verify() = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.Subject != "user-1" || claims.Tenant != "acme" || !slices.Equal(claims.Roles, []string{"admin"}) {
+				t.Errorf("verify() = %+v, want the token's subject, tenant and roles", claims)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	s := newSigner(t)
+	intercept := newTestVerifier(t, s).UnaryServerInterceptor()
+	admin := sign(t, "RS256", "rsa", s.rsa, validClaims())
+	user := sign(t, "RS256", "rsa", s.rsa, with(map[string]any{"roles": []string{}}))
+
+	for _, tt := range []struct {
+		name   string
+		method string
+		auth   string
+		want   grpc_codes.Code
+	}{
+		{"health needs no token", "/grpc.health.v1.Health/Check", "", grpc_codes.OK},
+		{"no token", "/user.UserService/GetUser", "", grpc_codes.Unauthenticated},
+		{"not a bearer token", "/user.UserService/GetUser", "Basic " + admin, grpc_codes.Unauthenticated},
+		{"invalid token", "/user.UserService/GetUser", "Bearer " + admin + "x", grpc_codes.Unauthenticated},
+		{"valid token", "/user.UserService/GetUser", "Bearer " + user, grpc_codes.OK},
+		{"missing role", "/user.UserService/DeleteUser", "Bearer " + user, grpc_codes.PermissionDenied},
+		{"required role", "/user.UserService/DeleteUser", "bearer " + admin, grpc_codes.OK},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.auth != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.auth))
+			}
+			var claims *Claims
+			_, err := intercept(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, _ any) (any, error) {
+				claims = FromContext(ctx)
+				return nil, nil
+			})
+			if code := status.Code(err); code != tt.want {
+				t.Fatalf("code = %v, want %v (%v)", code, tt.want, err)
+			}
+			if tt.want == grpc_codes.OK && tt.auth != "" && (claims == nil || claims.Subject != "user-1") {
+				t.Errorf("handler saw claims %+v, want the token's", claims)
+			}
+		})
+	}
+}
+
+// fakeStream is a server stream that only has a context.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	s := newSigner(t)
+	intercept := newTestVerifier(t, s).StreamServerInterceptor()
+	user := sign(t, "RS256", "rsa", s.rsa, with(map[string]any{"roles": []string{}}))
+
+	for _, tt := range []struct {
+		name string
+		auth string
+		want grpc_codes.Code
+	}{
+		{"no token", "", grpc_codes.Unauthenticated},
+		{"invalid token", "Bearer " + user + "x", grpc_codes.Unauthenticated},
+		{"valid token", "Bearer " + user, grpc_codes.OK},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.auth != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.auth))
+			}
+			called := false
+			info := &grpc.StreamServerInfo{FullMethod: "/user.UserService/TestStream", IsClientStream: true, IsServerStream: true}
+			err := intercept(nil, fakeStream{ctx: ctx}, info, func(_ any, ss grpc.ServerStream) error {
+				called = true
+				if claims := FromContext(ss.Context()); claims == nil || claims.Subject != "user-1" {
+					t.Errorf("handler saw claims %+v, want the token's", claims)
+				}
+				return nil
+			})
+			if code := status.Code(err); code != tt.want {
+				t.Fatalf("code = %v, want %v (%v)", code, tt.want, err)
+			}
+			if called != (tt.want == grpc_codes.OK) {
+				t.Errorf("handler called = %v, want %v", called, tt.want == grpc_codes.OK)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Package backup writes and restores logical backups of the service's tables
+// as gzip-compressed JSON lines: one Header line followed by one Record per
+// row.
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grpc-server/internal/database"
+	"grpc-server/internal/tenant"
+)
+
+// Format and Version identify the archive layout; Version is bumped whenever
+// a table or column is added to the archive. Restore also accepts archives
+// from MinVersion on; columns they lack are restored as NULL, or empty for
+// text columns that are NOT NULL.
+const (
+	Format     = "arch-backup"
+	Version    = 4
+	MinVersion = 1
+)
+
+// ErrNotEmpty is returned by Restore when the target tables already hold data
+// and truncation was not requested.
+var ErrNotEmpty = errors.New("target tables are not empty")
+
+// Header is the first line of an archive.
+type Header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Tables    []string  `json:"tables"`
+}
+
+// Record is one archived row.
+type Record struct {
+	Table string          `json:"table"`
+	Row   json.RawMessage `json:"row"`
+}
+
+type userRow struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Age       int32     `json:"age"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// MergedInto was added in version 2.
+	MergedInto string `json:"merged_into,omitempty"`
+	// CreatedBy and UpdatedBy were added in version 3.
+	CreatedBy string `json:"created_by,omitempty"`
+	UpdatedBy string `json:"updated_by,omitempty"`
+	// TenantID was added in version 4; older archives restore into the
+	// shared tenant.
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+var userColumns = []string{"id", "name", "email", "age", "status", "created_at", "updated_at", "merged_into", "created_by", "updated_by", "tenant_id"}
+
+// Stats summarizes a backup or restore.
+type Stats struct {
+	Rows map[string]int
+}
+
+// Backup streams every table to w, with the rows of every tenant. All tables
+// are read in one repeatable-read transaction, so the archive is a consistent
+// snapshot even while the service keeps serving writes.
+func Backup(ctx context.Context, pool *pgxpool.Pool, w io.Writer) (Stats, error) {
+	stats := Stats{Rows: make(map[string]int)}
+
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return stats, fmt.Errorf("failed to begin snapshot transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	if err := database.SetTenant(tenant.All(ctx), tx); err != nil {
+		return stats, fmt.Errorf("failed to select every tenant: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	enc := json.NewEncoder(gz)
+
+	header := Header{Format: Format, Version: Version, CreatedAt: time.Now().UTC(), Tables: []string{"users"}}
+	if err := enc.Encode(header); err != nil {
+		return stats, err
+	}
+
+	rows, err := tx.Query(ctx, "SELECT id, name, email, age, status, created_at, updated_at, merged_into, created_by, updated_by, tenant_id FROM users ORDER BY created_at, id")
+	if err != nil {
+		return stats, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id, mergedInto       pgtype.UUID
+			row                  userRow
+			createdAt, updatedAt pgtype.Timestamptz
+		)
+		if err := rows.Scan(&id, &row.Name, &row.Email, &row.Age, &row.Status, &createdAt, &updatedAt, &mergedInto, &row.CreatedBy, &row.UpdatedBy, &row.TenantID); err != nil {
+			return stats, fmt.Errorf("failed to scan user: %w", err)
+		}
+		row.ID = uuid.UUID(id.Bytes).String()
+		if mergedInto.Valid {
+			row.MergedInto = uuid.UUID(mergedInto.Bytes).String()
+		}
+		row.CreatedAt = createdAt.Time.UTC()
+		row.UpdatedAt = updatedAt.Time.UTC()
+
+		raw, err := json.Marshal(row)
+		if err != nil {
+			return stats, err
+		}
+		if err := enc.Encode(Record{Table: "users", Row: raw}); err != nil {
+			return stats, err
+		}
+		stats.Rows["users"]++
+	}
+	if err := rows.Err(); err != nil {
+		return stats, fmt.Errorf("failed to read users: %w", err)
+	}
+
+	if err := gz.Close(); err != nil {
+		return stats, err
+	}
+	return stats, tx.Commit(ctx)
+}
+
+// Restore loads an archive written by Backup in a single transaction. Unless
+// truncate is set, it refuses to load into tables that already hold data.
+func Restore(ctx context.Context, pool *pgxpool.Pool, r io.Reader, truncate bool) (Stats, error) {
+	stats := Stats{Rows: make(map[string]int)}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return stats, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer gz.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(gz))
+	var header Header
+	if err := dec.Decode(&header); err != nil {
+		return stats, fmt.Errorf("failed to read archive header: %w", err)
+	}
+	if header.Format != Format || header.Version < MinVersion || header.Version > Version {
+		return stats, fmt.Errorf("unsupported archive %s v%d", header.Format, header.Version)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("failed to begin restore transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	if err := database.SetTenant(tenant.All(ctx), tx); err != nil {
+		return stats, fmt.Errorf("failed to select every tenant: %w", err)
+	}
+
+	if truncate {
+		// TRUNCATE skips row triggers, so the email index is emptied with it.
+		if _, err := tx.Exec(ctx, "TRUNCATE users, user_emails"); err != nil {
+			return stats, fmt.Errorf("failed to truncate users: %w", err)
+		}
+	} else {
+		var exists bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users)").Scan(&exists); err != nil {
+			return stats, fmt.Errorf("failed to check users: %w", err)
+		}
+		if exists {
+			return stats, ErrNotEmpty
+		}
+	}
+
+	var decodeErr error
+	source := pgx.CopyFromFunc(func() ([]any, error) {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			if err == io.EOF {
+				return nil, nil
+			}
+			decodeErr = err
+			return nil, err
+		}
+		if rec.Table != "users" {
+			return nil, fmt.Errorf("unknown table %q in archive", rec.Table)
+		}
+		var row userRow
+		if err := json.Unmarshal(rec.Row, &row); err != nil {
+			return nil, err
+		}
+		id, err := uuid.Parse(row.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user id %q: %w", row.ID, err)
+		}
+		var mergedInto pgtype.UUID
+		if row.MergedInto != "" {
+			target, err := uuid.Parse(row.MergedInto)
+			if err != nil {
+				return nil, fmt.Errorf("invalid merged_into %q for user %s: %w", row.MergedInto, row.ID, err)
+			}
+			mergedInto = pgtype.UUID{Bytes: target, Valid: true}
+		}
+		stats.Rows["users"]++
+		return []any{
+			pgtype.UUID{Bytes: id, Valid: true},
+			row.Name, row.Email, row.Age, row.Status,
+			row.CreatedAt, row.UpdatedAt, mergedInto,
+			row.CreatedBy, row.UpdatedBy, row.TenantID,
+		}, nil
+	})
+
+	// COPY FROM refuses tables with row-level security, even for a
+	// transaction that sees every tenant. Turning it off is undone with the
+	// transaction if the restore fails.
+	if _, err := tx.Exec(ctx, "ALTER TABLE users DISABLE ROW LEVEL SECURITY"); err != nil {
+		return stats, fmt.Errorf("failed to disable row-level security: %w", err)
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"users"}, userColumns, source); err != nil {
+		if decodeErr != nil {
+			return stats, fmt.Errorf("failed to read archive: %w", decodeErr)
+		}
+		return stats, fmt.Errorf("failed to load users: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "ALTER TABLE users ENABLE ROW LEVEL SECURITY"); err != nil {
+		return stats, fmt.Errorf("failed to re-enable row-level security: %w", err)
+	}
+	return stats, tx.Commit(ctx)
+}
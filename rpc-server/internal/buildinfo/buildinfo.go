@@ -0,0 +1,62 @@
+// Package buildinfo describes the running binary. Release builds set the
+// variables below with -ldflags; otherwise they are filled in from the VCS
+// information the Go toolchain embeds when building inside a checkout.
+package buildinfo
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// Set with -ldflags "-X grpc-server/internal/buildinfo.version=...".
+var (
+	version string
+	commit  string
+	date    string
+)
+
+// Info identifies a build.
+type Info struct {
+	Version   string
+	Commit    string
+	Date      string // RFC 3339
+	GoVersion string
+	// Modified is true when the checkout had uncommitted changes.
+	Modified bool
+}
+
+var get = sync.OnceValue(func() Info {
+	info := Info{Version: version, Commit: commit, Date: date}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = bi.GoVersion
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+		if info.Version == "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+	}
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	return info
+})
+
+// Get returns the build info of the running binary.
+func Get() Info {
+	return get()
+}
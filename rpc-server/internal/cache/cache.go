@@ -2,10 +2,14 @@ package cache
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
+	"os"
 	"strings"
 	"time"
 
@@ -13,8 +17,14 @@ import (
 	"grpc-server/internal/logging"
 
 	"github.com/valkey-io/valkey-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
+// scanBatchSize is the COUNT hint of each SCAN in DeletePattern.
+const scanBatchSize = 500
+
 // Common cache errors
 var (
 	ErrCacheMiss = errors.New("cache miss")
@@ -22,8 +32,19 @@ var (
 
 type Cache interface {
 	Get(ctx context.Context, key string) ([]byte, error)
+	// MGet gets keys in a single command. It returns one value per key, nil
+	// for keys that missed.
+	MGet(ctx context.Context, keys ...string) ([][]byte, error)
 	Set(ctx context.Context, key string, value any, expiration time.Duration) error
-	Delete(ctx context.Context, key string) error
+	// Delete removes keys in a single command, so either all or none are
+	// deleted; in a Valkey Cluster, that only holds for keys sharing a slot.
+	Delete(ctx context.Context, keys ...string) error
+	// DeletePattern deletes every key matching pattern, a glob as in
+	// Valkey's SCAN MATCH, and returns how many it deleted. It is not atomic:
+	// keys written while it runs may survive.
+	DeletePattern(ctx context.Context, pattern string) (int, error)
+	// Expire resets the time to live of key; a missing key is not an error.
+	Expire(ctx context.Context, key string, expiration time.Duration) error
 	Close() error
 }
 
@@ -31,30 +52,120 @@ type Cache interface {
 type ValkeyCache struct {
 	client valkey.Client
 	logger *logging.Logger
+	// maxValueBytes bounds what Set writes; 0 means no limit.
+	maxValueBytes int
+	oversized     metric.Int64Counter
+	valueSize     metric.Int64Histogram
 }
 
 func NewValkeyCache(cfg *config.CacheConfig, base *slog.Logger) (*ValkeyCache, error) {
-	const prefix = "valkey://"
-	address, ok := strings.CutPrefix(cfg.URL, prefix)
-	if !ok || address == "" {
-		return nil, errors.New("invalid cache URL: must start with valkey:// and include host:port")
+	option, err := clientOption(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	base.Info("Creating Valkey client", "address", address)
+	base.Info("Creating Valkey client", "addresses", option.InitAddress, "mode", cfg.Mode, "tls", option.TLSConfig != nil)
 
-	client, err := valkey.NewClient(valkey.ClientOption{
-		InitAddress: []string{address},
-	})
+	client, err := valkey.NewClient(option)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create valkey client: %w", err)
 	}
+	if cfg.Mode == modeCluster && client.Mode() != valkey.ClientModeCluster {
+		client.Close()
+		return nil, fmt.Errorf("cache mode is %s, but %s is not a Valkey Cluster", modeCluster, option.InitAddress[0])
+	}
+	base.Info("Valkey client created", "mode", string(client.Mode()))
+
+	meter := otel.Meter("rpc-server.rpc/cache")
+	oversized, _ := meter.Int64Counter("cache.values.oversized",
+		metric.WithDescription("Number of cache writes skipped because the value exceeded the size limit"),
+		metric.WithUnit("{value}"),
+	)
+	// Powers of four from 64 bytes past the default 1MB value limit, so the
+	// limit can be set from where the distribution ends.
+	valueSize, _ := meter.Int64Histogram("cache.value.size",
+		metric.WithDescription("Encoded size of values written with Set, including those skipped as oversized"),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304),
+	)
 
 	return &ValkeyCache{
-		client: client,
-		logger: logging.New(base),
+		client:        client,
+		logger:        logging.New(logging.ForModule(base, logging.ModuleCache)),
+		maxValueBytes: cfg.MaxValueBytes,
+		oversized:     oversized,
+		valueSize:     valueSize,
 	}, nil
 }
 
+// Cache modes; see config.CacheConfig.Mode.
+const (
+	modeAuto       = "auto"
+	modeStandalone = "standalone"
+	modeCluster    = "cluster"
+)
+
+// clientOption builds the Valkey client options from cfg. The URL is
+// valkey://host:port, with further comma-separated host:port seed nodes for
+// a cluster. Credentials go in Username and Password rather than the URL, so
+// they can come from a secret while the URL sits in plain configuration.
+func clientOption(cfg *config.CacheConfig) (valkey.ClientOption, error) {
+	const prefix = "valkey://"
+	hosts, ok := strings.CutPrefix(cfg.URL, prefix)
+	if !ok || hosts == "" {
+		return valkey.ClientOption{}, errors.New("invalid cache URL: must start with valkey:// and include host:port")
+	}
+	if strings.Contains(hosts, "@") {
+		return valkey.ClientOption{}, errors.New("invalid cache URL: set credentials with CACHE_USERNAME and CACHE_PASSWORD instead")
+	}
+	var addresses []string
+	for address := range strings.SplitSeq(hosts, ",") {
+		address = strings.TrimSpace(address)
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return valkey.ClientOption{}, fmt.Errorf("invalid cache address %q: %w", address, err)
+		}
+		addresses = append(addresses, address)
+	}
+
+	option := valkey.ClientOption{
+		InitAddress: addresses,
+		Username:    cfg.Username,
+		Password:    cfg.Password,
+	}
+	switch cfg.Mode {
+	case "", modeAuto:
+	case modeStandalone:
+		if len(addresses) > 1 {
+			return valkey.ClientOption{}, fmt.Errorf("cache mode %s takes one address, got %d", modeStandalone, len(addresses))
+		}
+		option.ForceSingleClient = true
+	case modeCluster:
+		// Spread topology discovery over the seed nodes instead of always
+		// asking the first
+		option.ShuffleInit = true
+	default:
+		return valkey.ClientOption{}, fmt.Errorf("unknown cache mode %q: want %s, %s or %s", cfg.Mode, modeAuto, modeStandalone, modeCluster)
+	}
+
+	if cfg.TLSEnabled {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if cfg.TLSCAFile != "" {
+			pem, err := os.ReadFile(cfg.TLSCAFile)
+			if err != nil {
+				return valkey.ClientOption{}, fmt.Errorf("failed to read cache CA file: %w", err)
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+				return valkey.ClientOption{}, fmt.Errorf("cache CA file %s holds no PEM certificates", cfg.TLSCAFile)
+			}
+		}
+		// The server name is left empty so each node is verified against
+		// the host it is dialed at, which for a cluster varies per node
+		option.TLSConfig = tlsConfig
+	}
+	return option, nil
+}
+
 func (c *ValkeyCache) Get(ctx context.Context, key string) ([]byte, error) {
 	c.logger.DebugCtx(ctx, "Attempting cache get", "key", key)
 
@@ -78,27 +189,55 @@ func (c *ValkeyCache) Get(ctx context.Context, key string) ([]byte, error) {
 	return data, nil
 }
 
-func (c *ValkeyCache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
-	c.logger.DebugCtx(ctx, "Attempting cache set", "key", key, "expiration", expiration)
+func (c *ValkeyCache) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	c.logger.DebugCtx(ctx, "Attempting cache mget", "keys", len(keys))
 
-	var data []byte
-	var err error
+	// One MGET, or in a cluster one per slot, pipelined per node
+	messages, err := valkey.MGet(c.client, ctx, keys)
+	if err != nil {
+		c.logger.Error("Cache mget operation failed", "keys", len(keys), "error", err)
+		return nil, fmt.Errorf("cache mget failed: %w", err)
+	}
 
-	switch v := value.(type) {
-	case []byte:
-		data = v
-	case string:
-		data = []byte(v)
-	default:
-		data, err = json.Marshal(value)
+	values := make([][]byte, len(keys))
+	hits := 0
+	for i, key := range keys {
+		message, ok := messages[key]
+		if !ok || message.IsNil() {
+			continue
+		}
+		data, err := message.AsBytes()
 		if err != nil {
-			c.logger.Error("Failed to marshal value for cache", "key", key, "error", err)
-			return fmt.Errorf("failed to marshal value: %w", err)
+			c.logger.Error("Failed to convert cache result to bytes", "key", key, "error", err)
+			return nil, fmt.Errorf("failed to convert result: %w", err)
 		}
+		values[i] = data
+		hits++
+	}
+
+	c.logger.DebugCtx(ctx, "Cache mget completed", "keys", len(keys), "hits", hits)
+	return values, nil
+}
+
+func (c *ValkeyCache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	c.logger.DebugCtx(ctx, "Attempting cache set", "key", key, "expiration", expiration)
+
+	data, err := encodeValue(value)
+	if err != nil {
+		c.logger.Error("Failed to marshal value for cache", "key", key, "error", err)
+		return err
+	}
+
+	c.valueSize.Record(ctx, int64(len(data)), metric.WithAttributes(attribute.String("cache.namespace", Namespace(key))))
+	if c.maxValueBytes > 0 && len(data) > c.maxValueBytes {
+		return c.skipOversized(ctx, key, len(data))
 	}
 
 	if expiration <= 0 {
-		expiration = time.Hour
+		expiration = defaultExpiration
 		c.logger.Warn("No expiration provided, using default", "key", key, "default_expiration", expiration)
 	}
 
@@ -112,26 +251,156 @@ func (c *ValkeyCache) Set(ctx context.Context, key string, value any, expiration
 	return nil
 }
 
-func (c *ValkeyCache) Delete(ctx context.Context, key string) error {
-	c.logger.DebugCtx(ctx, "Attempting cache delete", "key", key)
+// SetNX sets key only if it doesn't exist yet, and reports whether it did.
+// Oversized values are not checked; it is meant for small markers.
+func (c *ValkeyCache) SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	data, err := encodeValue(value)
+	if err != nil {
+		return false, err
+	}
+	err = c.client.Do(ctx, c.client.B().Set().Key(key).Value(string(data)).Nx().PxMilliseconds(expiration.Milliseconds()).Build()).Error()
+	if valkey.IsValkeyNil(err) {
+		return false, nil
+	}
+	if err != nil {
+		c.logger.Error("Cache setnx operation failed", "key", key, "error", err)
+		return false, fmt.Errorf("cache setnx failed: %w", err)
+	}
+	return true, nil
+}
+
+// defaultExpiration applies to Set calls without an expiration.
+const defaultExpiration = time.Hour
+
+// encodeValue converts a Set value to the bytes stored: []byte and string as
+// they are, anything else as JSON.
+func encodeValue(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value: %w", err)
+	}
+	return data, nil
+}
+
+// skipOversized drops a write that exceeds maxValueBytes. Any value already
+// cached under key is deleted, since it is older than the one being skipped.
+func (c *ValkeyCache) skipOversized(ctx context.Context, key string, size int) error {
+	c.oversized.Add(ctx, 1, metric.WithAttributes(attribute.String("cache.namespace", Namespace(key))))
+	c.logger.WarnCtx(ctx, "Skipping oversized cache value", "key", key, "value_size", size, "max_value_size", c.maxValueBytes)
+
+	if err := c.client.Do(ctx, c.client.B().Del().Key(key).Build()).Error(); err != nil {
+		return fmt.Errorf("cache delete of stale value failed: %w", err)
+	}
+	return nil
+}
+
+func (c *ValkeyCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	c.logger.DebugCtx(ctx, "Attempting cache delete", "keys", keys)
 
-	result := c.client.Do(ctx, c.client.B().Del().Key(key).Build())
-	if err := result.Error(); err != nil {
-		c.logger.Error("Cache delete operation failed", "key", key, "error", err)
+	deletedCount, err := c.removeKeys(ctx, keys, func(keys ...string) valkey.Completed {
+		return c.client.B().Del().Key(keys...).Build()
+	})
+	if err != nil {
+		c.logger.Error("Cache delete operation failed", "keys", keys, "error", err)
 		return fmt.Errorf("cache delete failed: %w", err)
 	}
 
-	// Check how many keys were deleted
-	deletedCount, err := result.AsInt64()
+	c.logger.DebugCtx(ctx, "Cache delete completed", "keys", keys, "deleted_count", deletedCount)
+	return nil
+}
+
+// DeletePattern finds keys with SCAN, so Valkey is never blocked the way
+// KEYS would block it, and removes each batch with UNLINK, which frees the
+// values in the background. In a cluster, every primary is scanned.
+func (c *ValkeyCache) DeletePattern(ctx context.Context, pattern string) (int, error) {
+	nodes, err := c.primaries(ctx)
 	if err != nil {
-		c.logger.Error("Failed to get delete result", "key", key, "error", err)
-		return fmt.Errorf("failed to get delete result: %w", err)
+		return 0, err
+	}
+	unlink := func(keys ...string) valkey.Completed {
+		return c.client.B().Unlink().Key(keys...).Build()
+	}
+	deleted := 0
+	for _, node := range nodes {
+		var cursor uint64
+		for {
+			entry, err := node.Do(ctx, node.B().Scan().Cursor(cursor).Match(pattern).Count(scanBatchSize).Build()).AsScanEntry()
+			if err != nil {
+				return deleted, fmt.Errorf("cache scan failed: %w", err)
+			}
+			if len(entry.Elements) > 0 {
+				n, err := c.removeKeys(ctx, entry.Elements, unlink)
+				deleted += int(n)
+				if err != nil {
+					return deleted, fmt.Errorf("cache unlink failed: %w", err)
+				}
+			}
+			if cursor = entry.Cursor; cursor == 0 {
+				break
+			}
+		}
 	}
+	c.logger.DebugCtx(ctx, "Cache pattern delete completed", "pattern", pattern, "deleted_count", deleted)
+	return deleted, nil
+}
+
+func (c *ValkeyCache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	c.logger.DebugCtx(ctx, "Attempting cache expire", "key", key, "expiration", expiration)
 
-	c.logger.DebugCtx(ctx, "Cache delete completed", "key", key, "deleted_count", deletedCount)
+	result := c.client.Do(ctx, c.client.B().Expire().Key(key).Seconds(int64(expiration.Seconds())).Build())
+	if err := result.Error(); err != nil {
+		c.logger.Error("Cache expire operation failed", "key", key, "error", err)
+		return fmt.Errorf("cache expire failed: %w", err)
+	}
 	return nil
 }
 
+// Ping checks that Valkey answers.
+func (c *ValkeyCache) Ping(ctx context.Context) error {
+	if err := c.client.Do(ctx, c.client.B().Ping().Build()).Error(); err != nil {
+		return fmt.Errorf("cache ping failed: %w", err)
+	}
+	return nil
+}
+
+// ServerVersion returns the Valkey server's version from INFO. Servers that
+// predate the valkey_version field report it as redis_version.
+func (c *ValkeyCache) ServerVersion(ctx context.Context) (string, error) {
+	info, err := c.client.Do(ctx, c.client.B().Info().Section("server").Build()).ToString()
+	if err != nil {
+		return "", fmt.Errorf("cache info failed: %w", err)
+	}
+	var version string
+	for line := range strings.SplitSeq(info, "\n") {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), ":")
+		switch key {
+		case "valkey_version":
+			return value, nil
+		case "redis_version":
+			version = value
+		}
+	}
+	if version == "" {
+		return "", errors.New("cache info has no server version")
+	}
+	return version, nil
+}
+
+// BlockingPoolSize returns the size of the client's pool for blocking
+// commands; other commands share pipelined connections.
+func (c *ValkeyCache) BlockingPoolSize() int {
+	return valkey.DefaultPoolSize
+}
+
 func (c *ValkeyCache) Close() error {
 	c.client.Close()
 	c.logger.Info("Valkey cache connection closed")
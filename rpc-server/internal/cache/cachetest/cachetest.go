@@ -0,0 +1,200 @@
+// Package cachetest provides an in-memory cache.Cache for tests. It behaves
+// like Valkey for everything the server relies on, including TTLs, but time
+// only moves when the test advances its clock, so expiry and invalidation can
+// be asserted deterministically.
+package cachetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"grpc-server/internal/cache"
+	"grpc-server/internal/clock"
+)
+
+// DefaultExpiration applies to Set calls without an expiration, as in
+// cache.ValkeyCache.
+const DefaultExpiration = time.Hour
+
+// Cache is an unbounded in-memory cache.Cache whose entries expire according
+// to its clock. It is safe for concurrent use.
+type Cache struct {
+	clock clock.Clock
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+var _ cache.Cache = (*Cache)(nil)
+
+// New returns an empty Cache reading time from clk, typically a *clock.Fake
+// shared with the code under test.
+func New(clk clock.Clock) *Cache {
+	return &Cache{clock: clk, entries: make(map[string]entry)}
+}
+
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.live(key)
+	if !ok {
+		return nil, cache.ErrCacheMiss
+	}
+	return slices.Clone(e.value), nil
+}
+
+func (c *Cache) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		if e, ok := c.live(key); ok {
+			values[i] = slices.Clone(e.value)
+		}
+	}
+	return values, nil
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	data, err := encode(value)
+	if err != nil {
+		return err
+	}
+	if expiration <= 0 {
+		expiration = DefaultExpiration
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry{value: data, expiresAt: c.clock.Now().Add(expiration)}
+	return nil
+}
+
+// SetNX sets key only if it holds no live entry, and reports whether it did,
+// as cache.ValkeyCache.SetNX does.
+func (c *Cache) SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	data, err := encode(value)
+	if err != nil {
+		return false, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.live(key); ok {
+		return false, nil
+	}
+	c.entries[key] = entry{value: data, expiresAt: c.clock.Now().Add(expiration)}
+	return true, nil
+}
+
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+func (c *Cache) DeletePattern(ctx context.Context, pattern string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	deleted := 0
+	for key := range c.entries {
+		if _, ok := c.live(key); ok && cache.MatchPattern(pattern, key) {
+			delete(c.entries, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (c *Cache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.live(key)
+	if !ok {
+		return nil
+	}
+	e.expiresAt = c.clock.Now().Add(expiration)
+	c.entries[key] = e
+	return nil
+}
+
+func (c *Cache) Close() error {
+	return nil
+}
+
+// Keys returns the keys of live entries, sorted.
+func (c *Cache) Keys() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		c.live(key)
+	}
+	return slices.Sorted(maps.Keys(c.entries))
+}
+
+// Has reports whether key holds a live entry.
+func (c *Cache) Has(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.live(key)
+	return ok
+}
+
+// TTL returns the remaining time to live of key, or false if it holds no
+// live entry.
+func (c *Cache) TTL(key string) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.live(key)
+	if !ok {
+		return 0, false
+	}
+	return e.expiresAt.Sub(c.clock.Now()), true
+}
+
+// Flush removes every entry.
+func (c *Cache) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// live returns the entry under key, dropping it if it has expired. It must
+// be called with c.mu held.
+func (c *Cache) live(key string) (entry, bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return entry{}, false
+	}
+	if !c.clock.Now().Before(e.expiresAt) {
+		delete(c.entries, key)
+		return entry{}, false
+	}
+	return e, true
+}
+
+// encode stores values the way cache.ValkeyCache does.
+func encode(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return slices.Clone(v), nil
+	case string:
+		return []byte(v), nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// isCluster reports whether the client routes keys over Valkey Cluster
+// slots.
+func (c *ValkeyCache) isCluster() bool {
+	return c.client.Mode() == valkey.ClientModeCluster
+}
+
+// primaries returns a client for each node holding keys: the one server, or
+// every cluster primary. Commands without a key, such as SCAN, DBSIZE and
+// FLUSHDB, only cover the node they reach, so they are sent to each.
+func (c *ValkeyCache) primaries(ctx context.Context) ([]valkey.Client, error) {
+	if !c.isCluster() {
+		return []valkey.Client{c.client}, nil
+	}
+	var primaries []valkey.Client
+	for address, node := range c.client.Nodes() {
+		role, err := node.Do(ctx, node.B().Role().Build()).ToArray()
+		if err != nil {
+			return nil, fmt.Errorf("cache role of %s failed: %w", address, err)
+		}
+		if len(role) > 0 {
+			if name, _ := role[0].ToString(); name == "master" {
+				primaries = append(primaries, node)
+			}
+		}
+	}
+	if len(primaries) == 0 {
+		return nil, errors.New("cache cluster has no primary")
+	}
+	return primaries, nil
+}
+
+// removeKeys runs command, DEL or UNLINK, over keys and returns how many it
+// removed. A cluster rejects commands whose keys span slots, so there each
+// key gets its own command; they are still sent in one round trip per node.
+func (c *ValkeyCache) removeKeys(ctx context.Context, keys []string, command func(keys ...string) valkey.Completed) (int64, error) {
+	if !c.isCluster() {
+		return c.client.Do(ctx, command(keys...)).AsInt64()
+	}
+	commands := make(valkey.Commands, len(keys))
+	for i, key := range keys {
+		commands[i] = command(key)
+	}
+	var removed int64
+	for _, result := range c.client.DoMulti(ctx, commands...) {
+		n, err := result.AsInt64()
+		if err != nil {
+			return removed, err
+		}
+		removed += n
+	}
+	return removed, nil
+}
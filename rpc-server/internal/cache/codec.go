@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// protoCodecPrefix marks values written by MarshalProto. Change the version
+// whenever the encoding changes, so replicas can tell values apart during a
+// rolling deploy.
+const protoCodecPrefix = "pb1:"
+
+// ErrUnknownEncoding is returned for cached values in an encoding this
+// release does not know, such as one written by a newer release.
+var ErrUnknownEncoding = errors.New("unknown cache value encoding")
+
+// MarshalProto encodes m for caching. Unlike encoding/json it preserves
+// protobuf semantics, including fields added after the value was cached.
+func MarshalProto(m proto.Message) ([]byte, error) {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", m.ProtoReflect().Descriptor().FullName(), err)
+	}
+	return append([]byte(protoCodecPrefix), data...), nil
+}
+
+// UnmarshalProto decodes a value written by MarshalProto into m. JSON values
+// cached by releases before MarshalProto existed are still decoded, so they
+// are served until they expire rather than all missing at once.
+func UnmarshalProto(data []byte, m proto.Message) error {
+	if payload, ok := bytes.CutPrefix(data, []byte(protoCodecPrefix)); ok {
+		return proto.Unmarshal(payload, m)
+	}
+	if len(data) > 0 && data[0] == '{' {
+		return json.Unmarshal(data, m)
+	}
+	return ErrUnknownEncoding
+}
@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"grpc-server/internal/logging"
+)
+
+// ConnState is the state of the connection to Valkey as seen by a
+// ConnectionMonitor.
+type ConnState int
+
+const (
+	// ConnConnected means the last check succeeded.
+	ConnConnected ConnState = iota
+	// ConnDisconnected means the first check after a success failed.
+	ConnDisconnected
+	// ConnReconnecting means later checks keep failing and are retried
+	// with backoff.
+	ConnReconnecting
+)
+
+var connStateNames = map[ConnState]string{
+	ConnConnected:    "connected",
+	ConnDisconnected: "disconnected",
+	ConnReconnecting: "reconnecting",
+}
+
+func (s ConnState) String() string {
+	return connStateNames[s]
+}
+
+// ConnectionConfig tunes a ConnectionMonitor.
+type ConnectionConfig struct {
+	// Interval is the time between checks while connected.
+	Interval time.Duration
+	// MinBackoff is the wait before the first retry after a failed check;
+	// it doubles with every failure up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Timeout bounds one check.
+	Timeout time.Duration
+}
+
+// ConnectionMonitor checks the connection to Valkey and reports its state
+// transitions. valkey-go redials on its own; the monitor notices when it
+// has, so listeners can recover what a dropped connection lost, such as
+// pub/sub subscriptions, without waiting for the next failing request.
+type ConnectionMonitor struct {
+	ping   func(context.Context) error
+	cfg    ConnectionConfig
+	logger *logging.Logger
+
+	transitions metric.Int64Counter
+
+	mu        sync.Mutex
+	state     ConnState
+	since     time.Time
+	listeners []func(from, to ConnState)
+}
+
+// NewConnectionMonitor creates a monitor for the connection ping checks,
+// such as (*ValkeyCache).Ping. It starts out connected, since the server
+// checks Valkey before it starts serving.
+func NewConnectionMonitor(ping func(context.Context) error, cfg ConnectionConfig, base *slog.Logger) *ConnectionMonitor {
+	meter := otel.Meter("rpc-server.rpc/cache")
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	transitions, _ := meter.Int64Counter("cache.connection.transitions",
+		metric.WithDescription("Number of times the Valkey connection changed state"),
+		metric.WithUnit("{transition}"),
+	)
+	m := &ConnectionMonitor{
+		ping:        ping,
+		cfg:         cfg,
+		logger:      logging.New(logging.ForModule(base, logging.ModuleCache).With("component", "connection_monitor")),
+		transitions: transitions,
+		since:       time.Now(),
+	}
+
+	state, _ := meter.Int64ObservableGauge("cache.connection.state",
+		metric.WithDescription("1 for the current state of the Valkey connection, 0 for the others"),
+		metric.WithUnit("{state}"),
+	)
+	_, _ = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		current := m.State()
+		for s, name := range connStateNames {
+			var v int64
+			if s == current {
+				v = 1
+			}
+			o.ObserveInt64(state, v, metric.WithAttributes(attribute.String("state", name)))
+		}
+		return nil
+	}, state)
+	return m
+}
+
+// OnStateChange registers fn to be called, in Run's goroutine, on every
+// state transition. Register listeners before calling Run.
+func (m *ConnectionMonitor) OnStateChange(fn func(from, to ConnState)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, fn)
+}
+
+// State returns the current state.
+func (m *ConnectionMonitor) State() ConnState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+// Run checks the connection until ctx is done: every Interval while
+// connected, and with jittered exponential backoff while not.
+func (m *ConnectionMonitor) Run(ctx context.Context) {
+	backoff := m.cfg.MinBackoff
+	timer := time.NewTimer(m.cfg.Interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+		err := m.ping(checkCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil {
+			m.transition(ctx, ConnConnected, nil)
+			backoff = m.cfg.MinBackoff
+			timer.Reset(m.cfg.Interval)
+			continue
+		}
+		if m.State() == ConnConnected {
+			m.transition(ctx, ConnDisconnected, err)
+		} else {
+			m.transition(ctx, ConnReconnecting, err)
+		}
+		// Full jitter keeps replicas from redialing in lockstep.
+		timer.Reset(time.Duration(rand.Int64N(int64(backoff)) + 1))
+		backoff = min(2*backoff, m.cfg.MaxBackoff)
+	}
+}
+
+func (m *ConnectionMonitor) transition(ctx context.Context, to ConnState, err error) {
+	m.mu.Lock()
+	from, since := m.state, m.since
+	if from == to {
+		m.mu.Unlock()
+		return
+	}
+	m.state, m.since = to, time.Now()
+	listeners := m.listeners
+	m.mu.Unlock()
+
+	m.transitions.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("from", from.String()),
+		attribute.String("to", to.String()),
+	))
+	switch to {
+	case ConnConnected:
+		m.logger.InfoCtx(ctx, "Valkey connection restored", "from", from, "down_for", time.Since(since))
+	case ConnDisconnected:
+		m.logger.ErrorCtx(ctx, "Valkey connection lost", logging.Error, err)
+	}
+	for _, fn := range listeners {
+		fn(from, to)
+	}
+}
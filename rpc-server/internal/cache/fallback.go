@@ -0,0 +1,390 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"grpc-server/internal/logging"
+)
+
+// probeKey is read to check that the primary cache answers again.
+const probeKey = "cache:fallback:probe"
+
+// resyncBatchSize bounds the keys deleted from the primary per command.
+const resyncBatchSize = 500
+
+// FallbackConfig tunes FallbackCache's circuit breaker.
+type FallbackConfig struct {
+	// FailureThreshold consecutive primary errors open the breaker.
+	FailureThreshold int
+	// Cooldown is how long the breaker stays open before the primary is
+	// probed and resynchronized.
+	Cooldown time.Duration
+	// MaxDirtyKeys bounds the keys remembered for resynchronization; beyond
+	// it every key matching KeyPatterns is deleted on recovery instead.
+	MaxDirtyKeys int
+	// KeyPatterns match every key written through the cache, such as
+	// server.CacheKeyPatterns, so recovering from an overflow leaves the
+	// rest of a shared Valkey alone.
+	KeyPatterns []string
+	// ResyncTimeout bounds one resynchronization attempt.
+	ResyncTimeout time.Duration
+}
+
+// FallbackCache serves from a primary cache and, when the primary keeps
+// failing, switches to an in-memory fallback so an outage degrades hit rates
+// gradually instead of sending every read to the database at once.
+//
+// While the breaker is open, every key written or deleted is remembered as
+// dirty, because the primary missed the change. After Cooldown the dirty keys
+// are deleted from the primary; once that succeeds the fallback is flushed
+// and the primary resumes. Until then the fallback keeps serving.
+//
+// Keys whose writes fail while the breaker is still closed are dirty too, and
+// are deleted from the primary as soon as it answers again.
+type FallbackCache struct {
+	primary  Cache
+	fallback *MemoryCache
+	cfg      FallbackConfig
+	logger   *logging.Logger
+
+	transitions metric.Int64Counter
+
+	mu        sync.Mutex
+	open      bool
+	failures  int // consecutive primary errors
+	retryAt   time.Time
+	resyncing bool // a resync or cleanup is running
+	dirty     map[string]struct{}
+	// dirtyPatterns were deleted with DeletePattern while the primary missed
+	// it; they are deleted from the primary again on recovery.
+	dirtyPatterns map[string]struct{}
+	// overflowed means dirty lost keys, so only deleting every key matching
+	// cfg.KeyPatterns makes the primary trustworthy again.
+	overflowed bool
+}
+
+var _ Cache = (*FallbackCache)(nil)
+
+func NewFallbackCache(primary Cache, fallback *MemoryCache, cfg FallbackConfig, logger *slog.Logger) *FallbackCache {
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	transitions, _ := otel.Meter("rpc-server.rpc/cache").Int64Counter("cache.breaker.transitions",
+		metric.WithDescription("Number of times the cache switched between Valkey and the in-memory fallback"),
+		metric.WithUnit("{transition}"),
+	)
+	return &FallbackCache{
+		primary:       primary,
+		fallback:      fallback,
+		cfg:           cfg,
+		logger:        logging.New(logging.ForModule(logger, logging.ModuleCache)),
+		transitions:   transitions,
+		dirty:         make(map[string]struct{}),
+		dirtyPatterns: make(map[string]struct{}),
+	}
+}
+
+// Degraded reports whether the fallback is serving instead of the primary.
+func (c *FallbackCache) Degraded() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.open
+}
+
+// Reconnected tells c the primary answers again, e.g. from a
+// ConnectionMonitor, so an open breaker resynchronizes now instead of at the
+// end of its cooldown.
+func (c *FallbackCache) Reconnected() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.open || c.resyncing {
+		return
+	}
+	c.retryAt = time.Now()
+	c.resyncing = true
+	go c.resync()
+}
+
+// InvalidateLocal removes keys, and the keys matching patterns, from the
+// fallback only, for changes another instance already made to the primary.
+func (c *FallbackCache) InvalidateLocal(ctx context.Context, keys, patterns []string) {
+	_ = c.fallback.Delete(ctx, keys...)
+	for _, pattern := range patterns {
+		_, _ = c.fallback.DeletePattern(ctx, pattern)
+	}
+}
+
+// FlushLocal drops every fallback entry, for when changes made by other
+// instances may have been missed.
+func (c *FallbackCache) FlushLocal() {
+	c.fallback.Flush()
+}
+
+func (c *FallbackCache) Get(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	var err error
+	if c.whileOpen(nil, func() { data, err = c.fallback.Get(ctx, key) }) {
+		return data, err
+	}
+	data, err = c.primary.Get(ctx, key)
+	c.observe(ctx, err)
+	return data, err
+}
+
+func (c *FallbackCache) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	var values [][]byte
+	var err error
+	if c.whileOpen(nil, func() { values, err = c.fallback.MGet(ctx, keys...) }) {
+		return values, err
+	}
+	values, err = c.primary.MGet(ctx, keys...)
+	c.observe(ctx, err)
+	return values, err
+}
+
+func (c *FallbackCache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	var err error
+	if c.whileOpen([]string{key}, func() { err = c.fallback.Set(ctx, key, value, expiration) }) {
+		return err
+	}
+	err = c.primary.Set(ctx, key, value, expiration)
+	c.observe(ctx, err, key)
+	return err
+}
+
+func (c *FallbackCache) Delete(ctx context.Context, keys ...string) error {
+	var err error
+	if c.whileOpen(keys, func() { err = c.fallback.Delete(ctx, keys...) }) {
+		return err
+	}
+	err = c.primary.Delete(ctx, keys...)
+	c.observe(ctx, err, keys...)
+	return err
+}
+
+func (c *FallbackCache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	var err error
+	if c.whileOpen(nil, func() { err = c.fallback.Expire(ctx, key, expiration) }) {
+		return err
+	}
+	err = c.primary.Expire(ctx, key, expiration)
+	c.observe(ctx, err)
+	return err
+}
+
+func (c *FallbackCache) DeletePattern(ctx context.Context, pattern string) (int, error) {
+	var deleted int
+	var err error
+	if c.whileOpen(nil, func() {
+		c.dirtyPatterns[pattern] = struct{}{}
+		deleted, err = c.fallback.DeletePattern(ctx, pattern)
+	}) {
+		return deleted, err
+	}
+	deleted, err = c.primary.DeletePattern(ctx, pattern)
+	c.observe(ctx, err)
+	if err != nil {
+		c.mu.Lock()
+		c.dirtyPatterns[pattern] = struct{}{}
+		c.mu.Unlock()
+	}
+	return deleted, err
+}
+
+func (c *FallbackCache) Close() error {
+	return c.primary.Close()
+}
+
+// whileOpen runs op against the fallback if the breaker is open, marking
+// keys dirty, and reports whether it did. Holding c.mu across op keeps a
+// concurrent resynchronization from resuming the primary between the state
+// check and the write.
+func (c *FallbackCache) whileOpen(keys []string, op func()) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.open {
+		return false
+	}
+	c.markDirty(keys...)
+	op()
+	if !c.resyncing && !time.Now().Before(c.retryAt) {
+		c.resyncing = true
+		go c.resync()
+	}
+	return true
+}
+
+// observe counts a primary result towards opening the breaker. Misses are
+// answers, and errors from callers giving up are not the primary's fault.
+// keys are those a failed write may have left stale in the primary.
+func (c *FallbackCache) observe(ctx context.Context, err error, keys ...string) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil || errors.Is(err, ErrCacheMiss) {
+		if c.open {
+			return
+		}
+		c.failures = 0
+		if c.hasDirty() && !c.resyncing {
+			c.resyncing = true
+			go c.cleanup()
+		}
+		return
+	}
+	c.failures++
+	c.markDirty(keys...)
+	if c.open || c.failures < c.cfg.FailureThreshold {
+		return
+	}
+	c.open = true
+	c.retryAt = time.Now().Add(c.cfg.Cooldown)
+	c.transitions.Add(ctx, 1, metric.WithAttributes(attribute.String("state", "fallback")))
+	c.logger.ErrorCtx(ctx, "Cache unavailable, switching to in-memory fallback",
+		"consecutive_failures", c.failures, "cooldown", c.cfg.Cooldown, logging.Error, err)
+}
+
+// resync deletes the dirty keys from the primary and resumes it, or leaves
+// the breaker open for another Cooldown if the primary still fails.
+func (c *FallbackCache) resync() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.ResyncTimeout)
+	defer cancel()
+
+	if _, err := c.primary.Get(ctx, probeKey); err != nil && !errors.Is(err, ErrCacheMiss) {
+		c.retry(ctx, nil, nil, false, err)
+		return
+	}
+	for {
+		c.mu.Lock()
+		keys, overflowed := slices.Collect(maps.Keys(c.dirty)), c.overflowed
+		patterns := slices.Collect(maps.Keys(c.dirtyPatterns))
+		if len(keys) == 0 && len(patterns) == 0 && !overflowed {
+			// Nothing changed since the last batch: resume while holding
+			// c.mu so no fallback write can slip in unrecorded.
+			c.open, c.failures, c.resyncing = false, 0, false
+			c.fallback.Flush()
+			c.mu.Unlock()
+			c.transitions.Add(ctx, 1, metric.WithAttributes(attribute.String("state", "primary")))
+			c.logger.InfoCtx(ctx, "Cache available again, resumed after resynchronizing")
+			return
+		}
+		c.resetDirty()
+		c.mu.Unlock()
+
+		if err := c.invalidate(ctx, keys, patterns, overflowed); err != nil {
+			c.retry(ctx, keys, patterns, overflowed, err)
+			return
+		}
+	}
+}
+
+// cleanup deletes from the primary what failed writes may have left stale
+// while the breaker stayed closed, once the primary answers again. Whatever
+// it fails to delete stays dirty for the next attempt.
+func (c *FallbackCache) cleanup() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.ResyncTimeout)
+	defer cancel()
+
+	c.mu.Lock()
+	keys, overflowed := slices.Collect(maps.Keys(c.dirty)), c.overflowed
+	patterns := slices.Collect(maps.Keys(c.dirtyPatterns))
+	c.resetDirty()
+	c.mu.Unlock()
+
+	err := c.invalidate(ctx, keys, patterns, overflowed)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resyncing = false
+	if err != nil {
+		c.markDirty(keys...)
+		for _, pattern := range patterns {
+			c.dirtyPatterns[pattern] = struct{}{}
+		}
+		c.overflowed = c.overflowed || overflowed
+		c.logger.WarnCtx(ctx, "Failed to delete cache entries left stale by failed writes", "dirty_keys", len(c.dirty), logging.Error, err)
+	}
+}
+
+// invalidate removes keys and the keys matching patterns from the primary,
+// or every key matching cfg.KeyPatterns if overflowed.
+func (c *FallbackCache) invalidate(ctx context.Context, keys, patterns []string, overflowed bool) error {
+	if overflowed {
+		if len(c.cfg.KeyPatterns) == 0 {
+			c.logger.ErrorCtx(ctx, "Too many keys changed during the cache outage and no key patterns are configured; entries may be stale until they expire",
+				"max_dirty_keys", c.cfg.MaxDirtyKeys)
+			return nil
+		}
+		c.logger.WarnCtx(ctx, "Too many keys changed during the cache outage, deleting every cached entry",
+			"max_dirty_keys", c.cfg.MaxDirtyKeys, "patterns", c.cfg.KeyPatterns)
+		patterns = c.cfg.KeyPatterns
+	}
+	for batch := range slices.Chunk(keys, resyncBatchSize) {
+		if err := c.primary.Delete(ctx, batch...); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range patterns {
+		if _, err := c.primary.DeletePattern(ctx, pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retry puts keys and patterns back and schedules the next attempt.
+func (c *FallbackCache) retry(ctx context.Context, keys, patterns []string, overflowed bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.markDirty(keys...)
+	for _, pattern := range patterns {
+		c.dirtyPatterns[pattern] = struct{}{}
+	}
+	c.overflowed = c.overflowed || overflowed
+	c.retryAt = time.Now().Add(c.cfg.Cooldown)
+	c.resyncing = false
+	c.logger.WarnCtx(ctx, "Cache still unavailable, staying on in-memory fallback", "dirty_keys", len(c.dirty), logging.Error, err)
+}
+
+// markDirty must be called with c.mu held.
+func (c *FallbackCache) markDirty(keys ...string) {
+	for _, key := range keys {
+		if c.overflowed {
+			return
+		}
+		if _, ok := c.dirty[key]; ok {
+			continue
+		}
+		if len(c.dirty) >= c.cfg.MaxDirtyKeys {
+			c.overflowed = true
+			clear(c.dirty)
+			return
+		}
+		c.dirty[key] = struct{}{}
+	}
+}
+
+// hasDirty must be called with c.mu held.
+func (c *FallbackCache) hasDirty() bool {
+	return len(c.dirty) > 0 || len(c.dirtyPatterns) > 0 || c.overflowed
+}
+
+// resetDirty must be called with c.mu held.
+func (c *FallbackCache) resetDirty() {
+	clear(c.dirty)
+	clear(c.dirtyPatterns)
+	c.overflowed = false
+}
@@ -0,0 +1,273 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"grpc-server/internal/cache"
+	"grpc-server/internal/cache/cachetest"
+	"grpc-server/internal/clock"
+)
+
+var errDown = errors.New("primary is down")
+
+// flakyCache is a primary that fails every call while down is set.
+type flakyCache struct {
+	*cachetest.Cache
+	down atomic.Bool
+}
+
+func newFlakyCache() *flakyCache {
+	return &flakyCache{Cache: cachetest.New(clock.System)}
+}
+
+func (c *flakyCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if c.down.Load() {
+		return nil, errDown
+	}
+	return c.Cache.Get(ctx, key)
+}
+
+func (c *flakyCache) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	if c.down.Load() {
+		return nil, errDown
+	}
+	return c.Cache.MGet(ctx, keys...)
+}
+
+func (c *flakyCache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	if c.down.Load() {
+		return errDown
+	}
+	return c.Cache.Set(ctx, key, value, expiration)
+}
+
+func (c *flakyCache) Delete(ctx context.Context, keys ...string) error {
+	if c.down.Load() {
+		return errDown
+	}
+	return c.Cache.Delete(ctx, keys...)
+}
+
+func (c *flakyCache) DeletePattern(ctx context.Context, pattern string) (int, error) {
+	if c.down.Load() {
+		return 0, errDown
+	}
+	return c.Cache.DeletePattern(ctx, pattern)
+}
+
+func (c *flakyCache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	if c.down.Load() {
+		return errDown
+	}
+	return c.Cache.Expire(ctx, key, expiration)
+}
+
+func newFallbackCache(primary cache.Cache, cfg cache.FallbackConfig) *cache.FallbackCache {
+	if cfg.FailureThreshold == 0 {
+		cfg.FailureThreshold = 1
+	}
+	if cfg.Cooldown == 0 {
+		// Long enough that only Reconnected resynchronizes
+		cfg.Cooldown = time.Hour
+	}
+	if cfg.MaxDirtyKeys == 0 {
+		cfg.MaxDirtyKeys = 100
+	}
+	cfg.ResyncTimeout = time.Second
+	return cache.NewFallbackCache(primary, cache.NewMemoryCache(100), cfg, slog.New(slog.DiscardHandler))
+}
+
+// waitForPrimary calls Reconnected until c resumes the primary. Calling it
+// again covers an earlier resynchronization that was still probing a primary
+// that was down.
+func waitForPrimary(t *testing.T, c *cache.FallbackCache) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Degraded() {
+		if time.Now().After(deadline) {
+			t.Fatal("fallback did not resume the primary")
+		}
+		c.Reconnected()
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFallbackBreakerTransitions(t *testing.T) {
+	type step struct {
+		name string
+		down bool   // primary state for this step
+		do   string // "get", "set" or "reconnect"
+		// wantErr is the error the step's call returns.
+		wantErr error
+		// wantDegraded is the breaker state after the step.
+		wantDegraded bool
+	}
+	for _, tt := range []struct {
+		name      string
+		threshold int
+		steps     []step
+	}{
+		{
+			name:      "opens after the failure threshold",
+			threshold: 3,
+			steps: []step{
+				{name: "first failure", down: true, do: "get", wantErr: errDown},
+				{name: "second failure", down: true, do: "get", wantErr: errDown},
+				{name: "third failure", down: true, do: "get", wantErr: errDown, wantDegraded: true},
+				{name: "served by the fallback", down: true, do: "get", wantErr: cache.ErrCacheMiss, wantDegraded: true},
+			},
+		},
+		{
+			name:      "an answer resets the failure count",
+			threshold: 2,
+			steps: []step{
+				{name: "failure", down: true, do: "get", wantErr: errDown},
+				{name: "miss", do: "get", wantErr: cache.ErrCacheMiss},
+				{name: "failure after the miss", down: true, do: "get", wantErr: errDown},
+				{name: "write", do: "set"},
+				{name: "failure after the write", down: true, do: "get", wantErr: errDown},
+				{name: "second failure in a row", down: true, do: "set", wantErr: errDown, wantDegraded: true},
+			},
+		},
+		{
+			name:      "stays open until the primary answers",
+			threshold: 1,
+			steps: []step{
+				{name: "failure", down: true, do: "get", wantErr: errDown, wantDegraded: true},
+				{name: "written to the fallback", down: true, do: "set", wantDegraded: true},
+				{name: "read from the fallback", down: true, do: "get", wantDegraded: true},
+				{name: "reconnected while still down", down: true, do: "reconnect", wantDegraded: true},
+				{name: "still read from the fallback", down: true, do: "get", wantDegraded: true},
+				{name: "reconnected", do: "reconnect"},
+				// The fallback is flushed on resume and the primary never
+				// saw the write.
+				{name: "read from the primary", do: "get", wantErr: cache.ErrCacheMiss},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			primary := newFlakyCache()
+			c := newFallbackCache(primary, cache.FallbackConfig{FailureThreshold: tt.threshold})
+			for _, s := range tt.steps {
+				primary.down.Store(s.down)
+				var err error
+				switch s.do {
+				case "get":
+					_, err = c.Get(ctx, "user:1")
+				case "set":
+					err = c.Set(ctx, "user:1", "fresh", time.Hour)
+				case "reconnect":
+					if s.wantDegraded {
+						c.Reconnected()
+					} else {
+						waitForPrimary(t, c)
+					}
+				}
+				if !errors.Is(err, s.wantErr) {
+					t.Fatalf("%s: error = %v, want %v", s.name, err, s.wantErr)
+				}
+				if got := c.Degraded(); got != s.wantDegraded {
+					t.Fatalf("%s: Degraded() = %v, want %v", s.name, got, s.wantDegraded)
+				}
+			}
+		})
+	}
+}
+
+func TestFallbackResyncDeletesWhatThePrimaryMissed(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		maxDirtyKeys int
+		keyPatterns  []string
+		// written through the fallback while the primary is down
+		written []string
+		want    []string
+	}{
+		{
+			name:    "dirty keys",
+			written: []string{"user:1"},
+			want:    []string{"other:service", "user:2", "users:list:ids:page"},
+		},
+		{
+			name:         "overflow deletes the service's keys",
+			maxDirtyKeys: 1,
+			keyPatterns:  []string{"user:*", "users:*"},
+			written:      []string{"user:1", "user:3"},
+			want:         []string{"other:service"},
+		},
+		{
+			name:         "overflow without key patterns deletes nothing",
+			maxDirtyKeys: 1,
+			written:      []string{"user:1", "user:3"},
+			want:         []string{"other:service", "user:1", "user:2", "users:list:ids:page"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			primary := newFlakyCache()
+			for _, key := range []string{"user:1", "user:2", "users:list:ids:page", "other:service"} {
+				if err := primary.Set(ctx, key, "stale", time.Hour); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c := newFallbackCache(primary, cache.FallbackConfig{MaxDirtyKeys: tt.maxDirtyKeys, KeyPatterns: tt.keyPatterns})
+
+			primary.down.Store(true)
+			if _, err := c.Get(ctx, "user:1"); !errors.Is(err, errDown) {
+				t.Fatalf("Get = %v, want the primary's error", err)
+			}
+			if !c.Degraded() {
+				t.Fatal("breaker did not open")
+			}
+			for _, key := range tt.written {
+				if err := c.Set(ctx, key, "fresh", time.Hour); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			primary.down.Store(false)
+			waitForPrimary(t, c)
+			if got := primary.Keys(); !slices.Equal(got, tt.want) {
+				t.Errorf("primary keys after resync = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFallbackDeletesKeysOfFailedWritesWhileClosed(t *testing.T) {
+	ctx := context.Background()
+	primary := newFlakyCache()
+	if err := primary.Set(ctx, "user:1", "stale", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	c := newFallbackCache(primary, cache.FallbackConfig{FailureThreshold: 3})
+
+	primary.down.Store(true)
+	if err := c.Delete(ctx, "user:1"); !errors.Is(err, errDown) {
+		t.Fatalf("Delete = %v, want the primary's error", err)
+	}
+	if c.Degraded() {
+		t.Fatal("breaker opened below its threshold")
+	}
+
+	// The next answer resets the failure count and deletes what the failed
+	// write left behind.
+	primary.down.Store(false)
+	if _, err := c.Get(ctx, "user:2"); !errors.Is(err, cache.ErrCacheMiss) {
+		t.Fatalf("Get = %v, want a miss", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for primary.Has("user:1") {
+		if time.Now().After(deadline) {
+			t.Fatal("stale user:1 was never deleted from the primary")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,290 @@
+package cache
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"grpc-server/internal/logging"
+)
+
+// HotKeyConfig tunes a HotKeyCache.
+type HotKeyConfig struct {
+	// SampleRatio is the fraction of reads counted, between 0 and 1.
+	SampleRatio float64
+	// Window is how often read rates are estimated from the sampled counts,
+	// and for how long the keys found hot stay hot.
+	Window time.Duration
+	// Threshold is the estimated reads per second that make one of the TopN
+	// most read keys hot.
+	Threshold float64
+	TopN      int
+	// MaxTracked bounds the keys counted per window; reads of other keys are
+	// not counted once it is reached. A hot key is read often enough to be
+	// among the first.
+	MaxTracked int
+	// LocalTTL is how long hot keys are served from memory. Changes made by
+	// other instances are broadcast (see InvalidateLocal); LocalTTL bounds
+	// how stale an entry gets when they are not.
+	LocalTTL        time.Duration
+	LocalMaxEntries int
+	// ExtendTTL, when positive, is the time to live hot keys are given once
+	// per window, so they don't expire under load and send every reader to
+	// the database at once.
+	ExtendTTL time.Duration
+}
+
+// HotKey is a key among the most read of the last window.
+type HotKey struct {
+	Key string
+	// Rate is the estimated reads per second.
+	Rate float64
+	Hot  bool
+}
+
+// HotKeyCache samples reads to find the keys read most, and serves those
+// read more than Threshold times a second from memory, so a celebrity
+// user's entry doesn't pin one Valkey shard. Call Run to estimate the rates.
+type HotKeyCache struct {
+	cache  Cache
+	local  *MemoryCache
+	cfg    HotKeyConfig
+	logger *logging.Logger
+
+	localHits metric.Int64Counter
+
+	mu       sync.Mutex
+	counts   map[string]int      // sampled reads in the current window
+	extended map[string]struct{} // hot keys whose TTL was extended this window
+
+	hot atomic.Pointer[map[string]struct{}]
+	top atomic.Pointer[[]HotKey]
+}
+
+var _ Cache = (*HotKeyCache)(nil)
+
+func NewHotKeyCache(c Cache, cfg HotKeyConfig, base *slog.Logger) *HotKeyCache {
+	meter := otel.Meter("rpc-server.rpc/cache")
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	localHits, _ := meter.Int64Counter("cache.hot_keys.local_hits",
+		metric.WithDescription("Reads of hot keys served from memory instead of Valkey"),
+		metric.WithUnit("{read}"),
+	)
+	h := &HotKeyCache{
+		cache:     c,
+		local:     NewMemoryCache(cfg.LocalMaxEntries),
+		cfg:       cfg,
+		logger:    logging.New(logging.ForModule(base, logging.ModuleCache).With("component", "hot_keys")),
+		localHits: localHits,
+		counts:    make(map[string]int),
+		extended:  make(map[string]struct{}),
+	}
+	h.hot.Store(&map[string]struct{}{})
+	h.top.Store(&[]HotKey{})
+
+	rate, _ := meter.Float64ObservableGauge("cache.hot_keys.rate",
+		metric.WithDescription("Estimated reads per second of the most read keys in the last window"),
+		metric.WithUnit("{read}/s"),
+	)
+	_, _ = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		for _, k := range h.Top() {
+			o.ObserveFloat64(rate, k.Rate, metric.WithAttributes(
+				attribute.String("cache.key", k.Key),
+				attribute.String("cache.namespace", Namespace(k.Key)),
+				attribute.Bool("hot", k.Hot),
+			))
+		}
+		return nil
+	}, rate)
+	return h
+}
+
+// Top returns the TopN most read keys of the last window, most read first.
+func (h *HotKeyCache) Top() []HotKey {
+	return *h.top.Load()
+}
+
+// Run estimates read rates every Window until ctx is done.
+func (h *HotKeyCache) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.cfg.Window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.rotate(ctx)
+		}
+	}
+}
+
+// rotate ends the current window: it ranks the keys counted in it and makes
+// hot those of the TopN above Threshold.
+func (h *HotKeyCache) rotate(ctx context.Context) {
+	h.mu.Lock()
+	counts := h.counts
+	h.counts = make(map[string]int, len(counts))
+	clear(h.extended)
+	h.mu.Unlock()
+
+	top := make([]HotKey, 0, len(counts))
+	perSecond := h.cfg.SampleRatio * h.cfg.Window.Seconds()
+	for key, n := range counts {
+		top = append(top, HotKey{Key: key, Rate: float64(n) / perSecond})
+	}
+	slices.SortFunc(top, func(a, b HotKey) int { return cmp.Compare(b.Rate, a.Rate) })
+	top = top[:min(len(top), h.cfg.TopN)]
+
+	previous := *h.hot.Load()
+	hot := make(map[string]struct{})
+	for i := range top {
+		if top[i].Rate < h.cfg.Threshold {
+			continue
+		}
+		top[i].Hot = true
+		hot[top[i].Key] = struct{}{}
+		if _, ok := previous[top[i].Key]; !ok {
+			h.logger.InfoCtx(ctx, "Cache key became hot", logging.CacheKey, top[i].Key, "reads_per_second", int(top[i].Rate))
+		}
+	}
+	h.hot.Store(&hot)
+	h.top.Store(&top)
+}
+
+// sample counts a read of key, SampleRatio of the time.
+func (h *HotKeyCache) sample(key string) {
+	if rand.Float64() >= h.cfg.SampleRatio {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.counts[key]; ok || len(h.counts) < h.cfg.MaxTracked {
+		h.counts[key]++
+	}
+}
+
+func (h *HotKeyCache) isHot(key string) bool {
+	_, ok := (*h.hot.Load())[key]
+	return ok
+}
+
+// promote keeps a hot key's value in memory and, once per window, extends
+// its TTL in the underlying cache. A failed extension only means the key
+// may expire; it is logged at debug level.
+func (h *HotKeyCache) promote(ctx context.Context, key string, data []byte) {
+	_ = h.local.Set(ctx, key, data, h.cfg.LocalTTL)
+	if h.cfg.ExtendTTL <= 0 {
+		return
+	}
+	h.mu.Lock()
+	_, done := h.extended[key]
+	h.extended[key] = struct{}{}
+	h.mu.Unlock()
+	if done {
+		return
+	}
+	if err := h.cache.Expire(ctx, key, h.cfg.ExtendTTL); err != nil {
+		h.logger.DebugCtx(ctx, "Failed to extend hot key TTL", logging.CacheKey, key, logging.Error, err)
+	}
+}
+
+func (h *HotKeyCache) Get(ctx context.Context, key string) ([]byte, error) {
+	h.sample(key)
+	if !h.isHot(key) {
+		return h.cache.Get(ctx, key)
+	}
+	if data, err := h.local.Get(ctx, key); err == nil {
+		h.localHits.Add(ctx, 1, metric.WithAttributes(attribute.String("cache.namespace", Namespace(key))))
+		return data, nil
+	}
+	data, err := h.cache.Get(ctx, key)
+	if err == nil {
+		h.promote(ctx, key, data)
+	}
+	return data, err
+}
+
+func (h *HotKeyCache) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	var remote []int // indexes of keys not served from memory
+	for i, key := range keys {
+		h.sample(key)
+		if h.isHot(key) {
+			if data, err := h.local.Get(ctx, key); err == nil {
+				h.localHits.Add(ctx, 1, metric.WithAttributes(attribute.String("cache.namespace", Namespace(key))))
+				values[i] = data
+				continue
+			}
+		}
+		remote = append(remote, i)
+	}
+	if len(remote) == 0 {
+		return values, nil
+	}
+
+	remoteKeys := make([]string, len(remote))
+	for j, i := range remote {
+		remoteKeys[j] = keys[i]
+	}
+	fetched, err := h.cache.MGet(ctx, remoteKeys...)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range remote {
+		values[i] = fetched[j]
+		if fetched[j] != nil && h.isHot(keys[i]) {
+			h.promote(ctx, keys[i], fetched[j])
+		}
+	}
+	return values, nil
+}
+
+// Set drops the memory copy first, as do the other writes; a read racing
+// with one may put back the old value, for at most LocalTTL.
+func (h *HotKeyCache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	_ = h.local.Delete(ctx, key)
+	return h.cache.Set(ctx, key, value, expiration)
+}
+
+func (h *HotKeyCache) Delete(ctx context.Context, keys ...string) error {
+	_ = h.local.Delete(ctx, keys...)
+	return h.cache.Delete(ctx, keys...)
+}
+
+func (h *HotKeyCache) DeletePattern(ctx context.Context, pattern string) (int, error) {
+	_, _ = h.local.DeletePattern(ctx, pattern)
+	return h.cache.DeletePattern(ctx, pattern)
+}
+
+func (h *HotKeyCache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return h.cache.Expire(ctx, key, expiration)
+}
+
+func (h *HotKeyCache) Close() error {
+	return h.cache.Close()
+}
+
+// InvalidateLocal removes keys, and the keys matching patterns, from memory
+// only, for changes another instance already made to Valkey.
+func (h *HotKeyCache) InvalidateLocal(ctx context.Context, keys, patterns []string) {
+	_ = h.local.Delete(ctx, keys...)
+	for _, pattern := range patterns {
+		_, _ = h.local.DeletePattern(ctx, pattern)
+	}
+}
+
+// FlushLocal drops every hot key held in memory, for when changes made by
+// other instances may have been missed.
+func (h *HotKeyCache) FlushLocal() {
+	h.local.Flush()
+}
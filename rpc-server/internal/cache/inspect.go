@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Inspector reports on what a cache server holds, for admin tooling.
+type Inspector interface {
+	Memory(ctx context.Context) (MemoryInfo, error)
+	SampleKeys(ctx context.Context, n int) (KeySample, error)
+}
+
+// MemoryInfo holds the memory numbers from INFO memory.
+type MemoryInfo struct {
+	UsedBytes          int64
+	PeakBytes          int64
+	MaxBytes           int64 // 0 means no limit
+	MaxMemoryPolicy    string
+	FragmentationRatio float64
+}
+
+// KeySample counts a SCAN sample of keys per namespace; Total is the number
+// of keys in the database.
+type KeySample struct {
+	Sampled    int64
+	Total      int64
+	Namespaces map[string]int64
+}
+
+// Estimate extrapolates the number of keys in a namespace from the sample.
+func (s KeySample) Estimate(namespace string) int64 {
+	if s.Sampled == 0 {
+		return 0
+	}
+	return s.Namespaces[namespace] * s.Total / s.Sampled
+}
+
+// Memory reports INFO memory. For a cluster, the byte counts are summed
+// over the primaries and the fragmentation ratio is the worst of them.
+func (c *ValkeyCache) Memory(ctx context.Context) (MemoryInfo, error) {
+	nodes, err := c.primaries(ctx)
+	if err != nil {
+		return MemoryInfo{}, err
+	}
+	var info MemoryInfo
+	for _, node := range nodes {
+		text, err := node.Do(ctx, node.B().Info().Section("memory").Build()).ToString()
+		if err != nil {
+			return MemoryInfo{}, fmt.Errorf("cache info failed: %w", err)
+		}
+		nodeInfo := parseMemoryInfo(text)
+		info.UsedBytes += nodeInfo.UsedBytes
+		info.PeakBytes += nodeInfo.PeakBytes
+		info.MaxBytes += nodeInfo.MaxBytes
+		info.MaxMemoryPolicy = nodeInfo.MaxMemoryPolicy
+		info.FragmentationRatio = max(info.FragmentationRatio, nodeInfo.FragmentationRatio)
+	}
+	return info, nil
+}
+
+func parseMemoryInfo(text string) MemoryInfo {
+	var info MemoryInfo
+	for line := range strings.Lines(text) {
+		field, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		switch field {
+		case "used_memory":
+			info.UsedBytes, _ = strconv.ParseInt(value, 10, 64)
+		case "used_memory_peak":
+			info.PeakBytes, _ = strconv.ParseInt(value, 10, 64)
+		case "maxmemory":
+			info.MaxBytes, _ = strconv.ParseInt(value, 10, 64)
+		case "maxmemory_policy":
+			info.MaxMemoryPolicy = value
+		case "mem_fragmentation_ratio":
+			info.FragmentationRatio, _ = strconv.ParseFloat(value, 64)
+		}
+	}
+	return info
+}
+
+// SampleKeys scans until about n keys were seen or the keyspace was walked
+// once, primary by primary in a cluster. SCAN may return a key more than
+// once; that is fine for a sample.
+func (c *ValkeyCache) SampleKeys(ctx context.Context, n int) (KeySample, error) {
+	nodes, err := c.primaries(ctx)
+	if err != nil {
+		return KeySample{}, err
+	}
+	sample := KeySample{Namespaces: make(map[string]int64)}
+	for _, node := range nodes {
+		total, err := node.Do(ctx, node.B().Dbsize().Build()).AsInt64()
+		if err != nil {
+			return KeySample{}, fmt.Errorf("cache dbsize failed: %w", err)
+		}
+		sample.Total += total
+	}
+
+	for _, node := range nodes {
+		var cursor uint64
+		for sample.Sampled < int64(n) {
+			entry, err := node.Do(ctx, node.B().Scan().Cursor(cursor).Count(int64(min(n, 1000))).Build()).AsScanEntry()
+			if err != nil {
+				return KeySample{}, fmt.Errorf("cache scan failed: %w", err)
+			}
+			for _, key := range entry.Elements {
+				sample.Namespaces[Namespace(key)]++
+				sample.Sampled++
+			}
+			if cursor = entry.Cursor; cursor == 0 {
+				break
+			}
+		}
+	}
+	return sample, nil
+}
+
+var _ Inspector = (*ValkeyCache)(nil)
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"strings"
+)
+
+const (
+	// MaxKeyLength is the longest key Key builds from its parts verbatim;
+	// longer keys are hashed.
+	MaxKeyLength = 128
+	// HashedSegmentPrefix starts the segment that replaces hashed parts.
+	HashedSegmentPrefix = "~"
+	// hashedLength is how many hex characters of the SHA-256 digest are kept;
+	// 128 bits is plenty to make collisions between keys implausible.
+	hashedLength = 32
+)
+
+// Key builds a cache key from a readable prefix, such as "user:", and parts
+// that may come from user input. Parts are joined with ":" as they are when
+// all of them are plain identifiers and the key stays within MaxKeyLength.
+// Otherwise the parts are replaced by a truncated SHA-256 hash, so crafted
+// input can neither inflate the key nor inject separators that make it
+// collide with another key:
+//
+//	Key("user:", id)           // "user:6f1c2b1e-..."
+//	Key("users:search:", term) // "users:search:~9b0e4c..."
+func Key(prefix string, parts ...string) string {
+	plain := true
+	length := len(prefix)
+	for _, p := range parts {
+		length += len(p) + 1
+		if !plainPart(p) {
+			plain = false
+		}
+	}
+	if plain && length <= MaxKeyLength {
+		return prefix + strings.Join(parts, ":")
+	}
+
+	return prefix + HashedSegmentPrefix + hashParts(sha256.New(), parts)
+}
+
+// hashParts returns the first hashedLength hex characters of the digest of
+// parts. Length-prefixing each part keeps ("a", "bc") and ("ab", "c") apart.
+func hashParts(h hash.Hash, parts []string) string {
+	for _, p := range parts {
+		h.Write(binary.LittleEndian.AppendUint64(nil, uint64(len(p))))
+		h.Write([]byte(p))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:hashedLength]
+}
+
+// KeyHasher turns the parts of a key into an opaque hex segment.
+type KeyHasher interface {
+	HashKey(parts ...string) string
+}
+
+type hmacHasher struct {
+	secret []byte
+}
+
+// NewHMACHasher returns a KeyHasher keyed by secret. Unlike the plain
+// SHA-256 Key falls back to, its digests can't be reversed by hashing
+// candidate IDs or emails without the secret.
+func NewHMACHasher(secret []byte) KeyHasher {
+	return hmacHasher{secret: secret}
+}
+
+func (h hmacHasher) HashKey(parts ...string) string {
+	return hashParts(hmac.New(sha256.New, h.secret), parts)
+}
+
+// tenantPrefix starts the namespace of a tenant's keys.
+const tenantPrefix = "t:"
+
+// TenantKeyPattern matches every key Keys puts in a tenant namespace.
+const TenantKeyPattern = tenantPrefix + "*"
+
+// Keys builds cache keys like Key, optionally namespaced by tenant and with
+// their parts always hashed. The zero value builds the same keys as Key:
+//
+//	Keys{}.Key(ctx, "user:", id)                             // "user:6f1c2b1e-..."
+//	Keys{Tenant: tenantOf}.Key(ctx, "user:", id)             // "t:acme:user:6f1c2b1e-..."
+//	Keys{Tenant: tenantOf, Hasher: h}.Key(ctx, "user:", id)  // "t:~41d2...:user:~9b0e..."
+type Keys struct {
+	// Tenant returns the tenant whose namespace keys go in; "" and a nil
+	// Tenant leave keys outside any namespace.
+	Tenant func(context.Context) string
+	// Hasher, if set, hashes every part and the tenant, so shared
+	// monitoring tools never see them in readable form.
+	Hasher KeyHasher
+}
+
+// Key builds the key for prefix and parts in ctx's tenant namespace. A key
+// without parts, such as a fixed bookkeeping key, only gets the namespace.
+func (k Keys) Key(ctx context.Context, prefix string, parts ...string) string {
+	namespace := k.namespace(ctx)
+	switch {
+	case len(parts) == 0:
+		return namespace + prefix
+	case k.Hasher != nil:
+		return namespace + prefix + HashedSegmentPrefix + k.Hasher.HashKey(parts...)
+	}
+	return namespace + Key(prefix, parts...)
+}
+
+// Pattern returns the DeletePattern pattern matching every key built with
+// prefix in ctx's tenant namespace.
+func (k Keys) Pattern(ctx context.Context, prefix string) string {
+	return k.namespace(ctx) + prefix + "*"
+}
+
+func (k Keys) namespace(ctx context.Context) string {
+	if k.Tenant == nil {
+		return ""
+	}
+	tenant := k.Tenant(ctx)
+	switch {
+	case tenant == "":
+		return ""
+	case k.Hasher != nil:
+		return tenantPrefix + HashedSegmentPrefix + k.Hasher.HashKey(tenant) + ":"
+	}
+	return Key(tenantPrefix, tenant) + ":"
+}
+
+// plainPart reports whether p is a non-empty identifier that needs no
+// hashing: ASCII letters, digits, '-', '_' and '.'.
+func plainPart(p string) bool {
+	if p == "" {
+		return false
+	}
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"grpc-server/internal/logging"
+)
+
+// SubscribeConfig tunes how Subscribe resubscribes.
+type SubscribeConfig struct {
+	// MinBackoff is the wait before resubscribing after the subscription
+	// dropped; it doubles with every failed attempt up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Subscription delivers pub/sub messages to its handlers.
+type Subscription struct {
+	// Handle is called with every message, one at a time.
+	Handle func(ctx context.Context, channel string, message []byte)
+	// Resubscribed, if set, is called after the subscription dropped and was
+	// established again. Messages published in between are lost, so state
+	// they would have changed must be reset.
+	Resubscribed func(ctx context.Context)
+}
+
+// Publish sends message to the subscribers of channel.
+func (c *ValkeyCache) Publish(ctx context.Context, channel string, message []byte) error {
+	if err := c.client.Do(ctx, c.client.B().Publish().Channel(channel).Message(string(message)).Build()).Error(); err != nil {
+		return fmt.Errorf("cache publish failed: %w", err)
+	}
+	return nil
+}
+
+// Subscribe delivers the messages published on channels to sub until ctx is
+// done. It holds a dedicated connection; when that drops, Subscribe redials
+// and resubscribes with jittered exponential backoff.
+func (c *ValkeyCache) Subscribe(ctx context.Context, channels []string, sub Subscription, cfg SubscribeConfig) {
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	resubscribes, _ := otel.Meter("rpc-server.rpc/cache").Int64Counter("cache.pubsub.resubscribes",
+		metric.WithDescription("Number of times a dropped pub/sub subscription was established again"),
+		metric.WithUnit("{subscription}"),
+	)
+
+	backoff := cfg.MinBackoff
+	dropped := false
+	for {
+		err := c.subscribe(ctx, channels, sub, func() {
+			backoff = cfg.MinBackoff
+			if !dropped {
+				return
+			}
+			dropped = false
+			resubscribes.Add(ctx, 1, metric.WithAttributes(attribute.StringSlice("channels", channels)))
+			c.logger.InfoCtx(ctx, "Pub/sub subscription established again", "channels", channels)
+			if sub.Resubscribed != nil {
+				sub.Resubscribed(ctx)
+			}
+		})
+		if ctx.Err() != nil || errors.Is(err, valkey.ErrClosing) {
+			return
+		}
+		if !dropped {
+			c.logger.WarnCtx(ctx, "Pub/sub subscription dropped, resubscribing", "channels", channels, logging.Error, err)
+			dropped = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(rand.Int64N(int64(backoff)) + 1)):
+		}
+		backoff = min(2*backoff, cfg.MaxBackoff)
+	}
+}
+
+// subscribe subscribes a dedicated connection to channels, calls
+// established once Valkey confirmed every channel, and delivers messages
+// until the connection drops or ctx is done.
+func (c *ValkeyCache) subscribe(ctx context.Context, channels []string, sub Subscription, established func()) error {
+	client, release := c.client.Dedicate()
+	defer release()
+
+	confirmed := make(chan struct{})
+	closed := client.SetPubSubHooks(valkey.PubSubHooks{
+		OnMessage: func(msg valkey.PubSubMessage) {
+			sub.Handle(ctx, msg.Channel, []byte(msg.Message))
+		},
+		OnSubscription: func(s valkey.PubSubSubscription) {
+			if s.Kind == "subscribe" && s.Count == int64(len(channels)) {
+				close(confirmed)
+			}
+		},
+	})
+	if err := client.Do(ctx, client.B().Subscribe().Channel(channels...).Build()).Error(); err != nil {
+		return fmt.Errorf("cache subscribe failed: %w", err)
+	}
+
+	for {
+		select {
+		case <-confirmed:
+			established()
+			confirmed = nil
+		case err := <-closed:
+			if err == nil {
+				err = errors.New("pub/sub connection closed")
+			}
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+// Namespace returns the prefix that groups key with similar keys: its
+// leading colon-separated segments that contain no digit and are not a hash
+// from Key, so "user:<uuid>" is "user" and "users:list:0:20" is
+// "users:list". UUIDs always contain a digit, their version. A tenant
+// namespace from Keys is skipped, so tenants share namespaces and their
+// names stay out of metrics.
+func Namespace(key string) string {
+	segments := strings.Split(key, ":")
+	if len(segments) > 2 && segments[0]+":" == tenantPrefix {
+		segments = segments[2:]
+	}
+	n := 0
+	for n < len(segments)-1 && !strings.ContainsFunc(segments[n], unicode.IsDigit) && !strings.HasPrefix(segments[n], HashedSegmentPrefix) {
+		n++
+	}
+	if n == 0 {
+		return "other"
+	}
+	return strings.Join(segments[:n], ":")
+}
+
+// NamespaceCounts are lookup outcomes for one namespace.
+type NamespaceCounts struct {
+	Namespace string
+	Hits      int64
+	Misses    int64
+	Errors    int64
+}
+
+type counters struct {
+	hits, misses, errors atomic.Int64
+}
+
+// StatsCache counts hits, misses and errors of Get per namespace.
+type StatsCache struct {
+	cache Cache
+	since time.Time
+
+	mu         sync.RWMutex
+	namespaces map[string]*counters
+}
+
+func NewStatsCache(c Cache) *StatsCache {
+	return &StatsCache{cache: c, since: time.Now(), namespaces: make(map[string]*counters)}
+}
+
+func (s *StatsCache) counters(key string) *counters {
+	ns := Namespace(key)
+	s.mu.RLock()
+	c, ok := s.namespaces[ns]
+	s.mu.RUnlock()
+	if ok {
+		return c
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok = s.namespaces[ns]; !ok {
+		c = &counters{}
+		s.namespaces[ns] = c
+	}
+	return c
+}
+
+func (s *StatsCache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.cache.Get(ctx, key)
+	c := s.counters(key)
+	switch {
+	case err == nil:
+		c.hits.Add(1)
+	case errors.Is(err, ErrCacheMiss):
+		c.misses.Add(1)
+	default:
+		c.errors.Add(1)
+	}
+	return data, err
+}
+
+func (s *StatsCache) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	values, err := s.cache.MGet(ctx, keys...)
+	for i, key := range keys {
+		c := s.counters(key)
+		switch {
+		case err != nil:
+			c.errors.Add(1)
+		case values[i] != nil:
+			c.hits.Add(1)
+		default:
+			c.misses.Add(1)
+		}
+	}
+	return values, err
+}
+
+func (s *StatsCache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	return s.cache.Set(ctx, key, value, expiration)
+}
+
+func (s *StatsCache) Delete(ctx context.Context, keys ...string) error {
+	return s.cache.Delete(ctx, keys...)
+}
+
+func (s *StatsCache) DeletePattern(ctx context.Context, pattern string) (int, error) {
+	return s.cache.DeletePattern(ctx, pattern)
+}
+
+func (s *StatsCache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return s.cache.Expire(ctx, key, expiration)
+}
+
+func (s *StatsCache) Close() error {
+	return s.cache.Close()
+}
+
+// Counts returns the counts per namespace, sorted by namespace, and when
+// counting started.
+func (s *StatsCache) Counts() ([]NamespaceCounts, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	counts := make([]NamespaceCounts, 0, len(s.namespaces))
+	for ns, c := range s.namespaces {
+		counts = append(counts, NamespaceCounts{
+			Namespace: ns,
+			Hits:      c.hits.Load(),
+			Misses:    c.misses.Load(),
+			Errors:    c.errors.Load(),
+		})
+	}
+	slices.SortFunc(counts, func(a, b NamespaceCounts) int { return strings.Compare(a.Namespace, b.Namespace) })
+	return counts, s.since
+}
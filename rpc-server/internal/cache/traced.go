@@ -55,6 +55,34 @@ func (tc *TracedCache) Get(ctx context.Context, key string) ([]byte, error) {
 	return data, nil
 }
 
+func (tc *TracedCache) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	ctx, span := tc.tracer.Start(ctx, "cache.mget",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("cache.operation", "mget"),
+			attribute.Int("cache.keys", len(keys)),
+		),
+	)
+	defer span.End()
+
+	values, err := tc.cache.MGet(ctx, keys...)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return nil, err
+	}
+
+	hits := 0
+	for _, value := range values {
+		if value != nil {
+			hits++
+		}
+	}
+	span.SetAttributes(attribute.Int("cache.hits", hits))
+	span.SetStatus(codes.Ok, "")
+	return values, nil
+}
+
 func (tc *TracedCache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
 	ctx, span := tc.tracer.Start(ctx, "cache.set",
 		trace.WithSpanKind(trace.SpanKindClient),
@@ -76,17 +104,17 @@ func (tc *TracedCache) Set(ctx context.Context, key string, value any, expiratio
 	return nil
 }
 
-func (tc *TracedCache) Delete(ctx context.Context, key string) error {
+func (tc *TracedCache) Delete(ctx context.Context, keys ...string) error {
 	ctx, span := tc.tracer.Start(ctx, "cache.delete",
 		trace.WithSpanKind(trace.SpanKindClient),
 		trace.WithAttributes(
 			attribute.String("cache.operation", "delete"),
-			attribute.String("cache.key", key),
+			attribute.StringSlice("cache.key", keys),
 		),
 	)
 	defer span.End()
 
-	if err := tc.cache.Delete(ctx, key); err != nil {
+	if err := tc.cache.Delete(ctx, keys...); err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		span.RecordError(err)
 		return err
@@ -96,12 +124,49 @@ func (tc *TracedCache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
-func (tc *TracedCache) Close() error {
-	return tc.cache.Close()
+func (tc *TracedCache) DeletePattern(ctx context.Context, pattern string) (int, error) {
+	ctx, span := tc.tracer.Start(ctx, "cache.delete_pattern",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("cache.operation", "delete_pattern"),
+			attribute.String("cache.pattern", pattern),
+		),
+	)
+	defer span.End()
+
+	deleted, err := tc.cache.DeletePattern(ctx, pattern)
+	span.SetAttributes(attribute.Int("cache.deleted", deleted))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return deleted, err
+	}
+
+	span.SetStatus(codes.Ok, "cache delete pattern successful")
+	return deleted, nil
+}
+
+func (tc *TracedCache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	ctx, span := tc.tracer.Start(ctx, "cache.expire",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("cache.operation", "expire"),
+			attribute.String("cache.key", key),
+			attribute.String("cache.expiration", expiration.String()),
+		),
+	)
+	defer span.End()
+
+	if err := tc.cache.Expire(ctx, key, expiration); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "cache expire successful")
+	return nil
 }
 
-// DeleteUntraced performs a delete operation without creating a span
-// Useful for bulk operations where individual spans would create too much noise
-func (tc *TracedCache) DeleteUntraced(ctx context.Context, key string) error {
-	return tc.cache.Delete(ctx, key)
+func (tc *TracedCache) Close() error {
+	return tc.cache.Close()
 }
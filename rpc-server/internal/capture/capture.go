@@ -0,0 +1,259 @@
+// Package capture records sampled gRPC request/response pairs for replay
+// against a staging instance. Personal data is rewritten the same way the
+// anonymize command rewrites a database clone: IDs are kept and emails map to
+// the same placeholders for the same salt, so captured traffic lines up with
+// an anonymized copy of production.
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"grpc-server/internal/anonymize"
+	"grpc-server/internal/logging"
+	"grpc-server/pkg/apierror"
+)
+
+// Record is one captured call, written as a line of JSON.
+type Record struct {
+	Time     time.Time       `json:"time"`
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Code     string          `json:"code"`
+	// Reason is the ErrorInfo reason of a failed call. Status messages are
+	// not captured since they may quote personal data.
+	Reason     string  `json:"reason,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+	TraceID    string  `json:"trace_id,omitempty"`
+}
+
+// Sink stores captured records.
+type Sink interface {
+	Write(ctx context.Context, record Record) error
+	Close() error
+}
+
+// FileSink appends records to a file as JSON lines.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open capture file: %w", err)
+	}
+	return &FileSink{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (s *FileSink) Write(ctx context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(record)
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// Redactor rewrites personal data in captured messages, matching fields by
+// name across every message type.
+type Redactor struct {
+	anon *anonymize.Anonymizer
+}
+
+func NewRedactor(salt string) *Redactor {
+	return &Redactor{anon: anonymize.New(salt)}
+}
+
+const redacted = "[REDACTED]"
+
+// Redact returns a copy of msg with personal data replaced.
+func (r *Redactor) Redact(msg proto.Message) proto.Message {
+	msg = proto.Clone(msg)
+	r.redact(msg.ProtoReflect())
+	return msg
+}
+
+func (r *Redactor) redact(m protoreflect.Message) {
+	type change struct {
+		fd    protoreflect.FieldDescriptor
+		value string
+		clear bool
+	}
+	var changes []change
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch name := fd.Name(); {
+		case name == "document" || name == "payload" || name == "metadata":
+			// Exported documents, test payloads and echoed headers
+			changes = append(changes, change{fd: fd, clear: true})
+		case fd.Kind() == protoreflect.MessageKind && fd.IsList():
+			for i := range v.List().Len() {
+				r.redact(v.List().Get(i).Message())
+			}
+		case fd.Kind() == protoreflect.MessageKind && !fd.IsMap():
+			r.redact(v.Message())
+		case fd.Kind() == protoreflect.StringKind && !fd.IsList():
+			switch name {
+			case "email", "new_email":
+				changes = append(changes, change{fd: fd, value: r.anon.Email(v.String())})
+			case "name":
+				changes = append(changes, change{fd: fd, value: r.anon.Name(v.String())})
+			case "token", "reason", "signature":
+				changes = append(changes, change{fd: fd, value: redacted})
+			}
+		}
+		return true
+	})
+	for _, c := range changes {
+		if c.clear {
+			m.Clear(c.fd)
+		} else {
+			m.Set(c.fd, protoreflect.ValueOfString(c.value))
+		}
+	}
+}
+
+// Config controls which calls are captured.
+type Config struct {
+	// SampleRatio is the share of calls captured, from 0 to 1.
+	SampleRatio float64
+	// BufferSize is how many records may wait for the sink; further records
+	// are dropped rather than slowing down requests.
+	BufferSize int
+}
+
+// Recorder captures sampled calls and writes them to a sink in the
+// background.
+type Recorder struct {
+	sink     Sink
+	redactor *Redactor
+	cfg      Config
+	logger   *logging.Logger
+
+	records chan Record
+	done    chan struct{}
+
+	mu      sync.Mutex
+	dropped int
+}
+
+func NewRecorder(sink Sink, redactor *Redactor, cfg Config, base *slog.Logger) *Recorder {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 1024
+	}
+	r := &Recorder{
+		sink:     sink,
+		redactor: redactor,
+		cfg:      cfg,
+		logger:   logging.New(base.With("component", "capture")),
+		records:  make(chan Record, cfg.BufferSize),
+		done:     make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+	for record := range r.records {
+		if err := r.sink.Write(context.Background(), record); err != nil {
+			r.logger.Warn("Failed to write captured call", "method", record.Method, logging.Error, err)
+		}
+	}
+}
+
+// Close writes out buffered records and closes the sink.
+func (r *Recorder) Close() error {
+	close(r.records)
+	<-r.done
+
+	r.mu.Lock()
+	dropped := r.dropped
+	r.mu.Unlock()
+	if dropped > 0 {
+		r.logger.Warn("Captured calls dropped because the sink fell behind", "dropped", dropped)
+	}
+	return r.sink.Close()
+}
+
+// UnaryServerInterceptor captures a sample of unary calls.
+func (r *Recorder) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if r.cfg.SampleRatio <= 0 || rand.Float64() >= r.cfg.SampleRatio {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		elapsed := time.Since(start)
+
+		record, buildErr := r.record(ctx, info.FullMethod, req, resp, err, start, elapsed)
+		if buildErr != nil {
+			r.logger.DebugCtx(ctx, "Skipping capture of call", "method", info.FullMethod, logging.Error, buildErr)
+			return resp, err
+		}
+		select {
+		case r.records <- record:
+		default:
+			r.mu.Lock()
+			r.dropped++
+			r.mu.Unlock()
+		}
+		return resp, err
+	}
+}
+
+func (r *Recorder) record(ctx context.Context, method string, req, resp any, callErr error, start time.Time, elapsed time.Duration) (Record, error) {
+	reqMsg, ok := req.(proto.Message)
+	if !ok {
+		return Record{}, fmt.Errorf("request is not a protobuf message")
+	}
+	record := Record{
+		Time:       start.UTC(),
+		Method:     method,
+		Code:       status.Code(callErr).String(),
+		Reason:     apierror.Reason(callErr),
+		DurationMs: float64(elapsed.Microseconds()) / 1000,
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		record.TraceID = sc.TraceID().String()
+	}
+
+	var err error
+	if record.Request, err = marshal(r.redactor.Redact(reqMsg)); err != nil {
+		return Record{}, err
+	}
+	if respMsg, ok := resp.(proto.Message); ok && callErr == nil {
+		if record.Response, err = marshal(r.redactor.Redact(respMsg)); err != nil {
+			return Record{}, err
+		}
+	}
+	return record, nil
+}
+
+func marshal(msg proto.Message) (json.RawMessage, error) {
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", msg.ProtoReflect().Descriptor().FullName(), err)
+	}
+	return b, nil
+}
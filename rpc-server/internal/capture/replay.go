@@ -0,0 +1,146 @@
+package capture
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"grpc-server/internal/logging"
+	"grpc-server/pkg/apierror"
+	_ "grpc-server/pkg/pb" // registers the UserService descriptors
+)
+
+// ReplayOptions controls a replay run.
+type ReplayOptions struct {
+	// Rate limits calls per second; 0 replays as fast as the target answers.
+	Rate float64
+	// ReadOnly skips every method that is not a Get or List call.
+	ReadOnly bool
+	// Timeout bounds each replayed call.
+	Timeout time.Duration
+}
+
+// ReplayStats summarizes a replay run.
+type ReplayStats struct {
+	Replayed int
+	Skipped  int
+	// Mismatched counts calls whose status code or error reason differed
+	// from the captured call.
+	Mismatched int
+}
+
+// Replay re-issues the calls captured in r against conn and compares each
+// outcome with the original. Mismatches are logged; responses are not
+// compared since the target's data differs from the captured instance.
+func Replay(ctx context.Context, conn grpc.ClientConnInterface, r io.Reader, opts ReplayOptions, base *slog.Logger) (ReplayStats, error) {
+	logger := logging.New(base.With("component", "replay"))
+	var stats ReplayStats
+
+	var tick <-chan time.Time
+	if opts.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return stats, fmt.Errorf("line %d: invalid record: %w", line, err)
+		}
+		if opts.ReadOnly && !readOnly(record.Method) {
+			stats.Skipped++
+			continue
+		}
+
+		req, resp, err := messagesFor(record.Method)
+		if err != nil {
+			return stats, fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := protojson.Unmarshal(record.Request, req); err != nil {
+			return stats, fmt.Errorf("line %d: invalid request for %s: %w", line, record.Method, err)
+		}
+
+		if tick != nil {
+			select {
+			case <-ctx.Done():
+				return stats, ctx.Err()
+			case <-tick:
+			}
+		}
+
+		callCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+		callErr := conn.Invoke(callCtx, record.Method, req, resp)
+		cancel()
+		if ctx.Err() != nil {
+			return stats, ctx.Err()
+		}
+		stats.Replayed++
+
+		code, reason := status.Code(callErr).String(), apierror.Reason(callErr)
+		if code != record.Code || reason != record.Reason {
+			stats.Mismatched++
+			logger.WarnCtx(ctx, "Replayed call diverged from capture",
+				"line", line,
+				"method", record.Method,
+				"captured_code", record.Code,
+				"captured_reason", record.Reason,
+				"replayed_code", code,
+				"replayed_reason", reason,
+				"captured_trace_id", record.TraceID,
+			)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, fmt.Errorf("failed to read capture: %w", err)
+	}
+	return stats, nil
+}
+
+func readOnly(method string) bool {
+	name := path.Base(method)
+	return strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "List")
+}
+
+// messagesFor returns empty request and response messages for a full method
+// name ("/user.UserService/GetUser") using the registered descriptors.
+func messagesFor(method string) (protoreflect.ProtoMessage, protoreflect.ProtoMessage, error) {
+	service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid method %q", method)
+	}
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unknown service %s: %w", service, err)
+	}
+	sd, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s is not a service", service)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(name))
+	if md == nil {
+		return nil, nil, fmt.Errorf("unknown method %s", method)
+	}
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return nil, nil, fmt.Errorf("streaming method %s cannot be replayed", method)
+	}
+	return dynamicpb.NewMessage(md.Input()), dynamicpb.NewMessage(md.Output()), nil
+}
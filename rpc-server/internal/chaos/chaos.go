@@ -0,0 +1,272 @@
+// Package chaos injects faults into gRPC calls for chaos experiments: added
+// latency, error codes, or requests that never get an answer. Rules are seeded
+// from configuration and can be replaced at runtime over HTTP, and single
+// requests can ask for a fault through metadata.
+package chaos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"grpc-server/internal/logging"
+)
+
+// Request metadata that asks for a fault on that call only.
+const (
+	DelayHeader   = "x-fault-delay"   // duration, e.g. "250ms"
+	CodeHeader    = "x-fault-code"    // status code name, e.g. "UNAVAILABLE"
+	PercentHeader = "x-fault-percent" // share of such requests affected, default 100
+)
+
+// RulesPath is where the HTTP listener serves the current rules.
+const RulesPath = "/debug/faults"
+
+// Rule describes a fault applied to a share of calls to a method.
+type Rule struct {
+	// Method is the method name without the service ("GetUser"), or "*" for
+	// every method.
+	Method string `json:"method"`
+	// Percent is the share of matching calls affected, from 0 to 100.
+	Percent float64 `json:"percent"`
+	// Delay is added before the call is handled, e.g. "500ms".
+	Delay string `json:"delay,omitempty"`
+	// Code, if set, is returned instead of calling the handler, e.g.
+	// "UNAVAILABLE".
+	Code string `json:"code,omitempty"`
+	// Drop leaves the call unanswered until the caller gives up.
+	Drop bool `json:"drop,omitempty"`
+}
+
+// fault is a parsed Rule.
+type fault struct {
+	method  string
+	percent float64
+	delay   time.Duration
+	code    grpc_codes.Code
+	drop    bool
+}
+
+func (r Rule) parse() (fault, error) {
+	f := fault{method: r.Method, percent: r.Percent, drop: r.Drop}
+	if r.Method == "" {
+		return f, fmt.Errorf("rule has no method")
+	}
+	if r.Percent < 0 || r.Percent > 100 {
+		return f, fmt.Errorf("rule for %s: percent must be between 0 and 100, got %v", r.Method, r.Percent)
+	}
+	if r.Delay != "" {
+		delay, err := time.ParseDuration(r.Delay)
+		if err != nil {
+			return f, fmt.Errorf("rule for %s: invalid delay: %w", r.Method, err)
+		}
+		f.delay = delay
+	}
+	if r.Code != "" {
+		code, err := parseCode(r.Code)
+		if err != nil {
+			return f, fmt.Errorf("rule for %s: %w", r.Method, err)
+		}
+		f.code = code
+	}
+	return f, nil
+}
+
+// parseCode accepts upper-case status code names, as used in service configs.
+func parseCode(s string) (grpc_codes.Code, error) {
+	var code grpc_codes.Code
+	if err := code.UnmarshalJSON([]byte(strconv.Quote(strings.ToUpper(s)))); err != nil {
+		return 0, fmt.Errorf("invalid status code %q", s)
+	}
+	return code, nil
+}
+
+// ParseRules decodes a JSON array of rules.
+func ParseRules(raw string) ([]Rule, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var rules []Rule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse fault rules: %w", err)
+	}
+	return rules, nil
+}
+
+// Injector holds the active rules.
+type Injector struct {
+	logger *logging.Logger
+
+	mu     sync.RWMutex
+	rules  []Rule
+	faults []fault
+}
+
+func NewInjector(base *slog.Logger) *Injector {
+	return &Injector{logger: logging.New(base.With("component", "chaos"))}
+}
+
+// SetRules replaces the active rules. Nothing changes if any rule is invalid.
+func (i *Injector) SetRules(rules []Rule) error {
+	faults := make([]fault, 0, len(rules))
+	for _, r := range rules {
+		f, err := r.parse()
+		if err != nil {
+			return err
+		}
+		faults = append(faults, f)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rules = rules
+	i.faults = faults
+	return nil
+}
+
+// Rules returns the active rules.
+func (i *Injector) Rules() []Rule {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return append([]Rule(nil), i.rules...)
+}
+
+// pick returns the fault for this call, if any. The first rule matching the
+// method decides; metadata faults take precedence over rules.
+func (i *Injector) pick(ctx context.Context, fullMethod string) (fault, bool, error) {
+	if f, ok, err := fromMetadata(ctx); ok || err != nil {
+		return f, ok && roll(f.percent), err
+	}
+
+	method := path.Base(fullMethod)
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for _, f := range i.faults {
+		if f.method == "*" || f.method == method {
+			return f, roll(f.percent), nil
+		}
+	}
+	return fault{}, false, nil
+}
+
+func fromMetadata(ctx context.Context) (fault, bool, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return fault{}, false, nil
+	}
+	delay, code, percent := first(md, DelayHeader), first(md, CodeHeader), first(md, PercentHeader)
+	if delay == "" && code == "" {
+		return fault{}, false, nil
+	}
+
+	r := Rule{Method: "*", Percent: 100, Delay: delay, Code: code}
+	if percent != "" {
+		p, err := strconv.ParseFloat(percent, 64)
+		if err != nil {
+			return fault{}, false, fmt.Errorf("invalid %s: %w", PercentHeader, err)
+		}
+		r.Percent = p
+	}
+	f, err := r.parse()
+	return f, err == nil, err
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func roll(percent float64) bool {
+	return percent >= 100 || rand.Float64()*100 < percent
+}
+
+// UnaryServerInterceptor applies the fault picked for each call. Delays and
+// drops end early when the call's context is done.
+func (i *Injector) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		f, ok, err := i.pick(ctx, info.FullMethod)
+		if err != nil {
+			return nil, status.Error(grpc_codes.InvalidArgument, err.Error())
+		}
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		trace.SpanFromContext(ctx).AddEvent("fault.injected", trace.WithAttributes(
+			attribute.Int64("fault.delay_ms", f.delay.Milliseconds()),
+			attribute.String("fault.code", f.code.String()),
+			attribute.Bool("fault.drop", f.drop),
+		))
+		i.logger.DebugCtx(ctx, "Injecting fault", "method", info.FullMethod, "delay", f.delay, "code", f.code.String(), "drop", f.drop)
+
+		if f.delay > 0 {
+			timer := time.NewTimer(f.delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, status.FromContextError(ctx.Err()).Err()
+			case <-timer.C:
+			}
+		}
+		if f.drop {
+			<-ctx.Done()
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		if f.code != grpc_codes.OK {
+			return nil, status.Errorf(f.code, "injected fault: %s", f.code)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Register serves the rules on mux: GET lists them, PUT replaces them with a
+// JSON array, and DELETE clears them. Anyone who can reach mux can fail every
+// call, so only register it on a listener untrusted callers can't reach.
+func (i *Injector) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+RulesPath, func(w http.ResponseWriter, r *http.Request) {
+		writeRules(w, i.Rules())
+	})
+	mux.HandleFunc("PUT "+RulesPath, func(w http.ResponseWriter, r *http.Request) {
+		var rules []Rule
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&rules); err != nil {
+			http.Error(w, fmt.Sprintf("invalid rules: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := i.SetRules(rules); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		i.logger.WarnCtx(r.Context(), "Fault injection rules replaced", "rules", len(rules))
+		writeRules(w, rules)
+	})
+	mux.HandleFunc("DELETE "+RulesPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = i.SetRules(nil)
+		i.logger.InfoCtx(r.Context(), "Fault injection rules cleared")
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func writeRules(w http.ResponseWriter, rules []Rule) {
+	if rules == nil {
+		rules = []Rule{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
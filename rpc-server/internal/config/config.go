@@ -5,34 +5,186 @@ import (
 	"log/slog"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Logger   LoggerConfig
-	Database DatabaseConfig
-	Cache    CacheConfig
-	Tracing  TracingConfig
+	Server    ServerConfig
+	Logger    LoggerConfig
+	Audit     AuditConfig
+	Database  DatabaseConfig
+	Cache     CacheConfig
+	Tracing   TracingConfig
+	Retention RetentionConfig
+	SLO       SLOConfig
+	Capture   CaptureConfig
+	Shadow    ShadowConfig
+	Startup   StartupConfig
+	Auth      AuthConfig
 }
 
 type ServerConfig struct {
-	Port             string
+	Port     string
+	HTTPPort string // empty disables the HTTP listener
+	// GatewayEnabled serves the REST API on the HTTP listener.
+	GatewayEnabled bool
+	// TLSCertFile and TLSKeyFile turn on TLS for the gRPC listener;
+	// TLSClientCAFile additionally requires client certificates signed by
+	// one of its CAs. The files are read again on SIGHUP. Kubernetes gRPC
+	// probes cannot speak TLS, so use exec or TCP probes with it.
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+	// MetricsEnabled serves Prometheus metrics on the HTTP listener.
+	MetricsEnabled   bool
 	MaxRecvMsgSize   int
 	MaxSendMsgSize   int
 	EnableReflection bool
+	// MethodRequestSizeLimits and MethodResponseSizeLimits cap messages per
+	// method below MaxRecvMsgSize and MaxSendMsgSize, e.g.
+	// "ListUsers=1048576".
+	MethodRequestSizeLimits  string
+	MethodResponseSizeLimits string
+	// DeadlineReservePercent is the share of each request's remaining deadline
+	// held back from the database for response handling.
+	DeadlineReservePercent int
+	ExportSigningKey       string // empty disables ExportUserData
+	EmailChangeTTLMinutes  int    // validity of email change confirmation tokens
+	// FaultInjectionEnabled turns on chaos fault injection; never enable it in
+	// production. FaultInjectionRules seeds the rules as a JSON array.
+	FaultInjectionEnabled bool
+	FaultInjectionRules   string
+	// AdminHTTPAddr is where /debug/faults is served, unauthenticated, so it
+	// defaults to loopback: reach it with kubectl port-forward. Only bind it
+	// elsewhere on a network untrusted callers can't reach; empty disables
+	// the listener, leaving FaultInjectionRules fixed.
+	AdminHTTPAddr string
+	// TestStreamEnabled serves the TestStream load-test RPC, which lets any
+	// caller make the server send up to 64 MiB per stream.
+	TestStreamEnabled bool
+	// Initial runtime config; all of these can be changed with
+	// SetRuntimeConfig.
+	RateLimitQPS   float64 // server-wide; 0 disables rate limiting
+	RateLimitBurst int
+	ReadOnly       bool
+	// LowPriorityReserve is the share of the burst kept from batch and
+	// load-test callers; PriorityClasses lists "caller=class" entries. A
+	// caller is the subject of its token, or without auth
+	// "key:<first 16 hex digits of the x-api-key's SHA-256>" or
+	// "ip:<address>".
+	LowPriorityReserve float64
+	PriorityClasses    []string
+	// DegradedReads keeps GetUser and ListUsers answering from the cache,
+	// marked stale, while the database is unreachable; writes are rejected.
+	DegradedReads bool
+	// DefaultPageSize is the ListUsers page size when a request sets no
+	// limit; larger limits than MaxPageSize are rejected.
+	DefaultPageSize int
+	MaxPageSize     int
+	// UpdateDedupWindowMs collapses identical UpdateUser calls arriving
+	// while the first runs into one write, for up to this long; 0 disables
+	// it.
+	UpdateDedupWindowMs int
+	// The grpc.health.v1 status follows database and cache pings made every
+	// HealthCheckIntervalMs, each bounded by HealthCheckTimeoutMs.
+	HealthCheckIntervalMs int
+	HealthCheckTimeoutMs  int
+	// ClientRateLimitQPS limits each client, identified by ClientRateLimitKey
+	// ("peer" or "api_key"), on top of the server-wide limit; 0 disables
+	// it. Buckets are kept for up to ClientRateLimitMaxClients clients.
+	ClientRateLimitQPS        float64
+	ClientRateLimitBurst      int
+	ClientRateLimitKey        string
+	ClientRateLimitMaxClients int
+	// ResponseMessagesEnabled fills the deprecated human-readable message
+	// fields of responses; clients should read their outcome instead.
+	ResponseMessagesEnabled bool
 }
 
 type LoggerConfig struct {
 	Level  slog.Level
 	Format string // "json" or "text"
+	// ModuleLevels lists "module=level" entries, e.g. "cache=debug", for
+	// subsystems that log at a different level than Level.
+	ModuleLevels []string
+	// DEBUG and INFO records with the same message are sampled once more
+	// than SampleFirst are written in a second: one in SampleThereafter
+	// passes. SampleFirst 0 disables sampling.
+	SampleFirst      int
+	SampleThereafter int
+	// File also writes logs to this path, for hosts without a log
+	// collector; empty logs to stdout only. The file is rotated once it
+	// reaches FileMaxSizeMB or is FileRotateHours old, whichever comes first.
+	File            string
+	FileMaxSizeMB   int
+	FileRotateHours int
+	FileMaxBackups  int // 0 keeps every rotated file
+	FileCompress    bool
+	// RedactKeys and RedactPatterns (regular expressions) name attributes
+	// scrubbed from every record, either masked or hashed per RedactMode.
+	RedactKeys     []string
+	RedactPatterns []string
+	RedactMode     string // "mask" or "hash"
+	RedactSalt     string // keys "hash" so values cannot be guessed
+}
+
+// AuditConfig routes audit records to a sink of their own. An empty Sink
+// writes them to the application log.
+type AuditConfig struct {
+	Sink string // "", "stdout", "file" or "syslog"
+	// File, FileMaxSizeMB and FileMaxBackups configure the "file" sink.
+	File           string
+	FileMaxSizeMB  int
+	FileMaxBackups int // 0 keeps every rotated file
+	// SyslogAddress is a host:port reached over SyslogNetwork; empty uses
+	// the local syslog daemon.
+	SyslogNetwork string
+	SyslogAddress string
 }
 
 type DatabaseConfig struct {
-	URL         string
+	// URL must connect as an ordinary role: superusers and roles with
+	// BYPASSRLS skip the row-level security that keeps tenants apart.
+	URL string
+	// ShardURLs adds shards after the one at URL; users are spread across all
+	// of them by ID. Maintenance subcommands other than migrate only operate
+	// on URL.
+	ShardURLs   []string
 	MaxConns    int
 	MinConns    int
 	MaxIdleTime int // seconds
 	MaxLifetime int // seconds
+	// AcquireTimeoutMs bounds the wait for a free pool connection; 0 waits
+	// until the request deadline.
+	AcquireTimeoutMs int
+	// Connection health checks. HealthCheckPeriod is in seconds (0 keeps the
+	// pgx default). ValidationQuery, when set, replaces the acquire ping and
+	// must return one boolean, e.g. "SELECT NOT pg_is_in_recovery()" to drop
+	// connections to a primary that was demoted. A connection is discarded
+	// once it has seen MaxConnErrors connection-level errors (0 never).
+	HealthCheckPeriod int
+	PingOnAcquire     bool
+	ValidationQuery   string
+	MaxConnErrors     int
+	// The database counts as down after OutageThreshold consecutive failed
+	// pings, one every OutageCheckIntervalMs; see Server.DegradedReads.
+	OutageCheckIntervalMs int
+	OutageThreshold       int
+	// FanOutParallelism bounds the database operations a single batch, such
+	// as a user expiry run, has in flight at once. Keep it well below
+	// MaxConns so batches leave connections for requests.
+	FanOutParallelism int
+	// Queries slower than SlowQueryThresholdMs are logged (0 disables the
+	// check). With ExplainSlowQueries, a sample of the slow reads is run
+	// again in the background under EXPLAIN (ANALYZE, BUFFERS), bounded by
+	// ExplainTimeoutMs, and the plan is logged.
+	SlowQueryThresholdMs int
+	ExplainSlowQueries   bool
+	ExplainSampleRatio   float64
+	ExplainTimeoutMs     int
+	// MigrateOnStartup applies pending migrations to every shard before the
+	// server starts; otherwise run `server migrate`.
+	MigrateOnStartup bool
 }
 
 type CacheConfig struct {
@@ -41,6 +193,141 @@ type CacheConfig struct {
 	MinConns        int
 	ConnMaxIdleTime int // seconds
 	ConnMaxLifetime int // seconds
+	OpTimeoutMs     int // upper bound for a single cache operation
+	// SlidingTTLSeconds resets a user entry's TTL on every hit; 0 disables
+	// sliding expiration.
+	SlidingTTLSeconds         int
+	SlidingMaxLifetimeSeconds int // 0 lets hot entries live indefinitely
+	UserTTLSeconds            int
+	ListTTLSeconds            int
+	MaxValueBytes             int // larger values are not cached; 0 disables the limit
+	// FallbackMaxEntries sizes the in-memory cache used while Valkey is down;
+	// 0 disables the fallback. The breaker opens after BreakerFailures
+	// consecutive errors and retries Valkey every BreakerCooldownMs.
+	FallbackMaxEntries   int
+	FallbackMaxDirtyKeys int // keys tracked for resync before deleting every cached user and list
+	BreakerFailures      int
+	BreakerCooldownMs    int
+	// UserStrategy is "write_through" or "cache_aside"; ListStrategy is
+	// "cache_aside" or "write_back", which fills list pages from a queue
+	// flushed every WriteBackFlushMs.
+	UserStrategy        string
+	ListStrategy        string
+	WriteBackFlushMs    int
+	WriteBackMaxPending int
+	// TenantNamespace puts every key under the tenant of the caller's
+	// token. Users belong to a tenant, so without it tenants share entries:
+	// one tenant's lookup of an email could return another tenant's user.
+	// KeyHMACSecret, when set, hashes tenants and key material with HMAC so
+	// neither is readable in Valkey.
+	TenantNamespace bool
+	KeyHMACSecret   string
+	// The Valkey connection is checked every ConnCheckIntervalMs; after a
+	// failure, checks and pub/sub resubscription back off from
+	// ReconnectMinBackoffMs up to ReconnectMaxBackoffMs.
+	ConnCheckIntervalMs   int
+	ReconnectMinBackoffMs int
+	ReconnectMaxBackoffMs int
+	// InvalidationBroadcast shares invalidations between instances over
+	// pub/sub, for entries each instance holds locally.
+	InvalidationBroadcast bool
+	// A HotKeySampleRatio of reads is counted to estimate per-key read
+	// rates every HotKeyWindowSeconds. Of the HotKeyTopN most read keys,
+	// those above HotKeyThreshold reads per second are served from memory
+	// for HotKeyLocalTTLMs at a time and have their TTL extended to
+	// HotKeyExtendTTLSeconds (0 leaves it alone). A threshold of 0 disables
+	// hot key detection.
+	HotKeySampleRatio      float64
+	HotKeyWindowSeconds    int
+	HotKeyThreshold        float64
+	HotKeyTopN             int
+	HotKeyMaxTracked       int
+	HotKeyLocalTTLMs       int
+	HotKeyExtendTTLSeconds int
+
+	// Mode is "standalone", "cluster" or "auto", which detects a Valkey
+	// Cluster from the first node that answers. URL may list several seed
+	// nodes, comma-separated, for a cluster.
+	Mode     string
+	Username string
+	Password string
+	// TLSEnabled connects over TLS, verifying the nodes against TLSCAFile,
+	// or the system roots when it is empty.
+	TLSEnabled bool
+	TLSCAFile  string
+	// NegativeTTLSeconds is how long a user ID GetUser did not find is
+	// remembered as missing; 0 disables negative caching.
+	NegativeTTLSeconds int
+	// RaceUserReads is the initial race_user_reads runtime setting: GetUser
+	// reads the cache and the database at once.
+	RaceUserReads bool
+}
+
+type RetentionConfig struct {
+	InactiveExpiryDays    int // 0 disables the inactive account expiry job
+	ExpiryIntervalMinutes int
+	ExpiryBatchSize       int
+	ExpiryDryRun          bool
+	// Retention windows of the table retention job, in days; 0 keeps a
+	// table's rows forever.
+	HistoryRetentionDays     int
+	EmailChangeRetentionDays int
+	PruneIntervalMinutes     int
+	PruneBatchSize           int
+	PruneMaxBatches          int // per table and run; 0 is unlimited
+}
+
+type CaptureConfig struct {
+	File        string  // empty disables traffic capture
+	SampleRatio float64 // share of calls captured
+	// Salt derives replacement personal data; use the salt the staging
+	// database was anonymized with so replayed emails match.
+	Salt string
+}
+
+type ShadowConfig struct {
+	// DatabaseURL points at the candidate datastore reads are mirrored to;
+	// empty disables shadow reads.
+	DatabaseURL   string
+	SamplePercent float64 // share of reads mirrored, from 0 to 100
+	TimeoutMs     int     // upper bound for a single shadow read
+}
+
+type AuthConfig struct {
+	// JWKSURL is where the token issuer publishes its signing keys; empty
+	// leaves every RPC unauthenticated.
+	JWKSURL            string
+	JWKSRefreshMinutes int
+	Issuer             string // empty accepts any issuer
+	Audience           string // empty accepts any audience
+	RolesClaim         string
+	TenantClaim        string
+	// MethodPermissions names the role a method requires, e.g.
+	// "DeleteUser=admin,EraseUser=admin". Other methods only require a
+	// valid token.
+	MethodPermissions string
+}
+
+// StartupConfig bounds how long each dependency may take to connect at
+// startup; they connect concurrently.
+type StartupConfig struct {
+	TracingTimeoutMs  int
+	DatabaseTimeoutMs int // per pool: primary, each shard and the shadow
+	CacheTimeoutMs    int
+	// SchemaDriftMode is what happens when the users table differs from
+	// what this build expects: "off", "warn" or "fail" to refuse to start.
+	SchemaDriftMode string
+}
+
+type SLOConfig struct {
+	Enabled            bool
+	AvailabilityTarget float64 // share of requests without a server error
+	LatencyTarget      float64 // share of requests within the latency threshold
+	LatencyThresholdMs int
+	// MethodLatencyThresholds overrides the threshold per method, e.g.
+	// "ListUsers=500ms,ExportUserData=2s".
+	MethodLatencyThresholds string
+	EvalIntervalSeconds     int
 }
 
 type TracingConfig struct {
@@ -48,6 +335,13 @@ type TracingConfig struct {
 	ServiceName    string
 	ServiceVersion string
 	CollectorURL   string
+	// SampleRatio is the share of traces sampled up front. Dropped traces
+	// that contain an error are exported anyway.
+	SampleRatio float64
+	// URLTemplate links to a trace in the tracing UI, with "{trace_id}" in
+	// place of the ID, e.g. "https://jaeger.example.com/trace/{trace_id}".
+	// Responses carry the link when set.
+	URLTemplate string
 }
 
 func Load() *Config {
@@ -56,33 +350,168 @@ func Load() *Config {
 	config := &Config{
 		Server: ServerConfig{
 			Port:             requireEnv("GRPC_PORT"),
+			HTTPPort:         getEnv("HTTP_PORT", "8080"),
+			GatewayEnabled:   getEnvBool("GATEWAY_ENABLED", true),
+			TLSCertFile:      getEnv("GRPC_TLS_CERT_FILE", ""),
+			TLSKeyFile:       getEnv("GRPC_TLS_KEY_FILE", ""),
+			TLSClientCAFile:  getEnv("GRPC_TLS_CLIENT_CA_FILE", ""),
+			MetricsEnabled:   getEnvBool("METRICS_ENABLED", true),
 			MaxRecvMsgSize:   requireEnvInt("MAX_RECV_MSG_SIZE"),
 			MaxSendMsgSize:   requireEnvInt("MAX_SEND_MSG_SIZE"),
 			EnableReflection: requireEnvBool("ENABLE_REFLECTION"),
+
+			MethodRequestSizeLimits:  getEnv("METHOD_REQUEST_SIZE_LIMITS", ""),
+			MethodResponseSizeLimits: getEnv("METHOD_RESPONSE_SIZE_LIMITS", ""),
+
+			DeadlineReservePercent: getEnvInt("DEADLINE_RESERVE_PERCENT", 20),
+			ExportSigningKey:       getEnv("EXPORT_SIGNING_KEY", ""),
+			EmailChangeTTLMinutes:  getEnvInt("EMAIL_CHANGE_TTL_MINUTES", 60),
+			FaultInjectionEnabled:  getEnvBool("FAULT_INJECTION_ENABLED", false),
+			FaultInjectionRules:    getEnv("FAULT_INJECTION_RULES", ""),
+			AdminHTTPAddr:          getEnv("ADMIN_HTTP_ADDR", "127.0.0.1:8081"),
+			TestStreamEnabled:      getEnvBool("TEST_STREAM_ENABLED", false),
+			RateLimitQPS:           getEnvFloat("RATE_LIMIT_QPS", 0),
+			RateLimitBurst:         getEnvInt("RATE_LIMIT_BURST", 100),
+			ReadOnly:               getEnvBool("READ_ONLY", false),
+			LowPriorityReserve:     getEnvFloat("RATE_LIMIT_LOW_PRIORITY_RESERVE", 0.5),
+			PriorityClasses:        getEnvList("PRIORITY_CLASSES"),
+			DegradedReads:          getEnvBool("DEGRADED_READS_ENABLED", false),
+			DefaultPageSize:        getEnvInt("LIST_DEFAULT_PAGE_SIZE", 10),
+			MaxPageSize:            getEnvInt("LIST_MAX_PAGE_SIZE", 100),
+			UpdateDedupWindowMs:    getEnvInt("UPDATE_DEDUP_WINDOW_MS", 0),
+			HealthCheckIntervalMs:  getEnvInt("HEALTH_CHECK_INTERVAL_MS", 5000),
+			HealthCheckTimeoutMs:   getEnvInt("HEALTH_CHECK_TIMEOUT_MS", 1000),
+
+			ClientRateLimitQPS:        getEnvFloat("CLIENT_RATE_LIMIT_QPS", 0),
+			ClientRateLimitBurst:      getEnvInt("CLIENT_RATE_LIMIT_BURST", 20),
+			ClientRateLimitKey:        getEnv("CLIENT_RATE_LIMIT_KEY", "peer"),
+			ClientRateLimitMaxClients: getEnvInt("CLIENT_RATE_LIMIT_MAX_CLIENTS", 10000),
+
+			ResponseMessagesEnabled: getEnvBool("RESPONSE_MESSAGES_ENABLED", true),
 		},
 		Logger: LoggerConfig{
-			Level:  requireLogLevel("LOG_LEVEL"),
-			Format: requireEnv("LOG_FORMAT"),
+			Level:        requireLogLevel("LOG_LEVEL"),
+			Format:       requireEnv("LOG_FORMAT"),
+			ModuleLevels: getEnvList("LOG_MODULE_LEVELS"),
+
+			SampleFirst:      getEnvInt("LOG_SAMPLE_FIRST", 0),
+			SampleThereafter: getEnvInt("LOG_SAMPLE_THEREAFTER", 100),
+			File:             getEnv("LOG_FILE", ""),
+			FileMaxSizeMB:    getEnvInt("LOG_FILE_MAX_SIZE_MB", 100),
+			FileRotateHours:  getEnvInt("LOG_FILE_ROTATE_HOURS", 24),
+			FileMaxBackups:   getEnvInt("LOG_FILE_MAX_BACKUPS", 7),
+			FileCompress:     getEnvBool("LOG_FILE_COMPRESS", true),
+			RedactKeys:       getEnvList("LOG_REDACT_KEYS"),
+			RedactPatterns:   getEnvList("LOG_REDACT_PATTERNS"),
+			RedactMode:       getEnv("LOG_REDACT_MODE", "mask"),
+			RedactSalt:       getEnv("LOG_REDACT_SALT", ""),
 		},
-		Database: DatabaseConfig{
-			URL:         requireEnv("DATABASE_URL"),
-			MaxConns:    requireEnvInt("DB_MAX_CONNS"),
-			MinConns:    requireEnvInt("DB_MIN_CONNS"),
-			MaxIdleTime: requireEnvInt("DB_MAX_IDLE_TIME"),
-			MaxLifetime: requireEnvInt("DB_MAX_LIFETIME"),
+		Audit: AuditConfig{
+			Sink:           getEnv("AUDIT_LOG_SINK", ""),
+			File:           getEnv("AUDIT_LOG_FILE", "/var/log/rpc-server/audit.log"),
+			FileMaxSizeMB:  getEnvInt("AUDIT_LOG_FILE_MAX_SIZE_MB", 100),
+			FileMaxBackups: getEnvInt("AUDIT_LOG_FILE_MAX_BACKUPS", 0),
+			SyslogNetwork:  getEnv("AUDIT_SYSLOG_NETWORK", ""),
+			SyslogAddress:  getEnv("AUDIT_SYSLOG_ADDRESS", ""),
 		},
+		Database: *LoadDatabase(),
 		Cache: CacheConfig{
-			URL:             requireEnv("CACHE_URL"),
-			MaxConns:        requireEnvInt("CACHE_MAX_CONNS"),
-			MinConns:        requireEnvInt("CACHE_MIN_CONNS"),
-			ConnMaxIdleTime: requireEnvInt("CACHE_MAX_IDLE_TIME"),
-			ConnMaxLifetime: requireEnvInt("CACHE_MAX_LIFETIME"),
+			URL:                       requireEnv("CACHE_URL"),
+			MaxConns:                  requireEnvInt("CACHE_MAX_CONNS"),
+			MinConns:                  requireEnvInt("CACHE_MIN_CONNS"),
+			ConnMaxIdleTime:           requireEnvInt("CACHE_MAX_IDLE_TIME"),
+			ConnMaxLifetime:           requireEnvInt("CACHE_MAX_LIFETIME"),
+			OpTimeoutMs:               getEnvInt("CACHE_OP_TIMEOUT_MS", 50),
+			SlidingTTLSeconds:         getEnvInt("CACHE_SLIDING_TTL_SECONDS", 0),
+			SlidingMaxLifetimeSeconds: getEnvInt("CACHE_SLIDING_MAX_LIFETIME_SECONDS", 86400),
+			UserTTLSeconds:            getEnvInt("CACHE_USER_TTL_SECONDS", 900),
+			ListTTLSeconds:            getEnvInt("CACHE_LIST_TTL_SECONDS", 900),
+			MaxValueBytes:             getEnvInt("CACHE_MAX_VALUE_BYTES", 1<<20),
+			FallbackMaxEntries:        getEnvInt("CACHE_FALLBACK_MAX_ENTRIES", 10000),
+			FallbackMaxDirtyKeys:      getEnvInt("CACHE_FALLBACK_MAX_DIRTY_KEYS", 100000),
+			BreakerFailures:           getEnvInt("CACHE_BREAKER_FAILURES", 5),
+			BreakerCooldownMs:         getEnvInt("CACHE_BREAKER_COOLDOWN_MS", 5000),
+			UserStrategy:              getEnv("CACHE_USER_STRATEGY", "write_through"),
+			ListStrategy:              getEnv("CACHE_LIST_STRATEGY", "cache_aside"),
+			WriteBackFlushMs:          getEnvInt("CACHE_WRITE_BACK_FLUSH_MS", 100),
+			WriteBackMaxPending:       getEnvInt("CACHE_WRITE_BACK_MAX_PENDING", 1000),
+			TenantNamespace:           getEnvBool("CACHE_TENANT_NAMESPACE", true),
+			KeyHMACSecret:             getEnv("CACHE_KEY_HMAC_SECRET", ""),
+			ConnCheckIntervalMs:       getEnvInt("CACHE_CONN_CHECK_INTERVAL_MS", 1000),
+			ReconnectMinBackoffMs:     getEnvInt("CACHE_RECONNECT_MIN_BACKOFF_MS", 100),
+			ReconnectMaxBackoffMs:     getEnvInt("CACHE_RECONNECT_MAX_BACKOFF_MS", 10000),
+			InvalidationBroadcast:     getEnvBool("CACHE_INVALIDATION_BROADCAST", true),
+			HotKeySampleRatio:         getEnvFloat("CACHE_HOT_KEY_SAMPLE_RATIO", 0.01),
+			HotKeyWindowSeconds:       getEnvInt("CACHE_HOT_KEY_WINDOW_SECONDS", 10),
+			HotKeyThreshold:           getEnvFloat("CACHE_HOT_KEY_THRESHOLD", 0),
+			HotKeyTopN:                getEnvInt("CACHE_HOT_KEY_TOP_N", 10),
+			HotKeyMaxTracked:          getEnvInt("CACHE_HOT_KEY_MAX_TRACKED", 10000),
+			HotKeyLocalTTLMs:          getEnvInt("CACHE_HOT_KEY_LOCAL_TTL_MS", 1000),
+			HotKeyExtendTTLSeconds:    getEnvInt("CACHE_HOT_KEY_EXTEND_TTL_SECONDS", 900),
+
+			Mode:       getEnv("CACHE_MODE", "auto"),
+			Username:   getEnv("CACHE_USERNAME", ""),
+			Password:   getEnv("CACHE_PASSWORD", ""),
+			TLSEnabled: getEnvBool("CACHE_TLS_ENABLED", false),
+			TLSCAFile:  getEnv("CACHE_TLS_CA_FILE", ""),
+
+			NegativeTTLSeconds: getEnvInt("CACHE_NEGATIVE_TTL_SECONDS", 30),
+			RaceUserReads:      getEnvBool("CACHE_RACE_USER_READS", false),
 		},
 		Tracing: TracingConfig{
 			Enabled:        requireEnvBool("TRACING_ENABLED"),
 			ServiceName:    requireEnv("TRACING_SERVICE_NAME"),
 			ServiceVersion: requireEnv("TRACING_SERVICE_VERSION"),
 			CollectorURL:   requireEnv("TRACING_COLLECTOR_URL"),
+			SampleRatio:    getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+			URLTemplate:    getEnv("TRACING_URL_TEMPLATE", ""),
+		},
+		Retention: RetentionConfig{
+			InactiveExpiryDays:       getEnvInt("USER_INACTIVE_EXPIRY_DAYS", 0),
+			ExpiryIntervalMinutes:    getEnvInt("USER_EXPIRY_INTERVAL_MINUTES", 60),
+			ExpiryBatchSize:          getEnvInt("USER_EXPIRY_BATCH_SIZE", 100),
+			ExpiryDryRun:             getEnvBool("USER_EXPIRY_DRY_RUN", false),
+			HistoryRetentionDays:     getEnvInt("HISTORY_RETENTION_DAYS", 0),
+			EmailChangeRetentionDays: getEnvInt("EMAIL_CHANGE_RETENTION_DAYS", 0),
+			PruneIntervalMinutes:     getEnvInt("RETENTION_PRUNE_INTERVAL_MINUTES", 60),
+			PruneBatchSize:           getEnvInt("RETENTION_PRUNE_BATCH_SIZE", 1000),
+			PruneMaxBatches:          getEnvInt("RETENTION_PRUNE_MAX_BATCHES", 100),
+		},
+		Capture: CaptureConfig{
+			File:        getEnv("CAPTURE_FILE", ""),
+			SampleRatio: getEnvFloat("CAPTURE_SAMPLE_RATIO", 0.01),
+			Salt:        getEnv("CAPTURE_SALT", ""),
+		},
+		Shadow: ShadowConfig{
+			DatabaseURL:   getEnv("SHADOW_DATABASE_URL", ""),
+			SamplePercent: getEnvFloat("SHADOW_SAMPLE_PERCENT", 1),
+			TimeoutMs:     getEnvInt("SHADOW_TIMEOUT_MS", 2000),
+		},
+		Auth: AuthConfig{
+			JWKSURL:            getEnv("AUTH_JWKS_URL", ""),
+			JWKSRefreshMinutes: getEnvInt("AUTH_JWKS_REFRESH_MINUTES", 15),
+			Issuer:             getEnv("AUTH_ISSUER", ""),
+			Audience:           getEnv("AUTH_AUDIENCE", ""),
+			RolesClaim:         getEnv("AUTH_ROLES_CLAIM", "roles"),
+			TenantClaim:        getEnv("AUTH_TENANT_CLAIM", "tenant"),
+			MethodPermissions: getEnv("AUTH_METHOD_PERMISSIONS",
+				"DeleteUser=admin,EraseUser=admin,MergeUsers=admin,RevertUser=admin,"+
+					"ExportUserData=admin,GetUserAtTime=admin,"+
+					"GetCacheStats=admin,GetRuntimeConfig=admin,SetRuntimeConfig=admin,GetUserAttribution=admin"),
+		},
+		Startup: StartupConfig{
+			TracingTimeoutMs:  getEnvInt("STARTUP_TRACING_TIMEOUT_MS", 5000),
+			DatabaseTimeoutMs: getEnvInt("STARTUP_DATABASE_TIMEOUT_MS", 15000),
+			CacheTimeoutMs:    getEnvInt("STARTUP_CACHE_TIMEOUT_MS", 5000),
+			SchemaDriftMode:   getEnv("SCHEMA_DRIFT_MODE", "warn"),
+		},
+		SLO: SLOConfig{
+			Enabled:                 getEnvBool("SLO_ENABLED", true),
+			AvailabilityTarget:      getEnvFloat("SLO_AVAILABILITY_TARGET", 0.999),
+			LatencyTarget:           getEnvFloat("SLO_LATENCY_TARGET", 0.99),
+			LatencyThresholdMs:      getEnvInt("SLO_LATENCY_THRESHOLD_MS", 300),
+			MethodLatencyThresholds: getEnv("SLO_METHOD_LATENCY_THRESHOLDS", ""),
+			EvalIntervalSeconds:     getEnvInt("SLO_EVAL_INTERVAL_SECONDS", 60),
 		},
 	}
 
@@ -95,6 +524,36 @@ func Load() *Config {
 	return config
 }
 
+// LoadDatabase reads only the database settings, for maintenance subcommands
+// that don't start the server.
+func LoadDatabase() *DatabaseConfig {
+	return &DatabaseConfig{
+		URL:              requireEnv("DATABASE_URL"),
+		ShardURLs:        getEnvList("DATABASE_SHARD_URLS"),
+		MaxConns:         requireEnvInt("DB_MAX_CONNS"),
+		MinConns:         requireEnvInt("DB_MIN_CONNS"),
+		MaxIdleTime:      requireEnvInt("DB_MAX_IDLE_TIME"),
+		MaxLifetime:      requireEnvInt("DB_MAX_LIFETIME"),
+		AcquireTimeoutMs: getEnvInt("DB_ACQUIRE_TIMEOUT_MS", 1000),
+
+		HealthCheckPeriod: getEnvInt("DB_HEALTH_CHECK_PERIOD_SECONDS", 0),
+		PingOnAcquire:     getEnvBool("DB_PING_ON_ACQUIRE", false),
+		ValidationQuery:   getEnv("DB_VALIDATION_QUERY", ""),
+		MaxConnErrors:     getEnvInt("DB_MAX_CONN_ERRORS", 1),
+
+		OutageCheckIntervalMs: getEnvInt("DB_OUTAGE_CHECK_INTERVAL_MS", 2000),
+		FanOutParallelism:     getEnvInt("DB_FANOUT_PARALLELISM", 4),
+		OutageThreshold:       getEnvInt("DB_OUTAGE_THRESHOLD", 3),
+
+		SlowQueryThresholdMs: getEnvInt("DB_SLOW_QUERY_THRESHOLD_MS", 0),
+		ExplainSlowQueries:   getEnvBool("DB_EXPLAIN_SLOW_QUERIES", false),
+		ExplainSampleRatio:   getEnvFloat("DB_EXPLAIN_SAMPLE_RATIO", 0.01),
+		ExplainTimeoutMs:     getEnvInt("DB_EXPLAIN_TIMEOUT_MS", 5000),
+
+		MigrateOnStartup: getEnvBool("DB_MIGRATE_ON_STARTUP", false),
+	}
+}
+
 func requireEnv(key string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -103,6 +562,13 @@ func requireEnv(key string) string {
 	return value
 }
 
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
 func requireEnvInt(key string) int {
 	envVarStr := requireEnv(key)
 	val, err := strconv.Atoi(envVarStr)
@@ -112,6 +578,41 @@ func requireEnvInt(key string) int {
 	return val
 }
 
+func getEnvInt(key string, fallback int) int {
+	envVarStr, ok := os.LookupEnv(key)
+	if !ok || envVarStr == "" {
+		return fallback
+	}
+	val, err := strconv.Atoi(envVarStr)
+	if err != nil {
+		panic(fmt.Sprintf("Environment variable %s must be a valid integer, got: %s", key, envVarStr))
+	}
+	return val
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	envVarStr, ok := os.LookupEnv(key)
+	if !ok || envVarStr == "" {
+		return fallback
+	}
+	val, err := strconv.ParseFloat(envVarStr, 64)
+	if err != nil {
+		panic(fmt.Sprintf("Environment variable %s must be a valid number, got: %s", key, envVarStr))
+	}
+	return val
+}
+
+// getEnvList splits a comma-separated variable, ignoring empty entries.
+func getEnvList(key string) []string {
+	var values []string
+	for value := range strings.SplitSeq(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func requireEnvBool(key string) bool {
 	envVarStr := requireEnv(key)
 	val, err := strconv.ParseBool(envVarStr)
@@ -121,6 +622,18 @@ func requireEnvBool(key string) bool {
 	return val
 }
 
+func getEnvBool(key string, fallback bool) bool {
+	envVarStr, ok := os.LookupEnv(key)
+	if !ok || envVarStr == "" {
+		return fallback
+	}
+	val, err := strconv.ParseBool(envVarStr)
+	if err != nil {
+		panic(fmt.Sprintf("Environment variable %s must be a valid boolean, got: %s", key, envVarStr))
+	}
+	return val
+}
+
 func requireLogLevel(key string) slog.Level {
 	value := requireEnv(key)
 	switch value {
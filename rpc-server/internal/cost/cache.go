@@ -0,0 +1,58 @@
+package cost
+
+import (
+	"context"
+	"time"
+
+	"grpc-server/internal/cache"
+)
+
+// Cache counts every operation on the wrapped cache, and the bytes of values
+// set, against the request's Account.
+type Cache struct {
+	cache cache.Cache
+}
+
+func NewCache(c cache.Cache) *Cache {
+	return &Cache{cache: c}
+}
+
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	AddCacheOps(ctx, 1)
+	return c.cache.Get(ctx, key)
+}
+
+func (c *Cache) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	AddCacheOps(ctx, 1)
+	return c.cache.MGet(ctx, keys...)
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	AddCacheOps(ctx, 1)
+	switch v := value.(type) {
+	case []byte:
+		AddBytes(ctx, int64(len(v)))
+	case string:
+		AddBytes(ctx, int64(len(v)))
+	}
+	return c.cache.Set(ctx, key, value, expiration)
+}
+
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	AddCacheOps(ctx, 1)
+	return c.cache.Delete(ctx, keys...)
+}
+
+func (c *Cache) DeletePattern(ctx context.Context, pattern string) (int, error) {
+	AddCacheOps(ctx, 1)
+	return c.cache.DeletePattern(ctx, pattern)
+}
+
+func (c *Cache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	AddCacheOps(ctx, 1)
+	return c.cache.Expire(ctx, key, expiration)
+}
+
+func (c *Cache) Close() error {
+	return c.cache.Close()
+}
@@ -0,0 +1,144 @@
+// Package cost accounts for the work each request causes: database queries
+// and the rows they read, cache operations and bytes serialized. Totals are
+// reported per call, so expensive callers and methods can be found, and are
+// the basis for quotas by cost rather than by call count.
+package cost
+
+import (
+	"context"
+	"path"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"grpc-server/internal/tracing"
+)
+
+// Account accumulates the cost of one request. It is safe for concurrent
+// use, since a request may fan out.
+type Account struct {
+	dbQueries       atomic.Int64
+	rowsRead        atomic.Int64
+	cacheOps        atomic.Int64
+	bytesSerialized atomic.Int64
+}
+
+type accountKey struct{}
+
+// WithAccount returns a context that collects a new Account.
+func WithAccount(ctx context.Context) (context.Context, *Account) {
+	a := &Account{}
+	return context.WithValue(ctx, accountKey{}, a), a
+}
+
+// fromContext returns the request's Account, or nil outside a request.
+func fromContext(ctx context.Context) *Account {
+	a, _ := ctx.Value(accountKey{}).(*Account)
+	return a
+}
+
+// AddQuery records a database query that read rows rows.
+func AddQuery(ctx context.Context, rows int64) {
+	if a := fromContext(ctx); a != nil {
+		a.dbQueries.Add(1)
+		a.rowsRead.Add(max(rows, 0))
+	}
+}
+
+// AddCacheOps records n cache operations.
+func AddCacheOps(ctx context.Context, n int64) {
+	if a := fromContext(ctx); a != nil {
+		a.cacheOps.Add(n)
+	}
+}
+
+// AddBytes records n bytes serialized.
+func AddBytes(ctx context.Context, n int64) {
+	if a := fromContext(ctx); a != nil {
+		a.bytesSerialized.Add(n)
+	}
+}
+
+// Totals is a snapshot of an Account.
+type Totals struct {
+	DBQueries       int64
+	RowsRead        int64
+	CacheOps        int64
+	BytesSerialized int64
+}
+
+// Totals returns what the account has accumulated so far.
+func (a *Account) Totals() Totals {
+	return Totals{
+		DBQueries:       a.dbQueries.Load(),
+		RowsRead:        a.rowsRead.Load(),
+		CacheOps:        a.cacheOps.Load(),
+		BytesSerialized: a.bytesSerialized.Load(),
+	}
+}
+
+// UnaryServerInterceptor collects an Account for every call, counts the
+// serialized response against it, and reports the totals as "cost.*"
+// attributes on the call's span and as rpc.cost.* histograms by method and
+// caller.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	meter := otel.Meter("rpc-server.rpc/cost")
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	dbQueries, _ := meter.Int64Histogram("rpc.cost.db_queries",
+		metric.WithDescription("Database queries issued per call"),
+		metric.WithUnit("{query}"),
+	)
+	rowsRead, _ := meter.Int64Histogram("rpc.cost.rows_read",
+		metric.WithDescription("Database rows read per call"),
+		metric.WithUnit("{row}"),
+	)
+	cacheOps, _ := meter.Int64Histogram("rpc.cost.cache_ops",
+		metric.WithDescription("Cache operations per call"),
+		metric.WithUnit("{operation}"),
+	)
+	bytesSerialized, _ := meter.Int64Histogram("rpc.cost.bytes_serialized",
+		metric.WithDescription("Bytes serialized per call, for cache values and the response"),
+		metric.WithUnit("By"),
+	)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, account := WithAccount(ctx)
+		resp, err := handler(ctx, req)
+		if msg, ok := resp.(proto.Message); ok && err == nil {
+			AddBytes(ctx, int64(proto.Size(msg)))
+		}
+
+		totals := account.Totals()
+		trace.SpanFromContext(ctx).SetAttributes(
+			attribute.Int64("cost.db_queries", totals.DBQueries),
+			attribute.Int64("cost.rows_read", totals.RowsRead),
+			attribute.Int64("cost.cache_ops", totals.CacheOps),
+			attribute.Int64("cost.bytes_serialized", totals.BytesSerialized),
+		)
+		attrs := metric.WithAttributes(
+			attribute.String("rpc.method", path.Base(info.FullMethod)),
+			attribute.String("caller", caller(ctx)),
+		)
+		dbQueries.Record(ctx, totals.DBQueries, attrs)
+		rowsRead.Record(ctx, totals.RowsRead, attrs)
+		cacheOps.Record(ctx, totals.CacheOps, attrs)
+		bytesSerialized.Record(ctx, totals.BytesSerialized, attrs)
+		return resp, err
+	}
+}
+
+// caller returns the calling tenant, or "unknown".
+func caller(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if tenant := md.Get(tracing.TenantMetadataKey); len(tenant) > 0 && tenant[0] != "" {
+		return tenant[0]
+	}
+	return "unknown"
+}
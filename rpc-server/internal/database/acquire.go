@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrPoolExhausted means no connection became free within the acquire
+// timeout, i.e. the pool is saturated.
+var ErrPoolExhausted = errors.New("database connection pool exhausted")
+
+var errAcquireTimeout = errors.New("connection acquire timed out")
+
+type acquireStateKey struct{}
+
+type acquireState struct {
+	start  time.Time
+	cancel context.CancelFunc
+}
+
+type acquireWatchKey struct{}
+
+// WatchAcquire returns a context that remembers whether a connection acquire
+// made with it ran into the acquire timeout; see AcquireTimedOut.
+func WatchAcquire(ctx context.Context) context.Context {
+	return context.WithValue(ctx, acquireWatchKey{}, new(atomic.Bool))
+}
+
+// AcquireTimedOut reports whether a call with ctx, which must come from
+// WatchAcquire, gave up waiting for a pool connection. The error pgx returns
+// is then a bare context.DeadlineExceeded even though ctx is still live.
+func AcquireTimedOut(ctx context.Context) bool {
+	timedOut, ok := ctx.Value(acquireWatchKey{}).(*atomic.Bool)
+	return ok && timedOut.Load()
+}
+
+type acquireMetrics struct {
+	wait     metric.Float64Histogram
+	timeouts metric.Int64Counter
+}
+
+func newAcquireMetrics() acquireMetrics {
+	meter := otel.Meter("rpc-server.rpc/database")
+	wait, _ := meter.Float64Histogram("db.pool.acquire.duration",
+		metric.WithDescription("Time spent waiting for a pool connection"),
+		metric.WithUnit("ms"),
+	)
+	timeouts, _ := meter.Int64Counter("db.pool.acquire.timeouts",
+		metric.WithDescription("Number of acquires that gave up after the acquire timeout"),
+		metric.WithUnit("{acquire}"),
+	)
+	return acquireMetrics{wait: wait, timeouts: timeouts}
+}
+
+// TraceAcquireStart bounds the wait for a connection by the acquire timeout.
+// pgxpool only uses the returned context for the acquire itself.
+func (t *pgxTracer) TraceAcquireStart(ctx context.Context, pool *pgxpool.Pool, data pgxpool.TraceAcquireStartData) context.Context {
+	state := &acquireState{start: time.Now(), cancel: func() {}}
+	if t.acquireTimeout > 0 {
+		ctx, state.cancel = context.WithTimeoutCause(ctx, t.acquireTimeout, errAcquireTimeout)
+	}
+	return context.WithValue(ctx, acquireStateKey{}, state)
+}
+
+// TraceAcquireEnd records the wait as a span event and a metric.
+func (t *pgxTracer) TraceAcquireEnd(ctx context.Context, pool *pgxpool.Pool, data pgxpool.TraceAcquireEndData) {
+	state, ok := ctx.Value(acquireStateKey{}).(*acquireState)
+	if !ok {
+		return
+	}
+	// Read the cause before cancel overwrites it.
+	timedOut := data.Err != nil && context.Cause(ctx) == errAcquireTimeout
+	state.cancel()
+	wait := float64(time.Since(state.start).Microseconds()) / 1000
+
+	stat := pool.Stat()
+	trace.SpanFromContext(ctx).AddEvent("db.pool.acquire", trace.WithAttributes(
+		attribute.Float64("db.pool.wait_ms", wait),
+		attribute.Bool("db.pool.timed_out", timedOut),
+		attribute.Int("db.pool.acquired_conns", int(stat.AcquiredConns())),
+		attribute.Int("db.pool.max_conns", int(stat.MaxConns())),
+	))
+	t.acquire.wait.Record(ctx, wait, metric.WithAttributes(attribute.Bool("error", data.Err != nil)))
+
+	if timedOut {
+		t.acquire.timeouts.Add(ctx, 1)
+		if watch, ok := ctx.Value(acquireWatchKey{}).(*atomic.Bool); ok {
+			watch.Store(true)
+		}
+	}
+}
@@ -16,10 +16,14 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"grpc-server/internal/config"
+	"grpc-server/internal/cost"
+	"grpc-server/internal/timing"
 )
 
 type spanContextKey struct{}
 
+type queryTimerKey struct{}
+
 func Connect(ctx context.Context, cfg *config.DatabaseConfig) (*pgxpool.Pool, error) {
 	slog.Info("Connecting to database with connection pool", "url", maskPassword(cfg.URL))
 
@@ -34,15 +38,35 @@ func Connect(ctx context.Context, cfg *config.DatabaseConfig) (*pgxpool.Pool, er
 	poolConfig.MaxConnLifetime = time.Duration(cfg.MaxLifetime) * time.Second
 	poolConfig.MaxConnIdleTime = time.Duration(cfg.MaxIdleTime) * time.Second
 
-	// Add OpenTelemetry tracing
+	if cfg.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = time.Duration(cfg.HealthCheckPeriod) * time.Second
+	}
+	health := newConnHealth(cfg.PingOnAcquire, cfg.ValidationQuery, cfg.MaxConnErrors)
+	health.configure(poolConfig)
+
+	// Add OpenTelemetry tracing; the tracer also enforces the acquire timeout,
+	// reports query errors to the health checks and reports slow queries
+	slow := newSlowQueries(
+		time.Duration(cfg.SlowQueryThresholdMs)*time.Millisecond,
+		cfg.ExplainSlowQueries,
+		cfg.ExplainSampleRatio,
+		time.Duration(cfg.ExplainTimeoutMs)*time.Millisecond,
+	)
 	poolConfig.ConnConfig.Tracer = &pgxTracer{
-		tracer: otel.Tracer("rpc-server.rpc/database"),
+		tracer:         otel.Tracer("rpc-server.rpc/database"),
+		acquire:        newAcquireMetrics(),
+		acquireTimeout: time.Duration(cfg.AcquireTimeoutMs) * time.Millisecond,
+		health:         health,
+		slow:           slow,
 	}
 
-	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	// The pool keeps this context for opening its MinConns connections in
+	// the background, so a startup timeout on ctx must not cancel them
+	pool, err := pgxpool.NewWithConfig(context.WithoutCancel(ctx), poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create database connection pool: %w", err)
 	}
+	slow.pool.Store(pool)
 
 	if err := pool.Ping(ctx); err != nil {
 		pool.Close()
@@ -51,13 +75,23 @@ func Connect(ctx context.Context, cfg *config.DatabaseConfig) (*pgxpool.Pool, er
 
 	slog.Info("Database connection pool established successfully",
 		"max_conns", poolConfig.MaxConns,
-		"min_conns", poolConfig.MinConns)
+		"min_conns", poolConfig.MinConns,
+		"acquire_timeout_ms", cfg.AcquireTimeoutMs,
+		"ping_on_acquire", cfg.PingOnAcquire,
+		"validation_query", cfg.ValidationQuery != "",
+		"slow_query_threshold_ms", cfg.SlowQueryThresholdMs,
+		"explain_slow_queries", cfg.ExplainSlowQueries)
 	return pool, nil
 }
 
-// pgxTracer implements pgx tracing interface
+// pgxTracer implements the pgx query tracer and pgxpool acquire tracer
+// interfaces
 type pgxTracer struct {
-	tracer trace.Tracer
+	tracer         trace.Tracer
+	acquire        acquireMetrics
+	acquireTimeout time.Duration // 0 waits for a connection as long as ctx allows
+	health         *connHealth
+	slow           *slowQueries
 }
 
 func (t *pgxTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
@@ -69,10 +103,21 @@ func (t *pgxTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pg
 			attribute.String("db.statement", data.SQL),
 		),
 	)
+	ctx = context.WithValue(ctx, queryTimerKey{}, timing.Track(ctx, timing.StageDatabase))
+	ctx = t.slow.start(ctx, data)
 	return context.WithValue(ctx, spanContextKey{}, span)
 }
 
 func (t *pgxTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	if stop, ok := ctx.Value(queryTimerKey{}).(func()); ok {
+		stop()
+	}
+	var rowsRead int64
+	if data.Err == nil && data.CommandTag.Select() {
+		rowsRead = data.CommandTag.RowsAffected()
+	}
+	cost.AddQuery(ctx, rowsRead)
+	t.slow.end(ctx, data)
 	span, ok := ctx.Value(spanContextKey{}).(trace.Span)
 	if !ok {
 		return
@@ -80,6 +125,7 @@ func (t *pgxTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.
 	defer span.End()
 
 	if data.Err != nil {
+		t.health.recordError(conn, data.Err)
 		span.SetStatus(codes.Error, data.Err.Error())
 		span.RecordError(data.Err)
 		return
@@ -111,3 +157,12 @@ func maskPassword(rawURL string) string {
 
 	return masked
 }
+
+// ServerVersion returns the PostgreSQL server's version string.
+func ServerVersion(ctx context.Context, pool *pgxpool.Pool) (string, error) {
+	var version string
+	if err := pool.QueryRow(ctx, "SELECT version()").Scan(&version); err != nil {
+		return "", fmt.Errorf("failed to read database version: %w", err)
+	}
+	return version, nil
+}
@@ -8,11 +8,46 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type EmailChange struct {
+	UserID      pgtype.UUID        `json:"user_id"`
+	NewEmail    string             `json:"new_email"`
+	TokenHash   []byte             `json:"token_hash"`
+	RequestedAt pgtype.Timestamptz `json:"requested_at"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	TenantID    string             `json:"tenant_id"`
+}
+
 type User struct {
-	ID        pgtype.UUID        `json:"id"`
-	Name      string             `json:"name"`
-	Email     string             `json:"email"`
-	Age       int32              `json:"age"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	ID         pgtype.UUID        `json:"id"`
+	Name       string             `json:"name"`
+	Email      string             `json:"email"`
+	Age        int32              `json:"age"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	Status     string             `json:"status"`
+	MergedInto pgtype.UUID        `json:"merged_into"`
+	CreatedBy  string             `json:"created_by"`
+	UpdatedBy  string             `json:"updated_by"`
+	TenantID   string             `json:"tenant_id"`
+}
+
+type UserEmail struct {
+	Email    string      `json:"email"`
+	UserID   pgtype.UUID `json:"user_id"`
+	TenantID string      `json:"tenant_id"`
+}
+
+type UserHistory struct {
+	HistoryID  int64              `json:"history_id"`
+	UserID     pgtype.UUID        `json:"user_id"`
+	Name       string             `json:"name"`
+	Email      string             `json:"email"`
+	Age        int32              `json:"age"`
+	Status     string             `json:"status"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	ValidFrom  pgtype.Timestamptz `json:"valid_from"`
+	ValidTo    pgtype.Timestamptz `json:"valid_to"`
+	MergedInto pgtype.UUID        `json:"merged_into"`
+	TenantID   string             `json:"tenant_id"`
 }
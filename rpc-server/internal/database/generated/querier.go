@@ -11,13 +11,36 @@ import (
 )
 
 type Querier interface {
+	// user_emails answers from one index instead of probing every users partition.
 	CheckEmailExists(ctx context.Context, arg CheckEmailExistsParams) (bool, error)
 	CountUsers(ctx context.Context) (int64, error)
+	CountUsersByPrefix(ctx context.Context, arg CountUsersByPrefixParams) (int64, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteEmailChange(ctx context.Context, userID pgtype.UUID) error
 	DeleteUser(ctx context.Context, id pgtype.UUID) error
+	DeleteUserHistory(ctx context.Context, userID pgtype.UUID) error
+	ExpireUser(ctx context.Context, arg ExpireUserParams) (int64, error)
+	GetEmailChange(ctx context.Context, userID pgtype.UUID) (EmailChange, error)
 	GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
+	GetUserVersion(ctx context.Context, arg GetUserVersionParams) (UserHistory, error)
+	GetUserVersionAt(ctx context.Context, arg GetUserVersionAtParams) (UserHistory, error)
+	// Rows come back in no particular order; callers order them.
+	GetUsersByIDs(ctx context.Context, ids []pgtype.UUID) ([]User, error)
+	ListInactiveUsers(ctx context.Context, arg ListInactiveUsersParams) ([]User, error)
+	ListUserHistory(ctx context.Context, userID pgtype.UUID) ([]UserHistory, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	LockEmailChange(ctx context.Context, userID pgtype.UUID) (EmailChange, error)
+	LockUsers(ctx context.Context, ids []pgtype.UUID) ([]User, error)
+	MergeUser(ctx context.Context, arg MergeUserParams) (User, error)
+	PruneEmailChanges(ctx context.Context, arg PruneEmailChangesParams) (int64, error)
+	PruneUserHistory(ctx context.Context, arg PruneUserHistoryParams) (int64, error)
+	RevertUser(ctx context.Context, arg RevertUserParams) (User, error)
+	// Patterns are lowercase LIKE patterns ending in %, matched by the trigram
+	// indexes on lower(name) and lower(email).
+	SearchUsersByPrefix(ctx context.Context, arg SearchUsersByPrefixParams) ([]User, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error)
+	UpsertEmailChange(ctx context.Context, arg UpsertEmailChangeParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -13,18 +13,19 @@ import (
 
 const checkEmailExists = `-- name: CheckEmailExists :one
 SELECT EXISTS(
-    SELECT 1 FROM users 
-    WHERE email = $1 AND id != $2
+    SELECT 1 FROM user_emails
+    WHERE email = $1 AND user_id != $2
 ) as exists
 `
 
 type CheckEmailExistsParams struct {
-	Email string      `json:"email"`
-	ID    pgtype.UUID `json:"id"`
+	Email  string      `json:"email"`
+	UserID pgtype.UUID `json:"user_id"`
 }
 
+// user_emails answers from one index instead of probing every users partition.
 func (q *Queries) CheckEmailExists(ctx context.Context, arg CheckEmailExistsParams) (bool, error) {
-	row := q.db.QueryRow(ctx, checkEmailExists, arg.Email, arg.ID)
+	row := q.db.QueryRow(ctx, checkEmailExists, arg.Email, arg.UserID)
 	var exists bool
 	err := row.Scan(&exists)
 	return exists, err
@@ -32,6 +33,7 @@ func (q *Queries) CheckEmailExists(ctx context.Context, arg CheckEmailExistsPara
 
 const countUsers = `-- name: CountUsers :one
 SELECT COUNT(*) FROM users
+WHERE status <> 'merged'
 `
 
 func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
@@ -41,10 +43,29 @@ func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countUsersByPrefix = `-- name: CountUsersByPrefix :one
+SELECT COUNT(*) FROM users
+WHERE status <> 'merged'
+  AND lower(name) LIKE $1::text
+  AND lower(email) LIKE $2::text
+`
+
+type CountUsersByPrefixParams struct {
+	NamePattern  string `json:"name_pattern"`
+	EmailPattern string `json:"email_pattern"`
+}
+
+func (q *Queries) CountUsersByPrefix(ctx context.Context, arg CountUsersByPrefixParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsersByPrefix, arg.NamePattern, arg.EmailPattern)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, name, email, age, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, name, email, age, created_at, updated_at
+INSERT INTO users (id, name, email, age, created_at, updated_at, created_by, updated_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, name, email, age, created_at, updated_at, status, merged_into, created_by, updated_by, tenant_id
 `
 
 type CreateUserParams struct {
@@ -54,6 +75,8 @@ type CreateUserParams struct {
 	Age       int32              `json:"age"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	CreatedBy string             `json:"created_by"`
+	UpdatedBy string             `json:"updated_by"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -64,6 +87,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.Age,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.CreatedBy,
+		arg.UpdatedBy,
 	)
 	var i User
 	err := row.Scan(
@@ -73,10 +98,25 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Age,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.MergedInto,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.TenantID,
 	)
 	return i, err
 }
 
+const deleteEmailChange = `-- name: DeleteEmailChange :exec
+DELETE FROM email_changes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteEmailChange(ctx context.Context, userID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteEmailChange, userID)
+	return err
+}
+
 const deleteUser = `-- name: DeleteUser :exec
 DELETE FROM users 
 WHERE id = $1
@@ -87,8 +127,57 @@ func (q *Queries) DeleteUser(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
+const deleteUserHistory = `-- name: DeleteUserHistory :exec
+DELETE FROM user_history
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserHistory(ctx context.Context, userID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUserHistory, userID)
+	return err
+}
+
+const expireUser = `-- name: ExpireUser :execrows
+UPDATE users
+SET status = 'expired', updated_by = $3
+WHERE id = $1 AND status = 'active' AND updated_at < $2
+`
+
+type ExpireUserParams struct {
+	ID        pgtype.UUID        `json:"id"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	UpdatedBy string             `json:"updated_by"`
+}
+
+func (q *Queries) ExpireUser(ctx context.Context, arg ExpireUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, expireUser, arg.ID, arg.UpdatedAt, arg.UpdatedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getEmailChange = `-- name: GetEmailChange :one
+SELECT user_id, new_email, token_hash, requested_at, expires_at, tenant_id FROM email_changes
+WHERE user_id = $1
+`
+
+func (q *Queries) GetEmailChange(ctx context.Context, userID pgtype.UUID) (EmailChange, error) {
+	row := q.db.QueryRow(ctx, getEmailChange, userID)
+	var i EmailChange
+	err := row.Scan(
+		&i.UserID,
+		&i.NewEmail,
+		&i.TokenHash,
+		&i.RequestedAt,
+		&i.ExpiresAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, name, email, age, created_at, updated_at FROM users 
+SELECT id, name, email, age, created_at, updated_at, status, merged_into, created_by, updated_by, tenant_id FROM users 
 WHERE id = $1
 `
 
@@ -102,12 +191,201 @@ func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
 		&i.Age,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.MergedInto,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const getUserVersion = `-- name: GetUserVersion :one
+SELECT history_id, user_id, name, email, age, status, created_at, updated_at, valid_from, valid_to, merged_into, tenant_id FROM user_history
+WHERE history_id = $1 AND user_id = $2
+`
+
+type GetUserVersionParams struct {
+	HistoryID int64       `json:"history_id"`
+	UserID    pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetUserVersion(ctx context.Context, arg GetUserVersionParams) (UserHistory, error) {
+	row := q.db.QueryRow(ctx, getUserVersion, arg.HistoryID, arg.UserID)
+	var i UserHistory
+	err := row.Scan(
+		&i.HistoryID,
+		&i.UserID,
+		&i.Name,
+		&i.Email,
+		&i.Age,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ValidFrom,
+		&i.ValidTo,
+		&i.MergedInto,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const getUserVersionAt = `-- name: GetUserVersionAt :one
+SELECT history_id, user_id, name, email, age, status, created_at, updated_at, valid_from, valid_to, merged_into, tenant_id FROM user_history
+WHERE user_id = $1 AND valid_from <= $2 AND (valid_to IS NULL OR valid_to > $2)
+ORDER BY valid_from DESC
+LIMIT 1
+`
+
+type GetUserVersionAtParams struct {
+	UserID    pgtype.UUID        `json:"user_id"`
+	ValidFrom pgtype.Timestamptz `json:"valid_from"`
+}
+
+func (q *Queries) GetUserVersionAt(ctx context.Context, arg GetUserVersionAtParams) (UserHistory, error) {
+	row := q.db.QueryRow(ctx, getUserVersionAt, arg.UserID, arg.ValidFrom)
+	var i UserHistory
+	err := row.Scan(
+		&i.HistoryID,
+		&i.UserID,
+		&i.Name,
+		&i.Email,
+		&i.Age,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ValidFrom,
+		&i.ValidTo,
+		&i.MergedInto,
+		&i.TenantID,
 	)
 	return i, err
 }
 
+const getUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, name, email, age, created_at, updated_at, status, merged_into, created_by, updated_by, tenant_id FROM users
+WHERE id = ANY($1::uuid[])
+`
+
+// Rows come back in no particular order; callers order them.
+func (q *Queries) GetUsersByIDs(ctx context.Context, ids []pgtype.UUID) ([]User, error) {
+	rows, err := q.db.Query(ctx, getUsersByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Age,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.MergedInto,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInactiveUsers = `-- name: ListInactiveUsers :many
+SELECT id, name, email, age, created_at, updated_at, status, merged_into, created_by, updated_by, tenant_id FROM users
+WHERE status = 'active' AND updated_at < $1
+ORDER BY updated_at
+LIMIT $2
+`
+
+type ListInactiveUsersParams struct {
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	Limit     int32              `json:"limit"`
+}
+
+func (q *Queries) ListInactiveUsers(ctx context.Context, arg ListInactiveUsersParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listInactiveUsers, arg.UpdatedAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Age,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.MergedInto,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserHistory = `-- name: ListUserHistory :many
+SELECT history_id, user_id, name, email, age, status, created_at, updated_at, valid_from, valid_to, merged_into, tenant_id FROM user_history
+WHERE user_id = $1
+ORDER BY valid_from, history_id
+`
+
+func (q *Queries) ListUserHistory(ctx context.Context, userID pgtype.UUID) ([]UserHistory, error) {
+	rows, err := q.db.Query(ctx, listUserHistory, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserHistory{}
+	for rows.Next() {
+		var i UserHistory
+		if err := rows.Scan(
+			&i.HistoryID,
+			&i.UserID,
+			&i.Name,
+			&i.Email,
+			&i.Age,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ValidFrom,
+			&i.ValidTo,
+			&i.MergedInto,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsers = `-- name: ListUsers :many
-SELECT id, name, email, age, created_at, updated_at FROM users 
+SELECT id, name, email, age, created_at, updated_at, status, merged_into, created_by, updated_by, tenant_id FROM users 
+WHERE status <> 'merged'
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -133,6 +411,255 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.Age,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.MergedInto,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockEmailChange = `-- name: LockEmailChange :one
+SELECT user_id, new_email, token_hash, requested_at, expires_at, tenant_id FROM email_changes
+WHERE user_id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockEmailChange(ctx context.Context, userID pgtype.UUID) (EmailChange, error) {
+	row := q.db.QueryRow(ctx, lockEmailChange, userID)
+	var i EmailChange
+	err := row.Scan(
+		&i.UserID,
+		&i.NewEmail,
+		&i.TokenHash,
+		&i.RequestedAt,
+		&i.ExpiresAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const lockUsers = `-- name: LockUsers :many
+SELECT id, name, email, age, created_at, updated_at, status, merged_into, created_by, updated_by, tenant_id FROM users
+WHERE id = ANY($1::uuid[])
+ORDER BY id
+FOR UPDATE
+`
+
+func (q *Queries) LockUsers(ctx context.Context, ids []pgtype.UUID) ([]User, error) {
+	rows, err := q.db.Query(ctx, lockUsers, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Age,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.MergedInto,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const mergeUser = `-- name: MergeUser :one
+UPDATE users
+SET status = 'merged', merged_into = $2, updated_at = $3, updated_by = $4
+WHERE id = $1
+RETURNING id, name, email, age, created_at, updated_at, status, merged_into, created_by, updated_by, tenant_id
+`
+
+type MergeUserParams struct {
+	ID         pgtype.UUID        `json:"id"`
+	MergedInto pgtype.UUID        `json:"merged_into"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	UpdatedBy  string             `json:"updated_by"`
+}
+
+func (q *Queries) MergeUser(ctx context.Context, arg MergeUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, mergeUser,
+		arg.ID,
+		arg.MergedInto,
+		arg.UpdatedAt,
+		arg.UpdatedBy,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Age,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.MergedInto,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const pruneEmailChanges = `-- name: PruneEmailChanges :execrows
+DELETE FROM email_changes
+WHERE user_id IN (
+    SELECT user_id FROM email_changes
+    WHERE expires_at < $1
+    ORDER BY expires_at
+    LIMIT $2
+)
+`
+
+type PruneEmailChangesParams struct {
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	Limit     int32              `json:"limit"`
+}
+
+func (q *Queries) PruneEmailChanges(ctx context.Context, arg PruneEmailChangesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneEmailChanges, arg.ExpiresAt, arg.Limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const pruneUserHistory = `-- name: PruneUserHistory :execrows
+DELETE FROM user_history
+WHERE history_id IN (
+    SELECT history_id FROM user_history
+    WHERE valid_to < $1
+    ORDER BY valid_to
+    LIMIT $2
+)
+`
+
+type PruneUserHistoryParams struct {
+	ValidTo pgtype.Timestamptz `json:"valid_to"`
+	Limit   int32              `json:"limit"`
+}
+
+func (q *Queries) PruneUserHistory(ctx context.Context, arg PruneUserHistoryParams) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneUserHistory, arg.ValidTo, arg.Limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revertUser = `-- name: RevertUser :one
+UPDATE users
+SET name = $2, email = $3, age = $4, status = $5, merged_into = $6, updated_at = $7, updated_by = $8
+WHERE id = $1
+RETURNING id, name, email, age, created_at, updated_at, status, merged_into, created_by, updated_by, tenant_id
+`
+
+type RevertUserParams struct {
+	ID         pgtype.UUID        `json:"id"`
+	Name       string             `json:"name"`
+	Email      string             `json:"email"`
+	Age        int32              `json:"age"`
+	Status     string             `json:"status"`
+	MergedInto pgtype.UUID        `json:"merged_into"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	UpdatedBy  string             `json:"updated_by"`
+}
+
+func (q *Queries) RevertUser(ctx context.Context, arg RevertUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, revertUser,
+		arg.ID,
+		arg.Name,
+		arg.Email,
+		arg.Age,
+		arg.Status,
+		arg.MergedInto,
+		arg.UpdatedAt,
+		arg.UpdatedBy,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Age,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.MergedInto,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const searchUsersByPrefix = `-- name: SearchUsersByPrefix :many
+SELECT id, name, email, age, created_at, updated_at, status, merged_into, created_by, updated_by, tenant_id FROM users
+WHERE status <> 'merged'
+  AND lower(name) LIKE $1::text
+  AND lower(email) LIKE $2::text
+ORDER BY created_at DESC
+LIMIT $4 OFFSET $3
+`
+
+type SearchUsersByPrefixParams struct {
+	NamePattern  string `json:"name_pattern"`
+	EmailPattern string `json:"email_pattern"`
+	RowOffset    int32  `json:"row_offset"`
+	RowLimit     int32  `json:"row_limit"`
+}
+
+// Patterns are lowercase LIKE patterns ending in %, matched by the trigram
+// indexes on lower(name) and lower(email).
+func (q *Queries) SearchUsersByPrefix(ctx context.Context, arg SearchUsersByPrefixParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, searchUsersByPrefix,
+		arg.NamePattern,
+		arg.EmailPattern,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Age,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.MergedInto,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.TenantID,
 		); err != nil {
 			return nil, err
 		}
@@ -146,9 +673,9 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 
 const updateUser = `-- name: UpdateUser :one
 UPDATE users 
-SET name = $2, email = $3, age = $4, updated_at = $5
+SET name = $2, email = $3, age = $4, updated_at = $5, updated_by = $6
 WHERE id = $1
-RETURNING id, name, email, age, created_at, updated_at
+RETURNING id, name, email, age, created_at, updated_at, status, merged_into, created_by, updated_by, tenant_id
 `
 
 type UpdateUserParams struct {
@@ -157,6 +684,7 @@ type UpdateUserParams struct {
 	Email     string             `json:"email"`
 	Age       int32              `json:"age"`
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	UpdatedBy string             `json:"updated_by"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
@@ -166,6 +694,45 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.Email,
 		arg.Age,
 		arg.UpdatedAt,
+		arg.UpdatedBy,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Age,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.MergedInto,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const updateUserEmail = `-- name: UpdateUserEmail :one
+UPDATE users
+SET email = $2, updated_at = $3, updated_by = $4
+WHERE id = $1
+RETURNING id, name, email, age, created_at, updated_at, status, merged_into, created_by, updated_by, tenant_id
+`
+
+type UpdateUserEmailParams struct {
+	ID        pgtype.UUID        `json:"id"`
+	Email     string             `json:"email"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	UpdatedBy string             `json:"updated_by"`
+}
+
+func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserEmail,
+		arg.ID,
+		arg.Email,
+		arg.UpdatedAt,
+		arg.UpdatedBy,
 	)
 	var i User
 	err := row.Scan(
@@ -175,6 +742,40 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Age,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.MergedInto,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.TenantID,
 	)
 	return i, err
 }
+
+const upsertEmailChange = `-- name: UpsertEmailChange :exec
+INSERT INTO email_changes (user_id, new_email, token_hash, requested_at, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE
+SET new_email = EXCLUDED.new_email,
+    token_hash = EXCLUDED.token_hash,
+    requested_at = EXCLUDED.requested_at,
+    expires_at = EXCLUDED.expires_at
+`
+
+type UpsertEmailChangeParams struct {
+	UserID      pgtype.UUID        `json:"user_id"`
+	NewEmail    string             `json:"new_email"`
+	TokenHash   []byte             `json:"token_hash"`
+	RequestedAt pgtype.Timestamptz `json:"requested_at"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) UpsertEmailChange(ctx context.Context, arg UpsertEmailChangeParams) error {
+	_, err := q.db.Exec(ctx, upsertEmailChange,
+		arg.UserID,
+		arg.NewEmail,
+		arg.TokenHash,
+		arg.RequestedAt,
+		arg.ExpiresAt,
+	)
+	return err
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// connHealth keeps connections that went stale, typically after a failover,
+// from being handed out again.
+type connHealth struct {
+	pingOnAcquire   bool
+	validationQuery string
+	maxErrors       int // 0 never discards connections for their errors
+
+	// errors counts connection-level errors per pooled connection.
+	mu     sync.Mutex
+	errors map[*pgx.Conn]int
+
+	discarded metric.Int64Counter
+}
+
+func newConnHealth(pingOnAcquire bool, validationQuery string, maxErrors int) *connHealth {
+	discarded, _ := otel.Meter("rpc-server.rpc/database").Int64Counter("db.pool.conns.discarded",
+		metric.WithDescription("Number of pooled connections discarded as unhealthy"),
+		metric.WithUnit("{connection}"),
+	)
+	return &connHealth{
+		pingOnAcquire:   pingOnAcquire,
+		validationQuery: validationQuery,
+		maxErrors:       maxErrors,
+		errors:          make(map[*pgx.Conn]int),
+		discarded:       discarded,
+	}
+}
+
+// configure installs the health hooks on a pool configuration.
+func (h *connHealth) configure(cfg *pgxpool.Config) {
+	if h.pingOnAcquire || h.validationQuery != "" {
+		cfg.BeforeAcquire = h.beforeAcquire
+	}
+	cfg.AfterRelease = h.afterRelease
+	cfg.BeforeClose = h.forget
+}
+
+// beforeAcquire validates a connection before it is handed out; false makes
+// the pool destroy it and try another.
+func (h *connHealth) beforeAcquire(ctx context.Context, conn *pgx.Conn) bool {
+	if h.validationQuery != "" {
+		var ok bool
+		if err := conn.QueryRow(ctx, h.validationQuery).Scan(&ok); err != nil || !ok {
+			h.discard(ctx, "validation_failed", err)
+			return false
+		}
+		return true
+	}
+	if err := conn.Ping(ctx); err != nil {
+		h.discard(ctx, "ping_failed", err)
+		return false
+	}
+	return true
+}
+
+// afterRelease destroys connections that reached the error limit.
+func (h *connHealth) afterRelease(conn *pgx.Conn) bool {
+	if h.maxErrors <= 0 {
+		return true
+	}
+	h.mu.Lock()
+	count := h.errors[conn]
+	h.mu.Unlock()
+	if count < h.maxErrors {
+		return true
+	}
+	h.discard(context.Background(), "too_many_errors", nil)
+	return false
+}
+
+func (h *connHealth) forget(conn *pgx.Conn) {
+	h.mu.Lock()
+	delete(h.errors, conn)
+	h.mu.Unlock()
+}
+
+// recordError counts err against conn if it suggests the connection itself,
+// rather than the query, is broken.
+func (h *connHealth) recordError(conn *pgx.Conn, err error) {
+	if h.maxErrors <= 0 || conn == nil || !connectionError(err) {
+		return
+	}
+	h.mu.Lock()
+	h.errors[conn]++
+	h.mu.Unlock()
+}
+
+func (h *connHealth) discard(ctx context.Context, reason string, err error) {
+	h.discarded.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason)))
+	slog.WarnContext(ctx, "Discarding unhealthy database connection", "reason", reason, "error", err)
+}
+
+// connectionError reports whether err means the connection should not be
+// trusted again: connection exceptions (08), operator intervention such as
+// an administrator shutdown (57P), writes rejected by a server that was
+// demoted to a read-only replica (25006), and network errors.
+func connectionError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "57P") || pgErr.Code == "25006"
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
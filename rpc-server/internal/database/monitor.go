@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Monitor pings the database periodically and tracks whether it is
+// reachable. It reports an outage only after Threshold consecutive failed
+// pings, so a single slow ping does not flip the server into degraded mode,
+// and recovers on the first successful one.
+type Monitor struct {
+	ping      func(ctx context.Context) error
+	interval  time.Duration
+	threshold int
+
+	failures int // consecutive failed pings; only touched by check
+	healthy  atomic.Bool
+}
+
+// NewMonitor creates a Monitor that starts out healthy. A threshold below 1
+// is treated as 1.
+func NewMonitor(ping func(ctx context.Context) error, interval time.Duration, threshold int) *Monitor {
+	m := &Monitor{ping: ping, interval: interval, threshold: max(threshold, 1)}
+	m.healthy.Store(true)
+	return m
+}
+
+// Healthy reports whether the database answered the recent pings.
+func (m *Monitor) Healthy() bool {
+	return m.healthy.Load()
+}
+
+// Run pings the database every interval until ctx is cancelled.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+func (m *Monitor) check(ctx context.Context) {
+	// A ping that outlives the interval counts as failed.
+	pingCtx, cancel := context.WithTimeout(ctx, m.interval)
+	err := m.ping(pingCtx)
+	cancel()
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err == nil {
+		m.failures = 0
+		if !m.healthy.Swap(true) {
+			slog.InfoContext(ctx, "Database reachable again")
+		}
+		return
+	}
+	m.failures++
+	if m.failures >= m.threshold && m.healthy.Swap(false) {
+		slog.ErrorContext(ctx, "Database unreachable", "failed_pings", m.failures, "error", err)
+	}
+}
@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type queryStartKey struct{}
+
+// queryStart is what TraceQueryStart remembers for the slow query check.
+type queryStart struct {
+	at   time.Time
+	sql  string
+	args []any
+}
+
+// explainKey marks the queries run to capture a plan, so they are neither
+// reported as slow nor explained themselves.
+type explainKey struct{}
+
+// slowQueries logs statements slower than threshold and, if enabled,
+// captures a sample of their plans with EXPLAIN (ANALYZE, BUFFERS).
+type slowQueries struct {
+	threshold   time.Duration // 0 disables the check
+	explain     bool
+	sampleRatio float64
+	timeout     time.Duration
+	// pool runs the EXPLAINs; it is set once the pool the tracer belongs to
+	// has been created.
+	pool atomic.Pointer[pgxpool.Pool]
+	// busy lets one plan capture run at a time; slow queries seen meanwhile
+	// are not explained, so a slow database is not loaded further.
+	busy atomic.Bool
+
+	slow     metric.Int64Counter
+	captured metric.Int64Counter
+}
+
+func newSlowQueries(threshold time.Duration, explain bool, sampleRatio float64, timeout time.Duration) *slowQueries {
+	meter := otel.Meter("rpc-server.rpc/database")
+	slow, _ := meter.Int64Counter("db.query.slow",
+		metric.WithDescription("Number of queries slower than the slow query threshold"),
+		metric.WithUnit("{query}"),
+	)
+	captured, _ := meter.Int64Counter("db.query.plans_captured",
+		metric.WithDescription("Number of slow query plans captured with EXPLAIN ANALYZE"),
+		metric.WithUnit("{plan}"),
+	)
+	return &slowQueries{
+		threshold:   threshold,
+		explain:     explain,
+		sampleRatio: sampleRatio,
+		timeout:     timeout,
+		slow:        slow,
+		captured:    captured,
+	}
+}
+
+// start returns ctx remembering when the query began.
+func (q *slowQueries) start(ctx context.Context, data pgx.TraceQueryStartData) context.Context {
+	if q.threshold <= 0 || ctx.Value(explainKey{}) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, queryStartKey{}, queryStart{at: time.Now(), sql: data.SQL, args: data.Args})
+}
+
+// end reports the query started in ctx if it was slow.
+func (q *slowQueries) end(ctx context.Context, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok || data.Err != nil {
+		return
+	}
+	elapsed := time.Since(start.at)
+	if elapsed < q.threshold {
+		return
+	}
+	q.slow.Add(ctx, 1)
+	slog.WarnContext(ctx, "Slow database query", "duration_ms", elapsed.Milliseconds(), "statement", start.sql)
+
+	// EXPLAIN ANALYZE executes the statement again, so only reads are
+	// explained.
+	if !q.explain || !data.CommandTag.Select() || rand.Float64() >= q.sampleRatio {
+		return
+	}
+	pool := q.pool.Load()
+	if pool == nil || !q.busy.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer q.busy.Store(false)
+		// The plan is captured after the request may have finished; keep its
+		// trace for correlation but not its deadline.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), q.timeout)
+		defer cancel()
+		ctx = context.WithValue(ctx, explainKey{}, true)
+		plan, err := explain(ctx, pool, start.sql, start.args)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to capture slow query plan", "statement", start.sql, "error", err)
+			return
+		}
+		q.captured.Add(ctx, 1)
+		slog.InfoContext(ctx, "Captured slow query plan",
+			"duration_ms", elapsed.Milliseconds(),
+			"statement", start.sql,
+			"plan", plan,
+		)
+	}()
+}
+
+// explain runs EXPLAIN (ANALYZE, BUFFERS) for sql with args in a read-only
+// transaction that is rolled back, and returns the plan as text.
+func explain(ctx context.Context, pool *pgxpool.Pool, sql string, args []any) (string, error) {
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	// The plan depends on which rows the query may see.
+	if err := SetTenant(ctx, tx); err != nil {
+		return "", err
+	}
+	rows, err := tx.Query(ctx, "EXPLAIN (ANALYZE, BUFFERS) "+sql, args...)
+	if err != nil {
+		return "", err
+	}
+	lines, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", fmt.Errorf("failed to read plan: %w", err)
+	}
+	return strings.Join(lines, "\n"), nil
+}
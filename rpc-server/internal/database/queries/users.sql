@@ -1,15 +1,20 @@
 -- name: CreateUser :one
-INSERT INTO users (id, name, email, age, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO users (id, name, email, age, created_at, updated_at, created_by, updated_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
 -- name: GetUserByID :one
 SELECT * FROM users 
 WHERE id = $1;
 
+-- name: GetUsersByIDs :many
+-- Rows come back in no particular order; callers order them.
+SELECT * FROM users
+WHERE id = ANY(@ids::uuid[]);
+
 -- name: UpdateUser :one
 UPDATE users 
-SET name = $2, email = $3, age = $4, updated_at = $5
+SET name = $2, email = $3, age = $4, updated_at = $5, updated_by = $6
 WHERE id = $1
 RETURNING *;
 
@@ -19,14 +24,127 @@ WHERE id = $1;
 
 -- name: ListUsers :many
 SELECT * FROM users 
+WHERE status <> 'merged'
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2;
 
 -- name: CountUsers :one
-SELECT COUNT(*) FROM users;
+SELECT COUNT(*) FROM users
+WHERE status <> 'merged';
+
+-- name: SearchUsersByPrefix :many
+-- Patterns are lowercase LIKE patterns ending in %, matched by the trigram
+-- indexes on lower(name) and lower(email).
+SELECT * FROM users
+WHERE status <> 'merged'
+  AND lower(name) LIKE sqlc.arg(name_pattern)::text
+  AND lower(email) LIKE sqlc.arg(email_pattern)::text
+ORDER BY created_at DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountUsersByPrefix :one
+SELECT COUNT(*) FROM users
+WHERE status <> 'merged'
+  AND lower(name) LIKE sqlc.arg(name_pattern)::text
+  AND lower(email) LIKE sqlc.arg(email_pattern)::text;
 
 -- name: CheckEmailExists :one
+-- user_emails answers from one index instead of probing every users partition.
 SELECT EXISTS(
-    SELECT 1 FROM users 
-    WHERE email = $1 AND id != $2
-) as exists;
\ No newline at end of file
+    SELECT 1 FROM user_emails
+    WHERE email = $1 AND user_id != $2
+) as exists;
+
+-- name: ListInactiveUsers :many
+SELECT * FROM users
+WHERE status = 'active' AND updated_at < $1
+ORDER BY updated_at
+LIMIT $2;
+
+-- name: ExpireUser :execrows
+UPDATE users
+SET status = 'expired', updated_by = $3
+WHERE id = $1 AND status = 'active' AND updated_at < $2;
+
+-- name: GetUserVersionAt :one
+SELECT * FROM user_history
+WHERE user_id = $1 AND valid_from <= $2 AND (valid_to IS NULL OR valid_to > $2)
+ORDER BY valid_from DESC
+LIMIT 1;
+
+-- name: ListUserHistory :many
+SELECT * FROM user_history
+WHERE user_id = $1
+ORDER BY valid_from, history_id;
+
+-- name: DeleteUserHistory :exec
+DELETE FROM user_history
+WHERE user_id = $1;
+
+-- name: PruneUserHistory :execrows
+DELETE FROM user_history
+WHERE history_id IN (
+    SELECT history_id FROM user_history
+    WHERE valid_to < $1
+    ORDER BY valid_to
+    LIMIT $2
+);
+
+-- name: GetUserVersion :one
+SELECT * FROM user_history
+WHERE history_id = $1 AND user_id = $2;
+
+-- name: RevertUser :one
+UPDATE users
+SET name = $2, email = $3, age = $4, status = $5, merged_into = $6, updated_at = $7, updated_by = $8
+WHERE id = $1
+RETURNING *;
+
+-- name: LockUsers :many
+SELECT * FROM users
+WHERE id = ANY(@ids::uuid[])
+ORDER BY id
+FOR UPDATE;
+
+-- name: MergeUser :one
+UPDATE users
+SET status = 'merged', merged_into = $2, updated_at = $3, updated_by = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: UpsertEmailChange :exec
+INSERT INTO email_changes (user_id, new_email, token_hash, requested_at, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE
+SET new_email = EXCLUDED.new_email,
+    token_hash = EXCLUDED.token_hash,
+    requested_at = EXCLUDED.requested_at,
+    expires_at = EXCLUDED.expires_at;
+
+-- name: GetEmailChange :one
+SELECT * FROM email_changes
+WHERE user_id = $1;
+
+-- name: LockEmailChange :one
+SELECT * FROM email_changes
+WHERE user_id = $1
+FOR UPDATE;
+
+-- name: DeleteEmailChange :exec
+DELETE FROM email_changes
+WHERE user_id = $1;
+
+-- name: PruneEmailChanges :execrows
+DELETE FROM email_changes
+WHERE user_id IN (
+    SELECT user_id FROM email_changes
+    WHERE expires_at < $1
+    ORDER BY expires_at
+    LIMIT $2
+);
+
+-- name: UpdateUserEmail :one
+UPDATE users
+SET email = $2, updated_at = $3, updated_by = $4
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,175 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+
+	generated "grpc-server/internal/database/generated"
+)
+
+// Schema drift modes: what the server does when the live users table differs
+// from what this build expects.
+const (
+	DriftModeOff  = "off"
+	DriftModeWarn = "warn"
+	DriftModeFail = "fail"
+)
+
+// UsersSchema is the shape of the users table.
+type UsersSchema struct {
+	Columns     []string
+	Indexes     []string
+	Constraints []string
+}
+
+// ExpectedUsersSchema returns what the migrations shipped with this build
+// create. Columns come from the sqlc model, so regenerating it after a
+// migration keeps them in step; indexes and constraints are listed by hand.
+func ExpectedUsersSchema() UsersSchema {
+	var columns []string
+	model := reflect.TypeFor[generated.User]()
+	for i := range model.NumField() {
+		column, _, _ := strings.Cut(model.Field(i).Tag.Get("json"), ",")
+		columns = append(columns, column)
+	}
+	return UsersSchema{
+		Columns: columns,
+		Indexes: []string{
+			"users_pkey",
+			"idx_users_email",
+			"idx_users_created_at",
+			"idx_users_status_updated_at",
+			"idx_users_name_trgm",
+			"idx_users_email_trgm",
+			"idx_users_tenant_id_created_at",
+		},
+		Constraints: []string{
+			"users_pkey",
+			"users_age_check",
+			"users_status_check",
+			"users_merged_into_check",
+			"users_merged_into_fkey",
+		},
+	}
+}
+
+// LiveUsersSchema reads the users table in the connection's current schema.
+func LiveUsersSchema(ctx context.Context, pool *pgxpool.Pool) (UsersSchema, error) {
+	var live UsersSchema
+	queries := []struct {
+		what string
+		sql  string
+		dst  *[]string
+	}{
+		{"columns", "SELECT column_name FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'users'", &live.Columns},
+		{"indexes", "SELECT indexname FROM pg_indexes WHERE schemaname = current_schema() AND tablename = 'users'", &live.Indexes},
+		{"constraints", "SELECT conname FROM pg_constraint WHERE conrelid = to_regclass('users') AND contype <> 'n'", &live.Constraints},
+	}
+	for _, q := range queries {
+		rows, err := pool.Query(ctx, q.sql)
+		if err != nil {
+			return live, fmt.Errorf("failed to read users %s: %w", q.what, err)
+		}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return live, fmt.Errorf("failed to read users %s: %w", q.what, err)
+			}
+			*q.dst = append(*q.dst, name)
+		}
+		if err := rows.Err(); err != nil {
+			return live, fmt.Errorf("failed to read users %s: %w", q.what, err)
+		}
+	}
+	return live, nil
+}
+
+// SchemaDrift lists the differences between the expected and the live users
+// table. Missing objects usually mean a migration was not applied; extra ones
+// that a newer build or a manual change added them.
+type SchemaDrift struct {
+	MissingColumns     []string
+	ExtraColumns       []string
+	MissingIndexes     []string
+	ExtraIndexes       []string
+	MissingConstraints []string
+	ExtraConstraints   []string
+}
+
+// CompareUsersSchema returns how live differs from expected.
+func CompareUsersSchema(expected, live UsersSchema) SchemaDrift {
+	var drift SchemaDrift
+	drift.MissingColumns, drift.ExtraColumns = difference(expected.Columns, live.Columns)
+	drift.MissingIndexes, drift.ExtraIndexes = difference(expected.Indexes, live.Indexes)
+	drift.MissingConstraints, drift.ExtraConstraints = difference(expected.Constraints, live.Constraints)
+	return drift
+}
+
+// difference returns the names only in expected and those only in live.
+func difference(expected, live []string) (missing, extra []string) {
+	for _, name := range expected {
+		if !slices.Contains(live, name) {
+			missing = append(missing, name)
+		}
+	}
+	for _, name := range live {
+		if !slices.Contains(expected, name) {
+			extra = append(extra, name)
+		}
+	}
+	return missing, extra
+}
+
+// Count returns the number of differences.
+func (d SchemaDrift) Count() int {
+	return len(d.MissingColumns) + len(d.ExtraColumns) +
+		len(d.MissingIndexes) + len(d.ExtraIndexes) +
+		len(d.MissingConstraints) + len(d.ExtraConstraints)
+}
+
+// CheckUsersSchema compares the live users table with ExpectedUsersSchema,
+// logs any drift and records it on the db.schema.drift gauge. In
+// DriftModeFail drift is returned as an error; in DriftModeOff nothing is
+// checked.
+func CheckUsersSchema(ctx context.Context, pool *pgxpool.Pool, mode string) error {
+	if mode == DriftModeOff {
+		return nil
+	}
+	live, err := LiveUsersSchema(ctx, pool)
+	if err != nil {
+		return err
+	}
+	drift := CompareUsersSchema(ExpectedUsersSchema(), live)
+
+	gauge, _ := otel.Meter("rpc-server.rpc/database").Int64Gauge("db.schema.drift",
+		metric.WithDescription("Differences between the expected and the live users table at startup"),
+		metric.WithUnit("{difference}"),
+	)
+	gauge.Record(ctx, int64(drift.Count()))
+
+	if drift.Count() == 0 {
+		slog.InfoContext(ctx, "Database schema matches the expected users table")
+		return nil
+	}
+	slog.WarnContext(ctx, "Database schema drift detected on users",
+		"missing_columns", drift.MissingColumns,
+		"extra_columns", drift.ExtraColumns,
+		"missing_indexes", drift.MissingIndexes,
+		"extra_indexes", drift.ExtraIndexes,
+		"missing_constraints", drift.MissingConstraints,
+		"extra_constraints", drift.ExtraConstraints,
+	)
+	if mode == DriftModeFail {
+		return fmt.Errorf("users table differs from the expected schema in %d places; apply pending migrations or set SCHEMA_DRIFT_MODE=warn", drift.Count())
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grpc-server/internal/tenant"
+)
+
+// SetTenant confines the rest of tx to the tenant of ctx, through the
+// settings the row-level security policies check. Both are set every time,
+// so nothing depends on what earlier transactions on the connection left.
+func SetTenant(ctx context.Context, tx pgx.Tx) error {
+	id, all := tenant.FromContext(ctx)
+	allTenants := "off"
+	if all {
+		allTenants = "on"
+	}
+	_, err := tx.Exec(ctx, "SELECT set_config('app.tenant_id', $1, true), set_config('app.all_tenants', $2, true)", id, allTenants)
+	return err
+}
+
+// BypassesRowSecurity reports whether the pool's role skips row-level
+// security. Superusers and roles with BYPASSRLS see the rows of every tenant
+// whatever SetTenant set.
+func BypassesRowSecurity(ctx context.Context, pool *pgxpool.Pool) (bool, error) {
+	var bypasses bool
+	err := pool.QueryRow(ctx, "SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").Scan(&bypasses)
+	return bypasses, err
+}
@@ -0,0 +1,59 @@
+package deadline
+
+import (
+	"context"
+	"time"
+
+	"grpc-server/internal/cache"
+)
+
+// Cache bounds every operation on the wrapped cache by Budget.Cache.
+type Cache struct {
+	cache  cache.Cache
+	budget Budget
+}
+
+// NewCache wraps c so no single cache call can outlive its budget.
+func NewCache(c cache.Cache, budget Budget) *Cache {
+	return &Cache{cache: c, budget: budget}
+}
+
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	ctx, cancel := c.budget.Cache(ctx)
+	defer cancel()
+	return c.cache.Get(ctx, key)
+}
+
+func (c *Cache) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	ctx, cancel := c.budget.Cache(ctx)
+	defer cancel()
+	return c.cache.MGet(ctx, keys...)
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	ctx, cancel := c.budget.Cache(ctx)
+	defer cancel()
+	return c.cache.Set(ctx, key, value, expiration)
+}
+
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	ctx, cancel := c.budget.Cache(ctx)
+	defer cancel()
+	return c.cache.Delete(ctx, keys...)
+}
+
+func (c *Cache) DeletePattern(ctx context.Context, pattern string) (int, error) {
+	ctx, cancel := c.budget.Cache(ctx)
+	defer cancel()
+	return c.cache.DeletePattern(ctx, pattern)
+}
+
+func (c *Cache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	ctx, cancel := c.budget.Cache(ctx)
+	defer cancel()
+	return c.cache.Expire(ctx, key, expiration)
+}
+
+func (c *Cache) Close() error {
+	return c.cache.Close()
+}
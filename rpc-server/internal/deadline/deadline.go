@@ -0,0 +1,80 @@
+package deadline
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"grpc-server/internal/database"
+)
+
+// ErrBudgetExhausted is returned instead of starting a dependency call when the
+// caller's remaining deadline is too short for it to finish.
+var ErrBudgetExhausted = errors.New("deadline budget exhausted")
+
+// Budget splits the incoming request deadline between dependencies.
+type Budget struct {
+	// Reserve is the fraction of the remaining time held back for response
+	// handling and serialization (0.2 keeps 20%).
+	Reserve float64
+	// CacheTimeout caps a single cache operation; a slow cache should fall
+	// through to the database rather than eat the whole budget.
+	CacheTimeout time.Duration
+	// MinBudget is the smallest slice worth starting a database call with.
+	MinBudget time.Duration
+}
+
+func (b Budget) available(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(float64(time.Until(deadline)) * (1 - b.Reserve)), true
+}
+
+// Database derives the context for a database call. Without an incoming
+// deadline only the pool acquire watch is added.
+func (b Budget) Database(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	ctx = database.WatchAcquire(ctx)
+	budget, ok := b.available(ctx)
+	if !ok {
+		return ctx, func() {}, nil
+	}
+	if budget < b.MinBudget {
+		return ctx, func() {}, ErrBudgetExhausted
+	}
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	return ctx, cancel, nil
+}
+
+// Cache derives the context for a cache call: CacheTimeout, shortened further
+// if the request budget is smaller.
+func (b Budget) Cache(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := b.CacheTimeout
+	if budget, ok := b.available(ctx); ok && (timeout <= 0 || budget < timeout) {
+		timeout = budget
+	}
+	if timeout <= 0 {
+		if _, ok := ctx.Deadline(); ok {
+			return context.WithTimeout(ctx, 0)
+		}
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// exceeded wraps err so callers can tell a budget timeout or a saturated
+// connection pool apart from a genuine dependency failure, even when the
+// dependency masks the context error.
+func exceeded(ctx context.Context, err error) error {
+	if err != nil && database.AcquireTimedOut(ctx) {
+		return errors.Join(database.ErrPoolExhausted, err)
+	}
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	if errors.Is(err, ErrBudgetExhausted) {
+		return err
+	}
+	return errors.Join(ErrBudgetExhausted, ctx.Err(), err)
+}
@@ -0,0 +1,217 @@
+package deadline
+
+import (
+	"context"
+	"time"
+
+	"grpc-server/internal/models"
+	"grpc-server/internal/repository"
+)
+
+// UserRepository runs every call on the wrapped repository within
+// Budget.Database, failing fast with ErrBudgetExhausted when the caller has
+// too little time left for the query to be worth starting.
+type UserRepository struct {
+	repo   repository.UserRepository
+	budget Budget
+}
+
+// NewUserRepository wraps repo with deadline budgeting.
+func NewUserRepository(repo repository.UserRepository, budget Budget) *UserRepository {
+	return &UserRepository{repo: repo, budget: budget}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return exceeded(ctx, r.repo.Create(ctx, user))
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	user, err := r.repo.GetByID(ctx, id)
+	return user, exceeded(ctx, err)
+}
+
+func (r *UserRepository) BatchGet(ctx context.Context, ids []string) ([]*models.User, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	users, err := r.repo.BatchGet(ctx, ids)
+	return users, exceeded(ctx, err)
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return exceeded(ctx, r.repo.Update(ctx, user))
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return exceeded(ctx, r.repo.Delete(ctx, id))
+}
+
+func (r *UserRepository) List(ctx context.Context, offset, limit int) ([]*models.User, int, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cancel()
+	users, total, err := r.repo.List(ctx, offset, limit)
+	return users, total, exceeded(ctx, err)
+}
+
+func (r *UserRepository) SearchByPrefix(ctx context.Context, filter repository.PrefixFilter, offset, limit int) ([]*models.User, int, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cancel()
+	users, total, err := r.repo.SearchByPrefix(ctx, filter, offset, limit)
+	return users, total, exceeded(ctx, err)
+}
+
+func (r *UserRepository) EmailExists(ctx context.Context, email string, excludeID string) (bool, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer cancel()
+	exists, err := r.repo.EmailExists(ctx, email, excludeID)
+	return exists, exceeded(ctx, err)
+}
+
+func (r *UserRepository) ListInactive(ctx context.Context, cutoff time.Time, limit int) ([]*models.User, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	users, err := r.repo.ListInactive(ctx, cutoff, limit)
+	return users, exceeded(ctx, err)
+}
+
+func (r *UserRepository) Expire(ctx context.Context, id string, cutoff time.Time) (bool, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer cancel()
+	expired, err := r.repo.Expire(ctx, id, cutoff)
+	return expired, exceeded(ctx, err)
+}
+
+func (r *UserRepository) GetAt(ctx context.Context, id string, at time.Time) (*models.UserVersion, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	version, err := r.repo.GetAt(ctx, id, at)
+	return version, exceeded(ctx, err)
+}
+
+func (r *UserRepository) History(ctx context.Context, id string) ([]*models.UserVersion, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	versions, err := r.repo.History(ctx, id)
+	return versions, exceeded(ctx, err)
+}
+
+func (r *UserRepository) PruneHistory(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer cancel()
+	pruned, err := r.repo.PruneHistory(ctx, cutoff, limit)
+	return pruned, exceeded(ctx, err)
+}
+
+func (r *UserRepository) Erase(ctx context.Context, id string) error {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return exceeded(ctx, r.repo.Erase(ctx, id))
+}
+
+func (r *UserRepository) Revert(ctx context.Context, id string, versionID int64) (*models.User, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	user, err := r.repo.Revert(ctx, id, versionID)
+	return user, exceeded(ctx, err)
+}
+
+func (r *UserRepository) Merge(ctx context.Context, sourceID, targetID string, policy models.MergePolicy) (*models.User, *models.User, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cancel()
+	target, source, err := r.repo.Merge(ctx, sourceID, targetID, policy)
+	return target, source, exceeded(ctx, err)
+}
+
+func (r *UserRepository) RequestEmailChange(ctx context.Context, change *models.EmailChange) error {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return exceeded(ctx, r.repo.RequestEmailChange(ctx, change))
+}
+
+func (r *UserRepository) PendingEmailChange(ctx context.Context, id string) (*models.EmailChange, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	change, err := r.repo.PendingEmailChange(ctx, id)
+	return change, exceeded(ctx, err)
+}
+
+func (r *UserRepository) ConfirmEmailChange(ctx context.Context, id, token string) (*models.User, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	user, err := r.repo.ConfirmEmailChange(ctx, id, token)
+	return user, exceeded(ctx, err)
+}
+
+func (r *UserRepository) PruneEmailChanges(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	ctx, cancel, err := r.budget.Database(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer cancel()
+	pruned, err := r.repo.PruneEmailChanges(ctx, cutoff, limit)
+	return pruned, exceeded(ctx, err)
+}
@@ -0,0 +1,137 @@
+// Package dedup collapses concurrent identical writes, such as client retries
+// of the same request, into one. The first caller claims a key in the cache
+// and does the write; callers arriving while it runs wait for its result
+// instead of writing again. Callers arriving after it finished write again,
+// since other writes may have happened in between.
+package dedup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"grpc-server/internal/cache"
+	"grpc-server/internal/logging"
+)
+
+// Store is a cache that can claim keys atomically, e.g. *cache.ValkeyCache.
+type Store interface {
+	cache.Cache
+	SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error)
+}
+
+// Claimed keys hold pendingMarker while the write runs, then resultPrefix and
+// the result for resultGrace, long enough for waiting callers to pick it up.
+var (
+	pendingMarker = []byte("pending")
+	resultPrefix  = []byte("done:")
+)
+
+// pollInterval is how often waiting callers check for the result.
+const pollInterval = 25 * time.Millisecond
+
+// resultGrace is how long a result stays published. Serving it any longer
+// would answer a later call with a stale result: of updates to A, then B,
+// then A again, the third would return the first's response without
+// writing, leaving B stored.
+const resultGrace = 4 * pollInterval
+
+// Deduplicator runs one write at a time per key.
+type Deduplicator struct {
+	store     Store
+	window    time.Duration
+	logger    *logging.Logger
+	collapsed metric.Int64Counter
+}
+
+// New returns a Deduplicator that collapses calls with the same key made
+// while the first runs, for up to window. The window should outlast the
+// write.
+func New(store Store, window time.Duration, base *slog.Logger) *Deduplicator {
+	collapsed, _ := otel.Meter("rpc-server.rpc/dedup").Int64Counter("dedup.collapsed",
+		metric.WithDescription("Number of writes answered with the result of an identical concurrent write"),
+		metric.WithUnit("{request}"),
+	)
+	return &Deduplicator{
+		store:     store,
+		window:    window,
+		logger:    logging.New(base.With("component", "dedup")),
+		collapsed: collapsed,
+	}
+}
+
+// Do calls fn unless a call with the same key is running, in which case it
+// waits for that call and returns its result with shared true.
+// If the first call fails, a waiting caller makes its own attempt. Without
+// the cache, fn is always called.
+func (d *Deduplicator) Do(ctx context.Context, operation, key string, fn func(ctx context.Context) ([]byte, error)) (result []byte, shared bool, err error) {
+	for {
+		claimed, err := d.store.SetNX(ctx, key, pendingMarker, d.window)
+		if err != nil {
+			d.logger.WarnCtx(ctx, "Deduplication unavailable, writing anyway", logging.CacheKey, key, logging.Error, err)
+			result, err := fn(ctx)
+			return result, false, err
+		}
+		if claimed {
+			return d.run(ctx, key, fn)
+		}
+
+		result, err = d.wait(ctx, key)
+		switch {
+		case err == nil:
+			d.collapsed.Add(ctx, 1, metric.WithAttributes(attribute.String("operation", operation)))
+			return result, true, nil
+		case errors.Is(err, cache.ErrCacheMiss):
+			// The first call failed, outlasted the window, or finished
+			// before this one saw its result; try to claim
+			continue
+		case ctx.Err() != nil:
+			return nil, false, err
+		}
+		d.logger.WarnCtx(ctx, "Deduplication unavailable, writing anyway", logging.CacheKey, key, logging.Error, err)
+		result, err = fn(ctx)
+		return result, false, err
+	}
+}
+
+// run calls fn for a claimed key and publishes its result to waiting callers.
+func (d *Deduplicator) run(ctx context.Context, key string, fn func(ctx context.Context) ([]byte, error)) ([]byte, bool, error) {
+	result, err := fn(ctx)
+	if err != nil {
+		if err := d.store.Delete(context.WithoutCancel(ctx), key); err != nil {
+			d.logger.WarnCtx(ctx, "Failed to release deduplication key", logging.CacheKey, key, logging.Error, err)
+		}
+		return nil, false, err
+	}
+	if err := d.store.Set(ctx, key, append(bytes.Clone(resultPrefix), result...), resultGrace); err != nil {
+		d.logger.WarnCtx(ctx, "Failed to store deduplicated result", logging.CacheKey, key, logging.Error, err)
+	}
+	return result, false, nil
+}
+
+// wait polls key until the claimant stores its result. It returns
+// cache.ErrCacheMiss once the key is gone.
+func (d *Deduplicator) wait(ctx context.Context, key string) ([]byte, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		value, err := d.store.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if result, ok := bytes.CutPrefix(value, resultPrefix); ok {
+			return result, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package dedup
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"grpc-server/internal/cache/cachetest"
+	"grpc-server/internal/clock"
+)
+
+const window = 2 * time.Second
+
+func newDeduplicator() (*Deduplicator, *clock.Fake) {
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	return New(cachetest.New(clk), window, slog.New(slog.DiscardHandler)), clk
+}
+
+func TestDoCollapsesConcurrentCalls(t *testing.T) {
+	d, _ := newDeduplicator()
+	ctx := context.Background()
+	var calls atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+
+	first := make(chan []byte)
+	go func() {
+		result, _, _ := d.Do(ctx, "test", "k", func(ctx context.Context) ([]byte, error) {
+			calls.Add(1)
+			close(started)
+			<-release
+			return []byte("A"), nil
+		})
+		first <- result
+	}()
+	<-started
+
+	second := make(chan bool)
+	go func() {
+		result, shared, err := d.Do(ctx, "test", "k", func(ctx context.Context) ([]byte, error) {
+			calls.Add(1)
+			return []byte("second"), nil
+		})
+		second <- shared && err == nil && string(result) == "A"
+	}()
+	time.Sleep(2 * pollInterval)
+	close(release)
+
+	if got := string(<-first); got != "A" {
+		t.Fatalf("first call returned %q, want A", got)
+	}
+	if !<-second {
+		t.Fatal("second call did not share the first call's result")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("fn ran %d times, want 1", n)
+	}
+}
+
+// A call identical to one that already finished must write again: other
+// writes may have happened in between.
+func TestDoWritesAgainAfterTheFirstFinished(t *testing.T) {
+	d, clk := newDeduplicator()
+	ctx := context.Background()
+	stored := ""
+	update := func(value string) func(context.Context) ([]byte, error) {
+		return func(context.Context) ([]byte, error) {
+			stored = value
+			return []byte(value), nil
+		}
+	}
+
+	for _, step := range []struct{ key, value string }{
+		{"k:A", "A"},
+		{"k:B", "B"},
+		{"k:A", "A"},
+	} {
+		result, shared, err := d.Do(ctx, "test", step.key, update(step.value))
+		if err != nil || shared || string(result) != step.value {
+			t.Fatalf("update to %s = %q, shared %v, %v; want a write of its own", step.value, result, shared, err)
+		}
+		// Well within the window, but after waiters had their chance
+		clk.Advance(resultGrace)
+	}
+	if stored != "A" {
+		t.Fatalf("stored %q after updates to A, B and A", stored)
+	}
+}
+
+func TestDoRetriesAfterFailedFirstCall(t *testing.T) {
+	d, _ := newDeduplicator()
+	ctx := context.Background()
+	if _, _, err := d.Do(ctx, "test", "k", func(context.Context) ([]byte, error) {
+		return nil, context.DeadlineExceeded
+	}); err == nil {
+		t.Fatal("failed call returned no error")
+	}
+	result, shared, err := d.Do(ctx, "test", "k", func(context.Context) ([]byte, error) {
+		return []byte("ok"), nil
+	})
+	if err != nil || shared || string(result) != "ok" {
+		t.Fatalf("retry = %q, shared %v, %v; want a write of its own", result, shared, err)
+	}
+}
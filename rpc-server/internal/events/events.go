@@ -0,0 +1,99 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"grpc-server/internal/logging"
+)
+
+// Event types emitted by the user service.
+const (
+	TypeUserErased  = "user.erased"
+	TypeUserExpired = "user.expired"
+	TypeUserMerged  = "user.merged"
+
+	TypeEmailChangeRequested = "user.email_change_requested"
+	TypeEmailChanged         = "user.email_changed"
+)
+
+// DataConfirmationToken is the Data key of the secret an email change
+// notifier delivers to the new address. LogPublisher does not log its value.
+const DataConfirmationToken = "confirmation_token"
+
+// Event is a domain event about a single user.
+type Event struct {
+	ID         string
+	Type       string
+	UserID     string
+	OccurredAt time.Time
+	Data       map[string]string
+	// TraceContext holds the W3C trace context headers of the span the
+	// event belongs to, so it survives being stored and published later.
+	TraceContext map[string]string
+}
+
+// New returns an event of type for userID with a fresh ID and timestamp,
+// carrying the trace context of ctx.
+func New(ctx context.Context, eventType, userID string, data map[string]string) Event {
+	return Event{
+		ID:           uuid.New().String(),
+		Type:         eventType,
+		UserID:       userID,
+		OccurredAt:   time.Now(),
+		Data:         data,
+		TraceContext: traceContext(ctx),
+	}
+}
+
+// traceContext returns the propagation headers for the span in ctx, or nil
+// if there is none.
+func traceContext(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Publisher delivers events to downstream consumers.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// LogPublisher writes events to the application log. It is the default until
+// a message broker is wired in.
+type LogPublisher struct {
+	logger *logging.Logger
+}
+
+func NewLogPublisher(base *slog.Logger) *LogPublisher {
+	return &LogPublisher{logger: logging.New(base)}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event Event) error {
+	p.logger.InfoCtx(ctx, "Event published",
+		"event_id", event.ID,
+		"event_type", event.Type,
+		logging.UserID, event.UserID,
+		"occurred_at", event.OccurredAt,
+		"data", redact(event.Data),
+	)
+	return nil
+}
+
+func redact(data map[string]string) map[string]string {
+	if _, ok := data[DataConfirmationToken]; !ok {
+		return data
+	}
+	redacted := maps.Clone(data)
+	redacted[DataConfirmationToken] = "[REDACTED]"
+	return redacted
+}
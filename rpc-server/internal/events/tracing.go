@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingPublisher publishes every event in a producer span linked to the
+// trace the event was created in. Consumers receive the producer span's
+// context in Event.TraceContext, so creation, publishing and consumption are
+// connected even when events are published long after the request, e.g. by
+// an outbox relay.
+type TracingPublisher struct {
+	publisher Publisher
+	tracer    trace.Tracer
+}
+
+func NewTracingPublisher(publisher Publisher) *TracingPublisher {
+	return &TracingPublisher{publisher: publisher, tracer: otel.Tracer("rpc-server.rpc/events")}
+}
+
+func (p *TracingPublisher) Publish(ctx context.Context, event Event) error {
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.operation", "publish"),
+			attribute.String("messaging.message.id", event.ID),
+			attribute.String("event.type", event.Type),
+		),
+	}
+	origin := trace.SpanContextFromContext(otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(event.TraceContext)))
+	if origin.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: origin}))
+	}
+	ctx, span := p.tracer.Start(ctx, "events.publish "+event.Type, opts...)
+	defer span.End()
+
+	event.TraceContext = traceContext(ctx)
+	if err := p.publisher.Publish(ctx, event); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
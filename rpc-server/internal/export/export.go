@@ -0,0 +1,130 @@
+package export
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"grpc-server/internal/models"
+)
+
+// FormatVersion is bumped whenever Document changes incompatibly.
+const FormatVersion = 1
+
+// Algorithm identifies how Signer signs documents.
+const Algorithm = "HMAC-SHA256"
+
+// Document is the data-portability export for one user: the current profile
+// and every recorded past version. New user data stores must be added here.
+type Document struct {
+	FormatVersion int       `json:"format_version"`
+	ExportedAt    time.Time `json:"exported_at"`
+	// TimeZone is the IANA zone every timestamp in the document is written
+	// in, chosen by the requester.
+	TimeZone string    `json:"time_zone"`
+	Profile  Profile   `json:"profile"`
+	History  []Version `json:"history"`
+	// PendingEmailChange is nil unless an email change awaits confirmation.
+	PendingEmailChange *EmailChange `json:"pending_email_change"`
+}
+
+type Profile struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	Age        int32     `json:"age"`
+	Status     string    `json:"status"`
+	MergedInto string    `json:"merged_into,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Version is a past or current profile and the period it was valid for.
+type Version struct {
+	Profile
+	ValidFrom time.Time  `json:"valid_from"`
+	ValidTo   *time.Time `json:"valid_to"` // null for the current version
+}
+
+func newProfile(user *models.User, loc *time.Location) Profile {
+	return Profile{
+		ID:         user.ID,
+		Name:       user.Name,
+		Email:      user.Email,
+		Age:        user.Age,
+		Status:     user.Status,
+		MergedInto: user.MergedInto,
+		CreatedAt:  user.CreatedAt.In(loc),
+		UpdatedAt:  user.UpdatedAt.In(loc),
+	}
+}
+
+// EmailChange is an unconfirmed change of the user's email address.
+type EmailChange struct {
+	NewEmail    string    `json:"new_email"`
+	RequestedAt time.Time `json:"requested_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// NewDocument builds the export for user, its history and its pending email
+// change, which may be nil. Timestamps are written in loc; they carry their
+// UTC offset, so the instants are the same whatever loc is.
+func NewDocument(user *models.User, history []*models.UserVersion, change *models.EmailChange, exportedAt time.Time, loc *time.Location) Document {
+	doc := Document{
+		FormatVersion: FormatVersion,
+		ExportedAt:    exportedAt.In(loc),
+		TimeZone:      loc.String(),
+		Profile:       newProfile(user, loc),
+		History:       make([]Version, 0, len(history)),
+	}
+	for _, v := range history {
+		version := Version{Profile: newProfile(&v.User, loc), ValidFrom: v.ValidFrom.In(loc)}
+		if !v.ValidTo.IsZero() {
+			validTo := v.ValidTo.In(loc)
+			version.ValidTo = &validTo
+		}
+		doc.History = append(doc.History, version)
+	}
+	if change != nil {
+		doc.PendingEmailChange = &EmailChange{
+			NewEmail:    change.NewEmail,
+			RequestedAt: change.RequestedAt.In(loc),
+			ExpiresAt:   change.ExpiresAt.In(loc),
+		}
+	}
+	return doc
+}
+
+// Marshal encodes d as indented JSON, the exact bytes that get signed.
+func (d Document) Marshal() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// Signer signs export documents so recipients can prove they are unaltered.
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// Sign returns the base64-encoded signature of payload.
+func (s *Signer) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature matches payload.
+func (s *Signer) Verify(payload []byte, signature string) bool {
+	got, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,487 @@
+package gateway_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"grpc-server/internal/cache/cachetest"
+	"grpc-server/internal/clock"
+	"grpc-server/internal/gateway"
+	"grpc-server/internal/repository/memory"
+	"grpc-server/internal/server"
+	pb "grpc-server/pkg/pb"
+)
+
+// The contract tests check the HTTP contract of the REST gateway: status
+// codes for each outcome, snake_case JSON field names and types, and the
+// shape of error bodies. They drive the same scenarios as load-test.go,
+// including its intentional not-found reads, so a gateway that passes can
+// replace the FastAPI client without breaking callers. The gateway serves
+// from httptest in front of a UserService on bufconn, backed by the memory
+// repository and cache.
+
+// userFields are the fields every user object in a response carries.
+var userFields = []string{"id", "name", "email", "age", "created_at", "updated_at"}
+
+// env is the gateway under test.
+type env struct {
+	client  *http.Client
+	baseURL string
+}
+
+func newEnv(t *testing.T) *env {
+	t.Helper()
+	logger := slog.New(slog.DiscardHandler)
+	svc := server.NewCombinedServer(memory.NewUserRepository(), cachetest.New(clock.System), logger)
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(server.ValidationInterceptor()))
+	pb.RegisterUserServiceServer(grpcServer, svc)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	gw, err := gateway.New(t.Context(), gateway.Config{
+		Target: "passthrough:///contract",
+		Dial: func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		},
+		MaxRecvMsgSize: 4 << 20,
+		MaxSendMsgSize: 4 << 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = gw.Close() })
+
+	mux := http.NewServeMux()
+	gw.Register(mux)
+	httpServer := httptest.NewServer(mux)
+	t.Cleanup(httpServer.Close)
+	return &env{client: httpServer.Client(), baseURL: httpServer.URL}
+}
+
+func TestContract(t *testing.T) {
+	e := newEnv(t)
+	for _, c := range []struct {
+		name string
+		fn   func(context.Context, *env) error
+	}{
+		{"user lifecycle", checkUserLifecycle},
+		{"conditional get", checkConditionalGet},
+		{"read mask", checkReadMask},
+		{"list users", checkListUsers},
+		{"not found", checkNotFound},
+		{"duplicate email", checkDuplicateEmail},
+		{"invalid input", checkInvalidInput},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.fn(t.Context(), e); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// checkUserLifecycle creates, reads, updates and deletes a user, checking
+// the status and body of each step.
+func checkUserLifecycle(ctx context.Context, e *env) error {
+	created, err := e.createUser(ctx)
+	if err != nil {
+		return err
+	}
+	id := created["id"].(string)
+	defer e.deleteUser(ctx, id)
+
+	resp, err := e.do(ctx, http.MethodGet, "/v1/users/"+id, nil, nil)
+	if err != nil {
+		return err
+	}
+	got, err := resp.user(http.StatusOK)
+	if err != nil {
+		return fmt.Errorf("get: %w", err)
+	}
+	if got["email"] != created["email"] {
+		return fmt.Errorf("get returned email %v, want %v", got["email"], created["email"])
+	}
+
+	resp, err = e.do(ctx, http.MethodPut, "/v1/users/"+id, map[string]any{"name": "Contract Renamed", "age": 31}, nil)
+	if err != nil {
+		return err
+	}
+	updated, err := resp.user(http.StatusOK)
+	if err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+	if updated["name"] != "Contract Renamed" || updated["age"] != float64(31) {
+		return fmt.Errorf("update returned %v aged %v, want Contract Renamed aged 31", updated["name"], updated["age"])
+	}
+
+	resp, err = e.do(ctx, http.MethodDelete, "/v1/users/"+id, nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := resp.message(http.StatusOK); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	resp, err = e.do(ctx, http.MethodGet, "/v1/users/"+id, nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := resp.apiError(http.StatusNotFound); err != nil {
+		return fmt.Errorf("get after delete: %w", err)
+	}
+	resp, err = e.do(ctx, http.MethodDelete, "/v1/users/"+id, nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := resp.apiError(http.StatusNotFound); err != nil {
+		return fmt.Errorf("delete twice: %w", err)
+	}
+	return nil
+}
+
+// checkConditionalGet expects an ETag on reads and 304 with an empty body
+// when If-None-Match names it.
+func checkConditionalGet(ctx context.Context, e *env) error {
+	created, err := e.createUser(ctx)
+	if err != nil {
+		return err
+	}
+	id := created["id"].(string)
+	defer e.deleteUser(ctx, id)
+
+	resp, err := e.do(ctx, http.MethodGet, "/v1/users/"+id, nil, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := resp.user(http.StatusOK); err != nil {
+		return fmt.Errorf("get: %w", err)
+	}
+	etag := resp.header.Get("ETag")
+	if etag == "" {
+		return errors.New("get returned no ETag header")
+	}
+
+	resp, err = e.do(ctx, http.MethodGet, "/v1/users/"+id, nil, map[string]string{"If-None-Match": etag})
+	if err != nil {
+		return err
+	}
+	if resp.status != http.StatusNotModified {
+		return fmt.Errorf("get with If-None-Match returned %d, want 304", resp.status)
+	}
+	if len(resp.body) != 0 {
+		return fmt.Errorf("304 response has a body: %s", resp.body)
+	}
+	return nil
+}
+
+// checkReadMask expects read_mask, a comma-separated field mask, to leave
+// out the fields it does not name.
+func checkReadMask(ctx context.Context, e *env) error {
+	created, err := e.createUser(ctx)
+	if err != nil {
+		return err
+	}
+	id := created["id"].(string)
+	defer e.deleteUser(ctx, id)
+
+	resp, err := e.do(ctx, http.MethodGet, "/v1/users/"+id+"?read_mask=id,name", nil, nil)
+	if err != nil {
+		return err
+	}
+	var user map[string]any
+	if resp.status != http.StatusOK || json.Unmarshal(resp.body, &user) != nil {
+		return fmt.Errorf("get with read_mask returned %d: %s", resp.status, resp.body)
+	}
+	if user["id"] != id || user["name"] != created["name"] {
+		return fmt.Errorf("get with read_mask returned %s, want the id and name", resp.body)
+	}
+	if email, ok := user["email"]; ok && email != "" {
+		return fmt.Errorf("get with read_mask returned the email: %s", resp.body)
+	}
+
+	resp, err = e.do(ctx, http.MethodGet, "/v1/users/"+id+"?read_mask=password", nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := resp.apiError(http.StatusBadRequest); err != nil {
+		return fmt.Errorf("get with an unknown read_mask field: %w", err)
+	}
+	return nil
+}
+
+// checkListUsers pages through users the way load-test.go does.
+func checkListUsers(ctx context.Context, e *env) error {
+	created, err := e.createUser(ctx)
+	if err != nil {
+		return err
+	}
+	defer e.deleteUser(ctx, created["id"].(string))
+
+	resp, err := e.do(ctx, http.MethodGet, "/v1/users?page=1&limit=5", nil, nil)
+	if err != nil {
+		return err
+	}
+	var list map[string]json.RawMessage
+	if err := resp.object(http.StatusOK, &list, "users", "total", "message"); err != nil {
+		return err
+	}
+	var total float64
+	if err := json.Unmarshal(list["total"], &total); err != nil {
+		return fmt.Errorf("total is not a number: %s", list["total"])
+	}
+	var users []map[string]any
+	if err := json.Unmarshal(list["users"], &users); err != nil {
+		return fmt.Errorf("users is not an array of objects: %w", err)
+	}
+	if len(users) == 0 || len(users) > 5 {
+		return fmt.Errorf("list returned %d users, want 1 to 5", len(users))
+	}
+	for _, user := range users {
+		if err := checkUser(user); err != nil {
+			return fmt.Errorf("listed user: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkNotFound reads and updates IDs that don't exist, both a well-formed
+// one and the malformed kind load-test.go uses.
+func checkNotFound(ctx context.Context, e *env) error {
+	for _, id := range []string{uuid.NewString(), "nf-123456"} {
+		resp, err := e.do(ctx, http.MethodGet, "/v1/users/"+id, nil, nil)
+		if err != nil {
+			return err
+		}
+		if err := resp.apiError(http.StatusNotFound); err != nil {
+			return fmt.Errorf("get %s: %w", id, err)
+		}
+	}
+	resp, err := e.do(ctx, http.MethodPut, "/v1/users/"+uuid.NewString(), map[string]any{"name": "Nobody", "age": 30}, nil)
+	if err != nil {
+		return err
+	}
+	if err := resp.apiError(http.StatusNotFound); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+	return nil
+}
+
+func checkDuplicateEmail(ctx context.Context, e *env) error {
+	created, err := e.createUser(ctx)
+	if err != nil {
+		return err
+	}
+	defer e.deleteUser(ctx, created["id"].(string))
+
+	resp, err := e.do(ctx, http.MethodPost, "/v1/users", map[string]any{"name": "Duplicate", "email": created["email"], "age": 30}, nil)
+	if err != nil {
+		return err
+	}
+	if err := resp.apiError(http.StatusConflict); err != nil {
+		return fmt.Errorf("create with a taken email: %w", err)
+	}
+	return nil
+}
+
+// checkInvalidInput sends requests the gateway or the service must reject.
+// Either may reject them, so both 400 and 422 are accepted.
+func checkInvalidInput(ctx context.Context, e *env) error {
+	requests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+	}{
+		{"negative age", http.MethodPost, "/v1/users", map[string]any{"name": "Invalid", "email": "invalid-" + uuid.NewString() + "@example.com", "age": -1}},
+		{"malformed email", http.MethodPost, "/v1/users", map[string]any{"name": "Invalid", "email": "not-an-email", "age": 30}},
+		{"missing name", http.MethodPost, "/v1/users", map[string]any{"email": "invalid-" + uuid.NewString() + "@example.com", "age": 30}},
+		// limit=0 is the default page size, as an unset limit is
+		{"negative limit", http.MethodGet, "/v1/users?limit=-1", nil},
+	}
+	for _, r := range requests {
+		resp, err := e.do(ctx, r.method, r.path, r.body, nil)
+		if err != nil {
+			return err
+		}
+		if err := resp.apiError(http.StatusBadRequest, http.StatusUnprocessableEntity); err != nil {
+			return fmt.Errorf("%s: %w", r.name, err)
+		}
+	}
+	return nil
+}
+
+func (e *env) createUser(ctx context.Context) (map[string]any, error) {
+	email := fmt.Sprintf("contract-%s@example.com", uuid.NewString())
+	resp, err := e.do(ctx, http.MethodPost, "/v1/users", map[string]any{"name": "Contract User", "email": email, "age": 30}, nil)
+	if err != nil {
+		return nil, err
+	}
+	user, err := resp.user(http.StatusCreated)
+	if err != nil {
+		return nil, fmt.Errorf("create: %w", err)
+	}
+	if user["email"] != email {
+		return nil, fmt.Errorf("create returned email %v, want %s", user["email"], email)
+	}
+	return user, nil
+}
+
+// deleteUser cleans up after a check; the user may already be gone.
+func (e *env) deleteUser(ctx context.Context, id string) {
+	_, _ = e.do(ctx, http.MethodDelete, "/v1/users/"+id, nil, nil)
+}
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (e *env) do(ctx context.Context, method, path string, body any, header map[string]string) (*response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, e.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	return &response{status: resp.StatusCode, header: resp.Header, body: data}, nil
+}
+
+// object checks the status and content type and decodes the body into dst,
+// which must have the required fields, all in snake_case.
+func (r *response) object(status int, dst *map[string]json.RawMessage, required ...string) error {
+	if r.status != status {
+		return fmt.Errorf("status %d, want %d: %s", r.status, status, r.body)
+	}
+	if mediaType := r.header.Get("Content-Type"); !strings.HasPrefix(mediaType, "application/json") {
+		return fmt.Errorf("content type %q, want application/json", mediaType)
+	}
+	if err := json.Unmarshal(r.body, dst); err != nil {
+		return fmt.Errorf("body is not a JSON object: %s", r.body)
+	}
+	for _, field := range required {
+		if _, ok := (*dst)[field]; !ok {
+			return fmt.Errorf("body has no %q field: %s", field, r.body)
+		}
+	}
+	for field := range *dst {
+		if field != strings.ToLower(field) {
+			return fmt.Errorf("field %q is not snake_case", field)
+		}
+	}
+	return nil
+}
+
+// user checks for a user object with status.
+func (r *response) user(status int) (map[string]any, error) {
+	var fields map[string]json.RawMessage
+	if err := r.object(status, &fields, userFields...); err != nil {
+		return nil, err
+	}
+	var user map[string]any
+	if err := json.Unmarshal(r.body, &user); err != nil {
+		return nil, err
+	}
+	return user, checkUser(user)
+}
+
+// message checks for a {"message": "..."} body with status.
+func (r *response) message(status int) error {
+	var fields map[string]json.RawMessage
+	if err := r.object(status, &fields, "message"); err != nil {
+		return err
+	}
+	var message string
+	if err := json.Unmarshal(fields["message"], &message); err != nil {
+		return fmt.Errorf("message is not a string: %s", fields["message"])
+	}
+	return nil
+}
+
+// apiError checks for an error body with one of statuses: an object whose
+// detail field describes the error, as a string or, for rejected input, a
+// list of the problems found.
+func (r *response) apiError(statuses ...int) error {
+	if !slices.Contains(statuses, r.status) {
+		return fmt.Errorf("status %d, want one of %v: %s", r.status, statuses, r.body)
+	}
+	var fields map[string]json.RawMessage
+	if err := r.object(r.status, &fields, "detail"); err != nil {
+		return err
+	}
+	var detail any
+	if err := json.Unmarshal(fields["detail"], &detail); err != nil {
+		return err
+	}
+	switch detail := detail.(type) {
+	case string:
+		if detail == "" {
+			return errors.New("error detail is empty")
+		}
+	case []any:
+		if len(detail) == 0 {
+			return errors.New("error detail is empty")
+		}
+	default:
+		return fmt.Errorf("error detail is neither a string nor a list: %s", fields["detail"])
+	}
+	return nil
+}
+
+// checkUser checks the types of userFields: strings for id, name and email,
+// and plain JSON numbers, not strings, for age and the Unix timestamps.
+func checkUser(user map[string]any) error {
+	for _, field := range userFields {
+		var ok bool
+		switch field {
+		case "id", "name", "email":
+			_, ok = user[field].(string)
+		default:
+			_, ok = user[field].(float64)
+		}
+		if !ok {
+			return fmt.Errorf("user field %q has the wrong type: %v", field, user[field])
+		}
+	}
+	return nil
+}
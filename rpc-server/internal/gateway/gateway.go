@@ -0,0 +1,86 @@
+// Package gateway serves the REST API described by proto/user_gateway.yaml
+// from the server process, translating HTTP requests to gRPC calls on an
+// in-process listener of the server, so calls pass through the same
+// interceptors as any other client's. Responses keep the shape the FastAPI
+// client established: snake_case fields, 64-bit integers as JSON numbers, single-user responses
+// unwrapped to the user, and errors as {"detail": message}.
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "grpc-server/pkg/pb"
+)
+
+// Prefix is where Register mounts the gateway.
+const Prefix = "/v1/"
+
+// Config configures a Gateway.
+type Config struct {
+	// Target and Dial connect to the gRPC server, e.g.
+	// transport.InProcessTarget and the DialContext of the
+	// transport.InProcessListener the server serves on. The connection is
+	// not secured; the server must not require TLS on it.
+	Target string
+	Dial   func(ctx context.Context, addr string) (net.Conn, error)
+	// MaxRecvMsgSize and MaxSendMsgSize mirror the server's limits, so the
+	// gateway accepts every response the server may send.
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+}
+
+// Gateway is an HTTP handler for the UserService REST routes.
+type Gateway struct {
+	conn *grpc.ClientConn
+	mux  *runtime.ServeMux
+}
+
+func New(ctx context.Context, cfg Config) (*Gateway, error) {
+	conn, err := grpc.NewClient(cfg.Target,
+		grpc.WithContextDialer(cfg.Dial),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(cfg.MaxSendMsgSize),
+			grpc.MaxCallSendMsgSize(cfg.MaxRecvMsgSize),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gateway client: %w", err)
+	}
+
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, newJSONMarshaler()),
+		runtime.WithIncomingHeaderMatcher(incomingHeader),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeader),
+		runtime.WithForwardResponseRewriter(unwrapUser),
+		runtime.WithForwardResponseOption(responseStatus),
+		runtime.WithErrorHandler(writeError),
+	)
+	if err := pb.RegisterUserServiceHandler(ctx, mux, conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to register gateway handlers: %w", err)
+	}
+	return &Gateway{conn: conn, mux: mux}, nil
+}
+
+// Register mounts the gateway on mux under Prefix.
+func (g *Gateway) Register(mux *http.ServeMux) {
+	mux.Handle(Prefix, g.mux)
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// Close closes the connection to the gRPC server. Shut the HTTP server down
+// first, so in-flight requests can finish.
+func (g *Gateway) Close() error {
+	return g.conn.Close()
+}
@@ -0,0 +1,50 @@
+package gateway
+
+import (
+	"net/textproto"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"grpc-server/internal/logging"
+	"grpc-server/internal/ratelimit"
+	"grpc-server/internal/tracing"
+)
+
+// incomingHeaders are the HTTP request headers passed to the server as the
+// metadata it reads them from.
+var incomingHeaders = map[string]string{
+	"If-None-Match": "if-none-match",
+	"X-Tenant-Id":   tracing.TenantMetadataKey,
+	"X-Request-Id":  logging.RequestIDMetadataKey,
+	"Traceparent":   "traceparent",
+	"Tracestate":    "tracestate",
+	"Baggage":       "baggage",
+	"X-Api-Key":     ratelimit.APIKeyMetadataKey,
+}
+
+// outgoingHeaders are the response metadata keys the server sets for HTTP
+// clients, and the HTTP headers they become.
+var outgoingHeaders = map[string]string{
+	"etag":                "ETag",
+	"x-stale":             "X-Stale",
+	"ratelimit-limit":     "RateLimit-Limit",
+	"ratelimit-remaining": "RateLimit-Remaining",
+	"ratelimit-reset":     "RateLimit-Reset",
+	"retry-after":         "Retry-After",
+}
+
+func incomingHeader(key string) (string, bool) {
+	if md, ok := incomingHeaders[textproto.CanonicalMIMEHeaderKey(key)]; ok {
+		return md, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeader maps known metadata to plain HTTP headers and the rest to
+// Grpc-Metadata- headers, as grpc-gateway does by default.
+func outgoingHeader(key string) (string, bool) {
+	if header, ok := outgoingHeaders[key]; ok {
+		return header, true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
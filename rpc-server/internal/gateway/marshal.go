@@ -0,0 +1,96 @@
+package gateway
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// jsonMarshaler decodes requests as protojson, accepting field names in
+// either case and ignoring unknown fields, and encodes responses with proto
+// field names and every field present. Unlike protojson, it writes 64-bit
+// integers as numbers, since REST clients read timestamps such as
+// created_at as numbers.
+type jsonMarshaler struct {
+	runtime.JSONPb
+}
+
+func newJSONMarshaler() *jsonMarshaler {
+	return &jsonMarshaler{runtime.JSONPb{
+		UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+	}}
+}
+
+func (m *jsonMarshaler) Marshal(v any) ([]byte, error) {
+	if msg, ok := v.(proto.Message); ok {
+		return json.Marshal(messageValue(msg.ProtoReflect()))
+	}
+	return json.Marshal(v)
+}
+
+func (m *jsonMarshaler) NewEncoder(w io.Writer) runtime.Encoder {
+	return runtime.EncoderFunc(func(v any) error {
+		data, err := m.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+}
+
+// messageValue returns msg as a JSON object keyed by field name. Unset
+// oneof members are left out, as protojson leaves them out.
+func messageValue(msg protoreflect.Message) any {
+	if !msg.IsValid() {
+		return nil
+	}
+	fields := msg.Descriptor().Fields()
+	obj := make(map[string]any, fields.Len())
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		if fd.ContainingOneof() != nil && !msg.Has(fd) {
+			continue
+		}
+		obj[string(fd.Name())] = fieldValue(fd, msg.Get(fd))
+	}
+	return obj
+}
+
+func fieldValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		items := make([]any, list.Len())
+		for i := range list.Len() {
+			items[i] = singularValue(fd, list.Get(i))
+		}
+		return items
+	case fd.IsMap():
+		entries := make(map[string]any, v.Map().Len())
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			entries[k.String()] = singularValue(fd.MapValue(), v)
+			return true
+		})
+		return entries
+	}
+	return singularValue(fd, v)
+}
+
+func singularValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if value := fd.Enum().Values().ByNumber(v.Enum()); value != nil {
+			return string(value.Name())
+		}
+		return int32(v.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageValue(v.Message())
+	}
+	// Bytes encode as base64, as in protojson; everything else as is.
+	return v.Interface()
+}
@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "grpc-server/pkg/pb"
+)
+
+// unwrapUser answers calls that return a single user with the user itself.
+func unwrapUser(_ context.Context, resp proto.Message) (any, error) {
+	switch resp := resp.(type) {
+	case *pb.CreateUserResponse:
+		return resp.User, nil
+	case *pb.GetUserResponse:
+		return resp.User, nil
+	case *pb.UpdateUserResponse:
+		return resp.User, nil
+	case *pb.ConfirmEmailChangeResponse:
+		return resp.User, nil
+	}
+	return resp, nil
+}
+
+// responseStatus sets the status codes that differ from 200 OK. A
+// not_modified GetUser answers 304, whose body net/http drops.
+func responseStatus(_ context.Context, w http.ResponseWriter, resp proto.Message) error {
+	switch resp := resp.(type) {
+	case *pb.CreateUserResponse:
+		w.WriteHeader(http.StatusCreated)
+	case *pb.RequestEmailChangeResponse:
+		w.WriteHeader(http.StatusAccepted)
+	case *pb.GetUserResponse:
+		if resp.NotModified {
+			w.WriteHeader(http.StatusNotModified)
+		}
+	}
+	return nil
+}
+
+// errorBody is the error response.
+type errorBody struct {
+	Detail string `json:"detail"`
+}
+
+// writeError answers err as {"detail": message} with the HTTP status
+// grpc-gateway maps its gRPC code to, and the response headers the server
+// set, such as Retry-After.
+func writeError(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, _ *http.Request, err error) {
+	code := 0
+	var httpErr *runtime.HTTPStatusError
+	if errors.As(err, &httpErr) {
+		code, err = httpErr.HTTPStatus, httpErr.Err
+	}
+	s := status.Convert(err)
+	if code == 0 {
+		code = runtime.HTTPStatusFromCode(s.Code())
+	}
+
+	if md, ok := runtime.ServerMetadataFromContext(ctx); ok {
+		for key, values := range md.HeaderMD {
+			if header, ok := outgoingHeaders[key]; ok {
+				for _, v := range values {
+					w.Header().Add(header, v)
+				}
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(errorBody{Detail: s.Message()})
+}
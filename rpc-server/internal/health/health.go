@@ -0,0 +1,116 @@
+// Package health reports the server's dependencies through the standard
+// grpc.health.v1.Health service, so probes see a database or cache outage
+// rather than only whether the process accepts connections.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	grpc_health "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"grpc-server/internal/logging"
+)
+
+// Check is a dependency the server can't serve without.
+type Check struct {
+	Name string
+	Ping func(ctx context.Context) error
+}
+
+// Config configures a Checker.
+type Config struct {
+	// Interval between rounds of checks.
+	Interval time.Duration
+	// Timeout bounds each check; a slower dependency counts as down.
+	Timeout time.Duration
+}
+
+// Checker runs its checks periodically and sets the status of the overall
+// server ("") and of each listed service to SERVING when all of them pass,
+// NOT_SERVING otherwise.
+type Checker struct {
+	server   *grpc_health.Server
+	checks   []Check
+	services []string
+	cfg      Config
+	logger   *logging.Logger
+
+	failing map[string]bool // only touched by check
+}
+
+// NewChecker creates a Checker reporting to server. Statuses start as
+// NOT_SERVING until the first round of checks; call Check before serving to
+// start out with a real status.
+func NewChecker(server *grpc_health.Server, checks []Check, services []string, cfg Config, base *slog.Logger) *Checker {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+	if cfg.Timeout <= 0 || cfg.Timeout > cfg.Interval {
+		cfg.Timeout = cfg.Interval
+	}
+	c := &Checker{
+		server:   server,
+		checks:   checks,
+		services: append([]string{""}, services...),
+		cfg:      cfg,
+		logger:   logging.New(base.With("component", "health")),
+		failing:  make(map[string]bool),
+	}
+	c.set(healthpb.HealthCheckResponse_NOT_SERVING)
+	return c
+}
+
+// Run checks every Interval until ctx is cancelled.
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Check(ctx)
+		}
+	}
+}
+
+// Check runs every check once, updates the statuses, and reports whether
+// all of them passed.
+func (c *Checker) Check(ctx context.Context) bool {
+	healthy := true
+	for _, check := range c.checks {
+		checkCtx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+		err := check.Ping(checkCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return false
+		}
+
+		if err != nil {
+			healthy = false
+			if !c.failing[check.Name] {
+				c.logger.ErrorCtx(ctx, "Health check failed", "check", check.Name, logging.Error, err)
+			}
+		} else if c.failing[check.Name] {
+			c.logger.InfoCtx(ctx, "Health check recovered", "check", check.Name)
+		}
+		c.failing[check.Name] = err != nil
+	}
+
+	if healthy {
+		c.set(healthpb.HealthCheckResponse_SERVING)
+	} else {
+		c.set(healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return healthy
+}
+
+func (c *Checker) set(status healthpb.HealthCheckResponse_ServingStatus) {
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, status)
+	}
+}
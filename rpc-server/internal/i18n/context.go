@@ -0,0 +1,73 @@
+package i18n
+
+import (
+	"context"
+	"time"
+	// The runtime image is built FROM scratch and has no zoneinfo files.
+	_ "time/tzdata"
+
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/metadata"
+)
+
+// timezoneKeys are checked in order for an IANA time zone name such as
+// "Asia/Taipei". Gateway callers send it as the Grpc-Metadata-X-Timezone
+// header, which arrives without the prefix.
+var timezoneKeys = []string{"x-timezone"}
+
+type localeKey struct{}
+
+type timezoneKey struct{}
+
+// WithLocale returns a copy of ctx carrying tag as the caller's locale.
+func WithLocale(ctx context.Context, tag language.Tag) context.Context {
+	return context.WithValue(ctx, localeKey{}, tag)
+}
+
+// LocaleFrom returns the caller's locale stored by UnaryServerInterceptor.
+// Without one it falls back to the incoming metadata, so it also works for
+// handlers called outside the interceptor chain.
+func LocaleFrom(ctx context.Context) language.Tag {
+	if tag, ok := ctx.Value(localeKey{}).(language.Tag); ok {
+		return tag
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return FromMetadata(md)
+}
+
+// WithTimezone returns a copy of ctx carrying loc as the caller's time zone.
+func WithTimezone(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, timezoneKey{}, loc)
+}
+
+// TimezoneFrom returns the caller's time zone stored by
+// UnaryServerInterceptor, falling back to the incoming metadata and then UTC.
+func TimezoneFrom(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(timezoneKey{}).(*time.Location); ok {
+		return loc
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return TimezoneFromMetadata(md)
+}
+
+// TimezoneFromMetadata returns the time zone named in md, or UTC when none is
+// given or the name is not a known IANA zone.
+func TimezoneFromMetadata(md metadata.MD) *time.Location {
+	for _, key := range timezoneKeys {
+		values := md.Get(key)
+		if len(values) == 0 {
+			continue
+		}
+		// LoadLocation treats "" as UTC and "Local" as the server's zone,
+		// neither of which the caller meant.
+		if values[0] == "" || values[0] == "Local" {
+			return time.UTC
+		}
+		loc, err := time.LoadLocation(values[0])
+		if err != nil {
+			return time.UTC
+		}
+		return loc
+	}
+	return time.UTC
+}
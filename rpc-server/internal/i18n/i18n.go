@@ -0,0 +1,146 @@
+package i18n
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata keys consulted for the caller's locale, in priority order. The
+// gateway forwards the HTTP Accept-Language header with the grpcgateway- prefix.
+var localeKeys = []string{"x-locale", "accept-language", "grpcgateway-accept-language"}
+
+// Supported lists the locales with translations; the first entry is the fallback.
+var Supported = []language.Tag{
+	language.English,
+	language.TraditionalChinese,
+	language.Spanish,
+}
+
+var matcher = language.NewMatcher(Supported)
+
+// messages holds user-facing strings keyed by locale and ErrorInfo reason.
+// Placeholders in braces are filled from the ErrorInfo metadata.
+var messages = map[language.Tag]map[string]string{
+	language.English: {
+		"USER_NOT_FOUND":                     "User {user_id} was not found.",
+		"EMAIL_ALREADY_EXISTS":               "The email address {email} is already in use.",
+		"INTERNAL_ERROR":                     "Something went wrong on our side. Please try again later.",
+		"DEADLINE_EXCEEDED":                  "The request ran out of time. Please try again.",
+		"EXPORT_UNAVAILABLE":                 "Data export is not available right now.",
+		"USER_VERSION_NOT_FOUND":             "Version {version_id} of user {user_id} was not found.",
+		"SELF_MERGE":                         "A user cannot be merged into itself.",
+		"USER_ALREADY_MERGED":                "User {user_id} has already been merged into another user.",
+		"EMAIL_CHANGE_REQUIRES_CONFIRMATION": "Email addresses can only be changed through a confirmed email change request.",
+		"EMAIL_UNCHANGED":                    "The new email address is the same as the current one.",
+		"EMAIL_CHANGE_NOT_FOUND":             "There is no pending email change for user {user_id}.",
+		"EMAIL_CHANGE_EXPIRED":               "The email change request has expired. Please request a new one.",
+		"INVALID_CONFIRMATION_TOKEN":         "The confirmation code is not valid.",
+		"CROSS_SHARD_MERGE":                  "These users are stored separately and cannot be merged.",
+		"SERVER_BUSY":                        "The service is busy. Please try again shortly.",
+		"READ_ONLY_MODE":                     "Changes are temporarily disabled. Please try again later.",
+		"RATE_LIMITED":                       "Too many requests. Please slow down and try again.",
+		"DATABASE_UNAVAILABLE":               "This is temporarily unavailable. Please try again later.",
+		"INVALID_USER_ID":                    "{user_id} is not a valid user ID.",
+		"USER_ALREADY_EXISTS":                "A user with ID {user_id} already exists.",
+		"MESSAGE_TOO_LARGE":                  "The {direction} is too large. Please ask for less data at a time.",
+		"UNAUTHENTICATED":                    "Please sign in to continue.",
+		"PERMISSION_DENIED":                  "You do not have permission to do this.",
+	},
+	language.TraditionalChinese: {
+		"USER_NOT_FOUND":                     "找不到使用者 {user_id}。",
+		"EMAIL_ALREADY_EXISTS":               "電子郵件地址 {email} 已被使用。",
+		"INTERNAL_ERROR":                     "系統發生錯誤，請稍後再試。",
+		"DEADLINE_EXCEEDED":                  "請求逾時，請再試一次。",
+		"EXPORT_UNAVAILABLE":                 "目前無法匯出資料。",
+		"USER_VERSION_NOT_FOUND":             "找不到使用者 {user_id} 的版本 {version_id}。",
+		"SELF_MERGE":                         "無法將使用者合併到自己。",
+		"USER_ALREADY_MERGED":                "使用者 {user_id} 已合併到其他使用者。",
+		"EMAIL_CHANGE_REQUIRES_CONFIRMATION": "電子郵件地址只能透過已確認的變更請求修改。",
+		"EMAIL_UNCHANGED":                    "新的電子郵件地址與目前的相同。",
+		"EMAIL_CHANGE_NOT_FOUND":             "使用者 {user_id} 沒有待處理的電子郵件變更。",
+		"EMAIL_CHANGE_EXPIRED":               "電子郵件變更請求已過期，請重新申請。",
+		"INVALID_CONFIRMATION_TOKEN":         "確認碼無效。",
+		"CROSS_SHARD_MERGE":                  "這些使用者分別儲存，無法合併。",
+		"SERVER_BUSY":                        "服務忙碌中，請稍後再試。",
+		"READ_ONLY_MODE":                     "暫時無法進行變更，請稍後再試。",
+		"RATE_LIMITED":                       "請求過於頻繁，請稍後再試。",
+		"DATABASE_UNAVAILABLE":               "暫時無法使用，請稍後再試。",
+		"INVALID_USER_ID":                    "{user_id} 不是有效的使用者 ID。",
+		"USER_ALREADY_EXISTS":                "ID 為 {user_id} 的使用者已存在。",
+		"MESSAGE_TOO_LARGE":                  "資料量過大，請分次取得較少的資料。",
+		"UNAUTHENTICATED":                    "請先登入。",
+		"PERMISSION_DENIED":                  "您沒有執行此操作的權限。",
+	},
+	language.Spanish: {
+		"USER_NOT_FOUND":                     "No se encontró el usuario {user_id}.",
+		"EMAIL_ALREADY_EXISTS":               "La dirección de correo {email} ya está en uso.",
+		"INTERNAL_ERROR":                     "Algo salió mal. Inténtalo de nuevo más tarde.",
+		"DEADLINE_EXCEEDED":                  "La solicitud excedió el tiempo límite. Inténtalo de nuevo.",
+		"EXPORT_UNAVAILABLE":                 "La exportación de datos no está disponible en este momento.",
+		"USER_VERSION_NOT_FOUND":             "No se encontró la versión {version_id} del usuario {user_id}.",
+		"SELF_MERGE":                         "Un usuario no se puede fusionar consigo mismo.",
+		"USER_ALREADY_MERGED":                "El usuario {user_id} ya se fusionó con otro usuario.",
+		"EMAIL_CHANGE_REQUIRES_CONFIRMATION": "El correo solo se puede cambiar mediante una solicitud de cambio confirmada.",
+		"EMAIL_UNCHANGED":                    "La nueva dirección de correo es igual a la actual.",
+		"EMAIL_CHANGE_NOT_FOUND":             "No hay un cambio de correo pendiente para el usuario {user_id}.",
+		"EMAIL_CHANGE_EXPIRED":               "La solicitud de cambio de correo ha caducado. Solicita una nueva.",
+		"INVALID_CONFIRMATION_TOKEN":         "El código de confirmación no es válido.",
+		"CROSS_SHARD_MERGE":                  "Estos usuarios se almacenan por separado y no se pueden fusionar.",
+		"SERVER_BUSY":                        "El servicio está ocupado. Inténtalo de nuevo en unos momentos.",
+		"READ_ONLY_MODE":                     "Los cambios están desactivados temporalmente. Inténtalo de nuevo más tarde.",
+		"RATE_LIMITED":                       "Demasiadas solicitudes. Espera un momento e inténtalo de nuevo.",
+		"DATABASE_UNAVAILABLE":               "No está disponible temporalmente. Inténtalo de nuevo más tarde.",
+		"INVALID_USER_ID":                    "{user_id} no es un ID de usuario válido.",
+		"USER_ALREADY_EXISTS":                "Ya existe un usuario con el ID {user_id}.",
+		"MESSAGE_TOO_LARGE":                  "Hay demasiados datos. Solicita menos datos a la vez.",
+		"UNAUTHENTICATED":                    "Inicia sesión para continuar.",
+		"PERMISSION_DENIED":                  "No tienes permiso para hacer esto.",
+	},
+}
+
+// Match picks the best supported locale for one or more Accept-Language style
+// values, falling back to English.
+func Match(accept ...string) language.Tag {
+	var desired []language.Tag
+	for _, a := range accept {
+		tags, _, err := language.ParseAcceptLanguage(a)
+		if err != nil {
+			continue
+		}
+		desired = append(desired, tags...)
+	}
+	if len(desired) == 0 {
+		return Supported[0]
+	}
+	_, idx, _ := matcher.Match(desired...)
+	return Supported[idx]
+}
+
+// FromMetadata resolves the caller's locale from incoming gRPC metadata.
+func FromMetadata(md metadata.MD) language.Tag {
+	for _, key := range localeKeys {
+		if values := md.Get(key); len(values) > 0 {
+			return Match(values...)
+		}
+	}
+	return Supported[0]
+}
+
+// Localize renders the message for key in tag, falling back to English when
+// the locale lacks a translation. It reports false for unknown keys.
+func Localize(tag language.Tag, key string, args map[string]string) (string, bool) {
+	msg, ok := messages[tag][key]
+	if !ok {
+		msg, ok = messages[Supported[0]][key]
+		if !ok {
+			return "", false
+		}
+	}
+
+	for k, v := range args {
+		msg = strings.ReplaceAll(msg, "{"+k+"}", v)
+	}
+	return msg, true
+}
@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"context"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor resolves the caller's locale and time zone from the
+// request metadata once and stores them in the context for handlers, see
+// LocaleFrom and TimezoneFrom. It also adds an errdetails.LocalizedMessage in
+// that locale to error statuses that carry a known ErrorInfo reason. The
+// status code, reason and English status message are left untouched.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = WithLocale(ctx, FromMetadata(md))
+		ctx = WithTimezone(ctx, TimezoneFromMetadata(md))
+
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		return resp, localizeError(ctx, err)
+	}
+}
+
+func localizeError(ctx context.Context, err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	var errorInfo *errdetails.ErrorInfo
+	for _, d := range st.Details() {
+		switch v := d.(type) {
+		case *errdetails.ErrorInfo:
+			errorInfo = v
+		case *errdetails.LocalizedMessage:
+			return err
+		}
+	}
+	if errorInfo == nil {
+		return err
+	}
+
+	tag := LocaleFrom(ctx)
+	msg, ok := Localize(tag, errorInfo.Reason, errorInfo.Metadata)
+	if !ok {
+		return err
+	}
+
+	localized, detailErr := st.WithDetails(&errdetails.LocalizedMessage{
+		Locale:  tag.String(),
+		Message: msg,
+	})
+	if detailErr != nil {
+		return err
+	}
+	return localized.Err()
+}
@@ -0,0 +1,206 @@
+//go:build integration
+
+// Package integration exercises the cached UserService end to end against
+// a real Postgres and Valkey, started with testcontainers: CRUD, list
+// pagination, cache fills and the invalidations that keep the cache
+// consistent with the database. The server runs in process on a bufconn
+// listener with the same repository and cache wiring as cmd/server.
+//
+// The tests need Docker and only build with the integration tag:
+//
+//	go test -tags integration ./...
+package integration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcvalkey "github.com/testcontainers/testcontainers-go/modules/valkey"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"grpc-server/internal/cache"
+	"grpc-server/internal/config"
+	"grpc-server/internal/database"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/migrations"
+	"grpc-server/internal/repository"
+	"grpc-server/internal/repository/instrumented"
+	"grpc-server/internal/repository/postgres"
+	"grpc-server/internal/server"
+	pb "grpc-server/pkg/pb"
+)
+
+// The images match the ones deployed in kustomize/infra/storage.
+const (
+	postgresImage = "postgres:17-alpine"
+	valkeyImage   = "valkey/valkey:8.0"
+)
+
+// env is the running server plus direct handles on its dependencies, so
+// tests can look behind the API.
+type env struct {
+	client pb.UserServiceClient
+	repo   repository.UserRepository
+	cache  cache.Cache
+	stats  *cache.StatsCache
+}
+
+// serviceRole is the role the server connects as. Superusers skip row-level
+// security, so the tests must not use the one that owns the container.
+const serviceRole = "service"
+
+// newEnv starts Postgres and Valkey, migrates the database and serves the
+// cached UserService over bufconn. Everything is torn down with t.
+func newEnv(t *testing.T) *env {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if testing.Verbose() {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+
+	pg, err := tcpostgres.Run(ctx, postgresImage,
+		tcpostgres.WithDatabase("users"),
+		tcpostgres.WithUsername("integration"),
+		tcpostgres.WithPassword("integration"),
+		// Postgres restarts once after initdb; it is ready on the second line
+		testcontainers.WithWaitStrategy(wait.ForLog("database system is ready to accept connections").
+			WithOccurrence(2).
+			WithStartupTimeout(time.Minute)),
+	)
+	testcontainers.CleanupContainer(t, pg)
+	if err != nil {
+		t.Fatalf("failed to start Postgres: %v", err)
+	}
+	databaseURL, err := pg.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vk, err := tcvalkey.Run(ctx, valkeyImage)
+	testcontainers.CleanupContainer(t, vk)
+	if err != nil {
+		t.Fatalf("failed to start Valkey: %v", err)
+	}
+	host, err := vk.Host(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := vk.MappedPort(ctx, "6379/tcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin := connect(t, ctx, databaseURL)
+	if _, err := migrations.Apply(ctx, admin, logger); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if _, err := admin.Exec(ctx, fmt.Sprintf(`
+		CREATE ROLE %[1]s LOGIN PASSWORD '%[1]s';
+		GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO %[1]s;
+		GRANT USAGE ON ALL SEQUENCES IN SCHEMA public TO %[1]s;
+	`, serviceRole)); err != nil {
+		t.Fatalf("failed to create the service role: %v", err)
+	}
+	serviceURL, err := url.Parse(databaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceURL.User = url.UserPassword(serviceRole, serviceRole)
+	pool := connect(t, ctx, serviceURL.String())
+
+	valkeyCache, err := cache.NewValkeyCache(&config.CacheConfig{URL: fmt.Sprintf("valkey://%s:%s", host, port.Port())}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = valkeyCache.Close() })
+	stats := cache.NewStatsCache(valkeyCache)
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor(logger)))
+	repo := postgres.NewUserRepository(pool, logger)
+	pb.RegisterUserServiceServer(grpcServer, server.NewCombinedServer(instrumented.NewUserRepository(repo, "postgres", logger), stats, logger))
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///integration",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial server: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return &env{client: pb.NewUserServiceClient(conn), repo: repo, cache: valkeyCache, stats: stats}
+}
+
+// connect opens a pool on the database at databaseURL, closed with t.
+func connect(t *testing.T, ctx context.Context, databaseURL string) *pgxpool.Pool {
+	t.Helper()
+	pool, err := database.Connect(ctx, &config.DatabaseConfig{
+		URL:              databaseURL,
+		MaxConns:         4,
+		MinConns:         1,
+		MaxIdleTime:      60,
+		MaxLifetime:      300,
+		AcquireTimeoutMs: 1000,
+		MaxConnErrors:    1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func (e *env) createUser(t *testing.T) *pb.User {
+	t.Helper()
+	resp, err := e.client.CreateUser(t.Context(), &pb.CreateUserRequest{
+		Name:  "Integration User",
+		Email: fmt.Sprintf("integration-%s@example.com", uuid.NewString()),
+		Age:   30,
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	return resp.User
+}
+
+// deleteUserOnCleanup deletes the user when the test ends; it may already
+// be gone.
+func (e *env) deleteUserOnCleanup(t *testing.T, id string) {
+	t.Cleanup(func() {
+		_, _ = e.client.DeleteUser(context.Background(), &pb.DeleteUserRequest{Id: id})
+	})
+}
+
+func (e *env) hits(namespace string) int64 {
+	counts, _ := e.stats.Counts()
+	for _, c := range counts {
+		if c.Namespace == namespace {
+			return c.Hits
+		}
+	}
+	return 0
+}
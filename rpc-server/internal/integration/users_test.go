@@ -0,0 +1,212 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"grpc-server/internal/cache"
+	"grpc-server/internal/models"
+	"grpc-server/internal/repository"
+	"grpc-server/internal/tenant"
+	pb "grpc-server/pkg/pb"
+)
+
+// The subtests share one server and read its cache statistics, so they run
+// one at a time.
+func TestUserService(t *testing.T) {
+	e := newEnv(t)
+
+	t.Run("user lifecycle", func(t *testing.T) { testUserLifecycle(t, e) })
+	t.Run("list cache invalidation", func(t *testing.T) { testListInvalidation(t, e) })
+	t.Run("list cache hydration", func(t *testing.T) { testListHydration(t, e) })
+	t.Run("duplicate email", func(t *testing.T) { testDuplicateEmail(t, e) })
+	t.Run("tenant isolation", func(t *testing.T) { testTenantIsolation(t, e) })
+}
+
+// testUserLifecycle creates, reads, updates and deletes a user, checking
+// that reads are served from the cache and that writes keep it current.
+func testUserLifecycle(t *testing.T, e *env) {
+	ctx := t.Context()
+	created := e.createUser(t)
+	id := created.Id
+	e.deleteUserOnCleanup(t, id)
+
+	key := cache.Key("user:", id)
+	if _, err := e.cache.Get(ctx, key); err != nil {
+		t.Fatalf("user not cached after create: %v", err)
+	}
+
+	hits := e.hits("user")
+	got, err := e.client.GetUser(ctx, &pb.GetUserRequest{Id: id})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.User.Email != created.Email {
+		t.Errorf("get returned email %q, want %q", got.User.Email, created.Email)
+	}
+	if e.hits("user") != hits+1 {
+		t.Error("get after create was not a cache hit")
+	}
+
+	if _, err := e.client.UpdateUser(ctx, &pb.UpdateUserRequest{Id: id, Name: "Integration Renamed", Age: created.Age + 1}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got, err = e.client.GetUser(ctx, &pb.GetUserRequest{Id: id})
+	if err != nil {
+		t.Fatalf("get after update: %v", err)
+	}
+	if got.User.Name != "Integration Renamed" || got.User.Age != created.Age+1 {
+		t.Errorf("get after update returned %q aged %d, the cache is stale", got.User.Name, got.User.Age)
+	}
+
+	if _, err := e.client.DeleteUser(ctx, &pb.DeleteUserRequest{Id: id}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := e.cache.Get(ctx, key); !errors.Is(err, cache.ErrCacheMiss) {
+		t.Errorf("user still cached after delete: %v", err)
+	}
+	if _, err := e.client.GetUser(ctx, &pb.GetUserRequest{Id: id}); status.Code(err) != grpc_codes.NotFound {
+		t.Errorf("get after delete returned %v, want NotFound", err)
+	}
+}
+
+// testListInvalidation reads a list page twice, so the second read is a
+// hit, then creates a user and expects the page to reflect it.
+func testListInvalidation(t *testing.T, e *env) {
+	ctx := t.Context()
+	req := &pb.ListUsersRequest{Page: 1, Limit: 10}
+	before, err := e.client.ListUsers(ctx, req)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	hits := e.hits("users:list:ids")
+	if _, err := e.client.ListUsers(ctx, req); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if e.hits("users:list:ids") != hits+1 {
+		t.Error("repeated list was not a cache hit")
+	}
+
+	created := e.createUser(t)
+	e.deleteUserOnCleanup(t, created.Id)
+
+	after, err := e.client.ListUsers(ctx, req)
+	if err != nil {
+		t.Fatalf("list after create: %v", err)
+	}
+	if after.Total != before.Total+1 {
+		t.Errorf("list total is %d after create, want %d; the list cache was not invalidated", after.Total, before.Total+1)
+	}
+}
+
+// testListHydration renames a user on a cached list page and expects the
+// page to stay cached yet show the new name, since pages only hold IDs.
+func testListHydration(t *testing.T, e *env) {
+	ctx := t.Context()
+	created := e.createUser(t)
+	e.deleteUserOnCleanup(t, created.Id)
+
+	// The newest user is first on the first page
+	req := &pb.ListUsersRequest{Page: 1, Limit: 10}
+	if _, err := e.client.ListUsers(ctx, req); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if _, err := e.client.UpdateUser(ctx, &pb.UpdateUserRequest{Id: created.Id, Name: "Integration Hydrated"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	hits := e.hits("users:list:ids")
+	after, err := e.client.ListUsers(ctx, req)
+	if err != nil {
+		t.Fatalf("list after update: %v", err)
+	}
+	if e.hits("users:list:ids") != hits+1 {
+		t.Error("list after update was not a cache hit; the update invalidated list pages")
+	}
+	for _, user := range after.Users {
+		if user.Id == created.Id && user.Name != "Integration Hydrated" {
+			t.Errorf("list after update returned name %q, the hydrated user is stale", user.Name)
+		}
+	}
+}
+
+func testDuplicateEmail(t *testing.T, e *env) {
+	created := e.createUser(t)
+	e.deleteUserOnCleanup(t, created.Id)
+
+	_, err := e.client.CreateUser(t.Context(), &pb.CreateUserRequest{Name: "Duplicate", Email: created.Email, Age: 30})
+	if status.Code(err) != grpc_codes.AlreadyExists {
+		t.Errorf("create with a taken email returned %v, want AlreadyExists", err)
+	}
+}
+
+// testTenantIsolation checks that the row-level security policies, not the
+// queries, keep one tenant's users from another: every query the repository
+// runs for tenant B is the one it runs for tenant A.
+func testTenantIsolation(t *testing.T, e *env) {
+	ctxA := tenant.With(t.Context(), "tenant-a")
+	ctxB := tenant.With(t.Context(), "tenant-b")
+	newUser := func(email string) *models.User {
+		now := time.Now()
+		return &models.User{ID: uuid.NewString(), Name: "Tenant User", Email: email, Age: 30, CreatedAt: now, UpdatedAt: now}
+	}
+
+	email := fmt.Sprintf("tenant-%s@example.com", uuid.NewString())
+	user := newUser(email)
+	if err := e.repo.Create(ctxA, user); err != nil {
+		t.Fatalf("create for tenant A: %v", err)
+	}
+	t.Cleanup(func() { _ = e.repo.Delete(tenant.With(context.Background(), "tenant-a"), user.ID) })
+
+	if _, err := e.repo.GetByID(ctxA, user.ID); err != nil {
+		t.Errorf("get for tenant A: %v", err)
+	}
+	if _, err := e.repo.GetByID(ctxB, user.ID); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("get for tenant B returned %v, want ErrUserNotFound", err)
+	}
+	if _, err := e.repo.GetByID(t.Context(), user.ID); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("get without a tenant returned %v, want ErrUserNotFound", err)
+	}
+	if users, _ := e.repo.BatchGet(ctxB, []string{user.ID}); len(users) != 0 {
+		t.Errorf("batch get for tenant B returned %d users, want none", len(users))
+	}
+	if _, total, err := e.repo.List(ctxB, 0, 100); err != nil || total != 0 {
+		t.Errorf("list for tenant B = %d users, %v; want none", total, err)
+	}
+	if versions, err := e.repo.History(ctxB, user.ID); err != nil || len(versions) != 0 {
+		t.Errorf("history for tenant B = %d versions, %v; want none", len(versions), err)
+	}
+
+	renamed := *user
+	renamed.Name = "Renamed By B"
+	if err := e.repo.Update(ctxB, &renamed); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("update for tenant B returned %v, want ErrUserNotFound", err)
+	}
+	if err := e.repo.Erase(ctxB, user.ID); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("erase for tenant B returned %v, want ErrUserNotFound", err)
+	}
+	if got, err := e.repo.GetByID(ctxA, user.ID); err != nil || got.Name != user.Name {
+		t.Errorf("tenant A's user after writes by B = %v, %v; want it unchanged", got, err)
+	}
+
+	// Emails are unique within a tenant, not across tenants.
+	other := newUser(email)
+	if err := e.repo.Create(ctxB, other); err != nil {
+		t.Errorf("create for tenant B with tenant A's email: %v", err)
+	} else {
+		t.Cleanup(func() { _ = e.repo.Delete(tenant.With(context.Background(), "tenant-b"), other.ID) })
+	}
+	if err := e.repo.Create(ctxA, newUser(email)); !errors.Is(err, repository.ErrEmailExists) {
+		t.Errorf("second create for tenant A with the same email returned %v, want ErrEmailExists", err)
+	}
+}
@@ -0,0 +1,158 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"grpc-server/internal/actor"
+	"grpc-server/internal/clock"
+	"grpc-server/internal/events"
+	"grpc-server/internal/lock"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/models"
+	"grpc-server/internal/repository"
+	"grpc-server/internal/tenant"
+	"grpc-server/internal/workpool"
+)
+
+// CacheInvalidator drops every cached copy of a user.
+type CacheInvalidator interface {
+	InvalidateUser(ctx context.Context, id string) error
+}
+
+// ExpiryConfig controls the inactive account expiry job.
+type ExpiryConfig struct {
+	InactiveFor time.Duration // users not updated for this long are expired
+	Interval    time.Duration // time between runs
+	BatchSize   int
+	// Parallelism bounds the users of a batch expired at once; below 1
+	// expires them one at a time.
+	Parallelism int
+	DryRun      bool        // log candidates without changing anything
+	Clock       clock.Clock // nil uses clock.System
+}
+
+// expiryLock is held for each run so only one replica works at a time.
+const expiryLock = "jobs.user_expiry"
+
+// ExpiryJob periodically moves users that have been inactive for longer than
+// ExpiryConfig.InactiveFor to the expired status. Runs are serialized across
+// replicas by a lock, and each user is expired with a conditional update as
+// well, so a user is never expired (or announced) twice.
+type ExpiryJob struct {
+	repo   repository.UserRepository
+	locker lock.Locker
+	cache  CacheInvalidator
+	events events.Publisher
+	logger *logging.Logger
+	cfg    ExpiryConfig
+}
+
+func NewExpiryJob(repo repository.UserRepository, locker lock.Locker, cache CacheInvalidator, publisher events.Publisher, base *slog.Logger, cfg ExpiryConfig) *ExpiryJob {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	cfg.Clock = clock.OrSystem(cfg.Clock)
+	return &ExpiryJob{
+		repo:   repo,
+		locker: locker,
+		cache:  cache,
+		events: publisher,
+		logger: logging.New(logging.ForModule(base, logging.ModuleJobs).With("job", "user_expiry")),
+		cfg:    cfg,
+	}
+}
+
+// Run executes the job every Interval until ctx is cancelled.
+func (j *ExpiryJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		ran, err := lock.Do(ctx, j.locker, expiryLock, func(ctx context.Context) error {
+			_, err := j.RunOnce(ctx)
+			return err
+		})
+		if err != nil && ctx.Err() == nil {
+			j.logger.ErrorCtx(ctx, "User expiry run failed", logging.Error, err)
+		}
+		if !ran && err == nil {
+			j.logger.DebugCtx(ctx, "Skipping user expiry run, another replica holds the lock")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce expires every currently inactive user and returns how many were
+// expired (or, in dry-run mode, would have been).
+func (j *ExpiryJob) RunOnce(ctx context.Context) (int, error) {
+	ctx = actor.With(ctx, actor.System("user_expiry"))
+	ctx = tenant.All(ctx)
+	cutoff := j.cfg.Clock.Now().Add(-j.cfg.InactiveFor)
+	j.logger.DebugCtx(ctx, "Starting user expiry run", "cutoff", cutoff, "dry_run", j.cfg.DryRun)
+
+	total := 0
+	for {
+		users, err := j.repo.ListInactive(ctx, cutoff, j.cfg.BatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		if j.cfg.DryRun {
+			for _, user := range users {
+				j.logger.InfoCtx(ctx, "Would expire inactive user", logging.UserID, user.ID, "updated_at", user.UpdatedAt)
+			}
+			total += len(users)
+		} else {
+			var expired atomic.Int64
+			err := workpool.Run(ctx, j.cfg.Parallelism, users, func(ctx context.Context, user *models.User) error {
+				ok, err := j.expire(ctx, user, cutoff)
+				if ok {
+					expired.Add(1)
+				}
+				return err
+			})
+			total += int(expired.Load())
+			if err != nil {
+				return total, err
+			}
+		}
+
+		// A dry run changes nothing, so the next batch would be the same one.
+		if j.cfg.DryRun || len(users) < j.cfg.BatchSize {
+			break
+		}
+	}
+
+	j.logger.InfoCtx(ctx, "User expiry run completed", "expired", total, "dry_run", j.cfg.DryRun)
+	return total, nil
+}
+
+// expire expires user unless it changed since it was listed, and reports
+// whether it did.
+func (j *ExpiryJob) expire(ctx context.Context, user *models.User, cutoff time.Time) (bool, error) {
+	expired, err := j.repo.Expire(ctx, user.ID, cutoff)
+	if err != nil || !expired {
+		return false, err
+	}
+
+	// The run sees every tenant, but the user is cached in its own tenant's
+	// namespace.
+	if err := j.cache.InvalidateUser(tenant.With(ctx, user.Tenant), user.ID); err != nil {
+		j.logger.WarnCtx(ctx, "Failed to invalidate expired user in cache", logging.UserID, user.ID, logging.Error, err)
+	}
+	event := events.New(ctx, events.TypeUserExpired, user.ID, map[string]string{
+		"last_updated_at": user.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+	if err := j.events.Publish(ctx, event); err != nil {
+		j.logger.ErrorCtx(ctx, "Failed to publish expiry event", logging.UserID, user.ID, logging.Error, err)
+	}
+	j.logger.InfoCtx(ctx, "Expired inactive user", logging.UserID, user.ID, "updated_at", user.UpdatedAt)
+	return true, nil
+}
@@ -0,0 +1,160 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"grpc-server/internal/actor"
+	"grpc-server/internal/clock"
+	"grpc-server/internal/lock"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/repository"
+	"grpc-server/internal/tenant"
+)
+
+// RetentionConfig controls the retention job. A zero retention keeps the
+// table's rows forever.
+type RetentionConfig struct {
+	// HistoryRetention is how long a user version is kept after it stopped
+	// being current. Current versions are always kept.
+	HistoryRetention time.Duration
+	// EmailChangeRetention is how long a pending email change is kept after
+	// it expired.
+	EmailChangeRetention time.Duration
+	Interval             time.Duration // time between runs
+	// BatchSize bounds the rows deleted by one statement, so a run never
+	// holds locks on a large part of a table.
+	BatchSize int
+	// MaxBatches bounds the statements per table and run; the rest is left
+	// to the next run. Below 1 prunes until nothing is left.
+	MaxBatches int
+	Clock      clock.Clock // nil uses clock.System
+}
+
+// retentionLock is held for each run so only one replica works at a time.
+const retentionLock = "jobs.retention"
+
+// Tables pruned by the retention job, as reported in logs and metrics.
+const (
+	tableUserHistory  = "user_history"
+	tableEmailChanges = "email_changes"
+)
+
+// RetentionJob periodically deletes user history versions and expired email
+// changes older than their retention window, which would otherwise grow
+// without bound. Rows are deleted in batches, oldest first.
+type RetentionJob struct {
+	repo   repository.UserRepository
+	locker lock.Locker
+	logger *logging.Logger
+	cfg    RetentionConfig
+
+	pruned  metric.Int64Counter
+	batches metric.Int64Counter
+}
+
+func NewRetentionJob(repo repository.UserRepository, locker lock.Locker, base *slog.Logger, cfg RetentionConfig) *RetentionJob {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1000
+	}
+	cfg.Clock = clock.OrSystem(cfg.Clock)
+
+	meter := otel.Meter("rpc-server.rpc/jobs")
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	pruned, _ := meter.Int64Counter("jobs.retention.pruned",
+		metric.WithDescription("Number of rows deleted by the retention job"),
+		metric.WithUnit("{row}"),
+	)
+	batches, _ := meter.Int64Counter("jobs.retention.batches",
+		metric.WithDescription("Number of delete statements run by the retention job"),
+		metric.WithUnit("{batch}"),
+	)
+	return &RetentionJob{
+		repo:    repo,
+		locker:  locker,
+		logger:  logging.New(logging.ForModule(base, logging.ModuleJobs).With("job", "retention")),
+		cfg:     cfg,
+		pruned:  pruned,
+		batches: batches,
+	}
+}
+
+// Run executes the job every Interval until ctx is cancelled.
+func (j *RetentionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		ran, err := lock.Do(ctx, j.locker, retentionLock, func(ctx context.Context) error {
+			_, err := j.RunOnce(ctx)
+			return err
+		})
+		if err != nil && ctx.Err() == nil {
+			j.logger.ErrorCtx(ctx, "Retention run failed", logging.Error, err)
+		}
+		if !ran && err == nil {
+			j.logger.DebugCtx(ctx, "Skipping retention run, another replica holds the lock")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce prunes every table with a retention window and returns how many
+// rows were deleted per table.
+func (j *RetentionJob) RunOnce(ctx context.Context) (map[string]int, error) {
+	ctx = actor.With(ctx, actor.System("retention"))
+	ctx = tenant.All(ctx)
+	now := j.cfg.Clock.Now()
+	pruned := make(map[string]int)
+
+	if j.cfg.HistoryRetention > 0 {
+		n, err := j.prune(ctx, tableUserHistory, now.Add(-j.cfg.HistoryRetention), j.repo.PruneHistory)
+		pruned[tableUserHistory] = n
+		if err != nil {
+			return pruned, err
+		}
+	}
+	if j.cfg.EmailChangeRetention > 0 {
+		n, err := j.prune(ctx, tableEmailChanges, now.Add(-j.cfg.EmailChangeRetention), j.repo.PruneEmailChanges)
+		pruned[tableEmailChanges] = n
+		if err != nil {
+			return pruned, err
+		}
+	}
+
+	j.logger.InfoCtx(ctx, "Retention run completed", "pruned", pruned)
+	return pruned, nil
+}
+
+// prune deletes table's rows older than cutoff, BatchSize at a time, until
+// a batch comes back short or MaxBatches were run.
+func (j *RetentionJob) prune(ctx context.Context, table string, cutoff time.Time, batch func(ctx context.Context, cutoff time.Time, limit int) (int, error)) (int, error) {
+	attrs := metric.WithAttributes(attribute.String("table", table))
+	j.logger.DebugCtx(ctx, "Pruning table", "table", table, "cutoff", cutoff)
+
+	total := 0
+	for i := 0; j.cfg.MaxBatches < 1 || i < j.cfg.MaxBatches; i++ {
+		n, err := batch(ctx, cutoff, j.cfg.BatchSize)
+		j.batches.Add(ctx, 1, attrs)
+		j.pruned.Add(ctx, int64(n), attrs)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < j.cfg.BatchSize {
+			return total, nil
+		}
+	}
+	j.logger.InfoCtx(ctx, "Retention batch limit reached, continuing next run", "table", table, "pruned", total)
+	return total, nil
+}
@@ -0,0 +1,140 @@
+// Package lock serializes critical sections across replicas. Locks are
+// non-blocking: a replica that finds a lock held skips the work instead of
+// queueing behind it, which suits periodic jobs and one-off maintenance.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grpc-server/internal/logging"
+)
+
+// ErrNotAcquired means another holder has the lock.
+var ErrNotAcquired = errors.New("lock is held elsewhere")
+
+// Locker hands out named locks.
+type Locker interface {
+	TryAcquire(ctx context.Context, name string) (*Lock, error)
+}
+
+// Lock is a held lock.
+type Lock struct {
+	name    string
+	token   int64
+	release func(ctx context.Context) error
+	once    sync.Once
+}
+
+func (l *Lock) Name() string { return l.name }
+
+// Token is a fencing token that grows with every acquisition, so a system
+// receiving writes from lock holders can reject those from a holder that
+// lost the lock to a newer one.
+func (l *Lock) Token() int64 { return l.token }
+
+// Release gives the lock up; calling it again does nothing.
+func (l *Lock) Release(ctx context.Context) error {
+	var err error
+	l.once.Do(func() { err = l.release(ctx) })
+	return err
+}
+
+// Do runs fn while holding name and reports whether it ran; when another
+// holder has the lock, fn is skipped and Do returns false without an error.
+func Do(ctx context.Context, locker Locker, name string, fn func(ctx context.Context) error) (bool, error) {
+	l, err := locker.TryAcquire(ctx, name)
+	if errors.Is(err, ErrNotAcquired) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	// Release even if ctx was cancelled during fn.
+	defer l.Release(context.WithoutCancel(ctx))
+	return true, fn(ctx)
+}
+
+// Postgres locks with session-level advisory locks. Each held lock pins a
+// pool connection until released; if that connection drops, Postgres frees
+// the lock.
+type Postgres struct {
+	pool   *pgxpool.Pool
+	logger *logging.Logger
+}
+
+func NewPostgres(pool *pgxpool.Pool, base *slog.Logger) *Postgres {
+	return &Postgres{pool: pool, logger: logging.New(base.With("component", "lock"))}
+}
+
+// TryAcquire takes the advisory lock keyed by a hash of name. The fencing
+// token is a fresh transaction ID, which only grows across the cluster.
+func (p *Postgres) TryAcquire(ctx context.Context, name string) (*Lock, error) {
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection for lock %s: %w", name, err)
+	}
+
+	var acquired bool
+	var token int64
+	err = conn.QueryRow(ctx,
+		"SELECT pg_try_advisory_lock(hashtextextended($1, 0)), pg_current_xact_id()::text::bigint",
+		name,
+	).Scan(&acquired, &token)
+	if err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("failed to take lock %s: %w", name, err)
+	}
+	if !acquired {
+		conn.Release()
+		return nil, ErrNotAcquired
+	}
+
+	p.logger.DebugCtx(ctx, "Lock acquired", "lock", name, "token", token)
+	return &Lock{name: name, token: token, release: func(ctx context.Context) error {
+		defer conn.Release()
+		var released bool
+		if err := conn.QueryRow(ctx, "SELECT pg_advisory_unlock(hashtextextended($1, 0))", name).Scan(&released); err != nil {
+			// Closing the connection frees the lock on the server.
+			conn.Conn().Close(ctx)
+			return fmt.Errorf("failed to release lock %s: %w", name, err)
+		}
+		if !released {
+			p.logger.WarnCtx(ctx, "Lock was no longer held on release", "lock", name, "token", token)
+		}
+		return nil
+	}}, nil
+}
+
+// Local locks within one process, for single-instance deployments and the
+// in-memory test server.
+type Local struct {
+	mu    sync.Mutex
+	held  map[string]bool
+	token int64
+}
+
+func NewLocal() *Local {
+	return &Local{held: make(map[string]bool)}
+}
+
+func (l *Local) TryAcquire(ctx context.Context, name string) (*Lock, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[name] {
+		return nil, ErrNotAcquired
+	}
+	l.held[name] = true
+	l.token++
+	return &Lock{name: name, token: l.token, release: func(context.Context) error {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, name)
+		return nil
+	}}, nil
+}
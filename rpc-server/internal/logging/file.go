@@ -0,0 +1,194 @@
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileConfig configures a RotatingFile.
+type FileConfig struct {
+	Path string
+	// MaxSizeBytes rotates the file before a write would take it past this
+	// size; 0 disables size-based rotation.
+	MaxSizeBytes int64
+	// RotateEvery rotates files older than this; 0 disables time-based
+	// rotation.
+	RotateEvery time.Duration
+	MaxBackups  int  // rotated files kept; 0 keeps all of them
+	Compress    bool // gzip rotated files
+	// Sync flushes every write to disk before returning, for records that
+	// must survive a crash.
+	Sync bool
+}
+
+// RotatingFile is a log file that is renamed aside and reopened when it gets
+// too large or too old. Rotated files are named after the time of rotation,
+// e.g. server-20261016T101500.000.log, and compressed in the background.
+type RotatingFile struct {
+	cfg FileConfig
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// cleanup serializes compression and pruning of rotated files.
+	cleanup sync.Mutex
+	pending sync.WaitGroup
+}
+
+// OpenRotatingFile opens or creates cfg.Path for appending.
+func OpenRotatingFile(cfg FileConfig) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	f := &RotatingFile{cfg: cfg}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = time.Now()
+	return nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	tooLarge := f.cfg.MaxSizeBytes > 0 && f.size > 0 && f.size+int64(len(p)) > f.cfg.MaxSizeBytes
+	tooOld := f.cfg.RotateEvery > 0 && now.Sub(f.openedAt) >= f.cfg.RotateEvery
+	if tooLarge || tooOld {
+		if err := f.rotate(now); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err == nil && f.cfg.Sync {
+		err = f.file.Sync()
+	}
+	return n, err
+}
+
+// Close closes the file after background compression finishes.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pending.Wait()
+	return f.file.Close()
+}
+
+func (f *RotatingFile) rotate(now time.Time) error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	backup := f.backupName(now)
+	if err := os.Rename(f.cfg.Path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.pending.Add(1)
+	go func() {
+		defer f.pending.Done()
+		f.cleanup.Lock()
+		defer f.cleanup.Unlock()
+		if f.cfg.Compress {
+			// An earlier prune may already have removed the file
+			if err := compress(backup); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				fmt.Fprintf(os.Stderr, "failed to compress rotated log %s: %v\n", backup, err)
+			}
+		}
+		f.prune()
+	}()
+	return nil
+}
+
+// backupName names the file rotated at now, moving past names still in use
+// when rotations come faster than the timestamp resolution.
+func (f *RotatingFile) backupName(now time.Time) string {
+	ext := filepath.Ext(f.cfg.Path)
+	for {
+		name := strings.TrimSuffix(f.cfg.Path, ext) + "-" + now.UTC().Format("20060102T150405.000") + ext
+		if !exists(name) && !exists(name+".gz") {
+			return name
+		}
+		now = now.Add(time.Millisecond)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// compress replaces path with path.gz.
+func compress(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// prune removes the oldest rotated files beyond MaxBackups. Rotated names
+// sort by rotation time.
+func (f *RotatingFile) prune() {
+	if f.cfg.MaxBackups <= 0 {
+		return
+	}
+	ext := filepath.Ext(f.cfg.Path)
+	backups, err := filepath.Glob(strings.TrimSuffix(f.cfg.Path, ext) + "-*" + ext + "*")
+	if err != nil || len(backups) <= f.cfg.MaxBackups {
+		return
+	}
+	slices.Sort(backups)
+	for _, old := range backups[:len(backups)-f.cfg.MaxBackups] {
+		if err := os.Remove(old); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to remove rotated log %s: %v\n", old, err)
+		}
+	}
+}
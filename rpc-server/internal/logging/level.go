@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Subsystems that can log at their own level. Tag a subsystem's logger with
+// ForModule.
+const (
+	ModuleCache      = "cache"
+	ModuleRepository = "repository"
+	ModuleServer     = "server"
+	ModuleJobs       = "jobs"
+)
+
+// Modules lists every subsystem with its own level.
+var Modules = []string{ModuleCache, ModuleRepository, ModuleServer, ModuleJobs}
+
+// ForModule tags logger with module so ModuleHandler applies its level.
+func ForModule(logger *slog.Logger, module string) *slog.Logger {
+	return logger.With(Module, module)
+}
+
+// ModuleLeveler reports the minimum level of a module; module is empty for
+// records outside any subsystem.
+type ModuleLeveler interface {
+	ModuleLevel(module string) slog.Level
+}
+
+// ModuleHandler filters records by the level of the module their logger was
+// tagged with, so one subsystem can log at DEBUG without the rest. It
+// replaces the level of the handler it wraps.
+type ModuleHandler struct {
+	h      slog.Handler
+	levels ModuleLeveler
+	module string
+}
+
+func NewModuleHandler(h slog.Handler, levels ModuleLeveler) slog.Handler {
+	return &ModuleHandler{h: h, levels: levels}
+}
+
+func (m *ModuleHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= m.levels.ModuleLevel(m.module)
+}
+
+func (m *ModuleHandler) Handle(ctx context.Context, r slog.Record) error {
+	return m.h.Handle(ctx, r)
+}
+
+func (m *ModuleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	module := m.module
+	for _, a := range attrs {
+		if a.Key == Module {
+			module = a.Value.String()
+		}
+	}
+	return &ModuleHandler{h: m.h.WithAttrs(attrs), levels: m.levels, module: module}
+}
+
+func (m *ModuleHandler) WithGroup(name string) slog.Handler {
+	return &ModuleHandler{h: m.h.WithGroup(name), levels: m.levels, module: m.module}
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+)
+
+// Redaction modes.
+const (
+	// RedactMask replaces values with a fixed placeholder.
+	RedactMask = "mask"
+	// RedactHash replaces values with a keyed hash, so records about the same
+	// value can still be correlated without revealing it.
+	RedactHash = "hash"
+)
+
+const redacted = "[REDACTED]"
+
+// RedactConfig selects the attributes a Redactor scrubs.
+type RedactConfig struct {
+	Keys []string // exact attribute keys, e.g. UserEmail
+	// Patterns are regular expressions matched against attribute keys, e.g.
+	// "(?i)token|secret".
+	Patterns []string
+	Mode     string // RedactMask or RedactHash; empty masks
+	Salt     string // keys RedactHash; use a secret so hashes resist guessing
+}
+
+// Redactor scrubs sensitive attributes from log records. Pass ReplaceAttr in
+// the slog.HandlerOptions of the handler that writes the records.
+type Redactor struct {
+	keys     []string
+	patterns []*regexp.Regexp
+	hash     bool
+	salt     []byte
+}
+
+func NewRedactor(cfg RedactConfig) (*Redactor, error) {
+	r := &Redactor{keys: cfg.Keys, salt: []byte(cfg.Salt)}
+	switch cfg.Mode {
+	case "", RedactMask:
+	case RedactHash:
+		r.hash = true
+	default:
+		return nil, fmt.Errorf("unknown redaction mode %q", cfg.Mode)
+	}
+	for _, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// ReplaceAttr implements slog.HandlerOptions.ReplaceAttr. Attributes inside
+// groups are matched by their own key.
+func (r *Redactor) ReplaceAttr(_ []string, a slog.Attr) slog.Attr {
+	if !r.matches(a.Key) {
+		return a
+	}
+	if !r.hash {
+		return slog.String(a.Key, redacted)
+	}
+	mac := hmac.New(sha256.New, r.salt)
+	mac.Write([]byte(a.Value.String()))
+	return slog.String(a.Key, "sha256:"+hex.EncodeToString(mac.Sum(nil)[:8]))
+}
+
+func (r *Redactor) matches(key string) bool {
+	if slices.Contains(r.keys, key) {
+		return true
+	}
+	return slices.ContainsFunc(r.patterns, func(re *regexp.Regexp) bool {
+		return re.MatchString(key)
+	})
+}
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"grpc-server/internal/tracing"
+	"grpc-server/internal/transport"
+)
+
+// Request field names set by UnaryServerInterceptor.
+const (
+	Method    = "method"
+	RequestID = "request_id"
+	Caller    = "caller"
+)
+
+// RequestIDMetadataKey carries the request ID. A caller-supplied value is
+// kept so logs can be correlated across services; either way it is echoed
+// back in the response header.
+const RequestIDMetadataKey = "x-request-id"
+
+// maxRequestIDLength bounds caller-supplied request IDs; longer ones are
+// replaced rather than logged.
+const maxRequestIDLength = 128
+
+type loggerKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger stored by
+// UnaryServerInterceptor, or the default logger outside a request. trace_id
+// is added to every record by TraceContextHandler, not stored here.
+func FromContext(ctx context.Context) *Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*Logger); ok {
+		return logger
+	}
+	return New(slog.Default())
+}
+
+// UnaryServerInterceptor stores a logger derived from base with the method,
+// request ID and caller in the context of every call; see FromContext.
+func UnaryServerInterceptor(base *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withRequestLogger(ctx, base, info.FullMethod), req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls.
+func StreamServerInterceptor(base *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, transport.WithStreamContext(ss, withRequestLogger(ss.Context(), base, info.FullMethod)))
+	}
+}
+
+func withRequestLogger(ctx context.Context, base *slog.Logger, fullMethod string) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := first(md, RequestIDMetadataKey)
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		requestID = uuid.NewString()
+	}
+	// Outside a real gRPC call, e.g. in direct handler calls, there is no
+	// header to set.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, requestID))
+
+	attrs := []any{Method, fullMethod, RequestID, requestID}
+	if caller := first(md, tracing.TenantMetadataKey); caller != "" {
+		attrs = append(attrs, Caller, caller)
+	}
+	return WithLogger(ctx, New(base.With(attrs...)))
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// SamplingHandler thins out repetitive DEBUG and INFO records: each second
+// the first First records with the same message are written, then one in
+// every Thereafter. WARN and above are always written.
+type SamplingHandler struct {
+	h slog.Handler
+	s *sampler
+}
+
+type sampler struct {
+	first      int
+	thereafter int
+
+	mu     sync.Mutex
+	second int64
+	counts map[string]int
+}
+
+// NewSamplingHandler wraps h; thereafter <= 0 drops every record past the
+// first in a second.
+func NewSamplingHandler(h slog.Handler, first, thereafter int) slog.Handler {
+	return &SamplingHandler{h: h, s: &sampler{
+		first:      first,
+		thereafter: thereafter,
+		counts:     make(map[string]int),
+	}}
+}
+
+func (s *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return s.h.Enabled(ctx, level)
+}
+
+func (s *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level > slog.LevelInfo || s.s.allow(r) {
+		return s.h.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (s *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{h: s.h.WithAttrs(attrs), s: s.s}
+}
+
+func (s *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{h: s.h.WithGroup(name), s: s.s}
+}
+
+// allow counts r against its message's budget for the current second.
+func (s *sampler) allow(r slog.Record) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if second := r.Time.Unix(); second != s.second {
+		s.second = second
+		clear(s.counts)
+	}
+	s.counts[r.Message]++
+	n := s.counts[r.Message]
+	if n <= s.first {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.first)%s.thereafter == 0
+}
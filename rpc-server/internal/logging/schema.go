@@ -7,11 +7,14 @@ import (
 
 // Standard field names - use these consistently across all logging
 const (
-	UserID    = "user_id"
-	UserEmail = "user_email"
-	CacheKey  = "cache_key"
-	Error     = "error"
-	TraceID   = "trace_id"
+	UserID     = "user_id"
+	UserEmail  = "user_email"
+	CacheKey   = "cache_key"
+	Error      = "error"
+	TraceID    = "trace_id"
+	SpanID     = "span_id"
+	TraceFlags = "trace_flags"
+	Module     = "module"
 )
 
 // Logger wraps slog.Logger with consistent field names
@@ -7,8 +7,9 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-// TraceContextHandler wraps a slog.Handler and injects trace_id from the context into every record.
-// Use this to ensure all logs include trace_id when context carries an OpenTelemetry span.
+// TraceContextHandler wraps a slog.Handler and injects trace_id, span_id and trace_flags from the
+// context into every record, so a log line can be matched to the exact span that wrote it.
+// Use this to ensure all logs carry the trace context when ctx carries an OpenTelemetry span.
 // Wrap your base handler with NewTraceContextHandler in main when creating the logger.
 
 type TraceContextHandler struct {
@@ -26,7 +27,11 @@ func (t *TraceContextHandler) Enabled(ctx context.Context, level slog.Level) boo
 func (t *TraceContextHandler) Handle(ctx context.Context, r slog.Record) error {
 	sc := trace.SpanContextFromContext(ctx)
 	if sc.IsValid() {
-		r.AddAttrs(slog.String(TraceID, sc.TraceID().String()))
+		r.AddAttrs(
+			slog.String(TraceID, sc.TraceID().String()),
+			slog.String(SpanID, sc.SpanID().String()),
+			slog.String(TraceFlags, sc.TraceFlags().String()),
+		)
 	}
 	return t.h.Handle(ctx, r)
 }
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor counts calls by method and status code and records
+// how long the handler took. Install it first so the latency and code are
+// those the caller sees.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	meter := otel.Meter(meterName)
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	handled, _ := meter.Int64Counter("grpc.server.handled",
+		metric.WithDescription("Number of unary calls completed, by method and status code"),
+		metric.WithUnit("{call}"),
+	)
+	handling, _ := meter.Float64Histogram("grpc.server.handling",
+		metric.WithDescription("Time taken to handle unary calls"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10),
+	)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		elapsed := time.Since(start)
+
+		attrs := metric.WithAttributes(
+			attribute.String("grpc.service", path.Base(path.Dir(info.FullMethod))),
+			attribute.String("grpc.method", path.Base(info.FullMethod)),
+			attribute.String("grpc.code", status.Code(err).String()),
+		)
+		handled.Add(ctx, 1, attrs)
+		handling.Record(ctx, elapsed.Seconds(), attrs)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls,
+// recording how long each stream stayed open.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	meter := otel.Meter(meterName)
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	handled, _ := meter.Int64Counter("grpc.server.streams.handled",
+		metric.WithDescription("Number of streaming calls completed, by method and status code"),
+		metric.WithUnit("{call}"),
+	)
+	handling, _ := meter.Float64Histogram("grpc.server.streams.handling",
+		metric.WithDescription("Time streaming calls stayed open"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.01, 0.1, 0.5, 1, 5, 10, 30, 60, 300, 600),
+	)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		elapsed := time.Since(start)
+
+		ctx := ss.Context()
+		attrs := metric.WithAttributes(
+			attribute.String("grpc.service", path.Base(path.Dir(info.FullMethod))),
+			attribute.String("grpc.method", path.Base(info.FullMethod)),
+			attribute.String("grpc.code", status.Code(err).String()),
+		)
+		handled.Add(ctx, 1, attrs)
+		handling.Record(ctx, elapsed.Seconds(), attrs)
+		return err
+	}
+}
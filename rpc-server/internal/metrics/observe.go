@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"grpc-server/internal/cache"
+)
+
+// ObservePools reports the connection counts of pools, keyed by name such as
+// "shard-1", as gauges read on every collection.
+func ObservePools(pools map[string]*pgxpool.Pool) error {
+	meter := otel.Meter(meterName)
+	acquired, _ := meter.Int64ObservableGauge("db.pool.conns.acquired",
+		metric.WithDescription("Pool connections currently in use"),
+		metric.WithUnit("{connection}"),
+	)
+	idle, _ := meter.Int64ObservableGauge("db.pool.conns.idle",
+		metric.WithDescription("Pool connections currently idle"),
+		metric.WithUnit("{connection}"),
+	)
+	total, _ := meter.Int64ObservableGauge("db.pool.conns.total",
+		metric.WithDescription("Pool connections open, including ones being established"),
+		metric.WithUnit("{connection}"),
+	)
+	maxConns, _ := meter.Int64ObservableGauge("db.pool.conns.max",
+		metric.WithDescription("Largest number of connections the pool may open"),
+		metric.WithUnit("{connection}"),
+	)
+	acquires, _ := meter.Int64ObservableCounter("db.pool.acquires",
+		metric.WithDescription("Connections acquired from the pool"),
+		metric.WithUnit("{acquire}"),
+	)
+	emptyAcquires, _ := meter.Int64ObservableCounter("db.pool.acquires.waited",
+		metric.WithDescription("Acquires that had to wait because no connection was idle"),
+		metric.WithUnit("{acquire}"),
+	)
+
+	_, err := meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		for name, pool := range pools {
+			stat := pool.Stat()
+			attrs := metric.WithAttributes(attribute.String("db.pool", name))
+			o.ObserveInt64(acquired, int64(stat.AcquiredConns()), attrs)
+			o.ObserveInt64(idle, int64(stat.IdleConns()), attrs)
+			o.ObserveInt64(total, int64(stat.TotalConns()), attrs)
+			o.ObserveInt64(maxConns, int64(stat.MaxConns()), attrs)
+			o.ObserveInt64(acquires, stat.AcquireCount(), attrs)
+			o.ObserveInt64(emptyAcquires, stat.EmptyAcquireCount(), attrs)
+		}
+		return nil
+	}, acquired, idle, total, maxConns, acquires, emptyAcquires)
+	return err
+}
+
+// ObserveCache reports the lookups counted by stats, per key namespace, as
+// counters read on every collection.
+func ObserveCache(stats *cache.StatsCache) error {
+	meter := otel.Meter(meterName)
+	lookups, _ := meter.Int64ObservableCounter("cache.lookups",
+		metric.WithDescription("Cache lookups by key namespace and result: hit, miss or error"),
+		metric.WithUnit("{lookup}"),
+	)
+
+	_, err := meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		counts, _ := stats.Counts()
+		for _, c := range counts {
+			ns := attribute.String("cache.namespace", c.Namespace)
+			o.ObserveInt64(lookups, c.Hits, metric.WithAttributes(ns, attribute.String("result", "hit")))
+			o.ObserveInt64(lookups, c.Misses, metric.WithAttributes(ns, attribute.String("result", "miss")))
+			o.ObserveInt64(lookups, c.Errors, metric.WithAttributes(ns, attribute.String("result", "error")))
+		}
+		return nil
+	}, lookups)
+	return err
+}
@@ -0,0 +1,189 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// unitSuffixes maps OpenTelemetry units to the suffixes Prometheus names
+// carry. Annotations such as "{call}" add no suffix.
+var unitSuffixes = map[string]string{
+	"s":  "seconds",
+	"ms": "milliseconds",
+	"By": "bytes",
+}
+
+// family is one metric in the text format. Instruments of the same name
+// from different meters share it.
+type family struct {
+	help    string
+	kind    string // counter, gauge or histogram
+	samples []string
+}
+
+// writeText writes rm in the Prometheus text exposition format, families
+// sorted by name. Exponential histograms and summaries are left out.
+func writeText(w io.Writer, rm *metricdata.ResourceMetrics) {
+	families := make(map[string]*family)
+	add := func(name, help, kind string, samples []string) {
+		f, ok := families[name]
+		if !ok {
+			f = &family{help: help, kind: kind}
+			families[name] = f
+		}
+		if f.kind == kind {
+			f.samples = append(f.samples, samples...)
+		}
+	}
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			name := metricName(m.Name, m.Unit)
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				name, kind := sumName(name, data.IsMonotonic)
+				add(name, m.Description, kind, points(name, data.DataPoints))
+			case metricdata.Sum[float64]:
+				name, kind := sumName(name, data.IsMonotonic)
+				add(name, m.Description, kind, points(name, data.DataPoints))
+			case metricdata.Gauge[int64]:
+				add(name, m.Description, "gauge", points(name, data.DataPoints))
+			case metricdata.Gauge[float64]:
+				add(name, m.Description, "gauge", points(name, data.DataPoints))
+			case metricdata.Histogram[int64]:
+				add(name, m.Description, "histogram", histogramPoints(name, data.DataPoints))
+			case metricdata.Histogram[float64]:
+				add(name, m.Description, "histogram", histogramPoints(name, data.DataPoints))
+			}
+		}
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		f := families[name]
+		if f.help != "" {
+			fmt.Fprintf(w, "# HELP %s %s\n", name, escapeHelp(f.help))
+		}
+		fmt.Fprintf(w, "# TYPE %s %s\n", name, f.kind)
+		for _, sample := range f.samples {
+			io.WriteString(w, sample)
+		}
+	}
+}
+
+// metricName turns an OpenTelemetry name such as "db.pool.acquire.duration"
+// with unit "ms" into "db_pool_acquire_duration_milliseconds".
+func metricName(name, unit string) string {
+	name = sanitize(name)
+	if suffix, ok := unitSuffixes[unit]; ok && !strings.HasSuffix(name, "_"+suffix) {
+		name += "_" + suffix
+	}
+	return name
+}
+
+// sumName returns the family name and type of a sum: monotonic sums are
+// counters, named with "_total", the others gauges.
+func sumName(name string, monotonic bool) (string, string) {
+	if !monotonic {
+		return name, "gauge"
+	}
+	return name + "_total", "counter"
+}
+
+func points[N int64 | float64](name string, dps []metricdata.DataPoint[N]) []string {
+	samples := make([]string, 0, len(dps))
+	for _, dp := range dps {
+		samples = append(samples, sample(name, labels(dp.Attributes), float64(dp.Value)))
+	}
+	return samples
+}
+
+func histogramPoints[N int64 | float64](name string, dps []metricdata.HistogramDataPoint[N]) []string {
+	var samples []string
+	for _, dp := range dps {
+		base := labels(dp.Attributes)
+		var cumulative uint64
+		for i, bound := range dp.Bounds {
+			cumulative += dp.BucketCounts[i]
+			samples = append(samples, sample(name+"_bucket", slices.Concat(base, []label{{"le", formatFloat(bound)}}), float64(cumulative)))
+		}
+		samples = append(samples,
+			sample(name+"_bucket", slices.Concat(base, []label{{"le", "+Inf"}}), float64(dp.Count)),
+			sample(name+"_sum", base, float64(dp.Sum)),
+			sample(name+"_count", base, float64(dp.Count)),
+		)
+	}
+	return samples
+}
+
+type label struct {
+	name, value string
+}
+
+// labels returns the attributes of a data point, sorted by key as
+// attribute.Set keeps them.
+func labels(set attribute.Set) []label {
+	result := make([]label, 0, set.Len())
+	for _, kv := range set.ToSlice() {
+		result = append(result, label{sanitize(string(kv.Key)), kv.Value.Emit()})
+	}
+	return result
+}
+
+func sample(name string, labels []label, value float64) string {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(&b, "%s=\"%s\"", l.name, escapeLabel(l.value))
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(value))
+	b.WriteByte('\n')
+	return b.String()
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sanitize replaces the characters Prometheus doesn't allow in names.
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == ':' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
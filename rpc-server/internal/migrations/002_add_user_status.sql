@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'expired'));
+
+-- Supports the inactive account expiry scan
+CREATE INDEX idx_users_status_updated_at ON users(status, updated_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_status_updated_at;
+ALTER TABLE users DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every version of every user, valid over [valid_from, valid_to). The current
+-- version has valid_to NULL; a deleted user has no current version.
+CREATE TABLE user_history (
+    history_id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    age INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    valid_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    valid_to TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_user_history_user_id_valid_from ON user_history(user_id, valid_from);
+
+CREATE OR REPLACE FUNCTION record_user_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE user_history SET valid_to = NOW()
+        WHERE user_id = OLD.id AND valid_to IS NULL;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO user_history (user_id, name, email, age, status, created_at, updated_at, valid_from)
+        VALUES (NEW.id, NEW.name, NEW.email, NEW.age, NEW.status, NEW.created_at, NEW.updated_at, NOW());
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_users_history
+    AFTER INSERT OR UPDATE OR DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION record_user_history();
+
+-- Earlier versions are unknown; start each existing user's history at its
+-- last update.
+INSERT INTO user_history (user_id, name, email, age, status, created_at, updated_at, valid_from)
+SELECT id, name, email, age, status, created_at, updated_at, COALESCE(updated_at, NOW())
+FROM users;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS record_users_history ON users;
+DROP FUNCTION IF EXISTS record_user_history();
+DROP TABLE IF EXISTS user_history;
+-- +goose StatementEnd
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A merged user is soft-deleted: the row stays so references to its ID can be
+-- resolved to merged_into, the surviving user. merged_into becomes NULL if
+-- the surviving user is later deleted.
+ALTER TABLE users
+    DROP CONSTRAINT users_status_check,
+    ADD CONSTRAINT users_status_check CHECK (status IN ('active', 'expired', 'merged')),
+    ADD COLUMN merged_into UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD CONSTRAINT users_merged_into_check CHECK (merged_into IS NULL OR status = 'merged');
+
+ALTER TABLE user_history ADD COLUMN merged_into UUID;
+
+CREATE OR REPLACE FUNCTION record_user_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE user_history SET valid_to = NOW()
+        WHERE user_id = OLD.id AND valid_to IS NULL;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO user_history (user_id, name, email, age, status, merged_into, created_at, updated_at, valid_from)
+        VALUES (NEW.id, NEW.name, NEW.email, NEW.age, NEW.status, NEW.merged_into, NEW.created_at, NEW.updated_at, NOW());
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION record_user_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE user_history SET valid_to = NOW()
+        WHERE user_id = OLD.id AND valid_to IS NULL;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO user_history (user_id, name, email, age, status, created_at, updated_at, valid_from)
+        VALUES (NEW.id, NEW.name, NEW.email, NEW.age, NEW.status, NEW.created_at, NEW.updated_at, NOW());
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+ALTER TABLE user_history DROP COLUMN IF EXISTS merged_into;
+
+-- Merged users have no representation before this migration; keep the rows
+-- but mark them expired.
+UPDATE users SET status = 'expired', merged_into = NULL WHERE status = 'merged';
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_merged_into_check,
+    DROP COLUMN IF EXISTS merged_into,
+    DROP CONSTRAINT users_status_check,
+    ADD CONSTRAINT users_status_check CHECK (status IN ('active', 'expired'));
+-- +goose StatementEnd
@@ -0,0 +1,133 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Hash-partition users by id so lookups by ID touch one partition and
+-- vacuum, reindexing and bulk maintenance work a partition at a time.
+--
+-- A unique constraint on a partitioned table must include the partition key,
+-- so email uniqueness moves to user_emails, kept in step by a trigger.
+-- Duplicate emails now fail on user_emails_pkey instead of users_email_key.
+CREATE TABLE user_emails (
+    email VARCHAR(255) PRIMARY KEY,
+    user_id UUID NOT NULL
+);
+
+INSERT INTO user_emails (email, user_id)
+SELECT email, id FROM users;
+
+CREATE OR REPLACE FUNCTION maintain_user_emails()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        DELETE FROM user_emails WHERE email = OLD.email AND user_id = OLD.id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO user_emails (email, user_id) VALUES (NEW.email, NEW.id);
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+-- Same columns in the same order, so generated queries are unchanged.
+CREATE TABLE users_partitioned (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    age INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    status VARCHAR(16) NOT NULL DEFAULT 'active',
+    merged_into UUID
+) PARTITION BY HASH (id);
+
+DO $$
+BEGIN
+    FOR i IN 0..7 LOOP
+        EXECUTE format(
+            'CREATE TABLE users_p%s PARTITION OF users_partitioned FOR VALUES WITH (MODULUS 8, REMAINDER %s)',
+            i, i
+        );
+    END LOOP;
+END;
+$$;
+
+-- The new table has no triggers yet, so copying records no history.
+INSERT INTO users_partitioned (id, name, email, age, created_at, updated_at, status, merged_into)
+SELECT id, name, email, age, created_at, updated_at, status, merged_into FROM users;
+
+ALTER TABLE email_changes DROP CONSTRAINT email_changes_user_id_fkey;
+DROP TABLE users;
+ALTER TABLE users_partitioned RENAME TO users;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_pkey PRIMARY KEY (id),
+    ADD CONSTRAINT users_age_check CHECK (age > 0 AND age < 150),
+    ADD CONSTRAINT users_status_check CHECK (status IN ('active', 'expired', 'merged')),
+    ADD CONSTRAINT users_merged_into_check CHECK (merged_into IS NULL OR status = 'merged'),
+    ADD CONSTRAINT users_merged_into_fkey FOREIGN KEY (merged_into) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE email_changes
+    ADD CONSTRAINT email_changes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_users_email ON users(email);
+CREATE INDEX idx_users_created_at ON users(created_at);
+CREATE INDEX idx_users_status_updated_at ON users(status, updated_at);
+
+CREATE TRIGGER update_users_updated_at
+    BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER record_users_history
+    AFTER INSERT OR UPDATE OR DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION record_user_history();
+
+CREATE TRIGGER maintain_users_emails
+    AFTER INSERT OR UPDATE OF email OR DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION maintain_user_emails();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE users_unpartitioned (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    age INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    status VARCHAR(16) NOT NULL DEFAULT 'active',
+    merged_into UUID
+);
+
+INSERT INTO users_unpartitioned (id, name, email, age, created_at, updated_at, status, merged_into)
+SELECT id, name, email, age, created_at, updated_at, status, merged_into FROM users;
+
+ALTER TABLE email_changes DROP CONSTRAINT email_changes_user_id_fkey;
+DROP TABLE users;
+ALTER TABLE users_unpartitioned RENAME TO users;
+ALTER INDEX users_unpartitioned_pkey RENAME TO users_pkey;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_email_key UNIQUE (email),
+    ADD CONSTRAINT users_age_check CHECK (age > 0 AND age < 150),
+    ADD CONSTRAINT users_status_check CHECK (status IN ('active', 'expired', 'merged')),
+    ADD CONSTRAINT users_merged_into_check CHECK (merged_into IS NULL OR status = 'merged'),
+    ADD CONSTRAINT users_merged_into_fkey FOREIGN KEY (merged_into) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE email_changes
+    ADD CONSTRAINT email_changes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_users_email ON users(email);
+CREATE INDEX idx_users_created_at ON users(created_at);
+CREATE INDEX idx_users_status_updated_at ON users(status, updated_at);
+
+CREATE TRIGGER update_users_updated_at
+    BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER record_users_history
+    AFTER INSERT OR UPDATE OR DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION record_user_history();
+
+DROP FUNCTION IF EXISTS maintain_user_emails();
+DROP TABLE IF EXISTS user_emails;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Trigram indexes let name and email prefix searches (LIKE 'abc%') use an
+-- index for autocomplete instead of scanning every partition. They are on
+-- lower() because searches are case-insensitive.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_users_name_trgm ON users USING gin (lower(name) gin_trgm_ops);
+CREATE INDEX idx_users_email_trgm ON users USING gin (lower(email) gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_email_trgm;
+DROP INDEX IF EXISTS idx_users_name_trgm;
+-- +goose StatementEnd
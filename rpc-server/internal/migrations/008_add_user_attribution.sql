@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Who created and last changed each user: the authenticated caller, or
+-- system:<job> for background jobs. Rows written before attribution existed
+-- keep an empty value.
+ALTER TABLE users
+    ADD COLUMN created_by VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN updated_by VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN updated_by,
+    DROP COLUMN created_by;
+-- +goose StatementEnd
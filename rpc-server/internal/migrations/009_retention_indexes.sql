@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Let the retention job find closed history versions and expired email
+-- changes without scanning either table.
+CREATE INDEX idx_user_history_valid_to ON user_history(valid_to) WHERE valid_to IS NOT NULL;
+CREATE INDEX idx_email_changes_expires_at ON email_changes(expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_email_changes_expires_at;
+DROP INDEX IF EXISTS idx_user_history_valid_to;
+-- +goose StatementEnd
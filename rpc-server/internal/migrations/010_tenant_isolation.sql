@@ -0,0 +1,136 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Row-level security confines every statement to the rows of one tenant, so
+-- a repository bug can't return or change another tenant's users. The
+-- repository sets app.tenant_id in each transaction to the tenant of the
+-- caller's token; background jobs and maintenance commands set
+-- app.all_tenants to 'on' instead. Rows written before tenancy, and by
+-- callers without a tenant, belong to the shared tenant ''.
+--
+-- Superusers and roles with BYPASSRLS skip the policies even when they are
+-- forced, so the service must connect as an ordinary role.
+CREATE OR REPLACE FUNCTION tenant_visible(row_tenant VARCHAR)
+RETURNS BOOLEAN AS $$
+    SELECT row_tenant = current_setting('app.tenant_id', true)
+        OR current_setting('app.all_tenants', true) = 'on'
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE users ADD COLUMN tenant_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE user_history ADD COLUMN tenant_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE user_emails ADD COLUMN tenant_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE email_changes ADD COLUMN tenant_id VARCHAR(255) NOT NULL DEFAULT '';
+
+-- New rows belong to the tenant of the transaction writing them. Without
+-- one set, the default is NULL and the insert fails.
+ALTER TABLE users ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id', true);
+ALTER TABLE user_history ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id', true);
+ALTER TABLE user_emails ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id', true);
+ALTER TABLE email_changes ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id', true);
+
+-- Emails are unique within a tenant; two tenants may each have a user with
+-- the same address.
+ALTER TABLE user_emails
+    DROP CONSTRAINT user_emails_pkey,
+    ADD CONSTRAINT user_emails_pkey PRIMARY KEY (tenant_id, email);
+
+-- List pages read one tenant's users newest first.
+CREATE INDEX idx_users_tenant_id_created_at ON users(tenant_id, created_at);
+
+-- Triggers copy the tenant from the user, since background jobs writing
+-- for every tenant have none of their own.
+CREATE OR REPLACE FUNCTION record_user_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE user_history SET valid_to = NOW()
+        WHERE user_id = OLD.id AND valid_to IS NULL;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO user_history (tenant_id, user_id, name, email, age, status, merged_into, created_at, updated_at, valid_from)
+        VALUES (NEW.tenant_id, NEW.id, NEW.name, NEW.email, NEW.age, NEW.status, NEW.merged_into, NEW.created_at, NEW.updated_at, NOW());
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE OR REPLACE FUNCTION maintain_user_emails()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        DELETE FROM user_emails WHERE tenant_id = OLD.tenant_id AND email = OLD.email AND user_id = OLD.id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO user_emails (tenant_id, email, user_id) VALUES (NEW.tenant_id, NEW.email, NEW.id);
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+-- FORCE applies the policies to the table owner too, which the service
+-- usually connects as.
+ALTER TABLE users ENABLE ROW LEVEL SECURITY, FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_history ENABLE ROW LEVEL SECURITY, FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_emails ENABLE ROW LEVEL SECURITY, FORCE ROW LEVEL SECURITY;
+ALTER TABLE email_changes ENABLE ROW LEVEL SECURITY, FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON users USING (tenant_visible(tenant_id));
+CREATE POLICY tenant_isolation ON user_history USING (tenant_visible(tenant_id));
+CREATE POLICY tenant_isolation ON user_emails USING (tenant_visible(tenant_id));
+CREATE POLICY tenant_isolation ON email_changes USING (tenant_visible(tenant_id));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY IF EXISTS tenant_isolation ON email_changes;
+DROP POLICY IF EXISTS tenant_isolation ON user_emails;
+DROP POLICY IF EXISTS tenant_isolation ON user_history;
+DROP POLICY IF EXISTS tenant_isolation ON users;
+
+ALTER TABLE email_changes NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE user_emails NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE user_history NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE users NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+
+CREATE OR REPLACE FUNCTION maintain_user_emails()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        DELETE FROM user_emails WHERE email = OLD.email AND user_id = OLD.id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO user_emails (email, user_id) VALUES (NEW.email, NEW.id);
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE OR REPLACE FUNCTION record_user_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE user_history SET valid_to = NOW()
+        WHERE user_id = OLD.id AND valid_to IS NULL;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO user_history (user_id, name, email, age, status, merged_into, created_at, updated_at, valid_from)
+        VALUES (NEW.id, NEW.name, NEW.email, NEW.age, NEW.status, NEW.merged_into, NEW.created_at, NEW.updated_at, NOW());
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP INDEX IF EXISTS idx_users_tenant_id_created_at;
+
+-- Fails if two tenants share an email; those users must be merged or
+-- removed first.
+ALTER TABLE user_emails
+    DROP CONSTRAINT user_emails_pkey,
+    ADD CONSTRAINT user_emails_pkey PRIMARY KEY (email);
+
+ALTER TABLE email_changes DROP COLUMN tenant_id;
+ALTER TABLE user_emails DROP COLUMN tenant_id;
+ALTER TABLE user_history DROP COLUMN tenant_id;
+ALTER TABLE users DROP COLUMN tenant_id;
+
+DROP FUNCTION IF EXISTS tenant_visible(VARCHAR);
+-- +goose StatementEnd
@@ -0,0 +1,243 @@
+// Package migrations embeds the SQL migrations that build the database
+// schema and applies the pending ones, recording each applied version in
+// the schema_migrations table.
+//
+// Files are named NNN_description.sql and use goose annotations: only the
+// statements between "-- +goose Up" and "-- +goose Down" are applied, in one
+// transaction per file.
+package migrations
+
+import (
+	"cmp"
+	"context"
+	"embed"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grpc-server/internal/logging"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// lockName keys the advisory lock that keeps replicas starting together
+// from applying the same migration twice; the others wait for the first.
+const lockName = "schema_migrations"
+
+// Migration is one embedded migration file.
+type Migration struct {
+	Version int64
+	Name    string
+	// Up holds the statements that apply the migration.
+	Up string
+}
+
+// All returns the embedded migrations, ordered by version.
+func All() ([]Migration, error) {
+	entries, err := files.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+	var migrations []Migration
+	for _, entry := range entries {
+		name := entry.Name()
+		prefix, _, ok := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: name must start with a positive version and an underscore", name)
+		}
+		data, err := files.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		up, err := parseUp(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", name, err)
+		}
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    strings.TrimSuffix(name, path.Ext(name)),
+			Up:      up,
+		})
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return cmp.Compare(a.Version, b.Version) })
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", migrations[i-1].Name, migrations[i].Name, migrations[i].Version)
+		}
+	}
+	return migrations, nil
+}
+
+// parseUp returns the Up section of a migration file. Annotations other than
+// Up, Down and the statement markers would change how a file is applied, so
+// they are rejected rather than ignored.
+func parseUp(sql string) (string, error) {
+	var up strings.Builder
+	section := ""
+	for line := range strings.Lines(sql) {
+		annotation, ok := strings.CutPrefix(strings.TrimSpace(line), "-- +goose ")
+		if !ok {
+			if section == "Up" {
+				up.WriteString(line)
+			}
+			continue
+		}
+		switch annotation = strings.TrimSpace(annotation); annotation {
+		case "Up", "Down":
+			section = annotation
+		case "StatementBegin", "StatementEnd":
+		default:
+			return "", fmt.Errorf("unsupported annotation %q", annotation)
+		}
+	}
+	if strings.TrimSpace(up.String()) == "" {
+		return "", fmt.Errorf("no statements under -- +goose Up")
+	}
+	return up.String(), nil
+}
+
+// Apply applies the migrations not yet recorded in schema_migrations, in
+// version order, and returns how many it applied. Each migration runs in its
+// own transaction with its record, so a failed one leaves the schema at the
+// previous version.
+//
+// Databases migrated with goose before this runner existed are adopted: on
+// first run, the versions in goose_db_version are recorded as applied.
+func Apply(ctx context.Context, pool *pgxpool.Pool, base *slog.Logger) (int, error) {
+	logger := logging.New(base.With("component", "migrations"))
+	migrations, err := All()
+	if err != nil {
+		return 0, err
+	}
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire connection for migrations: %w", err)
+	}
+	defer conn.Release()
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock(hashtextextended($1, 0))", lockName); err != nil {
+		return 0, fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock(hashtextextended($1, 0))", lockName); err != nil {
+			// Closing the connection frees the lock on the server.
+			conn.Conn().Close(ctx)
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+)`); err != nil {
+		return 0, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	if err := adoptGoose(ctx, conn.Conn(), migrations, logger); err != nil {
+		return 0, err
+	}
+
+	applied, err := appliedVersions(ctx, conn.Conn())
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, m := range migrations {
+		if applied[m.Version] {
+			delete(applied, m.Version)
+			continue
+		}
+		start := time.Now()
+		if err := apply(ctx, conn.Conn(), m); err != nil {
+			return count, fmt.Errorf("migration %s failed: %w", m.Name, err)
+		}
+		count++
+		logger.InfoCtx(ctx, "Applied migration", "version", m.Version, "name", m.Name, "duration_ms", time.Since(start).Milliseconds())
+	}
+	for version := range applied {
+		// A rolled back deploy runs against a newer schema; it is meant to
+		// stay compatible, so this is not an error.
+		logger.WarnCtx(ctx, "Database has a migration this build does not know", "version", version)
+	}
+	return count, nil
+}
+
+func apply(ctx context.Context, conn *pgx.Conn, m Migration) error {
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		// Without arguments, Exec sends the file as one simple query, which
+		// may hold several statements.
+		if _, err := tx.Exec(ctx, m.Up); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", m.Version, m.Name)
+		return err
+	})
+}
+
+func appliedVersions(ctx context.Context, conn *pgx.Conn) (map[int64]bool, error) {
+	rows, err := conn.Query(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	versions, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	applied := make(map[int64]bool, len(versions))
+	for _, v := range versions {
+		applied[v] = true
+	}
+	return applied, nil
+}
+
+// adoptGoose records the versions goose applied, if schema_migrations is
+// still empty and goose_db_version exists.
+func adoptGoose(ctx context.Context, conn *pgx.Conn, migrations []Migration, logger *logging.Logger) error {
+	var empty, hasGoose bool
+	err := conn.QueryRow(ctx,
+		"SELECT NOT EXISTS (SELECT 1 FROM schema_migrations), to_regclass('goose_db_version') IS NOT NULL",
+	).Scan(&empty, &hasGoose)
+	if err != nil {
+		return fmt.Errorf("failed to check for goose migrations: %w", err)
+	}
+	if !empty || !hasGoose {
+		return nil
+	}
+
+	rows, err := conn.Query(ctx, "SELECT DISTINCT version_id FROM goose_db_version WHERE is_applied AND version_id > 0")
+	if err != nil {
+		return fmt.Errorf("failed to read goose_db_version: %w", err)
+	}
+	versions, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return fmt.Errorf("failed to read goose_db_version: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil
+	}
+	names := make(map[int64]string, len(migrations))
+	for _, m := range migrations {
+		names[m.Version] = m.Name
+	}
+	batch := &pgx.Batch{}
+	for _, v := range versions {
+		name, ok := names[v]
+		if !ok {
+			name = fmt.Sprintf("%03d_goose", v)
+		}
+		batch.Queue("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", v, name)
+	}
+	if err := conn.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to adopt goose migrations: %w", err)
+	}
+	logger.InfoCtx(ctx, "Adopted migrations applied by goose", "versions", len(versions))
+	return nil
+}
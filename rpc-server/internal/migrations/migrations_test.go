@@ -0,0 +1,87 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseUp(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		sql     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "up and down",
+			sql:  "-- +goose Up\nCREATE TABLE t (id INT);\n-- +goose Down\nDROP TABLE t;\n",
+			want: "CREATE TABLE t (id INT);\n",
+		},
+		{
+			name: "statement markers are dropped",
+			sql:  "-- +goose Up\n-- +goose StatementBegin\nCREATE FUNCTION f() ...;\n-- +goose StatementEnd\n-- +goose Down\nDROP FUNCTION f;\n",
+			want: "CREATE FUNCTION f() ...;\n",
+		},
+		{
+			name: "indented annotations",
+			sql:  "  -- +goose Up  \nSELECT 1;\n\t-- +goose Down\nSELECT 2;\n",
+			want: "SELECT 1;\n",
+		},
+		{
+			name: "comments before Up are not applied",
+			sql:  "-- adds t\n-- +goose Up\nSELECT 1;\n",
+			want: "SELECT 1;\n",
+		},
+		{
+			name:    "no Up section",
+			sql:     "CREATE TABLE t (id INT);\n",
+			wantErr: "no statements",
+		},
+		{
+			name:    "empty Up section",
+			sql:     "-- +goose Up\n\n-- +goose Down\nDROP TABLE t;\n",
+			wantErr: "no statements",
+		},
+		{
+			name:    "unsupported annotation",
+			sql:     "-- +goose NO TRANSACTION\n-- +goose Up\nCREATE INDEX CONCURRENTLY i ON t (id);\n",
+			wantErr: `unsupported annotation "NO TRANSACTION"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseUp(tt.sql)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseUp() = %q, %v, want an error containing %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("parseUp() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAll(t *testing.T) {
+	migrations, err := All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("All() returned no migrations")
+	}
+	for i, m := range migrations {
+		// Versions are applied in order and recorded once, so a gap would
+		// mean a file was renamed or lost.
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %d is %s, want version %d", i, m.Name, i+1)
+		}
+		if strings.Contains(m.Up, "-- +goose") {
+			t.Errorf("%s: Up section still holds goose annotations", m.Name)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"grpc-server/internal/clock"
+)
+
+// EmailChange is a pending change of a user's email address. It takes
+// effect only once the token sent to the new address is confirmed.
+type EmailChange struct {
+	UserID      string
+	NewEmail    string
+	TokenHash   []byte
+	RequestedAt time.Time
+	ExpiresAt   time.Time
+}
+
+// NewEmailChange starts a change of userID's email to newEmail that expires
+// after ttl. It returns the change together with the plaintext token, which
+// is not stored.
+func NewEmailChange(clk clock.Clock, userID, newEmail string, ttl time.Duration) (*EmailChange, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := clk.Now()
+	return &EmailChange{
+		UserID:      userID,
+		NewEmail:    newEmail,
+		TokenHash:   hashToken(token),
+		RequestedAt: now,
+		ExpiresAt:   now.Add(ttl),
+	}, token, nil
+}
+
+// Matches reports whether token is the one issued for c.
+func (c *EmailChange) Matches(token string) bool {
+	return subtle.ConstantTimeCompare(c.TokenHash, hashToken(token)) == 1
+}
+
+// Expired reports whether c can no longer be confirmed at now.
+func (c *EmailChange) Expired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
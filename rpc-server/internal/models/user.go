@@ -3,42 +3,84 @@ package models
 import (
 	"time"
 
+	"grpc-server/internal/clock"
 	pb "grpc-server/pkg/pb"
 )
 
+// User statuses, as stored in users.status.
+const (
+	StatusActive  = "active"
+	StatusExpired = "expired"
+	StatusMerged  = "merged"
+)
+
 type User struct {
-	ID        string
-	Name      string
-	Email     string
-	Age       int32
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID     string
+	Name   string
+	Email  string
+	Age    int32
+	Status string
+	// MergedInto is the surviving user's ID once this user has been merged.
+	MergedInto string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	// CreatedBy and UpdatedBy attribute the user's creation and latest change
+	// to a caller; see package actor.
+	CreatedBy string
+	UpdatedBy string
+	// Tenant owns the user; see package tenant.
+	Tenant string
+}
+
+// UserVersion is a historical snapshot of a user, valid over
+// [ValidFrom, ValidTo). ValidTo is zero for the current version.
+type UserVersion struct {
+	User
+	VersionID int64
+	ValidFrom time.Time
+	ValidTo   time.Time
 }
 
 func (u *User) ToProto() *pb.User {
 	return &pb.User{
-		Id:        u.ID,
-		Name:      u.Name,
-		Email:     u.Email,
-		Age:       u.Age,
-		CreatedAt: u.CreatedAt.Unix(),
-		UpdatedAt: u.UpdatedAt.Unix(),
+		Id:         u.ID,
+		Name:       u.Name,
+		Email:      u.Email,
+		Age:        u.Age,
+		CreatedAt:  u.CreatedAt.Unix(),
+		UpdatedAt:  u.UpdatedAt.Unix(),
+		Status:     statusToProto(u.Status),
+		MergedInto: u.MergedInto,
 	}
 }
 
-func NewUser(id, name, email string, age int32) *User {
-	now := time.Now()
+func statusToProto(status string) pb.UserStatus {
+	switch status {
+	case StatusActive:
+		return pb.UserStatus_USER_STATUS_ACTIVE
+	case StatusExpired:
+		return pb.UserStatus_USER_STATUS_EXPIRED
+	case StatusMerged:
+		return pb.UserStatus_USER_STATUS_MERGED
+	default:
+		return pb.UserStatus_USER_STATUS_UNSPECIFIED
+	}
+}
+
+func NewUser(clk clock.Clock, id, name, email string, age int32) *User {
+	now := clk.Now()
 	return &User{
 		ID:        id,
 		Name:      name,
 		Email:     email,
 		Age:       age,
+		Status:    StatusActive,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 }
 
-func (u *User) Update(name, email string, age int32) {
+func (u *User) Update(clk clock.Clock, name, email string, age int32) {
 	if name != "" {
 		u.Name = name
 	}
@@ -48,5 +90,34 @@ func (u *User) Update(name, email string, age int32) {
 	if age > 0 {
 		u.Age = age
 	}
-	u.UpdatedAt = time.Now()
+	u.UpdatedAt = clk.Now()
+}
+
+// MergePolicy decides which profile fields survive when two users are merged.
+type MergePolicy int
+
+const (
+	MergeKeepTarget MergePolicy = iota
+	MergePreferSource
+	MergeNewest
+)
+
+// Merge folds source's profile fields into u. policy picks the winner where
+// both users have a value; empty fields are filled from the other user. Email
+// is never taken from source, since it stays reserved by the merged user.
+func (u *User) Merge(clk clock.Clock, source *User, policy MergePolicy) {
+	winner, loser := u, source
+	if policy == MergePreferSource || (policy == MergeNewest && source.UpdatedAt.After(u.UpdatedAt)) {
+		winner, loser = source, u
+	}
+
+	name, age := winner.Name, winner.Age
+	if name == "" {
+		name = loser.Name
+	}
+	if age <= 0 {
+		age = loser.Age
+	}
+	u.Name, u.Age = name, age
+	u.UpdatedAt = clk.Now()
 }
@@ -0,0 +1,114 @@
+// Package msgsize records the serialized size of every request and response
+// per method and enforces per-method caps tighter than the server-wide
+// MaxRecvMsgSize and MaxSendMsgSize.
+package msgsize
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+
+	"grpc-server/internal/logging"
+	"grpc-server/pkg/apierror"
+)
+
+// Limits holds per-method caps in bytes, keyed by method name without the
+// service ("ListUsers"). Methods not listed are only bounded server-wide.
+type Limits struct {
+	Request  map[string]int
+	Response map[string]int
+}
+
+// ParseLimits parses a comma-separated list of per-method caps in bytes
+// ("ListUsers=1048576,CreateUser=4096").
+func ParseLimits(s string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for entry := range strings.SplitSeq(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		method, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid size limit %q: want Method=bytes", entry)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid size limit for %s: %q is not a positive number of bytes", method, value)
+		}
+		limits[strings.TrimSpace(method)] = limit
+	}
+	return limits, nil
+}
+
+// UnaryServerInterceptor records request and response sizes and rejects
+// calls whose request or response is over its method's limit with
+// RESOURCE_EXHAUSTED. Oversized responses are dropped after the handler
+// runs; the error tells the caller to ask for less.
+func UnaryServerInterceptor(limits Limits) grpc.UnaryServerInterceptor {
+	meter := otel.Meter("rpc-server.rpc/msgsize")
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	requestSize, _ := meter.Int64Histogram("rpc.method.request.size",
+		metric.WithDescription("Serialized size of unary requests"),
+		metric.WithUnit("By"),
+	)
+	responseSize, _ := meter.Int64Histogram("rpc.method.response.size",
+		metric.WithDescription("Serialized size of unary responses"),
+		metric.WithUnit("By"),
+	)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := path.Base(info.FullMethod)
+		attrs := metric.WithAttributes(attribute.String("rpc.method", method))
+
+		size := messageSize(req)
+		requestSize.Record(ctx, int64(size), attrs)
+		if limit, ok := limits.Request[method]; ok && size > limit {
+			logging.FromContext(ctx).WarnCtx(ctx, "Rejected oversized request", "size_bytes", size, "limit_bytes", limit)
+			return nil, tooLargeError(method, "request", size, limit,
+				"split the request into smaller ones")
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+
+		size = messageSize(resp)
+		responseSize.Record(ctx, int64(size), attrs)
+		if limit, ok := limits.Response[method]; ok && size > limit {
+			logging.FromContext(ctx).WarnCtx(ctx, "Rejected oversized response", "size_bytes", size, "limit_bytes", limit)
+			return nil, tooLargeError(method, "response", size, limit,
+				"request fewer results, e.g. with a smaller limit, or fewer fields with read_mask")
+		}
+		return resp, nil
+	}
+}
+
+func messageSize(m any) int {
+	if msg, ok := m.(proto.Message); ok {
+		return proto.Size(msg)
+	}
+	return 0
+}
+
+func tooLargeError(method, direction string, size, limit int, hint string) error {
+	return apierror.New(grpc_codes.ResourceExhausted, apierror.ReasonMessageTooLarge,
+		fmt.Sprintf("%s %s is %d bytes, over its %d-byte limit; %s", method, direction, size, limit, hint), nil,
+		map[string]string{
+			"method":      method,
+			"direction":   direction,
+			"size_bytes":  strconv.Itoa(size),
+			"limit_bytes": strconv.Itoa(limit),
+		})
+}
@@ -0,0 +1,50 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// Spec is the OpenAPI v2 document generated from proto/user.proto and the
+// gateway HTTP rules (see `just proto`).
+//
+//go:embed user.swagger.json
+var Spec []byte
+
+const (
+	SpecPath = "/openapi.json"
+	DocsPath = "/docs"
+)
+
+// swaggerUI loads the Swagger UI bundle from a CDN and points it at SpecPath, so
+// the binary only has to embed the spec itself.
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>User Service API</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "` + SpecPath + `", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// Register mounts the spec and the Swagger UI on mux.
+func Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+SpecPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(Spec)
+	})
+	mux.HandleFunc("GET "+DocsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(swaggerUI))
+	})
+}
@@ -0,0 +1,241 @@
+package protocompat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	schemaFile  = "schema.json"
+	messagesDir = "messages"
+	goldenExt   = ".binpb"
+)
+
+// Sample builds a deterministic message with every field populated, recursing
+// into nested messages up to depth levels.
+func Sample(md protoreflect.MessageDescriptor, depth int) proto.Message {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName())
+	if err != nil {
+		return nil
+	}
+	msg := mt.New()
+	fill(msg, depth)
+	return msg.Interface()
+}
+
+func fill(msg protoreflect.Message, depth int) {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		switch {
+		case fd.IsMap():
+			continue
+		case fd.IsList():
+			if fd.Kind() == protoreflect.MessageKind && depth <= 0 {
+				continue
+			}
+			list := msg.Mutable(fd).List()
+			for n := 0; n < 2; n++ {
+				if fd.Kind() == protoreflect.MessageKind {
+					elem := list.NewElement()
+					fill(elem.Message(), depth-1)
+					list.Append(elem)
+				} else {
+					list.Append(scalar(fd, n))
+				}
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			if depth > 0 {
+				fill(msg.Mutable(fd).Message(), depth-1)
+			}
+		default:
+			msg.Set(fd, scalar(fd, 0))
+		}
+	}
+}
+
+func scalar(fd protoreflect.FieldDescriptor, n int) protoreflect.Value {
+	num := int64(fd.Number()) + int64(n)
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(true)
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		return protoreflect.ValueOfEnum(values.Get(values.Len() - 1).Number())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(int32(num))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(num)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(uint32(num))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(uint64(num))
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(float32(num) + 0.5)
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(float64(num) + 0.5)
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(fmt.Sprintf("%s-%d", fd.Name(), n)))
+	default:
+		return protoreflect.ValueOfString(fmt.Sprintf("%s-%d", fd.Name(), n))
+	}
+}
+
+// Update rewrites the schema snapshot and golden messages in dir from fd.
+// Golden files for messages that still exist are kept as they are, so payloads
+// recorded by older versions keep being checked.
+func Update(dir string, fd protoreflect.FileDescriptor) error {
+	data, err := json.MarshalIndent(Snapshot(fd), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, messagesDir), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, schemaFile), append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+
+	for i := 0; i < fd.Messages().Len(); i++ {
+		md := fd.Messages().Get(i)
+		path := filepath.Join(dir, messagesDir, string(md.FullName())+goldenExt)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		b, err := proto.MarshalOptions{Deterministic: true}.Marshal(Sample(md, 2))
+		if err != nil {
+			return fmt.Errorf("failed to marshal golden %s: %w", md.FullName(), err)
+		}
+		if err := os.WriteFile(path, b, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check verifies fd against the snapshot and golden messages in golden. It
+// returns the breaking changes found (empty means compatible) and warnings.
+func Check(golden fs.FS, fd protoreflect.FileDescriptor) (breaking, warnings []string, err error) {
+	raw, err := fs.ReadFile(golden, schemaFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read schema snapshot: %w", err)
+	}
+	var old Schema
+	if err := json.Unmarshal(raw, &old); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse schema snapshot: %w", err)
+	}
+	breaking, warnings = Diff(old, Snapshot(fd))
+
+	entries, err := fs.ReadDir(golden, messagesDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, err
+	}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), goldenExt)
+		if !ok {
+			continue
+		}
+		b, err := fs.ReadFile(golden, messagesDir+"/"+e.Name())
+		if err != nil {
+			return nil, nil, err
+		}
+		if problem := checkGolden(protoreflect.FullName(name), b); problem != "" {
+			breaking = append(breaking, problem)
+		}
+	}
+
+	slices.Sort(breaking)
+	return breaking, warnings, nil
+}
+
+// checkGolden decodes a recorded payload with the current generated type and
+// requires it to re-encode byte for byte with no unknown fields left over,
+// other than fields whose numbers have since been reserved.
+func checkGolden(name protoreflect.FullName, b []byte) string {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(name)
+	if err != nil {
+		return fmt.Sprintf("golden %s: message type no longer exists", name)
+	}
+
+	msg := mt.New().Interface()
+	if err := proto.Unmarshal(b, msg); err != nil {
+		return fmt.Sprintf("golden %s: no longer decodes: %v", name, err)
+	}
+
+	var unknown, stripped bool
+	walk(msg.ProtoReflect(), func(m protoreflect.Message) {
+		raw := m.GetUnknown()
+		if len(raw) == 0 {
+			return
+		}
+		reserved := m.Descriptor().ReservedRanges()
+		for len(raw) > 0 {
+			num, typ, n := protowire.ConsumeTag(raw)
+			if n < 0 {
+				unknown = true
+				return
+			}
+			skip := protowire.ConsumeFieldValue(num, typ, raw[n:])
+			if skip < 0 {
+				unknown = true
+				return
+			}
+			if !reserved.Has(num) {
+				unknown = true
+			}
+			raw = raw[n+skip:]
+		}
+		// Fields removed and reserved on purpose are expected in old payloads.
+		m.SetUnknown(nil)
+		stripped = true
+	})
+	if unknown {
+		return fmt.Sprintf("golden %s: decodes with unknown fields (field removed or renumbered)", name)
+	}
+	if stripped {
+		return ""
+	}
+
+	again, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return fmt.Sprintf("golden %s: failed to re-encode: %v", name, err)
+	}
+	if !bytes.Equal(again, b) {
+		return fmt.Sprintf("golden %s: re-encoded bytes differ (field type or encoding changed)", name)
+	}
+	return ""
+}
+
+func walk(m protoreflect.Message, visit func(protoreflect.Message)) {
+	visit(m)
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					walk(mv.Message(), visit)
+					return true
+				})
+			}
+		case fd.IsList() && fd.Kind() == protoreflect.MessageKind:
+			for i := 0; i < v.List().Len(); i++ {
+				walk(v.List().Get(i).Message(), visit)
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			walk(v.Message(), visit)
+		}
+		return true
+	})
+}
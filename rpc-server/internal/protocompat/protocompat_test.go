@@ -0,0 +1,78 @@
+package protocompat
+
+import (
+	"os"
+	"testing"
+
+	pb "grpc-server/pkg/pb"
+)
+
+// TestUserProtoIsCompatible fails on changes to proto/user.proto that would
+// break clients built against the recorded schema. Record an intended
+// change with go run ./cmd/protocompat -update.
+func TestUserProtoIsCompatible(t *testing.T) {
+	breaking, warnings, err := Check(os.DirFS("golden"), pb.File_user_proto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range warnings {
+		t.Logf("warning: %s", w)
+	}
+	for _, b := range breaking {
+		t.Errorf("breaking: %s", b)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	user := func(fields map[string]Field, reserved ...int32) Schema {
+		return Schema{Messages: map[string]Message{"user.User": {Fields: fields, ReservedNums: reserved}}}
+	}
+	id := Field{Name: "id", Kind: "string", Cardinality: "singular"}
+	for _, tt := range []struct {
+		name     string
+		old, cur Schema
+		breaking int
+		warnings int
+	}{
+		{"unchanged", user(map[string]Field{"1": id}), user(map[string]Field{"1": id}), 0, 0},
+		{"field added", user(map[string]Field{"1": id}), user(map[string]Field{"1": id, "2": {Name: "age", Kind: "int32", Cardinality: "singular"}}), 0, 0},
+		{"field removed", user(map[string]Field{"1": id}), user(map[string]Field{}), 1, 0},
+		{"field removed and reserved", user(map[string]Field{"1": id}), user(map[string]Field{}, 1), 0, 0},
+		{"wire compatible type change", user(map[string]Field{"1": id}), user(map[string]Field{"1": {Name: "id", Kind: "bytes", Cardinality: "singular"}}), 0, 0},
+		{"incompatible type change", user(map[string]Field{"1": id}), user(map[string]Field{"1": {Name: "id", Kind: "int64", Cardinality: "singular"}}), 1, 0},
+		{"made repeated", user(map[string]Field{"1": id}), user(map[string]Field{"1": {Name: "id", Kind: "string", Cardinality: "repeated"}}), 1, 0},
+		{"renamed", user(map[string]Field{"1": id}), user(map[string]Field{"1": {Name: "uid", Kind: "string", Cardinality: "singular"}}), 0, 1},
+		{"message removed", user(map[string]Field{"1": id}), Schema{}, 1, 0},
+		{
+			"method signature changed",
+			Schema{Services: map[string]Service{"user.UserService": {Methods: map[string]Method{"GetUser": {Input: "user.GetUserRequest", Output: "user.GetUserResponse"}}}}},
+			Schema{Services: map[string]Service{"user.UserService": {Methods: map[string]Method{"GetUser": {Input: "user.GetUserRequest", Output: "user.User"}}}}},
+			1, 0,
+		},
+		{
+			"enum value removed",
+			Schema{Enums: map[string]Enum{"user.Status": {Values: map[string]string{"0": "STATUS_UNSPECIFIED", "1": "STATUS_ACTIVE"}}}},
+			Schema{Enums: map[string]Enum{"user.Status": {Values: map[string]string{"0": "STATUS_UNSPECIFIED"}}}},
+			1, 0,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			breaking, warnings := Diff(tt.old, tt.cur)
+			if len(breaking) != tt.breaking || len(warnings) != tt.warnings {
+				t.Errorf("Diff() = breaking %q, warnings %q; want %d and %d", breaking, warnings, tt.breaking, tt.warnings)
+			}
+		})
+	}
+}
+
+// The snapshot of the real file must diff clean against itself, or every
+// check would report changes nobody made.
+func TestSnapshotIsStable(t *testing.T) {
+	breaking, warnings := Diff(Snapshot(pb.File_user_proto), Snapshot(pb.File_user_proto))
+	if len(breaking) > 0 || len(warnings) > 0 {
+		t.Errorf("Diff of a snapshot with itself = %q, %q", breaking, warnings)
+	}
+	if _, ok := Snapshot(pb.File_user_proto).Messages["user.User"]; !ok {
+		t.Error("snapshot does not record user.User")
+	}
+}
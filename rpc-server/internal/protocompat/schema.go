@@ -0,0 +1,238 @@
+package protocompat
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Schema is the wire-relevant shape of a proto file: field numbers and types,
+// enum values and RPC signatures. Names are recorded but only compared as
+// warnings, since renaming a field is wire compatible.
+type Schema struct {
+	Messages map[string]Message `json:"messages"`
+	Enums    map[string]Enum    `json:"enums,omitempty"`
+	Services map[string]Service `json:"services"`
+}
+
+type Message struct {
+	Fields        map[string]Field `json:"fields"` // keyed by field number
+	ReservedNums  []int32          `json:"reserved_numbers,omitempty"`
+	ReservedNames []string         `json:"reserved_names,omitempty"`
+}
+
+type Field struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Cardinality string `json:"cardinality"`
+	TypeName    string `json:"type_name,omitempty"` // message or enum full name
+}
+
+type Enum struct {
+	Values map[string]string `json:"values"` // number -> name
+}
+
+type Service struct {
+	Methods map[string]Method `json:"methods"`
+}
+
+type Method struct {
+	Input           string `json:"input"`
+	Output          string `json:"output"`
+	ClientStreaming bool   `json:"client_streaming,omitempty"`
+	ServerStreaming bool   `json:"server_streaming,omitempty"`
+}
+
+func (f Field) isCollection() bool {
+	return f.Cardinality == "repeated" || f.Cardinality == "map"
+}
+
+// Snapshot records the schema of fd.
+func Snapshot(fd protoreflect.FileDescriptor) Schema {
+	s := Schema{
+		Messages: make(map[string]Message),
+		Enums:    make(map[string]Enum),
+		Services: make(map[string]Service),
+	}
+
+	var addEnum func(ed protoreflect.EnumDescriptor)
+	addEnum = func(ed protoreflect.EnumDescriptor) {
+		e := Enum{Values: make(map[string]string)}
+		for i := 0; i < ed.Values().Len(); i++ {
+			v := ed.Values().Get(i)
+			e.Values[strconv.Itoa(int(v.Number()))] = string(v.Name())
+		}
+		s.Enums[string(ed.FullName())] = e
+	}
+
+	var addMessage func(md protoreflect.MessageDescriptor)
+	addMessage = func(md protoreflect.MessageDescriptor) {
+		m := Message{Fields: make(map[string]Field)}
+		for i := 0; i < md.Fields().Len(); i++ {
+			fd := md.Fields().Get(i)
+			f := Field{
+				Name:        string(fd.Name()),
+				Kind:        fd.Kind().String(),
+				Cardinality: cardinality(fd),
+			}
+			switch {
+			case fd.Message() != nil:
+				f.TypeName = string(fd.Message().FullName())
+			case fd.Enum() != nil:
+				f.TypeName = string(fd.Enum().FullName())
+			}
+			m.Fields[strconv.Itoa(int(fd.Number()))] = f
+		}
+		for i := 0; i < md.ReservedRanges().Len(); i++ {
+			r := md.ReservedRanges().Get(i)
+			for n := r[0]; n < r[1]; n++ {
+				m.ReservedNums = append(m.ReservedNums, int32(n))
+			}
+		}
+		for i := 0; i < md.ReservedNames().Len(); i++ {
+			m.ReservedNames = append(m.ReservedNames, string(md.ReservedNames().Get(i)))
+		}
+		s.Messages[string(md.FullName())] = m
+
+		for i := 0; i < md.Messages().Len(); i++ {
+			if nested := md.Messages().Get(i); !nested.IsMapEntry() {
+				addMessage(nested)
+			}
+		}
+		for i := 0; i < md.Enums().Len(); i++ {
+			addEnum(md.Enums().Get(i))
+		}
+	}
+
+	for i := 0; i < fd.Messages().Len(); i++ {
+		addMessage(fd.Messages().Get(i))
+	}
+	for i := 0; i < fd.Enums().Len(); i++ {
+		addEnum(fd.Enums().Get(i))
+	}
+	for i := 0; i < fd.Services().Len(); i++ {
+		sd := fd.Services().Get(i)
+		svc := Service{Methods: make(map[string]Method)}
+		for j := 0; j < sd.Methods().Len(); j++ {
+			md := sd.Methods().Get(j)
+			svc.Methods[string(md.Name())] = Method{
+				Input:           string(md.Input().FullName()),
+				Output:          string(md.Output().FullName()),
+				ClientStreaming: md.IsStreamingClient(),
+				ServerStreaming: md.IsStreamingServer(),
+			}
+		}
+		s.Services[string(sd.FullName())] = svc
+	}
+	return s
+}
+
+func cardinality(fd protoreflect.FieldDescriptor) string {
+	switch {
+	case fd.IsMap():
+		return "map"
+	case fd.IsList():
+		return "repeated"
+	case fd.HasPresence():
+		return "optional"
+	default:
+		return "singular"
+	}
+}
+
+// wireGroups lists kinds that share a wire encoding and can be swapped without
+// breaking existing payloads.
+var wireGroups = [][]string{
+	{"int32", "int64", "uint32", "uint64", "bool"},
+	{"sint32", "sint64"},
+	{"fixed32", "sfixed32"},
+	{"fixed64", "sfixed64"},
+	{"string", "bytes"},
+}
+
+func wireCompatible(a, b string) bool {
+	if a == b {
+		return true
+	}
+	for _, g := range wireGroups {
+		if slices.Contains(g, a) && slices.Contains(g, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff compares a previously recorded schema against the current one. Breaking
+// changes would corrupt or reject payloads produced by the other side; warnings
+// are wire compatible but may affect JSON/REST consumers.
+func Diff(old, cur Schema) (breaking, warnings []string) {
+	for name, om := range old.Messages {
+		cm, ok := cur.Messages[name]
+		if !ok {
+			breaking = append(breaking, fmt.Sprintf("message %s removed", name))
+			continue
+		}
+		for num, of := range om.Fields {
+			cf, ok := cm.Fields[num]
+			if !ok {
+				n, _ := strconv.Atoi(num)
+				if !slices.Contains(cm.ReservedNums, int32(n)) {
+					breaking = append(breaking, fmt.Sprintf("%s: field %s (%s) removed without reserving its number", name, num, of.Name))
+				}
+				continue
+			}
+			if !wireCompatible(of.Kind, cf.Kind) {
+				breaking = append(breaking, fmt.Sprintf("%s: field %s (%s) changed type %s -> %s", name, num, of.Name, of.Kind, cf.Kind))
+			}
+			if of.TypeName != cf.TypeName {
+				breaking = append(breaking, fmt.Sprintf("%s: field %s (%s) changed type %s -> %s", name, num, of.Name, of.TypeName, cf.TypeName))
+			}
+			if of.Cardinality != cf.Cardinality && (of.isCollection() || cf.isCollection()) {
+				breaking = append(breaking, fmt.Sprintf("%s: field %s (%s) changed cardinality %s -> %s", name, num, of.Name, of.Cardinality, cf.Cardinality))
+			}
+			if of.Name != cf.Name {
+				warnings = append(warnings, fmt.Sprintf("%s: field %s renamed %s -> %s (breaks JSON clients)", name, num, of.Name, cf.Name))
+			}
+		}
+	}
+
+	for name, oe := range old.Enums {
+		ce, ok := cur.Enums[name]
+		if !ok {
+			breaking = append(breaking, fmt.Sprintf("enum %s removed", name))
+			continue
+		}
+		for num, ov := range oe.Values {
+			cv, ok := ce.Values[num]
+			if !ok {
+				breaking = append(breaking, fmt.Sprintf("%s: value %s (%s) removed", name, num, ov))
+			} else if cv != ov {
+				warnings = append(warnings, fmt.Sprintf("%s: value %s renamed %s -> %s (breaks JSON clients)", name, num, ov, cv))
+			}
+		}
+	}
+
+	for name, os := range old.Services {
+		cs, ok := cur.Services[name]
+		if !ok {
+			breaking = append(breaking, fmt.Sprintf("service %s removed", name))
+			continue
+		}
+		for mname, om := range os.Methods {
+			cm, ok := cs.Methods[mname]
+			if !ok {
+				breaking = append(breaking, fmt.Sprintf("%s: method %s removed", name, mname))
+				continue
+			}
+			if om != cm {
+				breaking = append(breaking, fmt.Sprintf("%s: method %s signature changed %+v -> %+v", name, mname, om, cm))
+			}
+		}
+	}
+
+	slices.Sort(breaking)
+	slices.Sort(warnings)
+	return breaking, warnings
+}
@@ -0,0 +1,251 @@
+// Package ratelimit limits how fast each client may call the server, with a
+// token bucket per client, so one bursty caller such as the load tester
+// cannot take the database's capacity from everyone else.
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"grpc-server/internal/clock"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/transport"
+	"grpc-server/pkg/apierror"
+	pb "grpc-server/pkg/pb"
+)
+
+// APIKeyMetadataKey is the metadata clients send their API key in; the REST
+// gateway passes the X-Api-Key header on as it.
+const APIKeyMetadataKey = "x-api-key"
+
+// retryAfterMetadataKey is the response header telling rejected callers how
+// many seconds to wait; the REST gateway passes it on as Retry-After.
+const retryAfterMetadataKey = "retry-after"
+
+// Calls from these services are never limited: probes must always be
+// answered, and operators must always be able to undo a change.
+var exemptServices = []string{
+	"/grpc.health.v1.Health/",
+	"/" + pb.AdminService_ServiceDesc.ServiceName + "/",
+}
+
+// KeyBy selects what identifies a client.
+type KeyBy string
+
+const (
+	// KeyByPeer limits each client IP address. Calls through the REST
+	// gateway are keyed by the HTTP client's address.
+	KeyByPeer KeyBy = "peer"
+	// KeyByAPIKey limits each API key, and callers without one by their
+	// address. Keys are not checked here, so only use it behind something
+	// that does, or clients evade the limit by making keys up.
+	KeyByAPIKey KeyBy = "api_key"
+)
+
+// ParseKeyBy parses a KeyBy name.
+func ParseKeyBy(s string) (KeyBy, error) {
+	switch k := KeyBy(s); k {
+	case KeyByPeer, KeyByAPIKey:
+		return k, nil
+	}
+	return "", fmt.Errorf("unknown rate limit key %q: want %q or %q", s, KeyByPeer, KeyByAPIKey)
+}
+
+// Config configures a Limiter.
+type Config struct {
+	// QPS is the sustained calls per second allowed per client, and Burst
+	// how many calls a client that has been idle may make at once.
+	QPS   float64
+	Burst int
+	KeyBy KeyBy
+	// MaxClients bounds the buckets held; idle clients are forgotten first.
+	MaxClients int
+	Clock      clock.Clock
+}
+
+// bucket is one client's token bucket.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter holds a token bucket per client.
+type Limiter struct {
+	cfg   Config
+	clock clock.Clock
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func NewLimiter(cfg Config) *Limiter {
+	return &Limiter{
+		cfg:     cfg,
+		clock:   clock.OrSystem(cfg.Clock),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow takes a token from client's bucket. When the bucket is empty it
+// returns false and how long until the next token.
+func (l *Limiter) Allow(client string) (bool, time.Duration) {
+	now := l.clock.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[client]
+	if !ok {
+		if len(l.buckets) >= l.cfg.MaxClients {
+			l.evict(now)
+		}
+		b = &bucket{tokens: float64(l.cfg.Burst), last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*l.cfg.QPS, float64(l.cfg.Burst))
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.cfg.QPS * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// evict forgets the buckets that have refilled, which a new bucket would
+// replace exactly. If every client is still limited, it forgets the one idle
+// longest, which at worst gives that client a fresh burst.
+func (l *Limiter) evict(now time.Time) {
+	var oldest string
+	var oldestLast time.Time
+	for client, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.cfg.QPS >= float64(l.cfg.Burst) {
+			delete(l.buckets, client)
+			continue
+		}
+		if oldest == "" || b.last.Before(oldestLast) {
+			oldest, oldestLast = client, b.last
+		}
+	}
+	if len(l.buckets) >= l.cfg.MaxClients {
+		delete(l.buckets, oldest)
+	}
+}
+
+// UnaryServerInterceptor rejects calls from clients over their rate with
+// RESOURCE_EXHAUSTED, carrying RetryInfo and a retry-after header with the
+// wait until their next call is admitted.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	rejections := newRejectionCounter()
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.admit(ctx, info.FullMethod, rejections); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls.
+// Opening a stream takes one token, however many messages it carries.
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	rejections := newRejectionCounter()
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.admit(ss.Context(), info.FullMethod, rejections); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func newRejectionCounter() metric.Int64Counter {
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	rejections, _ := otel.Meter("rpc-server.rpc/ratelimit").Int64Counter("rpc.ratelimit.rejections",
+		metric.WithDescription("Calls rejected because their client exceeded its rate limit"),
+		metric.WithUnit("{request}"),
+	)
+	return rejections
+}
+
+// admit takes a token for a call to fullMethod, or returns the status error
+// to reject it with.
+func (l *Limiter) admit(ctx context.Context, fullMethod string, rejections metric.Int64Counter) error {
+	for _, service := range exemptServices {
+		if strings.HasPrefix(fullMethod, service) {
+			return nil
+		}
+	}
+	client, keyedBy := l.client(ctx)
+	ok, retryAfter := l.Allow(client)
+	if ok {
+		return nil
+	}
+
+	rejections.Add(ctx, 1, metric.WithAttributes(attribute.String("key", string(keyedBy))))
+	logging.FromContext(ctx).DebugCtx(ctx, "Client rate limit exceeded", "client", client, "retry_after_ms", retryAfter.Milliseconds())
+	_ = grpc.SetHeader(ctx, metadata.Pairs(retryAfterMetadataKey, strconv.FormatInt(int64(math.Ceil(retryAfter.Seconds())), 10)))
+	return apierror.Retry(grpc_codes.ResourceExhausted, apierror.ReasonRateLimited,
+		"client rate limit exceeded", retryAfter,
+		map[string]string{
+			"retry_after_ms": strconv.FormatInt(retryAfter.Milliseconds(), 10),
+			"scope":          "client",
+		})
+}
+
+// client identifies the caller of the call in ctx, and reports what by.
+func (l *Limiter) client(ctx context.Context) (string, KeyBy) {
+	return Identify(ctx, l.cfg.KeyBy)
+}
+
+// Identify identifies the caller of the call in ctx by keyBy, as
+// "key:<hash>" or "ip:<address>", and reports what by. API keys are hashed,
+// the first 8 bytes of their SHA-256 in hex, so they are neither held in
+// memory nor logged.
+func Identify(ctx context.Context, keyBy KeyBy) (string, KeyBy) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if keyBy == KeyByAPIKey {
+		if keys := md.Get(APIKeyMetadataKey); len(keys) > 0 && keys[0] != "" {
+			sum := sha256.Sum256([]byte(keys[0]))
+			return "key:" + hex.EncodeToString(sum[:8]), KeyByAPIKey
+		}
+	}
+	return "ip:" + peerAddress(ctx, md), KeyByPeer
+}
+
+// peerAddress returns the caller's IP address. Calls from the REST gateway
+// come in process, so for them it is the last x-forwarded-for entry, the
+// one the gateway added; earlier entries come from the HTTP client and
+// can be forged.
+func peerAddress(ctx context.Context, md metadata.MD) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+	if transport.IsInProcess(p.Addr) {
+		forwarded := md.Get("x-forwarded-for")
+		if len(forwarded) == 0 {
+			return p.Addr.String()
+		}
+		entries := strings.Split(forwarded[len(forwarded)-1], ",")
+		return strings.TrimSpace(entries[len(entries)-1])
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
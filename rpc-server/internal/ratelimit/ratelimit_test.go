@@ -0,0 +1,169 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"grpc-server/internal/clock"
+)
+
+var start = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// step advances the clock by advance, then calls Allow for client.
+type step struct {
+	advance   time.Duration
+	client    string
+	wantOK    bool
+	wantRetry time.Duration
+}
+
+func runSteps(t *testing.T, cfg Config, steps []step) {
+	t.Helper()
+	clk := clock.NewFake(start)
+	cfg.Clock = clk
+	l := NewLimiter(cfg)
+	for i, s := range steps {
+		clk.Advance(s.advance)
+		ok, retry := l.Allow(s.client)
+		if ok != s.wantOK || retry != s.wantRetry {
+			t.Fatalf("step %d: Allow(%q) = %v, %v; want %v, %v", i, s.client, ok, retry, s.wantOK, s.wantRetry)
+		}
+	}
+}
+
+func TestAllowRefill(t *testing.T) {
+	cfg := Config{QPS: 2, Burst: 2, MaxClients: 10}
+	for _, tt := range []struct {
+		name  string
+		steps []step
+	}{
+		{"burst then empty", []step{
+			{0, "a", true, 0},
+			{0, "a", true, 0},
+			{0, "a", false, 500 * time.Millisecond},
+		}},
+		{"refills at QPS", []step{
+			{0, "a", true, 0},
+			{0, "a", true, 0},
+			{250 * time.Millisecond, "a", false, 250 * time.Millisecond},
+			{250 * time.Millisecond, "a", true, 0},
+			{0, "a", false, 500 * time.Millisecond},
+		}},
+		{"refill stops at the burst", []step{
+			{0, "a", true, 0},
+			{time.Hour, "a", true, 0},
+			{0, "a", true, 0},
+			{0, "a", false, 500 * time.Millisecond},
+		}},
+		{"clients have their own buckets", []step{
+			{0, "a", true, 0},
+			{0, "a", true, 0},
+			{0, "b", true, 0},
+			{0, "a", false, 500 * time.Millisecond},
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) { runSteps(t, cfg, tt.steps) })
+	}
+}
+
+func TestAllowEviction(t *testing.T) {
+	cfg := Config{QPS: 1, Burst: 1, MaxClients: 2}
+	for _, tt := range []struct {
+		name  string
+		steps []step
+	}{
+		{"refilled buckets are forgotten first", []step{
+			{0, "a", true, 0},
+			{0, "b", true, 0},
+			// a and b refill, so both can go; c gets a fresh bucket
+			{time.Second, "c", true, 0},
+			{0, "c", false, time.Second},
+		}},
+		{"a limited client keeps its bucket while another refilled", []step{
+			{0, "a", true, 0},
+			{500 * time.Millisecond, "b", true, 0},
+			// a has refilled and is forgotten; b is still limited
+			{500 * time.Millisecond, "c", true, 0},
+			{0, "b", false, 500 * time.Millisecond},
+		}},
+		{"the longest idle is forgotten when every client is limited", []step{
+			{0, "a", true, 0},
+			{100 * time.Millisecond, "b", true, 0},
+			{100 * time.Millisecond, "c", true, 0},
+			// a was evicted for c, so it starts with a full burst again
+			{0, "a", true, 0},
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) { runSteps(t, cfg, tt.steps) })
+	}
+}
+
+func TestClient(t *testing.T) {
+	tcp := &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 4242}
+	for _, tt := range []struct {
+		name    string
+		keyBy   KeyBy
+		addr    net.Addr
+		md      metadata.MD
+		want    string
+		wantKey KeyBy
+	}{
+		{"peer", KeyByPeer, tcp, nil, "ip:192.0.2.1", KeyByPeer},
+		{"API key ignored when keyed by peer", KeyByPeer, tcp, metadata.Pairs(APIKeyMetadataKey, "secret"), "ip:192.0.2.1", KeyByPeer},
+		{"API key hashed", KeyByAPIKey, tcp, metadata.Pairs(APIKeyMetadataKey, "secret"), "key:2bb80d537b1da3e3", KeyByAPIKey},
+		{"no API key falls back to the peer", KeyByAPIKey, tcp, nil, "ip:192.0.2.1", KeyByPeer},
+		{"forwarded header ignored off the gateway", KeyByPeer, tcp, metadata.Pairs("x-forwarded-for", "198.51.100.7"), "ip:192.0.2.1", KeyByPeer},
+		{"no peer", KeyByPeer, nil, nil, "ip:unknown", KeyByPeer},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			if tt.addr != nil {
+				ctx = peer.NewContext(ctx, &peer.Peer{Addr: tt.addr})
+			}
+			got, keyedBy := NewLimiter(Config{KeyBy: tt.keyBy}).client(ctx)
+			if got != tt.want || keyedBy != tt.wantKey {
+				t.Errorf("client() = %q, %q; want %q, %q", got, keyedBy, tt.want, tt.wantKey)
+			}
+		})
+	}
+}
+
+// fakeStream is a server stream that only has a context.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	l := NewLimiter(Config{QPS: 1, Burst: 1, KeyBy: KeyByPeer, MaxClients: 10, Clock: clock.NewFake(start)})
+	intercept := l.StreamServerInterceptor()
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 1234}})
+
+	for _, tt := range []struct {
+		name   string
+		method string
+		want   grpc_codes.Code
+	}{
+		{"first stream", "/user.UserService/TestStream", grpc_codes.OK},
+		{"second stream over the burst", "/user.UserService/TestLatencyStream", grpc_codes.ResourceExhausted},
+		{"health is exempt", "/grpc.health.v1.Health/Watch", grpc_codes.OK},
+	} {
+		info := &grpc.StreamServerInfo{FullMethod: tt.method, IsServerStream: true}
+		err := intercept(nil, fakeStream{ctx: ctx}, info, func(any, grpc.ServerStream) error { return nil })
+		if code := status.Code(err); code != tt.want {
+			t.Errorf("%s: code = %v, want %v (%v)", tt.name, code, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+// Package instrumented wraps any repository.UserRepository with tracing,
+// latency metrics and error logging, so backends only deal with storage.
+package instrumented
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"grpc-server/internal/database"
+	"grpc-server/internal/deadline"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/models"
+	"grpc-server/internal/repository"
+)
+
+// Error classes, recorded as the error.class attribute. Expected outcomes
+// are answers the caller handles, not failures of the backend.
+const (
+	classOK          = "ok"
+	classNotFound    = "not_found"   // expected
+	classConflict    = "conflict"    // expected
+	classRejected    = "rejected"    // expected
+	classTimeout     = "timeout"     // deadline or budget ran out
+	classCanceled    = "canceled"    // the caller went away
+	classUnavailable = "unavailable" // no connection to be had
+	classError       = "error"
+)
+
+// classify sorts err into one of the error classes.
+func classify(err error) string {
+	switch {
+	case err == nil:
+		return classOK
+	case errors.Is(err, repository.ErrUserNotFound),
+		errors.Is(err, repository.ErrVersionNotFound),
+		errors.Is(err, repository.ErrEmailChangeNotFound):
+		return classNotFound
+	case errors.Is(err, repository.ErrUserExists),
+		errors.Is(err, repository.ErrEmailExists),
+		errors.Is(err, repository.ErrUserMerged):
+		return classConflict
+	case errors.Is(err, repository.ErrEmailChangeExpired),
+		errors.Is(err, repository.ErrInvalidToken),
+		errors.Is(err, repository.ErrCrossShard):
+		return classRejected
+	case errors.Is(err, database.ErrPoolExhausted):
+		return classUnavailable
+	case errors.Is(err, deadline.ErrBudgetExhausted), errors.Is(err, context.DeadlineExceeded):
+		return classTimeout
+	case errors.Is(err, context.Canceled):
+		return classCanceled
+	default:
+		return classError
+	}
+}
+
+func expected(class string) bool {
+	return class == classOK || class == classNotFound || class == classConflict || class == classRejected
+}
+
+// UserRepository records a span, a latency sample and, for unexpected
+// errors, a log record for every call on the wrapped repository.
+type UserRepository struct {
+	repo     repository.UserRepository
+	backend  string
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	logger   *logging.Logger
+}
+
+// NewUserRepository wraps repo. backend names it in spans, metrics and logs,
+// e.g. "postgres" or a shard name.
+func NewUserRepository(repo repository.UserRepository, backend string, base *slog.Logger) *UserRepository {
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	duration, _ := otel.Meter("rpc-server.rpc/repository").Float64Histogram("db.repository.duration",
+		metric.WithDescription("Duration of user repository calls"),
+		metric.WithUnit("ms"),
+	)
+	return &UserRepository{
+		repo:     repo,
+		backend:  backend,
+		tracer:   otel.Tracer("rpc-server.rpc/repository"),
+		duration: duration,
+		logger:   logging.New(logging.ForModule(base, logging.ModuleRepository).With("backend", backend)),
+	}
+}
+
+// call runs fn, the wrapped repository's method, in a span and records its
+// outcome.
+func call[T any](ctx context.Context, r *UserRepository, method string, attrs []attribute.KeyValue, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx, span := r.tracer.Start(ctx, "repository."+method, trace.WithAttributes(
+		append(attrs, attribute.String("db.repository.backend", r.backend))...,
+	))
+	defer span.End()
+
+	start := time.Now()
+	result, err := fn(ctx)
+	elapsed := time.Since(start)
+
+	class := classify(err)
+	span.SetAttributes(attribute.String("error.class", class))
+	r.duration.Record(ctx, float64(elapsed.Microseconds())/1000, metric.WithAttributes(
+		attribute.String("db.repository.method", method),
+		attribute.String("db.repository.backend", r.backend),
+		attribute.String("error.class", class),
+	))
+
+	args := []any{"method", method, "error_class", class, "duration_ms", elapsed.Milliseconds()}
+	switch {
+	case expected(class):
+		if err != nil {
+			args = append(args, logging.Error, err)
+		}
+		span.SetStatus(codes.Ok, class)
+		r.logger.DebugCtx(ctx, "Repository call completed", args...)
+	case class == classCanceled:
+		span.SetStatus(codes.Error, err.Error())
+		r.logger.DebugCtx(ctx, "Repository call canceled", append(args, logging.Error, err)...)
+	default:
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		r.logger.ErrorCtx(ctx, "Repository call failed", append(args, logging.Error, err)...)
+	}
+	return result, err
+}
+
+// exec is call for methods that only return an error.
+func exec(ctx context.Context, r *UserRepository, method string, attrs []attribute.KeyValue, fn func(ctx context.Context) error) error {
+	_, err := call(ctx, r, method, attrs, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+func userID(id string) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String("user.id", id)}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	return exec(ctx, r, "Create", userID(user.ID), func(ctx context.Context) error {
+		return r.repo.Create(ctx, user)
+	})
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	return call(ctx, r, "GetByID", userID(id), func(ctx context.Context) (*models.User, error) {
+		return r.repo.GetByID(ctx, id)
+	})
+}
+
+func (r *UserRepository) BatchGet(ctx context.Context, ids []string) ([]*models.User, error) {
+	return call(ctx, r, "BatchGet", []attribute.KeyValue{attribute.Int("db.batch_size", len(ids))}, func(ctx context.Context) ([]*models.User, error) {
+		return r.repo.BatchGet(ctx, ids)
+	})
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	return exec(ctx, r, "Update", userID(user.ID), func(ctx context.Context) error {
+		return r.repo.Update(ctx, user)
+	})
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	return exec(ctx, r, "Delete", userID(id), func(ctx context.Context) error {
+		return r.repo.Delete(ctx, id)
+	})
+}
+
+// page is a page of users and the total they were taken from.
+type page struct {
+	users []*models.User
+	total int
+}
+
+func (r *UserRepository) List(ctx context.Context, offset, limit int) ([]*models.User, int, error) {
+	attrs := []attribute.KeyValue{attribute.Int("db.offset", offset), attribute.Int("db.limit", limit)}
+	p, err := call(ctx, r, "List", attrs, func(ctx context.Context) (page, error) {
+		users, total, err := r.repo.List(ctx, offset, limit)
+		return page{users, total}, err
+	})
+	return p.users, p.total, err
+}
+
+func (r *UserRepository) SearchByPrefix(ctx context.Context, filter repository.PrefixFilter, offset, limit int) ([]*models.User, int, error) {
+	attrs := []attribute.KeyValue{attribute.Int("db.offset", offset), attribute.Int("db.limit", limit)}
+	p, err := call(ctx, r, "SearchByPrefix", attrs, func(ctx context.Context) (page, error) {
+		users, total, err := r.repo.SearchByPrefix(ctx, filter, offset, limit)
+		return page{users, total}, err
+	})
+	return p.users, p.total, err
+}
+
+func (r *UserRepository) EmailExists(ctx context.Context, email string, excludeID string) (bool, error) {
+	return call(ctx, r, "EmailExists", nil, func(ctx context.Context) (bool, error) {
+		return r.repo.EmailExists(ctx, email, excludeID)
+	})
+}
+
+func (r *UserRepository) ListInactive(ctx context.Context, cutoff time.Time, limit int) ([]*models.User, error) {
+	return call(ctx, r, "ListInactive", []attribute.KeyValue{attribute.Int("db.limit", limit)}, func(ctx context.Context) ([]*models.User, error) {
+		return r.repo.ListInactive(ctx, cutoff, limit)
+	})
+}
+
+func (r *UserRepository) Expire(ctx context.Context, id string, cutoff time.Time) (bool, error) {
+	return call(ctx, r, "Expire", userID(id), func(ctx context.Context) (bool, error) {
+		return r.repo.Expire(ctx, id, cutoff)
+	})
+}
+
+func (r *UserRepository) GetAt(ctx context.Context, id string, at time.Time) (*models.UserVersion, error) {
+	return call(ctx, r, "GetAt", userID(id), func(ctx context.Context) (*models.UserVersion, error) {
+		return r.repo.GetAt(ctx, id, at)
+	})
+}
+
+func (r *UserRepository) History(ctx context.Context, id string) ([]*models.UserVersion, error) {
+	return call(ctx, r, "History", userID(id), func(ctx context.Context) ([]*models.UserVersion, error) {
+		return r.repo.History(ctx, id)
+	})
+}
+
+func (r *UserRepository) PruneHistory(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	return call(ctx, r, "PruneHistory", []attribute.KeyValue{attribute.Int("db.limit", limit)}, func(ctx context.Context) (int, error) {
+		return r.repo.PruneHistory(ctx, cutoff, limit)
+	})
+}
+
+func (r *UserRepository) Erase(ctx context.Context, id string) error {
+	return exec(ctx, r, "Erase", userID(id), func(ctx context.Context) error {
+		return r.repo.Erase(ctx, id)
+	})
+}
+
+func (r *UserRepository) Revert(ctx context.Context, id string, versionID int64) (*models.User, error) {
+	attrs := append(userID(id), attribute.Int64("user.version_id", versionID))
+	return call(ctx, r, "Revert", attrs, func(ctx context.Context) (*models.User, error) {
+		return r.repo.Revert(ctx, id, versionID)
+	})
+}
+
+// merged is the pair of users Merge returns.
+type merged struct {
+	target, source *models.User
+}
+
+func (r *UserRepository) Merge(ctx context.Context, sourceID, targetID string, policy models.MergePolicy) (*models.User, *models.User, error) {
+	attrs := []attribute.KeyValue{attribute.String("user.source_id", sourceID), attribute.String("user.target_id", targetID)}
+	m, err := call(ctx, r, "Merge", attrs, func(ctx context.Context) (merged, error) {
+		target, source, err := r.repo.Merge(ctx, sourceID, targetID, policy)
+		return merged{target, source}, err
+	})
+	return m.target, m.source, err
+}
+
+func (r *UserRepository) RequestEmailChange(ctx context.Context, change *models.EmailChange) error {
+	return exec(ctx, r, "RequestEmailChange", userID(change.UserID), func(ctx context.Context) error {
+		return r.repo.RequestEmailChange(ctx, change)
+	})
+}
+
+func (r *UserRepository) PendingEmailChange(ctx context.Context, id string) (*models.EmailChange, error) {
+	return call(ctx, r, "PendingEmailChange", userID(id), func(ctx context.Context) (*models.EmailChange, error) {
+		return r.repo.PendingEmailChange(ctx, id)
+	})
+}
+
+func (r *UserRepository) ConfirmEmailChange(ctx context.Context, id, token string) (*models.User, error) {
+	return call(ctx, r, "ConfirmEmailChange", userID(id), func(ctx context.Context) (*models.User, error) {
+		return r.repo.ConfirmEmailChange(ctx, id, token)
+	})
+}
+
+func (r *UserRepository) PruneEmailChanges(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	return call(ctx, r, "PruneEmailChanges", []attribute.KeyValue{attribute.Int("db.limit", limit)}, func(ctx context.Context) (int, error) {
+		return r.repo.PruneEmailChanges(ctx, cutoff, limit)
+	})
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"time"
+)
+
+// Fault makes calls to one repository method misbehave, so service-layer
+// tests can simulate database failures, slow queries and races.
+type Fault struct {
+	// Latency delays the call, or until its context is done.
+	Latency time.Duration
+	// Before runs ahead of the call without the repository locked, so it can
+	// change the repository as a concurrent request would; creating a user
+	// with the same email ahead of Create reproduces a duplicate-email race.
+	Before func(ctx context.Context, r *UserRepository)
+	// Err is returned instead of running the call.
+	Err error
+	// Times limits the fault to the next Times calls; 0 applies it to every
+	// call until cleared.
+	Times int
+}
+
+// WithFault injects f into every call to method, named as on
+// repository.UserRepository ("Create", "GetByID").
+func WithFault(method string, f Fault) Option {
+	return func(r *UserRepository) {
+		r.faults[method] = &f
+	}
+}
+
+// InjectFault replaces the fault of method, named as on
+// repository.UserRepository, with f.
+func (r *UserRepository) InjectFault(method string, f Fault) {
+	r.faultMu.Lock()
+	defer r.faultMu.Unlock()
+	r.faults[method] = &f
+}
+
+// ClearFaults removes every injected fault.
+func (r *UserRepository) ClearFaults() {
+	r.faultMu.Lock()
+	defer r.faultMu.Unlock()
+	clear(r.faults)
+}
+
+// fault applies the fault injected into method, if any, and returns the error
+// the call should fail with. It must be called without r.mu held.
+func (r *UserRepository) fault(ctx context.Context, method string) error {
+	r.faultMu.Lock()
+	f, ok := r.faults[method]
+	if !ok {
+		r.faultMu.Unlock()
+		return nil
+	}
+	injected := *f
+	if f.Times > 0 {
+		if f.Times--; f.Times == 0 {
+			delete(r.faults, method)
+		}
+	}
+	r.faultMu.Unlock()
+
+	if injected.Latency > 0 {
+		timer := time.NewTimer(injected.Latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if injected.Before != nil {
+		injected.Before(ctx, r)
+	}
+	return injected.Err
+}
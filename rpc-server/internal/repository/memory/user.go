@@ -0,0 +1,557 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"grpc-server/internal/actor"
+	"grpc-server/internal/clock"
+	"grpc-server/internal/models"
+	"grpc-server/internal/repository"
+)
+
+// UserRepository is an in-memory repository.UserRepository with the same
+// observable semantics as the postgres implementation (unique emails, newest
+// first listing). It is safe for concurrent use. Tests can make individual
+// methods fail or slow down with InjectFault.
+type UserRepository struct {
+	mu      sync.RWMutex
+	users   map[string]*models.User
+	history map[string][]*models.UserVersion
+	// lastVersionID mirrors the user_history.history_id sequence.
+	lastVersionID int64
+	emailChanges  map[string]*models.EmailChange
+	clock         clock.Clock
+
+	// faults are injected per method; see Fault.
+	faultMu sync.Mutex
+	faults  map[string]*Fault
+}
+
+// Option configures a UserRepository.
+type Option func(*UserRepository)
+
+// WithClock sets the clock timestamps and history are recorded by. The
+// default is clock.System.
+func WithClock(clk clock.Clock) Option {
+	return func(r *UserRepository) {
+		r.clock = clk
+	}
+}
+
+func NewUserRepository(opts ...Option) *UserRepository {
+	r := &UserRepository{
+		users:        make(map[string]*models.User),
+		history:      make(map[string][]*models.UserVersion),
+		emailChanges: make(map[string]*models.EmailChange),
+		clock:        clock.System,
+		faults:       make(map[string]*Fault),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	if err := r.fault(ctx, "Create"); err != nil {
+		return err
+	}
+
+	if _, err := uuid.Parse(user.ID); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[user.ID]; ok {
+		return repository.ErrUserExists
+	}
+	if r.emailTaken(user.Email, "") {
+		return repository.ErrEmailExists
+	}
+
+	stored := *user
+	if stored.Status == "" {
+		stored.Status = models.StatusActive
+	}
+	stored.CreatedBy = actor.FromContext(ctx)
+	stored.UpdatedBy = stored.CreatedBy
+	r.users[user.ID] = &stored
+	r.record(user.ID)
+	*user = stored
+	return nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	if err := r.fault(ctx, "GetByID"); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+
+	found := *user
+	return &found, nil
+}
+
+func (r *UserRepository) BatchGet(ctx context.Context, ids []string) ([]*models.User, error) {
+	if err := r.fault(ctx, "BatchGet"); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var users []*models.User
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			found := *user
+			users = append(users, &found)
+		}
+	}
+	return users, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	if err := r.fault(ctx, "Update"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.users[user.ID]
+	if !ok {
+		return repository.ErrUserNotFound
+	}
+	if r.emailTaken(user.Email, user.ID) {
+		return repository.ErrEmailExists
+	}
+
+	updated := *user
+	updated.CreatedAt = existing.CreatedAt
+	updated.CreatedBy = existing.CreatedBy
+	updated.Status = existing.Status
+	updated.MergedInto = existing.MergedInto
+	updated.UpdatedAt = r.clock.Now()
+	updated.UpdatedBy = actor.FromContext(ctx)
+	r.users[user.ID] = &updated
+	r.record(user.ID)
+
+	*user = updated
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	if err := r.fault(ctx, "Delete"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; !ok {
+		return repository.ErrUserNotFound
+	}
+	delete(r.users, id)
+	r.record(id)
+	r.detachMerged(id)
+	delete(r.emailChanges, id)
+	return nil
+}
+
+func (r *UserRepository) List(ctx context.Context, offset, limit int) ([]*models.User, int, error) {
+	if err := r.fault(ctx, "List"); err != nil {
+		return nil, 0, err
+	}
+
+	return r.SearchByPrefix(ctx, repository.PrefixFilter{}, offset, limit)
+}
+
+func (r *UserRepository) SearchByPrefix(ctx context.Context, filter repository.PrefixFilter, offset, limit int) ([]*models.User, int, error) {
+	if err := r.fault(ctx, "SearchByPrefix"); err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	namePrefix, emailPrefix := strings.ToLower(filter.NamePrefix), strings.ToLower(filter.EmailPrefix)
+	all := make([]*models.User, 0, len(r.users))
+	for _, user := range r.users {
+		if user.Status != models.StatusMerged &&
+			strings.HasPrefix(strings.ToLower(user.Name), namePrefix) &&
+			strings.HasPrefix(strings.ToLower(user.Email), emailPrefix) {
+			all = append(all, user)
+		}
+	}
+	slices.SortFunc(all, func(a, b *models.User) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	total := len(all)
+	start := min(max(offset, 0), total)
+	end := min(start+max(limit, 0), total)
+
+	users := make([]*models.User, 0, end-start)
+	for _, user := range all[start:end] {
+		found := *user
+		users = append(users, &found)
+	}
+	return users, total, nil
+}
+
+func (r *UserRepository) EmailExists(ctx context.Context, email string, excludeID string) (bool, error) {
+	if err := r.fault(ctx, "EmailExists"); err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.emailTaken(email, excludeID), nil
+}
+
+func (r *UserRepository) ListInactive(ctx context.Context, cutoff time.Time, limit int) ([]*models.User, error) {
+	if err := r.fault(ctx, "ListInactive"); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var inactive []*models.User
+	for _, user := range r.users {
+		if user.Status == models.StatusActive && user.UpdatedAt.Before(cutoff) {
+			found := *user
+			inactive = append(inactive, &found)
+		}
+	}
+	slices.SortFunc(inactive, func(a, b *models.User) int {
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	})
+	if len(inactive) > limit {
+		inactive = inactive[:max(limit, 0)]
+	}
+	return inactive, nil
+}
+
+func (r *UserRepository) Expire(ctx context.Context, id string, cutoff time.Time) (bool, error) {
+	if err := r.fault(ctx, "Expire"); err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.Status != models.StatusActive || !user.UpdatedAt.Before(cutoff) {
+		return false, nil
+	}
+	expired := *user
+	expired.Status = models.StatusExpired
+	expired.UpdatedAt = r.clock.Now()
+	expired.UpdatedBy = actor.FromContext(ctx)
+	r.users[id] = &expired
+	r.record(id)
+	return true, nil
+}
+
+func (r *UserRepository) GetAt(ctx context.Context, id string, at time.Time) (*models.UserVersion, error) {
+	if err := r.fault(ctx, "GetAt"); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := r.history[id]
+	for i := len(versions) - 1; i >= 0; i-- {
+		v := versions[i]
+		if !v.ValidFrom.After(at) && (v.ValidTo.IsZero() || v.ValidTo.After(at)) {
+			found := *v
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrUserNotFound
+}
+
+func (r *UserRepository) History(ctx context.Context, id string) ([]*models.UserVersion, error) {
+	if err := r.fault(ctx, "History"); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := make([]*models.UserVersion, 0, len(r.history[id]))
+	for _, v := range r.history[id] {
+		found := *v
+		versions = append(versions, &found)
+	}
+	return versions, nil
+}
+
+func (r *UserRepository) PruneHistory(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	if err := r.fault(ctx, "PruneHistory"); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ended []*models.UserVersion
+	for _, versions := range r.history {
+		for _, v := range versions {
+			if !v.ValidTo.IsZero() && v.ValidTo.Before(cutoff) {
+				ended = append(ended, v)
+			}
+		}
+	}
+	slices.SortFunc(ended, func(a, b *models.UserVersion) int {
+		return a.ValidTo.Compare(b.ValidTo)
+	})
+	ended = ended[:min(max(limit, 0), len(ended))]
+	for _, v := range ended {
+		r.history[v.ID] = slices.DeleteFunc(r.history[v.ID], func(kept *models.UserVersion) bool { return kept == v })
+		if len(r.history[v.ID]) == 0 {
+			delete(r.history, v.ID)
+		}
+	}
+	return len(ended), nil
+}
+
+func (r *UserRepository) Erase(ctx context.Context, id string) error {
+	if err := r.fault(ctx, "Erase"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; !ok {
+		return repository.ErrUserNotFound
+	}
+	delete(r.users, id)
+	delete(r.history, id)
+	r.detachMerged(id)
+	delete(r.emailChanges, id)
+	return nil
+}
+
+func (r *UserRepository) Revert(ctx context.Context, id string, versionID int64) (*models.User, error) {
+	if err := r.fault(ctx, "Revert"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	i := slices.IndexFunc(r.history[id], func(v *models.UserVersion) bool { return v.VersionID == versionID })
+	if i < 0 {
+		return nil, repository.ErrVersionNotFound
+	}
+	version := r.history[id][i]
+	if r.emailTaken(version.Email, id) {
+		return nil, repository.ErrEmailExists
+	}
+
+	reverted := *existing
+	reverted.Name = version.Name
+	reverted.Email = version.Email
+	reverted.Age = version.Age
+	reverted.Status = version.Status
+	reverted.MergedInto = version.MergedInto
+	reverted.UpdatedAt = r.clock.Now()
+	reverted.UpdatedBy = actor.FromContext(ctx)
+	r.users[id] = &reverted
+	r.record(id)
+
+	user := reverted
+	return &user, nil
+}
+
+func (r *UserRepository) Merge(ctx context.Context, sourceID, targetID string, policy models.MergePolicy) (*models.User, *models.User, error) {
+	if err := r.fault(ctx, "Merge"); err != nil {
+		return nil, nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	source, ok := r.users[sourceID]
+	if !ok {
+		return nil, nil, repository.ErrUserNotFound
+	}
+	target, ok := r.users[targetID]
+	if !ok {
+		return nil, nil, repository.ErrUserNotFound
+	}
+	if source.Status == models.StatusMerged || target.Status == models.StatusMerged {
+		return nil, nil, repository.ErrUserMerged
+	}
+
+	merged := *target
+	merged.Merge(r.clock, source, policy)
+	merged.UpdatedBy = actor.FromContext(ctx)
+	r.users[targetID] = &merged
+	r.record(targetID)
+
+	deleted := *source
+	deleted.Status = models.StatusMerged
+	deleted.MergedInto = targetID
+	deleted.UpdatedAt = merged.UpdatedAt
+	deleted.UpdatedBy = merged.UpdatedBy
+	r.users[sourceID] = &deleted
+	r.record(sourceID)
+
+	t, s := merged, deleted
+	return &t, &s, nil
+}
+
+func (r *UserRepository) RequestEmailChange(ctx context.Context, change *models.EmailChange) error {
+	if err := r.fault(ctx, "RequestEmailChange"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[change.UserID]; !ok {
+		return repository.ErrUserNotFound
+	}
+	stored := *change
+	r.emailChanges[change.UserID] = &stored
+	return nil
+}
+
+func (r *UserRepository) PendingEmailChange(ctx context.Context, id string) (*models.EmailChange, error) {
+	if err := r.fault(ctx, "PendingEmailChange"); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	change, ok := r.emailChanges[id]
+	if !ok {
+		return nil, repository.ErrEmailChangeNotFound
+	}
+	found := *change
+	return &found, nil
+}
+
+func (r *UserRepository) ConfirmEmailChange(ctx context.Context, id, token string) (*models.User, error) {
+	if err := r.fault(ctx, "ConfirmEmailChange"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	change, ok := r.emailChanges[id]
+	if !ok {
+		return nil, repository.ErrEmailChangeNotFound
+	}
+	if !change.Matches(token) {
+		return nil, repository.ErrInvalidToken
+	}
+	now := r.clock.Now()
+	if change.Expired(now) {
+		delete(r.emailChanges, id)
+		return nil, repository.ErrEmailChangeExpired
+	}
+	if r.emailTaken(change.NewEmail, id) {
+		return nil, repository.ErrEmailExists
+	}
+
+	updated := *r.users[id]
+	updated.Email = change.NewEmail
+	updated.UpdatedAt = now
+	updated.UpdatedBy = actor.FromContext(ctx)
+	r.users[id] = &updated
+	r.record(id)
+	delete(r.emailChanges, id)
+
+	user := updated
+	return &user, nil
+}
+
+func (r *UserRepository) PruneEmailChanges(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	if err := r.fault(ctx, "PruneEmailChanges"); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var expired []*models.EmailChange
+	for _, change := range r.emailChanges {
+		if change.ExpiresAt.Before(cutoff) {
+			expired = append(expired, change)
+		}
+	}
+	slices.SortFunc(expired, func(a, b *models.EmailChange) int {
+		return a.ExpiresAt.Compare(b.ExpiresAt)
+	})
+	expired = expired[:min(max(limit, 0), len(expired))]
+	for _, change := range expired {
+		delete(r.emailChanges, change.UserID)
+	}
+	return len(expired), nil
+}
+
+// detachMerged mirrors ON DELETE SET NULL on users.merged_into after id is
+// removed. It must be called with r.mu held.
+func (r *UserRepository) detachMerged(id string) {
+	for _, user := range r.users {
+		if user.MergedInto == id {
+			detached := *user
+			detached.MergedInto = ""
+			r.users[user.ID] = &detached
+			r.record(user.ID)
+		}
+	}
+}
+
+// record mirrors the postgres history trigger: it closes the current version
+// of id and, unless id was deleted, opens a new one. It must be called with
+// r.mu held.
+func (r *UserRepository) record(id string) {
+	now := r.clock.Now()
+	versions := r.history[id]
+	if n := len(versions); n > 0 && versions[n-1].ValidTo.IsZero() {
+		versions[n-1].ValidTo = now
+	}
+	if user, ok := r.users[id]; ok {
+		r.lastVersionID++
+		versions = append(versions, &models.UserVersion{User: *user, VersionID: r.lastVersionID, ValidFrom: now})
+	}
+	r.history[id] = versions
+}
+
+// emailTaken must be called with r.mu held.
+func (r *UserRepository) emailTaken(email, excludeID string) bool {
+	for id, user := range r.users {
+		if id != excludeID && user.Email == email {
+			return true
+		}
+	}
+	return false
+}
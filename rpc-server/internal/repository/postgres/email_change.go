@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"grpc-server/internal/actor"
+	database "grpc-server/internal/database/generated"
+	"grpc-server/internal/models"
+	"grpc-server/internal/repository"
+)
+
+func (r *UserRepository) toDomainEmailChange(dbChange database.EmailChange) *models.EmailChange {
+	return &models.EmailChange{
+		UserID:      uuid.UUID(dbChange.UserID.Bytes).String(),
+		NewEmail:    dbChange.NewEmail,
+		TokenHash:   dbChange.TokenHash,
+		RequestedAt: dbChange.RequestedAt.Time,
+		ExpiresAt:   dbChange.ExpiresAt.Time,
+	}
+}
+
+func (r *UserRepository) RequestEmailChange(ctx context.Context, change *models.EmailChange) error {
+	pgUUID, err := parseUUID(change.UserID)
+	if err != nil {
+		return repository.ErrUserNotFound
+	}
+
+	var requestedAt, expiresAt pgtype.Timestamptz
+	if err := requestedAt.Scan(change.RequestedAt); err != nil {
+		return err
+	}
+	if err := expiresAt.Scan(change.ExpiresAt); err != nil {
+		return err
+	}
+
+	return r.inTenant(ctx, func(qtx *database.Queries) error {
+		// Check existence first so a missing user is not reported as a
+		// foreign key violation.
+		if _, err := qtx.GetUserByID(ctx, pgUUID); err != nil {
+			if err == pgx.ErrNoRows {
+				return repository.ErrUserNotFound
+			}
+			return err
+		}
+		return qtx.UpsertEmailChange(ctx, database.UpsertEmailChangeParams{
+			UserID:      pgUUID,
+			NewEmail:    change.NewEmail,
+			TokenHash:   change.TokenHash,
+			RequestedAt: requestedAt,
+			ExpiresAt:   expiresAt,
+		})
+	})
+}
+
+func (r *UserRepository) PendingEmailChange(ctx context.Context, id string) (*models.EmailChange, error) {
+	pgUUID, err := parseUUID(id)
+	if err != nil {
+		return nil, repository.ErrEmailChangeNotFound
+	}
+
+	var dbChange database.EmailChange
+	err = r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		dbChange, err = qtx.GetEmailChange(ctx, pgUUID)
+		return err
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, repository.ErrEmailChangeNotFound
+		}
+		return nil, err
+	}
+	return r.toDomainEmailChange(dbChange), nil
+}
+
+func (r *UserRepository) ConfirmEmailChange(ctx context.Context, id, token string) (*models.User, error) {
+	pgUUID, err := parseUUID(id)
+	if err != nil {
+		return nil, repository.ErrEmailChangeNotFound
+	}
+
+	// Runs at SERIALIZABLE so checking the pending change and taking the new
+	// email happen against one consistent state.
+	var dbUser database.User
+	var expired bool
+	err = r.serializable(ctx, "confirm_email_change", func(qtx *database.Queries) error {
+		dbChange, err := qtx.LockEmailChange(ctx, pgUUID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return repository.ErrEmailChangeNotFound
+			}
+			return err
+		}
+		change := r.toDomainEmailChange(dbChange)
+		if !change.Matches(token) {
+			return repository.ErrInvalidToken
+		}
+
+		// An expired change is deleted and committed, then reported.
+		now := r.clock.Now()
+		if expired = change.Expired(now); expired {
+			if err := qtx.DeleteEmailChange(ctx, pgUUID); err != nil {
+				return err
+			}
+			return nil
+		}
+
+		var updatedAt pgtype.Timestamptz
+		if err := updatedAt.Scan(now); err != nil {
+			return err
+		}
+		dbUser, err = qtx.UpdateUserEmail(ctx, database.UpdateUserEmailParams{ID: pgUUID, Email: change.NewEmail, UpdatedAt: updatedAt, UpdatedBy: actor.FromContext(ctx)})
+		if err != nil {
+			if emailConflict(err) {
+				return repository.ErrEmailExists
+			}
+			return err
+		}
+		if err := qtx.DeleteEmailChange(ctx, pgUUID); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, repository.ErrEmailChangeExpired
+	}
+
+	user := r.toDomainUser(dbUser)
+	return user, nil
+}
+
+func (r *UserRepository) PruneEmailChanges(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	var expiredBefore pgtype.Timestamptz
+	if err := expiredBefore.Scan(cutoff); err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	err := r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		rows, err = qtx.PruneEmailChanges(ctx, database.PruneEmailChangesParams{ExpiresAt: expiredBefore, Limit: int32(limit)})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(rows), nil
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	db "grpc-server/internal/database"
+	database "grpc-server/internal/database/generated"
+	"grpc-server/internal/logging"
+)
+
+const (
+	// maxSerializableAttempts caps how often a transaction is run before a
+	// serialization failure is returned to the caller.
+	maxSerializableAttempts = 5
+	serializableBackoff     = 5 * time.Millisecond
+)
+
+type txMetrics struct {
+	retries  metric.Int64Counter
+	failures metric.Int64Counter
+}
+
+func newTxMetrics() txMetrics {
+	meter := otel.Meter("rpc-server.rpc/repository")
+	retries, _ := meter.Int64Counter("db.tx.serialization_retries",
+		metric.WithDescription("Number of transactions retried after a serialization failure"),
+		metric.WithUnit("{retry}"),
+	)
+	failures, _ := meter.Int64Counter("db.tx.serialization_failures",
+		metric.WithDescription("Number of transactions that still failed after the last attempt"),
+		metric.WithUnit("{transaction}"),
+	)
+	return txMetrics{retries: retries, failures: failures}
+}
+
+// retryable reports whether err is a serialization failure or deadlock,
+// after which the whole transaction can simply be run again.
+func retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == "40001" || pgErr.Code == "40P01")
+}
+
+// serializable runs fn in a SERIALIZABLE transaction and commits it, running
+// it again from the start on serialization failures. fn must not keep state
+// between attempts; an error from fn rolls back and is returned as is.
+func (r *UserRepository) serializable(ctx context.Context, operation string, fn func(qtx *database.Queries) error) error {
+	attrs := metric.WithAttributes(attribute.String("db.operation", operation))
+	for attempt := 1; ; attempt++ {
+		err := r.runSerializable(ctx, fn)
+		if err == nil || !retryable(err) {
+			return err
+		}
+		if attempt == maxSerializableAttempts {
+			r.tx.failures.Add(ctx, 1, attrs)
+			r.logger.WarnCtx(ctx, "Transaction kept failing to serialize", "operation", operation, "attempts", attempt, logging.Error, err)
+			return err
+		}
+
+		r.tx.retries.Add(ctx, 1, attrs)
+		trace.SpanFromContext(ctx).AddEvent("db.tx.retry", trace.WithAttributes(
+			attribute.String("db.operation", operation),
+			attribute.Int("db.tx.attempt", attempt),
+		))
+		r.logger.DebugCtx(ctx, "Retrying transaction after serialization failure", "operation", operation, "attempt", attempt)
+
+		// Jittered exponential backoff so the conflicting transactions don't
+		// collide again on the next attempt.
+		backoff := serializableBackoff << (attempt - 1)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff/2 + rand.N(backoff)):
+		}
+	}
+}
+
+func (r *UserRepository) runSerializable(ctx context.Context, fn func(qtx *database.Queries) error) error {
+	return r.runTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}, fn)
+}
+
+// inTenant runs fn in a transaction confined to the tenant of ctx and
+// commits it. Every query runs in one: the row-level security policies only
+// show a transaction the rows of the tenant it set, and none if it set none.
+func (r *UserRepository) inTenant(ctx context.Context, fn func(qtx *database.Queries) error) error {
+	return r.runTx(ctx, pgx.TxOptions{}, fn)
+}
+
+func (r *UserRepository) runTx(ctx context.Context, opts pgx.TxOptions, fn func(qtx *database.Queries) error) error {
+	tx, err := r.pool.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := db.SetTenant(ctx, tx); err != nil {
+		return err
+	}
+	if err := fn(r.queries.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -2,14 +2,19 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"grpc-server/internal/actor"
+	"grpc-server/internal/clock"
 	database "grpc-server/internal/database/generated"
 	"grpc-server/internal/logging"
 	"grpc-server/internal/models"
@@ -20,14 +25,50 @@ type UserRepository struct {
 	pool    *pgxpool.Pool
 	queries *database.Queries
 	logger  *logging.Logger
+	tx      txMetrics
+	clock   clock.Clock
 }
 
-func NewUserRepository(pool *pgxpool.Pool, base *slog.Logger) repository.UserRepository {
-	return &UserRepository{
+// Option configures a UserRepository.
+type Option func(*UserRepository)
+
+// WithClock sets the clock update timestamps are taken from. The default is
+// clock.System.
+func WithClock(clk clock.Clock) Option {
+	return func(r *UserRepository) {
+		r.clock = clk
+	}
+}
+
+func NewUserRepository(pool *pgxpool.Pool, base *slog.Logger, opts ...Option) repository.UserRepository {
+	r := &UserRepository{
 		pool:    pool,
 		queries: database.New(pool),
-		logger:  logging.New(base),
+		logger:  logging.New(logging.ForModule(base, logging.ModuleRepository)),
+		tx:      newTxMetrics(),
+		clock:   clock.System,
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
+}
+
+// idConflict reports whether err is a duplicate user ID, raised by the
+// primary key index of the users partition the ID hashes to.
+func idConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" &&
+		strings.HasPrefix(pgErr.ConstraintName, "users_") && strings.HasSuffix(pgErr.ConstraintName, "_pkey")
+}
+
+// emailConflict reports whether err is a duplicate email, raised by the
+// users_email_key constraint before users was partitioned and by the
+// user_emails primary key since.
+func emailConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" &&
+		(pgErr.ConstraintName == "user_emails_pkey" || pgErr.ConstraintName == "users_email_key")
 }
 
 // Helper to parse UUID string to pgtype.UUID
@@ -51,10 +92,17 @@ func (r *UserRepository) toDomainUser(dbUser database.User) *models.User {
 	}
 
 	user := &models.User{
-		ID:    idStr,
-		Name:  dbUser.Name,
-		Email: dbUser.Email,
-		Age:   dbUser.Age,
+		ID:        idStr,
+		Name:      dbUser.Name,
+		Email:     dbUser.Email,
+		Age:       dbUser.Age,
+		Status:    dbUser.Status,
+		CreatedBy: dbUser.CreatedBy,
+		UpdatedBy: dbUser.UpdatedBy,
+		Tenant:    dbUser.TenantID,
+	}
+	if dbUser.MergedInto.Valid {
+		user.MergedInto = uuid.UUID(dbUser.MergedInto.Bytes).String()
 	}
 
 	if dbUser.CreatedAt.Valid {
@@ -67,7 +115,7 @@ func (r *UserRepository) toDomainUser(dbUser database.User) *models.User {
 	return user
 }
 
-func (r *UserRepository) fromDomainUser(user *models.User) (database.CreateUserParams, error) {
+func (r *UserRepository) fromDomainUser(ctx context.Context, user *models.User) (database.CreateUserParams, error) {
 	userUUID, err := uuid.Parse(user.ID)
 	if err != nil {
 		return database.CreateUserParams{}, err
@@ -92,70 +140,103 @@ func (r *UserRepository) fromDomainUser(user *models.User) (database.CreateUserP
 		Age:       user.Age,
 		CreatedAt: createdAt,
 		UpdatedAt: updatedAt,
+		CreatedBy: actor.FromContext(ctx),
+		UpdatedBy: actor.FromContext(ctx),
 	}, nil
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
-	r.logger.DebugCtx(ctx, "Creating user", logging.UserID, user.ID, logging.UserEmail, user.Email)
-
-	params, err := r.fromDomainUser(user)
+	params, err := r.fromDomainUser(ctx, user)
 	if err != nil {
-		r.logger.ErrorCtx(ctx, "Failed to convert domain user to database params", logging.Error, err, logging.UserID, user.ID)
 		return err
 	}
 
-	dbUser, err := r.queries.CreateUser(ctx, params)
+	var dbUser database.User
+	err = r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		dbUser, err = qtx.CreateUser(ctx, params)
+		return err
+	})
 	if err != nil {
-		r.logger.ErrorCtx(ctx, "Failed to create user in database", logging.Error, err, logging.UserID, user.ID, logging.UserEmail, user.Email)
-		if err.Error() == `ERROR: duplicate key value violates unique constraint "users_email_key" (SQLSTATE 23505)` {
+		if emailConflict(err) {
 			return repository.ErrEmailExists
 		}
+		if idConflict(err) {
+			return repository.ErrUserExists
+		}
 		return err
 	}
 
 	*user = *r.toDomainUser(dbUser)
 
-	r.logger.InfoCtx(ctx, "User created successfully", logging.UserID, user.ID, logging.UserEmail, user.Email, "created_at", user.CreatedAt)
 	return nil
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
-	r.logger.DebugCtx(ctx, "Getting user by ID", logging.UserID, id)
-
 	pgUUID, err := parseUUID(id)
 	if err != nil {
-		r.logger.ErrorCtx(ctx, "Invalid user ID format", logging.Error, err, logging.UserID, id)
 		return nil, repository.ErrUserNotFound
 	}
 
-	dbUser, err := r.queries.GetUserByID(ctx, pgUUID)
+	var dbUser database.User
+	err = r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		dbUser, err = qtx.GetUserByID(ctx, pgUUID)
+		return err
+	})
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			r.logger.DebugCtx(ctx, "User not found", logging.UserID, id)
 			return nil, repository.ErrUserNotFound
 		}
-		r.logger.ErrorCtx(ctx, "Failed to get user from database", logging.Error, err, logging.UserID, id)
 		return nil, err
 	}
 
 	user := r.toDomainUser(dbUser)
 
-	r.logger.DebugCtx(ctx, "User retrieved successfully", logging.UserID, user.ID, logging.UserEmail, user.Email)
 	return user, nil
 }
 
-func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
-	r.logger.DebugCtx(ctx, "Updating user", logging.UserID, user.ID, logging.UserEmail, user.Email)
+func (r *UserRepository) BatchGet(ctx context.Context, ids []string) ([]*models.User, error) {
+	pgUUIDs := make([]pgtype.UUID, 0, len(ids))
+	for _, id := range ids {
+		// Malformed IDs can't name a user
+		if pgUUID, err := parseUUID(id); err == nil {
+			pgUUIDs = append(pgUUIDs, pgUUID)
+		}
+	}
+	if len(pgUUIDs) == 0 {
+		return nil, nil
+	}
+
+	var dbUsers []database.User
+	err := r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		dbUsers, err = qtx.GetUsersByIDs(ctx, pgUUIDs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	found := make(map[string]*models.User, len(dbUsers))
+	for _, dbUser := range dbUsers {
+		user := r.toDomainUser(dbUser)
+		found[user.ID] = user
+	}
+	users := make([]*models.User, 0, len(found))
+	for _, id := range ids {
+		if user, ok := found[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	pgUUID, err := parseUUID(user.ID)
 	if err != nil {
-		r.logger.ErrorCtx(ctx, "Invalid user ID format", logging.Error, err, logging.UserID, user.ID)
 		return repository.ErrUserNotFound
 	}
 
 	var updatedAt pgtype.Timestamptz
-	if err := updatedAt.Scan(time.Now()); err != nil {
-		r.logger.ErrorCtx(ctx, "Failed to scan timestamp", logging.Error, err, logging.UserID, user.ID)
+	if err := updatedAt.Scan(r.clock.Now()); err != nil {
 		return err
 	}
 
@@ -165,59 +246,91 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 		Email:     user.Email,
 		Age:       user.Age,
 		UpdatedAt: updatedAt,
+		UpdatedBy: actor.FromContext(ctx),
 	}
 
-	dbUser, err := r.queries.UpdateUser(ctx, params)
+	var dbUser database.User
+	err = r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		dbUser, err = qtx.UpdateUser(ctx, params)
+		return err
+	})
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			r.logger.DebugCtx(ctx, "User not found for update", logging.UserID, user.ID)
 			return repository.ErrUserNotFound
 		}
-		if err.Error() == `ERROR: duplicate key value violates unique constraint "users_email_key" (SQLSTATE 23505)` {
-			r.logger.ErrorCtx(ctx, "Email already exists", logging.UserEmail, user.Email, logging.UserID, user.ID)
+		if emailConflict(err) {
 			return repository.ErrEmailExists
 		}
-		r.logger.ErrorCtx(ctx, "Failed to update user in database", logging.Error, err, logging.UserID, user.ID)
 		return err
 	}
 
 	*user = *r.toDomainUser(dbUser)
 
-	r.logger.InfoCtx(ctx, "User updated successfully", logging.UserID, user.ID, logging.UserEmail, user.Email, "updated_at", user.UpdatedAt)
 	return nil
 }
 
 func (r *UserRepository) Delete(ctx context.Context, id string) error {
-	r.logger.DebugCtx(ctx, "Deleting user", logging.UserID, id)
-
 	pgUUID, err := parseUUID(id)
 	if err != nil {
-		r.logger.ErrorCtx(ctx, "Invalid user ID format", logging.Error, err, logging.UserID, id)
 		return repository.ErrUserNotFound
 	}
 
-	if err := r.queries.DeleteUser(ctx, pgUUID); err != nil {
-		r.logger.ErrorCtx(ctx, "Failed to delete user from database", logging.Error, err, logging.UserID, id)
+	if err := r.inTenant(ctx, func(qtx *database.Queries) error {
+		return qtx.DeleteUser(ctx, pgUUID)
+	}); err != nil {
 		return repository.ErrUserNotFound
 	}
 
-	r.logger.InfoCtx(ctx, "User deleted successfully", logging.UserID, id)
 	return nil
 }
 
 func (r *UserRepository) List(ctx context.Context, offset, limit int) ([]*models.User, int, error) {
-	r.logger.DebugCtx(ctx, "Listing users", "offset", offset, "limit", limit)
-
-	totalCount, err := r.queries.CountUsers(ctx)
+	var (
+		totalCount int64
+		dbUsers    []database.User
+	)
+	err := r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		if totalCount, err = qtx.CountUsers(ctx); err != nil {
+			return err
+		}
+		dbUsers, err = qtx.ListUsers(ctx, database.ListUsersParams{Limit: int32(limit), Offset: int32(offset)})
+		return err
+	})
 	if err != nil {
-		r.logger.ErrorCtx(ctx, "Failed to count users", logging.Error, err)
 		return nil, 0, err
 	}
 
-	params := database.ListUsersParams{Limit: int32(limit), Offset: int32(offset)}
-	dbUsers, err := r.queries.ListUsers(ctx, params)
+	users := make([]*models.User, len(dbUsers))
+	for i, dbUser := range dbUsers {
+		users[i] = r.toDomainUser(dbUser)
+	}
+
+	return users, int(totalCount), nil
+}
+
+func (r *UserRepository) SearchByPrefix(ctx context.Context, filter repository.PrefixFilter, offset, limit int) ([]*models.User, int, error) {
+	namePattern, emailPattern := prefixPattern(filter.NamePrefix), prefixPattern(filter.EmailPrefix)
+	var (
+		totalCount int64
+		dbUsers    []database.User
+	)
+	err := r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		totalCount, err = qtx.CountUsersByPrefix(ctx, database.CountUsersByPrefixParams{
+			NamePattern:  namePattern,
+			EmailPattern: emailPattern,
+		})
+		if err != nil {
+			return err
+		}
+		dbUsers, err = qtx.SearchUsersByPrefix(ctx, database.SearchUsersByPrefixParams{
+			NamePattern:  namePattern,
+			EmailPattern: emailPattern,
+			RowOffset:    int32(offset),
+			RowLimit:     int32(limit),
+		})
+		return err
+	})
 	if err != nil {
-		r.logger.ErrorCtx(ctx, "Failed to list users from database", logging.Error, err, "offset", offset, "limit", limit)
 		return nil, 0, err
 	}
 
@@ -225,28 +338,305 @@ func (r *UserRepository) List(ctx context.Context, offset, limit int) ([]*models
 	for i, dbUser := range dbUsers {
 		users[i] = r.toDomainUser(dbUser)
 	}
+	return users, int(totalCount), nil
+}
 
-	r.logger.DebugCtx(ctx, "Users retrieved successfully", "total_count", totalCount, "returned_count", len(users), "offset", offset, "limit", limit)
+// likeEscaper escapes LIKE wildcards and the escape character itself.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
 
-	return users, int(totalCount), nil
+// prefixPattern turns a prefix into a lowercase LIKE pattern that matches
+// the prefix literally.
+func prefixPattern(prefix string) string {
+	return likeEscaper.Replace(strings.ToLower(prefix)) + "%"
 }
 
 func (r *UserRepository) EmailExists(ctx context.Context, email string, excludeID string) (bool, error) {
-	r.logger.DebugCtx(ctx, "Checking email existence", logging.UserEmail, email, "exclude_id", excludeID)
-
 	pgUUID, err := parseUUID(excludeID)
 	if err != nil {
-		r.logger.ErrorCtx(ctx, "Invalid exclude ID format", logging.Error, err, "exclude_id", excludeID)
 		return false, err
 	}
 
-	params := database.CheckEmailExistsParams{Email: email, ID: pgUUID}
-	exists, err := r.queries.CheckEmailExists(ctx, params)
+	var exists bool
+	err = r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		exists, err = qtx.CheckEmailExists(ctx, database.CheckEmailExistsParams{Email: email, UserID: pgUUID})
+		return err
+	})
 	if err != nil {
-		r.logger.ErrorCtx(ctx, "Failed to check email existence", logging.Error, err, logging.UserEmail, email, "exclude_id", excludeID)
 		return false, err
 	}
 
-	r.logger.DebugCtx(ctx, "Email existence check completed", logging.UserEmail, email, "exclude_id", excludeID, "exists", exists)
 	return exists, nil
 }
+
+func (r *UserRepository) ListInactive(ctx context.Context, cutoff time.Time, limit int) ([]*models.User, error) {
+	var updatedBefore pgtype.Timestamptz
+	if err := updatedBefore.Scan(cutoff); err != nil {
+		return nil, err
+	}
+
+	var dbUsers []database.User
+	err := r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		dbUsers, err = qtx.ListInactiveUsers(ctx, database.ListInactiveUsersParams{UpdatedAt: updatedBefore, Limit: int32(limit)})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*models.User, len(dbUsers))
+	for i, dbUser := range dbUsers {
+		users[i] = r.toDomainUser(dbUser)
+	}
+	return users, nil
+}
+
+func (r *UserRepository) Expire(ctx context.Context, id string, cutoff time.Time) (bool, error) {
+	pgUUID, err := parseUUID(id)
+	if err != nil {
+		return false, repository.ErrUserNotFound
+	}
+
+	var updatedBefore pgtype.Timestamptz
+	if err := updatedBefore.Scan(cutoff); err != nil {
+		return false, err
+	}
+
+	var rows int64
+	err = r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		rows, err = qtx.ExpireUser(ctx, database.ExpireUserParams{ID: pgUUID, UpdatedAt: updatedBefore, UpdatedBy: actor.FromContext(ctx)})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *UserRepository) toDomainVersion(dbVersion database.UserHistory) *models.UserVersion {
+	version := &models.UserVersion{
+		User: *r.toDomainUser(database.User{
+			ID:         dbVersion.UserID,
+			Name:       dbVersion.Name,
+			Email:      dbVersion.Email,
+			Age:        dbVersion.Age,
+			Status:     dbVersion.Status,
+			MergedInto: dbVersion.MergedInto,
+			CreatedAt:  dbVersion.CreatedAt,
+			UpdatedAt:  dbVersion.UpdatedAt,
+		}),
+		VersionID: dbVersion.HistoryID,
+		ValidFrom: dbVersion.ValidFrom.Time,
+	}
+	if dbVersion.ValidTo.Valid {
+		version.ValidTo = dbVersion.ValidTo.Time
+	}
+	return version
+}
+
+func (r *UserRepository) GetAt(ctx context.Context, id string, at time.Time) (*models.UserVersion, error) {
+	pgUUID, err := parseUUID(id)
+	if err != nil {
+		return nil, repository.ErrUserNotFound
+	}
+
+	var validAt pgtype.Timestamptz
+	if err := validAt.Scan(at); err != nil {
+		return nil, err
+	}
+
+	var dbVersion database.UserHistory
+	err = r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		dbVersion, err = qtx.GetUserVersionAt(ctx, database.GetUserVersionAtParams{UserID: pgUUID, ValidFrom: validAt})
+		return err
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return r.toDomainVersion(dbVersion), nil
+}
+
+func (r *UserRepository) History(ctx context.Context, id string) ([]*models.UserVersion, error) {
+	pgUUID, err := parseUUID(id)
+	if err != nil {
+		return nil, repository.ErrUserNotFound
+	}
+
+	var dbVersions []database.UserHistory
+	err = r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		dbVersions, err = qtx.ListUserHistory(ctx, pgUUID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*models.UserVersion, len(dbVersions))
+	for i, dbVersion := range dbVersions {
+		versions[i] = r.toDomainVersion(dbVersion)
+	}
+	return versions, nil
+}
+
+func (r *UserRepository) PruneHistory(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	var endedBefore pgtype.Timestamptz
+	if err := endedBefore.Scan(cutoff); err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	err := r.inTenant(ctx, func(qtx *database.Queries) (err error) {
+		rows, err = qtx.PruneUserHistory(ctx, database.PruneUserHistoryParams{ValidTo: endedBefore, Limit: int32(limit)})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(rows), nil
+}
+
+func (r *UserRepository) Erase(ctx context.Context, id string) error {
+	pgUUID, err := parseUUID(id)
+	if err != nil {
+		return repository.ErrUserNotFound
+	}
+
+	return r.inTenant(ctx, func(qtx *database.Queries) error {
+		// DeleteUser does not report affected rows, so check existence first.
+		if _, err := qtx.GetUserByID(ctx, pgUUID); err != nil {
+			if err == pgx.ErrNoRows {
+				return repository.ErrUserNotFound
+			}
+			return err
+		}
+		if err := qtx.DeleteUser(ctx, pgUUID); err != nil {
+			return err
+		}
+		// Runs after the delete so the history row closed by the trigger goes too.
+		return qtx.DeleteUserHistory(ctx, pgUUID)
+	})
+}
+
+func (r *UserRepository) Revert(ctx context.Context, id string, versionID int64) (*models.User, error) {
+	pgUUID, err := parseUUID(id)
+	if err != nil {
+		return nil, repository.ErrUserNotFound
+	}
+
+	var updatedAt pgtype.Timestamptz
+	if err := updatedAt.Scan(r.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	// Runs at SERIALIZABLE so the version read and the write see the same
+	// state; the unique constraint alone still guards the email.
+	var dbUser database.User
+	err = r.serializable(ctx, "revert_user", func(qtx *database.Queries) error {
+		dbVersion, err := qtx.GetUserVersion(ctx, database.GetUserVersionParams{HistoryID: versionID, UserID: pgUUID})
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return repository.ErrVersionNotFound
+			}
+			return err
+		}
+
+		// The history trigger records the reverted state as a new version.
+		dbUser, err = qtx.RevertUser(ctx, database.RevertUserParams{
+			ID:         pgUUID,
+			Name:       dbVersion.Name,
+			Email:      dbVersion.Email,
+			Age:        dbVersion.Age,
+			Status:     dbVersion.Status,
+			MergedInto: dbVersion.MergedInto,
+			UpdatedAt:  updatedAt,
+			UpdatedBy:  actor.FromContext(ctx),
+		})
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return repository.ErrUserNotFound
+			}
+			if emailConflict(err) {
+				return repository.ErrEmailExists
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user := r.toDomainUser(dbUser)
+	return user, nil
+}
+
+func (r *UserRepository) Merge(ctx context.Context, sourceID, targetID string, policy models.MergePolicy) (*models.User, *models.User, error) {
+	sourceUUID, err := parseUUID(sourceID)
+	if err != nil {
+		return nil, nil, repository.ErrUserNotFound
+	}
+	targetUUID, err := parseUUID(targetID)
+	if err != nil {
+		return nil, nil, repository.ErrUserNotFound
+	}
+
+	var dbTarget, dbSource database.User
+	err = r.serializable(ctx, "merge_users", func(qtx *database.Queries) error {
+		// LockUsers locks in ID order, so concurrent merges of the same pair
+		// cannot deadlock.
+		dbUsers, err := qtx.LockUsers(ctx, []pgtype.UUID{sourceUUID, targetUUID})
+		if err != nil {
+			return err
+		}
+		var source, target *models.User
+		for _, dbUser := range dbUsers {
+			switch dbUser.ID {
+			case sourceUUID:
+				source = r.toDomainUser(dbUser)
+			case targetUUID:
+				target = r.toDomainUser(dbUser)
+			}
+		}
+		if source == nil || target == nil {
+			return repository.ErrUserNotFound
+		}
+		if source.Status == models.StatusMerged || target.Status == models.StatusMerged {
+			return repository.ErrUserMerged
+		}
+
+		target.Merge(r.clock, source, policy)
+
+		var updatedAt pgtype.Timestamptz
+		if err := updatedAt.Scan(target.UpdatedAt); err != nil {
+			return err
+		}
+		dbTarget, err = qtx.UpdateUser(ctx, database.UpdateUserParams{
+			ID:        targetUUID,
+			Name:      target.Name,
+			Email:     target.Email,
+			Age:       target.Age,
+			UpdatedAt: updatedAt,
+			UpdatedBy: actor.FromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+		dbSource, err = qtx.MergeUser(ctx, database.MergeUserParams{
+			ID:         sourceUUID,
+			MergedInto: targetUUID,
+			UpdatedAt:  updatedAt,
+			UpdatedBy:  actor.FromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r.toDomainUser(dbTarget), r.toDomainUser(dbSource), nil
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"grpc-server/internal/validation"
+)
+
+func FuzzParseUUID(f *testing.F) {
+	for _, id := range []string{
+		"",
+		"4b1f0d7e-8a9c-4f1e-9b7a-2c3d4e5f6a7b",
+		"4B1F0D7E-8A9C-4F1E-9B7A-2C3D4E5F6A7B",
+		"4b1f0d7e8a9c4f1e9b7a2c3d4e5f6a7b",
+		"urn:uuid:4b1f0d7e-8a9c-4f1e-9b7a-2c3d4e5f6a7b",
+		"{4b1f0d7e-8a9c-4f1e-9b7a-2c3d4e5f6a7b}",
+		"4b1f0d7e-8a9c-4f1e-9b7a-2c3d4e5f6a7",
+		"nf-123456",
+		"00000000-0000-0000-0000-000000000000",
+	} {
+		f.Add(id)
+	}
+	f.Fuzz(func(t *testing.T, id string) {
+		parsed, err := parseUUID(id)
+		if (err == nil) != (validation.ValidateUserID(id) == nil) {
+			t.Fatalf("parseUUID(%q) error %v disagrees with validation.ValidateUserID", id, err)
+		}
+		if err != nil {
+			if parsed.Valid {
+				t.Fatalf("parseUUID(%q) failed but returned a valid UUID", id)
+			}
+			return
+		}
+		if !parsed.Valid {
+			t.Fatalf("parseUUID(%q) returned an invalid UUID", id)
+		}
+		// Every accepted form maps to the canonical ID the repository
+		// returns, so lookups by any of them find the same row.
+		canonical := uuid.UUID(parsed.Bytes).String()
+		again, err := parseUUID(canonical)
+		if err != nil || again != parsed {
+			t.Fatalf("parseUUID(%q) = %s, which does not parse back to itself", id, canonical)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package sharded
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthPath serves the health of every shard.
+const HealthPath = "/debug/shards"
+
+// pingTimeout bounds each shard's health check.
+const pingTimeout = 2 * time.Second
+
+// ShardStatus is the health of one shard.
+type ShardStatus struct {
+	Name      string  `json:"name"`
+	Healthy   bool    `json:"healthy"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+}
+
+// Health pings every shard concurrently.
+func (r *UserRepository) Health(ctx context.Context) []ShardStatus {
+	statuses := make([]ShardStatus, len(r.shards))
+	var wg sync.WaitGroup
+	for i, shard := range r.shards {
+		statuses[i] = ShardStatus{Name: shard.Name, Healthy: true}
+		if shard.Ping == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+			defer cancel()
+			start := time.Now()
+			err := shard.Ping(ctx)
+			statuses[i].LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+			if err != nil {
+				statuses[i].Healthy = false
+				statuses[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+	return statuses
+}
+
+// Register serves shard health on mux, answering 503 when any shard is
+// unreachable.
+func (r *UserRepository) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+HealthPath, func(w http.ResponseWriter, req *http.Request) {
+		statuses := r.Health(req.Context())
+		code := http.StatusOK
+		for _, s := range statuses {
+			if !s.Healthy {
+				code = http.StatusServiceUnavailable
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(statuses)
+	})
+}
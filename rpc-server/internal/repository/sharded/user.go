@@ -0,0 +1,334 @@
+// Package sharded spreads users across several repositories, typically one
+// Postgres database each, by hashing the user ID. Calls about one user go to
+// the shard that owns it; listings query every shard and merge the results.
+//
+// Email uniqueness is checked across shards before writes, but not under a
+// common lock, so two concurrent writes on different shards can still claim
+// the same address.
+package sharded
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"grpc-server/internal/models"
+	"grpc-server/internal/repository"
+)
+
+// Shard is one backend of a sharded repository.
+type Shard struct {
+	Name string
+	Repo repository.UserRepository
+	// Ping checks that the shard is reachable; nil skips the check.
+	Ping func(ctx context.Context) error
+}
+
+type UserRepository struct {
+	shards []Shard
+}
+
+// NewUserRepository routes users across shards. The order of shards is part
+// of the routing: append new shards at the end, and move the users whose
+// owner changed before serving traffic.
+func NewUserRepository(shards ...Shard) *UserRepository {
+	return &UserRepository{shards: shards}
+}
+
+// ShardFor returns the index of the shard that owns id.
+func (r *UserRepository) ShardFor(id string) int {
+	h := fnv.New64a()
+	if parsed, err := uuid.Parse(id); err == nil {
+		h.Write(parsed[:])
+	} else {
+		h.Write([]byte(id))
+	}
+	return jumpHash(h.Sum64(), len(r.shards))
+}
+
+// jumpHash is Lamping and Veach's jump consistent hash: growing from n to
+// n+1 buckets moves only 1/(n+1) of the keys, all of them to the new bucket.
+func jumpHash(key uint64, buckets int) int {
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+func (r *UserRepository) owner(id string) repository.UserRepository {
+	return r.shards[r.ShardFor(id)].Repo
+}
+
+// fanOut calls fn with the index of every shard concurrently and returns
+// the results in shard order, or the first error.
+func fanOut[T any](ctx context.Context, r *UserRepository, fn func(ctx context.Context, shard int) (T, error)) ([]T, error) {
+	results := make([]T, len(r.shards))
+	errs := make([]error, len(r.shards))
+	var wg sync.WaitGroup
+	for i := range r.shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = fn(ctx, i)
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("shard %s: %w", r.shards[i].Name, err)
+		}
+	}
+	return results, nil
+}
+
+// emailTakenElsewhere reports whether email belongs to a user, other than
+// id, on a shard that does not own id. The owning shard checks its own users
+// as part of the write.
+func (r *UserRepository) emailTakenElsewhere(ctx context.Context, email, id string) (bool, error) {
+	owner := r.ShardFor(id)
+	taken, err := fanOut(ctx, r, func(ctx context.Context, shard int) (bool, error) {
+		if shard == owner {
+			return false, nil
+		}
+		return r.shards[shard].Repo.EmailExists(ctx, email, id)
+	})
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(taken, true), nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	taken, err := r.emailTakenElsewhere(ctx, user.Email, user.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return repository.ErrEmailExists
+	}
+	return r.owner(user.ID).Create(ctx, user)
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	return r.owner(id).GetByID(ctx, id)
+}
+
+// BatchGet asks each shard for the users it owns, then restores the order
+// of ids.
+func (r *UserRepository) BatchGet(ctx context.Context, ids []string) ([]*models.User, error) {
+	owned := make([][]string, len(r.shards))
+	for _, id := range ids {
+		shard := r.ShardFor(id)
+		owned[shard] = append(owned[shard], id)
+	}
+	perShard, err := fanOut(ctx, r, func(ctx context.Context, shard int) ([]*models.User, error) {
+		if len(owned[shard]) == 0 {
+			return nil, nil
+		}
+		return r.shards[shard].Repo.BatchGet(ctx, owned[shard])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]*models.User)
+	for _, users := range perShard {
+		for _, user := range users {
+			found[user.ID] = user
+		}
+	}
+	var users []*models.User
+	for _, id := range ids {
+		if user, ok := found[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	taken, err := r.emailTakenElsewhere(ctx, user.Email, user.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return repository.ErrEmailExists
+	}
+	return r.owner(user.ID).Update(ctx, user)
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	return r.owner(id).Delete(ctx, id)
+}
+
+type page struct {
+	users []*models.User
+	total int
+}
+
+// List merges the newest users of every shard. Each shard returns its first
+// offset+limit users, so deep pages cost more than on a single database.
+func (r *UserRepository) List(ctx context.Context, offset, limit int) ([]*models.User, int, error) {
+	return r.mergePages(ctx, offset, limit, func(ctx context.Context, repo repository.UserRepository, limit int) ([]*models.User, int, error) {
+		return repo.List(ctx, 0, limit)
+	})
+}
+
+// SearchByPrefix merges the matches of every shard the same way as List.
+func (r *UserRepository) SearchByPrefix(ctx context.Context, filter repository.PrefixFilter, offset, limit int) ([]*models.User, int, error) {
+	return r.mergePages(ctx, offset, limit, func(ctx context.Context, repo repository.UserRepository, limit int) ([]*models.User, int, error) {
+		return repo.SearchByPrefix(ctx, filter, 0, limit)
+	})
+}
+
+// mergePages asks every shard for its first offset+limit users with list,
+// then cuts the requested page out of their merge, newest first.
+func (r *UserRepository) mergePages(ctx context.Context, offset, limit int, list func(ctx context.Context, repo repository.UserRepository, limit int) ([]*models.User, int, error)) ([]*models.User, int, error) {
+	offset, limit = max(offset, 0), max(limit, 0)
+	pages, err := fanOut(ctx, r, func(ctx context.Context, shard int) (page, error) {
+		users, total, err := list(ctx, r.shards[shard].Repo, offset+limit)
+		return page{users, total}, err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var all []*models.User
+	total := 0
+	for _, p := range pages {
+		all = append(all, p.users...)
+		total += p.total
+	}
+	slices.SortStableFunc(all, func(a, b *models.User) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	start := min(offset, len(all))
+	end := min(start+limit, len(all))
+	return all[start:end], total, nil
+}
+
+func (r *UserRepository) EmailExists(ctx context.Context, email string, excludeID string) (bool, error) {
+	exists, err := fanOut(ctx, r, func(ctx context.Context, shard int) (bool, error) {
+		return r.shards[shard].Repo.EmailExists(ctx, email, excludeID)
+	})
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(exists, true), nil
+}
+
+func (r *UserRepository) ListInactive(ctx context.Context, cutoff time.Time, limit int) ([]*models.User, error) {
+	batches, err := fanOut(ctx, r, func(ctx context.Context, shard int) ([]*models.User, error) {
+		return r.shards[shard].Repo.ListInactive(ctx, cutoff, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	all := slices.Concat(batches...)
+	slices.SortStableFunc(all, func(a, b *models.User) int {
+		return cmp.Compare(a.UpdatedAt.UnixNano(), b.UpdatedAt.UnixNano())
+	})
+	return all[:min(max(limit, 0), len(all))], nil
+}
+
+func (r *UserRepository) Expire(ctx context.Context, id string, cutoff time.Time) (bool, error) {
+	return r.owner(id).Expire(ctx, id, cutoff)
+}
+
+func (r *UserRepository) GetAt(ctx context.Context, id string, at time.Time) (*models.UserVersion, error) {
+	return r.owner(id).GetAt(ctx, id, at)
+}
+
+func (r *UserRepository) History(ctx context.Context, id string) ([]*models.UserVersion, error) {
+	return r.owner(id).History(ctx, id)
+}
+
+// PruneHistory prunes up to limit versions on every shard.
+func (r *UserRepository) PruneHistory(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	pruned, err := fanOut(ctx, r, func(ctx context.Context, shard int) (int, error) {
+		return r.shards[shard].Repo.PruneHistory(ctx, cutoff, limit)
+	})
+	return sum(pruned), err
+}
+
+func (r *UserRepository) Erase(ctx context.Context, id string) error {
+	return r.owner(id).Erase(ctx, id)
+}
+
+func (r *UserRepository) Revert(ctx context.Context, id string, versionID int64) (*models.User, error) {
+	// A reverted email may have been taken on another shard since.
+	versions, err := r.owner(id).History(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.VersionID != versionID {
+			continue
+		}
+		taken, err := r.emailTakenElsewhere(ctx, v.Email, id)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, repository.ErrEmailExists
+		}
+	}
+	return r.owner(id).Revert(ctx, id, versionID)
+}
+
+// Merge only merges users on the same shard, since the merge must lock both
+// users in one transaction.
+func (r *UserRepository) Merge(ctx context.Context, sourceID, targetID string, policy models.MergePolicy) (*models.User, *models.User, error) {
+	if r.ShardFor(sourceID) != r.ShardFor(targetID) {
+		return nil, nil, repository.ErrCrossShard
+	}
+	return r.owner(targetID).Merge(ctx, sourceID, targetID, policy)
+}
+
+func (r *UserRepository) RequestEmailChange(ctx context.Context, change *models.EmailChange) error {
+	return r.owner(change.UserID).RequestEmailChange(ctx, change)
+}
+
+func (r *UserRepository) PendingEmailChange(ctx context.Context, id string) (*models.EmailChange, error) {
+	return r.owner(id).PendingEmailChange(ctx, id)
+}
+
+func (r *UserRepository) ConfirmEmailChange(ctx context.Context, id, token string) (*models.User, error) {
+	change, err := r.owner(id).PendingEmailChange(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	taken, err := r.emailTakenElsewhere(ctx, change.NewEmail, id)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, repository.ErrEmailExists
+	}
+	return r.owner(id).ConfirmEmailChange(ctx, id, token)
+}
+
+// PruneEmailChanges prunes up to limit email changes on every shard.
+func (r *UserRepository) PruneEmailChanges(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	pruned, err := fanOut(ctx, r, func(ctx context.Context, shard int) (int, error) {
+		return r.shards[shard].Repo.PruneEmailChanges(ctx, cutoff, limit)
+	})
+	return sum(pruned), err
+}
+
+func sum(counts []int) int {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"grpc-server/internal/models"
 )
@@ -11,13 +12,77 @@ var (
 	ErrUserNotFound = errors.New("user not found")
 	ErrUserExists   = errors.New("user already exists")
 	ErrEmailExists  = errors.New("email already exists")
+	// ErrVersionNotFound means the requested history version does not exist
+	// or belongs to a different user.
+	ErrVersionNotFound = errors.New("user version not found")
+	// ErrUserMerged means the user was already merged into another one.
+	ErrUserMerged = errors.New("user already merged")
+	// Email change confirmation errors.
+	ErrEmailChangeNotFound = errors.New("no pending email change")
+	ErrEmailChangeExpired  = errors.New("email change expired")
+	ErrInvalidToken        = errors.New("invalid confirmation token")
+	// ErrCrossShard means the operation spans users stored on different
+	// shards, which a sharded repository cannot do atomically.
+	ErrCrossShard = errors.New("users are on different shards")
 )
 
+// PrefixFilter selects users whose name and email start with the given
+// prefixes, ignoring case. An empty prefix matches every value.
+type PrefixFilter struct {
+	NamePrefix  string
+	EmailPrefix string
+}
+
+// UserRepository stores users. Merged users are soft-deleted: GetByID still
+// returns them, but List leaves them out.
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	GetByID(ctx context.Context, id string) (*models.User, error)
+	// BatchGet returns the users with the given IDs in the order of ids, in
+	// one query per database. IDs without a user are left out.
+	BatchGet(ctx context.Context, ids []string) ([]*models.User, error)
 	Update(ctx context.Context, user *models.User) error
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, offset, limit int) ([]*models.User, int, error)
+	// SearchByPrefix is List restricted to users matching filter.
+	SearchByPrefix(ctx context.Context, filter PrefixFilter, offset, limit int) ([]*models.User, int, error)
 	EmailExists(ctx context.Context, email string, excludeID string) (bool, error)
+	// ListInactive returns up to limit active users last updated before cutoff,
+	// oldest first.
+	ListInactive(ctx context.Context, cutoff time.Time, limit int) ([]*models.User, error)
+	// Expire marks an active user last updated before cutoff as expired. It
+	// reports false if the user was updated or expired in the meantime.
+	Expire(ctx context.Context, id string, cutoff time.Time) (bool, error)
+	// GetAt returns the version of a user that was current at the given time,
+	// or ErrUserNotFound if the user did not exist then.
+	GetAt(ctx context.Context, id string, at time.Time) (*models.UserVersion, error)
+	// History returns every recorded version of a user, oldest first.
+	History(ctx context.Context, id string) ([]*models.UserVersion, error)
+	// PruneHistory deletes up to limit history versions that stopped being
+	// current before cutoff, oldest first, and returns how many it deleted.
+	// Current versions are never pruned.
+	PruneHistory(ctx context.Context, cutoff time.Time, limit int) (int, error)
+	// Erase deletes a user together with its history.
+	Erase(ctx context.Context, id string) error
+	// Revert restores a user's name, email, age and status from one of its
+	// history versions and returns the updated user. The revert itself is
+	// recorded as a new version.
+	Revert(ctx context.Context, id string, versionID int64) (*models.User, error)
+	// Merge folds source into target according to policy and marks source as
+	// merged into target, in one transaction. It returns both users as
+	// updated, or ErrUserMerged if either was already merged.
+	Merge(ctx context.Context, sourceID, targetID string, policy models.MergePolicy) (target, source *models.User, err error)
+	// RequestEmailChange stores change as the user's pending email change,
+	// replacing any earlier one.
+	RequestEmailChange(ctx context.Context, change *models.EmailChange) error
+	// PendingEmailChange returns the user's pending email change, or
+	// ErrEmailChangeNotFound if there is none.
+	PendingEmailChange(ctx context.Context, id string) (*models.EmailChange, error)
+	// ConfirmEmailChange applies the pending email change if token matches
+	// and it has not expired, clears it, and returns the updated user. An
+	// expired change is cleared and reported as ErrEmailChangeExpired.
+	ConfirmEmailChange(ctx context.Context, id, token string) (*models.User, error)
+	// PruneEmailChanges deletes up to limit pending email changes that
+	// expired before cutoff, oldest first, and returns how many it deleted.
+	PruneEmailChanges(ctx context.Context, cutoff time.Time, limit int) (int, error)
 }
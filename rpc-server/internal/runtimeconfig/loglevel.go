@@ -0,0 +1,26 @@
+package runtimeconfig
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// ParseModuleLogLevels parses "module=level" entries, such as
+// "cache=debug", into a ModuleLogLevels map.
+func ParseModuleLogLevels(entries []string) (map[string]slog.Level, error) {
+	levels := make(map[string]slog.Level, len(entries))
+	for _, entry := range entries {
+		module, name, ok := strings.Cut(entry, "=")
+		module = strings.TrimSpace(module)
+		if !ok || module == "" {
+			return nil, fmt.Errorf("log level entry %q is not module=level", entry)
+		}
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+			return nil, fmt.Errorf("invalid log level for %s: %w", module, err)
+		}
+		levels[module] = level
+	}
+	return levels, nil
+}
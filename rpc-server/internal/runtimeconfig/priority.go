@@ -0,0 +1,69 @@
+package runtimeconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Class is a caller's priority class. Lower values are more important; when
+// the rate limit runs low, the least important classes are shed first.
+type Class int
+
+const (
+	// ClassInteractive is the default for callers without a class.
+	ClassInteractive Class = iota
+	ClassBatch
+	ClassLoadTest
+
+	lowestClass = ClassLoadTest
+)
+
+var classNames = map[Class]string{
+	ClassInteractive: "interactive",
+	ClassBatch:       "batch",
+	ClassLoadTest:    "load-test",
+}
+
+func (c Class) String() string {
+	if name, ok := classNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("Class(%d)", int(c))
+}
+
+// ParseClass parses a class name as returned by Class.String.
+func ParseClass(name string) (Class, error) {
+	for c, n := range classNames {
+		if strings.EqualFold(name, n) {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown priority class %q", name)
+}
+
+// ParseCallerClasses parses "caller=class" entries, such as
+// "reports=batch", into a CallerClasses map.
+func ParseCallerClasses(entries []string) (map[string]Class, error) {
+	classes := make(map[string]Class, len(entries))
+	for _, entry := range entries {
+		caller, name, ok := strings.Cut(entry, "=")
+		caller = strings.TrimSpace(caller)
+		if !ok || caller == "" {
+			return nil, fmt.Errorf("priority class entry %q is not caller=class", entry)
+		}
+		class, err := ParseClass(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		classes[caller] = class
+	}
+	return classes, nil
+}
+
+// reserve returns how many tokens class must leave in a bucket of size burst
+// for more important classes: none for interactive callers, and up to
+// LowPriorityReserve of the burst for the least important class.
+func (s *Settings) reserve(class Class) float64 {
+	class = min(max(class, ClassInteractive), lowestClass)
+	return s.LowPriorityReserve * float64(s.RateLimitBurst) * float64(class) / float64(lowestClass)
+}
@@ -0,0 +1,220 @@
+// Package runtimeconfig holds settings that operators can change while the
+// server runs. Readers take a snapshot per request, so a change applies to
+// the next request without locks on the hot path.
+package runtimeconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"grpc-server/internal/logging"
+)
+
+// Settings is one immutable snapshot of the dynamic settings.
+type Settings struct {
+	LogLevel slog.Level
+	// ModuleLogLevels overrides LogLevel for the subsystems in
+	// logging.Modules. It is replaced, never modified, by updates.
+	ModuleLogLevels map[string]slog.Level
+	UserCacheTTL    time.Duration
+	ListCacheTTL    time.Duration
+	// SampleRatio is the fraction of new traces that are sampled.
+	SampleRatio float64
+	// RateLimitQPS caps requests per second across the whole server; 0
+	// disables rate limiting.
+	RateLimitQPS   float64
+	RateLimitBurst int
+	// LowPriorityReserve is the fraction of the rate limit burst held back
+	// from lower priority classes, so interactive traffic keeps flowing while
+	// batch and load-test callers are shed.
+	LowPriorityReserve float64
+	// CallerClasses maps callers, by the subject of their verified token, to
+	// their priority class; unlisted callers are interactive. It is
+	// replaced, never modified, by updates.
+	CallerClasses map[string]Class
+	// ReadOnly rejects every call that could change data.
+	ReadOnly bool
+	// RaceUserReads has GetUser read the cache and the database at once
+	// and answer with whichever finds the user first.
+	RaceUserReads bool
+}
+
+// Validate reports the first setting that is out of range.
+func (s Settings) Validate() error {
+	switch {
+	case s.UserCacheTTL < time.Second:
+		return errors.New("user cache TTL must be at least 1s")
+	case s.ListCacheTTL < time.Second:
+		return errors.New("list cache TTL must be at least 1s")
+	case s.SampleRatio < 0 || s.SampleRatio > 1:
+		return fmt.Errorf("sample ratio %v is not between 0 and 1", s.SampleRatio)
+	case s.RateLimitQPS < 0:
+		return errors.New("rate limit must not be negative")
+	case s.RateLimitQPS > 0 && s.RateLimitBurst < 1:
+		return errors.New("rate limit burst must be at least 1")
+	case s.LowPriorityReserve < 0 || s.LowPriorityReserve >= 1:
+		return fmt.Errorf("low priority reserve %v is not in [0, 1)", s.LowPriorityReserve)
+	}
+	for module := range s.ModuleLogLevels {
+		if !slices.Contains(logging.Modules, module) {
+			return fmt.Errorf("unknown log module %q", module)
+		}
+	}
+	return nil
+}
+
+// Store holds the current settings.
+type Store struct {
+	current atomic.Pointer[Settings]
+	updated atomic.Int64 // unix milliseconds of the last Update, 0 if none
+
+	// mu serializes updates so concurrent changes to different fields
+	// don't overwrite each other.
+	mu      sync.Mutex
+	limiter limiter
+}
+
+// New creates a Store; initial must be valid.
+func New(initial Settings) (*Store, error) {
+	if err := initial.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid runtime config: %w", err)
+	}
+	s := &Store{}
+	s.current.Store(&initial)
+	return s, nil
+}
+
+// Load returns the current snapshot. It must not be modified.
+func (s *Store) Load() *Settings {
+	return s.current.Load()
+}
+
+// Updated returns when the settings last changed, or the zero time.
+func (s *Store) Updated() time.Time {
+	if ms := s.updated.Load(); ms != 0 {
+		return time.UnixMilli(ms)
+	}
+	return time.Time{}
+}
+
+// Update applies fn to a copy of the current settings and publishes the
+// result if fn succeeds and the result is valid.
+func (s *Store) Update(ctx context.Context, fn func(*Settings) error) (*Settings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := s.current.Load()
+	next := *old
+	if err := fn(&next); err != nil {
+		return nil, err
+	}
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+	s.current.Store(&next)
+	s.updated.Store(time.Now().UnixMilli())
+	slog.InfoContext(ctx, "Runtime config changed", "old", *old, "new", next)
+	return &next, nil
+}
+
+var (
+	_ slog.Leveler          = (*Store)(nil)
+	_ logging.ModuleLeveler = (*Store)(nil)
+)
+
+// Level implements slog.Leveler so log handlers follow LogLevel.
+func (s *Store) Level() slog.Level {
+	return s.Load().LogLevel
+}
+
+// ModuleLevel implements logging.ModuleLeveler; modules without a level of
+// their own follow LogLevel.
+func (s *Store) ModuleLevel(module string) slog.Level {
+	settings := s.Load()
+	if level, ok := settings.ModuleLogLevels[module]; ok {
+		return level
+	}
+	return settings.LogLevel
+}
+
+// SampleRatio returns the current trace sample ratio.
+func (s *Store) SampleRatio() float64 {
+	return s.Load().SampleRatio
+}
+
+// Class returns the priority class of caller.
+func (s *Store) Class(caller string) Class {
+	return s.Load().CallerClasses[caller]
+}
+
+// Quota is the state of the rate limit as one call of a class left it.
+type Quota struct {
+	// Allowed reports whether the call was admitted.
+	Allowed bool
+	// Limit is the bucket size; 0 means rate limiting is disabled and the
+	// other fields are unset.
+	Limit int
+	// Remaining is how many more calls the class would be admitted right
+	// now, after its reserve for more important classes.
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the class is admitted again, when it was
+	// not.
+	RetryAfter time.Duration
+}
+
+// Allow takes a token from the server-wide rate limit for a caller of the
+// given class. Lower classes must leave their reserve in the bucket. The
+// returned Quota tells callers how much room is left, so they can slow down
+// before they are rejected.
+func (s *Store) Allow(class Class) Quota {
+	settings := s.Load()
+	if settings.RateLimitQPS <= 0 {
+		return Quota{Allowed: true}
+	}
+	return s.limiter.allow(time.Now(), settings.RateLimitQPS, settings.RateLimitBurst, settings.reserve(class))
+}
+
+// limiter is a token bucket whose rate and size are passed on every call, so
+// it follows setting changes without being rebuilt.
+type limiter struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token if at least reserve tokens remain afterwards.
+func (l *limiter) allow(now time.Time, qps float64, burst int, reserve float64) Quota {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.last.IsZero() {
+		l.tokens = float64(burst)
+	} else {
+		l.tokens += now.Sub(l.last).Seconds() * qps
+	}
+	l.tokens = min(l.tokens, float64(burst))
+	l.last = now
+
+	// A full bucket always admits, or a small burst would starve low classes.
+	need := min(1+reserve, float64(burst))
+	q := Quota{Limit: burst}
+	if l.tokens >= need {
+		l.tokens--
+		q.Allowed = true
+	} else {
+		q.RetryAfter = time.Duration((need - l.tokens) / qps * float64(time.Second))
+	}
+	if l.tokens >= need {
+		q.Remaining = int(l.tokens-need) + 1
+	}
+	q.Reset = time.Duration((float64(burst) - l.tokens) / qps * float64(time.Second))
+	return q
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"grpc-server/internal/buildinfo"
+	"grpc-server/internal/cache"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/repository"
+	"grpc-server/internal/runtimeconfig"
+	pb "grpc-server/pkg/pb"
+)
+
+const (
+	defaultStatsSampleSize = 1000
+	maxStatsSampleSize     = 100000
+)
+
+// AdminServer serves operational RPCs. Stats are per replica: hit and miss
+// counts cover only lookups made by this process since it started.
+type AdminServer struct {
+	pb.UnimplementedAdminServiceServer
+	stats      *cache.StatsCache
+	inspector  cache.Inspector
+	runtime    *runtimeconfig.Store
+	repo       repository.UserRepository
+	instanceID string
+	startedAt  time.Time
+}
+
+// NewAdminServer creates an AdminServer; inspector may be nil, which leaves
+// key counts and memory out of the stats.
+func NewAdminServer(stats *cache.StatsCache, inspector cache.Inspector, runtime *runtimeconfig.Store, repo repository.UserRepository) *AdminServer {
+	instanceID, _ := os.Hostname()
+	return &AdminServer{
+		stats:      stats,
+		inspector:  inspector,
+		runtime:    runtime,
+		repo:       repo,
+		instanceID: instanceID,
+		startedAt:  time.Now(),
+	}
+}
+
+func (s *AdminServer) GetCacheStats(ctx context.Context, req *pb.GetCacheStatsRequest) (*pb.GetCacheStatsResponse, error) {
+	sampleSize := int(req.SampleSize)
+	if sampleSize <= 0 {
+		sampleSize = defaultStatsSampleSize
+	}
+	sampleSize = min(sampleSize, maxStatsSampleSize)
+	logging.FromContext(ctx).DebugCtx(ctx, "GetCacheStats request received", "sample_size", sampleSize)
+
+	counts, since := s.stats.Counts()
+	resp := &pb.GetCacheStatsResponse{
+		InstanceId:       s.instanceID,
+		StatsSinceUnixMs: since.UnixMilli(),
+	}
+
+	var sample cache.KeySample
+	if s.inspector != nil {
+		memory, err := s.inspector.Memory(ctx)
+		if err != nil {
+			logging.FromContext(ctx).ErrorCtx(ctx, "Failed to read cache memory info", logging.Error, err)
+			return nil, internalError("get_cache_stats", "", "failed to read cache memory info")
+		}
+		resp.Memory = &pb.CacheMemoryStats{
+			UsedMemoryBytes:     memory.UsedBytes,
+			UsedMemoryPeakBytes: memory.PeakBytes,
+			MaxMemoryBytes:      memory.MaxBytes,
+			MaxMemoryPolicy:     memory.MaxMemoryPolicy,
+			FragmentationRatio:  memory.FragmentationRatio,
+		}
+
+		if sample, err = s.inspector.SampleKeys(ctx, sampleSize); err != nil {
+			logging.FromContext(ctx).ErrorCtx(ctx, "Failed to sample cache keys", logging.Error, err)
+			return nil, internalError("get_cache_stats", "", "failed to sample cache keys")
+		}
+		resp.TotalKeys = sample.Total
+		resp.SampledKeys = sample.Sampled
+	}
+
+	// Namespaces seen either by lookups or in the key sample.
+	seen := make(map[string]bool, len(counts))
+	for _, c := range counts {
+		seen[c.Namespace] = true
+		ns := &pb.CacheNamespaceStats{
+			Namespace:     c.Namespace,
+			Hits:          c.Hits,
+			Misses:        c.Misses,
+			Errors:        c.Errors,
+			SampledKeys:   sample.Namespaces[c.Namespace],
+			EstimatedKeys: sample.Estimate(c.Namespace),
+		}
+		if lookups := c.Hits + c.Misses; lookups > 0 {
+			ns.HitRatio = float64(c.Hits) / float64(lookups)
+		}
+		resp.Namespaces = append(resp.Namespaces, ns)
+	}
+	for namespace, sampled := range sample.Namespaces {
+		if seen[namespace] {
+			continue
+		}
+		resp.Namespaces = append(resp.Namespaces, &pb.CacheNamespaceStats{
+			Namespace:     namespace,
+			SampledKeys:   sampled,
+			EstimatedKeys: sample.Estimate(namespace),
+		})
+	}
+	slices.SortFunc(resp.Namespaces, func(a, b *pb.CacheNamespaceStats) int {
+		return strings.Compare(a.Namespace, b.Namespace)
+	})
+	return resp, nil
+}
+
+func (s *AdminServer) GetVersion(ctx context.Context, req *pb.GetVersionRequest) (*pb.GetVersionResponse, error) {
+	info := buildinfo.Get()
+	return &pb.GetVersionResponse{
+		Version:         info.Version,
+		Commit:          info.Commit,
+		BuildDate:       info.Date,
+		GoVersion:       info.GoVersion,
+		Modified:        info.Modified,
+		InstanceId:      s.instanceID,
+		StartedAtUnixMs: s.startedAt.UnixMilli(),
+	}, nil
+}
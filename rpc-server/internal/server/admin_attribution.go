@@ -0,0 +1,31 @@
+package server
+
+import (
+	"context"
+
+	"grpc-server/internal/logging"
+	"grpc-server/internal/repository"
+	pb "grpc-server/pkg/pb"
+)
+
+// GetUserAttribution reads from the repository rather than the cache, since
+// attribution is not part of the public user and is rarely requested.
+func (s *AdminServer) GetUserAttribution(ctx context.Context, req *pb.GetUserAttributionRequest) (*pb.GetUserAttributionResponse, error) {
+	logging.FromContext(ctx).DebugCtx(ctx, "GetUserAttribution request received", logging.UserID, req.Id)
+
+	user, err := s.repo.GetByID(ctx, req.Id)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return nil, userNotFoundError(req.Id)
+		}
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to get user attribution from repository", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "get_user_attribution", req.Id, "failed to retrieve user")
+	}
+	return &pb.GetUserAttributionResponse{
+		UserId:    user.ID,
+		CreatedBy: user.CreatedBy,
+		CreatedAt: user.CreatedAt.Unix(),
+		UpdatedBy: user.UpdatedBy,
+		UpdatedAt: user.UpdatedAt.Unix(),
+	}, nil
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"grpc-server/internal/logging"
+	"grpc-server/internal/runtimeconfig"
+	pb "grpc-server/pkg/pb"
+)
+
+// runtimeConfigFields is the safelist of settings SetRuntimeConfig may change,
+// keyed by RuntimeConfig field name.
+var runtimeConfigFields = map[string]func(*runtimeconfig.Settings, *pb.RuntimeConfig) error{
+	"log_level": func(s *runtimeconfig.Settings, c *pb.RuntimeConfig) error {
+		return s.LogLevel.UnmarshalText([]byte(c.LogLevel))
+	},
+	"user_cache_ttl_seconds": func(s *runtimeconfig.Settings, c *pb.RuntimeConfig) error {
+		s.UserCacheTTL = time.Duration(c.UserCacheTtlSeconds) * time.Second
+		return nil
+	},
+	"list_cache_ttl_seconds": func(s *runtimeconfig.Settings, c *pb.RuntimeConfig) error {
+		s.ListCacheTTL = time.Duration(c.ListCacheTtlSeconds) * time.Second
+		return nil
+	},
+	"trace_sample_ratio": func(s *runtimeconfig.Settings, c *pb.RuntimeConfig) error {
+		s.SampleRatio = c.TraceSampleRatio
+		return nil
+	},
+	"rate_limit_qps": func(s *runtimeconfig.Settings, c *pb.RuntimeConfig) error {
+		s.RateLimitQPS = c.RateLimitQps
+		return nil
+	},
+	"rate_limit_burst": func(s *runtimeconfig.Settings, c *pb.RuntimeConfig) error {
+		s.RateLimitBurst = int(c.RateLimitBurst)
+		return nil
+	},
+	"read_only": func(s *runtimeconfig.Settings, c *pb.RuntimeConfig) error {
+		s.ReadOnly = c.ReadOnly
+		return nil
+	},
+	"race_user_reads": func(s *runtimeconfig.Settings, c *pb.RuntimeConfig) error {
+		s.RaceUserReads = c.RaceUserReads
+		return nil
+	},
+	"low_priority_reserve": func(s *runtimeconfig.Settings, c *pb.RuntimeConfig) error {
+		s.LowPriorityReserve = c.LowPriorityReserve
+		return nil
+	},
+	"caller_priorities": func(s *runtimeconfig.Settings, c *pb.RuntimeConfig) error {
+		classes := make(map[string]runtimeconfig.Class, len(c.CallerPriorities))
+		for _, p := range c.CallerPriorities {
+			if p.Caller == "" {
+				return errors.New("caller must not be empty")
+			}
+			class, ok := priorityClasses[p.Class]
+			if !ok {
+				return fmt.Errorf("unknown priority class %v for %s", p.Class, p.Caller)
+			}
+			classes[p.Caller] = class
+		}
+		s.CallerClasses = classes
+		return nil
+	},
+	"module_log_levels": func(s *runtimeconfig.Settings, c *pb.RuntimeConfig) error {
+		levels := make(map[string]slog.Level, len(c.ModuleLogLevels))
+		for _, l := range c.ModuleLogLevels {
+			var level slog.Level
+			if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+				return fmt.Errorf("invalid level for %s: %w", l.Module, err)
+			}
+			levels[l.Module] = level
+		}
+		s.ModuleLogLevels = levels
+		return nil
+	},
+}
+
+// priorityClasses maps the API's priority classes to runtimeconfig's.
+var priorityClasses = map[pb.PriorityClass]runtimeconfig.Class{
+	pb.PriorityClass_PRIORITY_CLASS_UNSPECIFIED: runtimeconfig.ClassInteractive,
+	pb.PriorityClass_PRIORITY_CLASS_INTERACTIVE: runtimeconfig.ClassInteractive,
+	pb.PriorityClass_PRIORITY_CLASS_BATCH:       runtimeconfig.ClassBatch,
+	pb.PriorityClass_PRIORITY_CLASS_LOAD_TEST:   runtimeconfig.ClassLoadTest,
+}
+
+func priorityClassToProto(class runtimeconfig.Class) pb.PriorityClass {
+	switch class {
+	case runtimeconfig.ClassBatch:
+		return pb.PriorityClass_PRIORITY_CLASS_BATCH
+	case runtimeconfig.ClassLoadTest:
+		return pb.PriorityClass_PRIORITY_CLASS_LOAD_TEST
+	default:
+		return pb.PriorityClass_PRIORITY_CLASS_INTERACTIVE
+	}
+}
+
+func runtimeConfigToProto(s *runtimeconfig.Settings) *pb.RuntimeConfig {
+	callers := slices.Sorted(maps.Keys(s.CallerClasses))
+	priorities := make([]*pb.CallerPriority, 0, len(callers))
+	for _, caller := range callers {
+		priorities = append(priorities, &pb.CallerPriority{
+			Caller: caller,
+			Class:  priorityClassToProto(s.CallerClasses[caller]),
+		})
+	}
+	modules := slices.Sorted(maps.Keys(s.ModuleLogLevels))
+	moduleLevels := make([]*pb.ModuleLogLevel, 0, len(modules))
+	for _, module := range modules {
+		moduleLevels = append(moduleLevels, &pb.ModuleLogLevel{
+			Module: module,
+			Level:  s.ModuleLogLevels[module].String(),
+		})
+	}
+	return &pb.RuntimeConfig{
+		LogLevel:            s.LogLevel.String(),
+		UserCacheTtlSeconds: int64(s.UserCacheTTL / time.Second),
+		ListCacheTtlSeconds: int64(s.ListCacheTTL / time.Second),
+		TraceSampleRatio:    s.SampleRatio,
+		RateLimitQps:        s.RateLimitQPS,
+		RateLimitBurst:      int32(s.RateLimitBurst),
+		ReadOnly:            s.ReadOnly,
+		LowPriorityReserve:  s.LowPriorityReserve,
+		CallerPriorities:    priorities,
+		ModuleLogLevels:     moduleLevels,
+		RaceUserReads:       s.RaceUserReads,
+	}
+}
+
+func (s *AdminServer) updatedAtUnixMs() int64 {
+	if updated := s.runtime.Updated(); !updated.IsZero() {
+		return updated.UnixMilli()
+	}
+	return 0
+}
+
+func (s *AdminServer) GetRuntimeConfig(ctx context.Context, req *pb.GetRuntimeConfigRequest) (*pb.GetRuntimeConfigResponse, error) {
+	logging.FromContext(ctx).DebugCtx(ctx, "GetRuntimeConfig request received")
+	return &pb.GetRuntimeConfigResponse{
+		Config:          runtimeConfigToProto(s.runtime.Load()),
+		InstanceId:      s.instanceID,
+		UpdatedAtUnixMs: s.updatedAtUnixMs(),
+	}, nil
+}
+
+func (s *AdminServer) SetRuntimeConfig(ctx context.Context, req *pb.SetRuntimeConfigRequest) (*pb.SetRuntimeConfigResponse, error) {
+	logging.FromContext(ctx).InfoCtx(ctx, "SetRuntimeConfig request received", "update_mask", req.UpdateMask)
+	if len(req.UpdateMask) == 0 {
+		return nil, status.Error(grpc_codes.InvalidArgument, "update_mask must name the fields to change")
+	}
+	config := req.Config
+	if config == nil {
+		config = &pb.RuntimeConfig{}
+	}
+
+	settings, err := s.runtime.Update(ctx, func(settings *runtimeconfig.Settings) error {
+		for _, field := range req.UpdateMask {
+			apply, ok := runtimeConfigFields[field]
+			if !ok {
+				return fmt.Errorf("%s is not a runtime config field", field)
+			}
+			if err := apply(settings, config); err != nil {
+				return fmt.Errorf("invalid %s: %w", field, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, status.Error(grpc_codes.InvalidArgument, err.Error())
+	}
+
+	return &pb.SetRuntimeConfigResponse{
+		Config:          runtimeConfigToProto(settings),
+		InstanceId:      s.instanceID,
+		UpdatedAtUnixMs: s.updatedAtUnixMs(),
+	}, nil
+}
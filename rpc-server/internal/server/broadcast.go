@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"grpc-server/internal/cache"
+	"grpc-server/internal/logging"
+)
+
+// InvalidationChannel is the pub/sub channel instances tell each other about
+// cache invalidations on. Valkey itself is shared, but each instance also
+// holds entries of its own: queued write-back list pages, hot keys served
+// from memory, and the in-memory fallback's entries while Valkey is
+// unreachable.
+const InvalidationChannel = "cache:invalidations"
+
+// InvalidationPublisher publishes pub/sub messages, e.g. *cache.ValkeyCache.
+type InvalidationPublisher interface {
+	Publish(ctx context.Context, channel string, message []byte) error
+}
+
+// LocalCache is the part of the cache held by this instance alone, e.g.
+// *cache.FallbackCache or *cache.HotKeyCache.
+type LocalCache interface {
+	InvalidateLocal(ctx context.Context, keys, patterns []string)
+	FlushLocal()
+}
+
+// invalidationMessage is what InvalidationChannel carries.
+type invalidationMessage struct {
+	Instance string   `json:"instance"`
+	Keys     []string `json:"keys,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// WithInvalidationBroadcast publishes every invalidation to the other
+// instances through publisher, and applies theirs, received through
+// InvalidationSubscription, to locals and the write-back queue.
+func WithInvalidationBroadcast(publisher InvalidationPublisher, locals ...LocalCache) Option {
+	return func(s *CachedUserServer) {
+		s.broadcast = publisher
+		s.localCaches = locals
+		s.instanceID = uuid.NewString()
+	}
+}
+
+// broadcastInvalidation tells the other instances keys and patterns changed.
+// A lost message only leaves local entries stale until they are next
+// written or the subscription is reestablished, so failures are logged.
+func (s *CachedUserServer) broadcastInvalidation(ctx context.Context, keys, patterns []string) {
+	if s.broadcast == nil {
+		return
+	}
+	data, err := json.Marshal(invalidationMessage{Instance: s.instanceID, Keys: keys, Patterns: patterns})
+	if err != nil {
+		return
+	}
+	if err := s.broadcast.Publish(ctx, InvalidationChannel, data); err != nil {
+		logging.FromContext(ctx).WarnCtx(ctx, "Failed to broadcast cache invalidation", logging.Error, err)
+	}
+}
+
+// InvalidationSubscription applies the invalidations other instances
+// broadcast on InvalidationChannel.
+func (s *CachedUserServer) InvalidationSubscription(base *slog.Logger) cache.Subscription {
+	logger := logging.New(logging.ForModule(base, logging.ModuleCache))
+	return cache.Subscription{
+		Handle: func(ctx context.Context, _ string, data []byte) {
+			var msg invalidationMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				logger.WarnCtx(ctx, "Ignoring malformed cache invalidation", logging.Error, err)
+				return
+			}
+			if msg.Instance == s.instanceID {
+				return
+			}
+			for _, local := range s.localCaches {
+				local.InvalidateLocal(ctx, msg.Keys, msg.Patterns)
+			}
+			for _, pattern := range msg.Patterns {
+				if s.listWriteBack != nil && strings.HasSuffix(pattern, userListCachePrefix+"*") {
+					s.listWriteBack.Discard()
+				}
+			}
+		},
+		// Invalidations broadcast while the subscription was down are lost.
+		Resubscribed: func(ctx context.Context) {
+			for _, local := range s.localCaches {
+				local.FlushLocal()
+			}
+			if s.listWriteBack != nil {
+				s.listWriteBack.Discard()
+			}
+			logger.InfoCtx(ctx, "Dropped local cache entries that missed invalidations while resubscribing")
+		},
+	}
+}
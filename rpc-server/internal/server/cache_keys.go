@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+
+	"grpc-server/internal/cache"
+	"grpc-server/internal/tenant"
+)
+
+// WithTenantCacheKeys namespaces every cache key by the tenant the call is
+// confined to, the one of the caller's verified token, so tenants never read
+// each other's entries and one tenant's writes only invalidate its own.
+// Calls without a tenant use the shared namespace. Background work that
+// changes a user invalidates with a context confined to the user's tenant.
+func WithTenantCacheKeys() Option {
+	return func(s *CachedUserServer) {
+		s.keys.Tenant = callerTenant
+	}
+}
+
+// WithCacheKeyHasher hashes the tenant and every ID, email or search term in
+// cache keys with hasher, such as cache.NewHMACHasher, so they don't show in
+// readable form in shared Valkey monitoring tools.
+func WithCacheKeyHasher(hasher cache.KeyHasher) Option {
+	return func(s *CachedUserServer) {
+		s.keys.Hasher = hasher
+	}
+}
+
+// CacheKeyPatterns match every key the server writes to the cache, apart
+// from short-lived deduplication markers, for deleting them all without
+// touching other data in a shared Valkey.
+func CacheKeyPatterns() []string {
+	return []string{userCachePrefix + "*", "users:*", cache.TenantKeyPattern}
+}
+
+// callerTenant returns the tenant ctx is confined to, or "". Unlike
+// x-tenant-id, the caller cannot choose it.
+func callerTenant(ctx context.Context) string {
+	id, _ := tenant.FromContext(ctx)
+	return id
+}
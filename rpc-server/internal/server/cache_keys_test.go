@@ -0,0 +1,32 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+
+	"grpc-server/internal/tenant"
+	"grpc-server/internal/tracing"
+)
+
+func TestCallerTenantComesFromTheTenantScope(t *testing.T) {
+	claimed := metadata.NewIncomingContext(context.Background(), metadata.Pairs(tracing.TenantMetadataKey, "other"))
+	for _, tt := range []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"no tenant", context.Background(), ""},
+		{"claimed tenant without token", claimed, ""},
+		{"token tenant", tenant.With(context.Background(), "acme"), "acme"},
+		{"claimed tenant ignored", tenant.With(claimed, "acme"), "acme"},
+		{"every tenant", tenant.All(context.Background()), ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := callerTenant(tt.ctx); got != tt.want {
+				t.Errorf("callerTenant() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"grpc-server/internal/cache"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/models"
+)
+
+// CacheStrategy is how an entity's cache entries follow writes to it.
+type CacheStrategy string
+
+const (
+	// StrategyWriteThrough sets the entry right after each database write.
+	// The default for users.
+	StrategyWriteThrough CacheStrategy = "write_through"
+	// StrategyCacheAside deletes the entry on writes and fills it on the
+	// next miss. The default for list pages.
+	StrategyCacheAside CacheStrategy = "cache_aside"
+	// StrategyWriteBack fills entries from a queue flushed in the background
+	// instead of on the request path. Only for derived data, where a lost or
+	// late entry costs no more than a cache miss.
+	StrategyWriteBack CacheStrategy = "write_back"
+)
+
+// Cached entities, each with its own strategy.
+const (
+	EntityUser = "user"
+	EntityList = "list"
+)
+
+// entityStrategies lists the strategies each entity supports. List pages
+// can't be rebuilt on writes, and users are not derived data.
+var entityStrategies = map[string][]CacheStrategy{
+	EntityUser: {StrategyWriteThrough, StrategyCacheAside},
+	EntityList: {StrategyCacheAside, StrategyWriteBack},
+}
+
+// ParseCacheStrategy validates a configured strategy for entity.
+func ParseCacheStrategy(entity, s string) (CacheStrategy, error) {
+	for _, strategy := range entityStrategies[entity] {
+		if CacheStrategy(s) == strategy {
+			return strategy, nil
+		}
+	}
+	return "", fmt.Errorf("cache strategy %q is not supported for %s entries; want one of %v", s, entity, entityStrategies[entity])
+}
+
+// WithUserCacheStrategy sets how user entries follow writes. The default is
+// StrategyWriteThrough.
+func WithUserCacheStrategy(strategy CacheStrategy) Option {
+	return func(s *CachedUserServer) {
+		s.userStrategy = strategy
+	}
+}
+
+// WithListWriteBack fills list pages through queue rather than on the
+// request path. Without it list pages are cache-aside.
+func WithListWriteBack(queue *WriteBackQueue) Option {
+	return func(s *CachedUserServer) {
+		s.listWriteBack = queue
+	}
+}
+
+// recordStrategy adds the strategy used for entity to the current span.
+func recordStrategy(ctx context.Context, entity string, strategy CacheStrategy) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("cache."+entity+".strategy", string(strategy)))
+}
+
+// userWritten brings the cache in line with a user just written to the
+// database.
+func (s *CachedUserServer) userWritten(ctx context.Context, user *models.User) {
+	recordStrategy(ctx, EntityUser, s.userStrategy)
+	if s.userStrategy == StrategyCacheAside {
+		if err := s.invalidateUsers(ctx, user.ID); err != nil {
+			logging.FromContext(ctx).WarnCtx(ctx, "Failed to invalidate cached user", logging.UserID, user.ID, logging.Error, err)
+		}
+		return
+	}
+	if err := s.cacheUser(ctx, user); err != nil {
+		logging.FromContext(ctx).WarnCtx(ctx, "Failed to update cache", logging.UserID, user.ID, logging.Error, err)
+	}
+	s.broadcastInvalidation(ctx, []string{s.userCacheKey(ctx, user.ID)}, nil)
+}
+
+// cacheListPage stores an encoded list page, directly or through the
+// write-back queue.
+func (s *CachedUserServer) cacheListPage(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	if s.listWriteBack == nil {
+		recordStrategy(ctx, EntityList, StrategyCacheAside)
+		return s.cache.Set(ctx, key, data, ttl)
+	}
+	recordStrategy(ctx, EntityList, StrategyWriteBack)
+	if !s.listWriteBack.Enqueue(key, data, ttl) {
+		logging.FromContext(ctx).DebugCtx(ctx, "Write-back queue full, not caching user list", logging.CacheKey, key)
+	}
+	return nil
+}
+
+// WriteBackConfig configures a WriteBackQueue.
+type WriteBackConfig struct {
+	FlushInterval time.Duration
+	// MaxPending bounds the queued entries; more are dropped.
+	MaxPending int
+}
+
+// WriteBackQueue collects cache entries and writes them in the background
+// every FlushInterval. A key queued again before a flush keeps only its
+// latest value.
+type WriteBackQueue struct {
+	cache  cache.Cache
+	cfg    WriteBackConfig
+	logger *logging.Logger
+
+	mu      sync.Mutex
+	pending map[string]pendingEntry
+}
+
+type pendingEntry struct {
+	value []byte
+	ttl   time.Duration
+}
+
+func NewWriteBackQueue(c cache.Cache, cfg WriteBackConfig, base *slog.Logger) *WriteBackQueue {
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 100 * time.Millisecond
+	}
+	return &WriteBackQueue{
+		cache:   c,
+		cfg:     cfg,
+		logger:  logging.New(base.With("component", "cache_write_back")),
+		pending: make(map[string]pendingEntry),
+	}
+}
+
+// Enqueue queues value for key and reports whether there was room for it.
+func (q *WriteBackQueue) Enqueue(key string, value []byte, ttl time.Duration) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.pending[key]; !ok && q.cfg.MaxPending > 0 && len(q.pending) >= q.cfg.MaxPending {
+		return false
+	}
+	q.pending[key] = pendingEntry{value: value, ttl: ttl}
+	return true
+}
+
+// Discard drops every queued entry, so an invalidation isn't undone by a
+// later flush of entries read before it.
+func (q *WriteBackQueue) Discard() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	clear(q.pending)
+}
+
+// Run flushes the queue every FlushInterval until ctx is done. Entries still
+// queued then are dropped.
+func (q *WriteBackQueue) Run(ctx context.Context) {
+	ticker := time.NewTicker(q.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.flush(ctx)
+		}
+	}
+}
+
+func (q *WriteBackQueue) flush(ctx context.Context) {
+	q.mu.Lock()
+	batch := q.pending
+	q.pending = make(map[string]pendingEntry, len(batch))
+	q.mu.Unlock()
+
+	for key, entry := range batch {
+		if err := q.cache.Set(ctx, key, entry.value, entry.ttl); err != nil {
+			q.logger.WarnCtx(ctx, "Failed to flush cache entry", logging.CacheKey, key, logging.Error, err)
+		}
+	}
+	if len(batch) > 0 {
+		q.logger.DebugCtx(ctx, "Flushed write-back queue", "entries", len(batch))
+	}
+}
@@ -3,290 +3,599 @@ package server
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"log/slog"
+	"strconv"
 	"time"
 
-	"go.opentelemetry.io/otel/trace"
-
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	grpc_codes "google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
 
+	"grpc-server/internal/audit"
 	"grpc-server/internal/cache"
+	"grpc-server/internal/clock"
+	"grpc-server/internal/dedup"
+	"grpc-server/internal/events"
+	"grpc-server/internal/export"
 	"grpc-server/internal/logging"
 	"grpc-server/internal/models"
 	"grpc-server/internal/repository"
+	"grpc-server/internal/runtimeconfig"
+	"grpc-server/internal/timing"
 	pb "grpc-server/pkg/pb"
 )
 
 type CachedUserServer struct {
 	pb.UnimplementedUserServiceServer
-	repo   repository.UserRepository
-	cache  cache.Cache
-	logger *logging.Logger
+	repo  repository.UserRepository
+	cache cache.Cache
+	// keys builds every cache key; see WithTenantCacheKeys.
+	keys   cache.Keys
 	tracer trace.Tracer
+	audit  *audit.Logger
+	events events.Publisher
+	signer *export.Signer
+	// emailChangeTTL is how long an email change confirmation token is valid.
+	emailChangeTTL time.Duration
+	// slidingTTL, when set, is what a user entry's TTL is reset to on every
+	// hit, up to maxCacheLifetime after the entry was written.
+	slidingTTL       time.Duration
+	maxCacheLifetime time.Duration
+	// runtime, when set, supplies cache TTLs that may change while running.
+	runtime *runtimeconfig.Store
+	// dbHealth, when set, enables degraded reads; see WithDegradedReads.
+	dbHealth     DatabaseHealth
+	invalidation invalidationMetrics
+	readRaces    metric.Int64Counter
+	clock        clock.Clock
+	// reads shares database reads between concurrent cache misses of a
+	// key; see sharedRead.
+	reads          singleflight.Group
+	collapsedReads metric.Int64Counter
+	// defaultPageSize and maxPageSize bound ListUsers pages; see
+	// WithPageSizes.
+	defaultPageSize int32
+	maxPageSize     int32
+	// userStrategy and listWriteBack select how entries follow writes; see
+	// CacheStrategy.
+	userStrategy  CacheStrategy
+	listWriteBack *WriteBackQueue
+	// updateDedup, when set, collapses identical concurrent UpdateUser calls.
+	updateDedup *dedup.Deduplicator
+	// broadcast, when set, shares invalidations with the other instances;
+	// see WithInvalidationBroadcast.
+	broadcast   InvalidationPublisher
+	localCaches []LocalCache
+	instanceID  string
+	// omitMessages leaves response message fields empty; see
+	// WithoutResponseMessages.
+	omitMessages bool
+	// negativeTTL, when positive, is how long a user ID not found is
+	// remembered; see WithNegativeCaching.
+	negativeTTL time.Duration
+}
+
+// Option configures optional CachedUserServer dependencies.
+type Option func(*CachedUserServer)
+
+// WithExportSigner enables ExportUserData, signing documents with signer.
+func WithExportSigner(signer *export.Signer) Option {
+	return func(s *CachedUserServer) {
+		s.signer = signer
+	}
+}
+
+// WithEventPublisher sets where domain events are published. The default
+// writes them to the application log.
+func WithEventPublisher(publisher events.Publisher) Option {
+	return func(s *CachedUserServer) {
+		s.events = publisher
+	}
+}
+
+// WithAuditLogger sets where audit records are written. The default writes
+// them to the application log.
+func WithAuditLogger(logger *audit.Logger) Option {
+	return func(s *CachedUserServer) {
+		s.audit = logger
+	}
+}
+
+// WithEmailChangeTTL sets how long email change confirmation tokens stay
+// valid. The default is defaultEmailChangeTTL.
+func WithEmailChangeTTL(ttl time.Duration) Option {
+	return func(s *CachedUserServer) {
+		s.emailChangeTTL = ttl
+	}
+}
+
+// WithSlidingExpiration refreshes the TTL of user entries to ttl whenever they
+// are read, so hot users stay cached while cold ones age out. An entry is
+// never kept longer than maxLifetime after it was written, bounding how stale
+// a missed invalidation can leave it.
+func WithSlidingExpiration(ttl, maxLifetime time.Duration) Option {
+	return func(s *CachedUserServer) {
+		s.slidingTTL = ttl
+		s.maxCacheLifetime = maxLifetime
+	}
+}
+
+// WithClock sets the clock timestamps and cache lifetimes are based on. The
+// default is clock.System.
+func WithClock(clk clock.Clock) Option {
+	return func(s *CachedUserServer) {
+		s.clock = clk
+	}
+}
+
+// WithRuntimeConfig reads cache TTLs from store on every write instead of
+// using defaultCacheTTL.
+func WithRuntimeConfig(store *runtimeconfig.Store) Option {
+	return func(s *CachedUserServer) {
+		s.runtime = store
+	}
 }
 
-func NewCachedUserServer(repo repository.UserRepository, cache cache.Cache, logger *slog.Logger) *CachedUserServer {
-	return &CachedUserServer{
+func NewCachedUserServer(repo repository.UserRepository, cache cache.Cache, logger *slog.Logger, opts ...Option) *CachedUserServer {
+	s := &CachedUserServer{
 		repo:   repo,
 		cache:  cache,
-		logger: logging.New(logger),
 		tracer: otel.Tracer("rpc-server.rpc/server"),
+		audit:  audit.New(logger),
+		events: events.NewTracingPublisher(events.NewLogPublisher(logger)),
+
+		emailChangeTTL: defaultEmailChangeTTL,
+		invalidation:   newInvalidationMetrics(),
+		readRaces:      newReadRaceCounter(),
+		collapsedReads: newCollapsedReadCounter(),
+		clock:          clock.System,
+
+		defaultPageSize: defaultPageSize,
+		maxPageSize:     defaultMaxPageSize,
+		userStrategy:    StrategyWriteThrough,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 const (
 	userCachePrefix     = "user:"
-	userListCachePrefix = "users:list:"
+	userListCachePrefix = "users:list:ids:" // pages of IDs; see cachedListPage
 	defaultCacheTTL     = 15 * time.Minute
+
+	defaultEmailChangeTTL = time.Hour
 )
 
-func (s *CachedUserServer) userCacheKey(id string) string {
-	return userCachePrefix + id
+// cachedUser is what user entries hold; CachedAt (unix milliseconds) bounds
+// sliding expiration and is zero for entries written before it existed.
+type cachedUser struct {
+	models.User
+	CachedAt int64
 }
 
-func (s *CachedUserServer) userListCacheKey(offset, limit int) string {
-	return fmt.Sprintf("%s%d:%d", userListCachePrefix, offset, limit)
+func (s *CachedUserServer) userCacheTTL() time.Duration {
+	if s.runtime == nil {
+		return defaultCacheTTL
+	}
+	return s.runtime.Load().UserCacheTTL
+}
+
+func (s *CachedUserServer) listCacheTTL() time.Duration {
+	if s.runtime == nil {
+		return defaultCacheTTL
+	}
+	return s.runtime.Load().ListCacheTTL
+}
+
+// userCacheKey hashes IDs that are not plain identifiers, since req.Id is
+// client input and reaches the cache before the repository validates it.
+func (s *CachedUserServer) userCacheKey(ctx context.Context, id string) string {
+	return s.keys.Key(ctx, userCachePrefix, id)
+}
+
+func (s *CachedUserServer) userListCacheKey(ctx context.Context, offset, limit int) string {
+	return s.keys.Key(ctx, userListCachePrefix, strconv.Itoa(offset), strconv.Itoa(limit))
 }
 
 func (s *CachedUserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
-	user := models.NewUser(uuid.New().String(), req.Name, req.Email, req.Age)
-	s.logger.DebugCtx(ctx, "Created domain user model", logging.UserID, user.ID, logging.UserEmail, user.Email)
+	// Clients may bring their own ID when importing users from elsewhere
+	id := uuid.New().String()
+	if req.Id != "" {
+		parsed, err := uuid.Parse(req.Id)
+		if err != nil {
+			return nil, invalidUserIDError(req.Id)
+		}
+		id = parsed.String()
+	}
+	user := models.NewUser(s.clock, id, req.Name, req.Email, req.Age)
+	logging.FromContext(ctx).DebugCtx(ctx, "Created domain user model", logging.UserID, user.ID, logging.UserEmail, user.Email)
 
 	if err := s.repo.Create(ctx, user); err != nil {
 		if err == repository.ErrEmailExists {
-			s.logger.WarnCtx(ctx, "CreateUser email already exists", logging.UserEmail, req.Email)
-			return nil, status.Errorf(grpc_codes.AlreadyExists, "user with email %s already exists", req.Email)
+			logging.FromContext(ctx).WarnCtx(ctx, "CreateUser email already exists", logging.UserEmail, req.Email)
+			return nil, emailExistsError(req.Email)
+		}
+		if err == repository.ErrUserExists {
+			logging.FromContext(ctx).WarnCtx(ctx, "CreateUser ID already exists", logging.UserID, user.ID)
+			return nil, userExistsError(user.ID)
 		}
-		s.logger.ErrorCtx(ctx, "Failed to create user in repository", logging.Error, err, logging.UserEmail, req.Email)
-		return nil, status.Errorf(grpc_codes.Internal, "failed to create user")
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to create user in repository", logging.Error, err, logging.UserEmail, req.Email)
+		return nil, repositoryError(err, "create_user", user.ID, "failed to create user")
 	}
 
-	if err := s.cacheUser(ctx, user); err != nil {
-		s.logger.WarnCtx(ctx, "Failed to cache new user", logging.UserID, user.ID, logging.Error, err)
-	}
+	s.userWritten(ctx, user)
+	s.forgetMissingUser(ctx, user.ID)
 
 	// Invalidate list cache
 	s.invalidateListCache(ctx)
 
 	return &pb.CreateUserResponse{
 		User:    user.ToProto(),
-		Message: "User created successfully",
+		Message: s.message("User created successfully"),
+		Outcome: pb.Outcome_OUTCOME_CREATED,
 	}, nil
 }
 
 func (s *CachedUserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
-	s.logger.DebugCtx(ctx, "GetUser request received", logging.UserID, req.Id)
+	logging.FromContext(ctx).DebugCtx(ctx, "GetUser request received", logging.UserID, req.Id)
 
-	// Try cache first
-	cacheKey := s.userCacheKey(req.Id)
-	s.logger.DebugCtx(ctx, "Attempting cache lookup", logging.UserID, req.Id, logging.CacheKey, cacheKey)
-	cachedData, err := s.cache.Get(ctx, cacheKey)
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	mask, err := readMask(req.ReadMask)
+	stopValidation()
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := s.userCacheKey(ctx, req.Id)
+	if s.raceUserReads(strongRead(req.ReadConsistency)) {
+		user, err := s.raceUserRead(ctx, req.Id, cacheKey)
+		if err != nil {
+			return nil, err
+		}
+		return s.userResponse(ctx, user, mask), nil
+	}
+
+	// Try cache first, unless the caller needs a strong read
+	var cachedData []byte
+	if strongRead(req.ReadConsistency) {
+		err = cache.ErrCacheMiss
+	} else {
+		logging.FromContext(ctx).DebugCtx(ctx, "Attempting cache lookup", logging.UserID, req.Id, logging.CacheKey, cacheKey)
+		cachedData, err = s.cache.Get(ctx, cacheKey)
+	}
 	if err == nil {
-		var user models.User
-		if err := json.Unmarshal(cachedData, &user); err == nil {
-			s.logger.DebugCtx(ctx, "Cache hit for user", logging.UserID, req.Id)
-			return &pb.GetUserResponse{
-				User:    user.ToProto(),
-				Message: "User retrieved successfully",
-			}, nil
+		var entry cachedUser
+		stopSerialization := timing.Track(ctx, timing.StageSerialization)
+		err := json.Unmarshal(cachedData, &entry)
+		stopSerialization()
+		if err == nil {
+			logging.FromContext(ctx).DebugCtx(ctx, "Cache hit for user", logging.UserID, req.Id)
+			s.refreshUserTTL(ctx, cacheKey, entry.CachedAt)
+			if checkETag(ctx, userETag(&entry.User)) {
+				return s.notModifiedResponse(ctx, s.degraded()), nil
+			}
+			stopSerialization = timing.Track(ctx, timing.StageSerialization)
+			response := &pb.GetUserResponse{
+				User:    entry.User.ToProto(),
+				Message: s.message("User retrieved successfully"),
+				Outcome: pb.Outcome_OUTCOME_RETRIEVED,
+			}
+			mask.apply(response.User)
+			stopSerialization()
+			if s.degraded() {
+				markStale(ctx)
+				response.Stale = true
+			}
+			return response, nil
 		}
-		s.logger.WarnCtx(ctx, "Failed to unmarshal cached user", logging.UserID, req.Id, logging.Error, err)
+		logging.FromContext(ctx).WarnCtx(ctx, "Failed to unmarshal cached user", logging.UserID, req.Id, logging.Error, err)
 	} else if err != cache.ErrCacheMiss {
-		s.logger.WarnCtx(ctx, "Cache get failed", logging.UserID, req.Id, logging.Error, err)
+		logging.FromContext(ctx).WarnCtx(ctx, "Cache get failed", logging.UserID, req.Id, logging.Error, err)
+	}
+	if !strongRead(req.ReadConsistency) && s.userKnownMissing(ctx, req.Id) {
+		return nil, userNotFoundError(req.Id)
+	}
+	if s.degraded() {
+		logging.FromContext(ctx).WarnCtx(ctx, "Database unavailable, cannot serve user from cache", logging.UserID, req.Id)
+		return nil, databaseUnavailableError("get_user")
 	}
 
-	// Cache miss - get from database
-	s.logger.DebugCtx(ctx, "Cache miss, fetching from database", logging.UserID, req.Id)
-	user, err := s.repo.GetByID(ctx, req.Id)
+	// Cache miss - get from database, once for concurrent misses unless the
+	// caller needs a read started after its call
+	logging.FromContext(ctx).DebugCtx(ctx, "Cache miss, fetching from database", logging.UserID, req.Id)
+	var user *models.User
+	if strongRead(req.ReadConsistency) {
+		user, err = s.loadUser(ctx, req.Id)
+	} else {
+		var loaded any
+		loaded, err = s.sharedRead(ctx, "get_user", cacheKey, func(ctx context.Context) (any, error) {
+			return s.loadUser(ctx, req.Id)
+		})
+		user, _ = loaded.(*models.User)
+	}
 	if err != nil {
 		if err == repository.ErrUserNotFound {
-			s.logger.InfoCtx(ctx, "User not found", logging.UserID, req.Id)
-			return nil, status.Errorf(grpc_codes.NotFound, "user with ID %s not found", req.Id)
+			logging.FromContext(ctx).InfoCtx(ctx, "User not found", logging.UserID, req.Id)
+			return nil, userNotFoundError(req.Id)
 		}
-		s.logger.ErrorCtx(ctx, "Failed to get user from repository", logging.UserID, req.Id, logging.Error, err)
-		return nil, status.Errorf(grpc_codes.Internal, "failed to retrieve user")
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to get user from repository", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "get_user", req.Id, "failed to retrieve user")
 	}
 
-	// Cache the user
+	logging.FromContext(ctx).DebugCtx(ctx, "User retrieved successfully", logging.UserID, user.ID, logging.UserEmail, user.Email)
+	return s.userResponse(ctx, user, mask), nil
+}
+
+// loadUser reads a user from the database and caches it, or remembers it
+// as missing.
+func (s *CachedUserServer) loadUser(ctx context.Context, id string) (*models.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err == repository.ErrUserNotFound {
+		s.rememberMissingUser(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
 	if err := s.cacheUser(ctx, user); err != nil {
-		s.logger.WarnCtx(ctx, "Failed to cache user", logging.UserID, req.Id, logging.Error, err)
+		logging.FromContext(ctx).WarnCtx(ctx, "Failed to cache user", logging.UserID, id, logging.Error, err)
 	}
+	return user, nil
+}
 
-	s.logger.DebugCtx(ctx, "User retrieved successfully", logging.UserID, user.ID, logging.UserEmail, user.Email)
-	return &pb.GetUserResponse{
+// userResponse answers GetUser with a user read past the cache fast path,
+// or not_modified if the caller holds its current ETag.
+func (s *CachedUserServer) userResponse(ctx context.Context, user *models.User, mask userMask) *pb.GetUserResponse {
+	if checkETag(ctx, userETag(user)) {
+		return s.notModifiedResponse(ctx, false)
+	}
+	defer timing.Track(ctx, timing.StageSerialization)()
+	response := &pb.GetUserResponse{
 		User:    user.ToProto(),
-		Message: "User retrieved successfully",
-	}, nil
+		Message: s.message("User retrieved successfully"),
+		Outcome: pb.Outcome_OUTCOME_RETRIEVED,
+	}
+	mask.apply(response.User)
+	return response
 }
 
-func (s *CachedUserServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
-	s.logger.DebugCtx(ctx, "UpdateUser request received", logging.UserID, req.Id, "name", req.Name, logging.UserEmail, req.Email, "age", req.Age)
+func (s *CachedUserServer) updateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	logging.FromContext(ctx).DebugCtx(ctx, "UpdateUser request received", logging.UserID, req.Id, "name", req.Name, logging.UserEmail, req.Email, "age", req.Age)
 
 	// Get existing user from database (not cache) to ensure consistency
-	s.logger.DebugCtx(ctx, "Fetching existing user from database", logging.UserID, req.Id)
+	logging.FromContext(ctx).DebugCtx(ctx, "Fetching existing user from database", logging.UserID, req.Id)
 	user, err := s.repo.GetByID(ctx, req.Id)
 	if err != nil {
 		if err == repository.ErrUserNotFound {
-			s.logger.InfoCtx(ctx, "User not found for update", logging.UserID, req.Id)
-			return nil, status.Errorf(grpc_codes.NotFound, "user with ID %s not found", req.Id)
+			logging.FromContext(ctx).InfoCtx(ctx, "User not found for update", logging.UserID, req.Id)
+			return nil, userNotFoundError(req.Id)
 		}
-		s.logger.ErrorCtx(ctx, "Failed to get user for update from repository", logging.UserID, req.Id, logging.Error, err)
-		return nil, status.Errorf(grpc_codes.Internal, "failed to retrieve user")
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to get user for update from repository", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "update_user", req.Id, "failed to retrieve user")
 	}
 
-	// Check email uniqueness if email is being updated
+	// Email is the primary identifier and only changes through the
+	// confirmation workflow
 	if req.Email != "" && req.Email != user.Email {
-		s.logger.DebugCtx(ctx, "Checking email uniqueness", "new_email", req.Email, logging.UserID, req.Id)
-		exists, err := s.repo.EmailExists(ctx, req.Email, req.Id)
-		if err != nil {
-			s.logger.ErrorCtx(ctx, "Failed to check email existence", logging.UserEmail, req.Email, logging.Error, err)
-			return nil, status.Errorf(grpc_codes.Internal, "failed to validate email")
-		}
-		if exists {
-			s.logger.WarnCtx(ctx, "Email already exists for different user", logging.UserEmail, req.Email, logging.UserID, req.Id)
-			return nil, status.Errorf(grpc_codes.AlreadyExists, "user with email %s already exists", req.Email)
-		}
+		logging.FromContext(ctx).InfoCtx(ctx, "Rejected email change through UpdateUser", logging.UserID, req.Id)
+		return nil, emailChangeRequiredError(req.Id)
 	}
 
 	// Update user
-	oldEmail := user.Email
-	user.Update(req.Name, req.Email, req.Age)
-	s.logger.DebugCtx(ctx, "User model updated", logging.UserID, user.ID, "old_email", oldEmail, "new_email", user.Email)
+	user.Update(s.clock, req.Name, "", req.Age)
+	logging.FromContext(ctx).DebugCtx(ctx, "User model updated", logging.UserID, user.ID)
 
 	// Save updated user
 	if err := s.repo.Update(ctx, user); err != nil {
-		s.logger.ErrorCtx(ctx, "Failed to update user in repository", logging.UserID, req.Id, logging.Error, err)
-		return nil, status.Errorf(grpc_codes.Internal, "failed to update user")
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to update user in repository", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "update_user", req.Id, "failed to update user")
 	}
 
-	// Update cache
-	if err := s.cacheUser(ctx, user); err != nil {
-		s.logger.WarnCtx(ctx, "Failed to update cache", logging.UserID, req.Id, logging.Error, err)
-	}
-
-	// Invalidate list cache
-	s.invalidateListCache(ctx)
+	// Update cache; list pages only hold IDs, so only searches can be stale
+	s.userWritten(ctx, user)
+	s.invalidateSearchCache(ctx)
 
-	s.logger.InfoCtx(ctx, "User updated successfully", logging.UserID, user.ID, logging.UserEmail, user.Email)
+	logging.FromContext(ctx).InfoCtx(ctx, "User updated successfully", logging.UserID, user.ID, logging.UserEmail, user.Email)
 
 	return &pb.UpdateUserResponse{
 		User:    user.ToProto(),
-		Message: "User updated successfully",
+		Message: s.message("User updated successfully"),
+		Outcome: pb.Outcome_OUTCOME_UPDATED,
 	}, nil
 }
 
 func (s *CachedUserServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
-	s.logger.DebugCtx(ctx, "DeleteUser request received", logging.UserID, req.Id)
+	logging.FromContext(ctx).DebugCtx(ctx, "DeleteUser request received", logging.UserID, req.Id)
 
 	if err := s.repo.Delete(ctx, req.Id); err != nil {
 		if err == repository.ErrUserNotFound {
-			s.logger.InfoCtx(ctx, "User not found for deletion", logging.UserID, req.Id)
-			return nil, status.Errorf(grpc_codes.NotFound, "user with ID %s not found", req.Id)
+			logging.FromContext(ctx).InfoCtx(ctx, "User not found for deletion", logging.UserID, req.Id)
+			return nil, userNotFoundError(req.Id)
 		}
-		s.logger.ErrorCtx(ctx, "Failed to delete user from repository", logging.UserID, req.Id, logging.Error, err)
-		return nil, status.Errorf(grpc_codes.Internal, "failed to delete user")
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to delete user from repository", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "delete_user", req.Id, "failed to delete user")
 	}
 
 	// Remove from cache
-	cacheKey := s.userCacheKey(req.Id)
-	s.logger.DebugCtx(ctx, "Removing user from cache", logging.UserID, req.Id, logging.CacheKey, cacheKey)
-	if err := s.cache.Delete(ctx, cacheKey); err != nil {
-		s.logger.WarnCtx(ctx, "Failed to delete user from cache", logging.UserID, req.Id, logging.Error, err)
+	logging.FromContext(ctx).DebugCtx(ctx, "Removing user from cache", logging.UserID, req.Id, logging.CacheKey, s.userCacheKey(ctx, req.Id))
+	if err := s.invalidateUsers(ctx, req.Id); err != nil {
+		logging.FromContext(ctx).WarnCtx(ctx, "Failed to delete user from cache", logging.UserID, req.Id, logging.Error, err)
 	}
 
 	// Invalidate list cache
 	s.invalidateListCache(ctx)
 
-	s.logger.InfoCtx(ctx, "User deleted successfully", logging.UserID, req.Id)
+	logging.FromContext(ctx).InfoCtx(ctx, "User deleted successfully", logging.UserID, req.Id)
 
 	return &pb.DeleteUserResponse{
-		Message: "User deleted successfully",
+		Message: s.message("User deleted successfully"),
+		Outcome: pb.Outcome_OUTCOME_DELETED,
 	}, nil
 }
 
 func (s *CachedUserServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
-	s.logger.DebugCtx(ctx, "ListUsers request received", "page", req.Page, "limit", req.Limit)
+	logging.FromContext(ctx).DebugCtx(ctx, "ListUsers request received", "page", req.Page, "limit", req.Limit, "name_prefix", req.NamePrefix, "email_prefix", req.EmailPrefix)
 
 	// Validate and normalize pagination parameters
-	page := max(req.Page, 1)
-	limit := min(max(req.Limit, 1), 100) // Between 1 and 100
-	offset := (page - 1) * limit
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	page, limit, offset, err := s.pagination(req)
+	var filter repository.PrefixFilter
+	var mask userMask
+	if err == nil {
+		filter, err = prefixFilter(req)
+	}
+	if err == nil {
+		mask, err = readMask(req.ReadMask)
+	}
+	stopValidation()
+	if err != nil {
+		return nil, err
+	}
+	search := filter != repository.PrefixFilter{}
 
-	s.logger.DebugCtx(ctx, "Normalized pagination parameters", "page", page, "limit", limit, "offset", offset)
+	logging.FromContext(ctx).DebugCtx(ctx, "Normalized pagination parameters", "page", page, "limit", limit, "offset", offset)
 
-	// Try cache first
-	cacheKey := s.userListCacheKey(int(offset), int(limit))
-	s.logger.DebugCtx(ctx, "Attempting cache lookup for user list", logging.CacheKey, cacheKey)
-	cachedData, err := s.cache.Get(ctx, cacheKey)
-	if err == nil {
-		var response pb.ListUsersResponse
-		if err := json.Unmarshal(cachedData, &response); err == nil {
-			s.logger.DebugCtx(ctx, "Cache hit for user list", "offset", offset, "limit", limit, "total", response.Total)
-			return &response, nil
+	// Try cache first; searches are cached under filter-aware keys
+	cacheKey := s.userListCacheKey(ctx, int(offset), int(limit))
+	if search {
+		cacheKey = s.searchCacheKey(ctx, filter, int(offset), int(limit))
+	}
+	if cacheKey != "" && !strongRead(req.ReadConsistency) {
+		logging.FromContext(ctx).DebugCtx(ctx, "Attempting cache lookup for user list", logging.CacheKey, cacheKey)
+		if users, total, ok := s.listPageFromCache(ctx, cacheKey); ok {
+			logging.FromContext(ctx).DebugCtx(ctx, "Cache hit for user list", "offset", offset, "limit", limit, "total", total)
+			response := s.listResponse(ctx, users, total, page, offset, limit)
+			if s.degraded() {
+				markStale(ctx)
+				response.Stale = true
+			}
+			for _, user := range response.Users {
+				mask.apply(user)
+			}
+			return response, nil
 		}
-		s.logger.WarnCtx(ctx, "Failed to unmarshal cached user list", logging.Error, err)
-	} else if err != cache.ErrCacheMiss {
-		s.logger.WarnCtx(ctx, "Cache get failed for user list", logging.Error, err)
+	}
+	if s.degraded() {
+		logging.FromContext(ctx).WarnCtx(ctx, "Database unavailable, cannot serve user list from cache", "offset", offset, "limit", limit)
+		return nil, databaseUnavailableError("list_users")
 	}
 
-	// Cache miss - get from database
-	s.logger.DebugCtx(ctx, "Cache miss, fetching user list from database", "offset", offset, "limit", limit)
-	users, total, err := s.repo.List(ctx, int(offset), int(limit))
+	// Cache miss - get from database, once for concurrent misses of this
+	// page unless the caller needs a read started after its call
+	logging.FromContext(ctx).DebugCtx(ctx, "Cache miss, fetching user list from database", "offset", offset, "limit", limit)
+	load := func(ctx context.Context) (any, error) {
+		return s.loadListPage(ctx, cacheKey, search, filter, int(offset), int(limit))
+	}
+	var loaded any
+	if cacheKey == "" || strongRead(req.ReadConsistency) {
+		loaded, err = load(ctx)
+	} else {
+		loaded, err = s.sharedRead(ctx, "list_users", cacheKey, load)
+	}
 	if err != nil {
-		s.logger.ErrorCtx(ctx, "Failed to list users from repository", logging.Error, err)
-		return nil, status.Errorf(grpc_codes.Internal, "failed to retrieve users")
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to list users from repository", logging.Error, err)
+		return nil, repositoryError(err, "list_users", "", "failed to retrieve users")
 	}
+	users, total := loaded.(loadedListPage).users, loaded.(loadedListPage).total
 
-	// Convert to protobuf messages
-	pbUsers := make([]*pb.User, len(users))
-	for i, user := range users {
-		pbUsers[i] = user.ToProto()
+	response := s.listResponse(ctx, users, total, page, offset, limit)
+	for _, user := range response.Users {
+		mask.apply(user)
 	}
 
-	response := &pb.ListUsersResponse{
-		Users:   pbUsers,
-		Total:   int32(total),
-		Message: fmt.Sprintf("Retrieved %d users (page %d)", len(pbUsers), page),
+	logging.FromContext(ctx).DebugCtx(ctx, "User list retrieved successfully", "total_count", total, "returned_count", len(users), "page", page)
+	return response, nil
+}
+
+// loadedListPage is a ListUsers page read from the database.
+type loadedListPage struct {
+	users []*models.User
+	total int
+}
+
+// loadListPage reads a ListUsers page from the database and caches its IDs
+// under cacheKey, unless cacheKey is empty. Its users are cached on the first
+// hit that misses them, so a list miss costs a single cache write.
+func (s *CachedUserServer) loadListPage(ctx context.Context, cacheKey string, search bool, filter repository.PrefixFilter, offset, limit int) (loadedListPage, error) {
+	var users []*models.User
+	var total int
+	var err error
+	if search {
+		users, total, err = s.repo.SearchByPrefix(ctx, filter, offset, limit)
+	} else {
+		users, total, err = s.repo.List(ctx, offset, limit)
+	}
+	if err != nil {
+		return loadedListPage{}, err
 	}
 
-	// Cache the response
-	if responseData, err := json.Marshal(response); err == nil {
-		if err := s.cache.Set(ctx, cacheKey, responseData, defaultCacheTTL); err != nil {
-			s.logger.WarnCtx(ctx, "Failed to cache user list", logging.Error, err)
+	if cacheKey == "" {
+		logging.FromContext(ctx).DebugCtx(ctx, "Search generation unavailable, not caching user list")
+	} else if pageData, err := marshalListPage(ctx, users, total); err == nil {
+		ttl := s.listCacheTTL()
+		if err := s.cacheListPage(ctx, cacheKey, pageData, ttl); err != nil {
+			logging.FromContext(ctx).WarnCtx(ctx, "Failed to cache user list", logging.Error, err)
 		} else {
-			s.logger.DebugCtx(ctx, "Cached user list", logging.CacheKey, cacheKey, "ttl", defaultCacheTTL)
+			logging.FromContext(ctx).DebugCtx(ctx, "Cached user list", logging.CacheKey, cacheKey, "ttl", ttl)
 		}
+	} else {
+		logging.FromContext(ctx).WarnCtx(ctx, "Failed to marshal user list for caching", logging.Error, err)
 	}
+	return loadedListPage{users: users, total: total}, nil
+}
 
-	s.logger.DebugCtx(ctx, "User list retrieved successfully", "total_count", total, "returned_count", len(users), "page", page)
-	return response, nil
+// strongRead reports whether a read must skip the cache and go to the
+// database.
+func strongRead(consistency pb.ReadConsistency) bool {
+	return consistency == pb.ReadConsistency_READ_CONSISTENCY_STRONG
 }
 
 func (s *CachedUserServer) cacheUser(ctx context.Context, user *models.User) error {
-	s.logger.DebugCtx(ctx, "Caching user", logging.UserID, user.ID, logging.UserEmail, user.Email)
+	logging.FromContext(ctx).DebugCtx(ctx, "Caching user", logging.UserID, user.ID, logging.UserEmail, user.Email)
 
-	data, err := json.Marshal(user)
+	stopSerialization := timing.Track(ctx, timing.StageSerialization)
+	data, err := json.Marshal(cachedUser{User: *user, CachedAt: s.clock.Now().UnixMilli()})
+	stopSerialization()
 	if err != nil {
-		s.logger.ErrorCtx(ctx, "Failed to marshal user for caching", logging.UserID, user.ID, logging.Error, err)
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to marshal user for caching", logging.UserID, user.ID, logging.Error, err)
 		return err
 	}
 
-	cacheKey := s.userCacheKey(user.ID)
-	err = s.cache.Set(ctx, cacheKey, data, defaultCacheTTL)
+	cacheKey := s.userCacheKey(ctx, user.ID)
+	ttl := s.userCacheTTL()
+	err = s.cache.Set(ctx, cacheKey, data, ttl)
 	if err != nil {
-		s.logger.ErrorCtx(ctx, "Failed to set user in cache", logging.UserID, user.ID, logging.CacheKey, cacheKey, logging.Error, err)
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to set user in cache", logging.UserID, user.ID, logging.CacheKey, cacheKey, logging.Error, err)
 		return err
 	}
 
-	s.logger.DebugCtx(ctx, "User cached successfully", logging.UserID, user.ID, logging.CacheKey, cacheKey, "ttl", defaultCacheTTL)
+	logging.FromContext(ctx).DebugCtx(ctx, "User cached successfully", logging.UserID, user.ID, logging.CacheKey, cacheKey, "ttl", ttl)
 	return nil
 }
 
+// refreshUserTTL applies sliding expiration to a user entry that was just
+// read. Entries past their max lifetime are left to expire.
+func (s *CachedUserServer) refreshUserTTL(ctx context.Context, cacheKey string, cachedAtMs int64) {
+	if s.slidingTTL <= 0 || cachedAtMs == 0 {
+		return
+	}
+	ttl := s.slidingTTL
+	if s.maxCacheLifetime > 0 {
+		remaining := time.UnixMilli(cachedAtMs).Add(s.maxCacheLifetime).Sub(s.clock.Now())
+		if remaining < time.Second {
+			return
+		}
+		ttl = min(ttl, remaining)
+	}
+	if err := s.cache.Expire(ctx, cacheKey, ttl); err != nil {
+		logging.FromContext(ctx).WarnCtx(ctx, "Failed to refresh cached user TTL", logging.CacheKey, cacheKey, logging.Error, err)
+	}
+}
+
 func (s *CachedUserServer) invalidateListCache(ctx context.Context) {
 	ctx, span := s.tracer.Start(ctx, "cache.invalidate_list",
 		trace.WithSpanKind(trace.SpanKindInternal),
@@ -296,34 +605,25 @@ func (s *CachedUserServer) invalidateListCache(ctx context.Context) {
 	)
 	defer span.End()
 
-	s.logger.DebugCtx(ctx, "Starting list cache invalidation")
-	invalidatedCount := 0
-
-	// Comprehensive approach: delete cache patterns for common pagination scenarios
-	// Cover more realistic pagination patterns based on typical user behavior
-	commonLimits := []int{1, 5, 10, 20, 25, 50, 100}
-	maxPages := 20 // Cover first 20 pages for each limit
-
-	for _, limit := range commonLimits {
-		for page := range maxPages {
-			offset := page * limit
-			cacheKey := s.userListCacheKey(offset, limit)
-
-			// Safe type assertion with proper error handling
-			if tracedCache, ok := s.cache.(*cache.TracedCache); ok {
-				// Use untraced delete to avoid creating individual spans
-				if err := tracedCache.DeleteUntraced(ctx, cacheKey); err == nil {
-					invalidatedCount++
-				}
-			} else {
-				// Fallback to regular delete if not a traced cache
-				if err := s.cache.Delete(ctx, cacheKey); err == nil {
-					invalidatedCount++
-				}
-			}
-		}
+	if s.listWriteBack != nil {
+		s.listWriteBack.Discard()
+	}
+	logging.FromContext(ctx).DebugCtx(ctx, "Starting list cache invalidation")
+	start := time.Now()
+
+	// Every page of every limit, however rarely requested
+	listPattern := s.keys.Pattern(ctx, userListCachePrefix)
+	invalidatedCount, err := s.cache.DeletePattern(ctx, listPattern)
+	s.broadcastInvalidation(ctx, nil, []string{listPattern})
+	if err != nil {
+		span.RecordError(err)
 	}
 
-	span.SetAttributes(attribute.Int("cache.invalidated_entries", invalidatedCount))
-	s.logger.DebugCtx(ctx, "List cache invalidation completed", "invalidated_entries", invalidatedCount)
+	elapsed := time.Since(start)
+
+	s.invalidation.record(ctx, span, scopeList, invalidatedCount, elapsed, err)
+	logging.FromContext(ctx).DebugCtx(ctx, "List cache invalidation completed", "invalidated_entries", invalidatedCount, "duration", elapsed)
+
+	// Searches return the same users as list pages, so they go too
+	s.invalidateSearchCache(ctx)
 }
@@ -15,9 +15,9 @@ type CombinedServer struct {
 	testServer       *TestServer
 }
 
-func NewCombinedServer(userRepo repository.UserRepository, cache cache.Cache, logger *slog.Logger) *CombinedServer {
+func NewCombinedServer(userRepo repository.UserRepository, cache cache.Cache, logger *slog.Logger, opts ...Option) *CombinedServer {
 	return &CombinedServer{
-		cachedUserServer: NewCachedUserServer(userRepo, cache, logger),
+		cachedUserServer: NewCachedUserServer(userRepo, cache, logger, opts...),
 		testServer:       NewTestServer(logger),
 	}
 }
@@ -31,6 +31,10 @@ func (s *CombinedServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*
 	return s.cachedUserServer.GetUser(ctx, req)
 }
 
+func (s *CombinedServer) GetUserAtTime(ctx context.Context, req *pb.GetUserAtTimeRequest) (*pb.GetUserAtTimeResponse, error) {
+	return s.cachedUserServer.GetUserAtTime(ctx, req)
+}
+
 func (s *CombinedServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
 	return s.cachedUserServer.UpdateUser(ctx, req)
 }
@@ -39,6 +43,30 @@ func (s *CombinedServer) DeleteUser(ctx context.Context, req *pb.DeleteUserReque
 	return s.cachedUserServer.DeleteUser(ctx, req)
 }
 
+func (s *CombinedServer) EraseUser(ctx context.Context, req *pb.EraseUserRequest) (*pb.EraseUserResponse, error) {
+	return s.cachedUserServer.EraseUser(ctx, req)
+}
+
+func (s *CombinedServer) ExportUserData(ctx context.Context, req *pb.ExportUserDataRequest) (*pb.ExportUserDataResponse, error) {
+	return s.cachedUserServer.ExportUserData(ctx, req)
+}
+
+func (s *CombinedServer) RevertUser(ctx context.Context, req *pb.RevertUserRequest) (*pb.RevertUserResponse, error) {
+	return s.cachedUserServer.RevertUser(ctx, req)
+}
+
+func (s *CombinedServer) MergeUsers(ctx context.Context, req *pb.MergeUsersRequest) (*pb.MergeUsersResponse, error) {
+	return s.cachedUserServer.MergeUsers(ctx, req)
+}
+
+func (s *CombinedServer) RequestEmailChange(ctx context.Context, req *pb.RequestEmailChangeRequest) (*pb.RequestEmailChangeResponse, error) {
+	return s.cachedUserServer.RequestEmailChange(ctx, req)
+}
+
+func (s *CombinedServer) ConfirmEmailChange(ctx context.Context, req *pb.ConfirmEmailChangeRequest) (*pb.ConfirmEmailChangeResponse, error) {
+	return s.cachedUserServer.ConfirmEmailChange(ctx, req)
+}
+
 func (s *CombinedServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
 	return s.cachedUserServer.ListUsers(ctx, req)
 }
@@ -46,3 +74,31 @@ func (s *CombinedServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest
 func (s *CombinedServer) TestError(ctx context.Context, req *pb.TestErrorRequest) (*pb.TestErrorResponse, error) {
 	return s.testServer.TestError(ctx, req)
 }
+
+func (s *CombinedServer) TestLatency(ctx context.Context, req *pb.TestLatencyRequest) (*pb.TestLatencyResponse, error) {
+	return s.testServer.TestLatency(ctx, req)
+}
+
+func (s *CombinedServer) TestLatencyStream(req *pb.TestLatencyStreamRequest, stream pb.UserService_TestLatencyStreamServer) error {
+	return s.testServer.TestLatencyStream(req, stream)
+}
+
+func (s *CombinedServer) TestStream(stream pb.UserService_TestStreamServer) error {
+	return s.testServer.TestStream(stream)
+}
+
+func (s *CombinedServer) TestEcho(ctx context.Context, req *pb.TestEchoRequest) (*pb.TestEchoResponse, error) {
+	return s.testServer.TestEcho(ctx, req)
+}
+
+// InvalidateUser drops every cached copy of a user, for background jobs that
+// change users outside of an RPC.
+func (s *CombinedServer) InvalidateUser(ctx context.Context, id string) error {
+	return s.cachedUserServer.purgeUserCache(ctx, id)
+}
+
+// InvalidationSubscription applies the cache invalidations other instances
+// broadcast; see WithInvalidationBroadcast.
+func (s *CombinedServer) InvalidationSubscription(base *slog.Logger) cache.Subscription {
+	return s.cachedUserServer.InvalidationSubscription(base)
+}
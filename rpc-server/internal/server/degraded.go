@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// staleMetadataKey is the response header set on answers served from the
+// cache while the database is unreachable.
+const staleMetadataKey = "x-stale"
+
+// DatabaseHealth reports whether the database is reachable, e.g.
+// *database.Monitor.
+type DatabaseHealth interface {
+	Healthy() bool
+}
+
+// WithDegradedReads keeps GetUser and ListUsers serving cache hits, marked
+// stale, while health reports the database as down. Cache misses fail fast
+// with UNAVAILABLE instead of waiting on the database.
+func WithDegradedReads(health DatabaseHealth) Option {
+	return func(s *CachedUserServer) {
+		s.dbHealth = health
+	}
+}
+
+// degraded reports whether reads must be answered from the cache alone.
+func (s *CachedUserServer) degraded() bool {
+	return s.dbHealth != nil && !s.dbHealth.Healthy()
+}
+
+// markStale sets the stale response header. Outside a gRPC call, e.g. when
+// the server is called directly, there is no header to set.
+func markStale(ctx context.Context) {
+	_ = grpc.SetHeader(ctx, metadata.Pairs(staleMetadataKey, "true"))
+}
+
+// DegradedModeInterceptor rejects writes with UNAVAILABLE while health
+// reports the database as down, rather than letting each one time out.
+func DegradedModeInterceptor(health DatabaseHealth) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if writeMethods[info.FullMethod] && !health.Healthy() {
+			return nil, databaseUnavailableError(info.FullMethod)
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"grpc-server/internal/audit"
+	"grpc-server/internal/events"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/models"
+	"grpc-server/internal/repository"
+	pb "grpc-server/pkg/pb"
+)
+
+// RequestEmailChange records a pending change of a user's email and emits a
+// user.email_change_requested event carrying the confirmation token for the
+// notifier to send to the new address. The email itself is unchanged until
+// ConfirmEmailChange. A new request replaces any pending one.
+func (s *CachedUserServer) RequestEmailChange(ctx context.Context, req *pb.RequestEmailChangeRequest) (*pb.RequestEmailChangeResponse, error) {
+	logging.FromContext(ctx).DebugCtx(ctx, "RequestEmailChange request received", logging.UserID, req.Id, "new_email", req.NewEmail)
+
+	user, err := s.repo.GetByID(ctx, req.Id)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			logging.FromContext(ctx).InfoCtx(ctx, "User not found for email change", logging.UserID, req.Id)
+			return nil, userNotFoundError(req.Id)
+		}
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to get user for email change from repository", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "request_email_change", req.Id, "failed to retrieve user")
+	}
+	if user.Status == models.StatusMerged {
+		return nil, userMergedError(req.Id)
+	}
+	if req.NewEmail == user.Email {
+		return nil, emailUnchangedError(req.Id)
+	}
+
+	// Checked again on confirmation, since the address may be taken meanwhile
+	exists, err := s.repo.EmailExists(ctx, req.NewEmail, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to check email existence", logging.UserEmail, req.NewEmail, logging.Error, err)
+		return nil, repositoryError(err, "request_email_change", req.Id, "failed to validate email")
+	}
+	if exists {
+		logging.FromContext(ctx).WarnCtx(ctx, "Email already exists for different user", logging.UserEmail, req.NewEmail, logging.UserID, req.Id)
+		return nil, emailExistsError(req.NewEmail)
+	}
+
+	change, token, err := models.NewEmailChange(s.clock, req.Id, req.NewEmail, s.emailChangeTTL)
+	if err != nil {
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to create email change", logging.UserID, req.Id, logging.Error, err)
+		return nil, internalError("request_email_change", req.Id, "failed to create email change")
+	}
+	if err := s.repo.RequestEmailChange(ctx, change); err != nil {
+		if err == repository.ErrUserNotFound {
+			return nil, userNotFoundError(req.Id)
+		}
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to store email change in repository", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "request_email_change", req.Id, "failed to store email change")
+	}
+
+	event := events.New(ctx, events.TypeEmailChangeRequested, req.Id, map[string]string{
+		"new_email":                  req.NewEmail,
+		"expires_at":                 change.ExpiresAt.UTC().Format(time.RFC3339),
+		events.DataConfirmationToken: token,
+	})
+	if err := s.events.Publish(ctx, event); err != nil {
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to publish email change request event", logging.UserID, req.Id, logging.Error, err)
+		return nil, internalError("request_email_change", req.Id, "failed to send confirmation")
+	}
+
+	logging.FromContext(ctx).InfoCtx(ctx, "Email change requested", logging.UserID, req.Id, "expires_at", change.ExpiresAt)
+
+	return &pb.RequestEmailChangeResponse{
+		ExpiresAt: change.ExpiresAt.Unix(),
+		Message:   s.message("Email change requested, confirmation sent to the new address"),
+		Outcome:   pb.Outcome_OUTCOME_EMAIL_CHANGE_REQUESTED,
+	}, nil
+}
+
+// ConfirmEmailChange applies a pending email change once the token sent to
+// the new address is presented, and emits a user.email_changed event.
+func (s *CachedUserServer) ConfirmEmailChange(ctx context.Context, req *pb.ConfirmEmailChangeRequest) (*pb.ConfirmEmailChangeResponse, error) {
+	logging.FromContext(ctx).DebugCtx(ctx, "ConfirmEmailChange request received", logging.UserID, req.Id)
+
+	previous, err := s.repo.GetByID(ctx, req.Id)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			logging.FromContext(ctx).InfoCtx(ctx, "User not found for email change confirmation", logging.UserID, req.Id)
+			return nil, userNotFoundError(req.Id)
+		}
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to get user for email change confirmation from repository", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "confirm_email_change", req.Id, "failed to retrieve user")
+	}
+
+	user, err := s.repo.ConfirmEmailChange(ctx, req.Id, req.Token)
+	if err != nil {
+		switch err {
+		case repository.ErrEmailChangeNotFound:
+			return nil, emailChangeNotFoundError(req.Id)
+		case repository.ErrInvalidToken:
+			logging.FromContext(ctx).WarnCtx(ctx, "Invalid email change confirmation token", logging.UserID, req.Id)
+			return nil, invalidTokenError(req.Id)
+		case repository.ErrEmailChangeExpired:
+			logging.FromContext(ctx).InfoCtx(ctx, "Email change expired", logging.UserID, req.Id)
+			return nil, emailChangeExpiredError(req.Id)
+		case repository.ErrEmailExists:
+			// The pending change is kept, so the user can retry once the
+			// address is released or request a different one.
+			var email string
+			if change, err := s.repo.PendingEmailChange(ctx, req.Id); err == nil {
+				email = change.NewEmail
+			}
+			logging.FromContext(ctx).WarnCtx(ctx, "Email already exists for different user", logging.UserEmail, email, logging.UserID, req.Id)
+			return nil, emailExistsError(email)
+		}
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to confirm email change in repository", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "confirm_email_change", req.Id, "failed to confirm email change")
+	}
+
+	s.userWritten(ctx, user)
+	s.invalidateSearchCache(ctx)
+
+	s.audit.Record(ctx, audit.ActionEmailChanged,
+		slog.String(logging.UserID, req.Id),
+		slog.String("old_email", previous.Email),
+		slog.String("new_email", user.Email),
+		slog.String(logging.TraceID, trace.SpanContextFromContext(ctx).TraceID().String()),
+	)
+
+	event := events.New(ctx, events.TypeEmailChanged, req.Id, map[string]string{
+		"old_email": previous.Email,
+		"new_email": user.Email,
+	})
+	if err := s.events.Publish(ctx, event); err != nil {
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to publish email changed event", logging.UserID, req.Id, logging.Error, err)
+	}
+
+	logging.FromContext(ctx).InfoCtx(ctx, "Email change confirmed", logging.UserID, req.Id, logging.UserEmail, user.Email)
+
+	return &pb.ConfirmEmailChangeResponse{
+		User:    user.ToProto(),
+		Message: s.message("Email changed successfully"),
+		Outcome: pb.Outcome_OUTCOME_EMAIL_CHANGED,
+	}, nil
+}
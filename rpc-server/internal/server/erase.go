@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"grpc-server/internal/audit"
+	"grpc-server/internal/events"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/repository"
+	pb "grpc-server/pkg/pb"
+)
+
+// EraseUser permanently removes a user for right-to-be-forgotten requests.
+// Unlike DeleteUser it also deletes the user's history, purges every cached
+// copy, records a deletion certificate in the audit log and emits a
+// user.erased event.
+func (s *CachedUserServer) EraseUser(ctx context.Context, req *pb.EraseUserRequest) (*pb.EraseUserResponse, error) {
+	logging.FromContext(ctx).DebugCtx(ctx, "EraseUser request received", logging.UserID, req.Id)
+
+	if err := s.repo.Erase(ctx, req.Id); err != nil {
+		if err == repository.ErrUserNotFound {
+			logging.FromContext(ctx).InfoCtx(ctx, "User not found for erasure", logging.UserID, req.Id)
+			return nil, userNotFoundError(req.Id)
+		}
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to erase user from repository", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "erase_user", req.Id, "failed to erase user")
+	}
+	erasedAt := s.clock.Now()
+	certificateID := uuid.New().String()
+
+	cachePurged := true
+	if err := s.purgeUserCache(ctx, req.Id); err != nil {
+		// The row is gone; a leftover entity entry expires with defaultCacheTTL.
+		cachePurged = false
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to purge erased user from cache", logging.UserID, req.Id, logging.Error, err)
+	}
+
+	s.audit.Record(ctx, audit.ActionUserErased,
+		slog.String("certificate_id", certificateID),
+		slog.String(logging.UserID, req.Id),
+		slog.String("reason", req.Reason),
+		slog.Time("erased_at", erasedAt),
+		slog.Bool("cache_purged", cachePurged),
+		slog.String(logging.TraceID, trace.SpanContextFromContext(ctx).TraceID().String()),
+	)
+
+	event := events.New(ctx, events.TypeUserErased, req.Id, map[string]string{
+		"certificate_id": certificateID,
+		"cache_purged":   strconv.FormatBool(cachePurged),
+	})
+	if err := s.events.Publish(ctx, event); err != nil {
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to publish erasure event", logging.UserID, req.Id, logging.Error, err)
+	}
+
+	logging.FromContext(ctx).InfoCtx(ctx, "User erased successfully", logging.UserID, req.Id, "certificate_id", certificateID)
+
+	return &pb.EraseUserResponse{
+		CertificateId: certificateID,
+		ErasedAt:      erasedAt.Unix(),
+		Message:       s.message("User erased successfully"),
+		Outcome:       pb.Outcome_OUTCOME_ERASED,
+	}, nil
+}
+
+// purgeUserCache removes every cache entry that may hold id's data: the entity
+// entry and all cached list pages.
+func (s *CachedUserServer) purgeUserCache(ctx context.Context, id string) error {
+	err := s.invalidateUsers(ctx, id)
+	s.invalidateListCache(ctx)
+	return err
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpc_codes "google.golang.org/grpc/codes"
+
+	"grpc-server/internal/database"
+	"grpc-server/internal/deadline"
+	"grpc-server/internal/runtimeconfig"
+	"grpc-server/pkg/apierror"
+)
+
+func userNotFoundError(id string) error {
+	return apierror.New(grpc_codes.NotFound, apierror.ReasonUserNotFound,
+		fmt.Sprintf("user with ID %s not found", id),
+		apierror.User(id, "user does not exist"),
+		map[string]string{"user_id": id},
+	)
+}
+
+func userVersionNotFoundError(id string, at time.Time) error {
+	return apierror.New(grpc_codes.NotFound, apierror.ReasonUserNotFound,
+		fmt.Sprintf("user with ID %s did not exist at %s", id, at.UTC().Format(time.RFC3339)),
+		apierror.User(id, "no version of the user was current at the requested time"),
+		map[string]string{"user_id": id, "at": at.UTC().Format(time.RFC3339)},
+	)
+}
+
+func versionNotFoundError(id string, versionID int64) error {
+	return apierror.New(grpc_codes.NotFound, apierror.ReasonVersionNotFound,
+		fmt.Sprintf("version %d of user with ID %s not found", versionID, id),
+		apierror.User(id, "the user has no history version with this ID"),
+		map[string]string{"user_id": id, "version_id": strconv.FormatInt(versionID, 10)},
+	)
+}
+
+func selfMergeError(id string) error {
+	return apierror.New(grpc_codes.InvalidArgument, apierror.ReasonSelfMerge,
+		fmt.Sprintf("cannot merge user with ID %s into itself", id),
+		apierror.User(id, "source and target must be different users"),
+		map[string]string{"user_id": id},
+	)
+}
+
+func userMergedError(id string) error {
+	return apierror.New(grpc_codes.FailedPrecondition, apierror.ReasonUserMerged,
+		fmt.Sprintf("user with ID %s has already been merged", id),
+		apierror.User(id, "user is soft-deleted by an earlier merge"),
+		map[string]string{"user_id": id},
+	)
+}
+
+func crossShardMergeError(sourceID, targetID string) error {
+	return apierror.New(grpc_codes.FailedPrecondition, apierror.ReasonCrossShardMerge,
+		fmt.Sprintf("users with IDs %s and %s are stored on different shards", sourceID, targetID),
+		apierror.User(sourceID, "source and target must be stored on the same shard"),
+		map[string]string{"source_id": sourceID, "target_id": targetID},
+	)
+}
+
+func emailChangeRequiredError(id string) error {
+	return apierror.New(grpc_codes.FailedPrecondition, apierror.ReasonEmailChangeRequired,
+		"email cannot be changed with UpdateUser; use RequestEmailChange",
+		apierror.User(id, "email changes require confirmation"),
+		map[string]string{"user_id": id},
+	)
+}
+
+func emailUnchangedError(id string) error {
+	return apierror.New(grpc_codes.InvalidArgument, apierror.ReasonEmailUnchanged,
+		"new email is the same as the current email",
+		apierror.User(id, "email is unchanged"),
+		map[string]string{"user_id": id},
+	)
+}
+
+func emailChangeNotFoundError(id string) error {
+	return apierror.New(grpc_codes.NotFound, apierror.ReasonEmailChangeNotFound,
+		fmt.Sprintf("no pending email change for user with ID %s", id),
+		apierror.User(id, "user has no pending email change"),
+		map[string]string{"user_id": id},
+	)
+}
+
+func emailChangeExpiredError(id string) error {
+	return apierror.New(grpc_codes.FailedPrecondition, apierror.ReasonEmailChangeExpired,
+		fmt.Sprintf("email change for user with ID %s has expired", id),
+		apierror.User(id, "the confirmation token has expired"),
+		map[string]string{"user_id": id},
+	)
+}
+
+func invalidTokenError(id string) error {
+	return apierror.New(grpc_codes.InvalidArgument, apierror.ReasonInvalidToken,
+		"invalid confirmation token",
+		apierror.User(id, "the confirmation token does not match the pending email change"),
+		map[string]string{"user_id": id},
+	)
+}
+
+func emailExistsError(email string) error {
+	return apierror.New(grpc_codes.AlreadyExists, apierror.ReasonEmailAlreadyExists,
+		fmt.Sprintf("user with email %s already exists", email),
+		apierror.User(email, "email is already registered to another user"),
+		map[string]string{"email": email},
+	)
+}
+
+func invalidUserIDError(id string) error {
+	return apierror.New(grpc_codes.InvalidArgument, apierror.ReasonInvalidUserID,
+		fmt.Sprintf("user ID %s is not a valid UUID", id),
+		apierror.User(id, "user IDs must be UUIDs"),
+		map[string]string{"user_id": id},
+	)
+}
+
+func userExistsError(id string) error {
+	return apierror.New(grpc_codes.AlreadyExists, apierror.ReasonUserAlreadyExists,
+		fmt.Sprintf("user with ID %s already exists", id),
+		apierror.User(id, "user ID is already taken"),
+		map[string]string{"user_id": id},
+	)
+}
+
+// internalError reports a failed operation without leaking the underlying cause;
+// userID may be empty for operations not tied to a single user.
+func internalError(operation, userID, msg string) error {
+	metadata := map[string]string{"operation": operation}
+	var resource *errdetails.ResourceInfo
+	if userID != "" {
+		metadata["user_id"] = userID
+		resource = apierror.User(userID, "")
+	}
+	return apierror.New(grpc_codes.Internal, apierror.ReasonInternal, msg, resource, metadata)
+}
+
+// repositoryError is internalError for repository failures, except that running
+// out of deadline budget is reported as DeadlineExceeded so clients can retry
+// with a longer timeout, and a saturated connection pool as ResourceExhausted
+// so they back off, instead of treating either as a server fault.
+func repositoryError(err error, operation, userID, msg string) error {
+	metadata := map[string]string{"operation": operation}
+	if userID != "" {
+		metadata["user_id"] = userID
+	}
+	switch {
+	case errors.Is(err, database.ErrPoolExhausted):
+		return apierror.New(grpc_codes.ResourceExhausted, apierror.ReasonServerBusy,
+			fmt.Sprintf("no database connection available during %s", operation), nil, metadata)
+	case errors.Is(err, deadline.ErrBudgetExhausted) || errors.Is(err, context.DeadlineExceeded):
+		return apierror.New(grpc_codes.DeadlineExceeded, apierror.ReasonDeadlineExceeded,
+			fmt.Sprintf("deadline exceeded during %s", operation), nil, metadata)
+	}
+	return internalError(operation, userID, msg)
+}
+
+func exportUnavailableError() error {
+	return apierror.New(grpc_codes.FailedPrecondition, apierror.ReasonExportUnavailable,
+		"data export is not configured on this server", nil, nil)
+}
+
+func readOnlyError(method string) error {
+	return apierror.New(grpc_codes.Unavailable, apierror.ReasonReadOnly,
+		"the server is in read-only mode", nil, map[string]string{"method": method})
+}
+
+func databaseUnavailableError(operation string) error {
+	return apierror.New(grpc_codes.Unavailable, apierror.ReasonDatabaseUnavailable,
+		fmt.Sprintf("database unavailable during %s", operation), nil,
+		map[string]string{"operation": operation})
+}
+
+func rateLimitedError(retryAfter time.Duration, class runtimeconfig.Class) error {
+	return apierror.Retry(grpc_codes.ResourceExhausted, apierror.ReasonRateLimited,
+		"rate limit exceeded", retryAfter,
+		map[string]string{
+			"retry_after_ms": strconv.FormatInt(retryAfter.Milliseconds(), 10),
+			"priority_class": class.String(),
+		})
+}
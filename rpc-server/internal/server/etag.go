@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"grpc-server/internal/models"
+	pb "grpc-server/pkg/pb"
+)
+
+// GetUser sets etagMetadataKey on its response header and answers
+// not_modified when the request's ifNoneMatchMetadataKey metadata names the
+// current ETag. The REST gateway maps them to the HTTP ETag and
+// If-None-Match headers.
+const (
+	etagMetadataKey        = "etag"
+	ifNoneMatchMetadataKey = "if-none-match"
+)
+
+// userETag returns a weak ETag for user. Expiry changes the status without
+// touching updated_at, so the status is part of it.
+func userETag(user *models.User) string {
+	return fmt.Sprintf(`W/"%x-%s"`, user.UpdatedAt.UnixMicro(), user.Status)
+}
+
+// checkETag sets etag on the response header and reports whether the caller
+// already holds it.
+func checkETag(ctx context.Context, etag string) bool {
+	_ = grpc.SetHeader(ctx, metadata.Pairs(etagMetadataKey, etag))
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, header := range md.Get(ifNoneMatchMetadataKey) {
+		for candidate := range strings.SplitSeq(header, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || weakTag(candidate) == weakTag(etag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// weakTag strips the weak indicator, since If-None-Match uses weak
+// comparison.
+func weakTag(etag string) string {
+	return strings.TrimPrefix(etag, "W/")
+}
+
+// notModifiedResponse answers a GetUser whose caller holds the current ETag.
+func (s *CachedUserServer) notModifiedResponse(ctx context.Context, stale bool) *pb.GetUserResponse {
+	if stale {
+		markStale(ctx)
+	}
+	return &pb.GetUserResponse{
+		Message:     s.message("User not modified"),
+		NotModified: true,
+		Stale:       stale,
+		Outcome:     pb.Outcome_OUTCOME_NOT_MODIFIED,
+	}
+}
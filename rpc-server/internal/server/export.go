@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+
+	"grpc-server/internal/audit"
+	"grpc-server/internal/export"
+	"grpc-server/internal/i18n"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/repository"
+	pb "grpc-server/pkg/pb"
+)
+
+// ExportUserData returns everything stored about a user as a signed JSON
+// document for data-portability requests. It always reads from the repository
+// so the export never reflects a stale cache entry. Timestamps are written in
+// the caller's time zone, see i18n.TimezoneFrom.
+func (s *CachedUserServer) ExportUserData(ctx context.Context, req *pb.ExportUserDataRequest) (*pb.ExportUserDataResponse, error) {
+	logging.FromContext(ctx).DebugCtx(ctx, "ExportUserData request received", logging.UserID, req.Id)
+
+	if s.signer == nil {
+		logging.FromContext(ctx).WarnCtx(ctx, "ExportUserData called without a signing key configured", logging.UserID, req.Id)
+		return nil, exportUnavailableError()
+	}
+
+	user, err := s.repo.GetByID(ctx, req.Id)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			logging.FromContext(ctx).InfoCtx(ctx, "User not found for export", logging.UserID, req.Id)
+			return nil, userNotFoundError(req.Id)
+		}
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to get user for export", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "export_user_data", req.Id, "failed to retrieve user")
+	}
+
+	history, err := s.repo.History(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to get user history for export", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "export_user_data", req.Id, "failed to retrieve user history")
+	}
+
+	change, err := s.repo.PendingEmailChange(ctx, req.Id)
+	if err != nil && err != repository.ErrEmailChangeNotFound {
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to get pending email change for export", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "export_user_data", req.Id, "failed to retrieve pending email change")
+	}
+
+	exportedAt := s.clock.Now()
+	document, err := export.NewDocument(user, history, change, exportedAt, i18n.TimezoneFrom(ctx)).Marshal()
+	if err != nil {
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to marshal export document", logging.UserID, req.Id, logging.Error, err)
+		return nil, internalError("export_user_data", req.Id, "failed to build export")
+	}
+
+	s.audit.Record(ctx, audit.ActionUserExported,
+		slog.String(logging.UserID, req.Id),
+		slog.Time("exported_at", exportedAt),
+	)
+	logging.FromContext(ctx).InfoCtx(ctx, "User data exported", logging.UserID, req.Id, "document_bytes", len(document))
+
+	return &pb.ExportUserDataResponse{
+		Document:           document,
+		Signature:          s.signer.Sign(document),
+		SignatureAlgorithm: export.Algorithm,
+		ExportedAt:         exportedAt.Unix(),
+	}, nil
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"grpc-server/internal/logging"
+	"grpc-server/internal/repository"
+	pb "grpc-server/pkg/pb"
+)
+
+// GetUserAtTime returns the version of a user that was current at req.At,
+// read straight from the history table for point-in-time debugging.
+func (s *CachedUserServer) GetUserAtTime(ctx context.Context, req *pb.GetUserAtTimeRequest) (*pb.GetUserAtTimeResponse, error) {
+	at := time.Unix(req.At, 0)
+	logging.FromContext(ctx).DebugCtx(ctx, "GetUserAtTime request received", logging.UserID, req.Id, "at", at)
+
+	version, err := s.repo.GetAt(ctx, req.Id, at)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			logging.FromContext(ctx).InfoCtx(ctx, "No user version at requested time", logging.UserID, req.Id, "at", at)
+			return nil, userVersionNotFoundError(req.Id, at)
+		}
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to get user version from repository", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "get_user_at_time", req.Id, "failed to retrieve user history")
+	}
+
+	resp := &pb.GetUserAtTimeResponse{
+		User:      version.ToProto(),
+		VersionId: version.VersionID,
+		ValidFrom: version.ValidFrom.Unix(),
+		Message:   s.message("User version retrieved successfully"),
+		Outcome:   pb.Outcome_OUTCOME_RETRIEVED,
+	}
+	if !version.ValidTo.IsZero() {
+		resp.ValidTo = version.ValidTo.Unix()
+	}
+	return resp, nil
+}
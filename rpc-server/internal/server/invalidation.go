@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"grpc-server/internal/logging"
+)
+
+// Invalidation scopes, recorded as the cache.invalidation.scope attribute.
+const (
+	scopeEntity = "entity"
+	scopeList   = "list"
+	scopeSearch = "search"
+)
+
+// invalidationMetrics describes how much work each cache invalidation does.
+// Instruments come from the global MeterProvider and are no-ops until one is
+// installed.
+type invalidationMetrics struct {
+	count    metric.Int64Counter
+	keys     metric.Int64Histogram
+	duration metric.Float64Histogram
+}
+
+func newInvalidationMetrics() invalidationMetrics {
+	meter := otel.Meter("rpc-server.rpc/server")
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	count, _ := meter.Int64Counter("cache.invalidations",
+		metric.WithDescription("Number of cache invalidations"),
+		metric.WithUnit("{invalidation}"),
+	)
+	keys, _ := meter.Int64Histogram("cache.invalidation.keys",
+		metric.WithDescription("Number of keys deleted by a single invalidation"),
+		metric.WithUnit("{key}"),
+		metric.WithExplicitBucketBoundaries(0, 1, 2, 5, 10, 25, 50, 100, 150, 250, 500),
+	)
+	duration, _ := meter.Float64Histogram("cache.invalidation.duration",
+		metric.WithDescription("Time spent deleting keys for a single invalidation"),
+		metric.WithUnit("s"),
+	)
+	return invalidationMetrics{count: count, keys: keys, duration: duration}
+}
+
+// record reports one invalidation on the metrics and on span.
+func (m invalidationMetrics) record(ctx context.Context, span trace.Span, scope string, keys int, elapsed time.Duration, err error) {
+	attrs := metric.WithAttributes(
+		attribute.String("cache.invalidation.scope", scope),
+		attribute.Bool("error", err != nil),
+	)
+	m.count.Add(ctx, 1, attrs)
+	m.keys.Record(ctx, int64(keys), attrs)
+	m.duration.Record(ctx, elapsed.Seconds(), attrs)
+
+	span.SetAttributes(
+		attribute.Int("cache.invalidation.keys", keys),
+		attribute.Float64("cache.invalidation.duration_ms", float64(elapsed.Microseconds())/1000),
+	)
+}
+
+// invalidateUsers removes the entity entries for ids in a single delete, so
+// readers never observe some of them gone and others still cached.
+func (s *CachedUserServer) invalidateUsers(ctx context.Context, ids ...string) error {
+	ctx, span := s.tracer.Start(ctx, "cache.invalidate_entity",
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			attribute.String("cache.operation", "invalidate_entity"),
+		),
+	)
+	defer span.End()
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = s.userCacheKey(ctx, id)
+	}
+
+	start := time.Now()
+	err := s.cache.Delete(ctx, keys...)
+	deleted := len(keys)
+	if err != nil {
+		deleted = 0
+		span.RecordError(err)
+	}
+	elapsed := time.Since(start)
+
+	s.broadcastInvalidation(ctx, keys, nil)
+	s.invalidation.record(ctx, span, scopeEntity, deleted, elapsed, err)
+	logging.FromContext(ctx).DebugCtx(ctx, "Entity cache invalidation completed", "invalidated_entries", deleted, "duration", elapsed)
+	return err
+}
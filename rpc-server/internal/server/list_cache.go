@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"grpc-server/internal/cache"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/models"
+	"grpc-server/internal/timing"
+	pb "grpc-server/pkg/pb"
+)
+
+const generationTTL = 24 * time.Hour
+
+// cacheGeneration returns the token held in the generation key named name,
+// which is part of every key of the pages it covers. Writes replace the
+// token instead of deleting pages, orphaning them until they expire; unlike
+// a SCAN for the pages, that is one command however large the keyspace.
+// It reports false if the token can't be read, in which case pages must not
+// be cached.
+func (s *CachedUserServer) cacheGeneration(ctx context.Context, name string) (string, bool) {
+	key := s.keys.Key(ctx, name)
+	generation, err := s.cache.Get(ctx, key)
+	if errors.Is(err, cache.ErrCacheMiss) {
+		// Start a new generation rather than assume one; pages cached
+		// before the token was lost may be stale.
+		token := uuid.NewString()
+		if err := s.cache.Set(ctx, key, token, generationTTL); err != nil {
+			return "", false
+		}
+		return token, true
+	}
+	if err != nil {
+		return "", false
+	}
+	return string(generation), true
+}
+
+// rotateGeneration invalidates every page of the generation key named name
+// and returns that key, for broadcasting.
+func (s *CachedUserServer) rotateGeneration(ctx context.Context, name string) (string, error) {
+	key := s.keys.Key(ctx, name)
+	return key, s.cache.Set(ctx, key, uuid.NewString(), generationTTL)
+}
+
+// cachedListPage is what list and search page entries hold: the IDs on the
+// page, in order, and the total they were taken from. Users are read from
+// their own entries, so writes that don't add, remove or reorder users
+// leave pages valid.
+type cachedListPage struct {
+	IDs   []string `json:"ids"`
+	Total int      `json:"total"`
+}
+
+// errHydrationUnavailable means a cached page names users that are neither
+// cached nor readable, because the database is down.
+var errHydrationUnavailable = errors.New("uncached users while the database is unavailable")
+
+// marshalListPage encodes a page of users for caching.
+func marshalListPage(ctx context.Context, users []*models.User, total int) ([]byte, error) {
+	defer timing.Track(ctx, timing.StageSerialization)()
+	page := cachedListPage{IDs: make([]string, len(users)), Total: total}
+	for i, user := range users {
+		page.IDs[i] = user.ID
+	}
+	return json.Marshal(page)
+}
+
+// listPageFromCache reads the page cached under key and hydrates its users.
+// It reports false on a miss, and when a user on the page no longer exists,
+// so the page is rebuilt from the database.
+func (s *CachedUserServer) listPageFromCache(ctx context.Context, key string) ([]*models.User, int, bool) {
+	data, err := s.cache.Get(ctx, key)
+	if err != nil {
+		if err != cache.ErrCacheMiss {
+			logging.FromContext(ctx).WarnCtx(ctx, "Cache get failed for user list", logging.Error, err)
+		}
+		return nil, 0, false
+	}
+
+	var page cachedListPage
+	stopSerialization := timing.Track(ctx, timing.StageSerialization)
+	err = json.Unmarshal(data, &page)
+	stopSerialization()
+	if err != nil {
+		logging.FromContext(ctx).WarnCtx(ctx, "Failed to unmarshal cached user list", logging.Error, err)
+		return nil, 0, false
+	}
+
+	users, err := s.hydrateUsers(ctx, page.IDs)
+	if err != nil {
+		logging.FromContext(ctx).WarnCtx(ctx, "Failed to hydrate cached user list", logging.Error, err)
+		return nil, 0, false
+	}
+	if len(users) != len(page.IDs) {
+		logging.FromContext(ctx).DebugCtx(ctx, "Cached user list names deleted users, rebuilding it", logging.CacheKey, key)
+		return nil, 0, false
+	}
+	return users, page.Total, true
+}
+
+// hydrateUsers returns the users with ids, in order: those cached with one
+// MGET, the rest with one BatchGet, which then get cached too. Users that no
+// longer exist are left out.
+func (s *CachedUserServer) hydrateUsers(ctx context.Context, ids []string) ([]*models.User, error) {
+	ctx, span := s.tracer.Start(ctx, "cache.hydrate_users",
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attribute.Int("cache.keys", len(ids))),
+	)
+	defer span.End()
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = s.userCacheKey(ctx, id)
+	}
+	values, err := s.cache.MGet(ctx, keys...)
+	if err != nil {
+		// Every user is still a database read away
+		logging.FromContext(ctx).WarnCtx(ctx, "Cache mget failed for user list", logging.Error, err)
+		values = make([][]byte, len(ids))
+	}
+
+	found := make(map[string]*models.User, len(ids))
+	var missing []string
+	stopSerialization := timing.Track(ctx, timing.StageSerialization)
+	for i, value := range values {
+		var entry cachedUser
+		if value != nil && json.Unmarshal(value, &entry) == nil {
+			found[ids[i]] = &entry.User
+			continue
+		}
+		missing = append(missing, ids[i])
+	}
+	stopSerialization()
+	span.SetAttributes(attribute.Int("cache.hits", len(ids)-len(missing)))
+
+	if len(missing) > 0 {
+		if s.degraded() {
+			return nil, errHydrationUnavailable
+		}
+		fetched, err := s.repo.BatchGet(ctx, missing)
+		if err != nil {
+			span.RecordError(err)
+			return nil, fmt.Errorf("failed to fetch uncached users: %w", err)
+		}
+		for _, user := range fetched {
+			found[user.ID] = user
+			if err := s.cacheUser(ctx, user); err != nil {
+				logging.FromContext(ctx).WarnCtx(ctx, "Failed to cache user", logging.UserID, user.ID, logging.Error, err)
+			}
+		}
+	}
+
+	users := make([]*models.User, 0, len(ids))
+	for _, id := range ids {
+		if user, ok := found[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// listResponse builds the ListUsers response for a page of users.
+func (s *CachedUserServer) listResponse(ctx context.Context, users []*models.User, total int, page, offset, limit int32) *pb.ListUsersResponse {
+	defer timing.Track(ctx, timing.StageSerialization)()
+	pbUsers := make([]*pb.User, len(users))
+	for i, user := range users {
+		pbUsers[i] = user.ToProto()
+	}
+	return &pb.ListUsersResponse{
+		Users:         pbUsers,
+		Total:         int32(total),
+		Message:       s.message(fmt.Sprintf("Retrieved %d users (page %d)", len(pbUsers), page)),
+		Limit:         limit,
+		NextPageToken: nextPageToken(offset, len(pbUsers), total),
+		Outcome:       pb.Outcome_OUTCOME_RETRIEVED,
+		Page:          page,
+	}
+}
+
+// invalidateSearchCache drops cached search results after a write that may
+// change which users match a search but leaves list pages alone: they hold
+// IDs, which such writes don't change.
+func (s *CachedUserServer) invalidateSearchCache(ctx context.Context) {
+	ctx, span := s.tracer.Start(ctx, "cache.invalidate_search",
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			attribute.String("cache.operation", "invalidate_search"),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	invalidatedCount := 1
+	key, err := s.rotateGeneration(ctx, searchGenerationKey)
+	if err != nil {
+		invalidatedCount = 0
+		span.RecordError(err)
+	}
+	s.broadcastInvalidation(ctx, []string{key}, nil)
+	s.invalidation.record(ctx, span, scopeSearch, invalidatedCount, time.Since(start), err)
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"grpc-server/internal/cache/cachetest"
+	"grpc-server/internal/clock"
+	"grpc-server/internal/repository/memory"
+	pb "grpc-server/pkg/pb"
+)
+
+func newTestServer(t *testing.T) (*CachedUserServer, *cachetest.Cache) {
+	t.Helper()
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := cachetest.New(clk)
+	repo := memory.NewUserRepository(memory.WithClock(clk))
+	return NewCachedUserServer(repo, c, slog.New(slog.DiscardHandler), WithClock(clk)), c
+}
+
+func listPageKeys(c *cachetest.Cache) []string {
+	var keys []string
+	for _, key := range c.Keys() {
+		if strings.HasPrefix(key, userListCachePrefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Writes delete every cached list page, whatever its offset and limit.
+func TestWritesDeleteListPages(t *testing.T) {
+	s, c := newTestServer(t)
+	ctx := context.Background()
+
+	if _, err := s.CreateUser(ctx, &pb.CreateUserRequest{Name: "Ada", Email: "ada@example.com", Age: 36}); err != nil {
+		t.Fatal(err)
+	}
+	for _, limit := range []int32{10, 20} {
+		list, err := s.ListUsers(ctx, &pb.ListUsersRequest{Limit: limit})
+		if err != nil || list.Total != 1 {
+			t.Fatalf("ListUsers(limit %d) = %v, %v; want 1 user", limit, list, err)
+		}
+	}
+	if keys := listPageKeys(c); len(keys) != 2 {
+		t.Fatalf("cached list pages = %v, want 2", keys)
+	}
+
+	if _, err := s.CreateUser(ctx, &pb.CreateUserRequest{Name: "Grace", Email: "grace@example.com", Age: 45}); err != nil {
+		t.Fatal(err)
+	}
+	if keys := listPageKeys(c); len(keys) != 0 {
+		t.Fatalf("cached list pages after a create = %v, want none", keys)
+	}
+	list, err := s.ListUsers(ctx, &pb.ListUsersRequest{Limit: 10})
+	if err != nil || list.Total != 2 || len(list.Users) != 2 {
+		t.Fatalf("ListUsers after a create = %v, %v; want 2 users", list, err)
+	}
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"grpc-server/internal/audit"
+	"grpc-server/internal/events"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/models"
+	"grpc-server/internal/repository"
+	pb "grpc-server/pkg/pb"
+)
+
+// MergeUsers folds a duplicate user into the surviving one, typically after
+// email normalization reveals that both belong to the same person. Profile
+// fields are consolidated according to req.ConflictPolicy and the source is
+// soft-deleted with merged_into pointing at the target. Services holding
+// records keyed by the source ID re-point them on the user.merged event.
+func (s *CachedUserServer) MergeUsers(ctx context.Context, req *pb.MergeUsersRequest) (*pb.MergeUsersResponse, error) {
+	logging.FromContext(ctx).DebugCtx(ctx, "MergeUsers request received", "source_id", req.SourceId, "target_id", req.TargetId, "policy", req.ConflictPolicy)
+
+	if req.SourceId == req.TargetId {
+		return nil, selfMergeError(req.SourceId)
+	}
+
+	// Check both users up front so errors name the offending one; Merge
+	// re-checks under lock.
+	for _, id := range []string{req.SourceId, req.TargetId} {
+		user, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			if err == repository.ErrUserNotFound {
+				logging.FromContext(ctx).InfoCtx(ctx, "User not found for merge", logging.UserID, id)
+				return nil, userNotFoundError(id)
+			}
+			logging.FromContext(ctx).ErrorCtx(ctx, "Failed to get user for merge from repository", logging.UserID, id, logging.Error, err)
+			return nil, repositoryError(err, "merge_users", id, "failed to retrieve user")
+		}
+		if user.Status == models.StatusMerged {
+			return nil, userMergedError(id)
+		}
+	}
+
+	target, _, err := s.repo.Merge(ctx, req.SourceId, req.TargetId, mergePolicy(req.ConflictPolicy))
+	if err != nil {
+		switch err {
+		case repository.ErrUserNotFound:
+			return nil, userNotFoundError(req.SourceId)
+		case repository.ErrUserMerged:
+			return nil, userMergedError(req.SourceId)
+		case repository.ErrCrossShard:
+			return nil, crossShardMergeError(req.SourceId, req.TargetId)
+		}
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to merge users in repository", "source_id", req.SourceId, "target_id", req.TargetId, logging.Error, err)
+		return nil, repositoryError(err, "merge_users", req.TargetId, "failed to merge users")
+	}
+
+	// Both entries go in one delete so readers never see the target updated
+	// while the source still looks active, or the other way round.
+	if err := s.invalidateUsers(ctx, req.SourceId, req.TargetId); err != nil {
+		logging.FromContext(ctx).WarnCtx(ctx, "Failed to invalidate merged users in cache", "source_id", req.SourceId, "target_id", req.TargetId, logging.Error, err)
+	}
+	s.invalidateListCache(ctx)
+
+	auditEntryID := uuid.New().String()
+	s.audit.Record(ctx, audit.ActionUserMerged,
+		slog.String("audit_entry_id", auditEntryID),
+		slog.String("source_id", req.SourceId),
+		slog.String("target_id", req.TargetId),
+		slog.String("conflict_policy", req.ConflictPolicy.String()),
+		slog.String("reason", req.Reason),
+		slog.String(logging.TraceID, trace.SpanContextFromContext(ctx).TraceID().String()),
+	)
+
+	event := events.New(ctx, events.TypeUserMerged, req.SourceId, map[string]string{"merged_into": req.TargetId})
+	if err := s.events.Publish(ctx, event); err != nil {
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to publish merge event", "source_id", req.SourceId, logging.Error, err)
+	}
+
+	logging.FromContext(ctx).InfoCtx(ctx, "Users merged successfully", "source_id", req.SourceId, "target_id", req.TargetId, "audit_entry_id", auditEntryID)
+
+	return &pb.MergeUsersResponse{
+		User:         target.ToProto(),
+		AuditEntryId: auditEntryID,
+		Message:      s.message("Users merged successfully"),
+		Outcome:      pb.Outcome_OUTCOME_MERGED,
+	}, nil
+}
+
+func mergePolicy(policy pb.MergeConflictPolicy) models.MergePolicy {
+	switch policy {
+	case pb.MergeConflictPolicy_MERGE_CONFLICT_POLICY_PREFER_SOURCE:
+		return models.MergePreferSource
+	case pb.MergeConflictPolicy_MERGE_CONFLICT_POLICY_NEWEST:
+		return models.MergeNewest
+	default:
+		return models.MergeKeepTarget
+	}
+}
@@ -0,0 +1,19 @@
+package server
+
+// WithoutResponseMessages leaves the deprecated human-readable message
+// fields of responses empty, so clients cannot come to depend on their
+// wording; they read the outcome and counts instead.
+func WithoutResponseMessages() Option {
+	return func(s *CachedUserServer) {
+		s.omitMessages = true
+	}
+}
+
+// message returns text for a response's message field, or nothing when
+// messages are turned off.
+func (s *CachedUserServer) message(text string) string {
+	if s.omitMessages {
+		return ""
+	}
+	return text
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"grpc-server/pkg/serviceconfig"
+)
+
+// MethodConfigInterceptor applies the per-method timeout from the service config
+// published with the client SDK, so callers that dial without it (or send no
+// deadline at all) still get the same budget the SDK would enforce.
+func MethodConfigInterceptor(sc *serviceconfig.ServiceConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		mc := sc.Lookup(info.FullMethod)
+		if mc == nil || mc.Timeout <= 0 {
+			return handler(ctx, req)
+		}
+
+		timeout := time.Duration(mc.Timeout)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= timeout {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
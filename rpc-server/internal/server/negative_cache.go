@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"grpc-server/internal/cache"
+	"grpc-server/internal/logging"
+)
+
+// missingUserCachePrefix keys the entries that mark a user ID as not found.
+// It is its own namespace, so cache stats tell negative hits apart.
+const missingUserCachePrefix = "user:missing:"
+
+// WithNegativeCaching remembers for ttl that a user ID was not found, so
+// repeated GetUser calls for deleted or made-up IDs don't each query the
+// database. Creating the user forgets it. A create racing with the lookup
+// that found the ID missing can leave it reported missing for up to ttl,
+// so keep ttl short.
+func WithNegativeCaching(ttl time.Duration) Option {
+	return func(s *CachedUserServer) {
+		s.negativeTTL = ttl
+	}
+}
+
+func (s *CachedUserServer) missingUserCacheKey(ctx context.Context, id string) string {
+	return s.keys.Key(ctx, missingUserCachePrefix, id)
+}
+
+// userKnownMissing reports whether id was recently found not to exist. A
+// failed lookup is logged and reported as unknown, sending the caller to
+// the database.
+func (s *CachedUserServer) userKnownMissing(ctx context.Context, id string) bool {
+	if s.negativeTTL <= 0 {
+		return false
+	}
+	key := s.missingUserCacheKey(ctx, id)
+	_, err := s.cache.Get(ctx, key)
+	if err == nil {
+		logging.FromContext(ctx).DebugCtx(ctx, "Negative cache hit for user", logging.UserID, id)
+		return true
+	}
+	if err != cache.ErrCacheMiss {
+		logging.FromContext(ctx).WarnCtx(ctx, "Negative cache get failed", logging.CacheKey, key, logging.Error, err)
+	}
+	return false
+}
+
+// rememberMissingUser records that id was not found in the database.
+func (s *CachedUserServer) rememberMissingUser(ctx context.Context, id string) {
+	if s.negativeTTL <= 0 {
+		return
+	}
+	key := s.missingUserCacheKey(ctx, id)
+	if err := s.cache.Set(ctx, key, "1", s.negativeTTL); err != nil {
+		logging.FromContext(ctx).WarnCtx(ctx, "Failed to cache missing user", logging.CacheKey, key, logging.Error, err)
+	}
+}
+
+// forgetMissingUser drops the not-found marker of a user just created, here
+// and in the other instances' local caches.
+func (s *CachedUserServer) forgetMissingUser(ctx context.Context, id string) {
+	if s.negativeTTL <= 0 {
+		return
+	}
+	key := s.missingUserCacheKey(ctx, id)
+	if err := s.cache.Delete(ctx, key); err != nil {
+		logging.FromContext(ctx).WarnCtx(ctx, "Failed to drop cached missing user", logging.CacheKey, key, logging.Error, err)
+	}
+	s.broadcastInvalidation(ctx, []string{key}, nil)
+}
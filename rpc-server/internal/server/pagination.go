@@ -0,0 +1,71 @@
+package server
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"grpc-server/internal/validation"
+	pb "grpc-server/pkg/pb"
+)
+
+const (
+	defaultPageSize    = 10
+	defaultMaxPageSize = 100
+)
+
+// WithPageSizes sets the ListUsers page size used when a request has no
+// limit, and the largest limit a request may ask for; larger ones are
+// rejected. The defaults are defaultPageSize and defaultMaxPageSize.
+func WithPageSizes(defaultSize, maxSize int) Option {
+	return func(s *CachedUserServer) {
+		s.defaultPageSize = int32(defaultSize)
+		s.maxPageSize = int32(maxSize)
+	}
+}
+
+// pagination returns the page, effective limit and offset of req, which
+// list and search requests alike are paged by. A page token takes
+// precedence over the page number.
+func (s *CachedUserServer) pagination(req *pb.ListUsersRequest) (page, limit, offset int32, err error) {
+	if req.PageToken == "" {
+		page, limit, err = validation.NormalizePage(req.Page, req.Limit, s.defaultPageSize, s.maxPageSize)
+		if err != nil {
+			return 0, 0, 0, status.Error(grpc_codes.InvalidArgument, err.Error())
+		}
+		return page, limit, (page - 1) * limit, nil
+	}
+	limit, err = validation.NormalizeLimit(req.Limit, s.defaultPageSize, s.maxPageSize)
+	if err != nil {
+		return 0, 0, 0, status.Error(grpc_codes.InvalidArgument, err.Error())
+	}
+	offset, err = decodePageToken(req.PageToken)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return offset/limit + 1, limit, offset, nil
+}
+
+// nextPageToken returns the token for the page after the one at offset, or
+// "" if that was the last page.
+func nextPageToken(offset int32, returned, total int) string {
+	next := int(offset) + returned
+	if returned == 0 || next >= total {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(next)))
+}
+
+func decodePageToken(token string) (int32, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		var offset int64
+		offset, err = strconv.ParseInt(string(raw), 10, 32)
+		if err == nil && offset >= 0 {
+			return int32(offset), nil
+		}
+	}
+	return 0, status.Error(grpc_codes.InvalidArgument, "page_token is not a token returned by ListUsers")
+}
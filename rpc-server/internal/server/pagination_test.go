@@ -0,0 +1,44 @@
+package server
+
+import (
+	"math"
+	"testing"
+
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func FuzzDecodePageToken(f *testing.F) {
+	for _, token := range []string{
+		"",
+		nextPageToken(0, 10, 100),
+		nextPageToken(math.MaxInt32-1, 1, math.MaxInt32),
+		"MTA",  // "10"
+		"LTE",  // "-1"
+		"MTA=", // padded
+		"not a token",
+		"OTk5OTk5OTk5OTk5", // past int32
+	} {
+		f.Add(token)
+	}
+	f.Fuzz(func(t *testing.T, token string) {
+		offset, err := decodePageToken(token)
+		if err != nil {
+			if status.Code(err) != grpc_codes.InvalidArgument {
+				t.Fatalf("decodePageToken(%q) = %v, want InvalidArgument", token, err)
+			}
+			return
+		}
+		if offset < 0 {
+			t.Fatalf("decodePageToken(%q) = %d, want a non-negative offset", token, offset)
+		}
+		// Tokens ListUsers hands out decode to the offset they encode.
+		if offset > 0 {
+			if next := nextPageToken(0, int(offset), int(offset)+1); next != "" {
+				if got, err := decodePageToken(next); err != nil || got != offset {
+					t.Fatalf("token for offset %d decodes to %d, %v", offset, got, err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	pb "grpc-server/pkg/pb"
+)
+
+// userMask is the set of User fields a caller asked for; nil keeps them all.
+// Masks are applied to responses, so cached entries always hold whole users.
+type userMask map[protoreflect.Name]bool
+
+// readMask validates the read_mask of a GetUser or ListUsers request. Its
+// paths are top-level User fields; a nil or empty mask keeps them all.
+func readMask(m *fieldmaskpb.FieldMask) (userMask, error) {
+	paths := m.GetPaths()
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	fields := (&pb.User{}).ProtoReflect().Descriptor().Fields()
+	mask := make(userMask, len(paths))
+	for _, path := range paths {
+		name := protoreflect.Name(path)
+		if fields.ByName(name) == nil {
+			return nil, status.Errorf(grpc_codes.InvalidArgument, "read_mask: %q is not a user field", path)
+		}
+		mask[name] = true
+	}
+	return mask, nil
+}
+
+// apply clears the fields of user that m doesn't name.
+func (m userMask) apply(user *pb.User) {
+	if m == nil || user == nil {
+		return
+	}
+	msg := user.ProtoReflect()
+	fields := msg.Descriptor().Fields()
+	for i := range fields.Len() {
+		if fd := fields.Get(i); !m[fd.Name()] {
+			msg.Clear(fd)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"testing"
+
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	pb "grpc-server/pkg/pb"
+)
+
+func TestReadMask(t *testing.T) {
+	user := func() *pb.User {
+		return &pb.User{Id: "id", Name: "Ada", Email: "ada@example.com", Age: 36}
+	}
+	for _, tt := range []struct {
+		name    string
+		mask    *fieldmaskpb.FieldMask
+		want    *pb.User
+		wantErr bool
+	}{
+		{"no mask", nil, user(), false},
+		{"empty mask", &fieldmaskpb.FieldMask{}, user(), false},
+		{"some fields", &fieldmaskpb.FieldMask{Paths: []string{"id", "name"}}, &pb.User{Id: "id", Name: "Ada"}, false},
+		{"unknown field", &fieldmaskpb.FieldMask{Paths: []string{"id", "password"}}, nil, true},
+		{"JSON name", &fieldmaskpb.FieldMask{Paths: []string{"createdAt"}}, nil, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mask, err := readMask(tt.mask)
+			if tt.wantErr {
+				if status.Code(err) != grpc_codes.InvalidArgument {
+					t.Fatalf("readMask() = %v, want InvalidArgument", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := user()
+			mask.apply(got)
+			if got.Id != tt.want.Id || got.Name != tt.want.Name || got.Email != tt.want.Email || got.Age != tt.want.Age {
+				t.Errorf("masked user = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"grpc-server/internal/cache"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/models"
+	"grpc-server/internal/repository"
+	"grpc-server/internal/timing"
+)
+
+// Read race winners, recorded as the winner attribute.
+const (
+	raceWinnerCache    = "cache"
+	raceWinnerDatabase = "database"
+	raceWinnerNone     = "none"
+)
+
+func newReadRaceCounter() metric.Int64Counter {
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	races, _ := otel.Meter("rpc-server.rpc/server").Int64Counter("cache.read_races",
+		metric.WithDescription("GetUser calls that read the cache and the database at once, by which found the user first"),
+		metric.WithUnit("{race}"),
+	)
+	return races
+}
+
+// raceUserReads reports whether GetUser should race the cache against the
+// database. Strong reads skip the cache anyway, and while the database is
+// down there is nothing to race.
+func (s *CachedUserServer) raceUserReads(strong bool) bool {
+	return s.runtime != nil && s.runtime.Load().RaceUserReads && !strong && !s.degraded()
+}
+
+// raceResult is what one side of a read race found.
+type raceResult struct {
+	winner   string
+	user     *models.User
+	cachedAt int64 // of a cache entry, see cachedUser
+	err      error
+}
+
+// raceUserRead reads id from the cache and the database at once and returns
+// the user from whichever finds it first, cancelling the other. A cache miss
+// waits for the database, and a database error for the cache. The cache is
+// only filled when it was seen to miss, so a slow cache isn't sent a write
+// per call.
+func (s *CachedUserServer) raceUserRead(ctx context.Context, id, cacheKey string) (*models.User, error) {
+	ctx, span := s.tracer.Start(ctx, "cache.read_race", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	raceCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan raceResult, 2)
+	go func() {
+		data, err := s.cache.Get(raceCtx, cacheKey)
+		if err != nil {
+			results <- raceResult{winner: raceWinnerCache, err: err}
+			return
+		}
+		var entry cachedUser
+		stopSerialization := timing.Track(ctx, timing.StageSerialization)
+		err = json.Unmarshal(data, &entry)
+		stopSerialization()
+		results <- raceResult{winner: raceWinnerCache, user: &entry.User, cachedAt: entry.CachedAt, err: err}
+	}()
+	go func() {
+		user, err := s.repo.GetByID(raceCtx, id)
+		results <- raceResult{winner: raceWinnerDatabase, user: user, err: err}
+	}()
+
+	var cacheErr, dbErr error
+	for range 2 {
+		r := <-results
+		if r.err != nil {
+			if r.winner == raceWinnerCache {
+				cacheErr = r.err
+			} else {
+				dbErr = r.err
+			}
+			continue
+		}
+		cancel()
+		s.readRaces.Add(ctx, 1, metric.WithAttributes(attribute.String("winner", r.winner)))
+		span.SetAttributes(attribute.String("cache.read_race.winner", r.winner))
+		logging.FromContext(ctx).DebugCtx(ctx, "Read race won", logging.UserID, id, "winner", r.winner)
+		if r.winner == raceWinnerCache {
+			s.refreshUserTTL(ctx, cacheKey, r.cachedAt)
+		} else if cacheErr == cache.ErrCacheMiss {
+			if err := s.cacheUser(ctx, r.user); err != nil {
+				logging.FromContext(ctx).WarnCtx(ctx, "Failed to cache user", logging.UserID, id, logging.Error, err)
+			}
+		}
+		return r.user, nil
+	}
+
+	s.readRaces.Add(ctx, 1, metric.WithAttributes(attribute.String("winner", raceWinnerNone)))
+	span.SetAttributes(attribute.String("cache.read_race.winner", raceWinnerNone))
+	if cacheErr != cache.ErrCacheMiss {
+		logging.FromContext(ctx).WarnCtx(ctx, "Cache get failed", logging.UserID, id, logging.Error, cacheErr)
+	}
+	if dbErr == repository.ErrUserNotFound {
+		logging.FromContext(ctx).InfoCtx(ctx, "User not found", logging.UserID, id)
+		s.rememberMissingUser(ctx, id)
+		return nil, userNotFoundError(id)
+	}
+	span.RecordError(dbErr)
+	logging.FromContext(ctx).ErrorCtx(ctx, "Failed to get user from repository", logging.UserID, id, logging.Error, dbErr)
+	return nil, repositoryError(dbErr, "get_user", id, "failed to retrieve user")
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"grpc-server/internal/audit"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/repository"
+	pb "grpc-server/pkg/pb"
+)
+
+// RevertUser restores a user's fields from one of its history versions, as
+// returned by GetUserAtTime, and records the revert in the audit log. The
+// restored state becomes a new history version, so a revert can itself be
+// reverted.
+func (s *CachedUserServer) RevertUser(ctx context.Context, req *pb.RevertUserRequest) (*pb.RevertUserResponse, error) {
+	logging.FromContext(ctx).DebugCtx(ctx, "RevertUser request received", logging.UserID, req.Id, "version_id", req.VersionId)
+
+	user, err := s.repo.Revert(ctx, req.Id, req.VersionId)
+	if err != nil {
+		switch err {
+		case repository.ErrUserNotFound:
+			logging.FromContext(ctx).InfoCtx(ctx, "User not found for revert", logging.UserID, req.Id)
+			return nil, userNotFoundError(req.Id)
+		case repository.ErrVersionNotFound:
+			logging.FromContext(ctx).InfoCtx(ctx, "User version not found for revert", logging.UserID, req.Id, "version_id", req.VersionId)
+			return nil, versionNotFoundError(req.Id, req.VersionId)
+		case repository.ErrEmailExists:
+			email := s.versionEmail(ctx, req.Id, req.VersionId)
+			logging.FromContext(ctx).WarnCtx(ctx, "Reverted email now belongs to a different user", logging.UserEmail, email, logging.UserID, req.Id)
+			return nil, emailExistsError(email)
+		}
+		logging.FromContext(ctx).ErrorCtx(ctx, "Failed to revert user in repository", logging.UserID, req.Id, logging.Error, err)
+		return nil, repositoryError(err, "revert_user", req.Id, "failed to revert user")
+	}
+
+	s.userWritten(ctx, user)
+	s.invalidateListCache(ctx)
+
+	auditEntryID := uuid.New().String()
+	s.audit.Record(ctx, audit.ActionUserReverted,
+		slog.String("audit_entry_id", auditEntryID),
+		slog.String(logging.UserID, req.Id),
+		slog.Int64("version_id", req.VersionId),
+		slog.String("reason", req.Reason),
+		slog.String(logging.TraceID, trace.SpanContextFromContext(ctx).TraceID().String()),
+	)
+
+	logging.FromContext(ctx).InfoCtx(ctx, "User reverted successfully", logging.UserID, req.Id, "version_id", req.VersionId, "audit_entry_id", auditEntryID)
+
+	return &pb.RevertUserResponse{
+		User:         user.ToProto(),
+		AuditEntryId: auditEntryID,
+		Message:      s.message("User reverted successfully"),
+		Outcome:      pb.Outcome_OUTCOME_REVERTED,
+	}, nil
+}
+
+// versionEmail looks up the email of a history version for error reporting,
+// returning "" if it cannot be found.
+func (s *CachedUserServer) versionEmail(ctx context.Context, id string, versionID int64) string {
+	versions, err := s.repo.History(ctx, id)
+	if err != nil {
+		return ""
+	}
+	for _, v := range versions {
+		if v.VersionID == versionID {
+			return v.Email
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"grpc-server/internal/auth"
+	"grpc-server/internal/ratelimit"
+	"grpc-server/internal/runtimeconfig"
+	pb "grpc-server/pkg/pb"
+)
+
+// writeMethods are the calls rejected in read-only mode.
+var writeMethods = map[string]bool{
+	pb.UserService_CreateUser_FullMethodName:         true,
+	pb.UserService_UpdateUser_FullMethodName:         true,
+	pb.UserService_RequestEmailChange_FullMethodName: true,
+	pb.UserService_ConfirmEmailChange_FullMethodName: true,
+	pb.UserService_DeleteUser_FullMethodName:         true,
+	pb.UserService_EraseUser_FullMethodName:          true,
+	pb.UserService_RevertUser_FullMethodName:         true,
+	pb.UserService_MergeUsers_FullMethodName:         true,
+}
+
+// While the rate limit is on, every call it admits or rejects carries these
+// response headers, named after the IETF RateLimit fields, so clients can
+// pace themselves before they are rejected. Times are whole seconds, rounded
+// up. The REST gateway passes them on as HTTP headers.
+const (
+	rateLimitLimitMetadataKey     = "ratelimit-limit"
+	rateLimitRemainingMetadataKey = "ratelimit-remaining"
+	rateLimitResetMetadataKey     = "ratelimit-reset"
+	retryAfterMetadataKey         = "retry-after"
+)
+
+// RuntimeConfigInterceptor enforces read-only mode and the server-wide rate
+// limit from the current runtime config. The rate limit sheds callers by
+// priority class, so batch and load-test traffic is rejected before
+// interactive traffic, and tells every caller how much of it is left. Admin
+// calls are exempt from both so operators can always undo a change.
+func RuntimeConfigInterceptor(store *runtimeconfig.Store) grpc.UnaryServerInterceptor {
+	shed := newShedCounter()
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := admitByRuntimeConfig(ctx, store, info.FullMethod, shed); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RuntimeConfigStreamInterceptor is RuntimeConfigInterceptor for streaming
+// calls. Opening a stream counts as one call against the rate limit.
+func RuntimeConfigStreamInterceptor(store *runtimeconfig.Store) grpc.StreamServerInterceptor {
+	shed := newShedCounter()
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := admitByRuntimeConfig(ss.Context(), store, info.FullMethod, shed); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func newShedCounter() metric.Int64Counter {
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	shed, _ := otel.Meter("rpc-server.rpc/server").Int64Counter("rpc.requests.shed",
+		metric.WithDescription("Number of requests rejected by the rate limit"),
+		metric.WithUnit("{request}"),
+	)
+	return shed
+}
+
+// admitByRuntimeConfig returns the status error to reject a call to
+// fullMethod with, if read-only mode or the rate limit turns it away.
+func admitByRuntimeConfig(ctx context.Context, store *runtimeconfig.Store, fullMethod string, shed metric.Int64Counter) error {
+	if strings.HasPrefix(fullMethod, "/"+pb.AdminService_ServiceDesc.ServiceName+"/") {
+		return nil
+	}
+	if store.Load().ReadOnly && writeMethods[fullMethod] {
+		return readOnlyError(fullMethod)
+	}
+	class := store.Class(callerID(ctx))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("rpc.priority_class", class.String()))
+	quota := store.Allow(class)
+	setRateLimitHeaders(ctx, quota)
+	if !quota.Allowed {
+		shed.Add(ctx, 1, metric.WithAttributes(attribute.String("priority_class", class.String())))
+		return rateLimitedError(quota.RetryAfter, class)
+	}
+	return nil
+}
+
+// callerID identifies the caller for priority classes: the subject of its
+// verified token, or without one the API key or address the client rate
+// limit keys it by. Anything a caller merely claims, such as its
+// x-tenant-id, would let it pick its own class; an API key's hash only
+// matches for callers holding the key.
+func callerID(ctx context.Context) string {
+	if claims := auth.FromContext(ctx); claims != nil {
+		return claims.Subject
+	}
+	id, _ := ratelimit.Identify(ctx, ratelimit.KeyByAPIKey)
+	return id
+}
+
+// setRateLimitHeaders sets the rate limit response headers for quota, if
+// the rate limit is on.
+func setRateLimitHeaders(ctx context.Context, quota runtimeconfig.Quota) {
+	if quota.Limit == 0 {
+		return
+	}
+	md := metadata.Pairs(
+		rateLimitLimitMetadataKey, strconv.Itoa(quota.Limit),
+		rateLimitRemainingMetadataKey, strconv.Itoa(quota.Remaining),
+		rateLimitResetMetadataKey, ceilSeconds(quota.Reset),
+	)
+	if !quota.Allowed {
+		md.Set(retryAfterMetadataKey, ceilSeconds(quota.RetryAfter))
+	}
+	_ = grpc.SetHeader(ctx, md)
+}
+
+// ceilSeconds formats d as whole seconds, rounded up so a client that waits
+// that long is not early.
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
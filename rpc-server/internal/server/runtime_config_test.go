@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"grpc-server/internal/auth"
+)
+
+func TestCallerID(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 1234}
+	for _, tt := range []struct {
+		name   string
+		claims *auth.Claims
+		md     metadata.MD
+		want   string
+	}{
+		{"token subject", &auth.Claims{Subject: "batch-job"}, metadata.Pairs("x-api-key", "secret"), "batch-job"},
+		{"API key without a token", nil, metadata.Pairs("x-api-key", "secret"), "key:2bb80d537b1da3e3"},
+		{"address without a token or key", nil, metadata.Pairs("x-tenant-id", "claimed"), "ip:192.0.2.1"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			ctx = metadata.NewIncomingContext(ctx, tt.md)
+			if tt.claims != nil {
+				ctx = auth.WithClaims(ctx, tt.claims)
+			}
+			if got := callerID(ctx); got != tt.want {
+				t.Errorf("callerID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
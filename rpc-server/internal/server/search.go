@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"grpc-server/internal/repository"
+	pb "grpc-server/pkg/pb"
+)
+
+const (
+	// userSearchCachePrefix holds pages of IDs, like userListCachePrefix.
+	userSearchCachePrefix = "users:search:ids:"
+	// searchGenerationKey holds the generation of search results; see
+	// cacheGeneration.
+	searchGenerationKey = "users:search:generation"
+
+	maxSearchPrefixLength = 255
+)
+
+// prefixFilter validates and normalizes the search prefixes of req.
+func prefixFilter(req *pb.ListUsersRequest) (repository.PrefixFilter, error) {
+	filter := repository.PrefixFilter{
+		NamePrefix:  strings.ToLower(strings.TrimSpace(req.NamePrefix)),
+		EmailPrefix: strings.ToLower(strings.TrimSpace(req.EmailPrefix)),
+	}
+	if len(filter.NamePrefix) > maxSearchPrefixLength || len(filter.EmailPrefix) > maxSearchPrefixLength {
+		return filter, status.Errorf(grpc_codes.InvalidArgument, "name_prefix and email_prefix must not exceed %d bytes", maxSearchPrefixLength)
+	}
+	return filter, nil
+}
+
+// searchCacheKey returns the cache key for a search page in the current
+// generation. It returns "" if the generation can't be read, in which case
+// the search must not be cached.
+func (s *CachedUserServer) searchCacheKey(ctx context.Context, filter repository.PrefixFilter, offset, limit int) string {
+	generation, ok := s.cacheGeneration(ctx, searchGenerationKey)
+	if !ok {
+		return ""
+	}
+	return s.keys.Key(ctx, userSearchCachePrefix, generation, filter.NamePrefix, filter.EmailPrefix,
+		strconv.Itoa(offset), strconv.Itoa(limit))
+}
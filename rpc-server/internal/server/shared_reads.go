@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+
+	"grpc-server/internal/logging"
+)
+
+func newCollapsedReadCounter() metric.Int64Counter {
+	// Instrument constructors only fail on invalid names; the returned
+	// instrument is a usable no-op either way.
+	collapsed, _ := otel.Meter("rpc-server.rpc/server").Int64Counter("db.reads.collapsed",
+		metric.WithDescription("Number of cache misses answered with the result of an identical concurrent database read"),
+		metric.WithUnit("{read}"),
+	)
+	return collapsed
+}
+
+// sharedRead runs load for the first caller to miss cacheKey; callers
+// missing it while load runs wait for its result instead of querying the
+// database again, so an invalidated hot key costs one query rather than one
+// per caller. load should fill the cache itself.
+//
+// A caller that gives up while waiting returns its own context error. If the
+// caller running load gave up instead, the others try again together rather
+// than fail with its cancellation.
+func (s *CachedUserServer) sharedRead(ctx context.Context, operation, cacheKey string, load func(ctx context.Context) (any, error)) (any, error) {
+	for {
+		loaded := false
+		results := s.reads.DoChan(cacheKey, func() (any, error) {
+			loaded = true
+			return load(ctx)
+		})
+		var r singleflight.Result
+		select {
+		case r = <-results:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if loaded {
+			return r.Val, r.Err
+		}
+		if errors.Is(r.Err, context.Canceled) || errors.Is(r.Err, context.DeadlineExceeded) {
+			continue
+		}
+
+		s.collapsedReads.Add(ctx, 1, metric.WithAttributes(attribute.String("operation", operation)))
+		trace.SpanFromContext(ctx).AddEvent("db.read_collapsed", trace.WithAttributes(attribute.String("cache.key", cacheKey)))
+		logging.FromContext(ctx).DebugCtx(ctx, "Collapsed database read into a concurrent identical one", logging.CacheKey, cacheKey)
+		return r.Val, r.Err
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"grpc-server/internal/logging"
+	pb "grpc-server/pkg/pb"
+)
+
+// redactedMetadata lists keys whose values TestEcho never returns.
+var redactedMetadata = []string{"authorization", "cookie", "proxy-authorization", "x-api-key"}
+
+// compressionReporter is implemented by grpc-go's server transport stream;
+// the negotiated encodings are not otherwise visible to handlers because
+// grpc-encoding is a reserved header.
+type compressionReporter interface {
+	RecvCompress() string
+	SendCompress() string
+}
+
+func (s *TestServer) TestEcho(ctx context.Context, req *pb.TestEchoRequest) (*pb.TestEchoResponse, error) {
+	resp := &pb.TestEchoResponse{
+		Message:    req.Message,
+		InstanceId: s.instanceID,
+		TraceId:    trace.SpanFromContext(ctx).SpanContext().TraceID().String(),
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		resp.Metadata = echoMetadata(md)
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			resp.PeerAddress = p.Addr.String()
+		}
+		if p.AuthInfo != nil {
+			resp.PeerAuthType = p.AuthInfo.AuthType()
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		resp.DeadlineUnixMs = deadline.UnixMilli()
+		resp.DeadlineRemainingMs = time.Until(deadline).Milliseconds()
+	}
+	if stream := grpc.ServerTransportStreamFromContext(ctx); stream != nil {
+		resp.Method = stream.Method()
+		if c, ok := stream.(compressionReporter); ok {
+			resp.RequestCompression = c.RecvCompress()
+			resp.ResponseCompression = c.SendCompress()
+		}
+	}
+	if accepted, err := grpc.ClientSupportedCompressors(ctx); err == nil {
+		resp.AcceptedCompression = accepted
+	}
+
+	logging.FromContext(ctx).InfoCtx(ctx, "TestEcho request received", "peer", resp.PeerAddress, "metadata_keys", len(resp.Metadata))
+	return resp, nil
+}
+
+func echoMetadata(md metadata.MD) []*pb.MetadataEntry {
+	entries := make([]*pb.MetadataEntry, 0, md.Len())
+	for key, values := range md {
+		entry := &pb.MetadataEntry{Key: key}
+		switch {
+		case slices.Contains(redactedMetadata, key):
+			entry.Values = slices.Repeat([]string{"[REDACTED]"}, len(values))
+		case strings.HasSuffix(key, "-bin"):
+			for _, v := range values {
+				entry.Values = append(entry.Values, base64.StdEncoding.EncodeToString([]byte(v)))
+			}
+		default:
+			entry.Values = values
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b *pb.MetadataEntry) int { return strings.Compare(a.Key, b.Key) })
+	return entries
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"grpc-server/internal/logging"
+	pb "grpc-server/pkg/pb"
+)
+
+const (
+	// maxTestLatency bounds a single TestLatency sleep so the endpoint can't
+	// tie up a server goroutine indefinitely.
+	maxTestLatency = time.Minute
+	// maxTestLatencyCount bounds the number of TestLatencyStream responses.
+	maxTestLatencyCount = 100
+	// maxTestLatencyStream bounds how long a TestLatencyStream may ask to
+	// stay open, summed over its sleeps, so one call can't hold a goroutine
+	// and connection for count times maxTestLatency.
+	maxTestLatencyStream = 2 * time.Minute
+)
+
+func (s *TestServer) TestLatency(ctx context.Context, req *pb.TestLatencyRequest) (*pb.TestLatencyResponse, error) {
+	if err := validateTestLatency(req.DurationMs, req.JitterMs); err != nil {
+		return nil, err
+	}
+	remaining := deadlineRemaining(ctx)
+
+	logging.FromContext(ctx).InfoCtx(ctx, "TestLatency request received", "duration_ms", req.DurationMs, "jitter_ms", req.JitterMs, "deadline_remaining_ms", remaining.Milliseconds())
+
+	resp, err := s.sleep(ctx, req.DurationMs, req.JitterMs)
+	if err != nil {
+		return nil, err
+	}
+	resp.DeadlineRemainingMs = remaining.Milliseconds()
+	resp.Sequence = 1
+	return resp, nil
+}
+
+func (s *TestServer) TestLatencyStream(req *pb.TestLatencyStreamRequest, stream pb.UserService_TestLatencyStreamServer) error {
+	ctx := stream.Context()
+	if err := validateTestLatency(req.DurationMs, req.JitterMs); err != nil {
+		return err
+	}
+	if req.Count < 1 || req.Count > maxTestLatencyCount {
+		return status.Errorf(grpc_codes.InvalidArgument, "count must be between 1 and %d", maxTestLatencyCount)
+	}
+	if time.Duration(req.DurationMs+req.JitterMs)*time.Millisecond*time.Duration(req.Count) > maxTestLatencyStream {
+		return status.Errorf(grpc_codes.InvalidArgument, "count times duration_ms plus jitter_ms must not exceed %d", maxTestLatencyStream.Milliseconds())
+	}
+	remaining := deadlineRemaining(ctx)
+
+	logging.FromContext(ctx).InfoCtx(ctx, "TestLatencyStream request received", "duration_ms", req.DurationMs, "jitter_ms", req.JitterMs, "count", req.Count, "deadline_remaining_ms", remaining.Milliseconds())
+
+	for i := range req.Count {
+		resp, err := s.sleep(ctx, req.DurationMs, req.JitterMs)
+		if err != nil {
+			return err
+		}
+		resp.DeadlineRemainingMs = remaining.Milliseconds()
+		resp.Sequence = i + 1
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateTestLatency(durationMs, jitterMs int64) error {
+	if durationMs < 0 || jitterMs < 0 {
+		return status.Error(grpc_codes.InvalidArgument, "duration_ms and jitter_ms must not be negative")
+	}
+	if time.Duration(durationMs+jitterMs)*time.Millisecond > maxTestLatency {
+		return status.Errorf(grpc_codes.InvalidArgument, "duration_ms plus jitter_ms must not exceed %d", maxTestLatency.Milliseconds())
+	}
+	return nil
+}
+
+// deadlineRemaining returns the time left before ctx's deadline, or 0 if it
+// has none.
+func deadlineRemaining(ctx context.Context) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0
+	}
+	return time.Until(deadline)
+}
+
+// sleep waits for durationMs moved by up to jitterMs either way, returning
+// early with the context's status if it is done first.
+func (s *TestServer) sleep(ctx context.Context, durationMs, jitterMs int64) (*pb.TestLatencyResponse, error) {
+	requested := durationMs
+	if jitterMs > 0 {
+		requested += rand.Int64N(2*jitterMs+1) - jitterMs
+	}
+	requested = max(requested, 0)
+
+	start := time.Now()
+	timer := time.NewTimer(time.Duration(requested) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		logging.FromContext(ctx).InfoCtx(ctx, "TestLatency sleep interrupted", "requested_ms", requested, "slept_ms", time.Since(start).Milliseconds())
+		return nil, status.FromContextError(ctx.Err()).Err()
+	case <-timer.C:
+	}
+
+	return &pb.TestLatencyResponse{
+		RequestedMs: requested,
+		SleptMs:     time.Since(start).Milliseconds(),
+		TraceId:     trace.SpanFromContext(ctx).SpanContext().TraceID().String(),
+	}, nil
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "grpc-server/pkg/pb"
+)
+
+// fakeLatencyStream counts the responses TestLatencyStream sends.
+type fakeLatencyStream struct {
+	grpc.ServerStream
+	sent int
+}
+
+func (s *fakeLatencyStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeLatencyStream) Send(*pb.TestLatencyResponse) error {
+	s.sent++
+	return nil
+}
+
+func TestTestLatencyStreamLimits(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		req  *pb.TestLatencyStreamRequest
+		want grpc_codes.Code
+	}{
+		{"within the limits", &pb.TestLatencyStreamRequest{Count: 2, DurationMs: 1}, grpc_codes.OK},
+		{"no responses", &pb.TestLatencyStreamRequest{Count: 0}, grpc_codes.InvalidArgument},
+		{"too many responses", &pb.TestLatencyStreamRequest{Count: maxTestLatencyCount + 1}, grpc_codes.InvalidArgument},
+		{"sleep too long", &pb.TestLatencyStreamRequest{Count: 1, DurationMs: maxTestLatency.Milliseconds() + 1}, grpc_codes.InvalidArgument},
+		{"stream too long", &pb.TestLatencyStreamRequest{Count: 3, DurationMs: maxTestLatencyStream.Milliseconds() / 3, JitterMs: 1}, grpc_codes.InvalidArgument},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &fakeLatencyStream{}
+			err := NewTestServer(slog.New(slog.DiscardHandler)).TestLatencyStream(tt.req, stream)
+			if code := status.Code(err); code != tt.want {
+				t.Fatalf("code = %v, want %v (%v)", code, tt.want, err)
+			}
+			if err == nil && stream.sent != int(tt.req.Count) {
+				t.Errorf("sent %d responses, want %d", stream.sent, tt.req.Count)
+			}
+		})
+	}
+}
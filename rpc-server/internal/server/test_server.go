@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
 	"strconv"
 
 	"go.opentelemetry.io/otel"
@@ -19,12 +20,22 @@ type TestServer struct {
 	pb.UnimplementedUserServiceServer
 	logger *logging.Logger
 	tracer trace.Tracer
+	// instanceID identifies this replica in TestEcho responses; on
+	// Kubernetes the host name is the pod name.
+	instanceID string
+	// streamEnabled serves TestStream; see CombinedServer.EnableTestStream.
+	streamEnabled bool
 }
 
 func NewTestServer(logger *slog.Logger) *TestServer {
+	instanceID, err := os.Hostname()
+	if err != nil {
+		instanceID = "unknown"
+	}
 	return &TestServer{
-		logger: logging.New(logger),
-		tracer: otel.Tracer("rpc-server.rpc/server"),
+		logger:     logging.New(logger),
+		tracer:     otel.Tracer("rpc-server.rpc/server"),
+		instanceID: instanceID,
 	}
 }
 
@@ -37,7 +48,7 @@ func (s *TestServer) TestError(ctx context.Context, req *pb.TestErrorRequest) (*
 		statusCode = 500 // Default to 500 if invalid input
 	}
 
-	s.logger.InfoCtx(ctx, "TestError request received", "status_code", statusCode, "trace_id", traceID)
+	logging.FromContext(ctx).InfoCtx(ctx, "TestError request received", "status_code", statusCode, "trace_id", traceID)
 
 	// Map HTTP status codes to gRPC codes
 	var grpcCode grpc_codes.Code
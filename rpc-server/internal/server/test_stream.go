@@ -0,0 +1,110 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "grpc-server/pkg/pb"
+)
+
+const (
+	// maxTestStreamResponseSize bounds the payload allocated per response.
+	maxTestStreamResponseSize = 4 << 20
+	// maxTestStreamCount bounds the responses sent for a single message.
+	maxTestStreamCount = 1000
+	// maxTestStreamTotalBytes bounds the payload bytes sent over a whole
+	// stream, however many messages ask for more.
+	maxTestStreamTotalBytes = 64 << 20
+)
+
+// EnableTestStream serves TestStream, which otherwise answers UNIMPLEMENTED.
+// It lets any caller make the server send tens of MiB, so only enable it
+// where load tests run.
+func (s *CombinedServer) EnableTestStream() {
+	s.testServer.streamEnabled = true
+}
+
+func (s *TestServer) TestStream(stream pb.UserService_TestStreamServer) error {
+	ctx := stream.Context()
+	if !s.streamEnabled {
+		return status.Error(grpc_codes.Unimplemented, "TestStream is disabled")
+	}
+	s.logger.InfoCtx(ctx, "TestStream started")
+
+	var sequence, totalReceived, totalSent int64
+	defer func() {
+		s.logger.InfoCtx(ctx, "TestStream finished", "responses", sequence, "total_received_bytes", totalReceived, "total_sent_bytes", totalSent)
+	}()
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if req.ResponseSize < 0 || req.ResponseSize > maxTestStreamResponseSize {
+			return status.Errorf(grpc_codes.InvalidArgument, "response_size must be between 0 and %d", maxTestStreamResponseSize)
+		}
+		if req.Count < 0 || req.Count > maxTestStreamCount {
+			return status.Errorf(grpc_codes.InvalidArgument, "count must be between 0 and %d", maxTestStreamCount)
+		}
+		if req.ResponsesPerSecond < 0 {
+			return status.Error(grpc_codes.InvalidArgument, "responses_per_second must not be negative")
+		}
+		if totalSent+int64(max(req.Count, 1))*int64(req.ResponseSize) > maxTestStreamTotalBytes {
+			return status.Errorf(grpc_codes.ResourceExhausted, "a stream may send at most %d payload bytes", maxTestStreamTotalBytes)
+		}
+
+		received := int64(len(req.Payload))
+		totalReceived += received
+		sent, err := sendTestStream(stream, req, sequence, received, totalReceived, totalSent)
+		sequence += sent
+		totalSent += sent * int64(req.ResponseSize)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// sendTestStream answers req and returns how many responses were sent.
+func sendTestStream(stream pb.UserService_TestStreamServer, req *pb.TestStreamRequest, sequence, received, totalReceived, totalSent int64) (int64, error) {
+	ctx := stream.Context()
+	payload := make([]byte, req.ResponseSize)
+
+	var tick <-chan time.Time
+	if req.ResponsesPerSecond > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(req.ResponsesPerSecond))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	count := max(req.Count, 1)
+	for i := range int64(count) {
+		if tick != nil && i > 0 {
+			select {
+			case <-ctx.Done():
+				return i, status.FromContextError(ctx.Err()).Err()
+			case <-tick:
+			}
+		}
+
+		// Send blocks while the client's flow control window is full.
+		if err := stream.Send(&pb.TestStreamResponse{
+			Sequence:           sequence + i + 1,
+			Payload:            payload,
+			ReceivedBytes:      received,
+			TotalReceivedBytes: totalReceived,
+			TotalSentBytes:     totalSent + (i+1)*int64(len(payload)),
+		}); err != nil {
+			return i, err
+		}
+	}
+	return int64(count), nil
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "grpc-server/pkg/pb"
+)
+
+// fakeTestStream feeds requests to TestStream and collects its responses.
+type fakeTestStream struct {
+	grpc.ServerStream
+	requests  []*pb.TestStreamRequest
+	responses []*pb.TestStreamResponse
+}
+
+func (s *fakeTestStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeTestStream) Recv() (*pb.TestStreamRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *fakeTestStream) Send(resp *pb.TestStreamResponse) error {
+	s.responses = append(s.responses, resp)
+	return nil
+}
+
+func TestTestStream(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		disabled      bool
+		requests      []*pb.TestStreamRequest
+		wantCode      grpc_codes.Code
+		wantResponses int
+	}{
+		{
+			name:     "disabled",
+			disabled: true,
+			requests: []*pb.TestStreamRequest{{Count: 1}},
+			wantCode: grpc_codes.Unimplemented,
+		},
+		{
+			name:          "answers each message",
+			requests:      []*pb.TestStreamRequest{{Count: 2, ResponseSize: 10}, {}},
+			wantResponses: 3,
+		},
+		{
+			name:     "response too large",
+			requests: []*pb.TestStreamRequest{{ResponseSize: maxTestStreamResponseSize + 1}},
+			wantCode: grpc_codes.InvalidArgument,
+		},
+		{
+			name:     "too many responses",
+			requests: []*pb.TestStreamRequest{{Count: maxTestStreamCount + 1}},
+			wantCode: grpc_codes.InvalidArgument,
+		},
+		{
+			name: "stream over its byte budget",
+			requests: []*pb.TestStreamRequest{
+				{Count: maxTestStreamTotalBytes / maxTestStreamResponseSize, ResponseSize: maxTestStreamResponseSize},
+				{ResponseSize: 1},
+			},
+			wantCode:      grpc_codes.ResourceExhausted,
+			wantResponses: maxTestStreamTotalBytes / maxTestStreamResponseSize,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewTestServer(slog.New(slog.DiscardHandler))
+			s.streamEnabled = !tt.disabled
+			stream := &fakeTestStream{requests: tt.requests}
+			err := s.TestStream(stream)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v (%v)", code, tt.wantCode, err)
+			}
+			if len(stream.responses) != tt.wantResponses {
+				t.Errorf("sent %d responses, want %d", len(stream.responses), tt.wantResponses)
+			}
+		})
+	}
+}
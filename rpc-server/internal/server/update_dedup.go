@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"google.golang.org/protobuf/proto"
+
+	"grpc-server/internal/dedup"
+	"grpc-server/internal/logging"
+	pb "grpc-server/pkg/pb"
+)
+
+const updateDedupPrefix = "dedup:update_user:"
+
+// WithUpdateDedup collapses concurrent UpdateUser calls with the same ID and
+// payload, such as retry storms through the REST gateway, into one write
+// whose response every caller gets.
+func WithUpdateDedup(d *dedup.Deduplicator) Option {
+	return func(s *CachedUserServer) {
+		s.updateDedup = d
+	}
+}
+
+func (s *CachedUserServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	if s.updateDedup == nil {
+		return s.updateUser(ctx, req)
+	}
+	payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return s.updateUser(ctx, req)
+	}
+	sum := sha256.Sum256(payload)
+	key := s.keys.Key(ctx, updateDedupPrefix, req.Id, hex.EncodeToString(sum[:16]))
+
+	var response *pb.UpdateUserResponse
+	result, shared, err := s.updateDedup.Do(ctx, "update_user", key, func(ctx context.Context) ([]byte, error) {
+		var err error
+		if response, err = s.updateUser(ctx, req); err != nil {
+			return nil, err
+		}
+		return proto.Marshal(response)
+	})
+	if err != nil || !shared {
+		return response, err
+	}
+
+	logging.FromContext(ctx).InfoCtx(ctx, "Collapsed duplicate UpdateUser into a concurrent identical one", logging.UserID, req.Id)
+	response = &pb.UpdateUserResponse{}
+	if err := proto.Unmarshal(result, response); err != nil {
+		return nil, internalError("update_user", req.Id, "failed to decode deduplicated response")
+	}
+	return response, nil
+}
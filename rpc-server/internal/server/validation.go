@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"grpc-server/internal/timing"
+	"grpc-server/internal/validation"
+	"grpc-server/pkg/pb"
+)
+
+// ValidationInterceptor rejects user requests that fail the validation
+// package's checks before they reach CachedUserServer, which trusts its
+// input. A malformed user ID is answered as not found, without a cache or
+// database lookup.
+func ValidationInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var id string
+		var err error
+		stop := timing.Track(ctx, timing.StageValidation)
+		switch r := req.(type) {
+		case *pb.CreateUserRequest:
+			err = validation.ValidateCreateUser(r)
+		case *pb.GetUserRequest:
+			id, err = r.Id, validation.ValidateGetUser(r)
+		case *pb.UpdateUserRequest:
+			id, err = r.Id, validation.ValidateUpdateUser(r)
+		case *pb.DeleteUserRequest:
+			id, err = r.Id, validation.ValidateUserID(r.Id)
+		case *pb.ListUsersRequest:
+			err = validation.ValidateListUsers(r)
+		case *pb.MergeUsersRequest:
+			err = validation.ValidateMergeUsers(r)
+		case *pb.GetUserAtTimeRequest:
+			id, err = r.Id, validation.ValidateUserID(r.Id)
+		case *pb.RevertUserRequest:
+			id, err = r.Id, validation.ValidateUserID(r.Id)
+		case *pb.EraseUserRequest:
+			id, err = r.Id, validation.ValidateUserID(r.Id)
+		case *pb.ExportUserDataRequest:
+			id, err = r.Id, validation.ValidateUserID(r.Id)
+		case *pb.RequestEmailChangeRequest:
+			id, err = r.Id, validation.ValidateRequestEmailChange(r)
+		case *pb.ConfirmEmailChangeRequest:
+			id, err = r.Id, validation.ValidateUserID(r.Id)
+		}
+		stop()
+
+		switch {
+		case err == nil:
+			return handler(ctx, req)
+		case errors.Is(err, validation.ErrMalformedUserID):
+			return nil, userNotFoundError(id)
+		default:
+			return nil, status.Error(grpc_codes.InvalidArgument, err.Error())
+		}
+	}
+}
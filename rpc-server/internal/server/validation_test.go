@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "grpc-server/pkg/pb"
+)
+
+func TestValidationInterceptor(t *testing.T) {
+	const validID = "4b1f0d7e-8a9c-4f1e-9b7a-2c3d4e5f6a7b"
+	for _, tt := range []struct {
+		name string
+		req  any
+		want grpc_codes.Code
+	}{
+		{"GetUserAtTime", &pb.GetUserAtTimeRequest{Id: validID}, grpc_codes.OK},
+		{"GetUserAtTime without an ID", &pb.GetUserAtTimeRequest{}, grpc_codes.InvalidArgument},
+		{"GetUserAtTime with a malformed ID", &pb.GetUserAtTimeRequest{Id: "nf-1"}, grpc_codes.NotFound},
+		{"RevertUser with a malformed ID", &pb.RevertUserRequest{Id: "nf-1"}, grpc_codes.NotFound},
+		{"EraseUser with a malformed ID", &pb.EraseUserRequest{Id: "nf-1"}, grpc_codes.NotFound},
+		{"ExportUserData with a malformed ID", &pb.ExportUserDataRequest{Id: "nf-1"}, grpc_codes.NotFound},
+		{"ConfirmEmailChange with a malformed ID", &pb.ConfirmEmailChangeRequest{Id: "nf-1", Token: "t"}, grpc_codes.NotFound},
+		{"RequestEmailChange", &pb.RequestEmailChangeRequest{Id: validID, NewEmail: "new@example.com"}, grpc_codes.OK},
+		{"RequestEmailChange with a malformed ID", &pb.RequestEmailChangeRequest{Id: "nf-1", NewEmail: "new@example.com"}, grpc_codes.NotFound},
+		{"RequestEmailChange without an email", &pb.RequestEmailChangeRequest{Id: validID}, grpc_codes.InvalidArgument},
+		{"RequestEmailChange with an invalid email", &pb.RequestEmailChangeRequest{Id: validID, NewEmail: "not-an-email"}, grpc_codes.InvalidArgument},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			_, err := ValidationInterceptor()(context.Background(), tt.req, &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+				called = true
+				return nil, nil
+			})
+			if code := status.Code(err); code != tt.want {
+				t.Fatalf("code = %v, want %v (%v)", code, tt.want, err)
+			}
+			if called != (tt.want == grpc_codes.OK) {
+				t.Errorf("handler called = %v, want %v", called, tt.want == grpc_codes.OK)
+			}
+		})
+	}
+}
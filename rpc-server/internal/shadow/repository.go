@@ -0,0 +1,361 @@
+// Package shadow mirrors a sample of repository reads to a second backend,
+// such as a candidate datastore during a migration, and reports where its
+// answers diverge from the primary's. Callers only ever see the primary's
+// results; shadow reads run in the background with their own timeout.
+package shadow
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"grpc-server/internal/logging"
+	"grpc-server/internal/models"
+	"grpc-server/internal/repository"
+)
+
+// Comparison outcomes, recorded as the shadow.result attribute.
+const (
+	resultMatch    = "match"
+	resultMismatch = "mismatch"
+	resultError    = "shadow_error"
+	resultSkipped  = "skipped"
+)
+
+// Config controls how much traffic is mirrored.
+type Config struct {
+	// SamplePercent is the share of reads mirrored, from 0 to 100.
+	SamplePercent float64
+	// Timeout bounds each shadow read.
+	Timeout time.Duration
+	// MaxInFlight caps concurrent shadow reads; reads beyond it are skipped
+	// so a slow shadow never builds up goroutines.
+	MaxInFlight int
+}
+
+// UserRepository serves every call from the primary and mirrors sampled
+// reads to the shadow.
+type UserRepository struct {
+	primary repository.UserRepository
+	shadow  repository.UserRepository
+	cfg     Config
+	logger  *logging.Logger
+
+	inFlight    chan struct{}
+	comparisons metric.Int64Counter
+}
+
+func NewUserRepository(primary, shadow repository.UserRepository, cfg Config, base *slog.Logger) *UserRepository {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 2 * time.Second
+	}
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = 64
+	}
+	comparisons, _ := otel.Meter("rpc-server.rpc/shadow").Int64Counter("shadow.comparisons",
+		metric.WithDescription("Number of mirrored reads by outcome"),
+		metric.WithUnit("{comparison}"),
+	)
+	return &UserRepository{
+		primary:     primary,
+		shadow:      shadow,
+		cfg:         cfg,
+		logger:      logging.New(base.With("component", "shadow")),
+		inFlight:    make(chan struct{}, cfg.MaxInFlight),
+		comparisons: comparisons,
+	}
+}
+
+// mirror runs read against the shadow in the background and compares its
+// result with the primary's. Reads the primary failed on are not mirrored.
+func mirror[T any](r *UserRepository, ctx context.Context, method, id string, want T, wantErr error, read func(context.Context) (T, error), diff func(a, b T) []string) {
+	if errClass(wantErr) == errOther {
+		return
+	}
+	if r.cfg.SamplePercent < 100 && rand.Float64()*100 >= r.cfg.SamplePercent {
+		return
+	}
+	select {
+	case r.inFlight <- struct{}{}:
+	default:
+		r.record(ctx, method, resultSkipped)
+		return
+	}
+
+	// The shadow read outlives the request, but keeps its trace context.
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer func() { <-r.inFlight }()
+		ctx, cancel := context.WithTimeout(ctx, r.cfg.Timeout)
+		defer cancel()
+
+		got, gotErr := read(ctx)
+		if errClass(gotErr) == errOther {
+			r.record(ctx, method, resultError)
+			r.logger.DebugCtx(ctx, "Shadow read failed", "method", method, logging.UserID, id, logging.Error, gotErr)
+			return
+		}
+
+		var fields []string
+		switch {
+		case errClass(wantErr) != errClass(gotErr):
+			fields = []string{"error"}
+		case wantErr == nil:
+			fields = diff(want, got)
+		}
+		if len(fields) == 0 {
+			r.record(ctx, method, resultMatch)
+			return
+		}
+		r.record(ctx, method, resultMismatch)
+		// Only field names are logged; values may be personal data.
+		r.logger.WarnCtx(ctx, "Shadow read diverged from primary",
+			"method", method,
+			logging.UserID, id,
+			"fields", fields,
+			"primary_error", errClass(wantErr),
+			"shadow_error", errClass(gotErr),
+		)
+	}()
+}
+
+func (r *UserRepository) record(ctx context.Context, method, result string) {
+	r.comparisons.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("repository.method", method),
+		attribute.String("shadow.result", result),
+	))
+}
+
+const errOther = "other"
+
+// errClass names the repository error err stands for, so answers such as
+// "not found" can be compared across backends. Anything else, like a lost
+// connection, is errOther.
+func errClass(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, sentinel := range []error{
+		repository.ErrUserNotFound,
+		repository.ErrVersionNotFound,
+		repository.ErrEmailChangeNotFound,
+	} {
+		if errors.Is(err, sentinel) {
+			return sentinel.Error()
+		}
+	}
+	return errOther
+}
+
+// diffUser returns the names of the fields that differ. Timestamps are
+// compared at the microsecond precision Postgres stores.
+func diffUser(a, b *models.User) []string {
+	if a == nil || b == nil {
+		if a != b {
+			return []string{"user"}
+		}
+		return nil
+	}
+	var fields []string
+	if a.ID != b.ID {
+		fields = append(fields, "id")
+	}
+	if a.Name != b.Name {
+		fields = append(fields, "name")
+	}
+	if a.Email != b.Email {
+		fields = append(fields, "email")
+	}
+	if a.Age != b.Age {
+		fields = append(fields, "age")
+	}
+	if a.Status != b.Status {
+		fields = append(fields, "status")
+	}
+	if a.MergedInto != b.MergedInto {
+		fields = append(fields, "merged_into")
+	}
+	if !sameTime(a.CreatedAt, b.CreatedAt) {
+		fields = append(fields, "created_at")
+	}
+	if !sameTime(a.UpdatedAt, b.UpdatedAt) {
+		fields = append(fields, "updated_at")
+	}
+	return fields
+}
+
+// diffVersion compares versions without their IDs, which each backend
+// assigns on its own.
+func diffVersion(a, b *models.UserVersion) []string {
+	if a == nil || b == nil {
+		if a != b {
+			return []string{"version"}
+		}
+		return nil
+	}
+	fields := diffUser(&a.User, &b.User)
+	if !sameTime(a.ValidFrom, b.ValidFrom) {
+		fields = append(fields, "valid_from")
+	}
+	if !sameTime(a.ValidTo, b.ValidTo) {
+		fields = append(fields, "valid_to")
+	}
+	return fields
+}
+
+func sameTime(a, b time.Time) bool {
+	return a.Truncate(time.Microsecond).Equal(b.Truncate(time.Microsecond))
+}
+
+// diffSlice compares two lists element by element, reporting the first
+// position that differs.
+func diffSlice[T any](a, b []T, diff func(a, b T) []string) []string {
+	if len(a) != len(b) {
+		return []string{"length"}
+	}
+	for i := range a {
+		if fields := diff(a[i], b[i]); len(fields) > 0 {
+			return fields
+		}
+	}
+	return nil
+}
+
+type page struct {
+	users []*models.User
+	total int
+}
+
+func diffPage(a, b page) []string {
+	if a.total != b.total {
+		return []string{"total"}
+	}
+	return diffSlice(a.users, b.users, diffUser)
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	user, err := r.primary.GetByID(ctx, id)
+	mirror(r, ctx, "GetByID", id, user, err, func(ctx context.Context) (*models.User, error) {
+		return r.shadow.GetByID(ctx, id)
+	}, diffUser)
+	return user, err
+}
+
+func (r *UserRepository) BatchGet(ctx context.Context, ids []string) ([]*models.User, error) {
+	users, err := r.primary.BatchGet(ctx, ids)
+	mirror(r, ctx, "BatchGet", "", users, err, func(ctx context.Context) ([]*models.User, error) {
+		return r.shadow.BatchGet(ctx, ids)
+	}, func(a, b []*models.User) []string {
+		return diffSlice(a, b, diffUser)
+	})
+	return users, err
+}
+
+func (r *UserRepository) List(ctx context.Context, offset, limit int) ([]*models.User, int, error) {
+	users, total, err := r.primary.List(ctx, offset, limit)
+	mirror(r, ctx, "List", "", page{users, total}, err, func(ctx context.Context) (page, error) {
+		users, total, err := r.shadow.List(ctx, offset, limit)
+		return page{users, total}, err
+	}, diffPage)
+	return users, total, err
+}
+
+func (r *UserRepository) SearchByPrefix(ctx context.Context, filter repository.PrefixFilter, offset, limit int) ([]*models.User, int, error) {
+	users, total, err := r.primary.SearchByPrefix(ctx, filter, offset, limit)
+	mirror(r, ctx, "SearchByPrefix", "", page{users, total}, err, func(ctx context.Context) (page, error) {
+		users, total, err := r.shadow.SearchByPrefix(ctx, filter, offset, limit)
+		return page{users, total}, err
+	}, diffPage)
+	return users, total, err
+}
+
+func (r *UserRepository) EmailExists(ctx context.Context, email string, excludeID string) (bool, error) {
+	exists, err := r.primary.EmailExists(ctx, email, excludeID)
+	mirror(r, ctx, "EmailExists", excludeID, exists, err, func(ctx context.Context) (bool, error) {
+		return r.shadow.EmailExists(ctx, email, excludeID)
+	}, func(a, b bool) []string {
+		if a != b {
+			return []string{"exists"}
+		}
+		return nil
+	})
+	return exists, err
+}
+
+func (r *UserRepository) GetAt(ctx context.Context, id string, at time.Time) (*models.UserVersion, error) {
+	version, err := r.primary.GetAt(ctx, id, at)
+	mirror(r, ctx, "GetAt", id, version, err, func(ctx context.Context) (*models.UserVersion, error) {
+		return r.shadow.GetAt(ctx, id, at)
+	}, diffVersion)
+	return version, err
+}
+
+func (r *UserRepository) History(ctx context.Context, id string) ([]*models.UserVersion, error) {
+	versions, err := r.primary.History(ctx, id)
+	mirror(r, ctx, "History", id, versions, err, func(ctx context.Context) ([]*models.UserVersion, error) {
+		return r.shadow.History(ctx, id)
+	}, func(a, b []*models.UserVersion) []string {
+		return diffSlice(a, b, diffVersion)
+	})
+	return versions, err
+}
+
+// Writes and job queries go to the primary only.
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	return r.primary.Create(ctx, user)
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	return r.primary.Update(ctx, user)
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	return r.primary.Delete(ctx, id)
+}
+
+func (r *UserRepository) ListInactive(ctx context.Context, cutoff time.Time, limit int) ([]*models.User, error) {
+	return r.primary.ListInactive(ctx, cutoff, limit)
+}
+
+func (r *UserRepository) Expire(ctx context.Context, id string, cutoff time.Time) (bool, error) {
+	return r.primary.Expire(ctx, id, cutoff)
+}
+
+func (r *UserRepository) PruneHistory(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	return r.primary.PruneHistory(ctx, cutoff, limit)
+}
+
+func (r *UserRepository) Erase(ctx context.Context, id string) error {
+	return r.primary.Erase(ctx, id)
+}
+
+func (r *UserRepository) Revert(ctx context.Context, id string, versionID int64) (*models.User, error) {
+	return r.primary.Revert(ctx, id, versionID)
+}
+
+func (r *UserRepository) Merge(ctx context.Context, sourceID, targetID string, policy models.MergePolicy) (*models.User, *models.User, error) {
+	return r.primary.Merge(ctx, sourceID, targetID, policy)
+}
+
+func (r *UserRepository) RequestEmailChange(ctx context.Context, change *models.EmailChange) error {
+	return r.primary.RequestEmailChange(ctx, change)
+}
+
+func (r *UserRepository) PendingEmailChange(ctx context.Context, id string) (*models.EmailChange, error) {
+	return r.primary.PendingEmailChange(ctx, id)
+}
+
+func (r *UserRepository) ConfirmEmailChange(ctx context.Context, id, token string) (*models.User, error) {
+	return r.primary.ConfirmEmailChange(ctx, id, token)
+}
+
+func (r *UserRepository) PruneEmailChanges(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	return r.primary.PruneEmailChanges(ctx, cutoff, limit)
+}
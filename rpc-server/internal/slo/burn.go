@@ -0,0 +1,180 @@
+package slo
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	bucketWidth = time.Minute
+	// bucketCount covers the longest policy window.
+	bucketCount = int(6 * time.Hour / bucketWidth)
+
+	// minRequests keeps a handful of failures on an idle method from paging.
+	minRequests = 10
+)
+
+// policy is a multi-window burn-rate alert: it fires when both windows burn
+// the budget at least burnRate times faster than the objective allows. The
+// short window lets the alert resolve soon after the problem stops.
+type policy struct {
+	name     string
+	long     time.Duration
+	short    time.Duration
+	burnRate float64
+}
+
+// policies are the usual fast and slow burn alerts; over a 30-day period they
+// fire after 2% and 5% of the budget is spent respectively.
+var policies = []policy{
+	{name: "fast", long: time.Hour, short: 5 * time.Minute, burnRate: 14.4},
+	{name: "slow", long: 6 * time.Hour, short: 30 * time.Minute, burnRate: 6},
+}
+
+const (
+	objectiveAvailability = "availability"
+	objectiveLatency      = "latency"
+)
+
+type alertKey struct {
+	method    string
+	objective string
+	policy    string
+}
+
+type bucket struct {
+	minute      int64
+	total       int64
+	serverError int64
+	slow        int64
+}
+
+// window counts requests in one-minute buckets over the last bucketCount
+// minutes. Buckets are reused in place as time moves on.
+type window struct {
+	buckets [bucketCount]bucket
+}
+
+func (w *window) add(now time.Time, serverError, slow bool) {
+	minute := now.Unix() / int64(bucketWidth/time.Second)
+	b := &w.buckets[minute%int64(bucketCount)]
+	if b.minute != minute {
+		*b = bucket{minute: minute}
+	}
+	b.total++
+	if serverError {
+		b.serverError++
+	}
+	if slow {
+		b.slow++
+	}
+}
+
+// sum totals the buckets within d of now.
+func (w *window) sum(now time.Time, d time.Duration) bucket {
+	current := now.Unix() / int64(bucketWidth/time.Second)
+	oldest := current - int64(d/bucketWidth)
+	var total bucket
+	for _, b := range w.buckets {
+		if b.minute > oldest && b.minute <= current {
+			total.total += b.total
+			total.serverError += b.serverError
+			total.slow += b.slow
+		}
+	}
+	return total
+}
+
+// burnRate is how many times faster than allowed the budget is being spent:
+// 1 spends exactly the budget over the SLO period.
+func burnRate(bad, total int64, target float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return (float64(bad) / float64(total)) / (1 - target)
+}
+
+type transition struct {
+	key       alertKey
+	firing    bool
+	target    float64
+	longRate  float64
+	shortRate float64
+	threshold float64
+}
+
+// Evaluate computes burn rates for every method seen so far and logs alerts
+// that started or stopped firing since the last evaluation.
+func (t *Tracker) Evaluate(ctx context.Context) {
+	now := t.now()
+
+	var changes []transition
+	t.mu.Lock()
+	for method, w := range t.methods {
+		obj := t.cfg.Objectives.For(method)
+		for _, p := range policies {
+			long, short := w.sum(now, p.long), w.sum(now, p.short)
+			for _, o := range []struct {
+				name              string
+				target            float64
+				longBad, shortBad int64
+			}{
+				{objectiveAvailability, obj.Availability, long.serverError, short.serverError},
+				{objectiveLatency, obj.Latency, long.slow, short.slow},
+			} {
+				// A target of 100% leaves no budget to burn.
+				if o.target <= 0 || o.target >= 1 {
+					continue
+				}
+				key := alertKey{method: method, objective: o.name, policy: p.name}
+				longRate := burnRate(o.longBad, long.total, o.target)
+				shortRate := burnRate(o.shortBad, short.total, o.target)
+				t.burnRates[key] = longRate
+
+				firing := short.total >= minRequests && longRate >= p.burnRate && shortRate >= p.burnRate
+				if firing == t.firing[key] {
+					continue
+				}
+				if firing {
+					t.firing[key] = true
+				} else {
+					delete(t.firing, key)
+				}
+				changes = append(changes, transition{
+					key:       key,
+					firing:    firing,
+					target:    o.target,
+					longRate:  longRate,
+					shortRate: shortRate,
+					threshold: p.burnRate,
+				})
+			}
+		}
+	}
+	t.mu.Unlock()
+
+	for _, c := range changes {
+		args := []any{
+			"method", c.key.method,
+			"objective", c.key.objective,
+			"policy", c.key.policy,
+			"target", c.target,
+			"burn_rate", c.longRate,
+			"short_burn_rate", c.shortRate,
+			"threshold", c.threshold,
+		}
+		if c.firing {
+			t.alerts.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("rpc.method", c.key.method),
+				attribute.String("slo.objective", c.key.objective),
+				attribute.String("slo.policy", c.key.policy),
+			))
+			t.logger.WarnCtx(ctx, "SLO error budget burning too fast", args...)
+		} else {
+			t.logger.InfoCtx(ctx, "SLO burn rate alert resolved", args...)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Package slo tracks per-method availability and latency objectives for the
+// gRPC server and warns when error budgets are burning too fast. It stands in
+// for external alerting: burn rates are exported as metrics and every alert
+// transition is logged.
+package slo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"grpc-server/internal/logging"
+)
+
+// Objective is the target for one method.
+type Objective struct {
+	// Availability is the share of requests that must not fail with a server
+	// error, e.g. 0.999.
+	Availability float64
+	// Latency is the share of requests that must complete within
+	// LatencyThreshold, e.g. 0.99.
+	Latency          float64
+	LatencyThreshold time.Duration
+}
+
+// Objectives holds the default objective and per-method overrides, keyed by
+// method name without the service ("GetUser").
+type Objectives struct {
+	Default Objective
+	Methods map[string]Objective
+}
+
+// For returns the objective for a full gRPC method name.
+func (o Objectives) For(fullMethod string) Objective {
+	if obj, ok := o.Methods[path.Base(fullMethod)]; ok {
+		return obj
+	}
+	return o.Default
+}
+
+// ParseLatencyThresholds parses a comma-separated list of per-method latency
+// thresholds ("ListUsers=500ms,ExportUserData=2s") into overrides of def.
+func ParseLatencyThresholds(s string, def Objective) (map[string]Objective, error) {
+	methods := make(map[string]Objective)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		method, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid latency threshold %q: want Method=duration", entry)
+		}
+		threshold, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency threshold for %s: %w", method, err)
+		}
+		obj := def
+		obj.LatencyThreshold = threshold
+		methods[strings.TrimSpace(method)] = obj
+	}
+	return methods, nil
+}
+
+// Config controls burn-rate evaluation.
+type Config struct {
+	Objectives Objectives
+	// Interval is the time between evaluations.
+	Interval time.Duration
+}
+
+// Tracker records request outcomes per method and evaluates burn rates.
+type Tracker struct {
+	cfg    Config
+	logger *logging.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	methods map[string]*window
+	// firing holds the alerts currently firing, so only transitions are logged.
+	firing map[alertKey]bool
+	// burnRates holds the latest long-window burn rates for the gauge.
+	burnRates map[alertKey]float64
+
+	alerts metric.Int64Counter
+}
+
+func NewTracker(cfg Config, base *slog.Logger) *Tracker {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	t := &Tracker{
+		cfg:       cfg,
+		logger:    logging.New(base.With("component", "slo")),
+		now:       time.Now,
+		methods:   make(map[string]*window),
+		firing:    make(map[alertKey]bool),
+		burnRates: make(map[alertKey]float64),
+	}
+
+	meter := otel.Meter("rpc-server.rpc/slo")
+	t.alerts, _ = meter.Int64Counter("slo.alerts",
+		metric.WithDescription("Number of SLO burn-rate alerts raised"),
+		metric.WithUnit("{alert}"),
+	)
+	_, _ = meter.Float64ObservableGauge("slo.burn_rate",
+		metric.WithDescription("Error budget burn rate over the long window of each alert policy"),
+		metric.WithFloat64Callback(t.observe),
+	)
+	return t
+}
+
+// UnaryServerInterceptor records the outcome and latency of every call.
+func (t *Tracker) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := t.now()
+		resp, err := handler(ctx, req)
+		t.Record(info.FullMethod, status.Code(err), t.now().Sub(start))
+		return resp, err
+	}
+}
+
+// Record adds one completed request to the method's window.
+func (t *Tracker) Record(fullMethod string, code grpc_codes.Code, elapsed time.Duration) {
+	obj := t.cfg.Objectives.For(fullMethod)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	w, ok := t.methods[fullMethod]
+	if !ok {
+		w = &window{}
+		t.methods[fullMethod] = w
+	}
+	w.add(t.now(), serverError(code), elapsed > obj.LatencyThreshold)
+}
+
+// serverError reports whether code counts against availability. Errors caused
+// by the request itself (invalid arguments, missing users, cancellations) do
+// not consume the error budget.
+func serverError(code grpc_codes.Code) bool {
+	switch code {
+	case grpc_codes.Unknown, grpc_codes.DeadlineExceeded, grpc_codes.Unimplemented,
+		grpc_codes.Internal, grpc_codes.Unavailable, grpc_codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// Run evaluates burn rates every Interval until ctx is cancelled.
+func (t *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Evaluate(ctx)
+		}
+	}
+}
+
+func (t *Tracker) observe(_ context.Context, o metric.Float64Observer) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, rate := range t.burnRates {
+		o.Observe(rate, metric.WithAttributes(
+			attribute.String("rpc.method", key.method),
+			attribute.String("slo.objective", key.objective),
+			attribute.String("slo.policy", key.policy),
+		))
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Package startup initializes the server's independent dependencies
+// concurrently, each within its own timeout, so a cold start takes as long as
+// the slowest dependency rather than the sum of all of them, and a failure
+// names the dependency that caused it.
+package startup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Step initializes one dependency.
+type Step struct {
+	Name string
+	// Timeout bounds the step; 0 leaves only the deadline of the context
+	// passed to Run.
+	Timeout time.Duration
+	// Init connects the dependency and stores it for the caller. It should
+	// return promptly once ctx is done; if it does not, Run gives up on it
+	// anyway and reports the timeout.
+	Init func(ctx context.Context) error
+}
+
+// Error is the failure of one step.
+type Error struct {
+	Step    string
+	Elapsed time.Duration
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s failed after %s: %v", e.Step, e.Elapsed.Round(time.Millisecond), e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Run runs every step concurrently and waits for all of them. It returns the
+// failed steps joined with errors.Join, each as an *Error, or nil if all
+// succeeded. Every step's duration is logged.
+func Run(ctx context.Context, steps ...Step) error {
+	start := time.Now()
+	errs := make([]error, len(steps))
+
+	var wg sync.WaitGroup
+	for i, step := range steps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = run(ctx, step)
+		}()
+	}
+	wg.Wait()
+
+	err := errors.Join(errs...)
+	slog.InfoContext(ctx, "Startup dependencies finished",
+		"steps", len(steps),
+		"elapsed_ms", time.Since(start).Milliseconds(),
+		"failed", err != nil,
+	)
+	return err
+}
+
+func run(ctx context.Context, step Step) error {
+	if step.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, step.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- step.Init(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	elapsed := time.Since(start)
+
+	if err != nil {
+		slog.ErrorContext(ctx, "Startup step failed", "step", step.Name, "elapsed_ms", elapsed.Milliseconds(), "error", err)
+		return &Error{Step: step.Name, Elapsed: elapsed, Err: err}
+	}
+	slog.InfoContext(ctx, "Startup step done", "step", step.Name, "elapsed_ms", elapsed.Milliseconds())
+	return nil
+}
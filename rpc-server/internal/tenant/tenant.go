@@ -0,0 +1,35 @@
+// Package tenant identifies whose data a call may see, for the row-level
+// security policies of the database.
+package tenant
+
+import "context"
+
+// Shared is the tenant of calls without a tenant claim, and of users stored
+// before tenancy.
+const Shared = ""
+
+type scope struct {
+	id  string
+	all bool
+}
+
+type scopeKey struct{}
+
+// With returns a context confined to the data of tenant id.
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope{id: id})
+}
+
+// All returns a context that sees the data of every tenant, for background
+// jobs and maintenance commands. Calls must never get one.
+func All(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope{all: true})
+}
+
+// FromContext returns the tenant set by With, and whether the context was
+// returned by All. Anything else is confined to Shared; what a caller claims
+// in its metadata is never used, since any caller could claim any tenant.
+func FromContext(ctx context.Context) (id string, all bool) {
+	s, _ := ctx.Value(scopeKey{}).(scope)
+	return s.id, s.all
+}
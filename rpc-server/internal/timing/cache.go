@@ -0,0 +1,52 @@
+package timing
+
+import (
+	"context"
+	"time"
+
+	"grpc-server/internal/cache"
+)
+
+// Cache records the time spent in the wrapped cache: Get as StageCacheLookup,
+// every other operation as StageCacheWrite.
+type Cache struct {
+	cache cache.Cache
+}
+
+func NewCache(c cache.Cache) *Cache {
+	return &Cache{cache: c}
+}
+
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	defer Track(ctx, StageCacheLookup)()
+	return c.cache.Get(ctx, key)
+}
+
+func (c *Cache) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	defer Track(ctx, StageCacheLookup)()
+	return c.cache.MGet(ctx, keys...)
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	defer Track(ctx, StageCacheWrite)()
+	return c.cache.Set(ctx, key, value, expiration)
+}
+
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	defer Track(ctx, StageCacheWrite)()
+	return c.cache.Delete(ctx, keys...)
+}
+
+func (c *Cache) DeletePattern(ctx context.Context, pattern string) (int, error) {
+	defer Track(ctx, StageCacheWrite)()
+	return c.cache.DeletePattern(ctx, pattern)
+}
+
+func (c *Cache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	defer Track(ctx, StageCacheWrite)()
+	return c.cache.Expire(ctx, key, expiration)
+}
+
+func (c *Cache) Close() error {
+	return c.cache.Close()
+}
@@ -0,0 +1,108 @@
+// Package timing attributes the latency of a request to the stages it went
+// through, so a regression can be traced to a stage without a profiler.
+package timing
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+
+	"grpc-server/internal/logging"
+)
+
+// Stages a request's time is broken down into. Serialization covers cache
+// value encoding and model to proto conversion; gRPC's own wire encoding of
+// the response happens after the breakdown is reported.
+const (
+	StageValidation    = "validation"
+	StageCacheLookup   = "cache_lookup"
+	StageDatabase      = "db_query"
+	StageCacheWrite    = "cache_write"
+	StageSerialization = "serialization"
+)
+
+var stages = []string{StageValidation, StageCacheLookup, StageDatabase, StageCacheWrite, StageSerialization}
+
+// Breakdown accumulates time per stage for one request. Stages entered more
+// than once, such as several queries, add up.
+type Breakdown struct {
+	mu        sync.Mutex
+	durations map[string]time.Duration
+}
+
+type breakdownKey struct{}
+
+// WithBreakdown returns a context that collects a new Breakdown.
+func WithBreakdown(ctx context.Context) (context.Context, *Breakdown) {
+	b := &Breakdown{durations: make(map[string]time.Duration)}
+	return context.WithValue(ctx, breakdownKey{}, b), b
+}
+
+// Track starts timing stage for the request in ctx and returns the function
+// that stops it. It does nothing outside a request with a Breakdown.
+//
+//	defer timing.Track(ctx, timing.StageSerialization)()
+func Track(ctx context.Context, stage string) func() {
+	b, ok := ctx.Value(breakdownKey{}).(*Breakdown)
+	if !ok {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { b.Add(stage, time.Since(start)) }
+}
+
+// Add records d against stage.
+func (b *Breakdown) Add(stage string, d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.durations[stage] += d
+}
+
+// milliseconds returns the recorded stages in pipeline order, in milliseconds.
+func (b *Breakdown) milliseconds() ([]string, []float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var names []string
+	var ms []float64
+	for _, stage := range stages {
+		if d, ok := b.durations[stage]; ok {
+			names = append(names, stage)
+			ms = append(ms, float64(d.Microseconds())/1000)
+		}
+	}
+	return names, ms
+}
+
+// UnaryServerInterceptor collects a Breakdown for every call and reports it
+// as a DEBUG log record and a "timing" event on the call's span. Install it
+// after logging.UnaryServerInterceptor so the record carries the request
+// attributes.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, b := WithBreakdown(ctx)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		total := time.Since(start)
+
+		names, ms := b.milliseconds()
+		logAttrs := make([]any, 0, len(names))
+		spanAttrs := make([]attribute.KeyValue, 0, len(names)+1)
+		for i, name := range names {
+			logAttrs = append(logAttrs, slog.Float64(name, ms[i]))
+			spanAttrs = append(spanAttrs, attribute.Float64("timing."+name+"_ms", ms[i]))
+		}
+		totalMs := float64(total.Microseconds()) / 1000
+		spanAttrs = append(spanAttrs, attribute.Float64("timing.total_ms", totalMs))
+		trace.SpanFromContext(ctx).AddEvent("timing", trace.WithAttributes(spanAttrs...))
+		logging.FromContext(ctx).DebugCtx(ctx, "RPC timing breakdown",
+			"total_ms", totalMs,
+			slog.Group("stages_ms", logAttrs...),
+		)
+		return resp, err
+	}
+}
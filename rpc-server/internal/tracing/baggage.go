@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Baggage keys set by BaggageInterceptor. They double as span attribute
+// names, so traces can be searched by user or tenant directly.
+const (
+	BaggageUserID   = "user.id"
+	BaggageTenantID = "tenant.id"
+)
+
+// TenantMetadataKey is the incoming metadata key carrying the caller's tenant.
+const TenantMetadataKey = "x-tenant-id"
+
+// baggageAttributes lists the baggage members BaggageSpanProcessor copies.
+var baggageAttributes = []string{BaggageUserID, BaggageTenantID}
+
+// BaggageInterceptor adds the request's target user ID and the caller's
+// tenant to the context baggage. Spans started further down (database,
+// cache, event publishing) pick them up through BaggageSpanProcessor, and
+// outgoing calls carry them to other services via the baggage propagator.
+// The already running server span is tagged directly.
+func BaggageInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		values := map[string]string{BaggageUserID: targetUserID(req)}
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if tenant := md.Get(TenantMetadataKey); len(tenant) > 0 {
+				values[BaggageTenantID] = tenant[0]
+			}
+		}
+
+		bag := baggage.FromContext(ctx)
+		span := trace.SpanFromContext(ctx)
+		for key, value := range values {
+			if value == "" {
+				continue
+			}
+			member, err := baggage.NewMemberRaw(key, value)
+			if err != nil {
+				continue
+			}
+			if updated, err := bag.SetMember(member); err == nil {
+				bag = updated
+			}
+			span.SetAttributes(attribute.String(key, value))
+		}
+		return handler(baggage.ContextWithBaggage(ctx, bag), req)
+	}
+}
+
+// targetUserID returns the ID of the user a request acts on, or "" for
+// requests without one. For merges that is the surviving user.
+func targetUserID(req any) string {
+	switch r := req.(type) {
+	case interface{ GetId() string }:
+		return r.GetId()
+	case interface{ GetTargetId() string }:
+		return r.GetTargetId()
+	}
+	return ""
+}
+
+// BaggageSpanProcessor copies the baggage members set by BaggageInterceptor
+// onto every span started in their context.
+type BaggageSpanProcessor struct{}
+
+var _ sdktrace.SpanProcessor = BaggageSpanProcessor{}
+
+func (BaggageSpanProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
+	bag := baggage.FromContext(ctx)
+	for _, key := range baggageAttributes {
+		if member := bag.Member(key); member.Key() != "" {
+			span.SetAttributes(attribute.String(key, member.Value()))
+		}
+	}
+}
+
+func (BaggageSpanProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (BaggageSpanProcessor) Shutdown(context.Context) error   { return nil }
+func (BaggageSpanProcessor) ForceFlush(context.Context) error { return nil }
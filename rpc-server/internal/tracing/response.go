@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Response metadata keys set by ResponseTraceInterceptor.
+const (
+	TraceParentMetadataKey = "traceparent"
+	TraceURLMetadataKey    = "x-trace-url"
+)
+
+// ResponseTraceInterceptor returns the W3C traceparent of every call in the
+// response header metadata, so callers can log and report the trace of a
+// failed call. The REST gateway forwards it as the Grpc-Metadata-Traceparent
+// HTTP header. When urlTemplate is set, its "{trace_id}" placeholder is
+// filled in and returned as x-trace-url, a direct link to the trace.
+func ResponseTraceInterceptor(urlTemplate string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		sc := trace.SpanContextFromContext(ctx)
+		if !sc.IsValid() {
+			return handler(ctx, req)
+		}
+		carrier := propagation.MapCarrier{}
+		propagation.TraceContext{}.Inject(ctx, carrier)
+		md := metadata.Pairs(TraceParentMetadataKey, carrier.Get(TraceParentMetadataKey))
+		if urlTemplate != "" {
+			md.Set(TraceURLMetadataKey, strings.ReplaceAll(urlTemplate, "{trace_id}", sc.TraceID().String()))
+		}
+		_ = grpc.SetHeader(ctx, md)
+		return handler(ctx, req)
+	}
+}
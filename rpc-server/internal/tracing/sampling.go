@@ -0,0 +1,172 @@
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Limits on what ErrorTraceProcessor holds while waiting for a trace's local
+// root span to end. Traces beyond them are not rescued.
+const (
+	maxPendingTraces   = 4096
+	maxSpansPerTrace   = 512
+	errorExportTimeout = 10 * time.Second
+)
+
+// RecordingSampler wraps a sampler so that spans it drops are still recorded,
+// just not sampled. Span processors then see every span, which lets
+// ErrorTraceProcessor export dropped traces that turn out to contain errors.
+type RecordingSampler struct {
+	base sdktrace.Sampler
+}
+
+func NewRecordingSampler(base sdktrace.Sampler) RecordingSampler {
+	return RecordingSampler{base: base}
+}
+
+func (s RecordingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.base.ShouldSample(p)
+	if result.Decision == sdktrace.Drop {
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
+
+func (s RecordingSampler) Description() string {
+	return "RecordingSampler{" + s.base.Description() + "}"
+}
+
+// RatioSampler samples the fraction of traces returned by ratio, which is
+// read for every new trace so it can change while the server runs.
+type RatioSampler struct {
+	ratio func() float64
+
+	mu      sync.Mutex
+	current float64
+	base    sdktrace.Sampler
+}
+
+func NewRatioSampler(ratio func() float64) *RatioSampler {
+	return &RatioSampler{ratio: ratio}
+}
+
+func (s *RatioSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return s.sampler().ShouldSample(p)
+}
+
+func (s *RatioSampler) Description() string {
+	return "RatioSampler{" + s.sampler().Description() + "}"
+}
+
+// sampler returns a TraceIDRatioBased sampler for the current ratio,
+// rebuilding it only when the ratio changed.
+func (s *RatioSampler) sampler() sdktrace.Sampler {
+	ratio := s.ratio()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.base == nil || ratio != s.current {
+		s.current = ratio
+		s.base = sdktrace.TraceIDRatioBased(ratio)
+	}
+	return s.base
+}
+
+// ErrorTraceProcessor exports traces that the sampler dropped if any of their
+// spans ended with an error status. It buffers the recorded, unsampled spans
+// of each trace until the trace's local root span ends, then exports them
+// all or discards them. Sampled spans are left to the regular batch
+// processor.
+type ErrorTraceProcessor struct {
+	exporter sdktrace.SpanExporter
+
+	mu      sync.Mutex
+	pending map[trace.TraceID]*pendingTrace
+	exports sync.WaitGroup
+}
+
+type pendingTrace struct {
+	spans  []sdktrace.ReadOnlySpan
+	failed bool
+}
+
+var _ sdktrace.SpanProcessor = (*ErrorTraceProcessor)(nil)
+
+func NewErrorTraceProcessor(exporter sdktrace.SpanExporter) *ErrorTraceProcessor {
+	return &ErrorTraceProcessor{
+		exporter: exporter,
+		pending:  make(map[trace.TraceID]*pendingTrace),
+	}
+}
+
+func (p *ErrorTraceProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p *ErrorTraceProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
+	if span.SpanContext().IsSampled() {
+		return
+	}
+	traceID := span.SpanContext().TraceID()
+	localRoot := !span.Parent().IsValid() || span.Parent().IsRemote()
+
+	p.mu.Lock()
+	t, ok := p.pending[traceID]
+	if !ok {
+		if len(p.pending) >= maxPendingTraces && !localRoot {
+			p.mu.Unlock()
+			return
+		}
+		t = &pendingTrace{}
+		p.pending[traceID] = t
+	}
+	if len(t.spans) < maxSpansPerTrace {
+		t.spans = append(t.spans, span)
+	}
+	if span.Status().Code == codes.Error {
+		t.failed = true
+	}
+	if !localRoot {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.pending, traceID)
+	p.mu.Unlock()
+
+	if !t.failed {
+		return
+	}
+	p.exports.Add(1)
+	go func() {
+		defer p.exports.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), errorExportTimeout)
+		defer cancel()
+		if err := p.exporter.ExportSpans(ctx, t.spans); err != nil {
+			slog.Warn("Failed to export error trace", "trace_id", traceID.String(), "error", err)
+		}
+	}()
+}
+
+// Shutdown waits for in-flight exports. The exporter itself is shut down by
+// the batch processor that shares it, which must be registered after this
+// processor.
+func (p *ErrorTraceProcessor) Shutdown(ctx context.Context) error {
+	return p.ForceFlush(ctx)
+}
+
+func (p *ErrorTraceProcessor) ForceFlush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		p.exports.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -2,19 +2,25 @@ package tracing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
 	"go.opentelemetry.io/contrib/propagators/b3"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+
+	"grpc-server/internal/buildinfo"
 )
 
 type TracingConfig struct {
@@ -22,9 +28,17 @@ type TracingConfig struct {
 	ServiceVersion string
 	CollectorURL   string
 	Enabled        bool
+	SampleRatio    float64 // 1 or more samples everything
+	// SampleRatioFunc, when set, replaces SampleRatio with a ratio that is
+	// read for every new trace.
+	SampleRatioFunc func() float64
+	// MetricReaders are added to the MeterProvider next to the collector
+	// exporter, e.g. to serve metrics for scraping as well.
+	MetricReaders []sdkmetric.Reader
 }
 
-// InitTracing initializes OpenTelemetry tracing
+// InitTracing initializes OpenTelemetry tracing. Metrics are sent to the same
+// collector.
 func InitTracing(ctx context.Context, cfg TracingConfig) (func(context.Context) error, error) {
 	if !cfg.Enabled {
 		slog.Info("Tracing disabled")
@@ -34,13 +48,18 @@ func InitTracing(ctx context.Context, cfg TracingConfig) (func(context.Context)
 	slog.Info("Initializing OpenTelemetry tracing",
 		"service", cfg.ServiceName,
 		"version", cfg.ServiceVersion,
-		"collector", cfg.CollectorURL)
+		"collector", cfg.CollectorURL,
+		"sample_ratio", cfg.SampleRatio)
 
-	// Create resource with service information
+	// Create resource with service and build information
+	build := buildinfo.Get()
 	res, err := resource.New(ctx,
 		resource.WithAttributes(
 			semconv.ServiceNameKey.String(cfg.ServiceName),
 			semconv.ServiceVersionKey.String(cfg.ServiceVersion),
+			attribute.String("service.build.version", build.Version),
+			attribute.String("service.build.commit", build.Commit),
+			attribute.String("service.build.date", build.Date),
 		),
 	)
 	if err != nil {
@@ -61,15 +80,46 @@ func InitTracing(ctx context.Context, cfg TracingConfig) (func(context.Context)
 	}
 
 	// Create trace provider
-	tracerProvider := trace.NewTracerProvider(
-		trace.WithBatcher(traceExporter),
+	opts := []trace.TracerProviderOption{
+		trace.WithSpanProcessor(BaggageSpanProcessor{}),
 		trace.WithResource(res),
-		trace.WithSampler(trace.AlwaysSample()),
-	)
+	}
+	if cfg.SampleRatioFunc == nil && cfg.SampleRatio >= 1 {
+		opts = append(opts, trace.WithSampler(trace.AlwaysSample()))
+	} else {
+		var ratio trace.Sampler = trace.TraceIDRatioBased(cfg.SampleRatio)
+		if cfg.SampleRatioFunc != nil {
+			ratio = NewRatioSampler(cfg.SampleRatioFunc)
+		}
+		// Dropped spans are still recorded so traces with errors can be
+		// exported after the fact. The error processor must come before the
+		// batcher, whose shutdown also shuts down the shared exporter.
+		opts = append(opts,
+			trace.WithSampler(NewRecordingSampler(trace.ParentBased(ratio))),
+			trace.WithSpanProcessor(NewErrorTraceProcessor(traceExporter)),
+		)
+	}
+	opts = append(opts, trace.WithBatcher(traceExporter))
+	tracerProvider := trace.NewTracerProvider(opts...)
 
 	// Set as global tracer provider
 	otel.SetTracerProvider(tracerProvider)
 
+	// Create metric provider on the same collector connection
+	metricExporter, err := otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithGRPCConn(conn))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric exporter: %w", err)
+	}
+	meterOpts := []sdkmetric.Option{
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+		sdkmetric.WithResource(res),
+	}
+	for _, reader := range cfg.MetricReaders {
+		meterOpts = append(meterOpts, sdkmetric.WithReader(reader))
+	}
+	meterProvider := sdkmetric.NewMeterProvider(meterOpts...)
+	otel.SetMeterProvider(meterProvider)
+
 	// Set up propagation to handle incoming trace context from Istio
 	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
 		propagation.TraceContext{},
@@ -84,6 +134,9 @@ func InitTracing(ctx context.Context, cfg TracingConfig) (func(context.Context)
 		slog.Info("Shutting down tracing...")
 		shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 		defer cancel()
-		return tracerProvider.Shutdown(shutdownCtx)
+		return errors.Join(
+			tracerProvider.Shutdown(shutdownCtx),
+			meterProvider.Shutdown(shutdownCtx),
+		)
 	}, nil
 }
@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// InProcessTarget is the address to dial an InProcessListener with, together
+// with grpc.WithContextDialer(listener.DialContext).
+const InProcessTarget = "passthrough:///in-process"
+
+// InProcessListener is a net.Listener whose connections never leave the
+// process. Serve the gRPC server on it alongside the network listener.
+type InProcessListener struct {
+	*bufconn.Listener
+}
+
+// inProcessConn marks the server side of an in-process connection, so
+// ServerCredentials can tell it apart from network connections.
+type inProcessConn struct {
+	net.Conn
+}
+
+// RemoteAddr returns an address IsInProcess recognizes, so interceptors can
+// tell calls from the REST gateway apart from network clients.
+func (inProcessConn) RemoteAddr() net.Addr {
+	return inProcessAddr{}
+}
+
+type inProcessAddr struct{}
+
+func (inProcessAddr) Network() string { return "in-process" }
+func (inProcessAddr) String() string  { return "in-process" }
+
+// IsInProcess reports whether addr, such as a peer's, is that of an
+// in-process connection.
+func IsInProcess(addr net.Addr) bool {
+	_, ok := addr.(inProcessAddr)
+	return ok
+}
+
+func NewInProcessListener() *InProcessListener {
+	return &InProcessListener{Listener: bufconn.Listen(1 << 20)}
+}
+
+func (l *InProcessListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return inProcessConn{conn}, nil
+}
+
+// DialContext connects to the listener; it has the signature
+// grpc.WithContextDialer expects.
+func (l *InProcessListener) DialContext(ctx context.Context, _ string) (net.Conn, error) {
+	return l.Listener.DialContext(ctx)
+}
+
+// ServerCredentials secures network connections with TLS and leaves
+// in-process ones, which cannot be intercepted, in plaintext. In-process
+// clients thus need no certificate of their own under mutual TLS.
+func ServerCredentials(tlsConfig credentials.TransportCredentials) credentials.TransportCredentials {
+	return &serverCredentials{TransportCredentials: tlsConfig}
+}
+
+type serverCredentials struct {
+	credentials.TransportCredentials
+}
+
+// inProcessAuthInfo is the AuthInfo of in-process connections.
+type inProcessAuthInfo struct {
+	credentials.CommonAuthInfo
+}
+
+func (inProcessAuthInfo) AuthType() string {
+	return "in-process"
+}
+
+func (c *serverCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	if _, ok := conn.(inProcessConn); ok {
+		return conn, inProcessAuthInfo{credentials.CommonAuthInfo{SecurityLevel: credentials.PrivacyAndIntegrity}}, nil
+	}
+	return c.TransportCredentials.ServerHandshake(conn)
+}
+
+func (c *serverCredentials) Clone() credentials.TransportCredentials {
+	return &serverCredentials{TransportCredentials: c.TransportCredentials.Clone()}
+}
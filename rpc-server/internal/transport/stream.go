@@ -0,0 +1,24 @@
+package transport
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// contextStream is a grpc.ServerStream whose handler sees ctx.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// WithStreamContext returns ss with its context replaced by ctx, for stream
+// interceptors that pass values on to the handler the way unary ones pass a
+// derived context.
+func WithStreamContext(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	return &contextStream{ServerStream: ss, ctx: ctx}
+}
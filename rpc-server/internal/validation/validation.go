@@ -0,0 +1,194 @@
+// Package validation checks UserService requests against the rules the REST
+// client enforces, so calls that reach the server directly are held to the
+// same ones.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"grpc-server/pkg/pb"
+)
+
+// Limits on user fields. Names and ages match the REST client's models;
+// emails are bounded by the users.email column.
+const (
+	MaxNameLength  = 100
+	MaxEmailLength = 255
+	MaxAge         = 150
+)
+
+// ErrMalformedUserID means a user ID is not a UUID. No user can have such an
+// ID, so callers answer it as not found.
+var ErrMalformedUserID = errors.New("user ID is not a UUID")
+
+// FieldError is a request field that failed validation.
+type FieldError struct {
+	Field       string
+	Description string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Description)
+}
+
+// ValidateUserID checks the ID of an existing user.
+func ValidateUserID(id string) error {
+	if id == "" {
+		return &FieldError{Field: "id", Description: "is required"}
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrMalformedUserID
+	}
+	return nil
+}
+
+// ValidateCreateUser checks a new user's fields. The optional ID is left to
+// CreateUser, which reports it with its own reason.
+func ValidateCreateUser(req *pb.CreateUserRequest) error {
+	if req.Name == "" {
+		return &FieldError{Field: "name", Description: "is required"}
+	}
+	if err := validateName(req.Name); err != nil {
+		return err
+	}
+	if req.Email == "" {
+		return &FieldError{Field: "email", Description: "is required"}
+	}
+	if err := validateEmail(req.Email); err != nil {
+		return err
+	}
+	return validateAge(req.Age)
+}
+
+// ValidateUpdateUser checks the fields of an update. Empty fields, and an
+// age of 0, leave the user's value unchanged.
+func ValidateUpdateUser(req *pb.UpdateUserRequest) error {
+	if err := ValidateUserID(req.Id); err != nil {
+		return err
+	}
+	if req.Name != "" {
+		if err := validateName(req.Name); err != nil {
+			return err
+		}
+	}
+	if req.Email != "" {
+		if err := validateEmail(req.Email); err != nil {
+			return err
+		}
+	}
+	return validateAge(req.Age)
+}
+
+// NormalizePage checks the page number and size of a paginated request and
+// fills in their defaults: page 0 is the first page and limit 0 is
+// defaultLimit. Negative values, a limit over maxLimit and a page past the
+// largest offset are rejected rather than clamped, so a client's mistake
+// surfaces instead of returning a page it did not ask for.
+func NormalizePage(page, limit, defaultLimit, maxLimit int32) (int32, int32, error) {
+	limit, err := NormalizeLimit(limit, defaultLimit, maxLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+	switch {
+	case page < 0:
+		return 0, 0, &FieldError{Field: "page", Description: "must not be negative"}
+	case page == 0:
+		page = 1
+	case int64(page-1)*int64(limit) > math.MaxInt32:
+		return 0, 0, &FieldError{Field: "page", Description: fmt.Sprintf("must not exceed %d with a limit of %d", math.MaxInt32/limit+1, limit)}
+	}
+	return page, limit, nil
+}
+
+// NormalizeLimit is NormalizePage for requests paged by token, which have
+// a limit but no page number.
+func NormalizeLimit(limit, defaultLimit, maxLimit int32) (int32, error) {
+	switch {
+	case limit == 0:
+		return defaultLimit, nil
+	case limit < 0 || limit > maxLimit:
+		return 0, &FieldError{Field: "limit", Description: fmt.Sprintf("must be between 1 and %d", maxLimit)}
+	}
+	return limit, nil
+}
+
+// ValidateGetUser checks the ID and read consistency of a GetUser request.
+func ValidateGetUser(req *pb.GetUserRequest) error {
+	if err := ValidateUserID(req.Id); err != nil {
+		return err
+	}
+	return validateEnum("read_consistency", req.ReadConsistency)
+}
+
+// ValidateListUsers checks the enums of a ListUsers request. Its page and
+// limit are checked with NormalizePage by the server, which knows its page
+// sizes.
+func ValidateListUsers(req *pb.ListUsersRequest) error {
+	return validateEnum("read_consistency", req.ReadConsistency)
+}
+
+// ValidateRequestEmailChange checks the ID and new email of a
+// RequestEmailChange request.
+func ValidateRequestEmailChange(req *pb.RequestEmailChangeRequest) error {
+	if err := ValidateUserID(req.Id); err != nil {
+		return err
+	}
+	if req.NewEmail == "" {
+		return &FieldError{Field: "new_email", Description: "is required"}
+	}
+	if err := validateEmail(req.NewEmail); err != nil {
+		var fieldErr *FieldError
+		if errors.As(err, &fieldErr) {
+			return &FieldError{Field: "new_email", Description: fieldErr.Description}
+		}
+		return err
+	}
+	return nil
+}
+
+// ValidateMergeUsers checks the conflict policy of a MergeUsers request.
+func ValidateMergeUsers(req *pb.MergeUsersRequest) error {
+	return validateEnum("conflict_policy", req.ConflictPolicy)
+}
+
+// validateEnum rejects values value's enum does not define, such as those
+// of a newer client, instead of treating them as the zero value.
+func validateEnum(field string, value protoreflect.Enum) error {
+	if value.Descriptor().Values().ByNumber(value.Number()) == nil {
+		return &FieldError{Field: field, Description: fmt.Sprintf("%d is not a known %s", value.Number(), value.Descriptor().Name())}
+	}
+	return nil
+}
+
+func validateName(name string) error {
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return &FieldError{Field: "name", Description: fmt.Sprintf("must not exceed %d characters", MaxNameLength)}
+	}
+	return nil
+}
+
+func validateEmail(email string) error {
+	if len(email) > MaxEmailLength {
+		return &FieldError{Field: "email", Description: fmt.Sprintf("must not exceed %d bytes", MaxEmailLength)}
+	}
+	// ParseAddress also accepts display names and comments; only a bare
+	// address is an email.
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return &FieldError{Field: "email", Description: "is not a valid email address"}
+	}
+	return nil
+}
+
+func validateAge(age int32) error {
+	if age < 0 || age > MaxAge {
+		return &FieldError{Field: "age", Description: fmt.Sprintf("must be between 0 and %d", MaxAge)}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package validation
+
+import (
+	"errors"
+	"math"
+	"net/mail"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"grpc-server/pkg/pb"
+)
+
+// Seeds are the inputs the contract checks and the load tester send on
+// purpose, plus the forms uuid.Parse and mail.ParseAddress accept beyond the
+// plain one.
+var (
+	userIDSeeds = []string{
+		"",
+		"4b1f0d7e-8a9c-4f1e-9b7a-2c3d4e5f6a7b",
+		"4B1F0D7E-8A9C-4F1E-9B7A-2C3D4E5F6A7B",
+		"4b1f0d7e8a9c4f1e9b7a2c3d4e5f6a7b",
+		"urn:uuid:4b1f0d7e-8a9c-4f1e-9b7a-2c3d4e5f6a7b",
+		"{4b1f0d7e-8a9c-4f1e-9b7a-2c3d4e5f6a7b}",
+		"4b1f0d7e-8a9c-4f1e-9b7a-2c3d4e5f6a7",
+		"4b1f0d7e-8a9c-4f1e-9b7a-2c3d4e5f6a7bz",
+		"nf-123456",
+		"00000000-0000-0000-0000-000000000000",
+		"' OR 1=1 --",
+	}
+	nameSeeds = []string{
+		"",
+		"Contract User",
+		"José Ñúñez",
+		"李小龙",
+		"👩‍💻",
+		"a\x00b",
+		"\xff\xfe",
+	}
+	emailSeeds = []string{
+		"",
+		"contract@example.com",
+		"not-an-email",
+		"Contract User <contract@example.com>",
+		"contract@example.com (comment)",
+		"\"quoted local\"@example.com",
+		"user@[127.0.0.1]",
+		"a@b",
+		"@example.com",
+		"ünïcödé@example.com",
+	}
+	ageSeeds = []int32{-1, 0, 30, MaxAge, MaxAge + 1, math.MinInt32, math.MaxInt32}
+)
+
+func FuzzValidateUserID(f *testing.F) {
+	for _, id := range userIDSeeds {
+		f.Add(id)
+	}
+	f.Fuzz(func(t *testing.T, id string) {
+		err := ValidateUserID(id)
+		var fieldErr *FieldError
+		switch {
+		case err == nil:
+			if _, parseErr := uuid.Parse(id); parseErr != nil {
+				t.Fatalf("ValidateUserID(%q) accepted an ID uuid.Parse rejects: %v", id, parseErr)
+			}
+		case errors.Is(err, ErrMalformedUserID):
+			if id == "" {
+				t.Fatalf("ValidateUserID(\"\") = %v, want a required field error", err)
+			}
+		case errors.As(err, &fieldErr):
+			if id != "" || fieldErr.Field != "id" {
+				t.Fatalf("ValidateUserID(%q) = %v, want only empty IDs reported as a field error", id, err)
+			}
+		default:
+			t.Fatalf("ValidateUserID(%q) = %T %v, want a FieldError or ErrMalformedUserID", id, err, err)
+		}
+	})
+}
+
+func FuzzValidateCreateUser(f *testing.F) {
+	for i, name := range nameSeeds {
+		f.Add(name, emailSeeds[i%len(emailSeeds)], ageSeeds[i%len(ageSeeds)])
+	}
+	for i, email := range emailSeeds {
+		f.Add(nameSeeds[i%len(nameSeeds)], email, ageSeeds[i%len(ageSeeds)])
+	}
+	f.Fuzz(func(t *testing.T, name, email string, age int32) {
+		err := ValidateCreateUser(&pb.CreateUserRequest{Name: name, Email: email, Age: age})
+		if err != nil {
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("ValidateCreateUser returned %T %v, want a FieldError", err, err)
+			}
+			return
+		}
+		if name == "" || utf8.RuneCountInString(name) > MaxNameLength {
+			t.Fatalf("ValidateCreateUser accepted name %q", name)
+		}
+		checkAcceptedEmail(t, email)
+		if age < 0 || age > MaxAge {
+			t.Fatalf("ValidateCreateUser accepted age %d", age)
+		}
+	})
+}
+
+func FuzzValidateUpdateUser(f *testing.F) {
+	for i, id := range userIDSeeds {
+		f.Add(id, nameSeeds[i%len(nameSeeds)], emailSeeds[i%len(emailSeeds)], ageSeeds[i%len(ageSeeds)])
+	}
+	f.Fuzz(func(t *testing.T, id, name, email string, age int32) {
+		err := ValidateUpdateUser(&pb.UpdateUserRequest{Id: id, Name: name, Email: email, Age: age})
+		if err != nil {
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) && !errors.Is(err, ErrMalformedUserID) {
+				t.Fatalf("ValidateUpdateUser returned %T %v, want a FieldError or ErrMalformedUserID", err, err)
+			}
+			return
+		}
+		if _, parseErr := uuid.Parse(id); parseErr != nil {
+			t.Fatalf("ValidateUpdateUser accepted ID %q", id)
+		}
+		if utf8.RuneCountInString(name) > MaxNameLength {
+			t.Fatalf("ValidateUpdateUser accepted name %q", name)
+		}
+		if email != "" {
+			checkAcceptedEmail(t, email)
+		}
+		if age < 0 || age > MaxAge {
+			t.Fatalf("ValidateUpdateUser accepted age %d", age)
+		}
+	})
+}
+
+// checkAcceptedEmail fails t unless email is a bare address that fits the
+// users.email column.
+func checkAcceptedEmail(t *testing.T, email string) {
+	t.Helper()
+	if len(email) > MaxEmailLength {
+		t.Fatalf("accepted email of %d bytes", len(email))
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		t.Fatalf("accepted email %q, which is not a bare address", email)
+	}
+}
+
+func FuzzNormalizePage(f *testing.F) {
+	const defaultLimit, maxLimit = 10, 100
+	for _, seed := range [][2]int32{
+		{0, 0}, {1, 10}, {3, 100}, {-1, 10}, {1, -1}, {1, 101},
+		{math.MaxInt32, 1}, {math.MaxInt32, maxLimit}, {math.MaxInt32/maxLimit + 1, maxLimit},
+		{math.MinInt32, math.MinInt32},
+	} {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, page, limit int32) {
+		gotPage, gotLimit, err := NormalizePage(page, limit, defaultLimit, maxLimit)
+		if err != nil {
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("NormalizePage(%d, %d) returned %T %v, want a FieldError", page, limit, err, err)
+			}
+			return
+		}
+		if gotLimit < 1 || gotLimit > maxLimit {
+			t.Fatalf("NormalizePage(%d, %d) returned limit %d", page, limit, gotLimit)
+		}
+		if gotPage < 1 {
+			t.Fatalf("NormalizePage(%d, %d) returned page %d", page, limit, gotPage)
+		}
+		// The offset the server queries with is an int32.
+		if offset := int64(gotPage-1) * int64(gotLimit); offset > math.MaxInt32 {
+			t.Fatalf("NormalizePage(%d, %d) returned an offset of %d", page, limit, offset)
+		}
+	})
+}
@@ -0,0 +1,27 @@
+// Package workpool bounds how many operations run at once when a batch fans
+// out into one database call per item, so a single large batch can't take
+// every pooled connection from interactive requests.
+package workpool
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Run calls fn for every item, at most limit at a time, and returns the first
+// error. After an error no more items are started, and the context passed to
+// calls still running is cancelled. A limit below 1 runs one at a time.
+func Run[T any](ctx context.Context, limit int, items []T, fn func(ctx context.Context, item T) error) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(limit, 1))
+	for _, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			return fn(ctx, item)
+		})
+	}
+	return g.Wait()
+}
@@ -0,0 +1,142 @@
+package apierror
+
+import (
+	"errors"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domain identifies this service in ErrorInfo details.
+const Domain = "user.rpc-server.arch"
+
+// ResourceTypeUser is the ResourceInfo type for user resources.
+const ResourceTypeUser = "user.User"
+
+// Machine-readable reasons carried in ErrorInfo. Clients should branch on these
+// rather than on status messages, which are meant for humans.
+const (
+	ReasonUserNotFound        = "USER_NOT_FOUND"
+	ReasonEmailAlreadyExists  = "EMAIL_ALREADY_EXISTS"
+	ReasonInternal            = "INTERNAL_ERROR"
+	ReasonDeadlineExceeded    = "DEADLINE_EXCEEDED"
+	ReasonExportUnavailable   = "EXPORT_UNAVAILABLE"
+	ReasonVersionNotFound     = "USER_VERSION_NOT_FOUND"
+	ReasonSelfMerge           = "SELF_MERGE"
+	ReasonUserMerged          = "USER_ALREADY_MERGED"
+	ReasonEmailChangeRequired = "EMAIL_CHANGE_REQUIRES_CONFIRMATION"
+	ReasonEmailUnchanged      = "EMAIL_UNCHANGED"
+	ReasonEmailChangeNotFound = "EMAIL_CHANGE_NOT_FOUND"
+	ReasonEmailChangeExpired  = "EMAIL_CHANGE_EXPIRED"
+	ReasonInvalidToken        = "INVALID_CONFIRMATION_TOKEN"
+	ReasonCrossShardMerge     = "CROSS_SHARD_MERGE"
+	ReasonServerBusy          = "SERVER_BUSY"
+	ReasonReadOnly            = "READ_ONLY_MODE"
+	ReasonRateLimited         = "RATE_LIMITED"
+	ReasonDatabaseUnavailable = "DATABASE_UNAVAILABLE"
+	ReasonInvalidUserID       = "INVALID_USER_ID"
+	ReasonUserAlreadyExists   = "USER_ALREADY_EXISTS"
+	ReasonMessageTooLarge     = "MESSAGE_TOO_LARGE"
+	ReasonUnauthenticated     = "UNAUTHENTICATED"
+	ReasonPermissionDenied    = "PERMISSION_DENIED"
+)
+
+// New builds a status error carrying ErrorInfo and, when resource is non-nil, ResourceInfo.
+func New(code codes.Code, reason, msg string, resource *errdetails.ResourceInfo, metadata map[string]string) error {
+	var details []protoadapt.MessageV1
+	if resource != nil {
+		details = append(details, resource)
+	}
+	return build(code, reason, msg, metadata, details...)
+}
+
+// Retry is New for errors the caller should retry after retryDelay; it
+// carries RetryInfo instead of ResourceInfo.
+func Retry(code codes.Code, reason, msg string, retryDelay time.Duration, metadata map[string]string) error {
+	return build(code, reason, msg, metadata, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryDelay)})
+}
+
+func build(code codes.Code, reason, msg string, metadata map[string]string, extra ...protoadapt.MessageV1) error {
+	st := status.New(code, msg)
+
+	details := append([]protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   Domain,
+		Metadata: metadata,
+	}}, extra...)
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// User returns a ResourceInfo describing the user identified by name (an ID or email).
+func User(name, description string) *errdetails.ResourceInfo {
+	return &errdetails.ResourceInfo{
+		ResourceType: ResourceTypeUser,
+		ResourceName: name,
+		Description:  description,
+	}
+}
+
+// ErrorInfo extracts the ErrorInfo detail from err, or nil if absent.
+func ErrorInfo(err error) *errdetails.ErrorInfo {
+	var info *errdetails.ErrorInfo
+	findDetail(err, &info)
+	return info
+}
+
+// ResourceInfo extracts the ResourceInfo detail from err, or nil if absent.
+func ResourceInfo(err error) *errdetails.ResourceInfo {
+	var info *errdetails.ResourceInfo
+	findDetail(err, &info)
+	return info
+}
+
+// RetryInfo extracts the RetryInfo detail from err, or nil if absent.
+func RetryInfo(err error) *errdetails.RetryInfo {
+	var info *errdetails.RetryInfo
+	findDetail(err, &info)
+	return info
+}
+
+// LocalizedMessage extracts the user-facing message in the caller's locale, or
+// nil if the server did not attach one.
+func LocalizedMessage(err error) *errdetails.LocalizedMessage {
+	var msg *errdetails.LocalizedMessage
+	findDetail(err, &msg)
+	return msg
+}
+
+// Reason returns the ErrorInfo reason of err, or "" if it has none.
+func Reason(err error) string {
+	if info := ErrorInfo(err); info != nil {
+		return info.Reason
+	}
+	return ""
+}
+
+// Is reports whether err carries the given reason in this service's domain.
+func Is(err error, reason string) bool {
+	info := ErrorInfo(err)
+	return info != nil && info.Domain == Domain && info.Reason == reason
+}
+
+func findDetail[T any](err error, target *T) {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return
+	}
+	for _, d := range grpcErr.GRPCStatus().Details() {
+		if v, ok := d.(T); ok {
+			*target = v
+			return
+		}
+	}
+}
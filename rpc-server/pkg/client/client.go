@@ -0,0 +1,51 @@
+package client
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	pb "grpc-server/pkg/pb"
+	"grpc-server/pkg/serviceconfig"
+)
+
+// Client is a UserService client preconfigured with the default service config
+// (per-method timeouts, retries on UNAVAILABLE and hedged GetUser calls).
+type Client struct {
+	pb.UserServiceClient
+	conn *grpc.ClientConn
+}
+
+// New creates a client for target. Callers supply transport credentials and any
+// other dial options; the default service config and hedging interceptor are
+// prepended so explicit options still win.
+func New(target string, opts ...grpc.DialOption) (*Client, error) {
+	sc, err := serviceconfig.Parse(serviceconfig.JSON)
+	if err != nil {
+		return nil, err
+	}
+
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithDefaultServiceConfig(serviceconfig.JSON),
+		grpc.WithChainUnaryInterceptor(HedgingInterceptor(sc)),
+	}, opts...)
+
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create grpc client: %w", err)
+	}
+
+	return &Client{
+		UserServiceClient: pb.NewUserServiceClient(conn),
+		conn:              conn,
+	}, nil
+}
+
+// Conn returns the underlying connection.
+func (c *Client) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
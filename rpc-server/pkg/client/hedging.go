@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"grpc-server/pkg/serviceconfig"
+)
+
+type attemptResult struct {
+	reply proto.Message
+	opts  *attemptOptions
+	err   error
+}
+
+// attemptOptions are a call's options with the ones grpc writes results
+// into (grpc.Header, grpc.Trailer, grpc.Peer) pointed at one attempt's own
+// copies, so concurrent attempts don't write to the caller's variables at
+// the same time. Only the attempt whose result is returned copies them back.
+type attemptOptions struct {
+	opts    []grpc.CallOption
+	header  metadata.MD
+	trailer metadata.MD
+	peer    peer.Peer
+}
+
+func newAttemptOptions(opts []grpc.CallOption) *attemptOptions {
+	a := &attemptOptions{opts: make([]grpc.CallOption, 0, len(opts))}
+	for _, opt := range opts {
+		switch opt.(type) {
+		case grpc.HeaderCallOption:
+			opt = grpc.Header(&a.header)
+		case grpc.TrailerCallOption:
+			opt = grpc.Trailer(&a.trailer)
+		case grpc.PeerCallOption:
+			opt = grpc.Peer(&a.peer)
+		}
+		a.opts = append(a.opts, opt)
+	}
+	return a
+}
+
+// commit copies the attempt's header, trailer and peer into the caller's
+// options.
+func (a *attemptOptions) commit(opts []grpc.CallOption) {
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			*o.HeaderAddr = a.header
+		case grpc.TrailerCallOption:
+			*o.TrailerAddr = a.trailer
+		case grpc.PeerCallOption:
+			*o.PeerAddr = a.peer
+		}
+	}
+}
+
+// HedgingInterceptor sends up to HedgingPolicy.MaxAttempts copies of a call,
+// spaced by HedgingDelay, and returns the first successful response. A
+// non-fatal failure triggers the next attempt immediately; a fatal one is
+// returned as-is. Methods without a hedging policy are passed through.
+func HedgingInterceptor(sc *serviceconfig.ServiceConfig) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		mc := sc.Lookup(method)
+		replyMsg, ok := reply.(proto.Message)
+		if mc == nil || mc.HedgingPolicy == nil || mc.HedgingPolicy.MaxAttempts <= 1 || !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		policy := mc.HedgingPolicy
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		results := make(chan attemptResult, policy.MaxAttempts)
+		launched, pending := 0, 0
+		launch := func() {
+			launched++
+			pending++
+			attemptReply := replyMsg.ProtoReflect().New().Interface()
+			attemptOpts := newAttemptOptions(opts)
+			go func() {
+				err := invoker(ctx, method, req, attemptReply, cc, attemptOpts.opts...)
+				results <- attemptResult{reply: attemptReply, opts: attemptOpts, err: err}
+			}()
+		}
+
+		launch()
+		timer := time.NewTimer(time.Duration(policy.HedgingDelay))
+		defer timer.Stop()
+
+		var last attemptResult
+		for {
+			select {
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			case <-timer.C:
+				if launched < policy.MaxAttempts {
+					launch()
+					timer.Reset(time.Duration(policy.HedgingDelay))
+				}
+			case res := <-results:
+				pending--
+				if res.err == nil {
+					proto.Reset(replyMsg)
+					proto.Merge(replyMsg, res.reply)
+					res.opts.commit(opts)
+					return nil
+				}
+				last = res
+				if !policy.IsNonFatal(status.Code(res.err)) {
+					res.opts.commit(opts)
+					return res.err
+				}
+				if launched < policy.MaxAttempts {
+					launch()
+					timer.Reset(time.Duration(policy.HedgingDelay))
+				} else if pending == 0 {
+					last.opts.commit(opts)
+					return last.err
+				}
+			}
+		}
+	}
+}
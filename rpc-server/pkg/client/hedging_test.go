@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"grpc-server/pkg/serviceconfig"
+)
+
+const hedgedMethod = "/user.UserService/GetUser"
+
+func hedgingConfig(t *testing.T) *serviceconfig.ServiceConfig {
+	t.Helper()
+	sc, err := serviceconfig.Parse(`{"methodConfig": [{
+		"name": [{"service": "user.UserService", "method": "GetUser"}],
+		"hedgingPolicy": {"maxAttempts": 3, "hedgingDelay": "0.001s", "nonFatalStatusCodes": ["UNAVAILABLE"]}
+	}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sc
+}
+
+// headerInvoker answers attempt n (from 1) with header attempt=n, after
+// outcome(n) decides whether it fails. Attempts that block wait for the
+// call to be cancelled.
+func headerInvoker(outcome func(attempt int) (block bool, err error)) grpc.UnaryInvoker {
+	var attempts atomic.Int32
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		n := int(attempts.Add(1))
+		for _, opt := range opts {
+			if h, ok := opt.(grpc.HeaderCallOption); ok {
+				*h.HeaderAddr = metadata.Pairs("attempt", strconv.Itoa(n))
+			}
+		}
+		block, err := outcome(n)
+		if block {
+			<-ctx.Done()
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		if err == nil {
+			reply.(*wrapperspb.StringValue).Value = strconv.Itoa(n)
+		}
+		return err
+	}
+}
+
+func TestHedgingInterceptor(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	for _, tt := range []struct {
+		name     string
+		outcome  func(attempt int) (bool, error)
+		wantCode codes.Code
+		// wantAttempt is the attempt whose reply and header the caller
+		// sees; empty accepts any single attempt
+		wantAttempt string
+	}{
+		{
+			name:        "first attempt answers",
+			outcome:     func(int) (bool, error) { return false, nil },
+			wantCode:    codes.OK,
+			wantAttempt: "1",
+		},
+		{
+			name:        "hedged attempt answers while the first hangs",
+			outcome:     func(n int) (bool, error) { return n == 1, nil },
+			wantCode:    codes.OK,
+			wantAttempt: "2",
+		},
+		{
+			name: "non-fatal failure moves on",
+			outcome: func(n int) (bool, error) {
+				if n < 3 {
+					return false, unavailable
+				}
+				return false, nil
+			},
+			wantCode:    codes.OK,
+			wantAttempt: "3",
+		},
+		{
+			name: "fatal failure is returned",
+			outcome: func(n int) (bool, error) {
+				return n > 1, status.Error(codes.InvalidArgument, "bad")
+			},
+			wantCode:    codes.InvalidArgument,
+			wantAttempt: "1",
+		},
+		{
+			name:     "every attempt fails",
+			outcome:  func(int) (bool, error) { return false, unavailable },
+			wantCode: codes.Unavailable,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var header metadata.MD
+			reply := &wrapperspb.StringValue{}
+			err := HedgingInterceptor(hedgingConfig(t))(context.Background(), hedgedMethod, &wrapperspb.StringValue{}, reply, nil,
+				headerInvoker(tt.outcome), grpc.Header(&header))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v (%v)", code, tt.wantCode, err)
+			}
+			if err == nil && reply.Value != tt.wantAttempt {
+				t.Errorf("reply from attempt %s, want %s", reply.Value, tt.wantAttempt)
+			}
+			if got := header.Get("attempt"); len(got) != 1 || tt.wantAttempt != "" && got[0] != tt.wantAttempt {
+				t.Errorf("header from attempt %v, want %s", got, tt.wantAttempt)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	"google.golang.org/grpc"
+
+	pb "grpc-server/pkg/pb"
+)
+
+// Done is returned by UsersIterator.Next when there are no more users.
+var Done = errors.New("no more items in iterator")
+
+// DefaultPageSize is used when Users is called with a non-positive page size.
+const DefaultPageSize = 50
+
+// UsersIterator walks every user across ListUsers pages, following each
+// response's next_page_token.
+//
+// A failed page fetch is returned from Next without advancing, so calling Next
+// again retries the same page instead of silently skipping users.
+type UsersIterator struct {
+	ctx      context.Context
+	client   pb.UserServiceClient
+	opts     []grpc.CallOption
+	pageSize int32
+
+	pageToken string
+	buf       []*pb.User
+	total     int32
+	done      bool
+}
+
+// Users returns an iterator over all users, fetching pageSize users per call.
+// The server rejects page sizes above its LIST_MAX_PAGE_SIZE, 100 unless
+// configured otherwise.
+func (c *Client) Users(ctx context.Context, pageSize int32, opts ...grpc.CallOption) *UsersIterator {
+	return NewUsersIterator(ctx, c.UserServiceClient, pageSize, opts...)
+}
+
+// NewUsersIterator is Users for callers holding a plain pb.UserServiceClient.
+func NewUsersIterator(ctx context.Context, client pb.UserServiceClient, pageSize int32, opts ...grpc.CallOption) *UsersIterator {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	return &UsersIterator{
+		ctx:      ctx,
+		client:   client,
+		opts:     opts,
+		pageSize: pageSize,
+	}
+}
+
+// Next returns the next user, or Done when the listing is exhausted.
+func (it *UsersIterator) Next() (*pb.User, error) {
+	for len(it.buf) == 0 {
+		if it.done {
+			return nil, Done
+		}
+		if _, err := it.fetch(); err != nil {
+			return nil, err
+		}
+	}
+
+	user := it.buf[0]
+	it.buf = it.buf[1:]
+	return user, nil
+}
+
+// Total is the server-reported user count from the most recent page, or 0
+// before the first fetch.
+func (it *UsersIterator) Total() int32 {
+	return it.total
+}
+
+// All ranges over every remaining user. Iteration stops after the first error
+// is yielded.
+func (it *UsersIterator) All() iter.Seq2[*pb.User, error] {
+	return func(yield func(*pb.User, error) bool) {
+		for {
+			user, err := it.Next()
+			if err == Done {
+				return
+			}
+			if !yield(user, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Pages ranges over the remaining raw ListUsers responses, one per page.
+// Users buffered by a previous Next call are not replayed.
+func (it *UsersIterator) Pages() iter.Seq2[*pb.ListUsersResponse, error] {
+	return func(yield func(*pb.ListUsersResponse, error) bool) {
+		it.buf = nil
+		for !it.done {
+			resp, err := it.fetch()
+			it.buf = nil
+			if !yield(resp, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (it *UsersIterator) fetch() (*pb.ListUsersResponse, error) {
+	if err := it.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	resp, err := it.client.ListUsers(it.ctx, &pb.ListUsersRequest{
+		Limit:     it.pageSize,
+		PageToken: it.pageToken,
+	}, it.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	it.pageToken = resp.NextPageToken
+	it.total = resp.Total
+	it.buf = resp.Users
+	// Users deleted mid-walk can shorten a page, so rely on the server's
+	// token rather than a short page to detect the end.
+	if resp.NextPageToken == "" {
+		it.done = true
+	}
+	return resp, nil
+}
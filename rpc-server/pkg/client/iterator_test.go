@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"grpc-server/internal/cache/cachetest"
+	"grpc-server/internal/clock"
+	"grpc-server/internal/repository/memory"
+	"grpc-server/internal/server"
+	pb "grpc-server/pkg/pb"
+)
+
+// listClient answers ListUsers from a server and records the limit of every
+// call. Other methods are not implemented.
+type listClient struct {
+	pb.UserServiceClient
+	server *server.CachedUserServer
+	limits []int32
+	// afterPage runs after each page is served
+	afterPage func()
+}
+
+func (c *listClient) ListUsers(ctx context.Context, req *pb.ListUsersRequest, _ ...grpc.CallOption) (*pb.ListUsersResponse, error) {
+	c.limits = append(c.limits, req.Limit)
+	resp, err := c.server.ListUsers(ctx, req)
+	if err == nil && c.afterPage != nil {
+		c.afterPage()
+	}
+	return resp, err
+}
+
+func newListClient(t *testing.T, users int, opts ...server.Option) *listClient {
+	t.Helper()
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	opts = append(opts, server.WithClock(clk))
+	s := server.NewCachedUserServer(memory.NewUserRepository(memory.WithClock(clk)), cachetest.New(clk), slog.New(slog.DiscardHandler), opts...)
+	for i := range users {
+		// Users are listed newest first; distinct times keep the order stable
+		clk.Advance(time.Second)
+		_, err := s.CreateUser(t.Context(), &pb.CreateUserRequest{Name: "User", Email: fmt.Sprintf("user%d@example.com", i), Age: 30})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &listClient{server: s}
+}
+
+func TestUsersIterator(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		users int
+		// maxPageSize is the server's, 100 if 0
+		maxPageSize int
+		pageSize    int32
+		wantLimit   int32
+		wantCalls   int
+	}{
+		{"default page size", 120, 0, 0, DefaultPageSize, 3},
+		{"exact pages", 20, 0, 10, 10, 2},
+		{"larger pages the server allows", 500, 1000, 250, 250, 2},
+		{"no users", 0, 0, 10, 10, 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []server.Option
+			if tt.maxPageSize > 0 {
+				opts = append(opts, server.WithPageSizes(10, tt.maxPageSize))
+			}
+			c := newListClient(t, tt.users, opts...)
+			it := NewUsersIterator(t.Context(), c, tt.pageSize)
+			seen := make(map[string]bool)
+			for user, err := range it.All() {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if seen[user.Id] {
+					t.Fatalf("user %s returned twice", user.Id)
+				}
+				seen[user.Id] = true
+			}
+			if len(seen) != tt.users {
+				t.Errorf("iterated %d users, want %d", len(seen), tt.users)
+			}
+			if len(c.limits) != tt.wantCalls {
+				t.Errorf("made %d ListUsers calls, want %d", len(c.limits), tt.wantCalls)
+			}
+			for _, limit := range c.limits {
+				if limit != tt.wantLimit {
+					t.Fatalf("requested pages of %d, want %d", limit, tt.wantLimit)
+				}
+			}
+		})
+	}
+}
+
+func TestUsersIteratorReturnsPageSizeErrors(t *testing.T) {
+	c := newListClient(t, 1)
+	it := NewUsersIterator(t.Context(), c, 101)
+	if _, err := it.Next(); status.Code(err) != grpc_codes.InvalidArgument {
+		t.Fatalf("Next() = %v, want the server's InvalidArgument", err)
+	}
+}
+
+// Deleting users mid-walk shortens the listing; the iterator still ends
+// when the server returns no next page token.
+func TestUsersIteratorEndsWhenUsersAreDeleted(t *testing.T) {
+	c := newListClient(t, 30)
+	ctx := t.Context()
+	c.afterPage = func() {
+		list, err := c.server.ListUsers(ctx, &pb.ListUsersRequest{Limit: 5, ReadConsistency: pb.ReadConsistency_READ_CONSISTENCY_STRONG})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, user := range list.Users {
+			if _, err := c.server.DeleteUser(ctx, &pb.DeleteUserRequest{Id: user.Id}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	it := NewUsersIterator(ctx, c, 10)
+	n := 0
+	for _, err := range it.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n == 0 || n > 30 {
+		t.Errorf("iterated %d users, want between 1 and 30", n)
+	}
+	if _, err := it.Next(); err != Done {
+		t.Errorf("Next after the last page = %v, want Done", err)
+	}
+}
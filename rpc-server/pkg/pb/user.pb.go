@@ -9,6 +9,7 @@ package pb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,6 +22,296 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// User lifecycle status
+type UserStatus int32
+
+const (
+	UserStatus_USER_STATUS_UNSPECIFIED UserStatus = 0
+	UserStatus_USER_STATUS_ACTIVE      UserStatus = 1
+	UserStatus_USER_STATUS_EXPIRED     UserStatus = 2 // set by the inactive account expiry job
+	UserStatus_USER_STATUS_MERGED      UserStatus = 3 // soft-deleted by MergeUsers, see User.merged_into
+)
+
+// Enum value maps for UserStatus.
+var (
+	UserStatus_name = map[int32]string{
+		0: "USER_STATUS_UNSPECIFIED",
+		1: "USER_STATUS_ACTIVE",
+		2: "USER_STATUS_EXPIRED",
+		3: "USER_STATUS_MERGED",
+	}
+	UserStatus_value = map[string]int32{
+		"USER_STATUS_UNSPECIFIED": 0,
+		"USER_STATUS_ACTIVE":      1,
+		"USER_STATUS_EXPIRED":     2,
+		"USER_STATUS_MERGED":      3,
+	}
+)
+
+func (x UserStatus) Enum() *UserStatus {
+	p := new(UserStatus)
+	*p = x
+	return p
+}
+
+func (x UserStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_enumTypes[0].Descriptor()
+}
+
+func (UserStatus) Type() protoreflect.EnumType {
+	return &file_user_proto_enumTypes[0]
+}
+
+func (x UserStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserStatus.Descriptor instead.
+func (UserStatus) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{0}
+}
+
+// What a successful call did, for clients to act on instead of the
+// human-readable message fields.
+type Outcome int32
+
+const (
+	Outcome_OUTCOME_UNSPECIFIED            Outcome = 0
+	Outcome_OUTCOME_CREATED                Outcome = 1
+	Outcome_OUTCOME_RETRIEVED              Outcome = 2
+	Outcome_OUTCOME_NOT_MODIFIED           Outcome = 3 // see GetUserResponse.not_modified
+	Outcome_OUTCOME_UPDATED                Outcome = 4
+	Outcome_OUTCOME_DELETED                Outcome = 5
+	Outcome_OUTCOME_EMAIL_CHANGE_REQUESTED Outcome = 6
+	Outcome_OUTCOME_EMAIL_CHANGED          Outcome = 7
+	Outcome_OUTCOME_ERASED                 Outcome = 8
+	Outcome_OUTCOME_REVERTED               Outcome = 9
+	Outcome_OUTCOME_MERGED                 Outcome = 10
+)
+
+// Enum value maps for Outcome.
+var (
+	Outcome_name = map[int32]string{
+		0:  "OUTCOME_UNSPECIFIED",
+		1:  "OUTCOME_CREATED",
+		2:  "OUTCOME_RETRIEVED",
+		3:  "OUTCOME_NOT_MODIFIED",
+		4:  "OUTCOME_UPDATED",
+		5:  "OUTCOME_DELETED",
+		6:  "OUTCOME_EMAIL_CHANGE_REQUESTED",
+		7:  "OUTCOME_EMAIL_CHANGED",
+		8:  "OUTCOME_ERASED",
+		9:  "OUTCOME_REVERTED",
+		10: "OUTCOME_MERGED",
+	}
+	Outcome_value = map[string]int32{
+		"OUTCOME_UNSPECIFIED":            0,
+		"OUTCOME_CREATED":                1,
+		"OUTCOME_RETRIEVED":              2,
+		"OUTCOME_NOT_MODIFIED":           3,
+		"OUTCOME_UPDATED":                4,
+		"OUTCOME_DELETED":                5,
+		"OUTCOME_EMAIL_CHANGE_REQUESTED": 6,
+		"OUTCOME_EMAIL_CHANGED":          7,
+		"OUTCOME_ERASED":                 8,
+		"OUTCOME_REVERTED":               9,
+		"OUTCOME_MERGED":                 10,
+	}
+)
+
+func (x Outcome) Enum() *Outcome {
+	p := new(Outcome)
+	*p = x
+	return p
+}
+
+func (x Outcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Outcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_enumTypes[1].Descriptor()
+}
+
+func (Outcome) Type() protoreflect.EnumType {
+	return &file_user_proto_enumTypes[1]
+}
+
+func (x Outcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Outcome.Descriptor instead.
+func (Outcome) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{1}
+}
+
+// Where reads may be answered from.
+type ReadConsistency int32
+
+const (
+	ReadConsistency_READ_CONSISTENCY_UNSPECIFIED ReadConsistency = 0 // treated as CACHE_OK
+	// May be served from the cache, up to the cache TTL stale.
+	ReadConsistency_READ_CONSISTENCY_CACHE_OK ReadConsistency = 1
+	// Read from the database, e.g. right after a write made through another
+	// channel. Fails rather than fall back to the cache while the database is
+	// unavailable.
+	ReadConsistency_READ_CONSISTENCY_STRONG ReadConsistency = 2
+)
+
+// Enum value maps for ReadConsistency.
+var (
+	ReadConsistency_name = map[int32]string{
+		0: "READ_CONSISTENCY_UNSPECIFIED",
+		1: "READ_CONSISTENCY_CACHE_OK",
+		2: "READ_CONSISTENCY_STRONG",
+	}
+	ReadConsistency_value = map[string]int32{
+		"READ_CONSISTENCY_UNSPECIFIED": 0,
+		"READ_CONSISTENCY_CACHE_OK":    1,
+		"READ_CONSISTENCY_STRONG":      2,
+	}
+)
+
+func (x ReadConsistency) Enum() *ReadConsistency {
+	p := new(ReadConsistency)
+	*p = x
+	return p
+}
+
+func (x ReadConsistency) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReadConsistency) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_enumTypes[2].Descriptor()
+}
+
+func (ReadConsistency) Type() protoreflect.EnumType {
+	return &file_user_proto_enumTypes[2]
+}
+
+func (x ReadConsistency) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReadConsistency.Descriptor instead.
+func (ReadConsistency) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{2}
+}
+
+// Merge Users
+// How MergeUsers resolves profile fields (name, age) that differ between the
+// source and target. The target always keeps its own email.
+type MergeConflictPolicy int32
+
+const (
+	MergeConflictPolicy_MERGE_CONFLICT_POLICY_UNSPECIFIED   MergeConflictPolicy = 0 // same as KEEP_TARGET
+	MergeConflictPolicy_MERGE_CONFLICT_POLICY_KEEP_TARGET   MergeConflictPolicy = 1
+	MergeConflictPolicy_MERGE_CONFLICT_POLICY_PREFER_SOURCE MergeConflictPolicy = 2
+	MergeConflictPolicy_MERGE_CONFLICT_POLICY_NEWEST        MergeConflictPolicy = 3 // take the fields of whichever user was updated last
+)
+
+// Enum value maps for MergeConflictPolicy.
+var (
+	MergeConflictPolicy_name = map[int32]string{
+		0: "MERGE_CONFLICT_POLICY_UNSPECIFIED",
+		1: "MERGE_CONFLICT_POLICY_KEEP_TARGET",
+		2: "MERGE_CONFLICT_POLICY_PREFER_SOURCE",
+		3: "MERGE_CONFLICT_POLICY_NEWEST",
+	}
+	MergeConflictPolicy_value = map[string]int32{
+		"MERGE_CONFLICT_POLICY_UNSPECIFIED":   0,
+		"MERGE_CONFLICT_POLICY_KEEP_TARGET":   1,
+		"MERGE_CONFLICT_POLICY_PREFER_SOURCE": 2,
+		"MERGE_CONFLICT_POLICY_NEWEST":        3,
+	}
+)
+
+func (x MergeConflictPolicy) Enum() *MergeConflictPolicy {
+	p := new(MergeConflictPolicy)
+	*p = x
+	return p
+}
+
+func (x MergeConflictPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MergeConflictPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_enumTypes[3].Descriptor()
+}
+
+func (MergeConflictPolicy) Type() protoreflect.EnumType {
+	return &file_user_proto_enumTypes[3]
+}
+
+func (x MergeConflictPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MergeConflictPolicy.Descriptor instead.
+func (MergeConflictPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{3}
+}
+
+// Under rate limiting, lower classes are shed first.
+type PriorityClass int32
+
+const (
+	PriorityClass_PRIORITY_CLASS_UNSPECIFIED PriorityClass = 0 // treated as interactive
+	PriorityClass_PRIORITY_CLASS_INTERACTIVE PriorityClass = 1
+	PriorityClass_PRIORITY_CLASS_BATCH       PriorityClass = 2
+	PriorityClass_PRIORITY_CLASS_LOAD_TEST   PriorityClass = 3
+)
+
+// Enum value maps for PriorityClass.
+var (
+	PriorityClass_name = map[int32]string{
+		0: "PRIORITY_CLASS_UNSPECIFIED",
+		1: "PRIORITY_CLASS_INTERACTIVE",
+		2: "PRIORITY_CLASS_BATCH",
+		3: "PRIORITY_CLASS_LOAD_TEST",
+	}
+	PriorityClass_value = map[string]int32{
+		"PRIORITY_CLASS_UNSPECIFIED": 0,
+		"PRIORITY_CLASS_INTERACTIVE": 1,
+		"PRIORITY_CLASS_BATCH":       2,
+		"PRIORITY_CLASS_LOAD_TEST":   3,
+	}
+)
+
+func (x PriorityClass) Enum() *PriorityClass {
+	p := new(PriorityClass)
+	*p = x
+	return p
+}
+
+func (x PriorityClass) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PriorityClass) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_enumTypes[4].Descriptor()
+}
+
+func (PriorityClass) Type() protoreflect.EnumType {
+	return &file_user_proto_enumTypes[4]
+}
+
+func (x PriorityClass) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PriorityClass.Descriptor instead.
+func (PriorityClass) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{4}
+}
+
 // User message
 type User struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -30,6 +321,8 @@ type User struct {
 	Age           int32                  `protobuf:"varint,4,opt,name=age,proto3" json:"age,omitempty"`
 	CreatedAt     int64                  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     int64                  `protobuf:"varint,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Status        UserStatus             `protobuf:"varint,7,opt,name=status,proto3,enum=user.UserStatus" json:"status,omitempty"`
+	MergedInto    string                 `protobuf:"bytes,8,opt,name=merged_into,json=mergedInto,proto3" json:"merged_into,omitempty"` // surviving user ID when status is MERGED
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -106,12 +399,29 @@ func (x *User) GetUpdatedAt() int64 {
 	return 0
 }
 
+func (x *User) GetStatus() UserStatus {
+	if x != nil {
+		return x.Status
+	}
+	return UserStatus_USER_STATUS_UNSPECIFIED
+}
+
+func (x *User) GetMergedInto() string {
+	if x != nil {
+		return x.MergedInto
+	}
+	return ""
+}
+
 // Create User
 type CreateUserRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Age           int32                  `protobuf:"varint,3,opt,name=age,proto3" json:"age,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Age   int32                  `protobuf:"varint,3,opt,name=age,proto3" json:"age,omitempty"`
+	// Optional ID for the new user, for migrations and imports that must keep
+	// existing IDs. Must be a UUID; generated by the server when empty.
+	Id            string `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -167,10 +477,22 @@ func (x *CreateUserRequest) GetAge() int32 {
 	return 0
 }
 
+func (x *CreateUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
 type CreateUserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// Human-readable and English only; not part of the API contract. Empty
+	// when the server runs with RESPONSE_MESSAGES_ENABLED=false.
+	//
+	// Deprecated: Marked as deprecated in user.proto.
+	Message       string  `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Outcome       Outcome `protobuf:"varint,3,opt,name=outcome,proto3,enum=user.Outcome" json:"outcome,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -212,6 +534,7 @@ func (x *CreateUserResponse) GetUser() *User {
 	return nil
 }
 
+// Deprecated: Marked as deprecated in user.proto.
 func (x *CreateUserResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
@@ -219,12 +542,22 @@ func (x *CreateUserResponse) GetMessage() string {
 	return ""
 }
 
+func (x *CreateUserResponse) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
 // Get User
 type GetUserRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// User fields to return, e.g. "id,name"; empty returns them all.
+	ReadMask        *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	ReadConsistency ReadConsistency        `protobuf:"varint,3,opt,name=read_consistency,json=readConsistency,proto3,enum=user.ReadConsistency" json:"read_consistency,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *GetUserRequest) Reset() {
@@ -264,10 +597,32 @@ func (x *GetUserRequest) GetId() string {
 	return ""
 }
 
+func (x *GetUserRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *GetUserRequest) GetReadConsistency() ReadConsistency {
+	if x != nil {
+		return x.ReadConsistency
+	}
+	return ReadConsistency_READ_CONSISTENCY_UNSPECIFIED
+}
+
 type GetUserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// Deprecated: Marked as deprecated in user.proto.
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // see CreateUserResponse.message
+	// Served from the cache while the database is unreachable; the user may
+	// have changed since.
+	Stale bool `protobuf:"varint,3,opt,name=stale,proto3" json:"stale,omitempty"`
+	// The caller's if-none-match metadata names the current etag, so user is
+	// left unset.
+	NotModified   bool    `protobuf:"varint,4,opt,name=not_modified,json=notModified,proto3" json:"not_modified,omitempty"`
+	Outcome       Outcome `protobuf:"varint,5,opt,name=outcome,proto3,enum=user.Outcome" json:"outcome,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -309,6 +664,7 @@ func (x *GetUserResponse) GetUser() *User {
 	return nil
 }
 
+// Deprecated: Marked as deprecated in user.proto.
 func (x *GetUserResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
@@ -316,31 +672,50 @@ func (x *GetUserResponse) GetMessage() string {
 	return ""
 }
 
-// Update User
-type UpdateUserRequest struct {
+func (x *GetUserResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *GetUserResponse) GetNotModified() bool {
+	if x != nil {
+		return x.NotModified
+	}
+	return false
+}
+
+func (x *GetUserResponse) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+// Get User At Time
+type GetUserAtTimeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
-	Age           int32                  `protobuf:"varint,4,opt,name=age,proto3" json:"age,omitempty"`
+	At            int64                  `protobuf:"varint,2,opt,name=at,proto3" json:"at,omitempty"` // unix seconds
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateUserRequest) Reset() {
-	*x = UpdateUserRequest{}
+func (x *GetUserAtTimeRequest) Reset() {
+	*x = GetUserAtTimeRequest{}
 	mi := &file_user_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserRequest) String() string {
+func (x *GetUserAtTimeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserRequest) ProtoMessage() {}
+func (*GetUserAtTimeRequest) ProtoMessage() {}
 
-func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+func (x *GetUserAtTimeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_user_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -352,61 +727,52 @@ func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
-func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetUserAtTimeRequest.ProtoReflect.Descriptor instead.
+func (*GetUserAtTimeRequest) Descriptor() ([]byte, []int) {
 	return file_user_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *UpdateUserRequest) GetId() string {
+func (x *GetUserAtTimeRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *UpdateUserRequest) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *UpdateUserRequest) GetEmail() string {
-	if x != nil {
-		return x.Email
-	}
-	return ""
-}
-
-func (x *UpdateUserRequest) GetAge() int32 {
+func (x *GetUserAtTimeRequest) GetAt() int64 {
 	if x != nil {
-		return x.Age
+		return x.At
 	}
 	return 0
 }
 
-type UpdateUserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+type GetUserAtTimeResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	User      *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	ValidFrom int64                  `protobuf:"varint,2,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`
+	ValidTo   int64                  `protobuf:"varint,3,opt,name=valid_to,json=validTo,proto3" json:"valid_to,omitempty"` // 0 if this is the current version
+	// Deprecated: Marked as deprecated in user.proto.
+	Message       string  `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`                       // see CreateUserResponse.message
+	VersionId     int64   `protobuf:"varint,5,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"` // pass to RevertUser to restore this version
+	Outcome       Outcome `protobuf:"varint,6,opt,name=outcome,proto3,enum=user.Outcome" json:"outcome,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateUserResponse) Reset() {
-	*x = UpdateUserResponse{}
+func (x *GetUserAtTimeResponse) Reset() {
+	*x = GetUserAtTimeResponse{}
 	mi := &file_user_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserResponse) String() string {
+func (x *GetUserAtTimeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserResponse) ProtoMessage() {}
+func (*GetUserAtTimeResponse) ProtoMessage() {}
 
-func (x *UpdateUserResponse) ProtoReflect() protoreflect.Message {
+func (x *GetUserAtTimeResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_user_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -418,47 +784,79 @@ func (x *UpdateUserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserResponse.ProtoReflect.Descriptor instead.
-func (*UpdateUserResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetUserAtTimeResponse.ProtoReflect.Descriptor instead.
+func (*GetUserAtTimeResponse) Descriptor() ([]byte, []int) {
 	return file_user_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *UpdateUserResponse) GetUser() *User {
+func (x *GetUserAtTimeResponse) GetUser() *User {
 	if x != nil {
 		return x.User
 	}
 	return nil
 }
 
-func (x *UpdateUserResponse) GetMessage() string {
+func (x *GetUserAtTimeResponse) GetValidFrom() int64 {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return 0
+}
+
+func (x *GetUserAtTimeResponse) GetValidTo() int64 {
+	if x != nil {
+		return x.ValidTo
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in user.proto.
+func (x *GetUserAtTimeResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-// Delete User
-type DeleteUserRequest struct {
+func (x *GetUserAtTimeResponse) GetVersionId() int64 {
+	if x != nil {
+		return x.VersionId
+	}
+	return 0
+}
+
+func (x *GetUserAtTimeResponse) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+// Update User
+type UpdateUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"` // must be empty or the current email; see RequestEmailChange
+	Age           int32                  `protobuf:"varint,4,opt,name=age,proto3" json:"age,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteUserRequest) Reset() {
-	*x = DeleteUserRequest{}
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
 	mi := &file_user_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteUserRequest) String() string {
+func (x *UpdateUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteUserRequest) ProtoMessage() {}
+func (*UpdateUserRequest) ProtoMessage() {}
 
-func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_user_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -470,40 +868,2356 @@ func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
-func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
 	return file_user_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *DeleteUserRequest) GetId() string {
+func (x *UpdateUserRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-type DeleteUserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *DeleteUserResponse) Reset() {
-	*x = DeleteUserResponse{}
-	mi := &file_user_proto_msgTypes[8]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *UpdateUserRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
 }
 
-func (x *DeleteUserResponse) String() string {
+func (x *UpdateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetAge() int32 {
+	if x != nil {
+		return x.Age
+	}
+	return 0
+}
+
+type UpdateUserResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// Deprecated: Marked as deprecated in user.proto.
+	Message       string  `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // see CreateUserResponse.message
+	Outcome       Outcome `protobuf:"varint,3,opt,name=outcome,proto3,enum=user.Outcome" json:"outcome,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserResponse) Reset() {
+	*x = UpdateUserResponse{}
+	mi := &file_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserResponse) ProtoMessage() {}
+
+func (x *UpdateUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserResponse.ProtoReflect.Descriptor instead.
+func (*UpdateUserResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateUserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in user.proto.
+func (x *UpdateUserResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UpdateUserResponse) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+// Request Email Change
+type RequestEmailChangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	NewEmail      string                 `protobuf:"bytes,2,opt,name=new_email,json=newEmail,proto3" json:"new_email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestEmailChangeRequest) Reset() {
+	*x = RequestEmailChangeRequest{}
+	mi := &file_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestEmailChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestEmailChangeRequest) ProtoMessage() {}
+
+func (x *RequestEmailChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestEmailChangeRequest.ProtoReflect.Descriptor instead.
+func (*RequestEmailChangeRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RequestEmailChangeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RequestEmailChangeRequest) GetNewEmail() string {
+	if x != nil {
+		return x.NewEmail
+	}
+	return ""
+}
+
+type RequestEmailChangeResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ExpiresAt int64                  `protobuf:"varint,1,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Deprecated: Marked as deprecated in user.proto.
+	Message       string  `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // see CreateUserResponse.message
+	Outcome       Outcome `protobuf:"varint,3,opt,name=outcome,proto3,enum=user.Outcome" json:"outcome,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestEmailChangeResponse) Reset() {
+	*x = RequestEmailChangeResponse{}
+	mi := &file_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestEmailChangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestEmailChangeResponse) ProtoMessage() {}
+
+func (x *RequestEmailChangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestEmailChangeResponse.ProtoReflect.Descriptor instead.
+func (*RequestEmailChangeResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RequestEmailChangeResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in user.proto.
+func (x *RequestEmailChangeResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RequestEmailChangeResponse) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+// Confirm Email Change
+type ConfirmEmailChangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmEmailChangeRequest) Reset() {
+	*x = ConfirmEmailChangeRequest{}
+	mi := &file_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmEmailChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmEmailChangeRequest) ProtoMessage() {}
+
+func (x *ConfirmEmailChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmEmailChangeRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmEmailChangeRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ConfirmEmailChangeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ConfirmEmailChangeRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type ConfirmEmailChangeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// Deprecated: Marked as deprecated in user.proto.
+	Message       string  `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // see CreateUserResponse.message
+	Outcome       Outcome `protobuf:"varint,3,opt,name=outcome,proto3,enum=user.Outcome" json:"outcome,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmEmailChangeResponse) Reset() {
+	*x = ConfirmEmailChangeResponse{}
+	mi := &file_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmEmailChangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmEmailChangeResponse) ProtoMessage() {}
+
+func (x *ConfirmEmailChangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmEmailChangeResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmEmailChangeResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ConfirmEmailChangeResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in user.proto.
+func (x *ConfirmEmailChangeResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ConfirmEmailChangeResponse) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+// Delete User
+type DeleteUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserRequest) Reset() {
+	*x = DeleteUserRequest{}
+	mi := &file_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserRequest) ProtoMessage() {}
+
+func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeleteUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteUserResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deprecated: Marked as deprecated in user.proto.
+	Message       string  `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"` // see CreateUserResponse.message
+	Outcome       Outcome `protobuf:"varint,2,opt,name=outcome,proto3,enum=user.Outcome" json:"outcome,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserResponse) Reset() {
+	*x = DeleteUserResponse{}
+	mi := &file_user_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserResponse) ProtoMessage() {}
+
+func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUserResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{14}
+}
+
+// Deprecated: Marked as deprecated in user.proto.
+func (x *DeleteUserResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DeleteUserResponse) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+// Erase User
+type EraseUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // recorded on the deletion certificate, e.g. a ticket reference
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseUserRequest) Reset() {
+	*x = EraseUserRequest{}
+	mi := &file_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseUserRequest) ProtoMessage() {}
+
+func (x *EraseUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseUserRequest.ProtoReflect.Descriptor instead.
+func (*EraseUserRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *EraseUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EraseUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type EraseUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CertificateId string                 `protobuf:"bytes,1,opt,name=certificate_id,json=certificateId,proto3" json:"certificate_id,omitempty"`
+	ErasedAt      int64                  `protobuf:"varint,2,opt,name=erased_at,json=erasedAt,proto3" json:"erased_at,omitempty"`
+	// Deprecated: Marked as deprecated in user.proto.
+	Message       string  `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"` // see CreateUserResponse.message
+	Outcome       Outcome `protobuf:"varint,4,opt,name=outcome,proto3,enum=user.Outcome" json:"outcome,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseUserResponse) Reset() {
+	*x = EraseUserResponse{}
+	mi := &file_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseUserResponse) ProtoMessage() {}
+
+func (x *EraseUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseUserResponse.ProtoReflect.Descriptor instead.
+func (*EraseUserResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *EraseUserResponse) GetCertificateId() string {
+	if x != nil {
+		return x.CertificateId
+	}
+	return ""
+}
+
+func (x *EraseUserResponse) GetErasedAt() int64 {
+	if x != nil {
+		return x.ErasedAt
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in user.proto.
+func (x *EraseUserResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *EraseUserResponse) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+// Export User Data
+type ExportUserDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUserDataRequest) Reset() {
+	*x = ExportUserDataRequest{}
+	mi := &file_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserDataRequest) ProtoMessage() {}
+
+func (x *ExportUserDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserDataRequest.ProtoReflect.Descriptor instead.
+func (*ExportUserDataRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ExportUserDataRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ExportUserDataResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Document           []byte                 `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`   // JSON, see internal/export.Document
+	Signature          string                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"` // base64 signature over document
+	SignatureAlgorithm string                 `protobuf:"bytes,3,opt,name=signature_algorithm,json=signatureAlgorithm,proto3" json:"signature_algorithm,omitempty"`
+	ExportedAt         int64                  `protobuf:"varint,4,opt,name=exported_at,json=exportedAt,proto3" json:"exported_at,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ExportUserDataResponse) Reset() {
+	*x = ExportUserDataResponse{}
+	mi := &file_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserDataResponse) ProtoMessage() {}
+
+func (x *ExportUserDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserDataResponse.ProtoReflect.Descriptor instead.
+func (*ExportUserDataResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ExportUserDataResponse) GetDocument() []byte {
+	if x != nil {
+		return x.Document
+	}
+	return nil
+}
+
+func (x *ExportUserDataResponse) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *ExportUserDataResponse) GetSignatureAlgorithm() string {
+	if x != nil {
+		return x.SignatureAlgorithm
+	}
+	return ""
+}
+
+func (x *ExportUserDataResponse) GetExportedAt() int64 {
+	if x != nil {
+		return x.ExportedAt
+	}
+	return 0
+}
+
+// Revert User
+type RevertUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	VersionId     int64                  `protobuf:"varint,2,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"` // see GetUserAtTimeResponse.version_id
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevertUserRequest) Reset() {
+	*x = RevertUserRequest{}
+	mi := &file_user_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevertUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevertUserRequest) ProtoMessage() {}
+
+func (x *RevertUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevertUserRequest.ProtoReflect.Descriptor instead.
+func (*RevertUserRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RevertUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RevertUserRequest) GetVersionId() int64 {
+	if x != nil {
+		return x.VersionId
+	}
+	return 0
+}
+
+func (x *RevertUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RevertUserResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	User         *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	AuditEntryId string                 `protobuf:"bytes,2,opt,name=audit_entry_id,json=auditEntryId,proto3" json:"audit_entry_id,omitempty"`
+	// Deprecated: Marked as deprecated in user.proto.
+	Message       string  `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"` // see CreateUserResponse.message
+	Outcome       Outcome `protobuf:"varint,4,opt,name=outcome,proto3,enum=user.Outcome" json:"outcome,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevertUserResponse) Reset() {
+	*x = RevertUserResponse{}
+	mi := &file_user_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevertUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevertUserResponse) ProtoMessage() {}
+
+func (x *RevertUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevertUserResponse.ProtoReflect.Descriptor instead.
+func (*RevertUserResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RevertUserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *RevertUserResponse) GetAuditEntryId() string {
+	if x != nil {
+		return x.AuditEntryId
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in user.proto.
+func (x *RevertUserResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RevertUserResponse) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+type MergeUsersRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SourceId       string                 `protobuf:"bytes,1,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"` // the duplicate, soft-deleted by the merge
+	TargetId       string                 `protobuf:"bytes,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // the surviving user
+	ConflictPolicy MergeConflictPolicy    `protobuf:"varint,3,opt,name=conflict_policy,json=conflictPolicy,proto3,enum=user.MergeConflictPolicy" json:"conflict_policy,omitempty"`
+	Reason         string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MergeUsersRequest) Reset() {
+	*x = MergeUsersRequest{}
+	mi := &file_user_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeUsersRequest) ProtoMessage() {}
+
+func (x *MergeUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeUsersRequest.ProtoReflect.Descriptor instead.
+func (*MergeUsersRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *MergeUsersRequest) GetSourceId() string {
+	if x != nil {
+		return x.SourceId
+	}
+	return ""
+}
+
+func (x *MergeUsersRequest) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *MergeUsersRequest) GetConflictPolicy() MergeConflictPolicy {
+	if x != nil {
+		return x.ConflictPolicy
+	}
+	return MergeConflictPolicy_MERGE_CONFLICT_POLICY_UNSPECIFIED
+}
+
+func (x *MergeUsersRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type MergeUsersResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	User         *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"` // the target after the merge
+	AuditEntryId string                 `protobuf:"bytes,2,opt,name=audit_entry_id,json=auditEntryId,proto3" json:"audit_entry_id,omitempty"`
+	// Deprecated: Marked as deprecated in user.proto.
+	Message       string  `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"` // see CreateUserResponse.message
+	Outcome       Outcome `protobuf:"varint,4,opt,name=outcome,proto3,enum=user.Outcome" json:"outcome,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergeUsersResponse) Reset() {
+	*x = MergeUsersResponse{}
+	mi := &file_user_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeUsersResponse) ProtoMessage() {}
+
+func (x *MergeUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeUsersResponse.ProtoReflect.Descriptor instead.
+func (*MergeUsersResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *MergeUsersResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *MergeUsersResponse) GetAuditEntryId() string {
+	if x != nil {
+		return x.AuditEntryId
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in user.proto.
+func (x *MergeUsersResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *MergeUsersResponse) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+// List Users
+type ListUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Page  int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Limit int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Case-insensitive prefix filters for autocomplete; empty matches all.
+	NamePrefix  string `protobuf:"bytes,3,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	EmailPrefix string `protobuf:"bytes,4,opt,name=email_prefix,json=emailPrefix,proto3" json:"email_prefix,omitempty"`
+	// User fields to return for each user; see GetUserRequest.read_mask.
+	ReadMask *fieldmaskpb.FieldMask `protobuf:"bytes,8,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	// next_page_token of a previous response; takes precedence over page.
+	PageToken       string          `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	ReadConsistency ReadConsistency `protobuf:"varint,7,opt,name=read_consistency,json=readConsistency,proto3,enum=user.ReadConsistency" json:"read_consistency,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_user_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListUsersRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetEmailPrefix() string {
+	if x != nil {
+		return x.EmailPrefix
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *ListUsersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetReadConsistency() ReadConsistency {
+	if x != nil {
+		return x.ReadConsistency
+	}
+	return ReadConsistency_READ_CONSISTENCY_UNSPECIFIED
+}
+
+type ListUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Users []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Total int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	// Deprecated: Marked as deprecated in user.proto.
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"` // see CreateUserResponse.message
+	Stale   bool   `protobuf:"varint,4,opt,name=stale,proto3" json:"stale,omitempty"`    // see GetUserResponse.stale
+	// Page size used, after applying the server's default.
+	Limit int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Pass as page_token to get the next page; empty on the last page.
+	NextPageToken string  `protobuf:"bytes,6,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	Outcome       Outcome `protobuf:"varint,7,opt,name=outcome,proto3,enum=user.Outcome" json:"outcome,omitempty"`
+	Page          int32   `protobuf:"varint,8,opt,name=page,proto3" json:"page,omitempty"` // page served, counting from 1
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_user_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in user.proto.
+func (x *ListUsersResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListUsersResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *ListUsersResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListUsersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListUsersResponse) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+func (x *ListUsersResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+// Test Error
+type TestErrorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StatusCode    string                 `protobuf:"bytes,1,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestErrorRequest) Reset() {
+	*x = TestErrorRequest{}
+	mi := &file_user_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestErrorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestErrorRequest) ProtoMessage() {}
+
+func (x *TestErrorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestErrorRequest.ProtoReflect.Descriptor instead.
+func (*TestErrorRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *TestErrorRequest) GetStatusCode() string {
+	if x != nil {
+		return x.StatusCode
+	}
+	return ""
+}
+
+type TestErrorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	TraceId       string                 `protobuf:"bytes,2,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestErrorResponse) Reset() {
+	*x = TestErrorResponse{}
+	mi := &file_user_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestErrorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestErrorResponse) ProtoMessage() {}
+
+func (x *TestErrorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestErrorResponse.ProtoReflect.Descriptor instead.
+func (*TestErrorResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *TestErrorResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TestErrorResponse) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+type TestLatencyRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	DurationMs int64                  `protobuf:"varint,1,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	// Each sleep is moved by a random amount within plus or minus jitter_ms.
+	JitterMs      int64 `protobuf:"varint,2,opt,name=jitter_ms,json=jitterMs,proto3" json:"jitter_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestLatencyRequest) Reset() {
+	*x = TestLatencyRequest{}
+	mi := &file_user_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestLatencyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestLatencyRequest) ProtoMessage() {}
+
+func (x *TestLatencyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestLatencyRequest.ProtoReflect.Descriptor instead.
+func (*TestLatencyRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *TestLatencyRequest) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *TestLatencyRequest) GetJitterMs() int64 {
+	if x != nil {
+		return x.JitterMs
+	}
+	return 0
+}
+
+type TestLatencyStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DurationMs    int64                  `protobuf:"varint,1,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	JitterMs      int64                  `protobuf:"varint,2,opt,name=jitter_ms,json=jitterMs,proto3" json:"jitter_ms,omitempty"`
+	Count         int32                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestLatencyStreamRequest) Reset() {
+	*x = TestLatencyStreamRequest{}
+	mi := &file_user_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestLatencyStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestLatencyStreamRequest) ProtoMessage() {}
+
+func (x *TestLatencyStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestLatencyStreamRequest.ProtoReflect.Descriptor instead.
+func (*TestLatencyStreamRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *TestLatencyStreamRequest) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *TestLatencyStreamRequest) GetJitterMs() int64 {
+	if x != nil {
+		return x.JitterMs
+	}
+	return 0
+}
+
+func (x *TestLatencyStreamRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type TestLatencyResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	RequestedMs int64                  `protobuf:"varint,1,opt,name=requested_ms,json=requestedMs,proto3" json:"requested_ms,omitempty"` // duration after jitter
+	SleptMs     int64                  `protobuf:"varint,2,opt,name=slept_ms,json=sleptMs,proto3" json:"slept_ms,omitempty"`
+	// Time left before the caller's deadline when the call arrived; 0 if the
+	// call has no deadline.
+	DeadlineRemainingMs int64  `protobuf:"varint,3,opt,name=deadline_remaining_ms,json=deadlineRemainingMs,proto3" json:"deadline_remaining_ms,omitempty"`
+	Sequence            int32  `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"` // position in the stream, starting at 1
+	TraceId             string `protobuf:"bytes,5,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *TestLatencyResponse) Reset() {
+	*x = TestLatencyResponse{}
+	mi := &file_user_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestLatencyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestLatencyResponse) ProtoMessage() {}
+
+func (x *TestLatencyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestLatencyResponse.ProtoReflect.Descriptor instead.
+func (*TestLatencyResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *TestLatencyResponse) GetRequestedMs() int64 {
+	if x != nil {
+		return x.RequestedMs
+	}
+	return 0
+}
+
+func (x *TestLatencyResponse) GetSleptMs() int64 {
+	if x != nil {
+		return x.SleptMs
+	}
+	return 0
+}
+
+func (x *TestLatencyResponse) GetDeadlineRemainingMs() int64 {
+	if x != nil {
+		return x.DeadlineRemainingMs
+	}
+	return 0
+}
+
+func (x *TestLatencyResponse) GetSequence() int32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *TestLatencyResponse) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+type TestStreamRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Payload            []byte                 `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"` // ignored apart from its size
+	ResponseSize       int32                  `protobuf:"varint,2,opt,name=response_size,json=responseSize,proto3" json:"response_size,omitempty"`
+	Count              int32                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`                                                       // responses to send for this message, default 1
+	ResponsesPerSecond int32                  `protobuf:"varint,4,opt,name=responses_per_second,json=responsesPerSecond,proto3" json:"responses_per_second,omitempty"` // 0 sends as fast as flow control allows
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TestStreamRequest) Reset() {
+	*x = TestStreamRequest{}
+	mi := &file_user_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestStreamRequest) ProtoMessage() {}
+
+func (x *TestStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestStreamRequest.ProtoReflect.Descriptor instead.
+func (*TestStreamRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *TestStreamRequest) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *TestStreamRequest) GetResponseSize() int32 {
+	if x != nil {
+		return x.ResponseSize
+	}
+	return 0
+}
+
+func (x *TestStreamRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *TestStreamRequest) GetResponsesPerSecond() int32 {
+	if x != nil {
+		return x.ResponsesPerSecond
+	}
+	return 0
+}
+
+type TestStreamResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Sequence           int64                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"` // position in the stream, starting at 1
+	Payload            []byte                 `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	ReceivedBytes      int64                  `protobuf:"varint,3,opt,name=received_bytes,json=receivedBytes,proto3" json:"received_bytes,omitempty"` // payload size of the message being answered
+	TotalReceivedBytes int64                  `protobuf:"varint,4,opt,name=total_received_bytes,json=totalReceivedBytes,proto3" json:"total_received_bytes,omitempty"`
+	TotalSentBytes     int64                  `protobuf:"varint,5,opt,name=total_sent_bytes,json=totalSentBytes,proto3" json:"total_sent_bytes,omitempty"` // including this response's payload
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TestStreamResponse) Reset() {
+	*x = TestStreamResponse{}
+	mi := &file_user_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestStreamResponse) ProtoMessage() {}
+
+func (x *TestStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestStreamResponse.ProtoReflect.Descriptor instead.
+func (*TestStreamResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *TestStreamResponse) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *TestStreamResponse) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *TestStreamResponse) GetReceivedBytes() int64 {
+	if x != nil {
+		return x.ReceivedBytes
+	}
+	return 0
+}
+
+func (x *TestStreamResponse) GetTotalReceivedBytes() int64 {
+	if x != nil {
+		return x.TotalReceivedBytes
+	}
+	return 0
+}
+
+func (x *TestStreamResponse) GetTotalSentBytes() int64 {
+	if x != nil {
+		return x.TotalSentBytes
+	}
+	return 0
+}
+
+type TestEchoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestEchoRequest) Reset() {
+	*x = TestEchoRequest{}
+	mi := &file_user_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestEchoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestEchoRequest) ProtoMessage() {}
+
+func (x *TestEchoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestEchoRequest.ProtoReflect.Descriptor instead.
+func (*TestEchoRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *TestEchoRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type MetadataEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Values        []string               `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetadataEntry) Reset() {
+	*x = MetadataEntry{}
+	mi := &file_user_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetadataEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetadataEntry) ProtoMessage() {}
+
+func (x *MetadataEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetadataEntry.ProtoReflect.Descriptor instead.
+func (*MetadataEntry) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *MetadataEntry) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *MetadataEntry) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type TestEchoResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// Incoming metadata sorted by key. Binary (-bin) values are base64 encoded
+	// and credentials are redacted.
+	Metadata            []*MetadataEntry `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	PeerAddress         string           `protobuf:"bytes,3,opt,name=peer_address,json=peerAddress,proto3" json:"peer_address,omitempty"`
+	PeerAuthType        string           `protobuf:"bytes,4,opt,name=peer_auth_type,json=peerAuthType,proto3" json:"peer_auth_type,omitempty"`        // empty for plaintext connections
+	DeadlineUnixMs      int64            `protobuf:"varint,5,opt,name=deadline_unix_ms,json=deadlineUnixMs,proto3" json:"deadline_unix_ms,omitempty"` // 0 if the call has no deadline
+	DeadlineRemainingMs int64            `protobuf:"varint,6,opt,name=deadline_remaining_ms,json=deadlineRemainingMs,proto3" json:"deadline_remaining_ms,omitempty"`
+	RequestCompression  string           `protobuf:"bytes,7,opt,name=request_compression,json=requestCompression,proto3" json:"request_compression,omitempty"`
+	ResponseCompression string           `protobuf:"bytes,8,opt,name=response_compression,json=responseCompression,proto3" json:"response_compression,omitempty"`
+	AcceptedCompression []string         `protobuf:"bytes,9,rep,name=accepted_compression,json=acceptedCompression,proto3" json:"accepted_compression,omitempty"` // advertised by the client
+	InstanceId          string           `protobuf:"bytes,10,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`                           // host name of the serving instance
+	Method              string           `protobuf:"bytes,11,opt,name=method,proto3" json:"method,omitempty"`
+	TraceId             string           `protobuf:"bytes,12,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *TestEchoResponse) Reset() {
+	*x = TestEchoResponse{}
+	mi := &file_user_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestEchoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestEchoResponse) ProtoMessage() {}
+
+func (x *TestEchoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestEchoResponse.ProtoReflect.Descriptor instead.
+func (*TestEchoResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *TestEchoResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TestEchoResponse) GetMetadata() []*MetadataEntry {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *TestEchoResponse) GetPeerAddress() string {
+	if x != nil {
+		return x.PeerAddress
+	}
+	return ""
+}
+
+func (x *TestEchoResponse) GetPeerAuthType() string {
+	if x != nil {
+		return x.PeerAuthType
+	}
+	return ""
+}
+
+func (x *TestEchoResponse) GetDeadlineUnixMs() int64 {
+	if x != nil {
+		return x.DeadlineUnixMs
+	}
+	return 0
+}
+
+func (x *TestEchoResponse) GetDeadlineRemainingMs() int64 {
+	if x != nil {
+		return x.DeadlineRemainingMs
+	}
+	return 0
+}
+
+func (x *TestEchoResponse) GetRequestCompression() string {
+	if x != nil {
+		return x.RequestCompression
+	}
+	return ""
+}
+
+func (x *TestEchoResponse) GetResponseCompression() string {
+	if x != nil {
+		return x.ResponseCompression
+	}
+	return ""
+}
+
+func (x *TestEchoResponse) GetAcceptedCompression() []string {
+	if x != nil {
+		return x.AcceptedCompression
+	}
+	return nil
+}
+
+func (x *TestEchoResponse) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *TestEchoResponse) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *TestEchoResponse) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+type GetCacheStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Keys to sample with SCAN for per-namespace key counts; 0 uses the
+	// server default.
+	SampleSize    int32 `protobuf:"varint,1,opt,name=sample_size,json=sampleSize,proto3" json:"sample_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCacheStatsRequest) Reset() {
+	*x = GetCacheStatsRequest{}
+	mi := &file_user_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCacheStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCacheStatsRequest) ProtoMessage() {}
+
+func (x *GetCacheStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCacheStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetCacheStatsRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetCacheStatsRequest) GetSampleSize() int32 {
+	if x != nil {
+		return x.SampleSize
+	}
+	return 0
+}
+
+// Cache usage for keys sharing a prefix, such as "user" or "users:list".
+type CacheNamespaceStats struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Namespace string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Lookups served by the answering instance since it started.
+	Hits          int64   `protobuf:"varint,2,opt,name=hits,proto3" json:"hits,omitempty"`
+	Misses        int64   `protobuf:"varint,3,opt,name=misses,proto3" json:"misses,omitempty"`
+	Errors        int64   `protobuf:"varint,4,opt,name=errors,proto3" json:"errors,omitempty"`
+	HitRatio      float64 `protobuf:"fixed64,5,opt,name=hit_ratio,json=hitRatio,proto3" json:"hit_ratio,omitempty"`               // hits / (hits + misses); 0 without lookups
+	SampledKeys   int64   `protobuf:"varint,6,opt,name=sampled_keys,json=sampledKeys,proto3" json:"sampled_keys,omitempty"`       // keys in the SCAN sample
+	EstimatedKeys int64   `protobuf:"varint,7,opt,name=estimated_keys,json=estimatedKeys,proto3" json:"estimated_keys,omitempty"` // sampled_keys scaled to the whole keyspace
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheNamespaceStats) Reset() {
+	*x = CacheNamespaceStats{}
+	mi := &file_user_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheNamespaceStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheNamespaceStats) ProtoMessage() {}
+
+func (x *CacheNamespaceStats) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheNamespaceStats.ProtoReflect.Descriptor instead.
+func (*CacheNamespaceStats) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CacheNamespaceStats) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *CacheNamespaceStats) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *CacheNamespaceStats) GetMisses() int64 {
+	if x != nil {
+		return x.Misses
+	}
+	return 0
+}
+
+func (x *CacheNamespaceStats) GetErrors() int64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *CacheNamespaceStats) GetHitRatio() float64 {
+	if x != nil {
+		return x.HitRatio
+	}
+	return 0
+}
+
+func (x *CacheNamespaceStats) GetSampledKeys() int64 {
+	if x != nil {
+		return x.SampledKeys
+	}
+	return 0
+}
+
+func (x *CacheNamespaceStats) GetEstimatedKeys() int64 {
+	if x != nil {
+		return x.EstimatedKeys
+	}
+	return 0
+}
+
+type CacheMemoryStats struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	UsedMemoryBytes     int64                  `protobuf:"varint,1,opt,name=used_memory_bytes,json=usedMemoryBytes,proto3" json:"used_memory_bytes,omitempty"`
+	UsedMemoryPeakBytes int64                  `protobuf:"varint,2,opt,name=used_memory_peak_bytes,json=usedMemoryPeakBytes,proto3" json:"used_memory_peak_bytes,omitempty"`
+	MaxMemoryBytes      int64                  `protobuf:"varint,3,opt,name=max_memory_bytes,json=maxMemoryBytes,proto3" json:"max_memory_bytes,omitempty"` // 0 when no limit is set
+	MaxMemoryPolicy     string                 `protobuf:"bytes,4,opt,name=max_memory_policy,json=maxMemoryPolicy,proto3" json:"max_memory_policy,omitempty"`
+	FragmentationRatio  float64                `protobuf:"fixed64,5,opt,name=fragmentation_ratio,json=fragmentationRatio,proto3" json:"fragmentation_ratio,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *CacheMemoryStats) Reset() {
+	*x = CacheMemoryStats{}
+	mi := &file_user_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheMemoryStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheMemoryStats) ProtoMessage() {}
+
+func (x *CacheMemoryStats) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheMemoryStats.ProtoReflect.Descriptor instead.
+func (*CacheMemoryStats) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *CacheMemoryStats) GetUsedMemoryBytes() int64 {
+	if x != nil {
+		return x.UsedMemoryBytes
+	}
+	return 0
+}
+
+func (x *CacheMemoryStats) GetUsedMemoryPeakBytes() int64 {
+	if x != nil {
+		return x.UsedMemoryPeakBytes
+	}
+	return 0
+}
+
+func (x *CacheMemoryStats) GetMaxMemoryBytes() int64 {
+	if x != nil {
+		return x.MaxMemoryBytes
+	}
+	return 0
+}
+
+func (x *CacheMemoryStats) GetMaxMemoryPolicy() string {
+	if x != nil {
+		return x.MaxMemoryPolicy
+	}
+	return ""
+}
+
+func (x *CacheMemoryStats) GetFragmentationRatio() float64 {
+	if x != nil {
+		return x.FragmentationRatio
+	}
+	return 0
+}
+
+type GetCacheStatsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Namespaces       []*CacheNamespaceStats `protobuf:"bytes,1,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	TotalKeys        int64                  `protobuf:"varint,2,opt,name=total_keys,json=totalKeys,proto3" json:"total_keys,omitempty"` // DBSIZE
+	SampledKeys      int64                  `protobuf:"varint,3,opt,name=sampled_keys,json=sampledKeys,proto3" json:"sampled_keys,omitempty"`
+	Memory           *CacheMemoryStats      `protobuf:"bytes,4,opt,name=memory,proto3" json:"memory,omitempty"`
+	InstanceId       string                 `protobuf:"bytes,5,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`                        // instance whose hit counts are reported
+	StatsSinceUnixMs int64                  `protobuf:"varint,6,opt,name=stats_since_unix_ms,json=statsSinceUnixMs,proto3" json:"stats_since_unix_ms,omitempty"` // when the instance started counting
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetCacheStatsResponse) Reset() {
+	*x = GetCacheStatsResponse{}
+	mi := &file_user_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCacheStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCacheStatsResponse) ProtoMessage() {}
+
+func (x *GetCacheStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCacheStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetCacheStatsResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetCacheStatsResponse) GetNamespaces() []*CacheNamespaceStats {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+func (x *GetCacheStatsResponse) GetTotalKeys() int64 {
+	if x != nil {
+		return x.TotalKeys
+	}
+	return 0
+}
+
+func (x *GetCacheStatsResponse) GetSampledKeys() int64 {
+	if x != nil {
+		return x.SampledKeys
+	}
+	return 0
+}
+
+func (x *GetCacheStatsResponse) GetMemory() *CacheMemoryStats {
+	if x != nil {
+		return x.Memory
+	}
+	return nil
+}
+
+func (x *GetCacheStatsResponse) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *GetCacheStatsResponse) GetStatsSinceUnixMs() int64 {
+	if x != nil {
+		return x.StatsSinceUnixMs
+	}
+	return 0
+}
+
+// Runtime config
+type RuntimeConfig struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	LogLevel            string                 `protobuf:"bytes,1,opt,name=log_level,json=logLevel,proto3" json:"log_level,omitempty"` // DEBUG, INFO, WARN or ERROR
+	UserCacheTtlSeconds int64                  `protobuf:"varint,2,opt,name=user_cache_ttl_seconds,json=userCacheTtlSeconds,proto3" json:"user_cache_ttl_seconds,omitempty"`
+	ListCacheTtlSeconds int64                  `protobuf:"varint,3,opt,name=list_cache_ttl_seconds,json=listCacheTtlSeconds,proto3" json:"list_cache_ttl_seconds,omitempty"`
+	TraceSampleRatio    float64                `protobuf:"fixed64,4,opt,name=trace_sample_ratio,json=traceSampleRatio,proto3" json:"trace_sample_ratio,omitempty"` // 0 to 1
+	RateLimitQps        float64                `protobuf:"fixed64,5,opt,name=rate_limit_qps,json=rateLimitQps,proto3" json:"rate_limit_qps,omitempty"`             // server-wide; 0 disables rate limiting
+	RateLimitBurst      int32                  `protobuf:"varint,6,opt,name=rate_limit_burst,json=rateLimitBurst,proto3" json:"rate_limit_burst,omitempty"`
+	ReadOnly            bool                   `protobuf:"varint,7,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"` // rejects writes with UNAVAILABLE
+	// Fraction of rate_limit_burst, in [0, 1), that lower priority classes
+	// must leave for more important ones.
+	LowPriorityReserve float64 `protobuf:"fixed64,8,opt,name=low_priority_reserve,json=lowPriorityReserve,proto3" json:"low_priority_reserve,omitempty"`
+	// Callers not listed are interactive. Replaced as a whole when named in
+	// update_mask.
+	CallerPriorities []*CallerPriority `protobuf:"bytes,9,rep,name=caller_priorities,json=callerPriorities,proto3" json:"caller_priorities,omitempty"`
+	// Levels for subsystems that log differently from log_level. Replaced as a
+	// whole when named in update_mask.
+	ModuleLogLevels []*ModuleLogLevel `protobuf:"bytes,10,rep,name=module_log_levels,json=moduleLogLevels,proto3" json:"module_log_levels,omitempty"`
+	// GetUser reads the cache and the database at once and answers with the
+	// first to find the user, trading a database read per call for lower tail
+	// latency while the cache is slow.
+	RaceUserReads bool `protobuf:"varint,11,opt,name=race_user_reads,json=raceUserReads,proto3" json:"race_user_reads,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuntimeConfig) Reset() {
+	*x = RuntimeConfig{}
+	mi := &file_user_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuntimeConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeConfig) ProtoMessage() {}
+
+func (x *RuntimeConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeConfig.ProtoReflect.Descriptor instead.
+func (*RuntimeConfig) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *RuntimeConfig) GetLogLevel() string {
+	if x != nil {
+		return x.LogLevel
+	}
+	return ""
+}
+
+func (x *RuntimeConfig) GetUserCacheTtlSeconds() int64 {
+	if x != nil {
+		return x.UserCacheTtlSeconds
+	}
+	return 0
+}
+
+func (x *RuntimeConfig) GetListCacheTtlSeconds() int64 {
+	if x != nil {
+		return x.ListCacheTtlSeconds
+	}
+	return 0
+}
+
+func (x *RuntimeConfig) GetTraceSampleRatio() float64 {
+	if x != nil {
+		return x.TraceSampleRatio
+	}
+	return 0
+}
+
+func (x *RuntimeConfig) GetRateLimitQps() float64 {
+	if x != nil {
+		return x.RateLimitQps
+	}
+	return 0
+}
+
+func (x *RuntimeConfig) GetRateLimitBurst() int32 {
+	if x != nil {
+		return x.RateLimitBurst
+	}
+	return 0
+}
+
+func (x *RuntimeConfig) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+func (x *RuntimeConfig) GetLowPriorityReserve() float64 {
+	if x != nil {
+		return x.LowPriorityReserve
+	}
+	return 0
+}
+
+func (x *RuntimeConfig) GetCallerPriorities() []*CallerPriority {
+	if x != nil {
+		return x.CallerPriorities
+	}
+	return nil
+}
+
+func (x *RuntimeConfig) GetModuleLogLevels() []*ModuleLogLevel {
+	if x != nil {
+		return x.ModuleLogLevels
+	}
+	return nil
+}
+
+func (x *RuntimeConfig) GetRaceUserReads() bool {
+	if x != nil {
+		return x.RaceUserReads
+	}
+	return false
+}
+
+type CallerPriority struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Caller        string                 `protobuf:"bytes,1,opt,name=caller,proto3" json:"caller,omitempty"` // the subject of the caller's verified token
+	Class         PriorityClass          `protobuf:"varint,2,opt,name=class,proto3,enum=user.PriorityClass" json:"class,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CallerPriority) Reset() {
+	*x = CallerPriority{}
+	mi := &file_user_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CallerPriority) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallerPriority) ProtoMessage() {}
+
+func (x *CallerPriority) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallerPriority.ProtoReflect.Descriptor instead.
+func (*CallerPriority) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *CallerPriority) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+func (x *CallerPriority) GetClass() PriorityClass {
+	if x != nil {
+		return x.Class
+	}
+	return PriorityClass_PRIORITY_CLASS_UNSPECIFIED
+}
+
+type ModuleLogLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Module        string                 `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"` // cache, repository, server or jobs
+	Level         string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`   // DEBUG, INFO, WARN or ERROR
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModuleLogLevel) Reset() {
+	*x = ModuleLogLevel{}
+	mi := &file_user_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModuleLogLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModuleLogLevel) ProtoMessage() {}
+
+func (x *ModuleLogLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModuleLogLevel.ProtoReflect.Descriptor instead.
+func (*ModuleLogLevel) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ModuleLogLevel) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
+}
+
+func (x *ModuleLogLevel) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+type GetRuntimeConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRuntimeConfigRequest) Reset() {
+	*x = GetRuntimeConfigRequest{}
+	mi := &file_user_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRuntimeConfigRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteUserResponse) ProtoMessage() {}
+func (*GetRuntimeConfigRequest) ProtoMessage() {}
 
-func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[8]
+func (x *GetRuntimeConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -514,42 +3228,96 @@ func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteUserResponse.ProtoReflect.Descriptor instead.
-func (*DeleteUserResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use GetRuntimeConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetRuntimeConfigRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *DeleteUserResponse) GetMessage() string {
+type GetRuntimeConfigResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Config          *RuntimeConfig         `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	InstanceId      string                 `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	UpdatedAtUnixMs int64                  `protobuf:"varint,3,opt,name=updated_at_unix_ms,json=updatedAtUnixMs,proto3" json:"updated_at_unix_ms,omitempty"` // 0 until the config is first changed
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetRuntimeConfigResponse) Reset() {
+	*x = GetRuntimeConfigResponse{}
+	mi := &file_user_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRuntimeConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRuntimeConfigResponse) ProtoMessage() {}
+
+func (x *GetRuntimeConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[43]
 	if x != nil {
-		return x.Message
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRuntimeConfigResponse.ProtoReflect.Descriptor instead.
+func (*GetRuntimeConfigResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *GetRuntimeConfigResponse) GetConfig() *RuntimeConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *GetRuntimeConfigResponse) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
 	}
 	return ""
 }
 
-// List Users
-type ListUsersRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+func (x *GetRuntimeConfigResponse) GetUpdatedAtUnixMs() int64 {
+	if x != nil {
+		return x.UpdatedAtUnixMs
+	}
+	return 0
+}
+
+type SetRuntimeConfigRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Config *RuntimeConfig         `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	// Fields of config to apply, e.g. "read_only"; others keep their value.
+	// Must not be empty.
+	UpdateMask    []string `protobuf:"bytes,2,rep,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUsersRequest) Reset() {
-	*x = ListUsersRequest{}
-	mi := &file_user_proto_msgTypes[9]
+func (x *SetRuntimeConfigRequest) Reset() {
+	*x = SetRuntimeConfigRequest{}
+	mi := &file_user_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUsersRequest) String() string {
+func (x *SetRuntimeConfigRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUsersRequest) ProtoMessage() {}
+func (*SetRuntimeConfigRequest) ProtoMessage() {}
 
-func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[9]
+func (x *SetRuntimeConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -560,49 +3328,107 @@ func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
-func (*ListUsersRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use SetRuntimeConfigRequest.ProtoReflect.Descriptor instead.
+func (*SetRuntimeConfigRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *ListUsersRequest) GetPage() int32 {
+func (x *SetRuntimeConfigRequest) GetConfig() *RuntimeConfig {
 	if x != nil {
-		return x.Page
+		return x.Config
 	}
-	return 0
+	return nil
 }
 
-func (x *ListUsersRequest) GetLimit() int32 {
+func (x *SetRuntimeConfigRequest) GetUpdateMask() []string {
 	if x != nil {
-		return x.Limit
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type SetRuntimeConfigResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Config          *RuntimeConfig         `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	InstanceId      string                 `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	UpdatedAtUnixMs int64                  `protobuf:"varint,3,opt,name=updated_at_unix_ms,json=updatedAtUnixMs,proto3" json:"updated_at_unix_ms,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SetRuntimeConfigResponse) Reset() {
+	*x = SetRuntimeConfigResponse{}
+	mi := &file_user_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRuntimeConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRuntimeConfigResponse) ProtoMessage() {}
+
+func (x *SetRuntimeConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRuntimeConfigResponse.ProtoReflect.Descriptor instead.
+func (*SetRuntimeConfigResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *SetRuntimeConfigResponse) GetConfig() *RuntimeConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *SetRuntimeConfigResponse) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *SetRuntimeConfigResponse) GetUpdatedAtUnixMs() int64 {
+	if x != nil {
+		return x.UpdatedAtUnixMs
 	}
 	return 0
 }
 
-type ListUsersResponse struct {
+// Version
+type GetVersionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUsersResponse) Reset() {
-	*x = ListUsersResponse{}
-	mi := &file_user_proto_msgTypes[10]
+func (x *GetVersionRequest) Reset() {
+	*x = GetVersionRequest{}
+	mi := &file_user_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUsersResponse) String() string {
+func (x *GetVersionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUsersResponse) ProtoMessage() {}
+func (*GetVersionRequest) ProtoMessage() {}
 
-func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[10]
+func (x *GetVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -613,55 +3439,126 @@ func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
-func (*ListUsersResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use GetVersionRequest.ProtoReflect.Descriptor instead.
+func (*GetVersionRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *ListUsersResponse) GetUsers() []*User {
+type GetVersionResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Version         string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Commit          string                 `protobuf:"bytes,2,opt,name=commit,proto3" json:"commit,omitempty"`
+	BuildDate       string                 `protobuf:"bytes,3,opt,name=build_date,json=buildDate,proto3" json:"build_date,omitempty"` // RFC 3339; empty if unknown
+	GoVersion       string                 `protobuf:"bytes,4,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
+	Modified        bool                   `protobuf:"varint,5,opt,name=modified,proto3" json:"modified,omitempty"` // built from a checkout with uncommitted changes
+	InstanceId      string                 `protobuf:"bytes,6,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	StartedAtUnixMs int64                  `protobuf:"varint,7,opt,name=started_at_unix_ms,json=startedAtUnixMs,proto3" json:"started_at_unix_ms,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetVersionResponse) Reset() {
+	*x = GetVersionResponse{}
+	mi := &file_user_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersionResponse) ProtoMessage() {}
+
+func (x *GetVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[47]
 	if x != nil {
-		return x.Users
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *ListUsersResponse) GetTotal() int32 {
+// Deprecated: Use GetVersionResponse.ProtoReflect.Descriptor instead.
+func (*GetVersionResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *GetVersionResponse) GetVersion() string {
 	if x != nil {
-		return x.Total
+		return x.Version
 	}
-	return 0
+	return ""
 }
 
-func (x *ListUsersResponse) GetMessage() string {
+func (x *GetVersionResponse) GetCommit() string {
 	if x != nil {
-		return x.Message
+		return x.Commit
 	}
 	return ""
 }
 
-// Test Error
-type TestErrorRequest struct {
+func (x *GetVersionResponse) GetBuildDate() string {
+	if x != nil {
+		return x.BuildDate
+	}
+	return ""
+}
+
+func (x *GetVersionResponse) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+func (x *GetVersionResponse) GetModified() bool {
+	if x != nil {
+		return x.Modified
+	}
+	return false
+}
+
+func (x *GetVersionResponse) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *GetVersionResponse) GetStartedAtUnixMs() int64 {
+	if x != nil {
+		return x.StartedAtUnixMs
+	}
+	return 0
+}
+
+// User Attribution
+type GetUserAttributionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	StatusCode    string                 `protobuf:"bytes,1,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TestErrorRequest) Reset() {
-	*x = TestErrorRequest{}
-	mi := &file_user_proto_msgTypes[11]
+func (x *GetUserAttributionRequest) Reset() {
+	*x = GetUserAttributionRequest{}
+	mi := &file_user_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TestErrorRequest) String() string {
+func (x *GetUserAttributionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TestErrorRequest) ProtoMessage() {}
+func (*GetUserAttributionRequest) ProtoMessage() {}
 
-func (x *TestErrorRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[11]
+func (x *GetUserAttributionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -672,41 +3569,48 @@ func (x *TestErrorRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TestErrorRequest.ProtoReflect.Descriptor instead.
-func (*TestErrorRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use GetUserAttributionRequest.ProtoReflect.Descriptor instead.
+func (*GetUserAttributionRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *TestErrorRequest) GetStatusCode() string {
+func (x *GetUserAttributionRequest) GetId() string {
 	if x != nil {
-		return x.StatusCode
+		return x.Id
 	}
 	return ""
 }
 
-type TestErrorResponse struct {
+// created_by and updated_by are the token subjects of the callers that created
+// and last changed the user, anonymous for calls without a token, or
+// system:<job> for background jobs; empty for changes made before attribution
+// was recorded.
+type GetUserAttributionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	TraceId       string                 `protobuf:"bytes,2,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,2,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedBy     string                 `protobuf:"bytes,4,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TestErrorResponse) Reset() {
-	*x = TestErrorResponse{}
-	mi := &file_user_proto_msgTypes[12]
+func (x *GetUserAttributionResponse) Reset() {
+	*x = GetUserAttributionResponse{}
+	mi := &file_user_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TestErrorResponse) String() string {
+func (x *GetUserAttributionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TestErrorResponse) ProtoMessage() {}
+func (*GetUserAttributionResponse) ProtoMessage() {}
 
-func (x *TestErrorResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[12]
+func (x *GetUserAttributionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -717,31 +3621,52 @@ func (x *TestErrorResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TestErrorResponse.ProtoReflect.Descriptor instead.
-func (*TestErrorResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use GetUserAttributionResponse.ProtoReflect.Descriptor instead.
+func (*GetUserAttributionResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *TestErrorResponse) GetMessage() string {
+func (x *GetUserAttributionResponse) GetUserId() string {
 	if x != nil {
-		return x.Message
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *TestErrorResponse) GetTraceId() string {
+func (x *GetUserAttributionResponse) GetCreatedBy() string {
 	if x != nil {
-		return x.TraceId
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *GetUserAttributionResponse) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *GetUserAttributionResponse) GetUpdatedBy() string {
+	if x != nil {
+		return x.UpdatedBy
 	}
 	return ""
 }
 
+func (x *GetUserAttributionResponse) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
 var File_user_proto protoreflect.FileDescriptor
 
 const file_user_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"user.proto\x12\x04user\"\x90\x01\n" +
+	"user.proto\x12\x04user\x1a google/protobuf/field_mask.proto\"\xdb\x01\n" +
 	"\x04User\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
@@ -750,58 +3675,335 @@ const file_user_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x05 \x01(\x03R\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\x06 \x01(\x03R\tupdatedAt\"O\n" +
+	"updated_at\x18\x06 \x01(\x03R\tupdatedAt\x12(\n" +
+	"\x06status\x18\a \x01(\x0e2\x10.user.UserStatusR\x06status\x12\x1f\n" +
+	"\vmerged_into\x18\b \x01(\tR\n" +
+	"mergedInto\"_\n" +
 	"\x11CreateUserRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x10\n" +
-	"\x03age\x18\x03 \x01(\x05R\x03age\"N\n" +
+	"\x03age\x18\x03 \x01(\x05R\x03age\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\tR\x02id\"{\n" +
 	"\x12CreateUserResponse\x12\x1e\n" +
 	"\x04user\x18\x01 \x01(\v2\n" +
-	".user.UserR\x04user\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\" \n" +
+	".user.UserR\x04user\x12\x1c\n" +
+	"\amessage\x18\x02 \x01(\tB\x02\x18\x01R\amessage\x12'\n" +
+	"\aoutcome\x18\x03 \x01(\x0e2\r.user.OutcomeR\aoutcome\"\xa1\x01\n" +
 	"\x0eGetUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"K\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x127\n" +
+	"\tread_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12@\n" +
+	"\x10read_consistency\x18\x03 \x01(\x0e2\x15.user.ReadConsistencyR\x0freadConsistencyJ\x04\b\x02\x10\x03\"\xb1\x01\n" +
 	"\x0fGetUserResponse\x12\x1e\n" +
 	"\x04user\x18\x01 \x01(\v2\n" +
-	".user.UserR\x04user\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"_\n" +
+	".user.UserR\x04user\x12\x1c\n" +
+	"\amessage\x18\x02 \x01(\tB\x02\x18\x01R\amessage\x12\x14\n" +
+	"\x05stale\x18\x03 \x01(\bR\x05stale\x12!\n" +
+	"\fnot_modified\x18\x04 \x01(\bR\vnotModified\x12'\n" +
+	"\aoutcome\x18\x05 \x01(\x0e2\r.user.OutcomeR\aoutcome\"6\n" +
+	"\x14GetUserAtTimeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x0e\n" +
+	"\x02at\x18\x02 \x01(\x03R\x02at\"\xd7\x01\n" +
+	"\x15GetUserAtTimeResponse\x12\x1e\n" +
+	"\x04user\x18\x01 \x01(\v2\n" +
+	".user.UserR\x04user\x12\x1d\n" +
+	"\n" +
+	"valid_from\x18\x02 \x01(\x03R\tvalidFrom\x12\x19\n" +
+	"\bvalid_to\x18\x03 \x01(\x03R\avalidTo\x12\x1c\n" +
+	"\amessage\x18\x04 \x01(\tB\x02\x18\x01R\amessage\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x05 \x01(\x03R\tversionId\x12'\n" +
+	"\aoutcome\x18\x06 \x01(\x0e2\r.user.OutcomeR\aoutcome\"_\n" +
 	"\x11UpdateUserRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
 	"\x05email\x18\x03 \x01(\tR\x05email\x12\x10\n" +
-	"\x03age\x18\x04 \x01(\x05R\x03age\"N\n" +
+	"\x03age\x18\x04 \x01(\x05R\x03age\"{\n" +
 	"\x12UpdateUserResponse\x12\x1e\n" +
 	"\x04user\x18\x01 \x01(\v2\n" +
-	".user.UserR\x04user\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"#\n" +
+	".user.UserR\x04user\x12\x1c\n" +
+	"\amessage\x18\x02 \x01(\tB\x02\x18\x01R\amessage\x12'\n" +
+	"\aoutcome\x18\x03 \x01(\x0e2\r.user.OutcomeR\aoutcome\"H\n" +
+	"\x19RequestEmailChangeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tnew_email\x18\x02 \x01(\tR\bnewEmail\"\x82\x01\n" +
+	"\x1aRequestEmailChangeResponse\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x01 \x01(\x03R\texpiresAt\x12\x1c\n" +
+	"\amessage\x18\x02 \x01(\tB\x02\x18\x01R\amessage\x12'\n" +
+	"\aoutcome\x18\x03 \x01(\x0e2\r.user.OutcomeR\aoutcome\"A\n" +
+	"\x19ConfirmEmailChangeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"\x83\x01\n" +
+	"\x1aConfirmEmailChangeResponse\x12\x1e\n" +
+	"\x04user\x18\x01 \x01(\v2\n" +
+	".user.UserR\x04user\x12\x1c\n" +
+	"\amessage\x18\x02 \x01(\tB\x02\x18\x01R\amessage\x12'\n" +
+	"\aoutcome\x18\x03 \x01(\x0e2\r.user.OutcomeR\aoutcome\"#\n" +
 	"\x11DeleteUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\".\n" +
-	"\x12DeleteUserResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"<\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"[\n" +
+	"\x12DeleteUserResponse\x12\x1c\n" +
+	"\amessage\x18\x01 \x01(\tB\x02\x18\x01R\amessage\x12'\n" +
+	"\aoutcome\x18\x02 \x01(\x0e2\r.user.OutcomeR\aoutcome\":\n" +
+	"\x10EraseUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\x9e\x01\n" +
+	"\x11EraseUserResponse\x12%\n" +
+	"\x0ecertificate_id\x18\x01 \x01(\tR\rcertificateId\x12\x1b\n" +
+	"\terased_at\x18\x02 \x01(\x03R\berasedAt\x12\x1c\n" +
+	"\amessage\x18\x03 \x01(\tB\x02\x18\x01R\amessage\x12'\n" +
+	"\aoutcome\x18\x04 \x01(\x0e2\r.user.OutcomeR\aoutcome\"'\n" +
+	"\x15ExportUserDataRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xa4\x01\n" +
+	"\x16ExportUserDataResponse\x12\x1a\n" +
+	"\bdocument\x18\x01 \x01(\fR\bdocument\x12\x1c\n" +
+	"\tsignature\x18\x02 \x01(\tR\tsignature\x12/\n" +
+	"\x13signature_algorithm\x18\x03 \x01(\tR\x12signatureAlgorithm\x12\x1f\n" +
+	"\vexported_at\x18\x04 \x01(\x03R\n" +
+	"exportedAt\"Z\n" +
+	"\x11RevertUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x02 \x01(\x03R\tversionId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\xa1\x01\n" +
+	"\x12RevertUserResponse\x12\x1e\n" +
+	"\x04user\x18\x01 \x01(\v2\n" +
+	".user.UserR\x04user\x12$\n" +
+	"\x0eaudit_entry_id\x18\x02 \x01(\tR\fauditEntryId\x12\x1c\n" +
+	"\amessage\x18\x03 \x01(\tB\x02\x18\x01R\amessage\x12'\n" +
+	"\aoutcome\x18\x04 \x01(\x0e2\r.user.OutcomeR\aoutcome\"\xa9\x01\n" +
+	"\x11MergeUsersRequest\x12\x1b\n" +
+	"\tsource_id\x18\x01 \x01(\tR\bsourceId\x12\x1b\n" +
+	"\ttarget_id\x18\x02 \x01(\tR\btargetId\x12B\n" +
+	"\x0fconflict_policy\x18\x03 \x01(\x0e2\x19.user.MergeConflictPolicyR\x0econflictPolicy\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"\xa1\x01\n" +
+	"\x12MergeUsersResponse\x12\x1e\n" +
+	"\x04user\x18\x01 \x01(\v2\n" +
+	".user.UserR\x04user\x12$\n" +
+	"\x0eaudit_entry_id\x18\x02 \x01(\tR\fauditEntryId\x12\x1c\n" +
+	"\amessage\x18\x03 \x01(\tB\x02\x18\x01R\amessage\x12'\n" +
+	"\aoutcome\x18\x04 \x01(\x0e2\r.user.OutcomeR\aoutcome\"\xa0\x02\n" +
 	"\x10ListUsersRequest\x12\x12\n" +
 	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\"e\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x1f\n" +
+	"\vname_prefix\x18\x03 \x01(\tR\n" +
+	"namePrefix\x12!\n" +
+	"\femail_prefix\x18\x04 \x01(\tR\vemailPrefix\x127\n" +
+	"\tread_mask\x18\b \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\x12@\n" +
+	"\x10read_consistency\x18\a \x01(\x0e2\x15.user.ReadConsistencyR\x0freadConsistencyJ\x04\b\x05\x10\x06\"\xfa\x01\n" +
 	"\x11ListUsersResponse\x12 \n" +
 	"\x05users\x18\x01 \x03(\v2\n" +
 	".user.UserR\x05users\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"3\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x1c\n" +
+	"\amessage\x18\x03 \x01(\tB\x02\x18\x01R\amessage\x12\x14\n" +
+	"\x05stale\x18\x04 \x01(\bR\x05stale\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12&\n" +
+	"\x0fnext_page_token\x18\x06 \x01(\tR\rnextPageToken\x12'\n" +
+	"\aoutcome\x18\a \x01(\x0e2\r.user.OutcomeR\aoutcome\x12\x12\n" +
+	"\x04page\x18\b \x01(\x05R\x04page\"3\n" +
 	"\x10TestErrorRequest\x12\x1f\n" +
 	"\vstatus_code\x18\x01 \x01(\tR\n" +
 	"statusCode\"H\n" +
 	"\x11TestErrorResponse\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\x12\x19\n" +
-	"\btrace_id\x18\x02 \x01(\tR\atraceId2\x84\x03\n" +
+	"\btrace_id\x18\x02 \x01(\tR\atraceId\"R\n" +
+	"\x12TestLatencyRequest\x12\x1f\n" +
+	"\vduration_ms\x18\x01 \x01(\x03R\n" +
+	"durationMs\x12\x1b\n" +
+	"\tjitter_ms\x18\x02 \x01(\x03R\bjitterMs\"n\n" +
+	"\x18TestLatencyStreamRequest\x12\x1f\n" +
+	"\vduration_ms\x18\x01 \x01(\x03R\n" +
+	"durationMs\x12\x1b\n" +
+	"\tjitter_ms\x18\x02 \x01(\x03R\bjitterMs\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x05R\x05count\"\xbe\x01\n" +
+	"\x13TestLatencyResponse\x12!\n" +
+	"\frequested_ms\x18\x01 \x01(\x03R\vrequestedMs\x12\x19\n" +
+	"\bslept_ms\x18\x02 \x01(\x03R\asleptMs\x122\n" +
+	"\x15deadline_remaining_ms\x18\x03 \x01(\x03R\x13deadlineRemainingMs\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x05R\bsequence\x12\x19\n" +
+	"\btrace_id\x18\x05 \x01(\tR\atraceId\"\x9a\x01\n" +
+	"\x11TestStreamRequest\x12\x18\n" +
+	"\apayload\x18\x01 \x01(\fR\apayload\x12#\n" +
+	"\rresponse_size\x18\x02 \x01(\x05R\fresponseSize\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x05R\x05count\x120\n" +
+	"\x14responses_per_second\x18\x04 \x01(\x05R\x12responsesPerSecond\"\xcd\x01\n" +
+	"\x12TestStreamResponse\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x03R\bsequence\x12\x18\n" +
+	"\apayload\x18\x02 \x01(\fR\apayload\x12%\n" +
+	"\x0ereceived_bytes\x18\x03 \x01(\x03R\rreceivedBytes\x120\n" +
+	"\x14total_received_bytes\x18\x04 \x01(\x03R\x12totalReceivedBytes\x12(\n" +
+	"\x10total_sent_bytes\x18\x05 \x01(\x03R\x0etotalSentBytes\"+\n" +
+	"\x0fTestEchoRequest\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"9\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06values\x18\x02 \x03(\tR\x06values\"\xef\x03\n" +
+	"\x10TestEchoResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12/\n" +
+	"\bmetadata\x18\x02 \x03(\v2\x13.user.MetadataEntryR\bmetadata\x12!\n" +
+	"\fpeer_address\x18\x03 \x01(\tR\vpeerAddress\x12$\n" +
+	"\x0epeer_auth_type\x18\x04 \x01(\tR\fpeerAuthType\x12(\n" +
+	"\x10deadline_unix_ms\x18\x05 \x01(\x03R\x0edeadlineUnixMs\x122\n" +
+	"\x15deadline_remaining_ms\x18\x06 \x01(\x03R\x13deadlineRemainingMs\x12/\n" +
+	"\x13request_compression\x18\a \x01(\tR\x12requestCompression\x121\n" +
+	"\x14response_compression\x18\b \x01(\tR\x13responseCompression\x121\n" +
+	"\x14accepted_compression\x18\t \x03(\tR\x13acceptedCompression\x12\x1f\n" +
+	"\vinstance_id\x18\n" +
+	" \x01(\tR\n" +
+	"instanceId\x12\x16\n" +
+	"\x06method\x18\v \x01(\tR\x06method\x12\x19\n" +
+	"\btrace_id\x18\f \x01(\tR\atraceId\"7\n" +
+	"\x14GetCacheStatsRequest\x12\x1f\n" +
+	"\vsample_size\x18\x01 \x01(\x05R\n" +
+	"sampleSize\"\xde\x01\n" +
+	"\x13CacheNamespaceStats\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04hits\x18\x02 \x01(\x03R\x04hits\x12\x16\n" +
+	"\x06misses\x18\x03 \x01(\x03R\x06misses\x12\x16\n" +
+	"\x06errors\x18\x04 \x01(\x03R\x06errors\x12\x1b\n" +
+	"\thit_ratio\x18\x05 \x01(\x01R\bhitRatio\x12!\n" +
+	"\fsampled_keys\x18\x06 \x01(\x03R\vsampledKeys\x12%\n" +
+	"\x0eestimated_keys\x18\a \x01(\x03R\restimatedKeys\"\xfa\x01\n" +
+	"\x10CacheMemoryStats\x12*\n" +
+	"\x11used_memory_bytes\x18\x01 \x01(\x03R\x0fusedMemoryBytes\x123\n" +
+	"\x16used_memory_peak_bytes\x18\x02 \x01(\x03R\x13usedMemoryPeakBytes\x12(\n" +
+	"\x10max_memory_bytes\x18\x03 \x01(\x03R\x0emaxMemoryBytes\x12*\n" +
+	"\x11max_memory_policy\x18\x04 \x01(\tR\x0fmaxMemoryPolicy\x12/\n" +
+	"\x13fragmentation_ratio\x18\x05 \x01(\x01R\x12fragmentationRatio\"\x94\x02\n" +
+	"\x15GetCacheStatsResponse\x129\n" +
+	"\n" +
+	"namespaces\x18\x01 \x03(\v2\x19.user.CacheNamespaceStatsR\n" +
+	"namespaces\x12\x1d\n" +
+	"\n" +
+	"total_keys\x18\x02 \x01(\x03R\ttotalKeys\x12!\n" +
+	"\fsampled_keys\x18\x03 \x01(\x03R\vsampledKeys\x12.\n" +
+	"\x06memory\x18\x04 \x01(\v2\x16.user.CacheMemoryStatsR\x06memory\x12\x1f\n" +
+	"\vinstance_id\x18\x05 \x01(\tR\n" +
+	"instanceId\x12-\n" +
+	"\x13stats_since_unix_ms\x18\x06 \x01(\x03R\x10statsSinceUnixMs\"\x90\x04\n" +
+	"\rRuntimeConfig\x12\x1b\n" +
+	"\tlog_level\x18\x01 \x01(\tR\blogLevel\x123\n" +
+	"\x16user_cache_ttl_seconds\x18\x02 \x01(\x03R\x13userCacheTtlSeconds\x123\n" +
+	"\x16list_cache_ttl_seconds\x18\x03 \x01(\x03R\x13listCacheTtlSeconds\x12,\n" +
+	"\x12trace_sample_ratio\x18\x04 \x01(\x01R\x10traceSampleRatio\x12$\n" +
+	"\x0erate_limit_qps\x18\x05 \x01(\x01R\frateLimitQps\x12(\n" +
+	"\x10rate_limit_burst\x18\x06 \x01(\x05R\x0erateLimitBurst\x12\x1b\n" +
+	"\tread_only\x18\a \x01(\bR\breadOnly\x120\n" +
+	"\x14low_priority_reserve\x18\b \x01(\x01R\x12lowPriorityReserve\x12A\n" +
+	"\x11caller_priorities\x18\t \x03(\v2\x14.user.CallerPriorityR\x10callerPriorities\x12@\n" +
+	"\x11module_log_levels\x18\n" +
+	" \x03(\v2\x14.user.ModuleLogLevelR\x0fmoduleLogLevels\x12&\n" +
+	"\x0frace_user_reads\x18\v \x01(\bR\rraceUserReads\"S\n" +
+	"\x0eCallerPriority\x12\x16\n" +
+	"\x06caller\x18\x01 \x01(\tR\x06caller\x12)\n" +
+	"\x05class\x18\x02 \x01(\x0e2\x13.user.PriorityClassR\x05class\">\n" +
+	"\x0eModuleLogLevel\x12\x16\n" +
+	"\x06module\x18\x01 \x01(\tR\x06module\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\"\x19\n" +
+	"\x17GetRuntimeConfigRequest\"\x95\x01\n" +
+	"\x18GetRuntimeConfigResponse\x12+\n" +
+	"\x06config\x18\x01 \x01(\v2\x13.user.RuntimeConfigR\x06config\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\tR\n" +
+	"instanceId\x12+\n" +
+	"\x12updated_at_unix_ms\x18\x03 \x01(\x03R\x0fupdatedAtUnixMs\"g\n" +
+	"\x17SetRuntimeConfigRequest\x12+\n" +
+	"\x06config\x18\x01 \x01(\v2\x13.user.RuntimeConfigR\x06config\x12\x1f\n" +
+	"\vupdate_mask\x18\x02 \x03(\tR\n" +
+	"updateMask\"\x95\x01\n" +
+	"\x18SetRuntimeConfigResponse\x12+\n" +
+	"\x06config\x18\x01 \x01(\v2\x13.user.RuntimeConfigR\x06config\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\tR\n" +
+	"instanceId\x12+\n" +
+	"\x12updated_at_unix_ms\x18\x03 \x01(\x03R\x0fupdatedAtUnixMs\"\x13\n" +
+	"\x11GetVersionRequest\"\xee\x01\n" +
+	"\x12GetVersionResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x16\n" +
+	"\x06commit\x18\x02 \x01(\tR\x06commit\x12\x1d\n" +
+	"\n" +
+	"build_date\x18\x03 \x01(\tR\tbuildDate\x12\x1d\n" +
+	"\n" +
+	"go_version\x18\x04 \x01(\tR\tgoVersion\x12\x1a\n" +
+	"\bmodified\x18\x05 \x01(\bR\bmodified\x12\x1f\n" +
+	"\vinstance_id\x18\x06 \x01(\tR\n" +
+	"instanceId\x12+\n" +
+	"\x12started_at_unix_ms\x18\a \x01(\x03R\x0fstartedAtUnixMs\"+\n" +
+	"\x19GetUserAttributionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xb1\x01\n" +
+	"\x1aGetUserAttributionResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x02 \x01(\tR\tcreatedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_by\x18\x04 \x01(\tR\tupdatedBy\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\x03R\tupdatedAt*r\n" +
+	"\n" +
+	"UserStatus\x12\x1b\n" +
+	"\x17USER_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12USER_STATUS_ACTIVE\x10\x01\x12\x17\n" +
+	"\x13USER_STATUS_EXPIRED\x10\x02\x12\x16\n" +
+	"\x12USER_STATUS_MERGED\x10\x03*\x8f\x02\n" +
+	"\aOutcome\x12\x17\n" +
+	"\x13OUTCOME_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fOUTCOME_CREATED\x10\x01\x12\x15\n" +
+	"\x11OUTCOME_RETRIEVED\x10\x02\x12\x18\n" +
+	"\x14OUTCOME_NOT_MODIFIED\x10\x03\x12\x13\n" +
+	"\x0fOUTCOME_UPDATED\x10\x04\x12\x13\n" +
+	"\x0fOUTCOME_DELETED\x10\x05\x12\"\n" +
+	"\x1eOUTCOME_EMAIL_CHANGE_REQUESTED\x10\x06\x12\x19\n" +
+	"\x15OUTCOME_EMAIL_CHANGED\x10\a\x12\x12\n" +
+	"\x0eOUTCOME_ERASED\x10\b\x12\x14\n" +
+	"\x10OUTCOME_REVERTED\x10\t\x12\x12\n" +
+	"\x0eOUTCOME_MERGED\x10\n" +
+	"*o\n" +
+	"\x0fReadConsistency\x12 \n" +
+	"\x1cREAD_CONSISTENCY_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19READ_CONSISTENCY_CACHE_OK\x10\x01\x12\x1b\n" +
+	"\x17READ_CONSISTENCY_STRONG\x10\x02*\xae\x01\n" +
+	"\x13MergeConflictPolicy\x12%\n" +
+	"!MERGE_CONFLICT_POLICY_UNSPECIFIED\x10\x00\x12%\n" +
+	"!MERGE_CONFLICT_POLICY_KEEP_TARGET\x10\x01\x12'\n" +
+	"#MERGE_CONFLICT_POLICY_PREFER_SOURCE\x10\x02\x12 \n" +
+	"\x1cMERGE_CONFLICT_POLICY_NEWEST\x10\x03*\x87\x01\n" +
+	"\rPriorityClass\x12\x1e\n" +
+	"\x1aPRIORITY_CLASS_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aPRIORITY_CLASS_INTERACTIVE\x10\x01\x12\x18\n" +
+	"\x14PRIORITY_CLASS_BATCH\x10\x02\x12\x1c\n" +
+	"\x18PRIORITY_CLASS_LOAD_TEST\x10\x032\xa3\t\n" +
 	"\vUserService\x12?\n" +
 	"\n" +
 	"CreateUser\x12\x17.user.CreateUserRequest\x1a\x18.user.CreateUserResponse\x126\n" +
-	"\aGetUser\x12\x14.user.GetUserRequest\x1a\x15.user.GetUserResponse\x12?\n" +
+	"\aGetUser\x12\x14.user.GetUserRequest\x1a\x15.user.GetUserResponse\x12H\n" +
+	"\rGetUserAtTime\x12\x1a.user.GetUserAtTimeRequest\x1a\x1b.user.GetUserAtTimeResponse\x12?\n" +
 	"\n" +
-	"UpdateUser\x12\x17.user.UpdateUserRequest\x1a\x18.user.UpdateUserResponse\x12?\n" +
+	"UpdateUser\x12\x17.user.UpdateUserRequest\x1a\x18.user.UpdateUserResponse\x12W\n" +
+	"\x12RequestEmailChange\x12\x1f.user.RequestEmailChangeRequest\x1a .user.RequestEmailChangeResponse\x12W\n" +
+	"\x12ConfirmEmailChange\x12\x1f.user.ConfirmEmailChangeRequest\x1a .user.ConfirmEmailChangeResponse\x12?\n" +
 	"\n" +
 	"DeleteUser\x12\x17.user.DeleteUserRequest\x1a\x18.user.DeleteUserResponse\x12<\n" +
 	"\tListUsers\x12\x16.user.ListUsersRequest\x1a\x17.user.ListUsersResponse\x12<\n" +
-	"\tTestError\x12\x16.user.TestErrorRequest\x1a\x17.user.TestErrorResponseB\x06Z\x04./pbb\x06proto3"
+	"\tEraseUser\x12\x16.user.EraseUserRequest\x1a\x17.user.EraseUserResponse\x12K\n" +
+	"\x0eExportUserData\x12\x1b.user.ExportUserDataRequest\x1a\x1c.user.ExportUserDataResponse\x12?\n" +
+	"\n" +
+	"RevertUser\x12\x17.user.RevertUserRequest\x1a\x18.user.RevertUserResponse\x12?\n" +
+	"\n" +
+	"MergeUsers\x12\x17.user.MergeUsersRequest\x1a\x18.user.MergeUsersResponse\x12<\n" +
+	"\tTestError\x12\x16.user.TestErrorRequest\x1a\x17.user.TestErrorResponse\x12B\n" +
+	"\vTestLatency\x12\x18.user.TestLatencyRequest\x1a\x19.user.TestLatencyResponse\x12P\n" +
+	"\x11TestLatencyStream\x12\x1e.user.TestLatencyStreamRequest\x1a\x19.user.TestLatencyResponse0\x01\x12C\n" +
+	"\n" +
+	"TestStream\x12\x17.user.TestStreamRequest\x1a\x18.user.TestStreamResponse(\x010\x01\x129\n" +
+	"\bTestEcho\x12\x15.user.TestEchoRequest\x1a\x16.user.TestEchoResponse2\x98\x03\n" +
+	"\fAdminService\x12H\n" +
+	"\rGetCacheStats\x12\x1a.user.GetCacheStatsRequest\x1a\x1b.user.GetCacheStatsResponse\x12Q\n" +
+	"\x10GetRuntimeConfig\x12\x1d.user.GetRuntimeConfigRequest\x1a\x1e.user.GetRuntimeConfigResponse\x12Q\n" +
+	"\x10SetRuntimeConfig\x12\x1d.user.SetRuntimeConfigRequest\x1a\x1e.user.SetRuntimeConfigResponse\x12?\n" +
+	"\n" +
+	"GetVersion\x12\x17.user.GetVersionRequest\x1a\x18.user.GetVersionResponse\x12W\n" +
+	"\x12GetUserAttribution\x12\x1f.user.GetUserAttributionRequest\x1a .user.GetUserAttributionResponseB\x06Z\x04./pbb\x06proto3"
 
 var (
 	file_user_proto_rawDescOnce sync.Once
@@ -815,44 +4017,150 @@ func file_user_proto_rawDescGZIP() []byte {
 	return file_user_proto_rawDescData
 }
 
-var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_user_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 50)
 var file_user_proto_goTypes = []any{
-	(*User)(nil),               // 0: user.User
-	(*CreateUserRequest)(nil),  // 1: user.CreateUserRequest
-	(*CreateUserResponse)(nil), // 2: user.CreateUserResponse
-	(*GetUserRequest)(nil),     // 3: user.GetUserRequest
-	(*GetUserResponse)(nil),    // 4: user.GetUserResponse
-	(*UpdateUserRequest)(nil),  // 5: user.UpdateUserRequest
-	(*UpdateUserResponse)(nil), // 6: user.UpdateUserResponse
-	(*DeleteUserRequest)(nil),  // 7: user.DeleteUserRequest
-	(*DeleteUserResponse)(nil), // 8: user.DeleteUserResponse
-	(*ListUsersRequest)(nil),   // 9: user.ListUsersRequest
-	(*ListUsersResponse)(nil),  // 10: user.ListUsersResponse
-	(*TestErrorRequest)(nil),   // 11: user.TestErrorRequest
-	(*TestErrorResponse)(nil),  // 12: user.TestErrorResponse
+	(UserStatus)(0),                    // 0: user.UserStatus
+	(Outcome)(0),                       // 1: user.Outcome
+	(ReadConsistency)(0),               // 2: user.ReadConsistency
+	(MergeConflictPolicy)(0),           // 3: user.MergeConflictPolicy
+	(PriorityClass)(0),                 // 4: user.PriorityClass
+	(*User)(nil),                       // 5: user.User
+	(*CreateUserRequest)(nil),          // 6: user.CreateUserRequest
+	(*CreateUserResponse)(nil),         // 7: user.CreateUserResponse
+	(*GetUserRequest)(nil),             // 8: user.GetUserRequest
+	(*GetUserResponse)(nil),            // 9: user.GetUserResponse
+	(*GetUserAtTimeRequest)(nil),       // 10: user.GetUserAtTimeRequest
+	(*GetUserAtTimeResponse)(nil),      // 11: user.GetUserAtTimeResponse
+	(*UpdateUserRequest)(nil),          // 12: user.UpdateUserRequest
+	(*UpdateUserResponse)(nil),         // 13: user.UpdateUserResponse
+	(*RequestEmailChangeRequest)(nil),  // 14: user.RequestEmailChangeRequest
+	(*RequestEmailChangeResponse)(nil), // 15: user.RequestEmailChangeResponse
+	(*ConfirmEmailChangeRequest)(nil),  // 16: user.ConfirmEmailChangeRequest
+	(*ConfirmEmailChangeResponse)(nil), // 17: user.ConfirmEmailChangeResponse
+	(*DeleteUserRequest)(nil),          // 18: user.DeleteUserRequest
+	(*DeleteUserResponse)(nil),         // 19: user.DeleteUserResponse
+	(*EraseUserRequest)(nil),           // 20: user.EraseUserRequest
+	(*EraseUserResponse)(nil),          // 21: user.EraseUserResponse
+	(*ExportUserDataRequest)(nil),      // 22: user.ExportUserDataRequest
+	(*ExportUserDataResponse)(nil),     // 23: user.ExportUserDataResponse
+	(*RevertUserRequest)(nil),          // 24: user.RevertUserRequest
+	(*RevertUserResponse)(nil),         // 25: user.RevertUserResponse
+	(*MergeUsersRequest)(nil),          // 26: user.MergeUsersRequest
+	(*MergeUsersResponse)(nil),         // 27: user.MergeUsersResponse
+	(*ListUsersRequest)(nil),           // 28: user.ListUsersRequest
+	(*ListUsersResponse)(nil),          // 29: user.ListUsersResponse
+	(*TestErrorRequest)(nil),           // 30: user.TestErrorRequest
+	(*TestErrorResponse)(nil),          // 31: user.TestErrorResponse
+	(*TestLatencyRequest)(nil),         // 32: user.TestLatencyRequest
+	(*TestLatencyStreamRequest)(nil),   // 33: user.TestLatencyStreamRequest
+	(*TestLatencyResponse)(nil),        // 34: user.TestLatencyResponse
+	(*TestStreamRequest)(nil),          // 35: user.TestStreamRequest
+	(*TestStreamResponse)(nil),         // 36: user.TestStreamResponse
+	(*TestEchoRequest)(nil),            // 37: user.TestEchoRequest
+	(*MetadataEntry)(nil),              // 38: user.MetadataEntry
+	(*TestEchoResponse)(nil),           // 39: user.TestEchoResponse
+	(*GetCacheStatsRequest)(nil),       // 40: user.GetCacheStatsRequest
+	(*CacheNamespaceStats)(nil),        // 41: user.CacheNamespaceStats
+	(*CacheMemoryStats)(nil),           // 42: user.CacheMemoryStats
+	(*GetCacheStatsResponse)(nil),      // 43: user.GetCacheStatsResponse
+	(*RuntimeConfig)(nil),              // 44: user.RuntimeConfig
+	(*CallerPriority)(nil),             // 45: user.CallerPriority
+	(*ModuleLogLevel)(nil),             // 46: user.ModuleLogLevel
+	(*GetRuntimeConfigRequest)(nil),    // 47: user.GetRuntimeConfigRequest
+	(*GetRuntimeConfigResponse)(nil),   // 48: user.GetRuntimeConfigResponse
+	(*SetRuntimeConfigRequest)(nil),    // 49: user.SetRuntimeConfigRequest
+	(*SetRuntimeConfigResponse)(nil),   // 50: user.SetRuntimeConfigResponse
+	(*GetVersionRequest)(nil),          // 51: user.GetVersionRequest
+	(*GetVersionResponse)(nil),         // 52: user.GetVersionResponse
+	(*GetUserAttributionRequest)(nil),  // 53: user.GetUserAttributionRequest
+	(*GetUserAttributionResponse)(nil), // 54: user.GetUserAttributionResponse
+	(*fieldmaskpb.FieldMask)(nil),      // 55: google.protobuf.FieldMask
 }
 var file_user_proto_depIdxs = []int32{
-	0,  // 0: user.CreateUserResponse.user:type_name -> user.User
-	0,  // 1: user.GetUserResponse.user:type_name -> user.User
-	0,  // 2: user.UpdateUserResponse.user:type_name -> user.User
-	0,  // 3: user.ListUsersResponse.users:type_name -> user.User
-	1,  // 4: user.UserService.CreateUser:input_type -> user.CreateUserRequest
-	3,  // 5: user.UserService.GetUser:input_type -> user.GetUserRequest
-	5,  // 6: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
-	7,  // 7: user.UserService.DeleteUser:input_type -> user.DeleteUserRequest
-	9,  // 8: user.UserService.ListUsers:input_type -> user.ListUsersRequest
-	11, // 9: user.UserService.TestError:input_type -> user.TestErrorRequest
-	2,  // 10: user.UserService.CreateUser:output_type -> user.CreateUserResponse
-	4,  // 11: user.UserService.GetUser:output_type -> user.GetUserResponse
-	6,  // 12: user.UserService.UpdateUser:output_type -> user.UpdateUserResponse
-	8,  // 13: user.UserService.DeleteUser:output_type -> user.DeleteUserResponse
-	10, // 14: user.UserService.ListUsers:output_type -> user.ListUsersResponse
-	12, // 15: user.UserService.TestError:output_type -> user.TestErrorResponse
-	10, // [10:16] is the sub-list for method output_type
-	4,  // [4:10] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	0,  // 0: user.User.status:type_name -> user.UserStatus
+	5,  // 1: user.CreateUserResponse.user:type_name -> user.User
+	1,  // 2: user.CreateUserResponse.outcome:type_name -> user.Outcome
+	55, // 3: user.GetUserRequest.read_mask:type_name -> google.protobuf.FieldMask
+	2,  // 4: user.GetUserRequest.read_consistency:type_name -> user.ReadConsistency
+	5,  // 5: user.GetUserResponse.user:type_name -> user.User
+	1,  // 6: user.GetUserResponse.outcome:type_name -> user.Outcome
+	5,  // 7: user.GetUserAtTimeResponse.user:type_name -> user.User
+	1,  // 8: user.GetUserAtTimeResponse.outcome:type_name -> user.Outcome
+	5,  // 9: user.UpdateUserResponse.user:type_name -> user.User
+	1,  // 10: user.UpdateUserResponse.outcome:type_name -> user.Outcome
+	1,  // 11: user.RequestEmailChangeResponse.outcome:type_name -> user.Outcome
+	5,  // 12: user.ConfirmEmailChangeResponse.user:type_name -> user.User
+	1,  // 13: user.ConfirmEmailChangeResponse.outcome:type_name -> user.Outcome
+	1,  // 14: user.DeleteUserResponse.outcome:type_name -> user.Outcome
+	1,  // 15: user.EraseUserResponse.outcome:type_name -> user.Outcome
+	5,  // 16: user.RevertUserResponse.user:type_name -> user.User
+	1,  // 17: user.RevertUserResponse.outcome:type_name -> user.Outcome
+	3,  // 18: user.MergeUsersRequest.conflict_policy:type_name -> user.MergeConflictPolicy
+	5,  // 19: user.MergeUsersResponse.user:type_name -> user.User
+	1,  // 20: user.MergeUsersResponse.outcome:type_name -> user.Outcome
+	55, // 21: user.ListUsersRequest.read_mask:type_name -> google.protobuf.FieldMask
+	2,  // 22: user.ListUsersRequest.read_consistency:type_name -> user.ReadConsistency
+	5,  // 23: user.ListUsersResponse.users:type_name -> user.User
+	1,  // 24: user.ListUsersResponse.outcome:type_name -> user.Outcome
+	38, // 25: user.TestEchoResponse.metadata:type_name -> user.MetadataEntry
+	41, // 26: user.GetCacheStatsResponse.namespaces:type_name -> user.CacheNamespaceStats
+	42, // 27: user.GetCacheStatsResponse.memory:type_name -> user.CacheMemoryStats
+	45, // 28: user.RuntimeConfig.caller_priorities:type_name -> user.CallerPriority
+	46, // 29: user.RuntimeConfig.module_log_levels:type_name -> user.ModuleLogLevel
+	4,  // 30: user.CallerPriority.class:type_name -> user.PriorityClass
+	44, // 31: user.GetRuntimeConfigResponse.config:type_name -> user.RuntimeConfig
+	44, // 32: user.SetRuntimeConfigRequest.config:type_name -> user.RuntimeConfig
+	44, // 33: user.SetRuntimeConfigResponse.config:type_name -> user.RuntimeConfig
+	6,  // 34: user.UserService.CreateUser:input_type -> user.CreateUserRequest
+	8,  // 35: user.UserService.GetUser:input_type -> user.GetUserRequest
+	10, // 36: user.UserService.GetUserAtTime:input_type -> user.GetUserAtTimeRequest
+	12, // 37: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
+	14, // 38: user.UserService.RequestEmailChange:input_type -> user.RequestEmailChangeRequest
+	16, // 39: user.UserService.ConfirmEmailChange:input_type -> user.ConfirmEmailChangeRequest
+	18, // 40: user.UserService.DeleteUser:input_type -> user.DeleteUserRequest
+	28, // 41: user.UserService.ListUsers:input_type -> user.ListUsersRequest
+	20, // 42: user.UserService.EraseUser:input_type -> user.EraseUserRequest
+	22, // 43: user.UserService.ExportUserData:input_type -> user.ExportUserDataRequest
+	24, // 44: user.UserService.RevertUser:input_type -> user.RevertUserRequest
+	26, // 45: user.UserService.MergeUsers:input_type -> user.MergeUsersRequest
+	30, // 46: user.UserService.TestError:input_type -> user.TestErrorRequest
+	32, // 47: user.UserService.TestLatency:input_type -> user.TestLatencyRequest
+	33, // 48: user.UserService.TestLatencyStream:input_type -> user.TestLatencyStreamRequest
+	35, // 49: user.UserService.TestStream:input_type -> user.TestStreamRequest
+	37, // 50: user.UserService.TestEcho:input_type -> user.TestEchoRequest
+	40, // 51: user.AdminService.GetCacheStats:input_type -> user.GetCacheStatsRequest
+	47, // 52: user.AdminService.GetRuntimeConfig:input_type -> user.GetRuntimeConfigRequest
+	49, // 53: user.AdminService.SetRuntimeConfig:input_type -> user.SetRuntimeConfigRequest
+	51, // 54: user.AdminService.GetVersion:input_type -> user.GetVersionRequest
+	53, // 55: user.AdminService.GetUserAttribution:input_type -> user.GetUserAttributionRequest
+	7,  // 56: user.UserService.CreateUser:output_type -> user.CreateUserResponse
+	9,  // 57: user.UserService.GetUser:output_type -> user.GetUserResponse
+	11, // 58: user.UserService.GetUserAtTime:output_type -> user.GetUserAtTimeResponse
+	13, // 59: user.UserService.UpdateUser:output_type -> user.UpdateUserResponse
+	15, // 60: user.UserService.RequestEmailChange:output_type -> user.RequestEmailChangeResponse
+	17, // 61: user.UserService.ConfirmEmailChange:output_type -> user.ConfirmEmailChangeResponse
+	19, // 62: user.UserService.DeleteUser:output_type -> user.DeleteUserResponse
+	29, // 63: user.UserService.ListUsers:output_type -> user.ListUsersResponse
+	21, // 64: user.UserService.EraseUser:output_type -> user.EraseUserResponse
+	23, // 65: user.UserService.ExportUserData:output_type -> user.ExportUserDataResponse
+	25, // 66: user.UserService.RevertUser:output_type -> user.RevertUserResponse
+	27, // 67: user.UserService.MergeUsers:output_type -> user.MergeUsersResponse
+	31, // 68: user.UserService.TestError:output_type -> user.TestErrorResponse
+	34, // 69: user.UserService.TestLatency:output_type -> user.TestLatencyResponse
+	34, // 70: user.UserService.TestLatencyStream:output_type -> user.TestLatencyResponse
+	36, // 71: user.UserService.TestStream:output_type -> user.TestStreamResponse
+	39, // 72: user.UserService.TestEcho:output_type -> user.TestEchoResponse
+	43, // 73: user.AdminService.GetCacheStats:output_type -> user.GetCacheStatsResponse
+	48, // 74: user.AdminService.GetRuntimeConfig:output_type -> user.GetRuntimeConfigResponse
+	50, // 75: user.AdminService.SetRuntimeConfig:output_type -> user.SetRuntimeConfigResponse
+	52, // 76: user.AdminService.GetVersion:output_type -> user.GetVersionResponse
+	54, // 77: user.AdminService.GetUserAttribution:output_type -> user.GetUserAttributionResponse
+	56, // [56:78] is the sub-list for method output_type
+	34, // [34:56] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_user_proto_init() }
@@ -865,13 +4173,14 @@ func file_user_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_proto_rawDesc), len(file_user_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   13,
+			NumEnums:      5,
+			NumMessages:   50,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_user_proto_goTypes,
 		DependencyIndexes: file_user_proto_depIdxs,
+		EnumInfos:         file_user_proto_enumTypes,
 		MessageInfos:      file_user_proto_msgTypes,
 	}.Build()
 	File_user_proto = out.File
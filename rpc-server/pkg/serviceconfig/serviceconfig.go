@@ -0,0 +1,138 @@
+package serviceconfig
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// JSON is the default gRPC service config for UserService. Clients pass it to
+// grpc.WithDefaultServiceConfig; the server reads the same document so method
+// timeouts agree on both sides. Only reads are retried or hedged: the writes
+// are not idempotent, so retrying one whose response was lost could apply it
+// twice.
+//
+//go:embed service_config.json
+var JSON string
+
+type ServiceConfig struct {
+	MethodConfig []MethodConfig `json:"methodConfig"`
+}
+
+type Name struct {
+	Service string `json:"service"`
+	Method  string `json:"method,omitempty"`
+}
+
+type MethodConfig struct {
+	Name          []Name         `json:"name"`
+	Timeout       Duration       `json:"timeout,omitempty"`
+	RetryPolicy   *RetryPolicy   `json:"retryPolicy,omitempty"`
+	HedgingPolicy *HedgingPolicy `json:"hedgingPolicy,omitempty"`
+}
+
+type RetryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       Duration `json:"initialBackoff"`
+	MaxBackoff           Duration `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []Code   `json:"retryableStatusCodes"`
+}
+
+// HedgingPolicy mirrors the gRPC hedging policy. grpc-go does not implement
+// hedging itself, so pkg/client applies it with an interceptor.
+type HedgingPolicy struct {
+	MaxAttempts         int      `json:"maxAttempts"`
+	HedgingDelay        Duration `json:"hedgingDelay"`
+	NonFatalStatusCodes []Code   `json:"nonFatalStatusCodes"`
+}
+
+// Duration decodes the protobuf JSON duration form used in service configs ("0.05s").
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Code decodes the upper-case status code names used in service configs ("UNAVAILABLE").
+type Code codes.Code
+
+func (c *Code) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("status code must be a string: %w", err)
+	}
+	var code codes.Code
+	if err := code.UnmarshalJSON([]byte(`"` + strings.ToUpper(s) + `"`)); err != nil {
+		return err
+	}
+	*c = Code(code)
+	return nil
+}
+
+// Parse decodes a service config document.
+func Parse(raw string) (*ServiceConfig, error) {
+	var sc ServiceConfig
+	if err := json.Unmarshal([]byte(raw), &sc); err != nil {
+		return nil, fmt.Errorf("failed to parse service config: %w", err)
+	}
+	return &sc, nil
+}
+
+// Default returns the parsed embedded service config.
+func Default() *ServiceConfig {
+	sc, err := Parse(JSON)
+	if err != nil {
+		panic(err)
+	}
+	return sc
+}
+
+// Lookup returns the method config for a full method name ("/user.UserService/GetUser"),
+// preferring an exact method match over a service-wide entry.
+func (sc *ServiceConfig) Lookup(fullMethod string) *MethodConfig {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return nil
+	}
+
+	var serviceMatch *MethodConfig
+	for i := range sc.MethodConfig {
+		mc := &sc.MethodConfig[i]
+		for _, n := range mc.Name {
+			if n.Service != service {
+				continue
+			}
+			if n.Method == method {
+				return mc
+			}
+			if n.Method == "" && serviceMatch == nil {
+				serviceMatch = mc
+			}
+		}
+	}
+	return serviceMatch
+}
+
+// IsNonFatal reports whether code allows another hedged attempt.
+func (p *HedgingPolicy) IsNonFatal(code codes.Code) bool {
+	for _, c := range p.NonFatalStatusCodes {
+		if codes.Code(c) == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+// Package usertest runs a complete in-process UserService for integration tests
+// of downstream services. It uses the memory repository and a bufconn listener,
+// so no Postgres, Valkey or network ports are needed.
+package usertest
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"grpc-server/internal/cache"
+	"grpc-server/internal/export"
+	"grpc-server/internal/logging"
+	"grpc-server/internal/repository/memory"
+	"grpc-server/internal/server"
+	"grpc-server/pkg/client"
+	pb "grpc-server/pkg/pb"
+)
+
+const bufSize = 1024 * 1024
+
+// SigningKey signs ExportUserData documents returned by the fake server.
+const SigningKey = "usertest-export-signing-key"
+
+// Fault makes calls to one repository method fail, slow down or race with
+// another write; inject it with Server.Repo.InjectFault.
+type Fault = memory.Fault
+
+// Server is a running in-process UserService.
+type Server struct {
+	// Repo is the backing store; tests may seed or inspect it directly.
+	Repo *memory.UserRepository
+
+	listener   *bufconn.Listener
+	grpcServer *grpc.Server
+}
+
+// NewServer starts a UserService on a bufconn listener. Call Close when done.
+func NewServer(opts ...grpc.ServerOption) *Server {
+	repo := memory.NewUserRepository()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	s := &Server{
+		Repo:       repo,
+		listener:   bufconn.Listen(bufSize),
+		grpcServer: grpc.NewServer(append([]grpc.ServerOption{grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor(logger))}, opts...)...),
+	}
+	pb.RegisterUserServiceServer(s.grpcServer, server.NewCombinedServer(repo, nopCache{}, logger,
+		server.WithExportSigner(export.NewSigner([]byte(SigningKey))),
+	))
+
+	go func() {
+		_ = s.grpcServer.Serve(s.listener)
+	}()
+	return s
+}
+
+// Dial opens a raw connection to the server. Extra options are appended to the
+// bufconn dialer and insecure transport credentials.
+func (s *Server) Dial(opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return grpc.NewClient("passthrough:///usertest", s.dialOptions(opts...)...)
+}
+
+// Client returns an SDK client, including the default service config, connected
+// to the server.
+func (s *Server) Client(opts ...grpc.DialOption) (*client.Client, error) {
+	return client.New("passthrough:///usertest", s.dialOptions(opts...)...)
+}
+
+func (s *Server) dialOptions(opts ...grpc.DialOption) []grpc.DialOption {
+	return append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)
+}
+
+// Close stops the server, waiting briefly for in-flight calls.
+func (s *Server) Close() {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		s.grpcServer.Stop()
+	}
+	_ = s.listener.Close()
+}
+
+// NewClient starts a server and returns a connected client; both are torn down
+// when the test finishes.
+func NewClient(tb testing.TB, opts ...grpc.DialOption) *client.Client {
+	tb.Helper()
+
+	s := NewServer()
+	c, err := s.Client(opts...)
+	if err != nil {
+		s.Close()
+		tb.Fatalf("usertest: failed to create client: %v", err)
+	}
+	tb.Cleanup(func() {
+		_ = c.Close()
+		s.Close()
+	})
+	return c
+}
+
+// nopCache always misses, so every call goes straight to the repository and
+// tests never observe stale reads.
+type nopCache struct{}
+
+func (nopCache) Get(ctx context.Context, key string) ([]byte, error) { return nil, cache.ErrCacheMiss }
+func (nopCache) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	return make([][]byte, len(keys)), nil
+}
+func (nopCache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	return nil
+}
+func (nopCache) Delete(ctx context.Context, keys ...string) error               { return nil }
+func (nopCache) DeletePattern(ctx context.Context, pattern string) (int, error) { return 0, nil }
+func (nopCache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return nil
+}
+func (nopCache) Close() error { return nil }